@@ -0,0 +1,44 @@
+package oas
+
+import (
+	"sort"
+)
+
+// Annotate fills in the missing titles of the component schemas and of the
+// schema properties throughout the document. Titles are derived from the
+// component key or the property name (e.g. "pet_name" becomes "Pet Name").
+// Referencing schemas are left untouched. The JSON Pointers of the annotated
+// schemas which lack a description are returned in sorted order so they can
+// be reported before the documentation is rendered.
+func (r *OpenAPI) Annotate() []string {
+	undocumented := make([]string, 0)
+	annotate := func(ptr string, name string, schema *Schema) {
+		if schema == nil || schema.Ref != "" {
+			return
+		}
+		if schema.Title == "" {
+			schema.Title = humanize(name)
+		}
+		if schema.Description == "" {
+			undocumented = append(undocumented, ptr)
+		}
+	}
+
+	if r.Components != nil {
+		for _, key := range sortedKeys(r.Components.Schemas) {
+			annotate(join("", "components", "schemas", key), key, r.Components.Schemas[key])
+		}
+	}
+
+	_ = walk(r, func(ptr string, node interface{}) error {
+		if schema, ok := node.(*Schema); ok {
+			for _, key := range sortedKeys(schema.Properties) {
+				annotate(join(ptr, "properties", key), key, schema.Properties.Get(key))
+			}
+		}
+		return nil
+	})
+
+	sort.Strings(undocumented)
+	return undocumented
+}
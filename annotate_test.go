@@ -0,0 +1,109 @@
+package oas
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+)
+
+type AnnotateSuite struct {
+	suite.Suite
+}
+
+func (r *AnnotateSuite) TestAnnotate() {
+	testCases := []struct {
+		input        *OpenAPI
+		expected     *OpenAPI
+		undocumented []string
+	}{
+		{
+			&OpenAPI{
+				Paths: Paths{
+					PathItems: NewPathItems(map[string]*PathItem{
+						"/pets": {
+							Post: &Operation{
+								RequestBody: &RequestBody{
+									Content: map[string]*MediaType{
+										"application/json": {
+											Schema: &Schema{
+												Type: "object",
+												Properties: NewProperties(map[string]*Schema{
+													"owner_id": {Type: "string", Description: "Owner of the pet."},
+												}),
+											},
+										},
+									},
+								},
+							},
+						},
+					}),
+				},
+				Components: &Components{
+					Schemas: map[string]*Schema{
+						"PetOwner": {
+							Type: "object",
+							Properties: NewProperties(map[string]*Schema{
+								"first_name": {Type: "string"},
+								"pet":        {Ref: "#/components/schemas/Pet"},
+							}),
+						},
+						"Pet": {Type: "object", Title: "A pet", Description: "A pet."},
+					},
+				},
+			},
+			&OpenAPI{
+				Paths: Paths{
+					PathItems: NewPathItems(map[string]*PathItem{
+						"/pets": {
+							Post: &Operation{
+								RequestBody: &RequestBody{
+									Content: map[string]*MediaType{
+										"application/json": {
+											Schema: &Schema{
+												Type: "object",
+												Properties: NewProperties(map[string]*Schema{
+													"owner_id": {Type: "string", Title: "Owner Id", Description: "Owner of the pet."},
+												}),
+											},
+										},
+									},
+								},
+							},
+						},
+					}),
+				},
+				Components: &Components{
+					Schemas: map[string]*Schema{
+						"PetOwner": {
+							Type:  "object",
+							Title: "Pet Owner",
+							Properties: NewProperties(map[string]*Schema{
+								"first_name": {Type: "string", Title: "First Name"},
+								"pet":        {Ref: "#/components/schemas/Pet"},
+							}),
+						},
+						"Pet": {Type: "object", Title: "A pet", Description: "A pet."},
+					},
+				},
+			},
+			[]string{
+				"/components/schemas/PetOwner",
+				"/components/schemas/PetOwner/properties/first_name",
+			},
+		},
+	}
+
+	for i, testCase := range testCases {
+		failMsg := fmt.Sprintf("testCase: %d %v", i, testCase)
+
+		undocumented := testCase.input.Annotate()
+		assert.Equal(r.T(), testCase.undocumented, undocumented, failMsg)
+		assert.EqualValues(r.T(), testCase.expected, testCase.input, failMsg)
+	}
+}
+
+func TestAnnotateSuite(t *testing.T) {
+	suite.Run(t, new(AnnotateSuite))
+}
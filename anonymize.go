@@ -0,0 +1,151 @@
+package oas
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// Anonymization describes the renames applied by Anonymize as maps from the
+// generic identifiers to the original ones. It is kept private by the owner
+// of the document and reverses the renames with Deanonymize.
+type Anonymization struct {
+	// Schemas describes the original names of the component schemas.
+	Schemas map[string]string `json:"schemas" yaml:"schemas"`
+
+	// Operations describes the original operationIds.
+	Operations map[string]string `json:"operations" yaml:"operations"`
+
+	// Paths describes the original paths.
+	Paths map[string]string `json:"paths" yaml:"paths"`
+}
+
+// Anonymize renames the component schemas (e.g. "Schema1"), the
+// operationIds (e.g. "operation1") and the literal segments of the paths
+// (e.g. "/segment1/{petId}") to generic identifiers, so a proprietary
+// document can be shared for debugging or benchmarking while keeping its
+// structure. Identifiers are numbered in sorted order and a path segment is
+// renamed alike wherever it appears. References, discriminator mappings and
+// links are updated accordingly. The returned mapping reverses the renames.
+func (r *OpenAPI) Anonymize() (*Anonymization, error) {
+	mapping := &Anonymization{
+		Schemas:    map[string]string{},
+		Operations: map[string]string{},
+		Paths:      map[string]string{},
+	}
+
+	schemas := map[string]string{}
+	if r.Components != nil {
+		for i, name := range sortedKeys(r.Components.Schemas) {
+			generic := fmt.Sprintf("Schema%d", i+1)
+			schemas[name] = generic
+			mapping.Schemas[generic] = name
+		}
+	}
+
+	operations := map[string]string{}
+	if err := walk(r, func(ptr string, node interface{}) error {
+		if operation, ok := node.(*Operation); ok && operation.OperationID != "" {
+			if _, ok := operations[operation.OperationID]; ok {
+				return errors.Errorf("%s: duplicate operationId %q", ptr, operation.OperationID)
+			}
+			generic := fmt.Sprintf("operation%d", len(operations)+1)
+			operations[operation.OperationID] = generic
+			mapping.Operations[generic] = operation.OperationID
+		}
+		return nil
+	}); err != nil {
+		return nil, err
+	}
+
+	paths := map[string]string{}
+	segments := map[string]string{}
+	for _, path := range sortedKeys(r.Paths.PathItems) {
+		tokens := strings.Split(path, "/")
+		for i, token := range tokens {
+			if token == "" || templateParam.MatchString(token) {
+				continue
+			}
+			if _, ok := segments[token]; !ok {
+				segments[token] = fmt.Sprintf("segment%d", len(segments)+1)
+			}
+			tokens[i] = segments[token]
+		}
+		generic := strings.Join(tokens, "/")
+		paths[path] = generic
+		mapping.Paths[generic] = path
+	}
+
+	if err := r.rename(schemas, operations, paths); err != nil {
+		return nil, err
+	}
+	return mapping, nil
+}
+
+// Deanonymize reverses the renames of Anonymize described by the mapping.
+func (r *OpenAPI) Deanonymize(mapping *Anonymization) error {
+	return r.rename(mapping.Schemas, mapping.Operations, mapping.Paths)
+}
+
+// rename renames the component schemas, the operationIds and the paths of
+// the document, updating the references, discriminator mappings and links.
+func (r *OpenAPI) rename(schemas map[string]string, operations map[string]string, paths map[string]string) error {
+	if len(schemas) > 0 && r.Components != nil {
+		renamed := make(map[string]*Schema, len(r.Components.Schemas))
+		for name, schema := range r.Components.Schemas {
+			if newName, ok := schemas[name]; ok {
+				name = newName
+			}
+			if _, ok := renamed[name]; ok {
+				return errors.Errorf("components/schemas: duplicate schema %q", name)
+			}
+			renamed[name] = schema
+		}
+		r.Components.Schemas = renamed
+	}
+
+	if len(paths) > 0 {
+		renamed := PathItems{}
+		for _, path := range r.Paths.PathItems.Keys() {
+			item := r.Paths.PathItems.Get(path)
+			if newPath, ok := paths[path]; ok {
+				path = newPath
+			}
+			if _, ok := renamed.Lookup(path); ok {
+				return errors.Errorf("paths: duplicate path %q", path)
+			}
+			renamed.Set(path, item)
+		}
+		r.Paths.PathItems = renamed
+	}
+
+	return walk(r, func(ptr string, node interface{}) error {
+		if ref := refOf(node); ref != nil {
+			*ref = renameSchemaRef(*ref, schemas)
+		}
+		switch node := node.(type) {
+		case *Schema:
+			if node.Discriminator == nil {
+				return nil
+			}
+			for key, value := range node.Discriminator.Mapping {
+				if newName, ok := schemas[value]; ok {
+					node.Discriminator.Mapping[key] = newName
+				} else {
+					node.Discriminator.Mapping[key] = renameSchemaRef(value, schemas)
+				}
+			}
+		case *Operation:
+			if newID, ok := operations[node.OperationID]; ok {
+				node.OperationID = newID
+			}
+		case *Link:
+			if newID, ok := operations[node.OperationID]; ok {
+				node.OperationID = newID
+			}
+			node.OperationRef = renamePathRef(node.OperationRef, paths)
+		}
+		return nil
+	})
+}
@@ -0,0 +1,92 @@
+package oas
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+)
+
+type AnonymizeSuite struct {
+	suite.Suite
+}
+
+func (r *AnonymizeSuite) TestAnonymize() {
+	patch := `
+paths:
+  /pets:
+    post:
+      responses:
+        "201":
+          links:
+            GetPet:
+              operationId: getPet
+            ByRef:
+              operationRef: "#/paths/~1pets~1{petId}/get"
+  /owners/{ownerId}/pets:
+    get:
+      responses:
+        "200":
+          description: Pets.
+components:
+  schemas:
+    Pet:
+      oneOf:
+        - $ref: "#/components/schemas/Dog"
+      discriminator:
+        propertyName: kind
+        mapping:
+          dog: Dog
+          cat: "#/components/schemas/Dog"
+    Dog:
+      type: object
+`
+	doc := petstore(r.T(), patch)
+	mapping, err := doc.Anonymize()
+	assert.Nil(r.T(), err)
+	assert.Equal(r.T(), &Anonymization{
+		Schemas: map[string]string{"Schema1": "Dog", "Schema2": "Pet"},
+		Operations: map[string]string{
+			"operation1": "listPets",
+			"operation2": "createPet",
+			"operation3": "getPet",
+			"operation4": "deletePet",
+		},
+		Paths: map[string]string{
+			"/segment1/{ownerId}/segment2": "/owners/{ownerId}/pets",
+			"/segment2":                    "/pets",
+			"/segment2/{petId}":            "/pets/{petId}",
+		},
+	}, mapping)
+
+	assert.Equal(r.T(), []string{"/segment1/{ownerId}/segment2", "/segment2", "/segment2/{petId}"}, sortedKeys(doc.Paths.PathItems))
+	post := doc.Paths.PathItems.Get("/segment2").Post
+	assert.Equal(r.T(), "operation2", post.OperationID)
+	assert.Equal(r.T(), "#/components/schemas/Schema2", post.RequestBody.Content["application/json"].Schema.Ref)
+	links := post.Responses.Get("201").Links
+	assert.Equal(r.T(), "operation3", links["GetPet"].OperationID)
+	assert.Equal(r.T(), "#/paths/~1segment2~1{petId}/get", links["ByRef"].OperationRef)
+	pet := doc.Components.Schemas["Schema2"]
+	assert.Equal(r.T(), "#/components/schemas/Schema1", pet.OneOf[0].Ref)
+	assert.Equal(r.T(), map[string]string{"dog": "Schema1", "cat": "#/components/schemas/Schema1"}, pet.Discriminator.Mapping)
+
+	assert.Nil(r.T(), doc.Deanonymize(mapping))
+	assert.Equal(r.T(), petstore(r.T(), patch), doc)
+}
+
+func (r *AnonymizeSuite) TestDuplicateOperationID() {
+	patch := `
+paths:
+  /pets/{petId}:
+    get:
+      operationId: createPet
+`
+	doc := petstore(r.T(), patch)
+	_, err := doc.Anonymize()
+	assert.NotNil(r.T(), err)
+	assert.Equal(r.T(), petstore(r.T(), patch), doc)
+}
+
+func TestAnonymizeSuite(t *testing.T) {
+	suite.Run(t, new(AnonymizeSuite))
+}
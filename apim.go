@@ -0,0 +1,308 @@
+package oas
+
+import (
+	"bytes"
+	"encoding/xml"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// APIManagementRateLimitExtension names the specification extension
+// declaring the rate limit Azure API Management enforces on an operation in
+// the terms of its rate-limit-by-key policy. It may be declared on the
+// operation, on the path item or on the document root, the closest
+// declaration taking precedence, and overrides the x-ratelimit extension
+// declared on the same object.
+const APIManagementRateLimitExtension = "x-rate-limit"
+
+// apimRateLimit describes the value of the x-rate-limit extension.
+type apimRateLimit struct {
+	Calls         int    `yaml:"calls"`
+	RenewalPeriod int    `yaml:"renewal-period"`
+	CounterKey    string `yaml:"counter-key"`
+}
+
+// apimDefaultCounterKey counts the calls of each client address.
+const apimDefaultCounterKey = "@(context.Request.IpAddress)"
+
+// APIManagementExport describes the artifacts imported into Azure API
+// Management.
+type APIManagementExport struct {
+	// Document describes the document trimmed to the constructs API
+	// Management imports.
+	Document *OpenAPI
+
+	// Policies describes the policy XML of each operation by operationId.
+	Policies map[string]string
+
+	// Removed describes the pointers of the constructs removed from the
+	// document, sorted.
+	Removed []string
+}
+
+// APIManagement returns the artifacts importing the document into Azure API
+// Management. API Management ignores callbacks, links, cookie parameters and
+// servers other than the first document server, so they are removed from the
+// copy of the document. Operations without operationId, which name the
+// operations of API Management, receive one derived from their method and
+// path. Each operation gets a policy stub validating the credentials of its
+// first security requirement and enforcing the x-rate-limit or x-ratelimit
+// extension.
+func (r OpenAPI) APIManagement() (*APIManagementExport, error) {
+	doc, err := r.Clone()
+	if err != nil {
+		return nil, err
+	}
+	export := &APIManagementExport{
+		Document: doc,
+		Policies: map[string]string{},
+		Removed:  make([]string, 0),
+	}
+	export.trim()
+
+	root, err := apimRateLimitOf(doc.Extensions)
+	if err != nil {
+		return nil, err
+	}
+	ids := map[string]bool{}
+	for _, op := range doc.Paths.operations() {
+		if op.operation.OperationID != "" {
+			ids[op.operation.OperationID] = true
+		}
+	}
+	for _, op := range doc.Paths.operations() {
+		ptr := join("/paths", op.path, op.method)
+		if op.operation.OperationID == "" {
+			op.operation.OperationID = uniqueOperationID(CamelCase.Format(op.method+" "+op.path), ids)
+		}
+		limit, err := apimRateLimitOf(op.operation.Extensions)
+		if err != nil {
+			return nil, errors.Wrap(err, ptr)
+		}
+		if limit == nil {
+			if limit, err = apimRateLimitOf(op.item.Extensions); err != nil {
+				return nil, errors.Wrap(err, join("/paths", op.path))
+			}
+		}
+		if limit == nil {
+			limit = root
+		}
+		requirements := doc.Security
+		if op.operation.Security != nil {
+			requirements = op.operation.Security
+		}
+		policy, err := doc.apimPolicy(requirements, limit)
+		if err != nil {
+			return nil, errors.Wrap(err, ptr)
+		}
+		export.Policies[op.operation.OperationID] = policy
+	}
+	sort.Strings(export.Removed)
+	return export, nil
+}
+
+// trim removes the constructs API Management does not import.
+func (r *APIManagementExport) trim() {
+	doc := r.Document
+	if len(doc.Servers) > 1 {
+		for i := 1; i < len(doc.Servers); i++ {
+			r.Removed = append(r.Removed, index("", "servers", i))
+		}
+		doc.Servers = doc.Servers[:1]
+	}
+	for _, path := range sortedKeys(doc.Paths.PathItems) {
+		item := doc.Paths.PathItems.Get(path)
+		if item == nil {
+			continue
+		}
+		if len(item.Servers) > 0 {
+			r.Removed = append(r.Removed, join("/paths", path, "servers"))
+			item.Servers = nil
+		}
+		item.Parameters = r.trimParameters(join("/paths", path), item.Parameters)
+	}
+	for _, op := range doc.Paths.operations() {
+		ptr := join("/paths", op.path, op.method)
+		if len(op.operation.Callbacks) > 0 {
+			r.Removed = append(r.Removed, join(ptr, "callbacks"))
+			op.operation.Callbacks = nil
+		}
+		if len(op.operation.Servers) > 0 {
+			r.Removed = append(r.Removed, join(ptr, "servers"))
+			op.operation.Servers = nil
+		}
+		op.operation.Parameters = r.trimParameters(ptr, op.operation.Parameters)
+		for _, code := range sortedKeys(op.operation.Responses) {
+			r.trimLinks(join(ptr, "responses", code), op.operation.Responses.Get(code))
+		}
+	}
+	if doc.Components == nil {
+		return
+	}
+	for _, name := range sortedKeys(doc.Components.Responses) {
+		r.trimLinks(join("/components/responses", name), doc.Components.Responses[name])
+	}
+	for _, name := range sortedKeys(doc.Components.Parameters) {
+		if parameter := doc.Components.Parameters[name]; parameter != nil && parameter.In == "cookie" {
+			r.Removed = append(r.Removed, join("/components/parameters", name))
+			delete(doc.Components.Parameters, name)
+		}
+	}
+	if len(doc.Components.Callbacks) > 0 {
+		r.Removed = append(r.Removed, "/components/callbacks")
+		doc.Components.Callbacks = nil
+	}
+	if len(doc.Components.Links) > 0 {
+		r.Removed = append(r.Removed, "/components/links")
+		doc.Components.Links = nil
+	}
+}
+
+// trimParameters returns the parameters of the object at the pointer
+// without cookie parameters.
+func (r *APIManagementExport) trimParameters(ptr string, parameters []*Parameter) []*Parameter {
+	kept := parameters[:0]
+	for i, parameter := range parameters {
+		if _, resolved := NewResolver(r.Document).parameter("", parameter); resolved != nil && resolved.In == "cookie" {
+			r.Removed = append(r.Removed, index(ptr, "parameters", i))
+			continue
+		}
+		kept = append(kept, parameter)
+	}
+	return kept
+}
+
+// trimLinks removes the links of the response.
+func (r *APIManagementExport) trimLinks(ptr string, response *Response) {
+	if response != nil && len(response.Links) > 0 {
+		r.Removed = append(r.Removed, join(ptr, "links"))
+		response.Links = nil
+	}
+}
+
+// apimRateLimitOf returns the rate limit declared with the x-rate-limit
+// extension or, failing that, the x-ratelimit extension, nil if neither is
+// declared.
+func apimRateLimitOf(extensions Extensions) (*apimRateLimit, error) {
+	limit := &apimRateLimit{}
+	ok, err := extensions.decode(APIManagementRateLimitExtension, limit)
+	if err != nil {
+		return nil, err
+	}
+	if !ok {
+		return apimGenericRateLimit(extensions)
+	}
+	if limit.Calls <= 0 || limit.RenewalPeriod <= 0 {
+		return nil, errors.Errorf("%s: calls and renewal-period must be positive", APIManagementRateLimitExtension)
+	}
+	if limit.CounterKey == "" {
+		limit.CounterKey = apimDefaultCounterKey
+	}
+	return limit, nil
+}
+
+// apimGenericRateLimit returns the rate limit declared with the x-ratelimit
+// extension, nil if it is not declared. API Management renews counters every
+// whole number of seconds.
+func apimGenericRateLimit(extensions Extensions) (*apimRateLimit, error) {
+	generic, err := extensions.RateLimit()
+	if generic == nil || err != nil {
+		return nil, err
+	}
+	window, _ := generic.WindowDuration()
+	if window%time.Second != 0 {
+		return nil, errors.Errorf("%s: window %q is not a whole number of seconds", RateLimitExtension, generic.Window)
+	}
+	limit := &apimRateLimit{
+		Calls:         generic.Limit,
+		RenewalPeriod: int(window / time.Second),
+		CounterKey:    apimDefaultCounterKey,
+	}
+	if header := generic.Header(); header != "" {
+		limit.CounterKey = fmt.Sprintf("@(context.Request.Headers.GetValueOrDefault(%q, \"\"))", header)
+	}
+	return limit, nil
+}
+
+// apimPolicy returns the policy XML of an operation protected by the
+// security requirements and rate limit.
+func (r OpenAPI) apimPolicy(requirements []*SecurityRequirement, limit *apimRateLimit) (string, error) {
+	buf := &bytes.Buffer{}
+	buf.WriteString("<policies>\n\t<inbound>\n\t\t<base />\n")
+	var requirement SecurityRequirement
+	for _, candidate := range requirements {
+		if candidate != nil && len(*candidate) > 0 {
+			requirement = *candidate
+			break
+		}
+	}
+	if len(requirements) > 1 {
+		buf.WriteString("\t\t<!-- alternative security requirements are not enforced -->\n")
+	}
+	for _, name := range sortedStrings(requirement) {
+		var scheme *SecurityScheme
+		if r.Components != nil {
+			scheme = r.Components.SecuritySchemes[name]
+		}
+		if scheme == nil {
+			return "", errors.Errorf("undefined security scheme %q", name)
+		}
+		apimSecurityPolicy(buf, name, scheme, requirement[name])
+	}
+	if limit != nil {
+		fmt.Fprintf(buf, "\t\t<rate-limit-by-key calls=\"%d\" renewal-period=\"%d\" counter-key=\"%s\" />\n",
+			limit.Calls, limit.RenewalPeriod, apimEscape(limit.CounterKey))
+	}
+	buf.WriteString("\t</inbound>\n")
+	buf.WriteString("\t<backend>\n\t\t<base />\n\t</backend>\n")
+	buf.WriteString("\t<outbound>\n\t\t<base />\n\t</outbound>\n")
+	buf.WriteString("\t<on-error>\n\t\t<base />\n\t</on-error>\n")
+	buf.WriteString("</policies>\n")
+	return buf.String(), nil
+}
+
+// apimSecurityPolicy writes the policy validating the credentials of the
+// security scheme.
+func apimSecurityPolicy(buf *bytes.Buffer, name string, scheme *SecurityScheme, scopes []string) {
+	switch {
+	case scheme.Type == "apiKey" && scheme.In == "header":
+		fmt.Fprintf(buf, "\t\t<check-header name=\"%s\" failed-check-httpcode=\"401\" failed-check-error-message=\"Unauthorized\" ignore-case=\"true\" />\n",
+			apimEscape(scheme.Name))
+	case scheme.Type == "http" && strings.EqualFold(scheme.Scheme, "basic"):
+		buf.WriteString("\t\t<check-header name=\"Authorization\" failed-check-httpcode=\"401\" failed-check-error-message=\"Unauthorized\" ignore-case=\"true\" />\n")
+	case scheme.Type == "http" && strings.EqualFold(scheme.Scheme, "bearer"),
+		scheme.Type == "oauth2", scheme.Type == "openIdConnect":
+		buf.WriteString("\t\t<validate-jwt header-name=\"Authorization\" failed-validation-httpcode=\"401\" failed-validation-error-message=\"Unauthorized\">\n")
+		if scheme.Type == "openIdConnect" && scheme.OpenIDConnectURL != "" {
+			fmt.Fprintf(buf, "\t\t\t<openid-config url=\"%s\" />\n", apimEscape(scheme.OpenIDConnectURL))
+		} else {
+			fmt.Fprintf(buf, "\t\t\t<!-- configure the signing keys of %s -->\n", apimComment(name))
+		}
+		if len(scopes) > 0 {
+			buf.WriteString("\t\t\t<required-claims>\n\t\t\t\t<claim name=\"scope\" match=\"all\" separator=\" \">\n")
+			for _, scope := range scopes {
+				fmt.Fprintf(buf, "\t\t\t\t\t<value>%s</value>\n", apimEscape(scope))
+			}
+			buf.WriteString("\t\t\t\t</claim>\n\t\t\t</required-claims>\n")
+		}
+		buf.WriteString("\t\t</validate-jwt>\n")
+	default:
+		fmt.Fprintf(buf, "\t\t<!-- validate the credentials of %s -->\n", apimComment(name))
+	}
+}
+
+// apimEscape escapes the text for XML attribute and element values.
+func apimEscape(text string) string {
+	buf := &bytes.Buffer{}
+	_ = xml.EscapeText(buf, []byte(text))
+	return buf.String()
+}
+
+// apimComment makes the text safe to embed in an XML comment.
+func apimComment(text string) string {
+	return strings.Replace(text, "--", "- -", -1)
+}
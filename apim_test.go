@@ -0,0 +1,184 @@
+package oas
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+)
+
+type APIManagementSuite struct {
+	suite.Suite
+}
+
+// apimPatch is the patch of the petstore fixture the suite exports.
+const apimPatch = `
+servers:
+  - url: https://api.example.com
+  - url: https://staging.example.com
+paths:
+  /pets:
+    get:
+      parameters:
+        - name: session
+          in: cookie
+        - name: limit
+          in: query
+      responses:
+        "200":
+          links:
+            next:
+              operationId: listPets
+      security:
+        - petstoreAuth: [read:pets]
+        - apiKey: []
+      x-rate-limit:
+        calls: 10
+        renewal-period: 60
+    post:
+      operationId: null
+      callbacks:
+        onCreated: {}
+components:
+  securitySchemes:
+    apiKey:
+      type: apiKey
+      name: X-API-Key
+      in: header
+    petstoreAuth:
+      type: openIdConnect
+      openIdConnectUrl: https://login.example.com/.well-known/openid-configuration
+security:
+  - apiKey: []
+x-rate-limit:
+  calls: 100
+  renewal-period: 3600
+  counter-key: "@(context.Subscription.Id)"
+`
+
+func (r *APIManagementSuite) TestAPIManagement() {
+	export, err := petstore(r.T(), apimPatch).APIManagement()
+	assert.Nil(r.T(), err)
+
+	assert.Equal(r.T(), []string{
+		"/paths/~1pets/get/parameters/0",
+		"/paths/~1pets/get/responses/200/links",
+		"/paths/~1pets/post/callbacks",
+		"/servers/1",
+	}, export.Removed)
+	doc := export.Document
+	assert.Len(r.T(), doc.Servers, 1)
+	assert.Equal(r.T(), []*Parameter{{Name: "limit", In: "query"}}, doc.Paths.PathItems.Get("/pets").Get.Parameters)
+	assert.Equal(r.T(), "postPets", doc.Paths.PathItems.Get("/pets").Post.OperationID)
+	assert.Len(r.T(), petstore(r.T(), apimPatch).Paths.PathItems.Get("/pets").Get.Parameters, 2)
+
+	assert.Equal(r.T(), `<policies>
+	<inbound>
+		<base />
+		<!-- alternative security requirements are not enforced -->
+		<validate-jwt header-name="Authorization" failed-validation-httpcode="401" failed-validation-error-message="Unauthorized">
+			<openid-config url="https://login.example.com/.well-known/openid-configuration" />
+			<required-claims>
+				<claim name="scope" match="all" separator=" ">
+					<value>read:pets</value>
+				</claim>
+			</required-claims>
+		</validate-jwt>
+		<rate-limit-by-key calls="10" renewal-period="60" counter-key="@(context.Request.IpAddress)" />
+	</inbound>
+	<backend>
+		<base />
+	</backend>
+	<outbound>
+		<base />
+	</outbound>
+	<on-error>
+		<base />
+	</on-error>
+</policies>
+`, export.Policies["listPets"])
+
+	assert.Equal(r.T(), `<policies>
+	<inbound>
+		<base />
+		<check-header name="X-API-Key" failed-check-httpcode="401" failed-check-error-message="Unauthorized" ignore-case="true" />
+		<rate-limit-by-key calls="100" renewal-period="3600" counter-key="@(context.Subscription.Id)" />
+	</inbound>
+	<backend>
+		<base />
+	</backend>
+	<outbound>
+		<base />
+	</outbound>
+	<on-error>
+		<base />
+	</on-error>
+</policies>
+`, export.Policies["postPets"])
+}
+
+func (r *APIManagementSuite) TestAPIManagementRateLimit() {
+	ratelimit := `
+paths:
+  /pets:
+    get:
+      x-rate-limit: null
+    x-ratelimit:
+      limit: 5
+      window: 1m
+      key: header:X-API-Key
+`
+	export, err := petstore(r.T(), apimPatch, ratelimit).APIManagement()
+	assert.Nil(r.T(), err)
+	assert.Contains(r.T(), export.Policies["listPets"],
+		`<rate-limit-by-key calls="5" renewal-period="60" counter-key="@(context.Request.Headers.GetValueOrDefault(&#34;X-API-Key&#34;, &#34;&#34;))" />`)
+	assert.Contains(r.T(), export.Policies["postPets"], `calls="5"`)
+
+	export, err = petstore(r.T(), apimPatch, ratelimit, `
+paths:
+  /pets:
+    post:
+      x-rate-limit:
+        calls: 1
+        renewal-period: 1
+`).APIManagement()
+	assert.Nil(r.T(), err)
+	assert.Contains(r.T(), export.Policies["postPets"], `calls="1" renewal-period="1"`)
+}
+
+func (r *APIManagementSuite) TestAPIManagementErrors() {
+	testCases := []struct {
+		patch string
+	}{
+		{`
+security:
+  - unknown: []
+`},
+		{`
+x-rate-limit:
+  renewal-period: null
+`},
+		{`
+paths:
+  /pets:
+    get:
+      x-rate-limit: often
+`},
+		{`
+x-rate-limit: null
+x-ratelimit:
+  limit: 10
+  window: 1500ms
+`},
+	}
+
+	for i, testCase := range testCases {
+		failMsg := "test case %d failed"
+		_, err := petstore(r.T(), apimPatch, testCase.patch).APIManagement()
+		assert.NotNil(r.T(), err, failMsg, i)
+	}
+}
+
+func TestAPIManagementSuite(t *testing.T) {
+	suite.Run(t, new(APIManagementSuite))
+}
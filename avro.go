@@ -0,0 +1,204 @@
+package oas
+
+import (
+	"regexp"
+	"strconv"
+
+	"github.com/pkg/errors"
+)
+
+// avroName matches the names Avro allows for records, fields and enum
+// symbols.
+var avroName = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+
+// AvroSchema converts the named schema of the components into an Avro
+// schema made of strings, maps and slices, ready to be marshalled to JSON.
+// Referenced schemas become named records and enums declared where they are
+// first used and referenced by name afterwards, which also supports
+// recursive schemas. Nested object schemas become records named after the
+// record and the field declaring them. Properties which are optional or
+// nullable become unions with null defaulting to null. oneOf and anyOf
+// become unions while allOf and untyped schemas are not supported.
+func (r OpenAPI) AvroSchema(name string, namespace string) (interface{}, error) {
+	if r.Components == nil || r.Components.Schemas[name] == nil {
+		return nil, errors.Errorf("missing schema %q", name)
+	}
+	converter := &avroConverter{doc: r, defined: map[string]bool{}, visiting: map[string]bool{}}
+	schema, err := converter.named(name)
+	if err != nil {
+		return nil, err
+	}
+	if record, ok := schema.(map[string]interface{}); ok && namespace != "" {
+		record["namespace"] = namespace
+	}
+	return schema, nil
+}
+
+// avroConverter tracks the named types declared while converting a schema.
+type avroConverter struct {
+	doc      OpenAPI
+	defined  map[string]bool
+	visiting map[string]bool
+}
+
+// named converts the schema of the components, referencing it by name if
+// it was already declared.
+func (r *avroConverter) named(name string) (interface{}, error) {
+	if r.defined[name] {
+		return name, nil
+	}
+	if !avroName.MatchString(name) {
+		return nil, errors.Errorf("schema name %q is not a valid Avro name", name)
+	}
+	if r.visiting[name] {
+		return nil, errors.Errorf("schema %q is recursive through an unnamed Avro type", name)
+	}
+	schema := NewResolver(&r.doc).schema(r.doc.Components.Schemas[name])
+	if schema == nil {
+		return nil, errors.Errorf("unresolvable schema %q", name)
+	}
+	r.visiting[name] = true
+	defer delete(r.visiting, name)
+	return r.convert(name, schema)
+}
+
+// convert converts the schema, naming the record or enum it produces after
+// the name.
+func (r *avroConverter) convert(name string, schema *Schema) (interface{}, error) {
+	if schema == nil {
+		return nil, errors.Errorf("schema %q: missing schema", name)
+	}
+	if schema.Ref != "" {
+		ref, ok := componentName(schema.Ref, "schemas")
+		if !ok || r.doc.Components.Schemas[ref] == nil {
+			return nil, errors.Errorf("unresolvable reference %q", schema.Ref)
+		}
+		return r.named(ref)
+	}
+
+	if len(schema.AllOf) > 0 {
+		return nil, errors.Errorf("schema %q: allOf is not supported", name)
+	}
+	if members := append(append([]*Schema{}, schema.OneOf...), schema.AnyOf...); len(members) > 0 {
+		union := make([]interface{}, 0, len(members))
+		for i, member := range members {
+			value, err := r.convert(name+"Option"+strconv.Itoa(i+1), member)
+			if err != nil {
+				return nil, err
+			}
+			union = append(union, value)
+		}
+		return union, nil
+	}
+
+	switch schema.Type {
+	case "boolean":
+		return "boolean", nil
+	case "integer":
+		if schema.Format == "int64" {
+			return "long", nil
+		}
+		return "int", nil
+	case "number":
+		if schema.Format == "float" {
+			return "float", nil
+		}
+		return "double", nil
+	case "string":
+		return r.convertString(name, schema), nil
+	case "array":
+		if schema.Items == nil {
+			return nil, errors.Errorf("schema %q: array without items", name)
+		}
+		items, err := r.convert(name+"Item", schema.Items)
+		if err != nil {
+			return nil, err
+		}
+		return map[string]interface{}{"type": "array", "items": items}, nil
+	case "object":
+		if schema.Properties.Len() == 0 && schema.AdditionalProperties != nil {
+			values, err := r.convert(name+"Value", schema.AdditionalProperties)
+			if err != nil {
+				return nil, err
+			}
+			return map[string]interface{}{"type": "map", "values": values}, nil
+		}
+		return r.convertRecord(name, schema)
+	}
+	return nil, errors.Errorf("schema %q: untyped schemas are not supported", name)
+}
+
+// convertString converts a string schema, mapping enums whose values are
+// valid Avro symbols to Avro enums and known formats to logical types.
+func (r *avroConverter) convertString(name string, schema *Schema) interface{} {
+	if len(schema.Enum) > 0 && !r.defined[name] {
+		symbols := make([]interface{}, 0, len(schema.Enum))
+		for _, value := range schema.Enum {
+			if symbol, ok := value.(string); ok && avroName.MatchString(symbol) {
+				symbols = append(symbols, symbol)
+			}
+		}
+		if len(symbols) == len(schema.Enum) {
+			r.defined[name] = true
+			return map[string]interface{}{"type": "enum", "name": name, "symbols": symbols}
+		}
+	}
+
+	switch schema.Format {
+	case "date":
+		return map[string]interface{}{"type": "int", "logicalType": "date"}
+	case "date-time":
+		return map[string]interface{}{"type": "long", "logicalType": "timestamp-millis"}
+	case "uuid":
+		return map[string]interface{}{"type": "string", "logicalType": "uuid"}
+	case "byte", "binary":
+		return "bytes"
+	}
+	return "string"
+}
+
+// convertRecord converts an object schema into a record whose fields are
+// ordered by name.
+func (r *avroConverter) convertRecord(name string, schema *Schema) (interface{}, error) {
+	if r.defined[name] {
+		return nil, errors.Errorf("schema %q: record name already declared", name)
+	}
+	r.defined[name] = true
+
+	required := map[string]bool{}
+	for _, property := range schema.Required {
+		required[property] = true
+	}
+	fields := make([]interface{}, 0, schema.Properties.Len())
+	for _, property := range sortedKeys(schema.Properties) {
+		if !avroName.MatchString(property) {
+			return nil, errors.Errorf("schema %q: property %q is not a valid Avro name", name, property)
+		}
+		value, err := r.convert(name+PascalCase.Format(property), schema.Properties.Get(property))
+		if err != nil {
+			return nil, err
+		}
+		field := map[string]interface{}{"name": property, "type": value}
+		resolved := NewResolver(&r.doc).schema(schema.Properties.Get(property))
+		if !required[property] || (resolved != nil && resolved.Nullable) {
+			if union, ok := value.([]interface{}); ok {
+				if len(union) == 0 || union[0] != "null" {
+					field["type"] = append([]interface{}{"null"}, union...)
+				}
+			} else {
+				field["type"] = []interface{}{"null", value}
+			}
+			field["default"] = nil
+		}
+		if description := schema.Properties.Get(property).Description; description != "" {
+			field["doc"] = description
+		}
+		fields = append(fields, field)
+	}
+
+	record := map[string]interface{}{"type": "record", "name": name, "fields": fields}
+	if schema.Description != "" {
+		record["doc"] = schema.Description
+	}
+	return record, nil
+}
@@ -0,0 +1,109 @@
+package oas
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+)
+
+type AvroSuite struct {
+	suite.Suite
+}
+
+// avroPatch is the patch of the petstore fixture the suite converts.
+const avroPatch = `
+components:
+  schemas:
+    Pet:
+      description: A pet.
+      required: [id, status, parent]
+      properties:
+        name: null
+        tag: null
+        born:
+          type: string
+          format: date
+        history:
+          type: array
+          items:
+            $ref: "#/components/schemas/Status"
+        id:
+          type: integer
+          format: int64
+        labels:
+          type: object
+          additionalProperties:
+            type: string
+        owner:
+          type: object
+          properties:
+            name:
+              type: string
+              description: Name.
+        parent:
+          $ref: "#/components/schemas/Pet"
+          nullable: true
+        status:
+          $ref: "#/components/schemas/Status"
+        tags:
+          type: array
+          items:
+            type: string
+    Status:
+      type: string
+      enum: [available, sold]
+    Tree:
+      type: array
+      items:
+        $ref: "#/components/schemas/Tree"
+    Mixed:
+      type: object
+      properties:
+        value: {}
+    Base:
+      allOf:
+        - $ref: "#/components/schemas/Pet"
+`
+
+func (r *AvroSuite) TestAvroSchema() {
+	schema, err := petstore(r.T(), avroPatch).AvroSchema("Pet", "io.petstore")
+	assert.Nil(r.T(), err)
+	data, err := json.Marshal(schema)
+	assert.Nil(r.T(), err)
+	assert.JSONEq(r.T(), `{
+		"type": "record",
+		"name": "Pet",
+		"namespace": "io.petstore",
+		"doc": "A pet.",
+		"fields": [
+			{"name": "born", "type": ["null", {"type": "int", "logicalType": "date"}], "default": null},
+			{"name": "history", "type": ["null", {"type": "array", "items": {"type": "enum", "name": "Status", "symbols": ["available", "sold"]}}], "default": null},
+			{"name": "id", "type": "long"},
+			{"name": "labels", "type": ["null", {"type": "map", "values": "string"}], "default": null},
+			{"name": "owner", "type": ["null", {
+				"type": "record",
+				"name": "PetOwner",
+				"fields": [{"name": "name", "type": ["null", "string"], "default": null, "doc": "Name."}]
+			}], "default": null},
+			{"name": "parent", "type": "Pet"},
+			{"name": "status", "type": "Status"},
+			{"name": "tags", "type": ["null", {"type": "array", "items": "string"}], "default": null}
+		]
+	}`, string(data))
+}
+
+func (r *AvroSuite) TestErrors() {
+	testCases := []string{"Missing", "Tree", "Mixed", "Base"}
+
+	for i, name := range testCases {
+		failMsg := "test case %d failed"
+		_, err := petstore(r.T(), avroPatch).AvroSchema(name, "")
+		assert.NotNil(r.T(), err, failMsg, i)
+	}
+}
+
+func TestAvroSuite(t *testing.T) {
+	suite.Run(t, new(AvroSuite))
+}
@@ -0,0 +1,271 @@
+package oas
+
+import (
+	"sort"
+
+	"github.com/pkg/errors"
+)
+
+// BatchExtension names the specification extension listing the operationIds
+// a batch operation generated by DeclareBatch dispatches to.
+const BatchExtension = "x-batch"
+
+// BatchOptions describes the behavior of DeclareBatch.
+type BatchOptions struct {
+	// Path describes the path of the batch operation. Defaults to "/batch".
+	Path string
+
+	// Operations describes the operationIds of the operations the batch
+	// dispatches to.
+	Operations []string
+
+	// Prefix describes the prefix of the names of the generated component
+	// schemas. Defaults to "Batch".
+	Prefix string
+}
+
+// DeclareBatch declares a POST batch operation accepting an array of
+// requests to the operations of the options and returning an array of their
+// responses. Each request and response is a schema per operation, selected
+// by its operationId, whose parameters, body and response bodies reference
+// the schemas of the original operation, so the batch contract follows every
+// change of the individual ones. Running it again regenerates the batch
+// operation and its schemas, dropping the schemas of operations no longer
+// batched.
+func (r *OpenAPI) DeclareBatch(opts BatchOptions) error {
+	if opts.Path == "" {
+		opts.Path = "/batch"
+	}
+	if opts.Prefix == "" {
+		opts.Prefix = "Batch"
+	}
+	if len(opts.Operations) == 0 {
+		return errors.New("no operations to batch")
+	}
+	if item := r.Paths.PathItems.Get(opts.Path); item != nil {
+		for _, op := range item.operations() {
+			if op.operation.Extensions[BatchExtension] == nil {
+				return errors.Errorf("path %q is already declared", opts.Path)
+			}
+		}
+	}
+
+	ids := append([]string{}, opts.Operations...)
+	sort.Strings(ids)
+	requests := make([]*Schema, 0, len(ids))
+	responses := make([]*Schema, 0, len(ids))
+	requestMapping := map[string]string{}
+	responseMapping := map[string]string{}
+	schemas := map[string]*Schema{}
+	for _, id := range ids {
+		op, ok := r.operationByID(id)
+		if !ok {
+			return errors.Errorf("operation %q not found", id)
+		}
+		if op.path == opts.Path {
+			return errors.Errorf("operation %q cannot be batched by itself", id)
+		}
+		name := opts.Prefix + PascalCase.Format(id)
+		schemas[name+"Request"] = r.batchRequest(op)
+		schemas[name+"Response"] = r.batchResponse(op)
+		requests = append(requests, &Schema{Ref: "#/components/schemas/" + name + "Request"})
+		responses = append(responses, &Schema{Ref: "#/components/schemas/" + name + "Response"})
+		requestMapping[id] = "#/components/schemas/" + name + "Request"
+		responseMapping[id] = "#/components/schemas/" + name + "Response"
+	}
+
+	schemas[opts.Prefix+"Request"] = &Schema{
+		Type:     "object",
+		Required: []string{"requests"},
+		Properties: NewProperties(map[string]*Schema{"requests": {
+			Type:     "array",
+			MinItems: 1,
+			Items: &Schema{
+				OneOf:         requests,
+				Discriminator: &Discriminator{PropertyName: "operationId", Mapping: requestMapping},
+			},
+		}}),
+	}
+	schemas[opts.Prefix+"Response"] = &Schema{
+		Type:     "object",
+		Required: []string{"responses"},
+		Properties: NewProperties(map[string]*Schema{"responses": {
+			Type: "array",
+			Items: &Schema{
+				OneOf:         responses,
+				Discriminator: &Discriminator{PropertyName: "operationId", Mapping: responseMapping},
+			},
+		}}),
+	}
+
+	if r.Components == nil {
+		r.Components = &Components{}
+	}
+	if r.Components.Schemas == nil {
+		r.Components.Schemas = map[string]*Schema{}
+	}
+	if item := r.Paths.PathItems.Get(opts.Path); item != nil && item.Post != nil {
+		var previous []string
+		_, _ = item.Post.Extensions.decode(BatchExtension, &previous)
+		for _, id := range previous {
+			delete(r.Components.Schemas, opts.Prefix+PascalCase.Format(id)+"Request")
+			delete(r.Components.Schemas, opts.Prefix+PascalCase.Format(id)+"Response")
+		}
+	}
+	for name, schema := range schemas {
+		r.Components.Schemas[name] = schema
+	}
+
+	batched := make([]interface{}, 0, len(ids))
+	for _, id := range ids {
+		batched = append(batched, id)
+	}
+	operation := &Operation{
+		OperationID: CamelCase.Format(opts.Prefix),
+		Summary:     "Sends several requests at once.",
+		RequestBody: &RequestBody{
+			Required: true,
+			Content: map[string]*MediaType{"application/json": {
+				Schema: &Schema{Ref: "#/components/schemas/" + opts.Prefix + "Request"},
+			}},
+		},
+		Responses: NewResponses(map[string]*Response{"200": {
+			Description: "The responses, in the order of the requests.",
+			Content: map[string]*MediaType{"application/json": {
+				Schema: &Schema{Ref: "#/components/schemas/" + opts.Prefix + "Response"},
+			}},
+		}}),
+	}
+	setExtension(&operation.Extensions, BatchExtension, batched)
+	if r.Paths.PathItems.Get(opts.Path) == nil {
+		r.Paths.PathItems.Set(opts.Path, &PathItem{})
+	}
+	r.Paths.PathItems.Get(opts.Path).Post = operation
+	return nil
+}
+
+// batchRequest returns the schema of a request to the operation within a
+// batch.
+func (r OpenAPI) batchRequest(op pathOperation) *Schema {
+	ptr := join("/paths", op.path, op.method)
+	schema := &Schema{
+		Type:     "object",
+		Required: []string{"operationId"},
+		Properties: NewProperties(map[string]*Schema{
+			"id": {
+				Type:        "string",
+				Description: "Identifier of the request, echoed by its response.",
+			},
+			"operationId": {Type: "string", Enum: []interface{}{op.operation.OperationID}},
+		}),
+	}
+
+	parameters := &Schema{Type: "object"}
+	declare := func(ptr string, parameter *Parameter) {
+		ptr, parameter = NewResolver(&r).parameter(ptr, parameter)
+		if parameter == nil || parameter.Schema == nil {
+			return
+		}
+		parameters.Properties.Set(parameter.Name, batchReference(join(ptr, "schema"), parameter.Schema))
+		if parameter.Required {
+			parameters.Required = append(parameters.Required, parameter.Name)
+		}
+	}
+	for i, parameter := range op.item.Parameters {
+		declare(index(join("/paths", op.path), "parameters", i), parameter)
+	}
+	for i, parameter := range op.operation.Parameters {
+		declare(index(ptr, "parameters", i), parameter)
+	}
+	if parameters.Properties.Len() > 0 {
+		parameters.Required = uniqueSorted(parameters.Required)
+		schema.Properties.Set("parameters", parameters)
+		if len(parameters.Required) > 0 {
+			schema.Required = append(schema.Required, "parameters")
+		}
+	}
+
+	bodyPtr, body := NewResolver(&r).requestBody(join(ptr, "requestBody"), op.operation.RequestBody)
+	if body != nil {
+		for _, mediaType := range sortedKeys(body.Content) {
+			if body.Content[mediaType] == nil || body.Content[mediaType].Schema == nil || !isJSONMediaType(mediaType) {
+				continue
+			}
+			schema.Properties.Set("body", batchReference(join(bodyPtr, "content", mediaType, "schema"), body.Content[mediaType].Schema))
+			if body.Required {
+				schema.Required = append(schema.Required, "body")
+			}
+			break
+		}
+	}
+	return schema
+}
+
+// batchResponse returns the schema of a response of the operation within a
+// batch.
+func (r OpenAPI) batchResponse(op pathOperation) *Schema {
+	ptr := join("/paths", op.path, op.method)
+	schema := &Schema{
+		Type:     "object",
+		Required: []string{"operationId", "status"},
+		Properties: NewProperties(map[string]*Schema{
+			"id": {
+				Type:        "string",
+				Description: "Identifier of the request the response answers.",
+			},
+			"operationId": {Type: "string", Enum: []interface{}{op.operation.OperationID}},
+			"status":      {Type: "integer", Description: "Status code of the response."},
+		}),
+	}
+
+	bodies := make([]*Schema, 0)
+	seen := map[string]bool{}
+	for _, code := range sortedKeys(op.operation.Responses) {
+		responsePtr, response := NewResolver(&r).response(join(ptr, "responses", code), op.operation.Responses.Get(code))
+		if response == nil {
+			continue
+		}
+		for _, mediaType := range sortedKeys(response.Content) {
+			if response.Content[mediaType] == nil || response.Content[mediaType].Schema == nil || !isJSONMediaType(mediaType) {
+				continue
+			}
+			body := batchReference(join(responsePtr, "content", mediaType, "schema"), response.Content[mediaType].Schema)
+			if !seen[body.Ref] {
+				seen[body.Ref] = true
+				bodies = append(bodies, body)
+			}
+			break
+		}
+	}
+	switch len(bodies) {
+	case 0:
+	case 1:
+		schema.Properties.Set("body", bodies[0])
+	default:
+		schema.Properties.Set("body", &Schema{OneOf: bodies})
+	}
+	return schema
+}
+
+// batchReference returns a reference to the schema at the pointer, or the
+// reference the schema holds.
+func batchReference(ptr string, schema *Schema) *Schema {
+	if schema.Ref != "" {
+		return &Schema{Ref: schema.Ref}
+	}
+	return &Schema{Ref: "#" + ptr}
+}
+
+// uniqueSorted returns the sorted values without duplicates.
+func uniqueSorted(values []string) []string {
+	seen := map[string]bool{}
+	unique := make([]string, 0, len(values))
+	for _, value := range values {
+		if !seen[value] {
+			seen[value] = true
+			unique = append(unique, value)
+		}
+	}
+	sort.Strings(unique)
+	return unique
+}
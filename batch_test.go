@@ -0,0 +1,103 @@
+package oas
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+)
+
+type BatchSuite struct {
+	suite.Suite
+}
+
+// batchPatch is the patch of the petstore fixture the suite batches.
+const batchPatch = `
+paths:
+  /pets:
+    post:
+      responses:
+        "201":
+          content:
+            application/json:
+              schema:
+                $ref: "#/components/schemas/Pet"
+        "400":
+          $ref: "#/components/responses/Problem"
+  /pets/{petId}:
+    delete:
+      parameters:
+        - name: reason
+          in: query
+          schema:
+            type: string
+components:
+  responses:
+    Problem:
+      description: A problem.
+      content:
+        application/problem+json:
+          schema:
+            type: object
+`
+
+func (r *BatchSuite) TestDeclareBatch() {
+	doc := petstore(r.T(), batchPatch)
+	assert.Nil(r.T(), doc.DeclareBatch(BatchOptions{Operations: []string{"deletePet", "createPet"}}))
+
+	operation := doc.Paths.PathItems.Get("/batch").Post
+	assert.Equal(r.T(), "batch", operation.OperationID)
+	assert.Equal(r.T(), []interface{}{"createPet", "deletePet"}, operation.Extensions[BatchExtension])
+	assert.Equal(r.T(), "#/components/schemas/BatchRequest", operation.RequestBody.Content["application/json"].Schema.Ref)
+
+	items := doc.Components.Schemas["BatchRequest"].Properties.Get("requests").Items
+	assert.Equal(r.T(), []*Schema{
+		{Ref: "#/components/schemas/BatchCreatePetRequest"},
+		{Ref: "#/components/schemas/BatchDeletePetRequest"},
+	}, items.OneOf)
+	assert.Equal(r.T(), "#/components/schemas/BatchDeletePetRequest", items.Discriminator.Mapping["deletePet"])
+
+	request := doc.Components.Schemas["BatchCreatePetRequest"]
+	assert.Equal(r.T(), []string{"operationId", "body"}, request.Required)
+	assert.Equal(r.T(), &Schema{Ref: "#/components/schemas/Pet"}, request.Properties.Get("body"))
+
+	request = doc.Components.Schemas["BatchDeletePetRequest"]
+	assert.Equal(r.T(), []string{"operationId", "parameters"}, request.Required)
+	assert.Equal(r.T(), []string{"petId"}, request.Properties.Get("parameters").Required)
+	assert.Equal(r.T(), NewProperties(map[string]*Schema{
+		"petId":  {Ref: "#/paths/~1pets~1{petId}/parameters/0/schema"},
+		"reason": {Ref: "#/paths/~1pets~1{petId}/delete/parameters/0/schema"},
+	}), request.Properties.Get("parameters").Properties)
+
+	response := doc.Components.Schemas["BatchCreatePetResponse"]
+	assert.Equal(r.T(), &Schema{OneOf: []*Schema{
+		{Ref: "#/components/schemas/Pet"},
+		{Ref: "#/components/responses/Problem/content/application~1problem+json/schema"},
+	}}, response.Properties.Get("body"))
+	assert.Nil(r.T(), doc.Components.Schemas["BatchDeletePetResponse"].Properties.Get("body"))
+
+	assert.Nil(r.T(), doc.DeclareBatch(BatchOptions{Operations: []string{"createPet"}}))
+	assert.Nil(r.T(), doc.Components.Schemas["BatchDeletePetRequest"])
+	assert.NotNil(r.T(), doc.Components.Schemas["BatchCreatePetRequest"])
+}
+
+func (r *BatchSuite) TestDeclareBatchErrors() {
+	testCases := []struct {
+		opts BatchOptions
+	}{
+		{BatchOptions{}},
+		{BatchOptions{Operations: []string{"updatePet"}}},
+		{BatchOptions{Path: "/pets", Operations: []string{"deletePet"}}},
+		{BatchOptions{Path: "/pets/{petId}", Operations: []string{"createPet"}}},
+	}
+
+	for i, testCase := range testCases {
+		failMsg := "test case %d failed"
+		err := petstore(r.T(), batchPatch).DeclareBatch(testCase.opts)
+		assert.NotNil(r.T(), err, failMsg, i)
+	}
+}
+
+func TestBatchSuite(t *testing.T) {
+	suite.Run(t, new(BatchSuite))
+}
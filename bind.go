@@ -0,0 +1,300 @@
+package oas
+
+import (
+	"bytes"
+	"encoding/json"
+	"io/ioutil"
+	"mime"
+	"net/http"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// BindTag describes the struct tag Bind reads. Parameters are bound with
+// "<in>:<name>" (e.g. `oas:"path:petId"`, `oas:"query:limit"`) and the
+// request body with "body".
+const BindTag = "oas"
+
+// Bind decodes the parameters and the body of the request into the struct
+// pointed to by out, removing per-handler parsing boilerplate. It is meant
+// for handlers behind Router.Middleware: the operation and the path
+// parameters are those of the route the middleware stored in the request
+// context, and an error is returned for requests carrying no route.
+//
+// Values are coerced into the Go type of the tagged field. Fields of type
+// interface{} receive a value matching the schema type (int64, float64,
+// bool, time.Time for the date and date-time formats, string otherwise) and
+// time.Time fields are parsed according to the schema format. Array
+// parameters are bound to slices from repeated query values or comma
+// separated values. Pointer fields are left nil when the parameter is
+// absent, and missing required parameters are reported as errors.
+//
+// JSON request bodies are decoded with encoding/json, other media types can
+// be bound to string or []byte fields. The request body is restored after
+// reading so handlers can still consume it.
+func (r OpenAPI) Bind(req *http.Request, out interface{}) error {
+	route, ok := RouteFromContext(req.Context())
+	if !ok {
+		return errors.New("request carries no route")
+	}
+	return r.bind(req, route.PathItem, route.Operation, route.PathParams, out)
+}
+
+// BindPath binds the request like Bind without a router, looking the
+// operation up by the path template (e.g. "/pets/{petId}") and the request
+// method. PathParams holds the values extracted for the path template.
+func (r OpenAPI) BindPath(req *http.Request, path string, pathParams map[string]string, out interface{}) error {
+	item := r.Paths.PathItems.Get(path)
+	if item == nil {
+		return errors.Errorf("unknown path %q", path)
+	}
+	var operation *Operation
+	for _, op := range item.operations() {
+		if strings.EqualFold(op.method, req.Method) {
+			operation = op.operation
+		}
+	}
+	if operation == nil {
+		return errors.Errorf("no %s operation declared for path %q", req.Method, path)
+	}
+	return r.bind(req, item, operation, pathParams, out)
+}
+
+// bind binds the request to out according to the operation declared on the
+// path item.
+func (r OpenAPI) bind(req *http.Request, item *PathItem, operation *Operation, pathParams map[string]string, out interface{}) error {
+	params := map[string]*Parameter{}
+	for _, values := range [][]*Parameter{item.Parameters, operation.Parameters} {
+		for _, value := range values {
+			if _, param := NewResolver(&r).parameter("", value); param != nil {
+				params[param.In+":"+param.Name] = param
+			}
+		}
+	}
+
+	value := reflect.ValueOf(out)
+	if value.Kind() != reflect.Ptr || value.Elem().Kind() != reflect.Struct {
+		return errors.Errorf("bind target must be a pointer to a struct, got %T", out)
+	}
+	value = value.Elem()
+	for i := 0; i < value.NumField(); i++ {
+		field := value.Type().Field(i)
+		tag, ok := field.Tag.Lookup(BindTag)
+		if !ok || field.PkgPath != "" {
+			continue
+		}
+
+		if tag == "body" {
+			if err := r.bindBody(req, operation.RequestBody, value.Field(i)); err != nil {
+				return errors.Wrap(err, "request body")
+			}
+			continue
+		}
+
+		param := params[tag]
+		if param == nil {
+			return errors.Errorf("field %s binds undeclared parameter %q", field.Name, tag)
+		}
+		raw, ok := parameterValues(req, param, pathParams)
+		if !ok {
+			if param.Required {
+				return errors.Errorf("missing required %s parameter %q", param.In, param.Name)
+			}
+			continue
+		}
+		if err := r.coerce(value.Field(i), raw, NewResolver(&r).schema(param.Schema)); err != nil {
+			return errors.Wrapf(err, "%s parameter %q", param.In, param.Name)
+		}
+	}
+	return nil
+}
+
+// bindBody decodes the request body into the field.
+func (r OpenAPI) bindBody(req *http.Request, body *RequestBody, field reflect.Value) error {
+	if _, body = NewResolver(&r).requestBody("", body); body == nil {
+		return errors.New("operation declares no request body")
+	}
+
+	rbytes := []byte{}
+	if req.Body != nil {
+		var err error
+		if rbytes, err = ioutil.ReadAll(req.Body); err != nil {
+			return errors.WithStack(err)
+		}
+		req.Body = ioutil.NopCloser(bytes.NewReader(rbytes))
+	}
+	if len(rbytes) == 0 {
+		if body.Required {
+			return errors.New("missing required request body")
+		}
+		return nil
+	}
+
+	mediaType, _, err := mime.ParseMediaType(req.Header.Get("Content-Type"))
+	if err != nil {
+		mediaType = ""
+	}
+	switch {
+	case isJSONMediaType(mediaType):
+		if err := json.Unmarshal(rbytes, field.Addr().Interface()); err != nil {
+			return errors.WithStack(err)
+		}
+	case field.Kind() == reflect.Slice && field.Type().Elem().Kind() == reflect.Uint8:
+		field.SetBytes(rbytes)
+	case field.Kind() == reflect.String:
+		field.SetString(string(rbytes))
+	default:
+		return errors.Errorf("cannot bind %q content into %s", mediaType, field.Type())
+	}
+	return nil
+}
+
+// parameterValues returns the raw values of the parameter and whether the
+// parameter is present in the request.
+func parameterValues(req *http.Request, param *Parameter, pathParams map[string]string) ([]string, bool) {
+	switch param.In {
+	case "path":
+		value, ok := pathParams[param.Name]
+		return []string{value}, ok
+	case "query":
+		values := req.URL.Query()[param.Name]
+		return values, len(values) > 0
+	case "header":
+		values := req.Header[http.CanonicalHeaderKey(param.Name)]
+		return values, len(values) > 0
+	case "cookie":
+		cookie, err := req.Cookie(param.Name)
+		if err != nil {
+			return nil, false
+		}
+		return []string{cookie.Value}, true
+	}
+	return nil, false
+}
+
+// coerce converts the raw values into the type of the field.
+func (r OpenAPI) coerce(field reflect.Value, raw []string, schema *Schema) error {
+	switch {
+	case field.Kind() == reflect.Ptr:
+		value := reflect.New(field.Type().Elem())
+		if err := r.coerce(value.Elem(), raw, schema); err != nil {
+			return err
+		}
+		field.Set(value)
+		return nil
+	case field.Kind() == reflect.Slice && field.Type().Elem().Kind() != reflect.Uint8:
+		values := raw
+		if len(raw) == 1 {
+			values = strings.Split(raw[0], ",")
+		}
+		var items *Schema
+		if schema != nil {
+			items = NewResolver(&r).schema(schema.Items)
+		}
+		slice := reflect.MakeSlice(field.Type(), len(values), len(values))
+		for i, value := range values {
+			if err := r.coerce(slice.Index(i), []string{value}, items); err != nil {
+				return err
+			}
+		}
+		field.Set(slice)
+		return nil
+	}
+	if len(raw) == 0 {
+		return nil
+	}
+	return coerceScalar(field, raw[0], schema)
+}
+
+// coerceScalar converts a single raw value into the type of the field.
+func coerceScalar(field reflect.Value, raw string, schema *Schema) error {
+	if schema == nil {
+		schema = &Schema{}
+	}
+
+	if field.Type() == reflect.TypeOf(time.Time{}) {
+		value, err := parseTime(raw, schema.Format)
+		if err != nil {
+			return err
+		}
+		field.Set(reflect.ValueOf(value))
+		return nil
+	}
+
+	switch field.Kind() {
+	case reflect.String:
+		field.SetString(raw)
+	case reflect.Bool:
+		value, err := strconv.ParseBool(raw)
+		if err != nil {
+			return errors.WithStack(err)
+		}
+		field.SetBool(value)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		value, err := strconv.ParseInt(raw, 10, field.Type().Bits())
+		if err != nil {
+			return errors.WithStack(err)
+		}
+		field.SetInt(value)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		value, err := strconv.ParseUint(raw, 10, field.Type().Bits())
+		if err != nil {
+			return errors.WithStack(err)
+		}
+		field.SetUint(value)
+	case reflect.Float32, reflect.Float64:
+		value, err := strconv.ParseFloat(raw, field.Type().Bits())
+		if err != nil {
+			return errors.WithStack(err)
+		}
+		field.SetFloat(value)
+	case reflect.Slice:
+		field.SetBytes([]byte(raw))
+	case reflect.Interface:
+		value, err := schemaValue(raw, schema)
+		if err != nil {
+			return err
+		}
+		field.Set(reflect.ValueOf(value))
+	default:
+		return errors.Errorf("unsupported field type %s", field.Type())
+	}
+	return nil
+}
+
+// schemaValue converts a raw value into the Go type matching the schema type
+// and format.
+func schemaValue(raw string, schema *Schema) (interface{}, error) {
+	switch {
+	case schema.Type == "integer":
+		value, err := strconv.ParseInt(raw, 10, 64)
+		return value, errors.WithStack(err)
+	case schema.Type == "number":
+		value, err := strconv.ParseFloat(raw, 64)
+		return value, errors.WithStack(err)
+	case schema.Type == "boolean":
+		value, err := strconv.ParseBool(raw)
+		return value, errors.WithStack(err)
+	case schema.Format == "date" || schema.Format == "date-time":
+		return parseTime(raw, schema.Format)
+	}
+	return raw, nil
+}
+
+// parseTime parses a full-date for the date format and a date-time as
+// defined by RFC 3339 otherwise.
+func parseTime(raw string, format string) (time.Time, error) {
+	layout := time.RFC3339
+	if format == "date" {
+		layout = "2006-01-02"
+	}
+	value, err := time.Parse(layout, raw)
+	if err != nil {
+		return time.Time{}, errors.WithStack(err)
+	}
+	return value, nil
+}
@@ -0,0 +1,152 @@
+package oas
+
+import (
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+)
+
+type BindSuite struct {
+	suite.Suite
+}
+
+func (r *BindSuite) bindDocument() *OpenAPI {
+	return &OpenAPI{
+		OpenAPI: "3.0.0",
+		Info:    Info{Title: "Petstore", Version: "1.0.0"},
+		Paths: Paths{
+			PathItems: NewPathItems(map[string]*PathItem{
+				"/pets/{petId}": {
+					Parameters: []*Parameter{
+						{Name: "petId", In: "path", Header: Header{Required: true, Schema: &Schema{Type: "integer", Format: "int64"}}},
+					},
+					Put: &Operation{
+						Parameters: []*Parameter{
+							{Header: Header{Ref: "#/components/parameters/Since"}},
+							{Name: "tags", In: "query", Header: Header{Schema: &Schema{Type: "array", Items: &Schema{Type: "integer"}}}},
+							{Name: "dryRun", In: "query", Header: Header{Schema: &Schema{Type: "boolean"}}},
+							{Name: "X-Request-ID", In: "header", Header: Header{Required: true, Schema: &Schema{Type: "string"}}},
+							{Name: "session", In: "cookie", Header: Header{Schema: &Schema{Type: "string"}}},
+							{Name: "limit", In: "query", Header: Header{Schema: &Schema{Type: "integer"}}},
+						},
+						RequestBody: &RequestBody{
+							Required: true,
+							Content: map[string]*MediaType{
+								"application/json": {Schema: &Schema{Type: "object"}},
+							},
+						},
+					},
+				},
+			}),
+		},
+		Components: &Components{
+			Parameters: map[string]*Parameter{
+				"Since": {Name: "since", In: "query", Header: Header{Schema: &Schema{Ref: "#/components/schemas/Date"}}},
+			},
+			Schemas: map[string]*Schema{
+				"Date": {Type: "string", Format: "date"},
+			},
+		},
+	}
+}
+
+type bindPet struct {
+	Name string `json:"name"`
+}
+
+type bindRequest struct {
+	PetID     int64       `oas:"path:petId"`
+	Since     time.Time   `oas:"query:since"`
+	Tags      []int       `oas:"query:tags"`
+	DryRun    *bool       `oas:"query:dryRun"`
+	Limit     *int        `oas:"query:limit"`
+	RequestID string      `oas:"header:X-Request-ID"`
+	Session   interface{} `oas:"cookie:session"`
+	Body      bindPet     `oas:"body"`
+	ignored   string
+}
+
+func (r *BindSuite) TestBind() {
+	req := httptest.NewRequest(http.MethodPut, "/pets/42?since=2019-06-01&tags=1,2,3&dryRun=true", strings.NewReader(`{"name":"Rex"}`))
+	req.Header.Set("Content-Type", "application/json; charset=utf-8")
+	req.Header.Set("X-Request-ID", "abc")
+	req.AddCookie(&http.Cookie{Name: "session", Value: "s1"})
+
+	dryRun := true
+	out := bindRequest{}
+	err := r.bindDocument().BindPath(req, "/pets/{petId}", map[string]string{"petId": "42"}, &out)
+	assert.NoError(r.T(), err)
+	assert.Equal(r.T(), bindRequest{
+		PetID:     42,
+		Since:     time.Date(2019, 6, 1, 0, 0, 0, 0, time.UTC),
+		Tags:      []int{1, 2, 3},
+		DryRun:    &dryRun,
+		RequestID: "abc",
+		Session:   "s1",
+		Body:      bindPet{Name: "Rex"},
+	}, out)
+
+	rbytes, _ := ioutil.ReadAll(req.Body)
+	assert.Equal(r.T(), `{"name":"Rex"}`, string(rbytes))
+}
+
+func (r *BindSuite) TestBindRoute() {
+	doc := r.bindDocument()
+	var out bindRequest
+	var err error
+	router := &Router{Doc: doc}
+	handler := router.Middleware(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		err = doc.Bind(req, &out)
+	}))
+
+	req := httptest.NewRequest(http.MethodPut, "/pets/42?tags=7", strings.NewReader(`{"name":"Rex"}`))
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("X-Request-ID", "abc")
+	handler.ServeHTTP(httptest.NewRecorder(), req)
+	assert.NoError(r.T(), err)
+	assert.Equal(r.T(), bindRequest{PetID: 42, Tags: []int{7}, RequestID: "abc", Body: bindPet{Name: "Rex"}}, out)
+
+	req = httptest.NewRequest(http.MethodPut, "/pets/42", strings.NewReader(`{}`))
+	err = doc.Bind(req, &bindRequest{})
+	assert.EqualError(r.T(), err, "request carries no route")
+}
+
+func (r *BindSuite) TestErrors() {
+	doc := r.bindDocument()
+	pathParams := map[string]string{"petId": "42"}
+
+	req := httptest.NewRequest(http.MethodPut, "/pets/42", strings.NewReader(`{}`))
+	req.Header.Set("Content-Type", "application/json")
+	err := doc.BindPath(req, "/pets/{petId}", pathParams, &bindRequest{})
+	assert.EqualError(r.T(), err, `missing required header parameter "X-Request-ID"`)
+
+	req = httptest.NewRequest(http.MethodPut, "/pets/42?limit=ten", strings.NewReader(`{}`))
+	req.Header.Set("X-Request-ID", "abc")
+	err = doc.BindPath(req, "/pets/{petId}", pathParams, &bindRequest{})
+	assert.Contains(r.T(), err.Error(), `query parameter "limit"`)
+
+	req = httptest.NewRequest(http.MethodPut, "/pets/42", nil)
+	req.Header.Set("X-Request-ID", "abc")
+	err = doc.BindPath(req, "/pets/{petId}", pathParams, &bindRequest{})
+	assert.EqualError(r.T(), err, "request body: missing required request body")
+
+	req = httptest.NewRequest(http.MethodGet, "/pets/42", nil)
+	err = doc.BindPath(req, "/pets/{petId}", pathParams, &bindRequest{})
+	assert.EqualError(r.T(), err, `no GET operation declared for path "/pets/{petId}"`)
+
+	req = httptest.NewRequest(http.MethodPut, "/pets/42", nil)
+	err = doc.BindPath(req, "/pets/{petId}", pathParams, &struct {
+		Owner string `oas:"query:owner"`
+	}{})
+	assert.EqualError(r.T(), err, `field Owner binds undeclared parameter "query:owner"`)
+}
+
+func TestBindSuite(t *testing.T) {
+	suite.Run(t, new(BindSuite))
+}
@@ -0,0 +1,163 @@
+package oas
+
+import (
+	"sort"
+)
+
+// Rules reported by AuditBounds.
+const (
+	RuleUnboundedString = "unbounded-string"
+	RuleUnboundedArray  = "unbounded-array"
+	RuleUnboundedObject = "unbounded-object"
+)
+
+// boundedFormats holds the string formats whose values have an inherent
+// maximum length.
+var boundedFormats = map[string]bool{
+	"date":      true,
+	"date-time": true,
+	"time":      true,
+	"uuid":      true,
+	"ipv4":      true,
+	"ipv6":      true,
+}
+
+// BoundsOptions describes the organization default bounds applied by
+// ApplyBounds. A zero value leaves the respective keyword unset.
+type BoundsOptions struct {
+	// MaxLength describes the maxLength applied to unbounded strings.
+	MaxLength int
+
+	// MaxItems describes the maxItems applied to unbounded arrays.
+	MaxItems int
+
+	// MaxProperties describes the maxProperties applied to unbounded maps.
+	MaxProperties int
+}
+
+// AuditBounds reports the schemas of user-supplied inputs (parameters and
+// request bodies) which do not bound the size of their values: strings
+// without maxLength, arrays without maxItems and objects accepting
+// additional properties without maxProperties. Strings restricted by an
+// enum or a fixed size format (e.g. uuid, date-time) are considered bounded.
+func (r OpenAPI) AuditBounds() []*Finding {
+	findings := make([]*Finding, 0)
+	r.inputSchemas(func(ptr string, schema *Schema) {
+		switch {
+		case unboundedString(schema):
+			findings = append(findings, &Finding{
+				Pointer:  ptr,
+				Rule:     RuleUnboundedString,
+				Severity: SeverityWarning,
+				Message:  "input string has no maxLength",
+			})
+		case unboundedArray(schema):
+			findings = append(findings, &Finding{
+				Pointer:  ptr,
+				Rule:     RuleUnboundedArray,
+				Severity: SeverityWarning,
+				Message:  "input array has no maxItems",
+			})
+		case unboundedObject(schema):
+			findings = append(findings, &Finding{
+				Pointer:  ptr,
+				Rule:     RuleUnboundedObject,
+				Severity: SeverityWarning,
+				Message:  "input object accepts additional properties but has no maxProperties",
+			})
+		}
+	})
+	sortFindings(findings)
+	return findings
+}
+
+// ApplyBounds sets the default bounds on the input schemas AuditBounds
+// reports, and returns the pointers of the modified schemas in sorted
+// order. Existing bounds are never changed.
+func (r *OpenAPI) ApplyBounds(opts BoundsOptions) []string {
+	modified := make([]string, 0)
+	r.inputSchemas(func(ptr string, schema *Schema) {
+		switch {
+		case opts.MaxLength > 0 && unboundedString(schema):
+			schema.MaxLength = opts.MaxLength
+		case opts.MaxItems > 0 && unboundedArray(schema):
+			schema.MaxItems = opts.MaxItems
+		case opts.MaxProperties > 0 && unboundedObject(schema):
+			schema.MaxProperties = opts.MaxProperties
+		default:
+			return
+		}
+		modified = append(modified, ptr)
+	})
+	sort.Strings(modified)
+	return modified
+}
+
+// inputSchemas calls fn for every schema reachable from the parameters and
+// request bodies of the operations. Every schema is visited once, even when
+// reachable through multiple references.
+func (r OpenAPI) inputSchemas(fn func(ptr string, schema *Schema)) {
+	visited := map[string]bool{}
+	seen := map[string]bool{}
+	once := func(ptr string, schema *Schema) {
+		if !seen[ptr] {
+			seen[ptr] = true
+			fn(ptr, schema)
+		}
+	}
+	parameters := func(ptr string, values []*Parameter) {
+		for i, value := range values {
+			paramPtr, param := NewResolver(&r).parameter(index(ptr, "parameters", i), value)
+			if param == nil {
+				continue
+			}
+			walkSchemas(r.Components, join(paramPtr, "schema"), param.Schema, visited, once)
+			for _, mediaType := range sortedKeys(param.Content) {
+				if param.Content[mediaType] != nil {
+					walkSchemas(r.Components, join(paramPtr, "content", mediaType, "schema"), param.Content[mediaType].Schema, visited, once)
+				}
+			}
+		}
+	}
+
+	for _, path := range sortedKeys(r.Paths.PathItems) {
+		if item := r.Paths.PathItems.Get(path); item != nil {
+			parameters(join("/paths", path), item.Parameters)
+		}
+	}
+	for _, op := range r.Paths.operations() {
+		ptr := join("/paths", op.path, op.method)
+		parameters(ptr, op.operation.Parameters)
+
+		bodyPtr, body := NewResolver(&r).requestBody(join(ptr, "requestBody"), op.operation.RequestBody)
+		if body == nil {
+			continue
+		}
+		for _, mediaType := range sortedKeys(body.Content) {
+			if body.Content[mediaType] != nil {
+				walkSchemas(r.Components, join(bodyPtr, "content", mediaType, "schema"), body.Content[mediaType].Schema, visited, once)
+			}
+		}
+	}
+}
+
+// unboundedString reports whether the schema describes a string of
+// unlimited length.
+func unboundedString(schema *Schema) bool {
+	return schema.Type == "string" && schema.MaxLength == nil &&
+		len(schema.Enum) == 0 && !boundedFormats[schema.Format]
+}
+
+// unboundedArray reports whether the schema describes an array with an
+// unlimited number of items.
+func unboundedArray(schema *Schema) bool {
+	return schema.Type == "array" && schema.MaxItems == nil
+}
+
+// unboundedObject reports whether the schema describes an object accepting
+// an unlimited number of additional properties.
+func unboundedObject(schema *Schema) bool {
+	accepts := schema.AdditionalProperties != nil ||
+		(schema.AdditionalPropertiesAllowed != nil && *schema.AdditionalPropertiesAllowed)
+	return accepts && schema.MaxProperties == nil
+}
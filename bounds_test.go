@@ -0,0 +1,106 @@
+package oas
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+)
+
+type BoundsSuite struct {
+	suite.Suite
+}
+
+func (r *BoundsSuite) boundsDocument() *OpenAPI {
+	return &OpenAPI{
+		OpenAPI: "3.0.0",
+		Info:    Info{Title: "Petstore", Version: "1.0.0"},
+		Paths: Paths{
+			PathItems: NewPathItems(map[string]*PathItem{
+				"/pets": {
+					Parameters: []*Parameter{{Header: Header{Ref: "#/components/parameters/Filter"}}},
+					Get: &Operation{
+						Parameters: []*Parameter{
+							{Name: "id", In: "query", Header: Header{Schema: &Schema{Type: "string", Format: "uuid"}}},
+							{Name: "sort", In: "query", Header: Header{Schema: &Schema{Type: "string", Enum: []interface{}{"asc", "desc"}}}},
+						},
+						Responses: NewResponses(map[string]*Response{
+							"200": {
+								Description: "Pets.",
+								Content: map[string]*MediaType{
+									"application/json": {Schema: &Schema{Type: "array", Items: &Schema{Type: "string"}}},
+								},
+							},
+						}),
+					},
+					Post: &Operation{
+						RequestBody: &RequestBody{
+							Content: map[string]*MediaType{
+								"application/json": {Schema: &Schema{Ref: "#/components/schemas/Pet"}},
+							},
+						},
+					},
+					Put: &Operation{
+						RequestBody: &RequestBody{
+							Content: map[string]*MediaType{
+								"application/json": {Schema: &Schema{Ref: "#/components/schemas/Pet"}},
+							},
+						},
+					},
+				},
+			}),
+		},
+		Components: &Components{
+			Parameters: map[string]*Parameter{
+				"Filter": {Name: "filter", In: "query", Header: Header{Schema: &Schema{Type: "string"}}},
+			},
+			Schemas: map[string]*Schema{
+				"Pet": {
+					Type: "object",
+					Properties: NewProperties(map[string]*Schema{
+						"name":   {Type: "string", MaxLength: 64},
+						"tags":   {Type: "array", Items: &Schema{Type: "string"}},
+						"labels": {Type: "object", AdditionalProperties: &Schema{Type: "string", MaxLength: 16}},
+					}),
+				},
+			},
+		},
+	}
+}
+
+func (r *BoundsSuite) TestAuditBounds() {
+	doc := r.boundsDocument()
+	findings := doc.AuditBounds()
+	pointers := make([]string, 0, len(findings))
+	for _, finding := range findings {
+		pointers = append(pointers, finding.Pointer+" "+finding.Rule)
+	}
+	assert.Equal(r.T(), []string{
+		"/components/parameters/Filter/schema unbounded-string",
+		"/components/schemas/Pet/properties/labels unbounded-object",
+		"/components/schemas/Pet/properties/tags unbounded-array",
+		"/components/schemas/Pet/properties/tags/items unbounded-string",
+	}, pointers)
+}
+
+func (r *BoundsSuite) TestApplyBounds() {
+	doc := r.boundsDocument()
+	modified := doc.ApplyBounds(BoundsOptions{MaxLength: 256, MaxItems: 100})
+	assert.Equal(r.T(), []string{
+		"/components/parameters/Filter/schema",
+		"/components/schemas/Pet/properties/tags",
+		"/components/schemas/Pet/properties/tags/items",
+	}, modified)
+	assert.Equal(r.T(), 256, doc.Components.Parameters["Filter"].Schema.MaxLength)
+	assert.Equal(r.T(), 100, doc.Components.Schemas["Pet"].Properties.Get("tags").MaxItems)
+	assert.Equal(r.T(), 64, doc.Components.Schemas["Pet"].Properties.Get("name").MaxLength)
+	assert.Nil(r.T(), doc.Paths.PathItems.Get("/pets").Get.Responses.Get("200").Content["application/json"].Schema.MaxItems)
+
+	findings := doc.AuditBounds()
+	assert.Len(r.T(), findings, 1)
+	assert.Equal(r.T(), RuleUnboundedObject, findings[0].Rule)
+}
+
+func TestBoundsSuite(t *testing.T) {
+	suite.Run(t, new(BoundsSuite))
+}
@@ -0,0 +1,260 @@
+package oas
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// Rules of the findings reported by AuditCaching.
+const (
+	RuleCacheUndeclared     = "cache-undeclared"
+	RuleCacheControlInvalid = "cache-control-invalid"
+	RuleCacheOnUnsafeMethod = "cache-on-unsafe-method"
+)
+
+// CachePolicy describes how clients may cache the successful responses of
+// an operation, as declared by their Cache-Control, ETag and Last-Modified
+// headers. The value of Cache-Control is read from the default, the single
+// enum value or the example of the header.
+type CachePolicy struct {
+	// Path describes the path template of the operation.
+	Path string `json:"path" yaml:"path"`
+
+	// Method describes the uppercase HTTP method of the operation.
+	Method string `json:"method" yaml:"method"`
+
+	// OperationID describes the operationId of the operation, if any.
+	OperationID string `json:"operationId,omitempty" yaml:"operationId,omitempty"`
+
+	// Status describes the successful response the policy is read from.
+	Status string `json:"status" yaml:"status"`
+
+	// Cacheable describes whether clients may store the responses. Only
+	// responses to GET and HEAD requests which are fresh for some time or
+	// carry a validator are cacheable, unless no-store is declared.
+	Cacheable bool `json:"cacheable" yaml:"cacheable"`
+
+	// MaxAge describes how many seconds a stored response is fresh.
+	MaxAge int `json:"maxAge,omitempty" yaml:"maxAge,omitempty"`
+
+	// Revalidate describes whether stored responses must be revalidated
+	// with a conditional request before being reused, once no longer fresh
+	// or always when MaxAge is zero.
+	Revalidate bool `json:"revalidate,omitempty" yaml:"revalidate,omitempty"`
+
+	// Private describes whether the responses are specific to the user and
+	// must not be stored by shared caches.
+	Private bool `json:"private,omitempty" yaml:"private,omitempty"`
+
+	// ETag describes whether the responses carry an entity tag to
+	// revalidate with If-None-Match.
+	ETag bool `json:"etag,omitempty" yaml:"etag,omitempty"`
+
+	// LastModified describes whether the responses carry a modification
+	// date to revalidate with If-Modified-Since.
+	LastModified bool `json:"lastModified,omitempty" yaml:"lastModified,omitempty"`
+
+	// CacheControl describes the declared value of the Cache-Control header,
+	// empty when unknown.
+	CacheControl string `json:"cacheControl,omitempty" yaml:"cacheControl,omitempty"`
+}
+
+// cacheDirectives describes the Cache-Control directives a client acts on.
+type cacheDirectives struct {
+	maxAge         int
+	noStore        bool
+	noCache        bool
+	mustRevalidate bool
+	private        bool
+}
+
+// parseCacheControl returns the directives of the Cache-Control value.
+// Unknown directives are ignored.
+func parseCacheControl(value string) (cacheDirectives, error) {
+	directives := cacheDirectives{}
+	for _, directive := range strings.Split(value, ",") {
+		directive = strings.TrimSpace(directive)
+		if directive == "" {
+			continue
+		}
+		name, arg := directive, ""
+		if i := strings.Index(directive, "="); i >= 0 {
+			name, arg = directive[:i], strings.Trim(directive[i+1:], `"`)
+		}
+		switch strings.ToLower(name) {
+		case "max-age":
+			maxAge, err := strconv.Atoi(arg)
+			if err != nil || maxAge < 0 {
+				return directives, errors.Errorf("invalid max-age %q", arg)
+			}
+			directives.maxAge = maxAge
+		case "no-store":
+			directives.noStore = true
+		case "no-cache":
+			directives.noCache = true
+		case "must-revalidate":
+			directives.mustRevalidate = true
+		case "private":
+			directives.private = true
+		}
+	}
+	return directives, nil
+}
+
+// CachePolicy returns the cache policy of the operation under the path and
+// lowercase method, or nil if it declares no successful response.
+func (r OpenAPI) CachePolicy(path string, method string) (*CachePolicy, error) {
+	op, err := r.Paths.operation(path, method)
+	if err != nil {
+		return nil, err
+	}
+	return r.cachePolicy(op)
+}
+
+// CachePolicies returns the cache policies of the operations declaring a
+// successful response, ordered by path and method, for clients to configure
+// their caches from the document.
+func (r OpenAPI) CachePolicies() ([]*CachePolicy, error) {
+	policies := make([]*CachePolicy, 0)
+	for _, op := range r.Paths.operations() {
+		policy, err := r.cachePolicy(op)
+		if err != nil {
+			return nil, err
+		}
+		if policy != nil {
+			policies = append(policies, policy)
+		}
+	}
+	return policies, nil
+}
+
+// cachePolicy returns the cache policy of the operation, read from its
+// first successful response.
+func (r OpenAPI) cachePolicy(op pathOperation) (*CachePolicy, error) {
+	status, response := r.successResponseOf(op.operation)
+	if response == nil {
+		return nil, nil
+	}
+	policy := &CachePolicy{
+		Path:         op.path,
+		Method:       strings.ToUpper(op.method),
+		OperationID:  op.operation.OperationID,
+		Status:       status,
+		ETag:         hasResponseHeader(response, "ETag"),
+		LastModified: hasResponseHeader(response, "Last-Modified"),
+		CacheControl: r.responseHeaderValue(response, "Cache-Control"),
+	}
+	directives, err := parseCacheControl(policy.CacheControl)
+	if err != nil {
+		return nil, errors.Wrapf(err, "%s %s: Cache-Control", policy.Method, op.path)
+	}
+
+	safe := op.method == "get" || op.method == "head"
+	validators := policy.ETag || policy.LastModified
+	if !safe || directives.noStore {
+		return policy, nil
+	}
+	if !directives.noCache {
+		policy.MaxAge = directives.maxAge
+	}
+	policy.Cacheable = policy.MaxAge > 0 || validators
+	policy.Revalidate = policy.Cacheable && validators &&
+		(directives.noCache || directives.mustRevalidate || policy.MaxAge == 0)
+	policy.Private = directives.private
+	return policy, nil
+}
+
+// successResponseOf returns the first successful response of the
+// operation and its status code, resolving references.
+func (r OpenAPI) successResponseOf(operation *Operation) (string, *Response) {
+	for _, status := range sortedKeys(operation.Responses) {
+		if !strings.HasPrefix(status, "2") {
+			continue
+		}
+		if _, response := NewResolver(&r).response("", operation.Responses.Get(status)); response != nil {
+			return status, response
+		}
+	}
+	return "", nil
+}
+
+// responseHeaderValue returns the value the response declares for the
+// header, read from the default, the single enum value or the example of
+// the header, or an empty string when unknown. Header names are compared
+// case-insensitively.
+func (r OpenAPI) responseHeaderValue(response *Response, name string) string {
+	for _, key := range sortedKeys(response.Headers) {
+		if !strings.EqualFold(key, name) {
+			continue
+		}
+		_, header := NewResolver(&r).header("", response.Headers[key])
+		if header == nil {
+			return ""
+		}
+		candidates := make([]interface{}, 0, 4)
+		schema := NewResolver(&r).schema(header.Schema)
+		if schema != nil {
+			candidates = append(candidates, schema.Default)
+			if len(schema.Enum) == 1 {
+				candidates = append(candidates, schema.Enum[0])
+			}
+		}
+		candidates = append(candidates, header.Example)
+		if schema != nil {
+			candidates = append(candidates, schema.Example)
+		}
+		for _, candidate := range candidates {
+			if value, ok := candidate.(string); ok && value != "" {
+				return value
+			}
+		}
+		return ""
+	}
+	return ""
+}
+
+// AuditCaching reports GET operations returning a body which declare
+// neither a Cache-Control header nor a validator, leaving clients without
+// a caching policy, Cache-Control values which cannot be parsed, and
+// operations of unsafe methods declaring their responses fresh for some
+// time, which clients must not cache.
+func (r OpenAPI) AuditCaching() []*Finding {
+	findings := make([]*Finding, 0)
+	for _, op := range r.Paths.operations() {
+		status, response := r.successResponseOf(op.operation)
+		if response == nil {
+			continue
+		}
+		ptr := join("/paths", op.path, op.method, "responses", status)
+		value := r.responseHeaderValue(response, "Cache-Control")
+		directives, err := parseCacheControl(value)
+		switch {
+		case err != nil:
+			findings = append(findings, &Finding{
+				Pointer:  join(ptr, "headers", "Cache-Control"),
+				Rule:     RuleCacheControlInvalid,
+				Severity: SeverityError,
+				Message:  err.Error(),
+			})
+		case op.method == "get" && len(response.Content) > 0 && !hasResponseHeader(response, "Cache-Control") &&
+			!hasResponseHeader(response, "ETag") && !hasResponseHeader(response, "Last-Modified"):
+			findings = append(findings, &Finding{
+				Pointer:  ptr,
+				Rule:     RuleCacheUndeclared,
+				Severity: SeverityWarning,
+				Message:  "GET response declares neither Cache-Control nor a validator, clients cannot tell whether to cache it",
+			})
+		case op.method != "get" && op.method != "head" && directives.maxAge > 0 && !directives.noStore:
+			findings = append(findings, &Finding{
+				Pointer:  join(ptr, "headers", "Cache-Control"),
+				Rule:     RuleCacheOnUnsafeMethod,
+				Severity: SeverityWarning,
+				Message:  "responses to " + strings.ToUpper(op.method) + " requests are not cached by clients, max-age has no effect",
+			})
+		}
+	}
+	sortFindings(findings)
+	return findings
+}
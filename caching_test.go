@@ -0,0 +1,161 @@
+package oas
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+)
+
+type CachingSuite struct {
+	suite.Suite
+}
+
+// cachingPatch is the patch of the petstore fixture the suite audits.
+const cachingPatch = `
+paths:
+  /pets:
+    get:
+      responses:
+        "200":
+          headers:
+            cache-control:
+              schema:
+                type: string
+                default: public, max-age=60
+    post:
+      responses:
+        "201":
+          headers:
+            Cache-Control:
+              example: max-age=60
+  /pets/{petId}:
+    get:
+      responses:
+        "200":
+          headers:
+            ETag:
+              schema:
+                type: string
+            Cache-Control:
+              $ref: "#/components/headers/NoCache"
+        "304":
+          description: Not modified.
+  /owners:
+    get:
+      responses:
+        "200":
+          description: The owners.
+          content:
+            application/json:
+              schema:
+                type: object
+  /session:
+    get:
+      responses:
+        "200":
+          description: The session.
+          headers:
+            Cache-Control:
+              schema:
+                type: string
+                enum: ["private, no-store"]
+          content:
+            application/json:
+              schema:
+                type: object
+components:
+  headers:
+    NoCache:
+      schema:
+        type: string
+        example: private, no-cache
+`
+
+func (r *CachingSuite) TestCachePolicies() {
+	policies, err := petstore(r.T(), cachingPatch).CachePolicies()
+	assert.Nil(r.T(), err)
+	assert.Equal(r.T(), []*CachePolicy{
+		{Path: "/owners", Method: "GET", Status: "200"},
+		{
+			Path:         "/pets",
+			Method:       "GET",
+			OperationID:  "listPets",
+			Status:       "200",
+			Cacheable:    true,
+			MaxAge:       60,
+			CacheControl: "public, max-age=60",
+		},
+		{Path: "/pets", Method: "POST", OperationID: "createPet", Status: "201", CacheControl: "max-age=60"},
+		{
+			Path:         "/pets/{petId}",
+			Method:       "GET",
+			OperationID:  "getPet",
+			Status:       "200",
+			Cacheable:    true,
+			Revalidate:   true,
+			Private:      true,
+			ETag:         true,
+			CacheControl: "private, no-cache",
+		},
+		{Path: "/pets/{petId}", Method: "DELETE", OperationID: "deletePet", Status: "204"},
+		{Path: "/session", Method: "GET", Status: "200", CacheControl: "private, no-store"},
+	}, policies)
+
+	policy, err := petstore(r.T(), cachingPatch).CachePolicy("/pets", "get")
+	assert.Nil(r.T(), err)
+	assert.Equal(r.T(), 60, policy.MaxAge)
+	_, err = petstore(r.T(), cachingPatch).CachePolicy("/pets", "put")
+	assert.NotNil(r.T(), err)
+
+	_, err = petstore(r.T(), cachingPatch, `
+paths:
+  /pets:
+    get:
+      responses:
+        "200":
+          headers:
+            cache-control:
+              schema:
+                default: max-age=soon
+`).CachePolicies()
+	assert.EqualError(r.T(), err, `GET /pets: Cache-Control: invalid max-age "soon"`)
+}
+
+func (r *CachingSuite) TestAuditCaching() {
+	doc := petstore(r.T(), cachingPatch, `
+paths:
+  /session:
+    get:
+      responses:
+        "200":
+          headers:
+            Cache-Control:
+              schema:
+                enum: [max-age=-1]
+`)
+	assert.Equal(r.T(), []*Finding{
+		{
+			Pointer:  "/paths/~1owners/get/responses/200",
+			Rule:     RuleCacheUndeclared,
+			Severity: SeverityWarning,
+			Message:  "GET response declares neither Cache-Control nor a validator, clients cannot tell whether to cache it",
+		},
+		{
+			Pointer:  "/paths/~1pets/post/responses/201/headers/Cache-Control",
+			Rule:     RuleCacheOnUnsafeMethod,
+			Severity: SeverityWarning,
+			Message:  "responses to POST requests are not cached by clients, max-age has no effect",
+		},
+		{
+			Pointer:  "/paths/~1session/get/responses/200/headers/Cache-Control",
+			Rule:     RuleCacheControlInvalid,
+			Severity: SeverityError,
+			Message:  `invalid max-age "-1"`,
+		},
+	}, doc.AuditCaching())
+}
+
+func TestCachingSuite(t *testing.T) {
+	suite.Run(t, new(CachingSuite))
+}
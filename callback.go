@@ -4,7 +4,7 @@ import (
 	"encoding/json"
 
 	"github.com/pkg/errors"
-	"gopkg.in/yaml.v2"
+	"gopkg.in/yaml.v3"
 )
 
 // Callback defines a wrapper structure for the Callback Object.
@@ -50,22 +50,7 @@ func (r Callback) MarshalJSON() ([]byte, error) {
 
 // UnmarshalJSON parses the JSON-encoded data and stores the result.
 func (r *Callback) UnmarshalJSON(data []byte) error {
-	return r.UnmarshalYAML(func(in interface{}) error {
-		obj := make(map[string]interface{})
-		if err := json.Unmarshal(data, &obj); err != nil {
-			return errors.WithStack(err)
-		}
-
-		rbytes, err := yaml.Marshal(obj)
-		if err != nil {
-			return errors.WithStack(err)
-		}
-
-		if err := yaml.Unmarshal(rbytes, in); err != nil {
-			return errors.WithStack(err)
-		}
-		return nil
-	})
+	return decodeJSON(data, r)
 }
 
 // MarshalYAML returns the YAML encoding.
@@ -88,7 +73,13 @@ func (r Callback) MarshalYAML() (interface{}, error) {
 }
 
 // UnmarshalYAML parses the YAML-encoded data and stores the result.
-func (r *Callback) UnmarshalYAML(unmarshal func(interface{}) error) error {
+func (r *Callback) UnmarshalYAML(node *yaml.Node) error {
+	return decodeYAML(node, r)
+}
+
+// unmarshalValue stores the result of the unmarshal function, which decodes
+// either JSON or YAML data.
+func (r *Callback) unmarshalValue(d *valueDecoder, unmarshal func(interface{}) error) error {
 	obj := make(map[string]interface{})
 	if err := unmarshal(&obj); err != nil {
 		return errors.WithStack(err)
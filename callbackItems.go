@@ -5,7 +5,7 @@ import (
 	"strings"
 
 	"github.com/pkg/errors"
-	"gopkg.in/yaml.v2"
+	"gopkg.in/yaml.v3"
 )
 
 // CallbackItems represents the collection of PathItem.
@@ -22,22 +22,7 @@ func (r CallbackItems) MarshalJSON() ([]byte, error) {
 
 // UnmarshalJSON parses the JSON-encoded data and stores the result.
 func (r *CallbackItems) UnmarshalJSON(data []byte) error {
-	return r.UnmarshalYAML(func(in interface{}) error {
-		obj := make(map[string]interface{})
-		if err := json.Unmarshal(data, &obj); err != nil {
-			return errors.WithStack(err)
-		}
-
-		rbytes, err := yaml.Marshal(obj)
-		if err != nil {
-			return errors.WithStack(err)
-		}
-
-		if err := yaml.Unmarshal(rbytes, in); err != nil {
-			return errors.WithStack(err)
-		}
-		return nil
-	})
+	return decodeJSON(data, r)
 }
 
 // MarshalYAML returns the YAML encoding.
@@ -52,7 +37,13 @@ func (r CallbackItems) MarshalYAML() (interface{}, error) {
 }
 
 // UnmarshalYAML parses the YAML-encoded data and stores the result.
-func (r *CallbackItems) UnmarshalYAML(unmarshal func(interface{}) error) error {
+func (r *CallbackItems) UnmarshalYAML(node *yaml.Node) error {
+	return decodeYAML(node, r)
+}
+
+// unmarshalValue stores the result of the unmarshal function, which decodes
+// either JSON or YAML data.
+func (r *CallbackItems) unmarshalValue(d *valueDecoder, unmarshal func(interface{}) error) error {
 	obj := make(map[string]*PathItem)
 	if err := unmarshal(&obj); err != nil {
 		return errors.WithStack(err)
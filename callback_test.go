@@ -7,7 +7,7 @@ import (
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/suite"
-	"gopkg.in/yaml.v2"
+	"gopkg.in/yaml.v3"
 )
 
 type CallbackSuite struct {
@@ -35,11 +35,11 @@ func (r *CallbackSuite) TestCallback() {
 									},
 								},
 							},
-							Responses: map[string]*Response{
+							Responses: NewResponses(map[string]*Response{
 								"200": {
 									Description: "webhook successfully processed and no retries will be performed",
 								},
-							},
+							}),
 						},
 					},
 				},
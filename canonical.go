@@ -0,0 +1,261 @@
+package oas
+
+import (
+	"bytes"
+	"encoding/json"
+	"reflect"
+	"sort"
+	"strings"
+
+	"github.com/pkg/errors"
+	"gopkg.in/yaml.v3"
+)
+
+// schemaFieldOrder lists the fields of the schema object in canonical order,
+// which groups related keywords rather than following the declaration of the
+// Schema type.
+var schemaFieldOrder = []string{
+	"$ref",
+	"title",
+	"description",
+	"type",
+	"format",
+	"nullable",
+	"enum",
+	"default",
+	"multipleOf",
+	"maximum",
+	"exclusiveMaximum",
+	"minimum",
+	"exclusiveMinimum",
+	"maxLength",
+	"minLength",
+	"pattern",
+	"contentMediaType",
+	"contentEncoding",
+	"items",
+	"maxItems",
+	"minItems",
+	"uniqueItems",
+	"required",
+	"properties",
+	"additionalProperties",
+	"maxProperties",
+	"minProperties",
+	"allOf",
+	"oneOf",
+	"anyOf",
+	"not",
+	"discriminator",
+	"readOnly",
+	"writeOnly",
+	"xml",
+	"externalDocs",
+	"example",
+	"deprecated",
+}
+
+// orderedEntry describes an entry of an ordered map.
+type orderedEntry struct {
+	key   string
+	value interface{}
+}
+
+// orderedMap describes a map encoded with its entries in order.
+type orderedMap []orderedEntry
+
+// MarshalJSON returns the JSON encoding.
+func (r orderedMap) MarshalJSON() ([]byte, error) {
+	buf := &bytes.Buffer{}
+	buf.WriteByte('{')
+	for i, entry := range r {
+		if i > 0 {
+			buf.WriteByte(',')
+		}
+		key, err := json.Marshal(entry.key)
+		if err != nil {
+			return nil, errors.WithStack(err)
+		}
+		value, err := json.Marshal(entry.value)
+		if err != nil {
+			return nil, errors.WithStack(err)
+		}
+		buf.Write(key)
+		buf.WriteByte(':')
+		buf.Write(value)
+	}
+	buf.WriteByte('}')
+	return buf.Bytes(), nil
+}
+
+// MarshalYAML returns the YAML encoding.
+func (r orderedMap) MarshalYAML() (interface{}, error) {
+	node := &yaml.Node{Kind: yaml.MappingNode, Tag: "!!map"}
+	for _, entry := range r {
+		key := &yaml.Node{}
+		if err := key.Encode(entry.key); err != nil {
+			return nil, errors.WithStack(err)
+		}
+		value := &yaml.Node{}
+		if err := value.Encode(entry.value); err != nil {
+			return nil, errors.WithStack(err)
+		}
+		node.Content = append(node.Content, key, value)
+	}
+	return node, nil
+}
+
+// MarshalCanonicalYAML returns the YAML encoding of the value, a document or
+// any object of the specification, with the fields of objects in the order
+// of the specification (openapi, info, servers, paths, components, ...),
+// followed by their extensions, and the entries of maps such as paths and
+// schemas in alphabetical order. The output is the same between runs, which
+// keeps the diffs of documents under version control minimal.
+func MarshalCanonicalYAML(in interface{}) ([]byte, error) {
+	value, err := canonicalValue(reflect.ValueOf(in))
+	if err != nil {
+		return nil, err
+	}
+	return marshalYAML(value)
+}
+
+// MarshalCanonicalJSON returns the JSON encoding of the value, indented by
+// two spaces, in the order of MarshalCanonicalYAML.
+func MarshalCanonicalJSON(in interface{}) ([]byte, error) {
+	value, err := canonicalValue(reflect.ValueOf(in))
+	if err != nil {
+		return nil, err
+	}
+	rbytes, err := json.Marshal(value)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+	buf := &bytes.Buffer{}
+	if err := json.Indent(buf, rbytes, "", "  "); err != nil {
+		return nil, errors.WithStack(err)
+	}
+	buf.WriteByte('\n')
+	return buf.Bytes(), nil
+}
+
+// canonicalValue returns the value with the objects of the specification
+// and the maps it holds replaced by ordered maps.
+func canonicalValue(rv reflect.Value) (interface{}, error) {
+	if !rv.IsValid() {
+		return nil, nil
+	}
+	if (rv.Kind() == reflect.Ptr || rv.Kind() == reflect.Interface) && rv.IsNil() {
+		return nil, nil
+	}
+	if marshaler, ok := rv.Interface().(yaml.Marshaler); ok {
+		if _, ok := marshaler.(orderedMap); ok {
+			return marshaler, nil
+		}
+		out, err := marshaler.MarshalYAML()
+		if err != nil {
+			return nil, err
+		}
+		switch out := out.(type) {
+		case map[string]interface{}:
+			return canonicalMap(out, fieldOrder(rv.Type()))
+		case orderedMap:
+			obj := make(map[string]interface{}, len(out))
+			for _, entry := range out {
+				obj[entry.key] = entry.value
+			}
+			return canonicalMap(obj, fieldOrder(rv.Type()))
+		}
+		return canonicalValue(reflect.ValueOf(out))
+	}
+
+	switch rv.Kind() {
+	case reflect.Ptr, reflect.Interface:
+		return canonicalValue(rv.Elem())
+	case reflect.Map:
+		obj := make(map[string]interface{}, rv.Len())
+		iter := rv.MapRange()
+		for iter.Next() {
+			key, ok := iter.Key().Interface().(string)
+			if !ok {
+				return rv.Interface(), nil
+			}
+			obj[key] = iter.Value().Interface()
+		}
+		return canonicalMap(obj, nil)
+	case reflect.Slice, reflect.Array:
+		if rv.Kind() == reflect.Slice && rv.Type().Elem().Kind() == reflect.Uint8 {
+			return rv.Interface(), nil
+		}
+		items := make([]interface{}, rv.Len())
+		for i := range items {
+			item, err := canonicalValue(rv.Index(i))
+			if err != nil {
+				return nil, err
+			}
+			items[i] = item
+		}
+		return items, nil
+	}
+	return rv.Interface(), nil
+}
+
+// canonicalMap returns the entries of the map ordered by the fields, with
+// the other entries following in alphabetical order.
+func canonicalMap(obj map[string]interface{}, fields []string) (orderedMap, error) {
+	rank := make(map[string]int, len(fields))
+	for i, field := range fields {
+		rank[field] = i
+	}
+	keys := make([]string, 0, len(obj))
+	for key := range obj {
+		keys = append(keys, key)
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		ri, iok := rank[keys[i]]
+		rj, jok := rank[keys[j]]
+		switch {
+		case iok && jok:
+			return ri < rj
+		case iok != jok:
+			return iok
+		}
+		return keys[i] < keys[j]
+	})
+
+	entries := make(orderedMap, 0, len(keys))
+	for _, key := range keys {
+		value, err := canonicalValue(reflect.ValueOf(obj[key]))
+		if err != nil {
+			return nil, err
+		}
+		entries = append(entries, orderedEntry{key: key, value: value})
+	}
+	return entries, nil
+}
+
+// fieldOrder returns the names of the fields of the object of the
+// specification in canonical order, the order of their declaration unless
+// listed otherwise.
+func fieldOrder(rt reflect.Type) []string {
+	for rt.Kind() == reflect.Ptr {
+		rt = rt.Elem()
+	}
+	if rt == reflect.TypeOf(Schema{}) {
+		return schemaFieldOrder
+	}
+	if rt.Kind() != reflect.Struct {
+		return nil
+	}
+	fields := make([]string, 0, rt.NumField())
+	for i := 0; i < rt.NumField(); i++ {
+		field := rt.Field(i)
+		name := strings.Split(field.Tag.Get("yaml"), ",")[0]
+		switch {
+		case field.Anonymous && name == "":
+			fields = append(fields, fieldOrder(field.Type)...)
+		case name != "" && name != "-":
+			fields = append(fields, name)
+		}
+	}
+	return fields
+}
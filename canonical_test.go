@@ -0,0 +1,167 @@
+package oas
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+)
+
+type CanonicalSuite struct {
+	suite.Suite
+}
+
+// canonicalPatch is the patch of the petstore fixture the suite marshals, its
+// keys out of the canonical order.
+const canonicalPatch = `
+x-logo: logo.png
+x-audience: public
+servers:
+  - url: https://petstore.example.com
+paths:
+  /pets:
+    get:
+      parameters:
+        - name: limit
+          in: query
+          schema:
+            type: integer
+            format: int32
+      responses:
+        default:
+          description: An error.
+  /owners: {}
+components:
+  schemas:
+    Pet:
+      properties:
+        age:
+          type: integer
+    Error:
+      type: string
+`
+
+func (r *CanonicalSuite) TestMarshalCanonicalYAML() {
+	expected := `openapi: 3.0.3
+info:
+  title: Petstore
+  version: 1.0.0
+servers:
+  - url: https://petstore.example.com
+paths:
+  /owners: {}
+  /pets:
+    get:
+      operationId: listPets
+      parameters:
+        - name: limit
+          in: query
+          schema:
+            type: integer
+            format: int32
+      responses:
+        "200":
+          description: The pets.
+          content:
+            application/json:
+              schema:
+                type: array
+                items:
+                  $ref: '#/components/schemas/Pet'
+        default:
+          description: An error.
+    post:
+      operationId: createPet
+      requestBody:
+        content:
+          application/json:
+            schema:
+              $ref: '#/components/schemas/Pet'
+        required: true
+      responses:
+        "201":
+          description: Created.
+  /pets/{petId}:
+    get:
+      operationId: getPet
+      responses:
+        "200":
+          description: The pet.
+          content:
+            application/json:
+              schema:
+                $ref: '#/components/schemas/Pet'
+    delete:
+      operationId: deletePet
+      responses:
+        "204":
+          description: Deleted.
+    parameters:
+      - name: petId
+        in: path
+        required: true
+        schema:
+          type: integer
+components:
+  schemas:
+    Error:
+      type: string
+    Pet:
+      type: object
+      required:
+        - name
+      properties:
+        age:
+          type: integer
+        name:
+          type: string
+        tag:
+          type: string
+x-audience: public
+x-logo: logo.png
+`
+	for i := 0; i < 5; i++ {
+		rbytes, err := MarshalCanonicalYAML(petstore(r.T(), canonicalPatch))
+		assert.Nil(r.T(), err)
+		assert.Equal(r.T(), expected, string(rbytes))
+	}
+
+	doc := &OpenAPI{}
+	rbytes, err := MarshalCanonicalYAML(petstore(r.T(), canonicalPatch))
+	assert.Nil(r.T(), err)
+	assert.Nil(r.T(), decodeDocument(rbytes, doc))
+	rbytes, err = MarshalCanonicalYAML(doc)
+	assert.Nil(r.T(), err)
+	assert.Equal(r.T(), expected, string(rbytes))
+}
+
+func (r *CanonicalSuite) TestMarshalCanonicalJSON() {
+	testCases := []struct {
+		in       interface{}
+		expected string
+	}{
+		{
+			&Info{Version: "1.0.0", Title: "Petstore", License: &License{Name: "MIT"}},
+			"{\n  \"title\": \"Petstore\",\n  \"license\": {\n    \"name\": \"MIT\"\n  },\n  \"version\": \"1.0.0\"\n}\n",
+		},
+		{
+			map[string]*Schema{"b": {Type: "string", Description: "B."}, "a": nil},
+			"{\n  \"a\": null,\n  \"b\": {\n    \"description\": \"B.\",\n    \"type\": \"string\"\n  }\n}\n",
+		},
+		{
+			[]*Tag{{Name: "pets"}},
+			"[\n  {\n    \"name\": \"pets\"\n  }\n]\n",
+		},
+	}
+
+	for i, testCase := range testCases {
+		failMsg := "test case %d failed"
+		rbytes, err := MarshalCanonicalJSON(testCase.in)
+		assert.Nil(r.T(), err, failMsg, i+1)
+		assert.Equal(r.T(), testCase.expected, string(rbytes), failMsg, i+1)
+	}
+}
+
+func TestCanonicalSuite(t *testing.T) {
+	suite.Run(t, new(CanonicalSuite))
+}
@@ -0,0 +1,176 @@
+package oas
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// CodeSamplesExtension names the specification extension of an operation
+// listing samples of code calling it, as displayed by documentation tools
+// such as ReDoc.
+const CodeSamplesExtension = "x-codeSamples"
+
+// Languages of the generated code samples.
+const (
+	LangShell      = "Shell"
+	LangGo         = "Go"
+	LangJavaScript = "JavaScript"
+)
+
+// CodeSample describes a sample of code calling an operation.
+type CodeSample struct {
+	// Lang describes the language of the sample, used for syntax
+	// highlighting (e.g. "Go").
+	Lang string `json:"lang" yaml:"lang"`
+
+	// Label describes the name of the sample shown instead of the language.
+	Label string `json:"label,omitempty" yaml:"label,omitempty"`
+
+	// Source describes the code of the sample.
+	Source string `json:"source" yaml:"source"`
+}
+
+// CodeSamples returns the code samples declared by the x-codeSamples
+// extension, or nil if the extension is absent.
+func (r Extensions) CodeSamples() ([]*CodeSample, error) {
+	samples := make([]*CodeSample, 0)
+	ok, err := r.decode(CodeSamplesExtension, &samples)
+	if !ok || err != nil {
+		return nil, err
+	}
+	for i, sample := range samples {
+		if sample == nil || sample.Lang == "" || sample.Source == "" {
+			return nil, errors.Errorf("%s: sample %d is missing lang or source", CodeSamplesExtension, i)
+		}
+	}
+	return samples, nil
+}
+
+// SetCodeSamples declares the code samples with the x-codeSamples
+// extension, or removes the extension when empty.
+func (r *Extensions) SetCodeSamples(samples []*CodeSample) {
+	if len(samples) == 0 {
+		delete(*r, CodeSamplesExtension)
+		return
+	}
+	setExtension(r, CodeSamplesExtension, samples)
+}
+
+// CodeSamples returns samples calling the operation under the path and
+// lowercase method at the base URL with curl, Go and JavaScript. The
+// request is built from the examples of the parameters and request body, as
+// for the generated tests; an error is returned when a required parameter
+// or body has no example.
+func (r OpenAPI) CodeSamples(path string, method string, baseURL string) ([]*CodeSample, error) {
+	op, err := r.Paths.operation(path, method)
+	if err != nil {
+		return nil, err
+	}
+	sample, missing := r.sampleRequest(op)
+	if sample == nil {
+		return nil, errors.Errorf("%s %s: %s", strings.ToUpper(method), path, missing)
+	}
+	method = strings.ToUpper(method)
+	url := strings.TrimSuffix(baseURL, "/") + sample.path
+	return []*CodeSample{
+		{Lang: LangShell, Label: "curl", Source: curlSample(method, url, sample)},
+		{Lang: LangGo, Source: goSample(method, url, sample)},
+		{Lang: LangJavaScript, Source: javaScriptSample(method, url, sample)},
+	}, nil
+}
+
+// GenerateCodeSamples declares code samples calling the operations at the
+// base URL with the x-codeSamples extension. Operations which already
+// declare samples, and operations missing examples of required parameters
+// or bodies, are left unchanged. It returns the number of operations given
+// samples.
+func (r *OpenAPI) GenerateCodeSamples(baseURL string) int {
+	count := 0
+	for _, op := range r.Paths.operations() {
+		if _, ok := op.operation.Extensions[CodeSamplesExtension]; ok {
+			continue
+		}
+		samples, err := r.CodeSamples(op.path, op.method, baseURL)
+		if err != nil {
+			continue
+		}
+		op.operation.Extensions.SetCodeSamples(samples)
+		count++
+	}
+	return count
+}
+
+// curlSample returns the curl command sending the request.
+func curlSample(method string, url string, sample *sampleRequest) string {
+	lines := []string{"curl -X " + method + " " + shellQuote(url)}
+	for _, name := range sortedStrings(sample.headers) {
+		lines = append(lines, "  -H "+shellQuote(name+": "+sample.headers[name]))
+	}
+	if sample.body != nil {
+		lines = append(lines, "  --data "+shellQuote(*sample.body))
+	}
+	return strings.Join(lines, " \\\n") + "\n"
+}
+
+// shellQuote returns the string quoted for POSIX shells.
+func shellQuote(s string) string {
+	return "'" + strings.Replace(s, "'", `'\''`, -1) + "'"
+}
+
+// goSample returns the Go code sending the request with net/http.
+func goSample(method string, url string, sample *sampleRequest) string {
+	buf := &strings.Builder{}
+	body := "nil"
+	if sample.body != nil {
+		fmt.Fprintf(buf, "body := strings.NewReader(%s)\n", strconv.Quote(*sample.body))
+		body = "body"
+	}
+	fmt.Fprintf(buf, "req, err := http.NewRequest(%s, %s, %s)\n", strconv.Quote(method), strconv.Quote(url), body)
+	buf.WriteString("if err != nil {\n\tlog.Fatal(err)\n}\n")
+	for _, name := range sortedStrings(sample.headers) {
+		fmt.Fprintf(buf, "req.Header.Set(%s, %s)\n", strconv.Quote(name), strconv.Quote(sample.headers[name]))
+	}
+	buf.WriteString("resp, err := http.DefaultClient.Do(req)\n")
+	buf.WriteString("if err != nil {\n\tlog.Fatal(err)\n}\n")
+	buf.WriteString("defer resp.Body.Close()\n")
+	return buf.String()
+}
+
+// javaScriptSample returns the JavaScript code sending the request with
+// fetch. JSON bodies are written as object literals.
+func javaScriptSample(method string, url string, sample *sampleRequest) string {
+	buf := &strings.Builder{}
+	fmt.Fprintf(buf, "const response = await fetch(%s, {\n", jsString(url))
+	fmt.Fprintf(buf, "  method: %s,\n", jsString(method))
+	if len(sample.headers) > 0 {
+		buf.WriteString("  headers: {\n")
+		names := sortedStrings(sample.headers)
+		for i, name := range names {
+			separator := ","
+			if i == len(names)-1 {
+				separator = ""
+			}
+			fmt.Fprintf(buf, "    %s: %s%s\n", jsString(name), jsString(sample.headers[name]), separator)
+		}
+		buf.WriteString("  },\n")
+	}
+	if sample.body != nil {
+		if isJSONMediaType(sample.headers["Content-Type"]) && json.Valid([]byte(*sample.body)) {
+			fmt.Fprintf(buf, "  body: JSON.stringify(%s),\n", *sample.body)
+		} else {
+			fmt.Fprintf(buf, "  body: %s,\n", jsString(*sample.body))
+		}
+	}
+	buf.WriteString("});\n")
+	return buf.String()
+}
+
+// jsString returns the string as a JavaScript string literal.
+func jsString(s string) string {
+	rbytes, _ := json.Marshal(s)
+	return string(rbytes)
+}
@@ -0,0 +1,142 @@
+package oas
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+)
+
+type CodeSamplesSuite struct {
+	suite.Suite
+}
+
+// codeSamplesPatch is the patch of the petstore fixture the suite samples.
+const codeSamplesPatch = `
+paths:
+  /pets/{petId}:
+    parameters:
+      - name: petId
+        in: path
+        required: true
+        schema:
+          type: integer
+        example: 7
+    put:
+      operationId: updatePet
+      parameters:
+        - name: X-Request-Id
+          in: header
+          example: abc
+      requestBody:
+        required: true
+        content:
+          application/json:
+            example:
+              name: Rex's
+      responses:
+        "200":
+          description: The pet.
+`
+
+func (r *CodeSamplesSuite) TestCodeSamples() {
+	samples, err := petstore(r.T(), codeSamplesPatch).CodeSamples("/pets/{petId}", "put", "https://petstore.example.com/")
+	assert.Nil(r.T(), err)
+	assert.Equal(r.T(), []*CodeSample{
+		{
+			Lang:  LangShell,
+			Label: "curl",
+			Source: `curl -X PUT 'https://petstore.example.com/pets/7' \
+  -H 'Content-Type: application/json' \
+  -H 'X-Request-Id: abc' \
+  --data '{"name":"Rex'\''s"}'
+`,
+		},
+		{
+			Lang: LangGo,
+			Source: `body := strings.NewReader("{\"name\":\"Rex's\"}")
+req, err := http.NewRequest("PUT", "https://petstore.example.com/pets/7", body)
+if err != nil {
+	log.Fatal(err)
+}
+req.Header.Set("Content-Type", "application/json")
+req.Header.Set("X-Request-Id", "abc")
+resp, err := http.DefaultClient.Do(req)
+if err != nil {
+	log.Fatal(err)
+}
+defer resp.Body.Close()
+`,
+		},
+		{
+			Lang: LangJavaScript,
+			Source: `const response = await fetch("https://petstore.example.com/pets/7", {
+  method: "PUT",
+  headers: {
+    "Content-Type": "application/json",
+    "X-Request-Id": "abc"
+  },
+  body: JSON.stringify({"name":"Rex's"}),
+});
+`,
+		},
+	}, samples)
+
+}
+
+func (r *CodeSamplesSuite) TestCodeSamplesErrors() {
+	testCases := []struct {
+		patch  string
+		path   string
+		method string
+	}{
+		{"", "/unknown", "get"},
+		{"", "/pets", "post"},
+		{`
+paths:
+  /pets/{petId}:
+    parameters:
+      - name: petId
+        in: path
+        required: true
+`, "/pets/{petId}", "delete"},
+	}
+
+	for i, testCase := range testCases {
+		failMsg := "test case %d failed"
+		_, err := petstore(r.T(), codeSamplesPatch, testCase.patch).CodeSamples(testCase.path, testCase.method, "https://petstore.example.com")
+		assert.NotNil(r.T(), err, failMsg, i)
+	}
+}
+
+func (r *CodeSamplesSuite) TestGenerateCodeSamples() {
+	doc := petstore(r.T(), codeSamplesPatch)
+	custom := []*CodeSample{{Lang: "Python", Source: "requests.get(url)"}}
+	doc.Paths.PathItems.Get("/pets").Get.Extensions.SetCodeSamples(custom)
+	assert.Equal(r.T(), 3, doc.GenerateCodeSamples("https://petstore.example.com"))
+
+	samples, err := doc.Paths.PathItems.Get("/pets").Get.Extensions.CodeSamples()
+	assert.Nil(r.T(), err)
+	assert.Equal(r.T(), custom, samples)
+	samples, err = doc.Paths.PathItems.Get("/pets/{petId}").Put.Extensions.CodeSamples()
+	assert.Nil(r.T(), err)
+	assert.Len(r.T(), samples, 3)
+	samples, err = doc.Paths.PathItems.Get("/pets").Post.Extensions.CodeSamples()
+	assert.Nil(r.T(), err)
+	assert.Nil(r.T(), samples)
+
+	rbytes, err := MarshalCanonicalYAML(doc)
+	assert.Nil(r.T(), err)
+	decoded := &OpenAPI{}
+	assert.Nil(r.T(), decodeDocument(rbytes, decoded))
+	samples, err = decoded.Paths.PathItems.Get("/pets").Get.Extensions.CodeSamples()
+	assert.Nil(r.T(), err)
+	assert.Equal(r.T(), custom, samples)
+
+	_, err = Extensions{CodeSamplesExtension: []interface{}{map[string]interface{}{"lang": "Go"}}}.CodeSamples()
+	assert.NotNil(r.T(), err)
+}
+
+func TestCodeSamplesSuite(t *testing.T) {
+	suite.Run(t, new(CodeSamplesSuite))
+}
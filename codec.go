@@ -0,0 +1,464 @@
+package oas
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+
+	"github.com/pkg/errors"
+	"gopkg.in/yaml.v3"
+)
+
+// valueUnmarshaler is implemented by the objects of the specification, which
+// decode JSON and YAML data alike through an unmarshal function. Their
+// members are decoded with the decoder the object is decoded with.
+type valueUnmarshaler interface {
+	unmarshalValue(d *valueDecoder, unmarshal func(interface{}) error) error
+}
+
+// valueUnmarshalerType is the type of the value unmarshalers.
+var valueUnmarshalerType = reflect.TypeOf((*valueUnmarshaler)(nil)).Elem()
+
+// keyOrders records the keys of the generic maps decoded from a document in
+// the order they were written, by the address of the map, as maps do not
+// keep the order of their keys. The maps are held by the decoded document,
+// so their addresses stay unique while it is decoded.
+type keyOrders map[uintptr][]string
+
+// valueDecoder stores the generic values decoded from one document into
+// Go values like the YAML decoder would, without encoding them again. It
+// carries the key orders of the document so ordered maps such as PathItems
+// keep the order their keys were written in.
+type valueDecoder struct {
+	orders keyOrders
+}
+
+// decodeJSON decodes the JSON-encoded data into the value out points to like
+// decodeValue, keeping the order of the keys of its objects.
+func decodeJSON(data []byte, out interface{}) error {
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+	d := &valueDecoder{orders: keyOrders{}}
+	value, err := d.orders.json(decoder)
+	if err != nil {
+		return err
+	}
+	return d.decode(value, out)
+}
+
+// decodeYAML decodes the YAML node into the value out points to like
+// decodeValue, keeping the order of the keys of its mappings.
+func decodeYAML(node *yaml.Node, out interface{}) error {
+	d := &valueDecoder{orders: keyOrders{}}
+	value, err := d.orders.node(node)
+	if err != nil {
+		return err
+	}
+	return d.decode(value, out)
+}
+
+// decodeDocument decodes JSON or YAML encoded data into the value pointed to
+// by out. Data starting with a JSON object or array is decoded as JSON.
+func decodeDocument(data []byte, out interface{}) error {
+	trimmed := bytes.TrimSpace(data)
+	if len(trimmed) > 0 && (trimmed[0] == '{' || trimmed[0] == '[') {
+		if err := json.Unmarshal(data, out); err != nil {
+			return errors.WithStack(err)
+		}
+		return nil
+	}
+	node := &yaml.Node{}
+	if err := yaml.Unmarshal(data, node); err != nil {
+		return errors.WithStack(err)
+	}
+	return decodeYAML(node, out)
+}
+
+// nodeValue returns the generic value of the YAML node. Mappings become maps
+// keyed by the text of their keys, as in JSON, so keys such as response
+// codes stay strings, and merge keys are expanded.
+func nodeValue(node *yaml.Node) (interface{}, error) {
+	return keyOrders{}.node(node)
+}
+
+// add records the keys of the map.
+func (r keyOrders) add(obj map[string]interface{}, keys []string) {
+	r[reflect.ValueOf(obj).Pointer()] = keys
+}
+
+// node returns the generic value of the YAML node like nodeValue, recording
+// the order of the keys of its mappings.
+func (r keyOrders) node(node *yaml.Node) (interface{}, error) {
+	switch node.Kind {
+	case yaml.DocumentNode:
+		if len(node.Content) == 0 {
+			return nil, nil
+		}
+		return r.node(node.Content[0])
+	case yaml.AliasNode:
+		return r.node(node.Alias)
+	case yaml.SequenceNode:
+		items := make([]interface{}, len(node.Content))
+		for i, child := range node.Content {
+			item, err := r.node(child)
+			if err != nil {
+				return nil, err
+			}
+			items[i] = item
+		}
+		return items, nil
+	case yaml.MappingNode:
+		obj := make(map[string]interface{}, len(node.Content)/2)
+		keys := make([]string, 0, len(node.Content)/2)
+		merged := make([]interface{}, 0)
+		for i := 0; i+1 < len(node.Content); i += 2 {
+			item, err := r.node(node.Content[i+1])
+			if err != nil {
+				return nil, err
+			}
+			if node.Content[i].Tag == "!!merge" {
+				if items, ok := item.([]interface{}); ok {
+					merged = append(merged, items...)
+				} else {
+					merged = append(merged, item)
+				}
+				continue
+			}
+			key := node.Content[i].Value
+			if _, ok := obj[key]; !ok {
+				keys = append(keys, key)
+			}
+			obj[key] = item
+		}
+		for _, item := range merged {
+			entries, _ := item.(map[string]interface{})
+			for _, key := range r[reflect.ValueOf(entries).Pointer()] {
+				if _, ok := obj[key]; !ok {
+					keys = append(keys, key)
+					obj[key] = entries[key]
+				}
+			}
+		}
+		r.add(obj, keys)
+		return obj, nil
+	}
+	var value interface{}
+	if err := node.Decode(&value); err != nil {
+		return nil, errors.WithStack(err)
+	}
+	return value, nil
+}
+
+// json returns the generic value of the next JSON value of the decoder,
+// recording the order of the keys of its objects. Numbers become the
+// integers and floats the YAML decoder produces for them.
+func (r keyOrders) json(decoder *json.Decoder) (interface{}, error) {
+	token, err := decoder.Token()
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+	switch token := token.(type) {
+	case json.Delim:
+		if token == '[' {
+			items := make([]interface{}, 0)
+			for decoder.More() {
+				item, err := r.json(decoder)
+				if err != nil {
+					return nil, err
+				}
+				items = append(items, item)
+			}
+			if _, err := decoder.Token(); err != nil {
+				return nil, errors.WithStack(err)
+			}
+			return items, nil
+		}
+		obj := make(map[string]interface{})
+		keys := make([]string, 0)
+		for decoder.More() {
+			token, err := decoder.Token()
+			if err != nil {
+				return nil, errors.WithStack(err)
+			}
+			key, _ := token.(string)
+			item, err := r.json(decoder)
+			if err != nil {
+				return nil, err
+			}
+			if _, ok := obj[key]; !ok {
+				keys = append(keys, key)
+			}
+			obj[key] = item
+		}
+		if _, err := decoder.Token(); err != nil {
+			return nil, errors.WithStack(err)
+		}
+		r.add(obj, keys)
+		return obj, nil
+	case json.Number:
+		return jsonNumber(token), nil
+	}
+	return token, nil
+}
+
+// jsonNumber returns the integer or float the YAML decoder produces for the
+// JSON number.
+func jsonNumber(value json.Number) interface{} {
+	if i, err := strconv.ParseInt(string(value), 10, 0); err == nil {
+		return int(i)
+	}
+	f, err := value.Float64()
+	if err != nil {
+		return string(value)
+	}
+	if i, err := strconv.ParseInt(strconv.FormatFloat(f, 'g', -1, 64), 10, 0); err == nil {
+		return int(i)
+	}
+	return f
+}
+
+// members returns the members of the object decoded by the unmarshal
+// function, as given to unmarshalValue, with their keys in the order they
+// were written. Objects of values which were not read from a document have
+// no order and their keys are returned in alphabetical order.
+func (d *valueDecoder) members(unmarshal func(interface{}) error) (map[string]interface{}, []string, error) {
+	var value interface{}
+	if err := unmarshal(&value); err != nil {
+		return nil, nil, errors.WithStack(err)
+	}
+	obj := make(map[string]interface{})
+	if err := d.decode(value, &obj); err != nil {
+		return nil, nil, errors.WithStack(err)
+	}
+	if d.orders == nil {
+		return obj, sortedStrings(obj), nil
+	}
+	generic, _ := value.(map[string]interface{})
+	keys, ok := d.orders[reflect.ValueOf(generic).Pointer()]
+	if !ok || len(keys) != len(obj) {
+		return nil, nil, errors.New("missing order of the keys of the object")
+	}
+	return obj, append([]string{}, keys...), nil
+}
+
+// decodeValue stores the generic value, as decoded from JSON or YAML, into
+// the value out points to like the YAML decoder would, without encoding it
+// again. Objects of the specification are decoded by their unmarshalValue
+// method, ordered maps in alphabetical order of their keys.
+func decodeValue(value interface{}, out interface{}) error {
+	return (&valueDecoder{}).decode(value, out)
+}
+
+// decode stores the generic value into the value out points to.
+func (d *valueDecoder) decode(value interface{}, out interface{}) error {
+	rv := reflect.ValueOf(out)
+	if rv.Kind() != reflect.Ptr || rv.IsNil() {
+		return errors.Errorf("cannot decode into %T", out)
+	}
+	return d.decodeReflect(value, rv.Elem())
+}
+
+// decodeMember stores the member of an object under the key into the value
+// out points to like decode, so that errors point at the member.
+func (d *valueDecoder) decodeMember(key string, value interface{}, out interface{}) error {
+	return memberError(key, d.decode(value, out))
+}
+
+// valueError describes a value which cannot be decoded, by its JSON Pointer
+// relative to the value being decoded.
+type valueError struct {
+	pointer string
+	err     error
+}
+
+// Error returns the string representation of the error.
+func (e *valueError) Error() string {
+	return e.pointer + ": " + e.err.Error()
+}
+
+// memberError returns the error of decoding the member under the token, its
+// pointer prefixed with the token, or nil when err is nil.
+func memberError(token string, err error) error {
+	if err == nil {
+		return nil
+	}
+	if verr, ok := errors.Cause(err).(*valueError); ok {
+		return &valueError{pointer: join("", token) + verr.pointer, err: verr.err}
+	}
+	return &valueError{pointer: join("", token), err: err}
+}
+
+// decodeReflect stores the generic value into the settable value.
+func (d *valueDecoder) decodeReflect(value interface{}, out reflect.Value) error {
+	if out.CanAddr() && out.Addr().Type().Implements(valueUnmarshalerType) {
+		if value == nil {
+			return nil
+		}
+		if out.Kind() == reflect.Map && out.IsNil() {
+			out.Set(reflect.MakeMap(out.Type()))
+		}
+		unmarshaler := out.Addr().Interface().(valueUnmarshaler)
+		return unmarshaler.unmarshalValue(d, func(in interface{}) error {
+			return d.decode(value, in)
+		})
+	}
+
+	if value == nil {
+		out.Set(reflect.Zero(out.Type()))
+		return nil
+	}
+
+	switch out.Kind() {
+	case reflect.Interface:
+		out.Set(reflect.ValueOf(value))
+		return nil
+	case reflect.Ptr:
+		elem := reflect.New(out.Type().Elem())
+		if err := d.decodeReflect(value, elem.Elem()); err != nil {
+			return err
+		}
+		out.Set(elem)
+		return nil
+	case reflect.Map:
+		if out.IsNil() {
+			out.Set(reflect.MakeMap(out.Type()))
+		}
+		return d.decodeMap(value, out)
+	case reflect.Slice:
+		items, ok := value.([]interface{})
+		if !ok {
+			return decodeError(value, out)
+		}
+		slice := reflect.MakeSlice(out.Type(), len(items), len(items))
+		for i, item := range items {
+			if err := d.decodeReflect(item, slice.Index(i)); err != nil {
+				return memberError(strconv.Itoa(i), err)
+			}
+		}
+		out.Set(slice)
+		return nil
+	case reflect.Struct:
+		return d.decodeStruct(value, out)
+	case reflect.String:
+		switch value := value.(type) {
+		case string:
+			out.SetString(value)
+		case int, int64, uint64, float64, bool:
+			out.SetString(fmt.Sprint(value))
+		default:
+			return decodeError(value, out)
+		}
+		return nil
+	case reflect.Bool:
+		b, ok := value.(bool)
+		if !ok {
+			return decodeError(value, out)
+		}
+		out.SetBool(b)
+		return nil
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		switch number := value.(type) {
+		case int:
+			out.SetInt(int64(number))
+		case int64:
+			out.SetInt(number)
+		case float64:
+			if number != float64(int64(number)) {
+				return decodeError(value, out)
+			}
+			out.SetInt(int64(number))
+		default:
+			return decodeError(value, out)
+		}
+		return nil
+	case reflect.Float32, reflect.Float64:
+		switch number := value.(type) {
+		case int:
+			out.SetFloat(float64(number))
+		case int64:
+			out.SetFloat(float64(number))
+		case float64:
+			out.SetFloat(number)
+		default:
+			return decodeError(value, out)
+		}
+		return nil
+	}
+	return decodeError(value, out)
+}
+
+// decodeMap stores the entries of the generic map into the map value,
+// converting keys to strings like the YAML decoder.
+func (d *valueDecoder) decodeMap(value interface{}, out reflect.Value) error {
+	entries := map[string]interface{}{}
+	switch value := value.(type) {
+	case map[string]interface{}:
+		entries = value
+	case map[interface{}]interface{}:
+		for key, item := range value {
+			entries[fmt.Sprint(key)] = item
+		}
+	default:
+		return decodeError(value, out)
+	}
+	if out.Type().Key().Kind() != reflect.String {
+		return decodeError(value, out)
+	}
+	for key, item := range entries {
+		elem := reflect.New(out.Type().Elem()).Elem()
+		if err := d.decodeReflect(item, elem); err != nil {
+			return memberError(key, err)
+		}
+		out.SetMapIndex(reflect.ValueOf(key).Convert(out.Type().Key()), elem)
+	}
+	return nil
+}
+
+// decodeStruct stores the entries of the generic map into the fields of the
+// struct value named by their yaml tags.
+func (d *valueDecoder) decodeStruct(value interface{}, out reflect.Value) error {
+	entries := map[string]interface{}{}
+	if err := d.decodeMap(value, reflect.ValueOf(entries)); err != nil {
+		return decodeError(value, out)
+	}
+	for i := 0; i < out.NumField(); i++ {
+		field := out.Type().Field(i)
+		name := strings.Split(field.Tag.Get("yaml"), ",")[0]
+		if name == "-" || field.PkgPath != "" {
+			continue
+		}
+		if name == "" {
+			name = strings.ToLower(field.Name)
+		}
+		if item, ok := entries[name]; ok {
+			if err := d.decodeReflect(item, out.Field(i)); err != nil {
+				return memberError(name, err)
+			}
+		}
+	}
+	return nil
+}
+
+// decodeError returns the error of a value which cannot be stored into the
+// value of the type.
+func decodeError(value interface{}, out reflect.Value) error {
+	return errors.Errorf("cannot unmarshal %T `%v` into %s", value, value, out.Type())
+}
+
+// marshalYAML returns the YAML encoding of the value, indented by two
+// spaces.
+func marshalYAML(in interface{}) ([]byte, error) {
+	buf := &bytes.Buffer{}
+	encoder := yaml.NewEncoder(buf)
+	encoder.SetIndent(2)
+	if err := encoder.Encode(in); err != nil {
+		return nil, errors.WithStack(err)
+	}
+	if err := encoder.Close(); err != nil {
+		return nil, errors.WithStack(err)
+	}
+	return buf.Bytes(), nil
+}
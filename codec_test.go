@@ -0,0 +1,240 @@
+package oas
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+	"gopkg.in/yaml.v3"
+)
+
+type CodecSuite struct {
+	suite.Suite
+}
+
+func (r *CodecSuite) TestJSONNumbers() {
+	testCases := []struct {
+		data     string
+		expected interface{}
+	}{
+		{`20`, 20},
+		{`-3`, -3},
+		{`1.5`, 1.5},
+		{`20.0`, 20},
+		{`1e21`, 1e21},
+		{`[1, {"a": 2.5}]`, []interface{}{1, map[string]interface{}{"a": 2.5}}},
+	}
+
+	failMsg := "test case %d failed"
+	for i, tc := range testCases {
+		var value interface{}
+		assert.Nil(r.T(), decodeJSON([]byte(tc.data), &value), failMsg, i)
+		assert.Equal(r.T(), tc.expected, value, failMsg, i)
+	}
+}
+
+func (r *CodecSuite) TestDecodeValue() {
+	type plain struct {
+		Name  string            `yaml:"name"`
+		Count int               `yaml:"count"`
+		Tags  []string          `yaml:"tags,omitempty"`
+		Skip  string            `yaml:"-"`
+		Meta  map[string]string `yaml:"meta"`
+	}
+	value := plain{}
+	assert.Nil(r.T(), decodeValue(map[interface{}]interface{}{
+		"name":  "pets",
+		"count": 3,
+		"tags":  []interface{}{"a", 1},
+		"Skip":  "x",
+		"meta":  map[interface{}]interface{}{200: true},
+	}, &value))
+	assert.Equal(r.T(), plain{Name: "pets", Count: 3, Tags: []string{"a", "1"}, Meta: map[string]string{"200": "true"}}, value)
+
+	assert.NotNil(r.T(), decodeValue("pets", &value))
+	assert.NotNil(r.T(), decodeValue(map[string]interface{}{"count": "3"}, &value))
+	assert.NotNil(r.T(), decodeValue(map[string]interface{}{}, value))
+}
+
+func (r *CodecSuite) TestEquivalence() {
+	data := []byte(`{
+		"openapi": "3.0.0",
+		"info": {"title": "Petstore", "version": "1.0.0", "x-audience": {"level": 2}},
+		"paths": {
+			"/pets": {
+				"get": {
+					"operationId": "listPets",
+					"parameters": [{"name": "limit", "in": "query", "schema": {"type": "integer", "maximum": 100, "default": 20}}],
+					"responses": {
+						"200": {
+							"description": "Pets.",
+							"content": {"application/json": {"schema": {"$ref": "#/components/schemas/Pets"}, "example": [{"id": 1.5}]}}
+						}
+					}
+				}
+			}
+		},
+		"components": {
+			"schemas": {
+				"Pets": {"type": "array", "items": {"type": "object", "required": ["id"], "properties": {"id": {"type": "number"}}}}
+			}
+		}
+	}`)
+	fromJSON := &OpenAPI{}
+	assert.Nil(r.T(), json.Unmarshal(data, fromJSON))
+	fromYAML := &OpenAPI{}
+	assert.Nil(r.T(), yaml.Unmarshal(data, fromYAML))
+	assert.Equal(r.T(), fromYAML, fromJSON)
+	assert.Equal(r.T(), 20, fromJSON.Paths.PathItems.Get("/pets").Get.Parameters[0].Schema.Default)
+}
+
+func (r *CodecSuite) TestNativeMaps() {
+	data := []byte(`
+type: object
+default:
+  name: Rex
+  tags: [a, b]
+example:
+  200: ok
+x-meta:
+  owner:
+    team: pets
+  codes:
+    404: missing
+`)
+	schema := &Schema{}
+	assert.Nil(r.T(), yaml.Unmarshal(data, schema))
+	assert.Equal(r.T(), map[string]interface{}{"name": "Rex", "tags": []interface{}{"a", "b"}}, schema.Default)
+	assert.Equal(r.T(), map[string]interface{}{"200": "ok"}, schema.Example)
+	assert.Equal(r.T(), map[string]interface{}{
+		"owner": map[string]interface{}{"team": "pets"},
+		"codes": map[string]interface{}{"404": "missing"},
+	}, schema.Extensions["x-meta"])
+
+	rbytes, err := marshalYAML(schema)
+	assert.Nil(r.T(), err)
+	actual := &Schema{}
+	assert.Nil(r.T(), yaml.Unmarshal(rbytes, actual))
+	assert.Equal(r.T(), schema, actual)
+}
+
+func (r *CodecSuite) TestNodeValue() {
+	testCases := []struct {
+		data     string
+		expected interface{}
+	}{
+		{`20`, 20},
+		{`{200: ok, true: 1, ~: 2}`, map[string]interface{}{"200": "ok", "true": 1, "~": 2}},
+		{"- {1: a}\n- [b]\n", []interface{}{map[string]interface{}{"1": "a"}, []interface{}{"b"}}},
+		{
+			"base: &base {200: ok, name: a}\nderived:\n  <<: *base\n  name: b\n",
+			map[string]interface{}{
+				"base":    map[string]interface{}{"200": "ok", "name": "a"},
+				"derived": map[string]interface{}{"200": "ok", "name": "b"},
+			},
+		},
+	}
+
+	failMsg := "test case %d failed"
+	for i, tc := range testCases {
+		node := &yaml.Node{}
+		if !assert.Nil(r.T(), yaml.Unmarshal([]byte(tc.data), node), failMsg, i) {
+			continue
+		}
+		value, err := nodeValue(node)
+		assert.Nil(r.T(), err, failMsg, i)
+		assert.Equal(r.T(), tc.expected, value, failMsg, i)
+	}
+}
+
+func (r *CodecSuite) TestKeyOrder() {
+	testCases := []struct {
+		decode   func(out interface{}) error
+		expected []string
+	}{
+		{
+			func(out interface{}) error {
+				return decodeJSON([]byte(`{"/pets": {}, "/owners": {}, "/a": {}, "/owners": {}}`), out)
+			},
+			[]string{"/pets", "/owners", "/a"},
+		},
+		{
+			func(out interface{}) error {
+				node := &yaml.Node{}
+				if err := yaml.Unmarshal([]byte("base: &base {/b: {}, /a: {}}\npaths:\n  /pets: {}\n  <<: *base\n"), node); err != nil {
+					return err
+				}
+				obj := struct {
+					Paths PathItems `yaml:"paths"`
+				}{}
+				if err := decodeYAML(node, &obj); err != nil {
+					return err
+				}
+				*out.(*PathItems) = obj.Paths
+				return nil
+			},
+			[]string{"/pets", "/b", "/a"},
+		},
+		{
+			func(out interface{}) error {
+				return decodeValue(map[string]interface{}{"/pets": nil, "/owners": nil, "/a": nil}, out)
+			},
+			[]string{"/a", "/owners", "/pets"},
+		},
+		{
+			func(out interface{}) error {
+				d := &valueDecoder{orders: keyOrders{}}
+				return d.decode(map[string]interface{}{"/pets": nil}, out)
+			},
+			nil,
+		},
+	}
+
+	failMsg := "test case %d failed"
+	for i, tc := range testCases {
+		paths := PathItems{}
+		err := tc.decode(&paths)
+		if tc.expected == nil {
+			assert.NotNil(r.T(), err, failMsg, i)
+			continue
+		}
+		assert.Nil(r.T(), err, failMsg, i)
+		assert.Equal(r.T(), tc.expected, paths.Keys(), failMsg, i)
+	}
+}
+
+func BenchmarkUnmarshalJSON(b *testing.B) {
+	doc := &OpenAPI{
+		OpenAPI: "3.0.0",
+		Info:    Info{Title: "Petstore", Version: "1.0.0"},
+	}
+	for i := 0; i < 200; i++ {
+		doc.Paths.PathItems.Set("/pets"+string(rune('a'+i%26))+string(rune('a'+i/26)), &PathItem{
+			Get: &Operation{
+				Parameters: []*Parameter{{Name: "limit", In: "query", Header: Header{Schema: &Schema{Type: "integer"}}}},
+				Responses: NewResponses(map[string]*Response{"200": {
+					Description: "Pets.",
+					Content: map[string]*MediaType{"application/json": {Schema: &Schema{
+						Type:       "object",
+						Properties: NewProperties(map[string]*Schema{"id": {Type: "integer"}, "name": {Type: "string"}}),
+					}}},
+				}}),
+			},
+		})
+	}
+	data, err := json.Marshal(doc)
+	if err != nil {
+		b.Fatal(err)
+	}
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if err := json.Unmarshal(data, &OpenAPI{}); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func TestCodecSuite(t *testing.T) {
+	suite.Run(t, new(CodecSuite))
+}
@@ -0,0 +1,106 @@
+package oas
+
+import (
+	"strconv"
+
+	"github.com/pkg/errors"
+	"gopkg.in/yaml.v3"
+)
+
+// Comment describes the YAML comments attached to a node of a document.
+type Comment struct {
+	// Head describes the comment lines preceding the node.
+	Head string `json:"head,omitempty" yaml:"head,omitempty"`
+
+	// Line describes the comment following the node on the same line.
+	Line string `json:"line,omitempty" yaml:"line,omitempty"`
+
+	// Foot describes the comment lines following the node.
+	Foot string `json:"foot,omitempty" yaml:"foot,omitempty"`
+}
+
+// Comments maps the JSON Pointers of the nodes of a YAML document to their
+// comments, which decoding into the document model discards. Comments read
+// before a transformation can be attached again when the document is saved,
+// so spec-as-code repositories keep their commentary.
+type Comments map[string]*Comment
+
+// ReadComments returns the comments of the YAML document data. The comments
+// of the document itself are recorded under the empty pointer.
+func ReadComments(data []byte) (Comments, error) {
+	node := &yaml.Node{}
+	if err := yaml.Unmarshal(data, node); err != nil {
+		return nil, errors.WithStack(err)
+	}
+	comments := Comments{}
+	visitComments(node, "", func(ptr string, key *yaml.Node, value *yaml.Node) {
+		comment := &Comment{Head: key.HeadComment, Line: key.LineComment, Foot: key.FootComment}
+		if value != nil && value.LineComment != "" {
+			comment.Line = value.LineComment
+		}
+		if *comment != (Comment{}) {
+			comments[ptr] = comment
+		}
+	})
+	return comments, nil
+}
+
+// Apply attaches the comments to the nodes of the YAML document data, such
+// as an encoded document, and returns the commented
+// document. Comments of nodes the data no longer holds are dropped.
+func (r Comments) Apply(data []byte) ([]byte, error) {
+	node := &yaml.Node{}
+	if err := yaml.Unmarshal(data, node); err != nil {
+		return nil, errors.WithStack(err)
+	}
+	visitComments(node, "", func(ptr string, key *yaml.Node, value *yaml.Node) {
+		comment := r[ptr]
+		if comment == nil {
+			return
+		}
+		key.HeadComment, key.FootComment = comment.Head, comment.Foot
+		if value != nil && value.Kind == yaml.ScalarNode {
+			value.LineComment = comment.Line
+		} else {
+			key.LineComment = comment.Line
+		}
+	})
+
+	return marshalYAML(node)
+}
+
+// MarshalYAMLComments returns the YAML encoding of the document with the
+// comments attached.
+func (r OpenAPI) MarshalYAMLComments(comments Comments) ([]byte, error) {
+	rbytes, err := marshalYAML(r)
+	if err != nil {
+		return nil, err
+	}
+	return comments.Apply(rbytes)
+}
+
+// visitComments calls fn with the pointer of every node of the YAML tree,
+// the node holding its comments (the key of mapping entries) and the value
+// node, nil for the document itself.
+func visitComments(node *yaml.Node, ptr string, fn func(ptr string, key *yaml.Node, value *yaml.Node)) {
+	switch node.Kind {
+	case yaml.DocumentNode:
+		fn(ptr, node, nil)
+		for _, child := range node.Content {
+			visitComments(child, ptr, fn)
+		}
+	case yaml.MappingNode:
+		for i := 0; i+1 < len(node.Content); i += 2 {
+			key, value := node.Content[i], node.Content[i+1]
+			child := join(ptr, key.Value)
+			fn(child, key, value)
+			visitComments(value, child, fn)
+		}
+	case yaml.SequenceNode:
+		for i, item := range node.Content {
+			child := join(ptr, strconv.Itoa(i))
+			fn(child, item, item)
+			visitComments(item, child, fn)
+		}
+	}
+}
@@ -0,0 +1,73 @@
+package oas
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+	"gopkg.in/yaml.v3"
+)
+
+type CommentsSuite struct {
+	suite.Suite
+}
+
+func (r *CommentsSuite) source() []byte {
+	return []byte(`# Petstore API.
+
+openapi: 3.0.0 # version
+info:
+  # Shown in the portal.
+  title: Petstore
+  version: 1.0.0
+paths:
+  /pets: # collection
+    get:
+      tags:
+        - pets # primary
+      responses:
+        "200":
+          description: Pets.
+`)
+}
+
+func (r *CommentsSuite) TestReadComments() {
+	comments, err := ReadComments(r.source())
+	assert.Nil(r.T(), err)
+	assert.Equal(r.T(), Comments{
+		"":                         {Head: "# Petstore API."},
+		"/openapi":                 {Line: "# version"},
+		"/info/title":              {Head: "# Shown in the portal."},
+		"/paths/~1pets":            {Line: "# collection"},
+		"/paths/~1pets/get/tags/0": {Line: "# primary"},
+	}, comments)
+
+	_, err = ReadComments([]byte("openapi: [3.0.0"))
+	assert.NotNil(r.T(), err)
+}
+
+func (r *CommentsSuite) TestMarshalYAMLComments() {
+	comments, err := ReadComments(r.source())
+	assert.Nil(r.T(), err)
+
+	doc := &OpenAPI{}
+	assert.Nil(r.T(), yaml.Unmarshal(r.source(), doc))
+	doc.Info.Title = "Pet Store"
+	doc.Paths.PathItems.Delete("/pets")
+
+	rbytes, err := doc.MarshalYAMLComments(comments)
+	assert.Nil(r.T(), err)
+	assert.Equal(r.T(), `# Petstore API.
+
+info:
+  # Shown in the portal.
+  title: Pet Store
+  version: 1.0.0
+openapi: 3.0.0 # version
+paths: {}
+`, string(rbytes))
+}
+
+func TestCommentsSuite(t *testing.T) {
+	suite.Run(t, new(CommentsSuite))
+}
@@ -0,0 +1,123 @@
+package oas
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// Rules reported by AuditComplexity.
+const (
+	RuleSchemaTooDeep      = "schema-too-deep"
+	RuleSchemaTooLarge     = "schema-too-large"
+	RuleSchemaTooComposite = "schema-too-composite"
+)
+
+// SchemaComplexity describes the size of a schema declared in a document,
+// counting its inline subschemas but not the schemas it references.
+type SchemaComplexity struct {
+	// Pointer describes the JSON Pointer of the schema.
+	Pointer string `json:"pointer" yaml:"pointer"`
+
+	// Depth describes the number of nested levels of inline schemas, 1 for
+	// a schema without inline subschemas.
+	Depth int `json:"depth" yaml:"depth"`
+
+	// Properties describes the number of properties declared by the schema
+	// and its inline subschemas.
+	Properties int `json:"properties" yaml:"properties"`
+
+	// FanOut describes the largest number of subschemas a single allOf,
+	// anyOf and oneOf combination of the schema holds.
+	FanOut int `json:"fanOut" yaml:"fanOut"`
+}
+
+// ComplexityThresholds describes the maximum complexity AuditComplexity
+// accepts. A zero value leaves the respective metric unchecked.
+type ComplexityThresholds struct {
+	// MaxDepth describes the maximum depth of a schema.
+	MaxDepth int
+
+	// MaxProperties describes the maximum number of properties of a schema.
+	MaxProperties int
+
+	// MaxFanOut describes the maximum composition fan-out of a schema.
+	MaxFanOut int
+}
+
+// SchemaComplexities returns the complexity of the component schemas and of
+// the schemas declared inline by parameters, headers, request bodies and
+// responses, sorted by pointer. Schemas which only reference another schema
+// are omitted.
+func (r OpenAPI) SchemaComplexities() []*SchemaComplexity {
+	complexities := make([]*SchemaComplexity, 0)
+	root := ""
+	_ = walk(&r, func(ptr string, node interface{}) error {
+		schema, ok := node.(*Schema)
+		if !ok || (root != "" && strings.HasPrefix(ptr, root+"/")) {
+			return nil
+		}
+		root = ptr
+		if schema.Ref == "" {
+			complexity := &SchemaComplexity{Pointer: ptr}
+			measureSchema(complexity, schema, 1)
+			complexities = append(complexities, complexity)
+		}
+		return nil
+	})
+	sort.Slice(complexities, func(i, j int) bool {
+		return complexities[i].Pointer < complexities[j].Pointer
+	})
+	return complexities
+}
+
+// measureSchema accumulates the metrics of the schema found at the depth
+// into the complexity. References are not followed, referencing a schema is
+// how a complex schema is decomposed.
+func measureSchema(complexity *SchemaComplexity, schema *Schema, depth int) {
+	if schema == nil || schema.Ref != "" {
+		return
+	}
+	if depth > complexity.Depth {
+		complexity.Depth = depth
+	}
+	complexity.Properties += schema.Properties.Len()
+	if fanOut := len(schema.AllOf) + len(schema.AnyOf) + len(schema.OneOf); fanOut > complexity.FanOut {
+		complexity.FanOut = fanOut
+	}
+
+	children := []*Schema{schema.Items, schema.AdditionalProperties, schema.Not}
+	for _, key := range sortedKeys(schema.Properties) {
+		children = append(children, schema.Properties.Get(key))
+	}
+	children = append(children, schema.AllOf...)
+	children = append(children, schema.AnyOf...)
+	children = append(children, schema.OneOf...)
+	for _, child := range children {
+		measureSchema(complexity, child, depth+1)
+	}
+}
+
+// AuditComplexity reports the schemas whose complexity exceeds the
+// thresholds, nudging authors to decompose large schemas into referenced
+// components.
+func (r OpenAPI) AuditComplexity(thresholds ComplexityThresholds) []*Finding {
+	findings := make([]*Finding, 0)
+	check := func(ptr string, rule string, value int, max int, metric string) {
+		if max > 0 && value > max {
+			findings = append(findings, &Finding{
+				Pointer:  ptr,
+				Rule:     rule,
+				Severity: SeverityError,
+				Message:  fmt.Sprintf("schema %s %d exceeds the maximum of %d", metric, value, max),
+			})
+		}
+	}
+	for _, complexity := range r.SchemaComplexities() {
+		check(complexity.Pointer, RuleSchemaTooDeep, complexity.Depth, thresholds.MaxDepth, "depth")
+		check(complexity.Pointer, RuleSchemaTooLarge, complexity.Properties, thresholds.MaxProperties, "property count")
+		check(complexity.Pointer, RuleSchemaTooComposite, complexity.FanOut, thresholds.MaxFanOut, "composition fan-out")
+	}
+	sortFindings(findings)
+	return findings
+}
@@ -0,0 +1,121 @@
+package oas
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+)
+
+type ComplexitySuite struct {
+	suite.Suite
+}
+
+// complexityPatch is the patch of the petstore fixture the suite measures.
+const complexityPatch = `
+paths:
+  /pets:
+    post:
+      responses:
+        "201":
+          content:
+            application/json:
+              schema:
+                type: array
+                items:
+                  type: object
+                  properties:
+                    id:
+                      type: integer
+components:
+  schemas:
+    Pet:
+      properties:
+        tag: null
+        owner:
+          $ref: "#/components/schemas/Owner"
+        address:
+          type: object
+          properties:
+            street:
+              type: string
+            city:
+              type: string
+    Owner:
+      oneOf:
+        - $ref: "#/components/schemas/Person"
+        - $ref: "#/components/schemas/Company"
+        - type: object
+          properties:
+            name:
+              type: string
+`
+
+func (r *ComplexitySuite) TestSchemaComplexities() {
+	assert.Equal(r.T(), []*SchemaComplexity{
+		{Pointer: "/components/schemas/Owner", Depth: 3, Properties: 1, FanOut: 3},
+		{Pointer: "/components/schemas/Pet", Depth: 3, Properties: 5},
+		{Pointer: "/paths/~1pets/get/responses/200/content/application~1json/schema", Depth: 1},
+		{Pointer: "/paths/~1pets/post/responses/201/content/application~1json/schema", Depth: 3, Properties: 1},
+		{Pointer: "/paths/~1pets~1{petId}/parameters/0/schema", Depth: 1},
+	}, petstore(r.T(), complexityPatch).SchemaComplexities())
+}
+
+func (r *ComplexitySuite) TestAuditComplexity() {
+	testCases := []struct {
+		thresholds ComplexityThresholds
+		expected   []*Finding
+	}{
+		{ComplexityThresholds{}, []*Finding{}},
+		{ComplexityThresholds{MaxDepth: 3, MaxProperties: 5, MaxFanOut: 3}, []*Finding{}},
+		{
+			ComplexityThresholds{MaxProperties: 4, MaxFanOut: 2},
+			[]*Finding{
+				{
+					Pointer:  "/components/schemas/Owner",
+					Rule:     RuleSchemaTooComposite,
+					Severity: SeverityError,
+					Message:  "schema composition fan-out 3 exceeds the maximum of 2",
+				},
+				{
+					Pointer:  "/components/schemas/Pet",
+					Rule:     RuleSchemaTooLarge,
+					Severity: SeverityError,
+					Message:  "schema property count 5 exceeds the maximum of 4",
+				},
+			},
+		},
+		{
+			ComplexityThresholds{MaxDepth: 2},
+			[]*Finding{
+				{
+					Pointer:  "/components/schemas/Owner",
+					Rule:     RuleSchemaTooDeep,
+					Severity: SeverityError,
+					Message:  "schema depth 3 exceeds the maximum of 2",
+				},
+				{
+					Pointer:  "/components/schemas/Pet",
+					Rule:     RuleSchemaTooDeep,
+					Severity: SeverityError,
+					Message:  "schema depth 3 exceeds the maximum of 2",
+				},
+				{
+					Pointer:  "/paths/~1pets/post/responses/201/content/application~1json/schema",
+					Rule:     RuleSchemaTooDeep,
+					Severity: SeverityError,
+					Message:  "schema depth 3 exceeds the maximum of 2",
+				},
+			},
+		},
+	}
+
+	for i, testCase := range testCases {
+		failMsg := "test case %d failed"
+		assert.Equal(r.T(), testCase.expected, petstore(r.T(), complexityPatch).AuditComplexity(testCase.thresholds), failMsg, i)
+	}
+}
+
+func TestComplexitySuite(t *testing.T) {
+	suite.Run(t, new(ComplexitySuite))
+}
@@ -4,7 +4,7 @@ import (
 	"encoding/json"
 
 	"github.com/pkg/errors"
-	"gopkg.in/yaml.v2"
+	"gopkg.in/yaml.v3"
 )
 
 // Components holds a set of reusable objects for different aspects of the OAS.
@@ -69,22 +69,7 @@ func (r Components) MarshalJSON() ([]byte, error) {
 
 // UnmarshalJSON parses the JSON-encoded data and stores the result.
 func (r *Components) UnmarshalJSON(data []byte) error {
-	return r.UnmarshalYAML(func(in interface{}) error {
-		obj := make(map[string]interface{})
-		if err := json.Unmarshal(data, &obj); err != nil {
-			return errors.WithStack(err)
-		}
-
-		rbytes, err := yaml.Marshal(obj)
-		if err != nil {
-			return errors.WithStack(err)
-		}
-
-		if err := yaml.Unmarshal(rbytes, in); err != nil {
-			return errors.WithStack(err)
-		}
-		return nil
-	})
+	return decodeJSON(data, r)
 }
 
 // MarshalYAML returns the YAML encoding.
@@ -135,116 +120,86 @@ func (r Components) MarshalYAML() (interface{}, error) {
 }
 
 // UnmarshalYAML parses the YAML-encoded data and stores the result.
-func (r *Components) UnmarshalYAML(unmarshal func(interface{}) error) error {
+func (r *Components) UnmarshalYAML(node *yaml.Node) error {
+	return decodeYAML(node, r)
+}
+
+// unmarshalValue stores the result of the unmarshal function, which decodes
+// either JSON or YAML data.
+func (r *Components) unmarshalValue(d *valueDecoder, unmarshal func(interface{}) error) error {
 	obj := make(map[string]interface{})
 	if err := unmarshal(&obj); err != nil {
 		return errors.WithStack(err)
 	}
 
-	if value, ok := obj["schemas"]; ok {
-		rbytes, err := yaml.Marshal(value)
-		if err != nil {
-			return errors.WithStack(err)
-		}
+	if raw, ok := obj["schemas"]; ok {
 		value := map[string]*Schema{}
-		if err := yaml.Unmarshal(rbytes, &value); err != nil {
-			return errors.WithStack(err)
+		if err := d.decodeMember("schemas", raw, &value); err != nil {
+			return err
 		}
 		r.Schemas = value
 	}
 
-	if value, ok := obj["responses"]; ok {
-		rbytes, err := yaml.Marshal(value)
-		if err != nil {
-			return errors.WithStack(err)
-		}
+	if raw, ok := obj["responses"]; ok {
 		value := map[string]*Response{}
-		if err := yaml.Unmarshal(rbytes, &value); err != nil {
-			return errors.WithStack(err)
+		if err := d.decodeMember("responses", raw, &value); err != nil {
+			return err
 		}
 		r.Responses = value
 	}
 
-	if value, ok := obj["parameters"]; ok {
-		rbytes, err := yaml.Marshal(value)
-		if err != nil {
-			return errors.WithStack(err)
-		}
+	if raw, ok := obj["parameters"]; ok {
 		value := map[string]*Parameter{}
-		if err := yaml.Unmarshal(rbytes, &value); err != nil {
-			return errors.WithStack(err)
+		if err := d.decodeMember("parameters", raw, &value); err != nil {
+			return err
 		}
 		r.Parameters = value
 	}
 
-	if value, ok := obj["examples"]; ok {
-		rbytes, err := yaml.Marshal(value)
-		if err != nil {
-			return errors.WithStack(err)
-		}
+	if raw, ok := obj["examples"]; ok {
 		value := map[string]*Example{}
-		if err := yaml.Unmarshal(rbytes, &value); err != nil {
-			return errors.WithStack(err)
+		if err := d.decodeMember("examples", raw, &value); err != nil {
+			return err
 		}
 		r.Examples = value
 	}
 
-	if value, ok := obj["requestBodies"]; ok {
-		rbytes, err := yaml.Marshal(value)
-		if err != nil {
-			return errors.WithStack(err)
-		}
+	if raw, ok := obj["requestBodies"]; ok {
 		value := map[string]*RequestBody{}
-		if err := yaml.Unmarshal(rbytes, &value); err != nil {
-			return errors.WithStack(err)
+		if err := d.decodeMember("requestBodies", raw, &value); err != nil {
+			return err
 		}
 		r.RequestBodies = value
 	}
 
-	if value, ok := obj["headers"]; ok {
-		rbytes, err := yaml.Marshal(value)
-		if err != nil {
-			return errors.WithStack(err)
-		}
+	if raw, ok := obj["headers"]; ok {
 		value := map[string]*Header{}
-		if err := yaml.Unmarshal(rbytes, &value); err != nil {
-			return errors.WithStack(err)
+		if err := d.decodeMember("headers", raw, &value); err != nil {
+			return err
 		}
 		r.Headers = value
 	}
 
-	if value, ok := obj["securitySchemes"]; ok {
-		rbytes, err := yaml.Marshal(value)
-		if err != nil {
-			return errors.WithStack(err)
-		}
+	if raw, ok := obj["securitySchemes"]; ok {
 		value := map[string]*SecurityScheme{}
-		if err := yaml.Unmarshal(rbytes, &value); err != nil {
-			return errors.WithStack(err)
+		if err := d.decodeMember("securitySchemes", raw, &value); err != nil {
+			return err
 		}
 		r.SecuritySchemes = value
 	}
 
-	if value, ok := obj["links"]; ok {
-		rbytes, err := yaml.Marshal(value)
-		if err != nil {
-			return errors.WithStack(err)
-		}
+	if raw, ok := obj["links"]; ok {
 		value := map[string]*Link{}
-		if err := yaml.Unmarshal(rbytes, &value); err != nil {
-			return errors.WithStack(err)
+		if err := d.decodeMember("links", raw, &value); err != nil {
+			return err
 		}
 		r.Links = value
 	}
 
-	if value, ok := obj["callbacks"]; ok {
-		rbytes, err := yaml.Marshal(value)
-		if err != nil {
-			return errors.WithStack(err)
-		}
+	if raw, ok := obj["callbacks"]; ok {
 		value := map[string]*Callback{}
-		if err := yaml.Unmarshal(rbytes, &value); err != nil {
-			return errors.WithStack(err)
+		if err := d.decodeMember("callbacks", raw, &value); err != nil {
+			return err
 		}
 		r.Callbacks = value
 	}
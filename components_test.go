@@ -7,7 +7,7 @@ import (
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/suite"
-	"gopkg.in/yaml.v2"
+	"gopkg.in/yaml.v3"
 )
 
 type ComponentsSuite struct {
@@ -25,7 +25,7 @@ func (r *ComponentsSuite) TestComponents() {
 				Schemas: map[string]*Schema{
 					"GeneralError": {
 						Type: "object",
-						Properties: map[string]*Schema{
+						Properties: NewProperties(map[string]*Schema{
 							"code": {
 								Type:   "integer",
 								Format: "int32",
@@ -33,11 +33,11 @@ func (r *ComponentsSuite) TestComponents() {
 							"message": {
 								Type: "string",
 							},
-						},
+						}),
 					},
 					"Category": {
 						Type: "object",
-						Properties: map[string]*Schema{
+						Properties: NewProperties(map[string]*Schema{
 							"id": {
 								Type:   "integer",
 								Format: "int64",
@@ -45,11 +45,11 @@ func (r *ComponentsSuite) TestComponents() {
 							"name": {
 								Type: "string",
 							},
-						},
+						}),
 					},
 					"Tag": {
 						Type: "object",
-						Properties: map[string]*Schema{
+						Properties: NewProperties(map[string]*Schema{
 							"id": {
 								Type:   "integer",
 								Format: "int64",
@@ -57,7 +57,7 @@ func (r *ComponentsSuite) TestComponents() {
 							"name": {
 								Type: "string",
 							},
-						},
+						}),
 					},
 				},
 				Parameters: map[string]*Parameter{
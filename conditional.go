@@ -0,0 +1,249 @@
+package oas
+
+import (
+	"strings"
+)
+
+// Rules reported by AuditConditionalRequests.
+const (
+	RuleNotModifiedWithoutValidator = "not-modified-without-validator"
+	RuleNotModifiedWithoutCondition = "not-modified-without-condition"
+	RuleNotModifiedOnUnsafeMethod   = "not-modified-on-unsafe-method"
+	RuleValidatorWithoutNotModified = "validator-without-not-modified"
+)
+
+// ConditionalOptions describes the validators DeclareConditionalRequests
+// declares.
+type ConditionalOptions struct {
+	// ETag declares entity tags through the ETag response header and the
+	// If-None-Match request header.
+	ETag bool
+
+	// LastModified declares modification dates through the Last-Modified
+	// response header and the If-Modified-Since request header.
+	LastModified bool
+}
+
+// conditionalHeader pairs a validator response header with the request
+// header which makes a request conditional on it.
+type conditionalHeader struct {
+	response  string
+	component string
+	request   string
+	parameter string
+	header    *Header
+}
+
+// conditionalHeaders returns the validators selected by the options.
+func (r ConditionalOptions) conditionalHeaders() []conditionalHeader {
+	headers := make([]conditionalHeader, 0, 2)
+	if r.ETag {
+		headers = append(headers, conditionalHeader{
+			response:  "ETag",
+			component: "ETag",
+			request:   "If-None-Match",
+			parameter: "IfNoneMatch",
+			header: &Header{
+				Description: "Entity tag of the current representation.",
+				Schema:      &Schema{Type: "string"},
+			},
+		})
+	}
+	if r.LastModified {
+		headers = append(headers, conditionalHeader{
+			response:  "Last-Modified",
+			component: "LastModified",
+			request:   "If-Modified-Since",
+			parameter: "IfModifiedSince",
+			header: &Header{
+				Description: "Date the representation was last modified.",
+				Schema:      &Schema{Type: "string"},
+			},
+		})
+	}
+	return headers
+}
+
+// DeclareConditionalRequests documents conditional GET support on every GET
+// operation with a 200 response. The validator headers are added to the 200
+// response, the matching conditional request headers to the parameters and
+// a 304 Not Modified response to the responses. The definitions are shared
+// through the "ETag", "LastModified", "IfNoneMatch", "IfModifiedSince" and
+// "NotModified" components, existing components of the same name are
+// reused. The pointers of the modified operations are returned in sorted
+// order.
+func (r *OpenAPI) DeclareConditionalRequests(opts ConditionalOptions) []string {
+	modified := make([]string, 0)
+	headers := opts.conditionalHeaders()
+	if len(headers) == 0 {
+		return modified
+	}
+
+	declared := false
+	declare := func() {
+		if declared {
+			return
+		}
+		declared = true
+		if r.Components == nil {
+			r.Components = &Components{}
+		}
+		c := r.Components
+		if c.Headers == nil {
+			c.Headers = map[string]*Header{}
+		}
+		if c.Parameters == nil {
+			c.Parameters = map[string]*Parameter{}
+		}
+		if c.Responses == nil {
+			c.Responses = map[string]*Response{}
+		}
+		notModified := &Response{
+			Description: "Not Modified. The representation has not changed since the version the client holds.",
+			Headers:     map[string]*Header{},
+		}
+		for _, header := range headers {
+			if c.Headers[header.component] == nil {
+				c.Headers[header.component] = header.header
+			}
+			if c.Parameters[header.parameter] == nil {
+				c.Parameters[header.parameter] = &Parameter{
+					Name: header.request,
+					In:   "header",
+					Header: Header{
+						Description: "Makes the request conditional on the " + header.response + " validator.",
+						Schema:      &Schema{Type: "string"},
+					},
+				}
+			}
+			notModified.Headers[header.response] = &Header{Ref: "#/components/headers/" + header.component}
+		}
+		if c.Responses["NotModified"] == nil {
+			c.Responses["NotModified"] = notModified
+		}
+	}
+
+	for _, op := range r.Paths.operations() {
+		if op.method != "get" || op.operation.Responses.Get("200") == nil {
+			continue
+		}
+		_, response := NewResolver(r).response("", op.operation.Responses.Get("200"))
+		if response == nil {
+			continue
+		}
+		declare()
+
+		changed := false
+		for _, header := range headers {
+			if !hasResponseHeader(response, header.response) {
+				if response.Headers == nil {
+					response.Headers = map[string]*Header{}
+				}
+				response.Headers[header.response] = &Header{Ref: "#/components/headers/" + header.component}
+				changed = true
+			}
+			if !r.hasHeaderParameter(op.item, op.operation, header.request) {
+				op.operation.Parameters = append(op.operation.Parameters, &Parameter{
+					Header: Header{Ref: "#/components/parameters/" + header.parameter},
+				})
+				changed = true
+			}
+		}
+		if op.operation.Responses.Get("304") == nil {
+			op.operation.Responses.Set("304", &Response{Ref: "#/components/responses/NotModified"})
+			changed = true
+		}
+		if changed {
+			modified = append(modified, join("/paths", op.path, op.method))
+		}
+	}
+	return modified
+}
+
+// AuditConditionalRequests checks that caching semantics are declared
+// consistently. It flags 304 responses of operations whose successful
+// responses declare neither an ETag nor a Last-Modified header, 304
+// responses of operations accepting neither If-None-Match nor
+// If-Modified-Since, 304 responses on methods other than GET and HEAD, and
+// GET operations returning validators without declaring a 304 response.
+func (r OpenAPI) AuditConditionalRequests() []*Finding {
+	findings := make([]*Finding, 0)
+	for _, op := range r.Paths.operations() {
+		ptr := join("/paths", op.path, op.method)
+		_, notModified := op.operation.Responses.Lookup("304")
+
+		validators := false
+		for _, code := range sortedKeys(op.operation.Responses) {
+			if !strings.HasPrefix(code, "2") {
+				continue
+			}
+			_, response := NewResolver(&r).response("", op.operation.Responses.Get(code))
+			if response != nil && (hasResponseHeader(response, "ETag") || hasResponseHeader(response, "Last-Modified")) {
+				validators = true
+			}
+		}
+		conditions := r.hasHeaderParameter(op.item, op.operation, "If-None-Match") ||
+			r.hasHeaderParameter(op.item, op.operation, "If-Modified-Since")
+
+		switch {
+		case notModified && op.method != "get" && op.method != "head":
+			findings = append(findings, &Finding{
+				Pointer:  join(ptr, "responses", "304"),
+				Rule:     RuleNotModifiedOnUnsafeMethod,
+				Severity: SeverityWarning,
+				Message:  "304 Not Modified is only meaningful for GET and HEAD requests",
+			})
+		case notModified:
+			if !validators {
+				findings = append(findings, &Finding{
+					Pointer:  join(ptr, "responses", "304"),
+					Rule:     RuleNotModifiedWithoutValidator,
+					Severity: SeverityWarning,
+					Message:  "304 response is declared but no successful response declares an ETag or Last-Modified header",
+				})
+			}
+			if !conditions {
+				findings = append(findings, &Finding{
+					Pointer:  join(ptr, "responses", "304"),
+					Rule:     RuleNotModifiedWithoutCondition,
+					Severity: SeverityWarning,
+					Message:  "304 response is declared but neither If-None-Match nor If-Modified-Since is accepted",
+				})
+			}
+		case validators && op.method == "get":
+			findings = append(findings, &Finding{
+				Pointer:  join(ptr, "responses"),
+				Rule:     RuleValidatorWithoutNotModified,
+				Severity: SeverityInfo,
+				Message:  "operation returns cache validators but does not declare a 304 response",
+			})
+		}
+	}
+	sortFindings(findings)
+	return findings
+}
+
+// hasResponseHeader reports whether the response declares the header. Header
+// names are compared case-insensitively.
+func hasResponseHeader(response *Response, name string) bool {
+	for key := range response.Headers {
+		if strings.EqualFold(key, name) {
+			return true
+		}
+	}
+	return false
+}
+
+// hasHeaderParameter reports whether the operation or its path item declares
+// the request header. Header names are compared case-insensitively.
+func (r OpenAPI) hasHeaderParameter(item *PathItem, operation *Operation, name string) bool {
+	for _, values := range [][]*Parameter{item.Parameters, operation.Parameters} {
+		for _, value := range values {
+			_, param := NewResolver(&r).parameter("", value)
+			if param != nil && param.In == "header" && strings.EqualFold(param.Name, name) {
+				return true
+			}
+		}
+	}
+	return false
+}
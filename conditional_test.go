@@ -0,0 +1,106 @@
+package oas
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+)
+
+type ConditionalSuite struct {
+	suite.Suite
+}
+
+func (r *ConditionalSuite) TestDeclareConditionalRequests() {
+	doc := &OpenAPI{
+		OpenAPI: "3.0.0",
+		Info:    Info{Title: "Petstore", Version: "1.0.0"},
+		Paths: Paths{
+			PathItems: NewPathItems(map[string]*PathItem{
+				"/pets": {
+					Get: &Operation{
+						Responses: NewResponses(map[string]*Response{"200": {Ref: "#/components/responses/Pets"}}),
+					},
+					Post: &Operation{
+						Responses: NewResponses(map[string]*Response{"201": {Description: "Created."}}),
+					},
+				},
+				"/pets/{petId}": {
+					Get: &Operation{
+						Parameters: []*Parameter{{Name: "if-none-match", In: "header"}},
+						Responses: NewResponses(map[string]*Response{
+							"200": {Description: "A pet.", Headers: map[string]*Header{"etag": {}}},
+							"304": {Description: "Not modified."},
+						}),
+					},
+				},
+			}),
+		},
+		Components: &Components{
+			Responses: map[string]*Response{"Pets": {Description: "Pets."}},
+		},
+	}
+
+	modified := doc.DeclareConditionalRequests(ConditionalOptions{ETag: true})
+	assert.Equal(r.T(), []string{"/paths/~1pets/get"}, modified)
+	assert.Equal(r.T(), map[string]*Header{
+		"ETag": {Ref: "#/components/headers/ETag"},
+	}, doc.Components.Responses["Pets"].Headers)
+	assert.Equal(r.T(), []*Parameter{
+		{Header: Header{Ref: "#/components/parameters/IfNoneMatch"}},
+	}, doc.Paths.PathItems.Get("/pets").Get.Parameters)
+	assert.Equal(r.T(), &Response{Ref: "#/components/responses/NotModified"}, doc.Paths.PathItems.Get("/pets").Get.Responses.Get("304"))
+	assert.Contains(r.T(), doc.Components.Headers, "ETag")
+	assert.Equal(r.T(), "If-None-Match", doc.Components.Parameters["IfNoneMatch"].Name)
+	assert.Contains(r.T(), doc.Components.Responses["NotModified"].Headers, "ETag")
+	assert.Len(r.T(), doc.Paths.PathItems.Get("/pets/{petId}").Get.Parameters, 1)
+	assert.Empty(r.T(), doc.AuditConditionalRequests())
+
+	assert.Empty(r.T(), doc.DeclareConditionalRequests(ConditionalOptions{ETag: true}))
+	assert.Empty(r.T(), doc.DeclareConditionalRequests(ConditionalOptions{}))
+}
+
+func (r *ConditionalSuite) TestAuditConditionalRequests() {
+	doc := OpenAPI{
+		OpenAPI: "3.0.0",
+		Info:    Info{Title: "Petstore", Version: "1.0.0"},
+		Paths: Paths{
+			PathItems: NewPathItems(map[string]*PathItem{
+				"/pets": {
+					Get: &Operation{
+						Responses: NewResponses(map[string]*Response{
+							"200": {Description: "Pets.", Headers: map[string]*Header{"Last-Modified": {}}},
+						}),
+					},
+					Put: &Operation{
+						Responses: NewResponses(map[string]*Response{"304": {Description: "Not modified."}}),
+					},
+				},
+				"/owners": {
+					Get: &Operation{
+						Responses: NewResponses(map[string]*Response{
+							"200": {Description: "Owners."},
+							"304": {Description: "Not modified."},
+						}),
+					},
+				},
+			}),
+		},
+	}
+
+	findings := doc.AuditConditionalRequests()
+	rules := make([]string, 0, len(findings))
+	for _, finding := range findings {
+		rules = append(rules, finding.Pointer+" "+finding.Rule)
+	}
+	assert.Equal(r.T(), []string{
+		"/paths/~1owners/get/responses/304 " + RuleNotModifiedWithoutCondition,
+		"/paths/~1owners/get/responses/304 " + RuleNotModifiedWithoutValidator,
+		"/paths/~1pets/get/responses " + RuleValidatorWithoutNotModified,
+		"/paths/~1pets/put/responses/304 " + RuleNotModifiedOnUnsafeMethod,
+	}, rules)
+}
+
+func TestConditionalSuite(t *testing.T) {
+	suite.Run(t, new(ConditionalSuite))
+}
@@ -0,0 +1,55 @@
+package oas
+
+import (
+	"context"
+	"net/http"
+)
+
+// contextKey is the type of the keys the package stores values under in a
+// context, so they cannot collide with keys of other packages.
+type contextKey int
+
+const (
+	routeContextKey contextKey = iota
+	idempotencyKeyContextKey
+)
+
+// NewRouteContext returns a copy of the context carrying the route.
+func NewRouteContext(ctx context.Context, route *Route) context.Context {
+	return context.WithValue(ctx, routeContextKey, route)
+}
+
+// RouteFromContext returns the route stored in the context by the router
+// middleware, if any.
+func RouteFromContext(ctx context.Context) (*Route, bool) {
+	route, ok := ctx.Value(routeContextKey).(*Route)
+	return route, ok && route != nil
+}
+
+// OperationFromContext returns the operation the request was matched to, or
+// nil if the context carries no route.
+func OperationFromContext(ctx context.Context) *Operation {
+	if route, ok := RouteFromContext(ctx); ok {
+		return route.Operation
+	}
+	return nil
+}
+
+// BodyFromContext returns the decoded JSON request body, or nil if the
+// context carries no route or the request had no JSON body.
+func BodyFromContext(ctx context.Context) interface{} {
+	if route, ok := RouteFromContext(ctx); ok {
+		return route.Body
+	}
+	return nil
+}
+
+// PathParam returns the unescaped value of the named path parameter of the
+// request, or an empty string if the request carries no route or the path
+// template declares no such parameter.
+func PathParam(req *http.Request, name string) string {
+	if route, ok := RouteFromContext(req.Context()); ok {
+		return route.PathParams[name]
+	}
+	return ""
+}
@@ -0,0 +1,299 @@
+package oas
+
+import (
+	"net/http"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/pkg/errors"
+)
+
+// simpleContentTypes holds the media types browsers send without a preflight
+// request.
+var simpleContentTypes = map[string]bool{
+	"application/x-www-form-urlencoded": true,
+	"multipart/form-data":               true,
+	"text/plain":                        true,
+}
+
+// ErrCORSWildcardCredentials is returned for policies allowing any origin
+// together with credentials, which would grant every site credentialed
+// access.
+var ErrCORSWildcardCredentials = errors.New("allowing any origin with credentials is not permitted")
+
+// CORSRule describes the cross-origin access allowed for a path.
+type CORSRule struct {
+	// Methods describes the uppercase methods which may be used.
+	Methods []string `json:"methods,omitempty" yaml:"methods,omitempty"`
+
+	// Headers describes the request headers which may be sent.
+	Headers []string `json:"headers,omitempty" yaml:"headers,omitempty"`
+
+	// ExposedHeaders describes the response headers scripts may read.
+	ExposedHeaders []string `json:"exposedHeaders,omitempty" yaml:"exposedHeaders,omitempty"`
+}
+
+// CORSPolicy describes a Cross-Origin Resource Sharing configuration and
+// implements it as http middleware.
+type CORSPolicy struct {
+	// AllowedOrigins describes the origins allowed to access the API. The
+	// value "*" allows any origin and cannot be combined with
+	// AllowCredentials.
+	AllowedOrigins []string `json:"allowedOrigins,omitempty" yaml:"allowedOrigins,omitempty"`
+
+	// AllowCredentials describes whether requests may include credentials.
+	AllowCredentials bool `json:"allowCredentials,omitempty" yaml:"allowCredentials,omitempty"`
+
+	// MaxAge describes for how many seconds preflight results may be cached.
+	// Zero omits the header.
+	MaxAge int `json:"maxAge,omitempty" yaml:"maxAge,omitempty"`
+
+	// Paths describes the rules keyed by path template.
+	Paths map[string]*CORSRule `json:"paths,omitempty" yaml:"paths,omitempty"`
+
+	once     sync.Once
+	err      error
+	patterns map[string]*regexp.Regexp
+}
+
+// CORSPolicy derives a CORS policy from the documented surface, so the
+// policy stays in sync with the document. Every path allows the methods it
+// declares, the header parameters of its operations, the headers carrying
+// credentials of the security schemes its operations use and Content-Type
+// when a request body accepts a media type which requires a preflight
+// request. The response headers its operations declare are exposed.
+func (r OpenAPI) CORSPolicy(origins []string) *CORSPolicy {
+	policy := &CORSPolicy{
+		AllowedOrigins: origins,
+		Paths:          map[string]*CORSRule{},
+	}
+
+	for _, path := range sortedKeys(r.Paths.PathItems) {
+		item := r.Paths.PathItems.Get(path)
+		if item == nil {
+			continue
+		}
+		methods := newHeaderSet()
+		headers := newHeaderSet()
+		exposed := newHeaderSet()
+
+		for _, op := range item.operations() {
+			methods.add(strings.ToUpper(op.method))
+			for _, values := range [][]*Parameter{item.Parameters, op.operation.Parameters} {
+				for _, value := range values {
+					if _, param := NewResolver(&r).parameter("", value); param != nil && param.In == "header" {
+						headers.add(param.Name)
+					}
+				}
+			}
+
+			if _, body := NewResolver(&r).requestBody("", op.operation.RequestBody); body != nil {
+				for mediaType := range body.Content {
+					if !simpleContentTypes[strings.ToLower(mediaType)] {
+						headers.add("Content-Type")
+					}
+				}
+			}
+
+			requirements := r.Security
+			if op.operation.Security != nil {
+				requirements = op.operation.Security
+			}
+			for _, name := range requirementSchemes(requirements) {
+				if r.Components == nil || r.Components.SecuritySchemes[name] == nil {
+					continue
+				}
+				scheme := r.Components.SecuritySchemes[name]
+				switch {
+				case scheme.Type == "apiKey" && scheme.In == "header":
+					headers.add(scheme.Name)
+				case scheme.Type == "http", scheme.Type == "oauth2", scheme.Type == "openIdConnect":
+					headers.add("Authorization")
+				}
+			}
+
+			for _, code := range sortedKeys(op.operation.Responses) {
+				if _, response := NewResolver(&r).response("", op.operation.Responses.Get(code)); response != nil {
+					for name := range response.Headers {
+						exposed.add(name)
+					}
+				}
+			}
+		}
+
+		policy.Paths[path] = &CORSRule{
+			Methods:        methods.values(),
+			Headers:        headers.values(),
+			ExposedHeaders: exposed.values(),
+		}
+	}
+	return policy
+}
+
+// Validate returns ErrCORSWildcardCredentials if the policy allows any
+// origin together with credentials. Middleware refuses to serve such
+// policies, so checking at startup surfaces the mistake early.
+func (r *CORSPolicy) Validate() error {
+	if !r.AllowCredentials {
+		return nil
+	}
+	for _, allowed := range r.AllowedOrigins {
+		if allowed == "*" {
+			return errors.WithStack(ErrCORSWildcardCredentials)
+		}
+	}
+	return nil
+}
+
+// compile validates the policy and compiles the path templates of the
+// rules.
+func (r *CORSPolicy) compile() error {
+	r.once.Do(func() {
+		if r.err = r.Validate(); r.err != nil {
+			return
+		}
+		r.patterns = make(map[string]*regexp.Regexp, len(r.Paths))
+		for path := range r.Paths {
+			pattern, _, err := compileTemplate(path)
+			if err != nil {
+				r.err = err
+				return
+			}
+			r.patterns[path] = pattern
+		}
+	})
+	return r.err
+}
+
+// rule returns the union of the rules of every path template matching the
+// request path, or nil if none matches.
+func (r *CORSPolicy) rule(req *http.Request) *CORSRule {
+	methods, headers, exposed := newHeaderSet(), newHeaderSet(), newHeaderSet()
+	found := false
+	for path, pattern := range r.patterns {
+		if !pattern.MatchString(req.URL.EscapedPath()) || r.Paths[path] == nil {
+			continue
+		}
+		found = true
+		methods.add(r.Paths[path].Methods...)
+		headers.add(r.Paths[path].Headers...)
+		exposed.add(r.Paths[path].ExposedHeaders...)
+	}
+	if !found {
+		return nil
+	}
+	return &CORSRule{Methods: methods.values(), Headers: headers.values(), ExposedHeaders: exposed.values()}
+}
+
+// allowOrigin returns the value of the Access-Control-Allow-Origin header
+// for the origin, or an empty string if the origin is not allowed.
+func (r *CORSPolicy) allowOrigin(origin string) string {
+	for _, allowed := range r.AllowedOrigins {
+		switch {
+		case allowed == "*":
+			return "*"
+		case strings.EqualFold(allowed, origin):
+			return origin
+		}
+	}
+	return ""
+}
+
+// Middleware returns a handler implementing the policy. Preflight requests
+// for paths of the policy are answered directly, actual cross-origin
+// requests receive the CORS response headers before next is called.
+// Requests from origins which are not allowed are passed on without CORS
+// headers, so browsers block access to the response. Policies failing
+// Validate answer every request with 500.
+func (r *CORSPolicy) Middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		if err := r.compile(); err != nil {
+			http.Error(w, errors.Cause(err).Error(), http.StatusInternalServerError)
+			return
+		}
+
+		origin := req.Header.Get("Origin")
+		if origin == "" {
+			next.ServeHTTP(w, req)
+			return
+		}
+		w.Header().Add("Vary", "Origin")
+		rule := r.rule(req)
+		allowed := r.allowOrigin(origin)
+		if rule == nil || allowed == "" {
+			next.ServeHTTP(w, req)
+			return
+		}
+
+		requested := req.Header.Get("Access-Control-Request-Method")
+		if req.Method == http.MethodOptions && requested != "" {
+			w.Header().Add("Vary", "Access-Control-Request-Method")
+			w.Header().Add("Vary", "Access-Control-Request-Headers")
+			if newHeaderSet(rule.Methods...).contains(requested) {
+				w.Header().Set("Access-Control-Allow-Origin", allowed)
+				w.Header().Set("Access-Control-Allow-Methods", strings.Join(rule.Methods, ", "))
+				if len(rule.Headers) > 0 {
+					w.Header().Set("Access-Control-Allow-Headers", strings.Join(rule.Headers, ", "))
+				}
+				if r.AllowCredentials {
+					w.Header().Set("Access-Control-Allow-Credentials", "true")
+				}
+				if r.MaxAge > 0 {
+					w.Header().Set("Access-Control-Max-Age", strconv.Itoa(r.MaxAge))
+				}
+			}
+			w.WriteHeader(http.StatusNoContent)
+			return
+		}
+
+		if newHeaderSet(rule.Methods...).contains(req.Method) {
+			w.Header().Set("Access-Control-Allow-Origin", allowed)
+			if len(rule.ExposedHeaders) > 0 {
+				w.Header().Set("Access-Control-Expose-Headers", strings.Join(rule.ExposedHeaders, ", "))
+			}
+			if r.AllowCredentials {
+				w.Header().Set("Access-Control-Allow-Credentials", "true")
+			}
+		}
+		next.ServeHTTP(w, req)
+	})
+}
+
+// headerSet collects header names and methods case-insensitively while
+// preserving the spelling first added.
+type headerSet map[string]string
+
+// newHeaderSet returns a set holding the values.
+func newHeaderSet(values ...string) headerSet {
+	set := headerSet{}
+	set.add(values...)
+	return set
+}
+
+// add adds the values to the set.
+func (r headerSet) add(values ...string) {
+	for _, value := range values {
+		if _, ok := r[strings.ToLower(value)]; !ok {
+			r[strings.ToLower(value)] = value
+		}
+	}
+}
+
+// contains reports whether the set holds the value.
+func (r headerSet) contains(value string) bool {
+	_, ok := r[strings.ToLower(value)]
+	return ok
+}
+
+// values returns the values of the set in sorted order.
+func (r headerSet) values() []string {
+	values := make([]string, 0, len(r))
+	for _, value := range r {
+		values = append(values, value)
+	}
+	sort.Strings(values)
+	return values
+}
@@ -0,0 +1,160 @@
+package oas
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+)
+
+type CORSSuite struct {
+	suite.Suite
+}
+
+func (r *CORSSuite) corsDocument() *OpenAPI {
+	return &OpenAPI{
+		OpenAPI:  "3.0.0",
+		Info:     Info{Title: "Petstore", Version: "1.0.0"},
+		Security: []*SecurityRequirement{{"bearer": {}}},
+		Paths: Paths{
+			PathItems: NewPathItems(map[string]*PathItem{
+				"/pets": {
+					Parameters: []*Parameter{{Name: "X-Request-ID", In: "header"}},
+					Get: &Operation{
+						Security: []*SecurityRequirement{},
+						Responses: NewResponses(map[string]*Response{
+							"200": {Description: "Pets.", Headers: map[string]*Header{"X-Total-Count": {}}},
+						}),
+					},
+					Post: &Operation{
+						Security: []*SecurityRequirement{{"key": {}}},
+						RequestBody: &RequestBody{
+							Content: map[string]*MediaType{"application/json": {}},
+						},
+					},
+				},
+				"/pets/{petId}": {
+					Delete: &Operation{},
+					Patch: &Operation{
+						RequestBody: &RequestBody{
+							Content: map[string]*MediaType{"application/x-www-form-urlencoded": {}},
+						},
+					},
+				},
+			}),
+		},
+		Components: &Components{
+			SecuritySchemes: map[string]*SecurityScheme{
+				"bearer": {Type: "http", Scheme: "bearer"},
+				"key":    {Type: "apiKey", Name: "X-API-Key", In: "header"},
+			},
+		},
+	}
+}
+
+func (r *CORSSuite) TestCORSPolicy() {
+	policy := r.corsDocument().CORSPolicy([]string{"https://app.example.com"})
+	assert.Equal(r.T(), []string{"https://app.example.com"}, policy.AllowedOrigins)
+	assert.Equal(r.T(), map[string]*CORSRule{
+		"/pets": {
+			Methods:        []string{"GET", "POST"},
+			Headers:        []string{"Content-Type", "X-API-Key", "X-Request-ID"},
+			ExposedHeaders: []string{"X-Total-Count"},
+		},
+		"/pets/{petId}": {
+			Methods:        []string{"DELETE", "PATCH"},
+			Headers:        []string{"Authorization"},
+			ExposedHeaders: []string{},
+		},
+	}, policy.Paths)
+}
+
+func (r *CORSSuite) TestMiddleware() {
+	policy := r.corsDocument().CORSPolicy([]string{"https://app.example.com"})
+	policy.AllowCredentials = true
+	policy.MaxAge = 600
+	handler := policy.Middleware(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		w.Write([]byte("ok"))
+	}))
+
+	req := httptest.NewRequest(http.MethodOptions, "/pets/7", nil)
+	req.Header.Set("Origin", "https://app.example.com")
+	req.Header.Set("Access-Control-Request-Method", "DELETE")
+	recorder := httptest.NewRecorder()
+	handler.ServeHTTP(recorder, req)
+	assert.Equal(r.T(), http.StatusNoContent, recorder.Code)
+	assert.Equal(r.T(), "https://app.example.com", recorder.Header().Get("Access-Control-Allow-Origin"))
+	assert.Equal(r.T(), "DELETE, PATCH", recorder.Header().Get("Access-Control-Allow-Methods"))
+	assert.Equal(r.T(), "Authorization", recorder.Header().Get("Access-Control-Allow-Headers"))
+	assert.Equal(r.T(), "true", recorder.Header().Get("Access-Control-Allow-Credentials"))
+	assert.Equal(r.T(), "600", recorder.Header().Get("Access-Control-Max-Age"))
+
+	req = httptest.NewRequest(http.MethodOptions, "/pets/7", nil)
+	req.Header.Set("Origin", "https://app.example.com")
+	req.Header.Set("Access-Control-Request-Method", "PUT")
+	recorder = httptest.NewRecorder()
+	handler.ServeHTTP(recorder, req)
+	assert.Equal(r.T(), http.StatusNoContent, recorder.Code)
+	assert.Equal(r.T(), "", recorder.Header().Get("Access-Control-Allow-Origin"))
+
+	req = httptest.NewRequest(http.MethodGet, "/pets", nil)
+	req.Header.Set("Origin", "https://app.example.com")
+	recorder = httptest.NewRecorder()
+	handler.ServeHTTP(recorder, req)
+	assert.Equal(r.T(), "ok", recorder.Body.String())
+	assert.Equal(r.T(), "https://app.example.com", recorder.Header().Get("Access-Control-Allow-Origin"))
+	assert.Equal(r.T(), "X-Total-Count", recorder.Header().Get("Access-Control-Expose-Headers"))
+
+	req = httptest.NewRequest(http.MethodGet, "/pets", nil)
+	req.Header.Set("Origin", "https://evil.example.com")
+	recorder = httptest.NewRecorder()
+	handler.ServeHTTP(recorder, req)
+	assert.Equal(r.T(), "ok", recorder.Body.String())
+	assert.Equal(r.T(), "", recorder.Header().Get("Access-Control-Allow-Origin"))
+}
+
+func (r *CORSSuite) TestWildcardOrigin() {
+	policy := &CORSPolicy{
+		AllowedOrigins: []string{"*"},
+		Paths:          map[string]*CORSRule{"/pets": {Methods: []string{"GET"}}},
+	}
+	handler := policy.Middleware(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {}))
+
+	req := httptest.NewRequest(http.MethodGet, "/pets", nil)
+	req.Header.Set("Origin", "https://any.example.com")
+	recorder := httptest.NewRecorder()
+	handler.ServeHTTP(recorder, req)
+	assert.Equal(r.T(), "*", recorder.Header().Get("Access-Control-Allow-Origin"))
+}
+
+func (r *CORSSuite) TestWildcardOriginWithCredentials() {
+	policy := &CORSPolicy{
+		AllowedOrigins:   []string{"https://app.example.com", "*"},
+		AllowCredentials: true,
+		Paths:            map[string]*CORSRule{"/pets": {Methods: []string{"GET"}}},
+	}
+	assert.Equal(r.T(), ErrCORSWildcardCredentials, errors.Cause(policy.Validate()))
+
+	called := false
+	handler := policy.Middleware(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		called = true
+	}))
+	req := httptest.NewRequest(http.MethodGet, "/pets", nil)
+	req.Header.Set("Origin", "https://evil.example.com")
+	recorder := httptest.NewRecorder()
+	handler.ServeHTTP(recorder, req)
+	assert.False(r.T(), called)
+	assert.Equal(r.T(), http.StatusInternalServerError, recorder.Code)
+	assert.Equal(r.T(), "", recorder.Header().Get("Access-Control-Allow-Origin"))
+	assert.Equal(r.T(), "", recorder.Header().Get("Access-Control-Allow-Credentials"))
+
+	policy = &CORSPolicy{AllowedOrigins: []string{"*"}}
+	assert.Nil(r.T(), policy.Validate())
+}
+
+func TestCORSSuite(t *testing.T) {
+	suite.Run(t, new(CORSSuite))
+}
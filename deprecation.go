@@ -0,0 +1,156 @@
+package oas
+
+import (
+	"github.com/pkg/errors"
+)
+
+// Specification extensions structuring the deprecation of schemas and
+// properties.
+const (
+	// DeprecatedReasonExtension names the extension explaining why a schema
+	// is deprecated and what replaces it.
+	DeprecatedReasonExtension = "x-deprecated-reason"
+
+	// RemovalVersionExtension names the extension declaring the semantic
+	// version of the document the schema is removed in.
+	RemovalVersionExtension = "x-removal-version"
+)
+
+// RulePastRemoval names the rule flagging fields still declared in versions
+// of the document at or past their removal version.
+const RulePastRemoval = "past-removal-version"
+
+// Deprecation describes why and until when a schema is deprecated.
+type Deprecation struct {
+	// Reason describes why the schema is deprecated and what replaces it.
+	Reason string `json:"reason,omitempty" yaml:"reason,omitempty"`
+
+	// RemovalVersion describes the semantic version of the document the
+	// schema is removed in.
+	RemovalVersion string `json:"removalVersion,omitempty" yaml:"removalVersion,omitempty"`
+}
+
+// Deprecation returns the deprecation declared by the x-deprecated-reason
+// and x-removal-version extensions, or nil if neither is present.
+func (r Extensions) Deprecation() (*Deprecation, error) {
+	deprecation := &Deprecation{}
+	reason, err := r.decode(DeprecatedReasonExtension, &deprecation.Reason)
+	if err != nil {
+		return nil, err
+	}
+	removal, err := r.decode(RemovalVersionExtension, &deprecation.RemovalVersion)
+	if err != nil {
+		return nil, err
+	}
+	if !reason && !removal {
+		return nil, nil
+	}
+	if removal {
+		if _, ok := parseVersion(deprecation.RemovalVersion); !ok {
+			return nil, errors.Errorf("%s: %q is not a semantic version", RemovalVersionExtension, deprecation.RemovalVersion)
+		}
+	}
+	return deprecation, nil
+}
+
+// SetDeprecation declares the deprecation with the x-deprecated-reason and
+// x-removal-version extensions, or removes them when nil.
+func (r *Extensions) SetDeprecation(deprecation *Deprecation) {
+	delete(*r, DeprecatedReasonExtension)
+	delete(*r, RemovalVersionExtension)
+	if deprecation == nil {
+		return
+	}
+	if deprecation.Reason != "" {
+		setExtension(r, DeprecatedReasonExtension, deprecation.Reason)
+	}
+	if deprecation.RemovalVersion != "" {
+		setExtension(r, RemovalVersionExtension, deprecation.RemovalVersion)
+	}
+}
+
+// DeprecatedField describes a deprecated schema of a document.
+type DeprecatedField struct {
+	// Pointer describes the JSON Pointer of the schema.
+	Pointer string `json:"pointer" yaml:"pointer"`
+
+	// Deprecation describes why and until when the schema is deprecated.
+	Deprecation
+}
+
+// DeprecatedFields returns the schemas marked deprecated or annotated with
+// the deprecation extensions, ordered by pointer, so their reason and removal
+// version can be documented next to them.
+func (r *OpenAPI) DeprecatedFields() ([]*DeprecatedField, error) {
+	fields := make([]*DeprecatedField, 0)
+	err := walk(r, func(ptr string, node interface{}) error {
+		schema, ok := node.(*Schema)
+		if !ok || schema.Ref != "" {
+			return nil
+		}
+		deprecation, err := schema.Extensions.Deprecation()
+		if err != nil {
+			return errors.Wrap(err, ptr)
+		}
+		if deprecation == nil && !schema.Deprecated {
+			return nil
+		}
+		if deprecation == nil {
+			deprecation = &Deprecation{}
+		}
+		fields = append(fields, &DeprecatedField{Pointer: ptr, Deprecation: *deprecation})
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return fields, nil
+}
+
+// AuditRemovals flags the schemas whose removal version is at or before the
+// version, which defaults to the version of the document, as they should be
+// gone by now. Schemas annotated with a removal version but not marked
+// deprecated are flagged as well, as clients are not warned about them.
+func (r *OpenAPI) AuditRemovals(version string) ([]*Finding, error) {
+	if version == "" {
+		version = r.Info.Version
+	}
+	if _, ok := parseVersion(version); !ok {
+		return nil, errors.Errorf("%q is not a semantic version", version)
+	}
+	fields, err := r.DeprecatedFields()
+	if err != nil {
+		return nil, err
+	}
+
+	findings := make([]*Finding, 0)
+	for _, field := range fields {
+		if field.RemovalVersion == "" {
+			continue
+		}
+		if compareVersions(field.RemovalVersion, version) <= 0 {
+			findings = append(findings, &Finding{
+				Pointer:  field.Pointer,
+				Rule:     RulePastRemoval,
+				Severity: SeverityError,
+				Message:  "field scheduled for removal in " + field.RemovalVersion + " is still declared in " + version,
+			})
+		}
+	}
+	err = walk(r, func(ptr string, node interface{}) error {
+		if schema, ok := node.(*Schema); ok && !schema.Deprecated && schema.Extensions[RemovalVersionExtension] != nil {
+			findings = append(findings, &Finding{
+				Pointer:  ptr,
+				Rule:     RulePastRemoval,
+				Severity: SeverityWarning,
+				Message:  "field scheduled for removal is not marked deprecated",
+			})
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	sortFindings(findings)
+	return findings, nil
+}
@@ -0,0 +1,127 @@
+package oas
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+)
+
+type DeprecationSuite struct {
+	suite.Suite
+}
+
+// deprecationPatch is the patch of the petstore fixture the suite audits.
+const deprecationPatch = `
+info:
+  version: 2.1.0
+components:
+  schemas:
+    Pet:
+      properties:
+        tag:
+          deprecated: true
+          x-deprecated-reason: Use tags instead.
+          x-removal-version: 2.0.0
+        nickname:
+          type: string
+          deprecated: true
+          x-removal-version: 3.0.0
+        legacy:
+          type: string
+          deprecated: true
+        color:
+          type: string
+          x-removal-version: 4.0.0
+`
+
+func (r *DeprecationSuite) TestDeprecation() {
+	testCases := []struct {
+		extensions Extensions
+		expected   *Deprecation
+		isErr      bool
+	}{
+		{Extensions{}, nil, false},
+		{
+			Extensions{DeprecatedReasonExtension: "Use tags instead."},
+			&Deprecation{Reason: "Use tags instead."},
+			false,
+		},
+		{
+			Extensions{DeprecatedReasonExtension: "Use tags instead.", RemovalVersionExtension: "v2.0.0"},
+			&Deprecation{Reason: "Use tags instead.", RemovalVersion: "v2.0.0"},
+			false,
+		},
+		{Extensions{RemovalVersionExtension: "next"}, nil, true},
+		{Extensions{DeprecatedReasonExtension: []interface{}{"a"}}, nil, true},
+	}
+
+	for i, testCase := range testCases {
+		failMsg := "test case %d failed"
+		deprecation, err := testCase.extensions.Deprecation()
+		if testCase.isErr {
+			assert.NotNil(r.T(), err, failMsg, i)
+			continue
+		}
+		assert.Nil(r.T(), err, failMsg, i)
+		assert.Equal(r.T(), testCase.expected, deprecation, failMsg, i)
+	}
+}
+
+func (r *DeprecationSuite) TestSetDeprecation() {
+	extensions := Extensions{}
+	deprecation := &Deprecation{Reason: "Use tags instead.", RemovalVersion: "2.0.0"}
+	extensions.SetDeprecation(deprecation)
+	decoded, err := extensions.Deprecation()
+	assert.Nil(r.T(), err)
+	assert.Equal(r.T(), deprecation, decoded)
+
+	extensions.SetDeprecation(&Deprecation{Reason: "Gone."})
+	assert.Nil(r.T(), extensions[RemovalVersionExtension])
+	extensions.SetDeprecation(nil)
+	assert.Empty(r.T(), extensions)
+}
+
+func (r *DeprecationSuite) TestDeprecatedFields() {
+	fields, err := petstore(r.T(), deprecationPatch).DeprecatedFields()
+	assert.Nil(r.T(), err)
+	assert.Equal(r.T(), []*DeprecatedField{
+		{Pointer: "/components/schemas/Pet/properties/color", Deprecation: Deprecation{RemovalVersion: "4.0.0"}},
+		{Pointer: "/components/schemas/Pet/properties/legacy"},
+		{Pointer: "/components/schemas/Pet/properties/nickname", Deprecation: Deprecation{RemovalVersion: "3.0.0"}},
+		{
+			Pointer:     "/components/schemas/Pet/properties/tag",
+			Deprecation: Deprecation{Reason: "Use tags instead.", RemovalVersion: "2.0.0"},
+		},
+	}, fields)
+}
+
+func (r *DeprecationSuite) TestAuditRemovals() {
+	findings, err := petstore(r.T(), deprecationPatch).AuditRemovals("")
+	assert.Nil(r.T(), err)
+	assert.Equal(r.T(), []*Finding{
+		{
+			Pointer:  "/components/schemas/Pet/properties/color",
+			Rule:     RulePastRemoval,
+			Severity: SeverityWarning,
+			Message:  "field scheduled for removal is not marked deprecated",
+		},
+		{
+			Pointer:  "/components/schemas/Pet/properties/tag",
+			Rule:     RulePastRemoval,
+			Severity: SeverityError,
+			Message:  "field scheduled for removal in 2.0.0 is still declared in 2.1.0",
+		},
+	}, findings)
+
+	findings, err = petstore(r.T(), deprecationPatch).AuditRemovals("3.0.0")
+	assert.Nil(r.T(), err)
+	assert.Len(r.T(), findings, 3)
+
+	_, err = petstore(r.T(), deprecationPatch).AuditRemovals("latest")
+	assert.NotNil(r.T(), err)
+}
+
+func TestDeprecationSuite(t *testing.T) {
+	suite.Run(t, new(DeprecationSuite))
+}
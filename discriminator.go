@@ -5,7 +5,7 @@ import (
 	"fmt"
 
 	"github.com/pkg/errors"
-	"gopkg.in/yaml.v2"
+	"gopkg.in/yaml.v3"
 )
 
 // Discriminator indicates when request bodies or response payloads may be one
@@ -47,22 +47,7 @@ func (r Discriminator) MarshalJSON() ([]byte, error) {
 
 // UnmarshalJSON parses the JSON-encoded data and stores the result.
 func (r *Discriminator) UnmarshalJSON(data []byte) error {
-	return r.UnmarshalYAML(func(in interface{}) error {
-		obj := make(map[string]interface{})
-		if err := json.Unmarshal(data, &obj); err != nil {
-			return errors.WithStack(err)
-		}
-
-		rbytes, err := yaml.Marshal(obj)
-		if err != nil {
-			return errors.WithStack(err)
-		}
-
-		if err := yaml.Unmarshal(rbytes, in); err != nil {
-			return errors.WithStack(err)
-		}
-		return nil
-	})
+	return decodeJSON(data, r)
 }
 
 // MarshalYAML returns the YAML encoding.
@@ -79,7 +64,13 @@ func (r Discriminator) MarshalYAML() (interface{}, error) {
 }
 
 // UnmarshalYAML parses the YAML-encoded data and stores the result.
-func (r *Discriminator) UnmarshalYAML(unmarshal func(interface{}) error) error {
+func (r *Discriminator) UnmarshalYAML(node *yaml.Node) error {
+	return decodeYAML(node, r)
+}
+
+// unmarshalValue stores the result of the unmarshal function, which decodes
+// either JSON or YAML data.
+func (r *Discriminator) unmarshalValue(d *valueDecoder, unmarshal func(interface{}) error) error {
 	obj := make(map[string]interface{})
 	if err := unmarshal(&obj); err != nil {
 		return errors.WithStack(err)
@@ -92,7 +83,7 @@ func (r *Discriminator) UnmarshalYAML(unmarshal func(interface{}) error) error {
 	}
 
 	if value, ok := obj["mapping"]; ok {
-		if value, ok := cleanupMapValue(value).(map[string]interface{}); ok {
+		if value, ok := value.(map[string]interface{}); ok {
 			s := make(map[string]string, len(value))
 			for k, v := range value {
 				s[k] = fmt.Sprint(v)
@@ -0,0 +1,235 @@
+package oas
+
+import (
+	"sort"
+	"strings"
+)
+
+// DefaultSimilarity is the minimum similarity of near-duplicate endpoints
+// when DuplicateOptions does not specify one.
+const DefaultSimilarity = 0.8
+
+// Kinds of duplicate endpoints.
+const (
+	// DuplicateCollision describes endpoints of different documents sharing
+	// the method and the path, ignoring the names of path parameters.
+	DuplicateCollision = "collision"
+
+	// DuplicateSimilar describes endpoints of different documents sharing the
+	// method and the path shape and exchanging similar schemas.
+	DuplicateSimilar = "similar"
+)
+
+// DuplicateOptions describes the behavior of DuplicateEndpoints.
+type DuplicateOptions struct {
+	// Similarity describes the minimum share of property names two
+	// endpoints must have in common to be reported as near-duplicates, from
+	// 0 to 1. Defaults to DefaultSimilarity.
+	Similarity float64
+}
+
+// WorkspaceEndpoint addresses an operation of a workspace document.
+type WorkspaceEndpoint struct {
+	// Location describes the workspace location of the document.
+	Location string `json:"location" yaml:"location"`
+
+	// Path describes the path template of the operation.
+	Path string `json:"path" yaml:"path"`
+
+	// Method describes the uppercase HTTP method of the operation.
+	Method string `json:"method" yaml:"method"`
+}
+
+// DuplicateEndpoint describes endpoints of different documents overlapping
+// each other.
+type DuplicateEndpoint struct {
+	// Kind describes the kind of the overlap, DuplicateCollision or
+	// DuplicateSimilar.
+	Kind string `json:"kind" yaml:"kind"`
+
+	// Endpoints describes the overlapping endpoints sorted by location and
+	// path.
+	Endpoints []*WorkspaceEndpoint `json:"endpoints" yaml:"endpoints"`
+
+	// Similarity describes the share of property names the schemas of the
+	// endpoints have in common. Collisions have a similarity of 1.
+	Similarity float64 `json:"similarity" yaml:"similarity"`
+}
+
+// endpointInfo holds what DuplicateEndpoints compares of an operation.
+type endpointInfo struct {
+	endpoint   *WorkspaceEndpoint
+	shape      []string
+	properties map[string]bool
+}
+
+// DuplicateEndpoints detects endpoints overlapping across the documents of
+// the workspace, helping platform teams find APIs offering the same thing.
+// Endpoints of different documents with the same method and path, ignoring
+// the names of path parameters, are reported as collisions. Endpoints with
+// the same method, path parameters at the same positions and the same last
+// static path segment are reported as similar when the property names of
+// their request and response schemas overlap by at least the configured
+// similarity. Collisions are reported first, followed by the similar
+// endpoints ordered by decreasing similarity.
+func (r *Workspace) DuplicateEndpoints(opts DuplicateOptions) []*DuplicateEndpoint {
+	if opts.Similarity <= 0 {
+		opts.Similarity = DefaultSimilarity
+	}
+
+	infos := make([]*endpointInfo, 0)
+	for _, location := range r.Locations() {
+		doc, _ := r.Document(location)
+		for _, op := range doc.Paths.operations() {
+			infos = append(infos, &endpointInfo{
+				endpoint:   &WorkspaceEndpoint{Location: location, Path: op.path, Method: strings.ToUpper(op.method)},
+				shape:      pathShape(op.path),
+				properties: doc.operationProperties(op.operation),
+			})
+		}
+	}
+
+	collisions := map[string][]*endpointInfo{}
+	keys := make([]string, 0)
+	for _, info := range infos {
+		key := info.endpoint.Method + " " + strings.Join(info.shape, "/")
+		if collisions[key] == nil {
+			keys = append(keys, key)
+		}
+		collisions[key] = append(collisions[key], info)
+	}
+	sort.Strings(keys)
+
+	duplicates := make([]*DuplicateEndpoint, 0)
+	for _, key := range keys {
+		group := collisions[key]
+		if !spansLocations(group) {
+			continue
+		}
+		endpoints := make([]*WorkspaceEndpoint, 0, len(group))
+		for _, info := range group {
+			endpoints = append(endpoints, info.endpoint)
+		}
+		duplicates = append(duplicates, &DuplicateEndpoint{Kind: DuplicateCollision, Endpoints: endpoints, Similarity: 1})
+	}
+
+	similar := make([]*DuplicateEndpoint, 0)
+	for i, a := range infos {
+		for _, b := range infos[i+1:] {
+			if a.endpoint.Location == b.endpoint.Location || !similarShapes(a, b) {
+				continue
+			}
+			similarity := jaccard(a.properties, b.properties)
+			if similarity < opts.Similarity {
+				continue
+			}
+			similar = append(similar, &DuplicateEndpoint{
+				Kind:       DuplicateSimilar,
+				Endpoints:  []*WorkspaceEndpoint{a.endpoint, b.endpoint},
+				Similarity: similarity,
+			})
+		}
+	}
+	sort.SliceStable(similar, func(i, j int) bool {
+		return similar[i].Similarity > similar[j].Similarity
+	})
+	return append(duplicates, similar...)
+}
+
+// pathShape returns the lowercase segments of the path template with the
+// path parameters replaced by "{}".
+func pathShape(path string) []string {
+	segments := strings.Split(strings.Trim(strings.ToLower(path), "/"), "/")
+	for i, segment := range segments {
+		if strings.HasPrefix(segment, "{") && strings.HasSuffix(segment, "}") {
+			segments[i] = "{}"
+		}
+	}
+	return segments
+}
+
+// spansLocations reports whether the endpoints belong to more than one
+// document.
+func spansLocations(infos []*endpointInfo) bool {
+	for _, info := range infos[1:] {
+		if info.endpoint.Location != infos[0].endpoint.Location {
+			return true
+		}
+	}
+	return false
+}
+
+// similarShapes reports whether the endpoints have the same method and path
+// parameter positions and end with the same static segment, without
+// colliding.
+func similarShapes(a *endpointInfo, b *endpointInfo) bool {
+	if a.endpoint.Method != b.endpoint.Method || len(a.shape) != len(b.shape) {
+		return false
+	}
+	equal, last := true, -1
+	for i := range a.shape {
+		if (a.shape[i] == "{}") != (b.shape[i] == "{}") {
+			return false
+		}
+		if a.shape[i] != "{}" {
+			last = i
+		}
+		equal = equal && a.shape[i] == b.shape[i]
+	}
+	return !equal && last >= 0 && a.shape[last] == b.shape[last]
+}
+
+// operationProperties returns the top-level property names of the request
+// body and successful response schemas of the operation.
+func (r OpenAPI) operationProperties(operation *Operation) map[string]bool {
+	properties := map[string]bool{}
+	add := func(content map[string]*MediaType) {
+		for _, mediaType := range content {
+			if mediaType == nil {
+				continue
+			}
+			schema := NewResolver(&r).schema(mediaType.Schema)
+			if schema != nil && schema.Items != nil {
+				schema = NewResolver(&r).schema(schema.Items)
+			}
+			if schema == nil {
+				continue
+			}
+			for _, name := range schema.Properties.Keys() {
+				properties[name] = true
+			}
+		}
+	}
+
+	if _, body := NewResolver(&r).requestBody("", operation.RequestBody); body != nil {
+		add(body.Content)
+	}
+	for _, code := range operation.Responses.Keys() {
+		response := operation.Responses.Get(code)
+		if !strings.HasPrefix(code, "2") {
+			continue
+		}
+		if _, response := NewResolver(&r).response("", response); response != nil {
+			add(response.Content)
+		}
+	}
+	return properties
+}
+
+// jaccard returns the size of the intersection of the sets divided by the
+// size of their union, or 0 if both are empty.
+func jaccard(a map[string]bool, b map[string]bool) float64 {
+	union := len(a)
+	common := 0
+	for key := range b {
+		if a[key] {
+			common++
+		} else {
+			union++
+		}
+	}
+	if union == 0 {
+		return 0
+	}
+	return float64(common) / float64(union)
+}
@@ -0,0 +1,100 @@
+package oas
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+)
+
+type DuplicatesSuite struct {
+	suite.Suite
+}
+
+func (r *DuplicatesSuite) workspace(docs map[string]*OpenAPI) *Workspace {
+	workspace := &Workspace{
+		Read: func(ctx context.Context, location string) ([]byte, error) {
+			return docs[location].MarshalJSON()
+		},
+	}
+	locations := make([]string, 0, len(docs))
+	for location := range docs {
+		locations = append(locations, location)
+	}
+	assert.Nil(r.T(), workspace.Load(context.Background(), locations...))
+	return workspace
+}
+
+func (r *DuplicatesSuite) operation(properties ...string) *Operation {
+	schema := &Schema{Type: "object"}
+	for _, name := range properties {
+		schema.Properties.Set(name, &Schema{Type: "string"})
+	}
+	return &Operation{
+		Responses: NewResponses(map[string]*Response{
+			"200": {
+				Description: "OK.",
+				Content:     map[string]*MediaType{"application/json": {Schema: schema}},
+			},
+		}),
+	}
+}
+
+func (r *DuplicatesSuite) TestDuplicateEndpoints() {
+	workspace := r.workspace(map[string]*OpenAPI{
+		"pets": {
+			OpenAPI: "3.0.0",
+			Info:    Info{Title: "Pets", Version: "1.0.0"},
+			Paths: Paths{PathItems: NewPathItems(map[string]*PathItem{
+				"/pets/{petId}": {Get: r.operation("id", "name", "tag")},
+				"/v1/owners":    {Get: r.operation("id", "name", "email")},
+				"/stores":       {Get: r.operation("id", "name")},
+			})},
+		},
+		"shop": {
+			OpenAPI: "3.0.0",
+			Info:    Info{Title: "Shop", Version: "1.0.0"},
+			Paths: Paths{PathItems: NewPathItems(map[string]*PathItem{
+				"/pets/{id}":  {Get: r.operation("id", "name", "tag")},
+				"/v2/owners":  {Get: r.operation("id", "name", "email", "phone")},
+				"/api/stores": {Get: r.operation("id", "name")},
+				"/v3/owners":  {Post: r.operation("id", "name", "email")},
+			})},
+		},
+	})
+
+	assert.Equal(r.T(), []*DuplicateEndpoint{
+		{
+			Kind: DuplicateCollision,
+			Endpoints: []*WorkspaceEndpoint{
+				{Location: "pets", Path: "/pets/{petId}", Method: "GET"},
+				{Location: "shop", Path: "/pets/{id}", Method: "GET"},
+			},
+			Similarity: 1,
+		},
+	}, workspace.DuplicateEndpoints(DuplicateOptions{}))
+
+	assert.Equal(r.T(), []*DuplicateEndpoint{
+		{
+			Kind: DuplicateCollision,
+			Endpoints: []*WorkspaceEndpoint{
+				{Location: "pets", Path: "/pets/{petId}", Method: "GET"},
+				{Location: "shop", Path: "/pets/{id}", Method: "GET"},
+			},
+			Similarity: 1,
+		},
+		{
+			Kind: DuplicateSimilar,
+			Endpoints: []*WorkspaceEndpoint{
+				{Location: "pets", Path: "/v1/owners", Method: "GET"},
+				{Location: "shop", Path: "/v2/owners", Method: "GET"},
+			},
+			Similarity: 0.75,
+		},
+	}, workspace.DuplicateEndpoints(DuplicateOptions{Similarity: 0.7}))
+}
+
+func TestDuplicatesSuite(t *testing.T) {
+	suite.Run(t, new(DuplicatesSuite))
+}
@@ -0,0 +1,130 @@
+package oas
+
+import (
+	"context"
+	"net/http"
+	"sort"
+	"strings"
+)
+
+// LoadEmbedded decodes and validates the document embedded into the binary
+// (e.g. with a go:embed directive) from the location it was read from,
+// with Validate and then ValidateSemantics. Violations are located in the
+// source of the document, so the *ValidationError points at the offending
+// lines.
+func LoadEmbedded(location string, data []byte) (*OpenAPI, error) {
+	doc, provenance, err := Loader{}.LoadLocated(location, data)
+	if err != nil {
+		return nil, err
+	}
+	err = doc.Validate(context.Background())
+	if err == nil {
+		err = doc.ValidateSemantics(context.Background(), SemanticOptions{})
+	}
+	if err != nil {
+		if invalid, ok := err.(*ValidationError); ok {
+			provenance.Annotate(invalid.Violations)
+		}
+		return nil, err
+	}
+	return doc, nil
+}
+
+// MustLoadEmbedded is like LoadEmbedded but panics if the document is
+// invalid. It is intended for package level variables and init functions,
+// so a broken embedded document stops the program at startup rather than
+// at the first request.
+func MustLoadEmbedded(location string, data []byte) *OpenAPI {
+	doc, err := LoadEmbedded(location, data)
+	if err != nil {
+		panic(err)
+	}
+	return doc
+}
+
+// HandlerDriftError describes the differences between the operations of a
+// document and the handlers registered for them.
+type HandlerDriftError struct {
+	// Missing describes the operations without a handler, by operationId,
+	// or by uppercase method and path for operations without one.
+	Missing []string
+
+	// Unknown describes the handlers registered for operations the document
+	// does not declare.
+	Unknown []string
+}
+
+// Error returns the string representation of the error.
+func (e *HandlerDriftError) Error() string {
+	messages := make([]string, 0, 2)
+	if len(e.Missing) > 0 {
+		messages = append(messages, "operations without a handler: "+strings.Join(e.Missing, ", "))
+	}
+	if len(e.Unknown) > 0 {
+		messages = append(messages, "handlers of undeclared operations: "+strings.Join(e.Unknown, ", "))
+	}
+	return strings.Join(messages, "; ")
+}
+
+// VerifyHandlers checks that every operation of the document has a handler
+// and every handler an operation. Handlers are registered by the
+// operationId of their operation, or by uppercase method and path (e.g.
+// "GET /pets/{petId}") for operations without one. Differences are returned
+// as a *HandlerDriftError.
+func (r OpenAPI) VerifyHandlers(handlers map[string]http.Handler) error {
+	drift := &HandlerDriftError{}
+	used := map[string]bool{}
+	for _, op := range r.Paths.operations() {
+		key := strings.ToUpper(op.method) + " " + op.path
+		if op.operation.OperationID != "" {
+			if _, ok := handlers[op.operation.OperationID]; ok {
+				used[op.operation.OperationID] = true
+				continue
+			}
+		}
+		if _, ok := handlers[key]; ok {
+			used[key] = true
+			continue
+		}
+		if op.operation.OperationID != "" {
+			key = op.operation.OperationID
+		}
+		drift.Missing = append(drift.Missing, key)
+	}
+	for key := range handlers {
+		if !used[key] {
+			drift.Unknown = append(drift.Unknown, key)
+		}
+	}
+	sort.Strings(drift.Unknown)
+	if len(drift.Missing) == 0 && len(drift.Unknown) == 0 {
+		return nil
+	}
+	return drift
+}
+
+// TestingT is the subset of testing.TB used by VerifyEmbedded and Expect.
+type TestingT interface {
+	Helper()
+	Fatalf(format string, args ...interface{})
+}
+
+// VerifyEmbedded fails the test if the embedded document is invalid or its
+// operations do not match the handlers, catching drift between the
+// document and the implementation in CI rather than in production. When
+// handlers is nil, only the document is checked. It returns the document.
+func VerifyEmbedded(t TestingT, location string, data []byte, handlers map[string]http.Handler) *OpenAPI {
+	t.Helper()
+	doc, err := LoadEmbedded(location, data)
+	if err != nil {
+		t.Fatalf("%v", err)
+		return nil
+	}
+	if handlers != nil {
+		if err := doc.VerifyHandlers(handlers); err != nil {
+			t.Fatalf("%s: %v", location, err)
+			return nil
+		}
+	}
+	return doc
+}
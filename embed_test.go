@@ -0,0 +1,118 @@
+package oas
+
+import (
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+)
+
+type EmbedSuite struct {
+	suite.Suite
+}
+
+type fakeT struct {
+	failures []string
+}
+
+func (t *fakeT) Helper() {}
+
+func (t *fakeT) Fatalf(format string, args ...interface{}) {
+	t.failures = append(t.failures, fmt.Sprintf(format, args...))
+}
+
+func (r *EmbedSuite) data() []byte {
+	return []byte(`openapi: 3.0.3
+info:
+  title: Pets
+  version: 1.0.0
+paths:
+  /pets:
+    get:
+      operationId: listPets
+      responses:
+        "200":
+          description: OK.
+    post:
+      responses:
+        "201":
+          description: Created.
+`)
+}
+
+func (r *EmbedSuite) TestLoadEmbedded() {
+	doc, err := LoadEmbedded("spec.yaml", r.data())
+	assert.Nil(r.T(), err)
+	assert.NotNil(r.T(), doc)
+
+	_, err = LoadEmbedded("spec.yaml", []byte(`openapi: 3.0.3
+info:
+  version: 1.0.0
+paths:
+  pets: {}
+`))
+	assert.EqualError(r.T(), err, "invalid document: "+
+		"spec.yaml:2:1: /info/title: missing title; "+
+		"spec.yaml:5:3: /paths/pets: path \"pets\" must begin with a slash")
+
+	assert.Panics(r.T(), func() { MustLoadEmbedded("spec.yaml", []byte("openapi: [")) })
+	assert.NotPanics(r.T(), func() { MustLoadEmbedded("spec.yaml", r.data()) })
+}
+
+func (r *EmbedSuite) TestVerifyHandlers() {
+	doc, err := LoadEmbedded("spec.yaml", r.data())
+	if !assert.Nil(r.T(), err) {
+		return
+	}
+	handler := http.NotFoundHandler()
+
+	testCases := []struct {
+		handlers map[string]http.Handler
+		expected error
+	}{
+		{
+			map[string]http.Handler{"listPets": handler, "POST /pets": handler},
+			nil,
+		},
+		{
+			map[string]http.Handler{"GET /pets": handler, "POST /pets": handler},
+			nil,
+		},
+		{
+			map[string]http.Handler{"listPets": handler, "deletePet": handler},
+			&HandlerDriftError{Missing: []string{"POST /pets"}, Unknown: []string{"deletePet"}},
+		},
+		{
+			map[string]http.Handler{},
+			&HandlerDriftError{Missing: []string{"listPets", "POST /pets"}},
+		},
+	}
+
+	for i, testCase := range testCases {
+		failMsg := "test case %d failed"
+		err := doc.VerifyHandlers(testCase.handlers)
+		assert.Equal(r.T(), testCase.expected, err, failMsg, i)
+	}
+}
+
+func (r *EmbedSuite) TestVerifyEmbedded() {
+	t := &fakeT{}
+	doc := VerifyEmbedded(t, "spec.yaml", r.data(), map[string]http.Handler{
+		"listPets": http.NotFoundHandler(),
+	})
+	assert.Nil(r.T(), doc)
+	assert.Equal(r.T(), []string{
+		"spec.yaml: operations without a handler: POST /pets",
+	}, t.failures)
+
+	t = &fakeT{}
+	doc = VerifyEmbedded(t, "spec.yaml", r.data(), nil)
+	assert.NotNil(r.T(), doc)
+	assert.Empty(r.T(), t.failures)
+}
+
+func TestEmbedSuite(t *testing.T) {
+	suite.Run(t, new(EmbedSuite))
+}
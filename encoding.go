@@ -4,7 +4,7 @@ import (
 	"encoding/json"
 
 	"github.com/pkg/errors"
-	"gopkg.in/yaml.v2"
+	"gopkg.in/yaml.v3"
 )
 
 // Encoding defines a single encoding definition applied to a single schema
@@ -77,22 +77,7 @@ func (r Encoding) MarshalJSON() ([]byte, error) {
 
 // UnmarshalJSON parses the JSON-encoded data and stores the result.
 func (r *Encoding) UnmarshalJSON(data []byte) error {
-	return r.UnmarshalYAML(func(in interface{}) error {
-		obj := make(map[string]interface{})
-		if err := json.Unmarshal(data, &obj); err != nil {
-			return errors.WithStack(err)
-		}
-
-		rbytes, err := yaml.Marshal(obj)
-		if err != nil {
-			return errors.WithStack(err)
-		}
-
-		if err := yaml.Unmarshal(rbytes, in); err != nil {
-			return errors.WithStack(err)
-		}
-		return nil
-	})
+	return decodeJSON(data, r)
 }
 
 // MarshalYAML returns the YAML encoding.
@@ -127,7 +112,13 @@ func (r Encoding) MarshalYAML() (interface{}, error) {
 }
 
 // UnmarshalYAML parses the YAML-encoded data and stores the result.
-func (r *Encoding) UnmarshalYAML(unmarshal func(interface{}) error) error {
+func (r *Encoding) UnmarshalYAML(node *yaml.Node) error {
+	return decodeYAML(node, r)
+}
+
+// unmarshalValue stores the result of the unmarshal function, which decodes
+// either JSON or YAML data.
+func (r *Encoding) unmarshalValue(d *valueDecoder, unmarshal func(interface{}) error) error {
 	obj := make(map[string]interface{})
 	if err := unmarshal(&obj); err != nil {
 		return errors.WithStack(err)
@@ -139,14 +130,10 @@ func (r *Encoding) UnmarshalYAML(unmarshal func(interface{}) error) error {
 		}
 	}
 
-	if value, ok := obj["headers"]; ok {
-		rbytes, err := yaml.Marshal(value)
-		if err != nil {
-			return errors.WithStack(err)
-		}
+	if raw, ok := obj["headers"]; ok {
 		value := map[string]*Header{}
-		if err := yaml.Unmarshal(rbytes, &value); err != nil {
-			return errors.WithStack(err)
+		if err := d.decodeMember("headers", raw, &value); err != nil {
+			return err
 		}
 		r.Headers = value
 	}
@@ -0,0 +1,105 @@
+package oas
+
+import (
+	"sort"
+
+	"github.com/pkg/errors"
+)
+
+// ServerEnvironment describes the servers a document is published with for
+// a single deployment environment.
+type ServerEnvironment struct {
+	// Servers describes the servers which replace the document level
+	// servers.
+	Servers []*Server `json:"servers,omitempty" yaml:"servers,omitempty"`
+
+	// Append describes whether the servers are appended to the existing
+	// servers instead of replacing them. Servers with a URL which is already
+	// present are not appended twice.
+	Append bool `json:"append,omitempty" yaml:"append,omitempty"`
+
+	// Paths describes servers which override the servers of individual path
+	// items, keyed by path template.
+	Paths map[string][]*Server `json:"paths,omitempty" yaml:"paths,omitempty"`
+}
+
+// ServerEnvironments maps environment names (e.g. "dev", "stage", "prod") to
+// their servers. It is typically decoded from a configuration file.
+type ServerEnvironments map[string]*ServerEnvironment
+
+// Transform returns a transform injecting the servers of the named
+// environment.
+func (r ServerEnvironments) Transform(name string) Transform {
+	return func(doc *OpenAPI) error {
+		env, ok := r[name]
+		if !ok || env == nil {
+			return errors.Errorf("unknown environment %q", name)
+		}
+		return doc.InjectServers(env)
+	}
+}
+
+// InjectServers replaces or augments the document level and path level
+// servers with those of the environment. Every path an override is declared
+// for must exist in the document. The servers are copied so the environment
+// can be injected into multiple documents.
+func (r *OpenAPI) InjectServers(env *ServerEnvironment) error {
+	paths := make([]string, 0, len(env.Paths))
+	for path := range env.Paths {
+		if r.Paths.PathItems.Get(path) == nil {
+			return errors.Errorf("unknown path %q", path)
+		}
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+
+	servers, err := mergeServers(r.Servers, env.Servers, env.Append)
+	if err != nil {
+		return err
+	}
+
+	items := make(map[string][]*Server, len(env.Paths))
+	for _, path := range paths {
+		value, err := mergeServers(r.Paths.PathItems.Get(path).Servers, env.Paths[path], env.Append)
+		if err != nil {
+			return errors.Wrap(err, path)
+		}
+		items[path] = value
+	}
+
+	if env.Servers != nil {
+		r.Servers = servers
+	}
+	for path, value := range items {
+		r.Paths.PathItems.Get(path).Servers = value
+	}
+	return nil
+}
+
+// mergeServers returns copies of the injected servers either appended to or
+// replacing the existing servers.
+func mergeServers(existing []*Server, injected []*Server, augment bool) ([]*Server, error) {
+	result := make([]*Server, 0, len(existing)+len(injected))
+	urls := map[string]bool{}
+	if augment {
+		for _, server := range existing {
+			if server != nil {
+				urls[server.URL] = true
+			}
+			result = append(result, server)
+		}
+	}
+
+	for _, server := range injected {
+		if server == nil || urls[server.URL] {
+			continue
+		}
+		value, err := server.Clone()
+		if err != nil {
+			return nil, err
+		}
+		urls[server.URL] = true
+		result = append(result, value)
+	}
+	return result, nil
+}
@@ -0,0 +1,80 @@
+package oas
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+	"gopkg.in/yaml.v3"
+)
+
+type EnvironmentSuite struct {
+	suite.Suite
+}
+
+func (r *EnvironmentSuite) environmentDocument() *OpenAPI {
+	return &OpenAPI{
+		OpenAPI: "3.0.0",
+		Info:    Info{Title: "Petstore", Version: "1.0.0"},
+		Servers: []*Server{{URL: "http://localhost:8080"}},
+		Paths: Paths{
+			PathItems: NewPathItems(map[string]*PathItem{
+				"/pets":    {Get: &Operation{OperationID: "listPets"}},
+				"/uploads": {Post: &Operation{OperationID: "upload"}},
+			}),
+		},
+	}
+}
+
+func (r *EnvironmentSuite) TestTransform() {
+	config := []byte(`
+stage:
+  append: true
+  servers:
+    - url: http://localhost:8080
+    - url: https://stage.example.com
+prod:
+  servers:
+    - url: https://api.example.com
+      description: Production
+  paths:
+    /uploads:
+      - url: https://uploads.example.com
+`)
+	environments := ServerEnvironments{}
+	if err := yaml.Unmarshal(config, &environments); err != nil {
+		r.T().Fatal(err)
+	}
+
+	doc := r.environmentDocument()
+	assert.NoError(r.T(), environments.Transform("stage")(doc))
+	expected := r.environmentDocument()
+	expected.Servers = append(expected.Servers, &Server{URL: "https://stage.example.com"})
+	assert.Equal(r.T(), expected, doc)
+
+	doc = r.environmentDocument()
+	assert.NoError(r.T(), environments.Transform("prod")(doc))
+	expected = r.environmentDocument()
+	expected.Servers = []*Server{{URL: "https://api.example.com", Description: "Production"}}
+	expected.Paths.PathItems.Get("/uploads").Servers = []*Server{{URL: "https://uploads.example.com"}}
+	assert.Equal(r.T(), expected, doc)
+
+	doc.Servers[0].URL = "https://changed.example.com"
+	assert.Equal(r.T(), "https://api.example.com", environments["prod"].Servers[0].URL)
+
+	assert.Error(r.T(), environments.Transform("dev")(r.environmentDocument()))
+}
+
+func (r *EnvironmentSuite) TestUnknownPath() {
+	doc := r.environmentDocument()
+	err := doc.InjectServers(&ServerEnvironment{
+		Servers: []*Server{{URL: "https://api.example.com"}},
+		Paths:   map[string][]*Server{"/owners": {{URL: "https://owners.example.com"}}},
+	})
+	assert.Error(r.T(), err)
+	assert.Equal(r.T(), r.environmentDocument(), doc)
+}
+
+func TestEnvironmentSuite(t *testing.T) {
+	suite.Run(t, new(EnvironmentSuite))
+}
@@ -0,0 +1,135 @@
+package oas
+
+import (
+	"strconv"
+	"strings"
+)
+
+// PayloadValidator reports why the value does not conform to the schema,
+// whose references are resolved against the document, or nil if it does.
+type PayloadValidator func(doc *OpenAPI, schema *Schema, value interface{}) error
+
+// Locations of the payloads replayed by SimulateEvolution.
+const (
+	PayloadRequest  = "request"
+	PayloadResponse = "response"
+)
+
+// EvolutionReport describes how a change of a document affects a corpus of
+// recorded payloads.
+type EvolutionReport struct {
+	// Payloads describes the number of payloads replayed.
+	Payloads int `json:"payloads" yaml:"payloads"`
+
+	// AlreadyInvalid describes the number of payloads the current document
+	// already rejects, which are not replayed against the next one.
+	AlreadyInvalid int `json:"alreadyInvalid" yaml:"alreadyInvalid"`
+
+	// Broken describes the payloads the current document accepts and the
+	// next one rejects.
+	Broken []*BrokenPayload `json:"broken" yaml:"broken"`
+}
+
+// BrokenPayload describes a recorded payload a change of a document breaks.
+type BrokenPayload struct {
+	// OperationID describes the operation the payload was recorded for.
+	OperationID string `json:"operationId" yaml:"operationId"`
+
+	// Example describes the name of the example pair holding the payload.
+	Example string `json:"example" yaml:"example"`
+
+	// Location describes whether the payload is the body of the request,
+	// PayloadRequest, or of the response, PayloadResponse.
+	Location string `json:"location" yaml:"location"`
+
+	// Message describes why the next document rejects the payload.
+	Message string `json:"message" yaml:"message"`
+}
+
+// SimulateEvolution replays the request and response bodies of the corpus,
+// such as examples recorded from production traffic, against the current
+// and the next version of the document, and reports the payloads the current
+// version accepts but the next one rejects. Payloads are matched to their
+// schemas by operationId, status code and media type, and payloads whose
+// operation, response or media type the next version no longer declares
+// are reported broken as well. Payloads are validated with ValidatePayload
+// when validate is nil. It quantifies the risk of a schema change before it
+// ships.
+func (r OpenAPI) SimulateEvolution(next OpenAPI, corpus ExampleStore, validate PayloadValidator) (*EvolutionReport, error) {
+	if validate == nil {
+		validate = ValidatePayload
+	}
+	report := &EvolutionReport{Broken: make([]*BrokenPayload, 0)}
+	for _, id := range sortedStrings(corpus) {
+		current, ok := r.operationByID(id)
+		if !ok {
+			continue
+		}
+		proposed, declared := next.operationByID(id)
+		for _, name := range sortedStrings(corpus[id]) {
+			pair := corpus[id][name]
+			if pair == nil {
+				continue
+			}
+			replay := func(location string, value interface{}, schemaOf func(doc OpenAPI, op pathOperation) (*Schema, string)) {
+				report.Payloads++
+				schema, _ := schemaOf(r, current)
+				if schema == nil || validate(&r, schema, value) != nil {
+					report.AlreadyInvalid++
+					return
+				}
+				var message string
+				if !declared {
+					message = "operation is removed"
+				} else if schema, missing := schemaOf(next, proposed); schema == nil {
+					message = missing
+				} else if err := validate(&next, schema, value); err != nil {
+					message = err.Error()
+				}
+				if message != "" {
+					report.Broken = append(report.Broken, &BrokenPayload{
+						OperationID: id,
+						Example:     name,
+						Location:    location,
+						Message:     message,
+					})
+				}
+			}
+
+			if request := pair.Request; request != nil && request.Body != nil {
+				replay(PayloadRequest, request.Body, func(doc OpenAPI, op pathOperation) (*Schema, string) {
+					_, body := NewResolver(&doc).requestBody("", op.operation.RequestBody)
+					if body == nil {
+						return nil, "request body is removed"
+					}
+					return payloadSchema(body.Content, exampleMediaType(request.MediaType))
+				})
+			}
+			if response := pair.Response; response != nil && response.Body != nil {
+				replay(PayloadResponse, response.Body, func(doc OpenAPI, op pathOperation) (*Schema, string) {
+					_, declared := NewResolver(&doc).response("", declaredResponse(op.operation.Responses, response.Status))
+					if declared == nil {
+						return nil, "response " + strconv.Itoa(response.Status) + " is removed"
+					}
+					return payloadSchema(declared.Content, exampleMediaType(response.MediaType))
+				})
+			}
+		}
+	}
+	return report, nil
+}
+
+// payloadSchema returns the schema of the media type in the content,
+// matching media type ranges (e.g. "application/*"), or why there is none.
+func payloadSchema(content map[string]*MediaType, mediaType string) (*Schema, string) {
+	mediaType = strings.ToLower(mediaType)
+	candidates := []string{mediaType, mediaType[:strings.Index(mediaType+"/", "/")] + "/*", "*/*"}
+	for _, candidate := range candidates {
+		for key, value := range content {
+			if strings.ToLower(key) == candidate && value != nil && value.Schema != nil {
+				return value.Schema, ""
+			}
+		}
+	}
+	return nil, "media type " + mediaType + " is removed"
+}
@@ -0,0 +1,151 @@
+package oas
+
+import (
+	"testing"
+
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+)
+
+type EvolutionSuite struct {
+	suite.Suite
+}
+
+// evolutionPatch is the patch of the petstore fixture the suite evolves.
+const evolutionPatch = `
+paths:
+  /pets:
+    post:
+      responses:
+        "201":
+          content:
+            application/*:
+              schema:
+                $ref: "#/components/schemas/Pet"
+`
+
+// validate checks the types and the required properties of the value, which
+// is enough to exercise the simulator.
+func (r *EvolutionSuite) validate(doc *OpenAPI, schema *Schema, value interface{}) error {
+	schema = NewResolver(doc).schema(schema)
+	switch schema.Type {
+	case "array":
+		if _, ok := value.([]interface{}); !ok {
+			return errors.New("expected an array")
+		}
+	case "object":
+		object, ok := value.(map[string]interface{})
+		if !ok {
+			return errors.New("expected an object")
+		}
+		for _, name := range schema.Required {
+			if _, ok := object[name]; !ok {
+				return errors.Errorf("missing required property %q", name)
+			}
+		}
+	}
+	return nil
+}
+
+func (r *EvolutionSuite) corpus() ExampleStore {
+	return ExampleStore{
+		"createPet": {
+			"complete": {
+				Request:  &ExampleRequest{Body: map[string]interface{}{"name": "Rex", "tag": "dog"}},
+				Response: &ExampleResponse{Status: 201, Body: map[string]interface{}{"name": "Rex", "tag": "dog"}},
+			},
+			"untagged": {
+				Request:  &ExampleRequest{Body: map[string]interface{}{"name": "Tom"}},
+				Response: &ExampleResponse{Status: 201, MediaType: "application/hal+json", Body: map[string]interface{}{"name": "Tom"}},
+			},
+			"invalid": {
+				Request: &ExampleRequest{Body: map[string]interface{}{}},
+			},
+		},
+		"listPets": {
+			"empty": {Response: &ExampleResponse{Status: 200, Body: []interface{}{}}},
+		},
+		"deletePet": {
+			"gone": {Response: &ExampleResponse{Status: 204}},
+		},
+	}
+}
+
+func (r *EvolutionSuite) TestSimulateEvolution() {
+	required := `
+components:
+  schemas:
+    Pet:
+      required: [name, tag]
+`
+	testCases := []struct {
+		next     string
+		validate PayloadValidator
+		expected *EvolutionReport
+	}{
+		{
+			required + `
+paths:
+  /pets:
+    get: null
+`,
+			r.validate,
+			&EvolutionReport{
+				Payloads:       6,
+				AlreadyInvalid: 1,
+				Broken: []*BrokenPayload{
+					{OperationID: "createPet", Example: "untagged", Location: PayloadRequest, Message: `missing required property "tag"`},
+					{OperationID: "createPet", Example: "untagged", Location: PayloadResponse, Message: `missing required property "tag"`},
+					{OperationID: "listPets", Example: "empty", Location: PayloadResponse, Message: "operation is removed"},
+				},
+			},
+		},
+		{
+			`
+paths:
+  /pets:
+    post:
+      responses:
+        "201":
+          content:
+            application/*: null
+            application/json:
+              schema:
+                $ref: "#/components/schemas/Pet"
+`,
+			r.validate,
+			&EvolutionReport{
+				Payloads:       6,
+				AlreadyInvalid: 1,
+				Broken: []*BrokenPayload{
+					{OperationID: "createPet", Example: "untagged", Location: PayloadResponse, Message: "media type application/hal+json is removed"},
+				},
+			},
+		},
+		{
+			required,
+			nil,
+			&EvolutionReport{
+				Payloads:       6,
+				AlreadyInvalid: 1,
+				Broken: []*BrokenPayload{
+					{OperationID: "createPet", Example: "untagged", Location: PayloadRequest, Message: `/: missing required property "tag"`},
+					{OperationID: "createPet", Example: "untagged", Location: PayloadResponse, Message: `/: missing required property "tag"`},
+				},
+			},
+		},
+	}
+
+	for i, testCase := range testCases {
+		failMsg := "test case %d failed"
+		next := petstore(r.T(), evolutionPatch, testCase.next)
+		report, err := petstore(r.T(), evolutionPatch).SimulateEvolution(*next, r.corpus(), testCase.validate)
+		assert.Nil(r.T(), err, failMsg, i)
+		assert.Equal(r.T(), testCase.expected, report, failMsg, i)
+	}
+}
+
+func TestEvolutionSuite(t *testing.T) {
+	suite.Run(t, new(EvolutionSuite))
+}
@@ -0,0 +1,266 @@
+package oas
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// Rules of the findings reported by AuditExamples.
+const (
+	RuleExampleUnknownOperation    = "example-unknown-operation"
+	RuleExampleUndeclaredResponse  = "example-undeclared-response"
+	RuleExampleUndeclaredMediaType = "example-undeclared-media-type"
+)
+
+// ExampleRequest describes the request of an example pair.
+type ExampleRequest struct {
+	// PathParams describes the values of the path parameters.
+	PathParams map[string]string `json:"pathParams,omitempty" yaml:"pathParams,omitempty"`
+
+	// Query describes the values of the query parameters.
+	Query map[string]string `json:"query,omitempty" yaml:"query,omitempty"`
+
+	// Headers describes the values of the request headers.
+	Headers map[string]string `json:"headers,omitempty" yaml:"headers,omitempty"`
+
+	// MediaType describes the media type of the body, "application/json"
+	// when empty.
+	MediaType string `json:"mediaType,omitempty" yaml:"mediaType,omitempty"`
+
+	// Body describes the request body.
+	Body interface{} `json:"body,omitempty" yaml:"body,omitempty"`
+}
+
+// ExampleResponse describes the response of an example pair.
+type ExampleResponse struct {
+	// Status describes the HTTP status code of the response.
+	Status int `json:"status" yaml:"status"`
+
+	// Headers describes the values of the response headers.
+	Headers map[string]string `json:"headers,omitempty" yaml:"headers,omitempty"`
+
+	// MediaType describes the media type of the body, "application/json"
+	// when empty.
+	MediaType string `json:"mediaType,omitempty" yaml:"mediaType,omitempty"`
+
+	// Body describes the response body.
+	Body interface{} `json:"body,omitempty" yaml:"body,omitempty"`
+}
+
+// ExamplePair describes a request made to an operation together with the
+// response the API answers it with.
+type ExamplePair struct {
+	// Summary describes a short description of the pair.
+	Summary string `json:"summary,omitempty" yaml:"summary,omitempty"`
+
+	// Description describes a long description of the pair.
+	Description string `json:"description,omitempty" yaml:"description,omitempty"`
+
+	// Request describes the request of the pair.
+	Request *ExampleRequest `json:"request,omitempty" yaml:"request,omitempty"`
+
+	// Response describes the response of the pair.
+	Response *ExampleResponse `json:"response,omitempty" yaml:"response,omitempty"`
+}
+
+// ExampleStore holds named example pairs keyed by operationId and then by
+// example name. It is stored in a directory holding a directory per
+// operationId with a file per example (e.g. "listPets/empty.json").
+type ExampleStore map[string]map[string]*ExamplePair
+
+// LoadExampleStore reads the example pairs stored in the directory. JSON and
+// YAML files are read and other files are ignored.
+func LoadExampleStore(dir string) (ExampleStore, error) {
+	operations, err := ioutil.ReadDir(dir)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+	store := ExampleStore{}
+	for _, operation := range operations {
+		if !operation.IsDir() {
+			continue
+		}
+		files, err := ioutil.ReadDir(filepath.Join(dir, operation.Name()))
+		if err != nil {
+			return nil, errors.WithStack(err)
+		}
+		for _, file := range files {
+			ext := filepath.Ext(file.Name())
+			if file.IsDir() || (ext != ".json" && ext != ".yaml" && ext != ".yml") {
+				continue
+			}
+			location := filepath.Join(dir, operation.Name(), file.Name())
+			data, err := ioutil.ReadFile(location)
+			if err != nil {
+				return nil, errors.WithStack(err)
+			}
+			pair := &ExamplePair{}
+			if err := decodeDocument(data, pair); err != nil {
+				return nil, errors.Wrapf(err, "failed to decode %q", location)
+			}
+			store.Add(operation.Name(), strings.TrimSuffix(file.Name(), ext), pair)
+		}
+	}
+	return store, nil
+}
+
+// Save writes the example pairs to the directory as indented JSON files,
+// creating the directories as needed. Files of pairs missing from the store
+// are left in place.
+func (r ExampleStore) Save(dir string) error {
+	for _, operationID := range sortedStrings(r) {
+		if strings.ContainsAny(operationID, `/\`) {
+			return errors.Errorf("operationId %q cannot name a directory", operationID)
+		}
+		if err := os.MkdirAll(filepath.Join(dir, operationID), 0755); err != nil {
+			return errors.WithStack(err)
+		}
+		for _, name := range sortedStrings(r[operationID]) {
+			if strings.ContainsAny(name, `/\`) {
+				return errors.Errorf("example name %q cannot name a file", name)
+			}
+			data, err := json.MarshalIndent(r[operationID][name], "", "  ")
+			if err != nil {
+				return errors.WithStack(err)
+			}
+			location := filepath.Join(dir, operationID, name+".json")
+			if err := ioutil.WriteFile(location, append(data, '\n'), 0644); err != nil {
+				return errors.WithStack(err)
+			}
+		}
+	}
+	return nil
+}
+
+// Add stores the pair under the operationId and name, replacing a pair of
+// the same name.
+func (r ExampleStore) Add(operationID string, name string, pair *ExamplePair) {
+	if r[operationID] == nil {
+		r[operationID] = map[string]*ExamplePair{}
+	}
+	r[operationID][name] = pair
+}
+
+// Lookup returns the pair stored under the operationId and name.
+func (r ExampleStore) Lookup(operationID string, name string) (*ExamplePair, bool) {
+	pair, ok := r[operationID][name]
+	return pair, ok
+}
+
+// AuditExamples reports the example pairs of the store which do not match
+// the document: pairs of unknown operations, responses with status codes
+// the operation does not declare and bodies of undeclared media types.
+func (r OpenAPI) AuditExamples(store ExampleStore) []*Finding {
+	operations := map[string]pathOperation{}
+	for _, op := range r.Paths.operations() {
+		if op.operation.OperationID != "" {
+			operations[op.operation.OperationID] = op
+		}
+	}
+
+	findings := make([]*Finding, 0)
+	for _, operationID := range sortedStrings(store) {
+		op, ok := operations[operationID]
+		if !ok {
+			findings = append(findings, &Finding{
+				Pointer:  "/paths",
+				Rule:     RuleExampleUnknownOperation,
+				Severity: SeverityError,
+				Message:  fmt.Sprintf("examples of unknown operation %q", operationID),
+			})
+			continue
+		}
+		ptr := join("/paths", op.path, op.method)
+		for _, name := range sortedStrings(store[operationID]) {
+			pair := store[operationID][name]
+			if pair.Request != nil && pair.Request.Body != nil {
+				_, body := NewResolver(&r).requestBody("", op.operation.RequestBody)
+				if body == nil || !declaresMediaType(body.Content, exampleMediaType(pair.Request.MediaType)) {
+					findings = append(findings, &Finding{
+						Pointer:  join(ptr, "requestBody"),
+						Rule:     RuleExampleUndeclaredMediaType,
+						Severity: SeverityError,
+						Message:  fmt.Sprintf("example %q sends an undeclared %s request body", name, exampleMediaType(pair.Request.MediaType)),
+					})
+				}
+			}
+			if pair.Response == nil {
+				continue
+			}
+			_, response := NewResolver(&r).response("", declaredResponse(op.operation.Responses, pair.Response.Status))
+			switch {
+			case response == nil:
+				findings = append(findings, &Finding{
+					Pointer:  join(ptr, "responses"),
+					Rule:     RuleExampleUndeclaredResponse,
+					Severity: SeverityError,
+					Message:  fmt.Sprintf("example %q answers with undeclared status %d", name, pair.Response.Status),
+				})
+			case pair.Response.Body != nil && !declaresMediaType(response.Content, exampleMediaType(pair.Response.MediaType)):
+				findings = append(findings, &Finding{
+					Pointer:  join(ptr, "responses"),
+					Rule:     RuleExampleUndeclaredMediaType,
+					Severity: SeverityError,
+					Message:  fmt.Sprintf("example %q answers with an undeclared %s body", name, exampleMediaType(pair.Response.MediaType)),
+				})
+			}
+		}
+	}
+	sortFindings(findings)
+	return findings
+}
+
+// ApplyExamples copies the bodies of the example pairs of the store into
+// the examples of the media types of the operations, under the name of the
+// pair, so they are part of the rendered documentation. Media types holding
+// a single example are left untouched since example and examples are
+// mutually exclusive. Pairs which do not match the document are ignored.
+func (r *OpenAPI) ApplyExamples(store ExampleStore) {
+	for _, op := range r.Paths.operations() {
+		pairs := store[op.operation.OperationID]
+		if op.operation.OperationID == "" || len(pairs) == 0 {
+			continue
+		}
+		for _, name := range sortedStrings(pairs) {
+			pair := pairs[name]
+			if pair.Request != nil && pair.Request.Body != nil {
+				if _, body := NewResolver(r).requestBody("", op.operation.RequestBody); body != nil {
+					applyExample(body.Content, name, pair.Summary, pair.Request.MediaType, pair.Request.Body)
+				}
+			}
+			if pair.Response != nil && pair.Response.Body != nil {
+				_, response := NewResolver(r).response("", declaredResponse(op.operation.Responses, pair.Response.Status))
+				if response != nil {
+					applyExample(response.Content, name, pair.Summary, pair.Response.MediaType, pair.Response.Body)
+				}
+			}
+		}
+	}
+}
+
+// applyExample sets the named example of the media type of the content.
+func applyExample(content map[string]*MediaType, name string, summary string, mediaType string, value interface{}) {
+	media := content[exampleMediaType(mediaType)]
+	if media == nil || media.Example != nil {
+		return
+	}
+	if media.Examples == nil {
+		media.Examples = map[string]*Example{}
+	}
+	media.Examples[name] = &Example{Summary: summary, Value: value}
+}
+
+// exampleMediaType returns the media type of an example body, defaulting to
+// JSON.
+func exampleMediaType(mediaType string) string {
+	if mediaType == "" {
+		return "application/json"
+	}
+	return mediaType
+}
@@ -0,0 +1,165 @@
+package oas
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+)
+
+type ExampleStoreSuite struct {
+	suite.Suite
+}
+
+// exampleStorePatch is the patch of the petstore fixture the suite audits.
+const exampleStorePatch = `
+paths:
+  /pets:
+    post:
+      responses:
+        "201":
+          content:
+            application/json: {}
+        default:
+          $ref: "#/components/responses/Error"
+components:
+  responses:
+    Error:
+      description: An error.
+      content:
+        application/json:
+          example: error
+`
+
+func (r *ExampleStoreSuite) store() ExampleStore {
+	store := ExampleStore{}
+	store.Add("createPet", "created", &ExamplePair{
+		Summary: "A created pet.",
+		Request: &ExampleRequest{
+			Headers: map[string]string{"X-Request-Id": "1"},
+			Body:    map[string]interface{}{"name": "Rex"},
+		},
+		Response: &ExampleResponse{
+			Status: 201,
+			Body:   map[string]interface{}{"id": float64(1), "name": "Rex"},
+		},
+	})
+	store.Add("createPet", "conflict", &ExamplePair{
+		Response: &ExampleResponse{Status: 409, Body: map[string]interface{}{"code": "conflict"}},
+	})
+	return store
+}
+
+func (r *ExampleStoreSuite) TestLoadSave() {
+	dir, err := ioutil.TempDir("", "examples")
+	assert.Nil(r.T(), err)
+	defer os.RemoveAll(dir)
+
+	assert.Nil(r.T(), r.store().Save(dir))
+	assert.Nil(r.T(), ioutil.WriteFile(filepath.Join(dir, "createPet", "README.md"), []byte("notes"), 0644))
+	assert.Nil(r.T(), os.MkdirAll(filepath.Join(dir, "listPets"), 0755))
+	yaml := "summary: No pets.\nresponse:\n  status: 200\n  body:\n    items: []\n"
+	assert.Nil(r.T(), ioutil.WriteFile(filepath.Join(dir, "listPets", "empty.yaml"), []byte(yaml), 0644))
+
+	store, err := LoadExampleStore(dir)
+	assert.Nil(r.T(), err)
+	expected := r.store()
+	expected.Add("listPets", "empty", &ExamplePair{
+		Summary:  "No pets.",
+		Response: &ExampleResponse{Status: 200, Body: map[string]interface{}{"items": []interface{}{}}},
+	})
+	assert.Equal(r.T(), expected, store)
+
+	pair, ok := store.Lookup("listPets", "empty")
+	assert.True(r.T(), ok)
+	assert.Equal(r.T(), 200, pair.Response.Status)
+
+	_, err = LoadExampleStore(filepath.Join(dir, "missing"))
+	assert.NotNil(r.T(), err)
+	assert.NotNil(r.T(), ExampleStore{"../pets": {}}.Save(dir))
+}
+
+func (r *ExampleStoreSuite) TestAuditExamples() {
+	store := r.store()
+	store.Add("createPet", "text", &ExamplePair{
+		Request:  &ExampleRequest{MediaType: "text/plain", Body: "Rex"},
+		Response: &ExampleResponse{Status: 201, MediaType: "text/plain", Body: "1"},
+	})
+	store.Add("updatePet", "renamed", &ExamplePair{})
+
+	testCases := []struct {
+		patch    string
+		store    ExampleStore
+		expected []*Finding
+	}{
+		{"", store, []*Finding{
+			{
+				Pointer:  "/paths",
+				Rule:     RuleExampleUnknownOperation,
+				Severity: SeverityError,
+				Message:  `examples of unknown operation "updatePet"`,
+			},
+			{
+				Pointer:  "/paths/~1pets/post/requestBody",
+				Rule:     RuleExampleUndeclaredMediaType,
+				Severity: SeverityError,
+				Message:  `example "text" sends an undeclared text/plain request body`,
+			},
+			{
+				Pointer:  "/paths/~1pets/post/responses",
+				Rule:     RuleExampleUndeclaredMediaType,
+				Severity: SeverityError,
+				Message:  `example "text" answers with an undeclared text/plain body`,
+			},
+		}},
+		{`
+paths:
+  /pets:
+    post:
+      responses:
+        "201":
+          content: null
+        default: null
+`, r.store(), []*Finding{
+			{
+				Pointer:  "/paths/~1pets/post/responses",
+				Rule:     RuleExampleUndeclaredMediaType,
+				Severity: SeverityError,
+				Message:  `example "created" answers with an undeclared application/json body`,
+			},
+			{
+				Pointer:  "/paths/~1pets/post/responses",
+				Rule:     RuleExampleUndeclaredResponse,
+				Severity: SeverityError,
+				Message:  `example "conflict" answers with undeclared status 409`,
+			},
+		}},
+	}
+
+	for i, testCase := range testCases {
+		failMsg := "test case %d failed"
+		doc := petstore(r.T(), exampleStorePatch, testCase.patch)
+		assert.Equal(r.T(), testCase.expected, doc.AuditExamples(testCase.store), failMsg, i)
+	}
+}
+
+func (r *ExampleStoreSuite) TestApplyExamples() {
+	doc := petstore(r.T(), exampleStorePatch)
+	assert.Nil(r.T(), ApplyExamplesTransform(r.store())(doc))
+
+	post := doc.Paths.PathItems.Get("/pets").Post
+	assert.Equal(r.T(), map[string]*Example{
+		"created": {Summary: "A created pet.", Value: map[string]interface{}{"name": "Rex"}},
+	}, post.RequestBody.Content["application/json"].Examples)
+	assert.Equal(r.T(), map[string]*Example{
+		"created": {Summary: "A created pet.", Value: map[string]interface{}{"id": float64(1), "name": "Rex"}},
+	}, post.Responses.Get("201").Content["application/json"].Examples)
+	assert.Nil(r.T(), doc.Components.Responses["Error"].Content["application/json"].Examples)
+}
+
+func TestExampleStoreSuite(t *testing.T) {
+	suite.Run(t, new(ExampleStoreSuite))
+}
@@ -0,0 +1,115 @@
+package oas
+
+import (
+	"mime"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+)
+
+// ResponseExpectation checks the response recorded by a handler test
+// against the operation of the document it was returned for, so table-driven
+// handler tests can assert conformance to the document in one line:
+//
+//	Expect(t, doc, "get", "/pets/{petId}", recorder).Status(200).JSONMatchingSchema()
+//
+// Failed checks are reported with Fatalf.
+type ResponseExpectation struct {
+	t        TestingT
+	doc      *OpenAPI
+	route    *Route
+	recorder *httptest.ResponseRecorder
+}
+
+// Expect returns the expectation of the response held by the recorder for
+// the operation declared with the HTTP method and the path template. The
+// test fails if the document declares no such operation.
+func Expect(t TestingT, doc *OpenAPI, method string, path string, recorder *httptest.ResponseRecorder) *ResponseExpectation {
+	t.Helper()
+	method = strings.ToLower(method)
+	e := &ResponseExpectation{t: t, doc: doc, recorder: recorder}
+	if item := doc.Paths.PathItems.Get(path); item != nil {
+		for _, op := range item.operations() {
+			if op.method == method {
+				e.route = &Route{Path: path, Method: method, PathItem: item, Operation: op.operation}
+			}
+		}
+	}
+	if e.route == nil {
+		t.Fatalf("%s %s: operation not declared", strings.ToUpper(method), path)
+	}
+	return e
+}
+
+// Status checks that the response has the status code and that the
+// operation declares the status code and the content type of the response,
+// as a ResponseEnforcer would.
+func (e *ResponseExpectation) Status(status int) *ResponseExpectation {
+	e.t.Helper()
+	if e.route == nil {
+		return e
+	}
+	if e.recorder.Code != status {
+		e.t.Fatalf("%s: status %d, expected %d", e.operation(), e.recorder.Code, status)
+		return e
+	}
+
+	var undeclared error
+	enforcer := ResponseEnforcer{
+		Doc:    e.doc,
+		Policy: WarnUndeclaredResponses,
+		Report: func(req *http.Request, err error) { undeclared = err },
+	}
+	w := enforcer.Wrap(httptest.NewRecorder(), nil, e.route)
+	for key, values := range e.recorder.Header() {
+		w.Header()[key] = values
+	}
+	w.WriteHeader(e.recorder.Code)
+	if undeclared != nil {
+		e.t.Fatalf("%v", undeclared)
+	}
+	return e
+}
+
+// JSONMatchingSchema checks that the body of the response is JSON and
+// conforms to the schema the operation declares for the status code and the
+// content type of the response.
+func (e *ResponseExpectation) JSONMatchingSchema() *ResponseExpectation {
+	e.t.Helper()
+	if e.route == nil {
+		return e
+	}
+	response := declaredResponse(e.route.Operation.Responses, e.recorder.Code)
+	_, response = NewResolver(e.doc).response("", response)
+	if response == nil {
+		e.t.Fatalf("%s: undeclared status %d", e.operation(), e.recorder.Code)
+		return e
+	}
+
+	contentType := e.recorder.Header().Get("Content-Type")
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		mediaType = contentType
+	}
+	declared, ok := declaredMediaType(response.Content, mediaType)
+	if !ok || declared == nil || declared.Schema == nil {
+		e.t.Fatalf("%s: no schema declared for content type %q of status %d", e.operation(), mediaType, e.recorder.Code)
+		return e
+	}
+
+	var value interface{}
+	if err := decodeJSON(e.recorder.Body.Bytes(), &value); err != nil {
+		e.t.Fatalf("%s: invalid JSON body: %v", e.operation(), err)
+		return e
+	}
+	if err := ValidatePayload(e.doc, declared.Schema, value); err != nil {
+		e.t.Fatalf("%s: body does not match the schema: %v", e.operation(), err)
+	}
+	return e
+}
+
+// operation returns the method and path template of the operation, as used
+// in failure messages.
+func (e *ResponseExpectation) operation() string {
+	return strings.ToUpper(e.route.Method) + " " + e.route.Path
+}
@@ -0,0 +1,98 @@
+package oas
+
+import (
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+)
+
+type ExpectSuite struct {
+	suite.Suite
+}
+
+func (r *ExpectSuite) TestExpect() {
+	doc, err := LoadReader(strings.NewReader(`openapi: 3.0.3
+info:
+  title: Pets
+  version: 1.0.0
+paths:
+  /pets/{petId}:
+    get:
+      responses:
+        "200":
+          description: A pet.
+          content:
+            application/json:
+              schema:
+                $ref: "#/components/schemas/Pet"
+        "404":
+          $ref: "#/components/responses/NotFound"
+components:
+  schemas:
+    Pet:
+      type: object
+      required: [id]
+      properties:
+        id:
+          type: integer
+  responses:
+    NotFound:
+      description: Not found.
+`))
+	if !assert.Nil(r.T(), err) {
+		return
+	}
+
+	testCases := []struct {
+		method      string
+		path        string
+		expect      int
+		status      int
+		contentType string
+		body        string
+		expected    []string
+	}{
+		{"GET", "/pets/{petId}", 200, 200, "application/json; charset=utf-8", `{"id": 1}`, nil},
+		{"get", "/pets/{petId}", 404, 404, "", "", []string{
+			`GET /pets/{petId}: no schema declared for content type "" of status 404`,
+		}},
+		{"get", "/pets/{petId}", 200, 200, "application/json", `{"id": "1"}`, []string{
+			"GET /pets/{petId}: body does not match the schema: /id: value must be of type integer, not string",
+		}},
+		{"get", "/pets/{petId}", 200, 200, "application/json", `{"id":`, []string{
+			"GET /pets/{petId}: invalid JSON body: EOF",
+		}},
+		{"get", "/pets/{petId}", 200, 200, "text/plain", "pet", []string{
+			`GET /pets/{petId}: undeclared content type "text/plain" for status 200`,
+			`GET /pets/{petId}: no schema declared for content type "text/plain" of status 200`,
+		}},
+		{"get", "/pets/{petId}", 200, 500, "", "", []string{
+			"GET /pets/{petId}: status 500, expected 200",
+			"GET /pets/{petId}: undeclared status 500",
+		}},
+		{"delete", "/pets/{petId}", 200, 200, "", "", []string{
+			"DELETE /pets/{petId}: operation not declared",
+		}},
+	}
+
+	failMsg := "test case %d failed"
+	for i, testCase := range testCases {
+		recorder := httptest.NewRecorder()
+		if testCase.contentType != "" {
+			recorder.Header().Set("Content-Type", testCase.contentType)
+		}
+		recorder.WriteHeader(testCase.status)
+		recorder.WriteString(testCase.body)
+
+		t := &fakeT{}
+		Expect(t, doc, testCase.method, testCase.path, recorder).Status(testCase.expect).JSONMatchingSchema()
+		assert.Equal(r.T(), testCase.expected, t.failures, failMsg, i)
+	}
+}
+
+func TestExpectSuite(t *testing.T) {
+	suite.Run(t, new(ExpectSuite))
+}
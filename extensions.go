@@ -2,11 +2,10 @@ package oas
 
 import (
 	"encoding/json"
-	"fmt"
 	"strings"
 
 	"github.com/pkg/errors"
-	"gopkg.in/yaml.v2"
+	"gopkg.in/yaml.v3"
 )
 
 // Extensions defines the Specification Extensions collection.
@@ -23,22 +22,7 @@ func (r Extensions) MarshalJSON() ([]byte, error) {
 
 // UnmarshalJSON parses the JSON-encoded data and stores the result.
 func (r *Extensions) UnmarshalJSON(data []byte) error {
-	return r.UnmarshalYAML(func(in interface{}) error {
-		obj := make(map[string]interface{})
-		if err := json.Unmarshal(data, &obj); err != nil {
-			return errors.WithStack(err)
-		}
-
-		rbytes, err := yaml.Marshal(obj)
-		if err != nil {
-			return errors.WithStack(err)
-		}
-
-		if err := yaml.Unmarshal(rbytes, in); err != nil {
-			return errors.WithStack(err)
-		}
-		return nil
-	})
+	return decodeJSON(data, r)
 }
 
 // MarshalYAML returns the YAML encoding.
@@ -53,42 +37,45 @@ func (r Extensions) MarshalYAML() (interface{}, error) {
 }
 
 // UnmarshalYAML parses the YAML-encoded data and stores the result.
-func (r *Extensions) UnmarshalYAML(unmarshal func(interface{}) error) error {
+func (r *Extensions) UnmarshalYAML(node *yaml.Node) error {
+	return decodeYAML(node, r)
+}
+
+// unmarshalValue stores the result of the unmarshal function, which decodes
+// either JSON or YAML data.
+func (r *Extensions) unmarshalValue(d *valueDecoder, unmarshal func(interface{}) error) error {
 	obj := make(map[string]interface{})
 	if err := unmarshal(&obj); err != nil {
 		return errors.WithStack(err)
 	}
 	for k := range obj {
 		if strings.HasPrefix(strings.ToLower(k), "x-") {
-			(*r)[k] = cleanupMapValue(obj[k])
+			(*r)[k] = obj[k]
 		}
 	}
 	return nil
 }
 
-func cleanupMapValue(v interface{}) interface{} {
-	switch value := v.(type) {
-	case []interface{}:
-		return cleanupInterfaceArray(value)
-	case map[interface{}]interface{}:
-		return cleanupInterfaceMap(value)
-	default:
-		return value
-	}
+// stringValue returns the value of the extension if it holds a string.
+func (r Extensions) stringValue(key string) (string, bool) {
+	value, ok := r[key].(string)
+	return value, ok
 }
 
-func cleanupInterfaceArray(in []interface{}) []interface{} {
-	res := make([]interface{}, len(in))
-	for i, value := range in {
-		res[i] = cleanupMapValue(value)
+// decode stores the value of the extension in the value pointed to by out.
+// It reports whether the extension is present. Values set programmatically
+// may hold arbitrary types and are converted through their JSON encoding.
+func (r Extensions) decode(key string, out interface{}) (bool, error) {
+	value, ok := r[key]
+	if !ok {
+		return false, nil
 	}
-	return res
-}
-
-func cleanupInterfaceMap(in map[interface{}]interface{}) map[string]interface{} {
-	res := make(map[string]interface{})
-	for k, v := range in {
-		res[fmt.Sprintf("%v", k)] = cleanupMapValue(v)
+	rbytes, err := json.Marshal(value)
+	if err != nil {
+		return true, errors.Wrap(err, key)
+	}
+	if err := decodeJSON(rbytes, out); err != nil {
+		return true, errors.Wrap(err, key)
 	}
-	return res
+	return true, nil
 }
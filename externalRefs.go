@@ -0,0 +1,323 @@
+package oas
+
+import (
+	"context"
+	"io/ioutil"
+	"path"
+	"path/filepath"
+	"reflect"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/pkg/errors"
+	"gopkg.in/yaml.v3"
+)
+
+// ErrRefOutsideRoot is returned by DirRefLoader for locations outside of its
+// root directory.
+var ErrRefOutsideRoot = errors.New("reference outside of the root directory")
+
+// componentNameInvalid matches the characters which are not allowed in the
+// names of reusable components.
+var componentNameInvalid = regexp.MustCompile(`[^a-zA-Z0-9.\-_]`)
+
+// RefLoader reads the files external references point to, so callers
+// control which files and URLs a document may pull in.
+type RefLoader interface {
+	// LoadRef returns the content of the file at the location, a path or
+	// an http or https URL resolved against the location of the document
+	// holding the reference.
+	LoadRef(ctx context.Context, location string) ([]byte, error)
+}
+
+// RefLoaderFunc adapts a function to the RefLoader interface.
+type RefLoaderFunc func(ctx context.Context, location string) ([]byte, error)
+
+// LoadRef calls the function.
+func (f RefLoaderFunc) LoadRef(ctx context.Context, location string) ([]byte, error) {
+	return f(ctx, location)
+}
+
+// DirRefLoader reads files from the file system, refusing URLs and files
+// outside of the root directory.
+type DirRefLoader struct {
+	// Root describes the directory the files are read from.
+	Root string
+}
+
+// LoadRef reads the file at the location if it is inside the root
+// directory.
+func (r DirRefLoader) LoadRef(ctx context.Context, location string) ([]byte, error) {
+	if isURL(location) {
+		return nil, errors.Wrap(ErrRefOutsideRoot, location)
+	}
+	root, err := filepath.Abs(r.Root)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+	file, err := filepath.Abs(filepath.FromSlash(location))
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+	rel, err := filepath.Rel(root, file)
+	if err != nil || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+		return nil, errors.Wrap(ErrRefOutsideRoot, location)
+	}
+	data, err := ioutil.ReadFile(file)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+	return data, nil
+}
+
+// LoadBundle reads the document and the files it references with the
+// default loader.
+func LoadBundle(ctx context.Context, location string) (*OpenAPI, error) {
+	return Loader{}.LoadBundle(ctx, location)
+}
+
+// LoadBundle reads the document at the location with the RefLoader of the
+// loader, or ReadLocation when it has none, and bundles the files it
+// references into it as ResolveExternalRefs does.
+func (r Loader) LoadBundle(ctx context.Context, location string) (*OpenAPI, error) {
+	loader := r.RefLoader
+	if loader == nil {
+		loader = RefLoaderFunc(ReadLocation)
+	}
+	data, err := loader.LoadRef(ctx, location)
+	if err != nil {
+		return nil, &LoadError{Location: location, Err: err}
+	}
+	doc, err := r.Load(location, data)
+	if err != nil {
+		return nil, err
+	}
+	if err := doc.ResolveExternalRefs(ctx, location, loader); err != nil {
+		return nil, &LoadError{Location: location, Err: err}
+	}
+	return doc, nil
+}
+
+// ResolveExternalRefs bundles the files referenced by the document read from
+// the location into it, so every reference becomes local (e.g.
+// "./schemas/pet.yaml#/Pet" becomes "#/components/schemas/Pet"). Relative
+// references are resolved against the file holding them and files are read
+// with the loader. Every referenced object is added once to the components
+// of its kind, named after the last token of the fragment or after the file
+// when the reference has no fragment, with a numeric suffix when the name is
+// taken. Path items, which have no components, are copied in place of the
+// referencing path item. References of the bundled objects are resolved in
+// turn, so files may reference each other and themselves. On failure the
+// document is left partially bundled.
+func (r *OpenAPI) ResolveExternalRefs(ctx context.Context, location string, loader RefLoader) error {
+	if location != "" && !isURL(location) {
+		location = path.Clean(filepath.ToSlash(location))
+	}
+	b := &refBundler{
+		ctx:      ctx,
+		loader:   loader,
+		location: location,
+		doc:      r,
+		files:    map[string]*yaml.Node{},
+		bundled:  map[string]string{},
+	}
+
+	err := walk(r, func(ptr string, node interface{}) error {
+		if ref := refOf(node); ref != nil && *ref != "" {
+			*ref = rebaseRef(location, *ref)
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	for {
+		added := len(b.bundled)
+		if err := walk(r, b.node); err != nil {
+			return err
+		}
+		if len(b.bundled) == added {
+			return nil
+		}
+	}
+}
+
+// refBundler bundles the objects external references point to into a
+// document.
+type refBundler struct {
+	ctx      context.Context
+	loader   RefLoader
+	location string
+	doc      *OpenAPI
+	files    map[string]*yaml.Node
+	bundled  map[string]string
+}
+
+// node rewrites the reference of the node into a local reference, bundling
+// its target into the components of the document. References are expected
+// to be resolved against the file holding them.
+func (b *refBundler) node(ptr string, node interface{}) error {
+	if err := b.ctx.Err(); err != nil {
+		return errors.WithStack(err)
+	}
+	ref := refOf(node)
+	if ref == nil || *ref == "" || b.local(*ref) {
+		if ref != nil && *ref != "" {
+			_, fragment := splitRef(*ref)
+			*ref = "#" + fragment
+		}
+		return nil
+	}
+
+	if item, ok := node.(*PathItem); ok {
+		return b.pathItem(item)
+	}
+	kind, ok := componentKind(node)
+	if !ok {
+		return nil
+	}
+	if bundled, ok := b.bundled[*ref]; ok {
+		*ref = bundled
+		return nil
+	}
+
+	target := reflect.New(reflect.TypeOf(node).Elem()).Interface()
+	if err := b.load(*ref, target); err != nil {
+		return err
+	}
+	name := b.name(kind, *ref)
+	components := reflect.ValueOf(b.doc.Components).Elem().FieldByName(strings.ToUpper(kind[:1]) + kind[1:])
+	if components.IsNil() {
+		components.Set(reflect.MakeMap(components.Type()))
+	}
+	components.SetMapIndex(reflect.ValueOf(name), reflect.ValueOf(target))
+	b.bundled[*ref] = join("#/components", kind, name)
+	*ref = b.bundled[*ref]
+	return nil
+}
+
+// pathItem replaces the path item with the one its reference points to.
+func (b *refBundler) pathItem(item *PathItem) error {
+	visited := map[string]bool{}
+	for item.Ref != "" && !b.local(item.Ref) {
+		if visited[item.Ref] {
+			return errors.Wrapf(ErrRefCycle, "%q", item.Ref)
+		}
+		visited[item.Ref] = true
+		target := &PathItem{}
+		if err := b.load(item.Ref, target); err != nil {
+			return err
+		}
+		*item = *target
+	}
+	if item.Ref != "" {
+		_, fragment := splitRef(item.Ref)
+		item.Ref = "#" + fragment
+	}
+	return nil
+}
+
+// local reports whether the reference points into the document.
+func (b *refBundler) local(ref string) bool {
+	file, _ := splitRef(ref)
+	return file == "" || file == b.location
+}
+
+// load decodes the object the reference points to into out, resolving its
+// references against the file holding it.
+func (b *refBundler) load(ref string, out interface{}) error {
+	file, fragment := splitRef(ref)
+	content, ok := b.files[file]
+	if !ok {
+		data, err := b.loader.LoadRef(b.ctx, file)
+		if err != nil {
+			return errors.Wrapf(err, "resolving %q", ref)
+		}
+		content = &yaml.Node{}
+		if err := yaml.Unmarshal(data, content); err != nil {
+			return errors.Wrapf(err, "resolving %q", ref)
+		}
+		b.files[file] = content
+	}
+
+	target, err := pointerNode(content, fragment)
+	if err != nil {
+		return errors.Wrapf(err, "resolving %q", ref)
+	}
+	data, err := yaml.Marshal(blockStyle(target))
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	return errors.Wrapf(DecodeFragment(file, data, out), "resolving %q", ref)
+}
+
+// name returns an unused name for the component of the kind the reference
+// points to.
+func (b *refBundler) name(kind string, ref string) string {
+	file, fragment := splitRef(ref)
+	name := strings.TrimSuffix(path.Base(file), path.Ext(file))
+	if tokens := strings.Split(fragment, "/"); tokens[len(tokens)-1] != "" {
+		name = unescapePointer(tokens[len(tokens)-1])
+	}
+	name = componentNameInvalid.ReplaceAllString(name, "_")
+
+	if b.doc.Components == nil {
+		b.doc.Components = &Components{}
+	}
+	resolver := NewResolver(b.doc)
+	unique := name
+	for i := 2; resolver.component(kind, unique) != nil; i++ {
+		unique = name + strconv.Itoa(i)
+	}
+	return unique
+}
+
+// pointerNode returns the node the JSON Pointer points to inside the
+// decoded content of a file.
+func pointerNode(node *yaml.Node, pointer string) (*yaml.Node, error) {
+	if node.Kind == yaml.DocumentNode && len(node.Content) > 0 {
+		node = node.Content[0]
+	}
+	if pointer == "" {
+		return node, nil
+	}
+	if !strings.HasPrefix(pointer, "/") {
+		return nil, errors.Errorf("invalid pointer %q", pointer)
+	}
+	for _, token := range strings.Split(pointer[1:], "/") {
+		token = unescapePointer(token)
+		var next *yaml.Node
+		switch node.Kind {
+		case yaml.MappingNode:
+			for i := 0; i+1 < len(node.Content); i += 2 {
+				if node.Content[i].Value == token {
+					next = node.Content[i+1]
+					break
+				}
+			}
+		case yaml.SequenceNode:
+			if i, err := strconv.Atoi(token); err == nil && i >= 0 && i < len(node.Content) {
+				next = node.Content[i]
+			}
+		}
+		if next == nil {
+			return nil, errors.Errorf("%q not found", pointer)
+		}
+		node = next
+	}
+	return node, nil
+}
+
+// blockStyle returns a copy of the node whose mappings and sequences are
+// written in block style, so content read from JSON files is not encoded
+// as YAML flow collections which decodeDocument would take for JSON.
+func blockStyle(node *yaml.Node) *yaml.Node {
+	copied := *node
+	copied.Style &^= yaml.FlowStyle
+	copied.Content = make([]*yaml.Node, len(node.Content))
+	for i, child := range node.Content {
+		copied.Content[i] = blockStyle(child)
+	}
+	return &copied
+}
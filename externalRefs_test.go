@@ -0,0 +1,202 @@
+package oas
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+)
+
+type ExternalRefsSuite struct {
+	suite.Suite
+}
+
+// files returns the files of a document split across a directory.
+func (r *ExternalRefsSuite) files() map[string]string {
+	return map[string]string{
+		"api/openapi.yaml": `openapi: 3.0.3
+info:
+  title: Pets
+  version: 1.0.0
+paths:
+  /pets:
+    $ref: ./paths/pets.yaml
+components:
+  schemas:
+    Tag:
+      type: string
+    Error:
+      $ref: ../common.yaml#/components/schemas/Error
+`,
+		"api/paths/pets.yaml": `get:
+  operationId: listPets
+  parameters:
+    - $ref: ../parameters/limit.json
+  responses:
+    200:
+      description: pets
+      content:
+        application/json:
+          schema:
+            type: array
+            items:
+              $ref: ../schemas/pet.yaml#/Pet
+    default:
+      description: error
+      content:
+        application/json:
+          schema:
+            $ref: ../openapi.yaml#/components/schemas/Error
+`,
+		"api/parameters/limit.json": `{"name": "limit", "in": "query", "schema": {"type": "integer"}}`,
+		"api/schemas/pet.yaml": `Pet:
+  type: object
+  properties:
+    tag:
+      $ref: '#/Tag'
+    parent:
+      $ref: '#/Pet'
+Tag:
+  type: object
+`,
+		"common.yaml": `components:
+  schemas:
+    Error:
+      type: object
+      properties:
+        message:
+          type: string
+`,
+	}
+}
+
+// write writes the files into a temporary directory and returns it.
+func (r *ExternalRefsSuite) write(files map[string]string) string {
+	dir, err := ioutil.TempDir("", "oas-refs")
+	r.Require().Nil(err)
+	for name, content := range files {
+		file := filepath.Join(dir, filepath.FromSlash(name))
+		r.Require().Nil(os.MkdirAll(filepath.Dir(file), 0755))
+		r.Require().Nil(ioutil.WriteFile(file, []byte(content), 0644))
+	}
+	return dir
+}
+
+func (r *ExternalRefsSuite) TestLoadBundle() {
+	dir := r.write(r.files())
+	defer os.RemoveAll(dir)
+
+	doc, err := Loader{RefLoader: DirRefLoader{Root: dir}}.LoadBundle(
+		context.Background(), filepath.Join(dir, "api", "openapi.yaml"))
+	if !assert.Nil(r.T(), err) {
+		return
+	}
+
+	refs := map[string]string{}
+	_ = walk(doc, func(ptr string, node interface{}) error {
+		if ref := refOf(node); ref != nil && *ref != "" {
+			refs[ptr] = *ref
+		}
+		return nil
+	})
+	assert.Equal(r.T(), map[string]string{
+		"/paths/~1pets/get/parameters/0":                                         "#/components/parameters/limit",
+		"/paths/~1pets/get/responses/200/content/application~1json/schema/items": "#/components/schemas/Pet",
+		"/paths/~1pets/get/responses/default/content/application~1json/schema":   "#/components/schemas/Error",
+		"/components/schemas/Error":                                              "#/components/schemas/Error2",
+		"/components/schemas/Pet/properties/parent":                              "#/components/schemas/Pet",
+		"/components/schemas/Pet/properties/tag":                                 "#/components/schemas/Tag2",
+	}, refs)
+
+	resolver := NewResolver(doc)
+	schema, err := resolver.ResolveSchema("#/components/schemas/Error")
+	if assert.Nil(r.T(), err) {
+		assert.Equal(r.T(), "string", schema.Properties.Get("message").Type)
+	}
+	schema, err = resolver.ResolveSchema("#/components/schemas/Tag2")
+	if assert.Nil(r.T(), err) {
+		assert.Equal(r.T(), "object", schema.Type)
+	}
+	parameter, err := resolver.ResolveParameter("#/components/parameters/limit")
+	if assert.Nil(r.T(), err) {
+		assert.Equal(r.T(), "query", parameter.In)
+	}
+	assert.Equal(r.T(), "listPets", doc.Paths.PathItems.Get("/pets").Get.OperationID)
+	assert.Nil(r.T(), doc.Validate(context.Background()))
+}
+
+func (r *ExternalRefsSuite) TestLoadBundleFailure() {
+	testCases := []struct {
+		files map[string]string
+		err   error
+	}{
+		{
+			map[string]string{
+				"api/openapi.yaml": "openapi: 3.0.3\npaths:\n  /pets:\n    $ref: ../secret.yaml\n",
+				"secret.yaml":      "get: {}\n",
+			},
+			ErrRefOutsideRoot,
+		},
+		{
+			map[string]string{
+				"api/openapi.yaml": "openapi: 3.0.3\npaths:\n  /pets:\n    $ref: a.yaml\n",
+				"api/a.yaml":       "$ref: b.yaml\n",
+				"api/b.yaml":       "$ref: a.yaml\n",
+			},
+			ErrRefCycle,
+		},
+		{
+			map[string]string{
+				"api/openapi.yaml": "openapi: 3.0.3\ncomponents:\n  schemas:\n    Pet:\n      $ref: pet.yaml#/Pet\n",
+				"api/pet.yaml":     "Dog:\n  type: object\n",
+			},
+			nil,
+		},
+	}
+
+	for i, testCase := range testCases {
+		failMsg := "test case %d failed"
+		dir := r.write(testCase.files)
+		_, err := Loader{RefLoader: DirRefLoader{Root: filepath.Join(dir, "api")}}.LoadBundle(
+			context.Background(), filepath.Join(dir, "api", "openapi.yaml"))
+		os.RemoveAll(dir)
+
+		if assert.IsType(r.T(), &LoadError{}, err, failMsg, i) && testCase.err != nil {
+			assert.Equal(r.T(), testCase.err, errors.Cause(err), failMsg, i)
+		}
+	}
+}
+
+func (r *ExternalRefsSuite) TestResolveExternalRefs() {
+	files := map[string]string{
+		"schemas/pet.yaml": "type: object\nproperties:\n  name:\n    type: string\n",
+	}
+	loader := RefLoaderFunc(func(ctx context.Context, location string) ([]byte, error) {
+		if content, ok := files[location]; ok {
+			return []byte(content), nil
+		}
+		return nil, os.ErrNotExist
+	})
+
+	doc := &OpenAPI{
+		Components: &Components{Schemas: map[string]*Schema{
+			"Pets": {Type: "array", Items: &Schema{Ref: "./schemas/pet.yaml"}},
+			"Pet":  {Ref: "schemas/pet.yaml"},
+			"Self": {Ref: "#/components/schemas/Pets"},
+		}},
+	}
+	assert.Nil(r.T(), doc.ResolveExternalRefs(context.Background(), "openapi.yaml", loader))
+	assert.Equal(r.T(), "#/components/schemas/pet", doc.Components.Schemas["Pets"].Items.Ref)
+	assert.Equal(r.T(), "#/components/schemas/pet", doc.Components.Schemas["Pet"].Ref)
+	assert.Equal(r.T(), "#/components/schemas/Pets", doc.Components.Schemas["Self"].Ref)
+	assert.Equal(r.T(), "string", doc.Components.Schemas["pet"].Properties.Get("name").Type)
+}
+
+func TestExternalRefsSuite(t *testing.T) {
+	suite.Run(t, new(ExternalRefsSuite))
+}
@@ -0,0 +1,178 @@
+package oas
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"io/ioutil"
+	"mime"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/pkg/errors"
+	"gopkg.in/yaml.v3"
+)
+
+// DefaultExternalValueMaxSize is the maximum size in bytes of a fetched
+// example value when ExternalValueOptions does not specify one.
+const DefaultExternalValueMaxSize = 1 << 20
+
+// ExternalValueOptions describes the behavior of EmbedExternalValues.
+type ExternalValueOptions struct {
+	// Client describes the HTTP client used to fetch the example values.
+	// Defaults to http.DefaultClient.
+	Client *http.Client
+
+	// BaseURL describes the URL relative externalValue URLs are resolved
+	// against. Relative URLs are rejected when empty.
+	BaseURL string
+
+	// MaxSize describes the maximum size in bytes of a single example value.
+	// Defaults to DefaultExternalValueMaxSize.
+	MaxSize int64
+}
+
+// EmbedExternalValues fetches the externalValue URL of every example in the
+// document and embeds the fetched content as the example value, so published
+// documents do not depend on the availability of the URLs. JSON and YAML
+// content is decoded into structured values, other textual content is
+// embedded as a string. Examples whose content is binary are left untouched.
+// The media type is taken from the Content-Type response header, falling
+// back to the media type the example is declared under.
+func (r *OpenAPI) EmbedExternalValues(ctx context.Context, opts ExternalValueOptions) error {
+	if opts.Client == nil {
+		opts.Client = http.DefaultClient
+	}
+	if opts.MaxSize <= 0 {
+		opts.MaxSize = DefaultExternalValueMaxSize
+	}
+
+	var base *url.URL
+	if opts.BaseURL != "" {
+		var err error
+		if base, err = url.Parse(opts.BaseURL); err != nil {
+			return errors.WithStack(err)
+		}
+	}
+
+	return walk(r, func(ptr string, node interface{}) error {
+		example, ok := node.(*Example)
+		if !ok || example.ExternalValue == "" {
+			return nil
+		}
+
+		value, err := fetchExternalValue(ctx, opts, base, example.ExternalValue, enclosingMediaType(ptr))
+		if err != nil {
+			return errors.Wrapf(err, "%s: %s", ptr, example.ExternalValue)
+		}
+		if value != nil {
+			example.Value = value
+			example.ExternalValue = ""
+		}
+		return nil
+	})
+}
+
+// fetchExternalValue retrieves and decodes a single example value. A nil
+// value is returned for binary content.
+func fetchExternalValue(ctx context.Context, opts ExternalValueOptions, base *url.URL, rawurl string, fallback string) (interface{}, error) {
+	target, err := url.Parse(rawurl)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+	if !target.IsAbs() {
+		if base == nil {
+			return nil, errors.New("relative URL without a base URL")
+		}
+		target = base.ResolveReference(target)
+	}
+
+	req, err := http.NewRequest(http.MethodGet, target.String(), nil)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+
+	resp, err := opts.Client.Do(req.WithContext(ctx))
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return nil, errors.Errorf("unexpected status %s", resp.Status)
+	}
+
+	data, err := ioutil.ReadAll(io.LimitReader(resp.Body, opts.MaxSize+1))
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+	if int64(len(data)) > opts.MaxSize {
+		return nil, errors.Errorf("value exceeds %d bytes", opts.MaxSize)
+	}
+
+	mediaType := fallback
+	if value := resp.Header.Get("Content-Type"); value != "" {
+		if value, _, err := mime.ParseMediaType(value); err == nil && value != "application/octet-stream" {
+			mediaType = value
+		}
+	}
+
+	switch {
+	case isJSONMediaType(mediaType):
+		var value interface{}
+		if err := json.Unmarshal(data, &value); err != nil {
+			return nil, errors.WithStack(err)
+		}
+		return value, nil
+	case isYAMLMediaType(mediaType):
+		node := &yaml.Node{}
+		if err := yaml.Unmarshal(data, node); err != nil {
+			return nil, errors.WithStack(err)
+		}
+		return nodeValue(node)
+	case isTextMediaType(mediaType):
+		return string(data), nil
+	}
+	return nil, nil
+}
+
+// enclosingMediaType returns the media type key under which the node
+// addressed by the pointer is declared, if any.
+func enclosingMediaType(ptr string) string {
+	tokens := splitPointer(ptr)
+	for i := len(tokens) - 2; i >= 0; i-- {
+		if tokens[i] == "content" {
+			return tokens[i+1]
+		}
+	}
+	return ""
+}
+
+// isJSONMediaType reports whether the media type describes JSON content.
+func isJSONMediaType(mediaType string) bool {
+	mediaType = strings.ToLower(mediaType)
+	return mediaType == "application/json" || strings.HasSuffix(mediaType, "+json")
+}
+
+// isYAMLMediaType reports whether the media type describes YAML content.
+func isYAMLMediaType(mediaType string) bool {
+	switch strings.ToLower(mediaType) {
+	case "application/yaml", "application/x-yaml", "text/yaml", "text/x-yaml":
+		return true
+	}
+	return strings.HasSuffix(strings.ToLower(mediaType), "+yaml")
+}
+
+// isTextMediaType reports whether the media type describes textual content.
+func isTextMediaType(mediaType string) bool {
+	mediaType = strings.ToLower(mediaType)
+	switch {
+	case strings.HasPrefix(mediaType, "text/"),
+		mediaType == "application/xml",
+		strings.HasSuffix(mediaType, "+xml"),
+		mediaType == "application/x-www-form-urlencoded":
+		return true
+	}
+	return false
+}
@@ -0,0 +1,182 @@
+package oas
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+)
+
+type ExternalValueSuite struct {
+	suite.Suite
+	server *httptest.Server
+}
+
+func (r *ExternalValueSuite) SetupSuite() {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/pet.json", func(w http.ResponseWriter, req *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprint(w, `{"name": "Puma", "tags": ["cat"]}`)
+	})
+	mux.HandleFunc("/pet.yaml", func(w http.ResponseWriter, req *http.Request) {
+		w.Header().Set("Content-Type", "application/octet-stream")
+		fmt.Fprint(w, "name: Puma\n")
+	})
+	mux.HandleFunc("/pet.txt", func(w http.ResponseWriter, req *http.Request) {
+		w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+		fmt.Fprint(w, "Puma")
+	})
+	mux.HandleFunc("/pet.png", func(w http.ResponseWriter, req *http.Request) {
+		w.Header().Set("Content-Type", "image/png")
+		fmt.Fprint(w, "\x89PNG")
+	})
+	mux.HandleFunc("/big.txt", func(w http.ResponseWriter, req *http.Request) {
+		w.Header().Set("Content-Type", "text/plain")
+		fmt.Fprint(w, strings.Repeat("a", 64))
+	})
+	r.server = httptest.NewServer(mux)
+}
+
+func (r *ExternalValueSuite) TearDownSuite() {
+	r.server.Close()
+}
+
+func (r *ExternalValueSuite) TestEmbedExternalValues() {
+	document := func(mediaType string, externalValue string) *OpenAPI {
+		return &OpenAPI{
+			Components: &Components{
+				RequestBodies: map[string]*RequestBody{
+					"Pet": {
+						Content: map[string]*MediaType{
+							mediaType: {
+								Examples: map[string]*Example{
+									"puma": {Summary: "Puma", ExternalValue: externalValue},
+								},
+							},
+						},
+					},
+				},
+			},
+		}
+	}
+
+	testCases := []struct {
+		shouldFail bool
+		opts       ExternalValueOptions
+		input      *OpenAPI
+		expected   *OpenAPI
+	}{
+		{
+			false,
+			ExternalValueOptions{},
+			document("application/json", r.server.URL+"/pet.json"),
+			&OpenAPI{
+				Components: &Components{
+					RequestBodies: map[string]*RequestBody{
+						"Pet": {
+							Content: map[string]*MediaType{
+								"application/json": {
+									Examples: map[string]*Example{
+										"puma": {
+											Summary: "Puma",
+											Value: map[string]interface{}{
+												"name": "Puma",
+												"tags": []interface{}{"cat"},
+											},
+										},
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+		{
+			false,
+			ExternalValueOptions{BaseURL: r.server.URL + "/examples/"},
+			document("application/yaml", "../pet.yaml"),
+			&OpenAPI{
+				Components: &Components{
+					RequestBodies: map[string]*RequestBody{
+						"Pet": {
+							Content: map[string]*MediaType{
+								"application/yaml": {
+									Examples: map[string]*Example{
+										"puma": {Summary: "Puma", Value: map[string]interface{}{"name": "Puma"}},
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+		{
+			false,
+			ExternalValueOptions{},
+			document("text/plain", r.server.URL+"/pet.txt"),
+			&OpenAPI{
+				Components: &Components{
+					RequestBodies: map[string]*RequestBody{
+						"Pet": {
+							Content: map[string]*MediaType{
+								"text/plain": {
+									Examples: map[string]*Example{
+										"puma": {Summary: "Puma", Value: "Puma"},
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+		{
+			false,
+			ExternalValueOptions{},
+			document("image/png", r.server.URL+"/pet.png"),
+			document("image/png", r.server.URL+"/pet.png"),
+		},
+		{
+			true,
+			ExternalValueOptions{MaxSize: 32},
+			document("text/plain", r.server.URL+"/big.txt"),
+			nil,
+		},
+		{
+			true,
+			ExternalValueOptions{},
+			document("text/plain", r.server.URL+"/missing.txt"),
+			nil,
+		},
+		{
+			true,
+			ExternalValueOptions{},
+			document("text/plain", "pet.txt"),
+			nil,
+		},
+	}
+
+	for i, testCase := range testCases {
+		failMsg := fmt.Sprintf("testCase: %d %v", i, testCase)
+
+		err := testCase.input.EmbedExternalValues(context.Background(), testCase.opts)
+		if (err != nil) != testCase.shouldFail {
+			assert.Fail(r.T(), failMsg, err)
+		}
+
+		if !testCase.shouldFail {
+			assert.EqualValues(r.T(), testCase.expected, testCase.input, failMsg)
+		}
+	}
+}
+
+func TestExternalValueSuite(t *testing.T) {
+	suite.Run(t, new(ExternalValueSuite))
+}
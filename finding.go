@@ -0,0 +1,46 @@
+package oas
+
+import (
+	"sort"
+)
+
+// Severity describes how serious a finding is.
+type Severity string
+
+// Severities of the findings reported by the audits.
+const (
+	SeverityError   Severity = "error"
+	SeverityWarning Severity = "warning"
+	SeverityInfo    Severity = "info"
+)
+
+// Finding describes an issue an audit detected in a document.
+type Finding struct {
+	// Pointer describes the JSON Pointer of the location the finding applies
+	// to.
+	Pointer string `json:"pointer" yaml:"pointer"`
+
+	// Rule describes the identifier of the rule which produced the finding.
+	Rule string `json:"rule" yaml:"rule"`
+
+	// Severity describes how serious the finding is.
+	Severity Severity `json:"severity" yaml:"severity"`
+
+	// Message describes the finding in a human readable form.
+	Message string `json:"message" yaml:"message"`
+
+	// Location describes where the pointer is written in the source of the
+	// document, set by Provenance.Annotate.
+	Location *Source `json:"location,omitempty" yaml:"location,omitempty"`
+}
+
+// sortFindings orders findings by pointer and rule so audits produce
+// deterministic output.
+func sortFindings(findings []*Finding) {
+	sort.SliceStable(findings, func(i, j int) bool {
+		if findings[i].Pointer != findings[j].Pointer {
+			return findings[i].Pointer < findings[j].Pointer
+		}
+		return findings[i].Rule < findings[j].Rule
+	})
+}
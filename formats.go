@@ -0,0 +1,109 @@
+package oas
+
+import (
+	"net"
+	"net/mail"
+	"net/url"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// FormatValidator reports why the string does not conform to a format, or
+// nil if it does. Numbers are given in their decimal representation.
+type FormatValidator func(value string) error
+
+// uuidPattern matches the textual representation of UUIDs.
+var uuidPattern = regexp.MustCompile(`^[0-9a-fA-F]{8}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{12}$`)
+
+// formats holds the validators of the formats by name.
+var formats = struct {
+	sync.RWMutex
+	validators map[string]FormatValidator
+}{
+	validators: map[string]FormatValidator{
+		"date-time": func(value string) error {
+			if _, err := time.Parse(time.RFC3339Nano, value); err != nil {
+				return errors.New("value must be an RFC 3339 date-time")
+			}
+			return nil
+		},
+		"date": func(value string) error {
+			if _, err := time.Parse("2006-01-02", value); err != nil {
+				return errors.New("value must be an RFC 3339 full-date")
+			}
+			return nil
+		},
+		"email": func(value string) error {
+			if address, err := mail.ParseAddress(value); err != nil || address.Address != value {
+				return errors.New("value must be an email address")
+			}
+			return nil
+		},
+		"uuid": func(value string) error {
+			if !uuidPattern.MatchString(value) {
+				return errors.New("value must be a UUID")
+			}
+			return nil
+		},
+		"uri": func(value string) error {
+			if uri, err := url.Parse(value); err != nil || !uri.IsAbs() {
+				return errors.New("value must be an absolute URI")
+			}
+			return nil
+		},
+		"ipv4": func(value string) error {
+			if ip := net.ParseIP(value); ip == nil || strings.Contains(value, ":") {
+				return errors.New("value must be an IPv4 address")
+			}
+			return nil
+		},
+		"ipv6": func(value string) error {
+			if ip := net.ParseIP(value); ip == nil || !strings.Contains(value, ":") {
+				return errors.New("value must be an IPv6 address")
+			}
+			return nil
+		},
+		"int32": func(value string) error {
+			if _, err := strconv.ParseInt(value, 10, 32); err != nil {
+				return errors.New("value must be a signed 32-bit integer")
+			}
+			return nil
+		},
+		"int64": func(value string) error {
+			if _, err := strconv.ParseInt(value, 10, 64); err != nil {
+				return errors.New("value must be a signed 64-bit integer")
+			}
+			return nil
+		},
+	},
+}
+
+// RegisterFormat registers the validator of the format, so values of
+// schemas declaring it are checked by SchemaValidator. Built-in validators
+// are registered for date-time, date, email, uuid, uri, ipv4, ipv6, int32
+// and int64, and can be replaced. Registering a nil validator removes the
+// format. It is safe for concurrent use and typically called from init
+// functions.
+func RegisterFormat(name string, validator FormatValidator) {
+	formats.Lock()
+	defer formats.Unlock()
+	if validator == nil {
+		delete(formats.validators, name)
+		return
+	}
+	formats.validators[name] = validator
+}
+
+// formatValidator returns the validator of the format, or nil when the
+// format is unknown. Unknown formats are not checked, as required by the
+// specification.
+func formatValidator(name string) FormatValidator {
+	formats.RLock()
+	defer formats.RUnlock()
+	return formats.validators[name]
+}
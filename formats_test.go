@@ -0,0 +1,99 @@
+package oas
+
+import (
+	"encoding/json"
+	"regexp"
+	"testing"
+
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+)
+
+type FormatsSuite struct {
+	suite.Suite
+}
+
+func (r *FormatsSuite) TestBuiltinFormats() {
+	testCases := []struct {
+		format string
+		value  interface{}
+		valid  bool
+	}{
+		{"date-time", "2019-10-12T07:20:50.52Z", true},
+		{"date-time", "2019-10-12 07:20", false},
+		{"date", "2019-10-12", true},
+		{"date", "2019-13-12", false},
+		{"email", "rex@example.com", true},
+		{"email", "Rex <rex@example.com>", false},
+		{"uuid", "123e4567-e89b-12d3-a456-426614174000", true},
+		{"uuid", "123e4567e89b12d3a456426614174000", false},
+		{"uri", "https://example.com/pets?id=1", true},
+		{"uri", "/pets", false},
+		{"ipv4", "192.168.0.1", true},
+		{"ipv4", "::1", false},
+		{"ipv6", "2001:db8::1", true},
+		{"ipv6", "192.168.0.1", false},
+		{"int32", 2147483647, true},
+		{"int32", 2147483648, false},
+		{"int64", int64(-9223372036854775808), true},
+		{"int64", "12", true},
+		{"int64", 1.5, false},
+		{"int32", float64(100000000), true},
+		{"int32", float64(1234567), true},
+		{"int32", float64(3e9), false},
+		{"int32", float32(1e6), true},
+		{"int64", float64(1e15), true},
+		{"int64", float64(1e19), false},
+		{"int64", json.Number("1e6"), true},
+		{"int64", json.Number("12345678901"), true},
+		{"unknown", "anything", true},
+	}
+
+	for i, testCase := range testCases {
+		failMsg := "test case %d failed"
+		err := (&Schema{Format: testCase.format}).ValidateValue(testCase.value)
+		if testCase.valid {
+			assert.Nil(r.T(), err, failMsg, i)
+			continue
+		}
+		if assert.IsType(r.T(), ValueErrors{}, err, failMsg, i) {
+			assert.Equal(r.T(), "format", err.(ValueErrors)[0].Keyword, failMsg, i)
+		}
+	}
+}
+
+func (r *FormatsSuite) TestDecodedNumbers() {
+	schema := &Schema{Type: "object", Properties: NewProperties(map[string]*Schema{
+		"n": {Type: "integer", Format: "int32"},
+		"m": {Type: "integer", Format: "int64"},
+	})}
+	var value interface{}
+	r.Require().Nil(json.Unmarshal([]byte(`{"n": 100000000, "m": 1234567890123}`), &value))
+	assert.Nil(r.T(), schema.ValidateValue(value))
+
+	r.Require().Nil(json.Unmarshal([]byte(`{"n": 4294967296}`), &value))
+	assert.EqualError(r.T(), schema.ValidateValue(value), "/n: value must be a signed 32-bit integer")
+}
+
+func (r *FormatsSuite) TestRegisterFormat() {
+	ulid := regexp.MustCompile(`^[0-9A-HJKMNP-TV-Z]{26}$`)
+	RegisterFormat("ulid", func(value string) error {
+		if !ulid.MatchString(value) {
+			return errors.New("value must be a ULID")
+		}
+		return nil
+	})
+	defer RegisterFormat("ulid", nil)
+
+	schema := &Schema{Type: "string", Format: "ulid"}
+	assert.Nil(r.T(), schema.ValidateValue("01ARZ3NDEKTSV4RRFFQ69G5FAV"))
+	assert.EqualError(r.T(), schema.ValidateValue("pets"), "/: value must be a ULID")
+
+	RegisterFormat("ulid", nil)
+	assert.Nil(r.T(), schema.ValidateValue("pets"))
+}
+
+func TestFormatsSuite(t *testing.T) {
+	suite.Run(t, new(FormatsSuite))
+}
@@ -0,0 +1,155 @@
+package oas
+
+import (
+	"context"
+	"net/url"
+	"path"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// ReadFragment reads the fragment file at the location with ReadLocation and
+// decodes it into out as DecodeFragment does.
+func ReadFragment(ctx context.Context, location string, out interface{}) error {
+	data, err := ReadLocation(ctx, location)
+	if err != nil {
+		return err
+	}
+	return DecodeFragment(location, data, out)
+}
+
+// DecodeFragment decodes the JSON or YAML data of a fragment file, a file
+// holding a single object rather than a whole document, read from the
+// location into out. Fragments are *Schema, *PathItem, *Operation,
+// *Parameter, *RequestBody, *Response, *Header, *Example, *Link, *Callback
+// or *SecurityScheme values. References of the fragment are resolved against
+// its own location: "#/Tag" becomes "schemas/pet.yaml#/Tag" and "tag.yaml"
+// becomes "schemas/tag.yaml" for a fragment read from "schemas/pet.yaml", so
+// fragments can be stitched into a document without losing their targets.
+func DecodeFragment(location string, data []byte, out interface{}) error {
+	if err := decodeDocument(data, out); err != nil {
+		return errors.Wrap(err, location)
+	}
+	return visitFragment(out, func(ref *string) {
+		*ref = rebaseRef(location, *ref)
+	})
+}
+
+// EncodeFragment returns the encoding of the fragment to be saved at the
+// location, JSON when the location has a ".json" extension and YAML
+// otherwise. References into the location and its directory are written
+// relative to the location, reversing DecodeFragment. Fields are written in
+// canonical order.
+func EncodeFragment(location string, in interface{}) ([]byte, error) {
+	refs := map[*string]string{}
+	err := visitFragment(in, func(ref *string) {
+		refs[ref] = *ref
+		*ref = relativeRef(location, *ref)
+	})
+	defer func() {
+		for ref, value := range refs {
+			*ref = value
+		}
+	}()
+	if err != nil {
+		return nil, err
+	}
+
+	if path.Ext(location) == ".json" {
+		return MarshalCanonicalJSON(in)
+	}
+	return MarshalCanonicalYAML(in)
+}
+
+// visitFragment calls fn with the address of every $ref field of the
+// fragment.
+func visitFragment(fragment interface{}, fn func(ref *string)) error {
+	w := &walker{fn: func(ptr string, node interface{}) error {
+		if ref := refOf(node); ref != nil && *ref != "" {
+			fn(ref)
+		}
+		return nil
+	}}
+	switch fragment := fragment.(type) {
+	case *Schema:
+		return w.schema("", fragment)
+	case *PathItem:
+		return w.pathItem("", fragment)
+	case *Operation:
+		return w.operation("", fragment)
+	case *Parameter:
+		return w.parameter("", fragment)
+	case *RequestBody:
+		return w.requestBody("", fragment)
+	case *Response:
+		return w.response("", fragment)
+	case *Header:
+		return w.header("", fragment)
+	case *Example:
+		return w.example("", fragment)
+	case *Link:
+		return w.link("", fragment)
+	case *Callback:
+		return w.callback("", fragment)
+	case *SecurityScheme:
+		return w.fn("", fragment)
+	}
+	return errors.Errorf("unsupported fragment type %T", fragment)
+}
+
+// rebaseRef resolves the reference of a fragment read from the location.
+func rebaseRef(location string, ref string) string {
+	file, fragment := splitRef(ref)
+	switch {
+	case file == "":
+		file = location
+	case isURL(location):
+		base, err := url.Parse(location)
+		if err != nil {
+			return ref
+		}
+		target, err := url.Parse(file)
+		if err != nil {
+			return ref
+		}
+		file = base.ResolveReference(target).String()
+	case !isURL(file) && !path.IsAbs(file):
+		file = path.Join(path.Dir(location), file)
+	}
+	if fragment == "" {
+		return file
+	}
+	return file + "#" + fragment
+}
+
+// relativeRef returns the reference relative to a fragment saved at the
+// location.
+func relativeRef(location string, ref string) string {
+	file, fragment := splitRef(ref)
+	dir := location[:strings.LastIndex(location, "/")+1]
+	switch {
+	case file == location:
+		file = ""
+	case file != "" && strings.HasPrefix(file, dir):
+		file = strings.TrimPrefix(file, dir)
+	}
+	if fragment == "" {
+		return file
+	}
+	return file + "#" + fragment
+}
+
+// splitRef splits a reference into the location of the file and the
+// fragment.
+func splitRef(ref string) (string, string) {
+	if i := strings.Index(ref, "#"); i >= 0 {
+		return ref[:i], ref[i+1:]
+	}
+	return ref, ""
+}
+
+// isURL reports whether the location is an http or https URL.
+func isURL(location string) bool {
+	return strings.HasPrefix(location, "http://") || strings.HasPrefix(location, "https://")
+}
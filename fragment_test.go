@@ -0,0 +1,91 @@
+package oas
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+)
+
+type FragmentSuite struct {
+	suite.Suite
+}
+
+func (r *FragmentSuite) TestDecodeFragment() {
+	schema := &Schema{}
+	err := DecodeFragment("schemas/pet.yaml", []byte(`type: object
+properties:
+  tag:
+    $ref: '#/Tag'
+  owner:
+    $ref: ../owners/owner.yaml
+  photo:
+    $ref: 'https://example.com/photo.json#/Photo'
+`), schema)
+	assert.Nil(r.T(), err)
+	assert.Equal(r.T(), "schemas/pet.yaml#/Tag", schema.Properties.Get("tag").Ref)
+	assert.Equal(r.T(), "owners/owner.yaml", schema.Properties.Get("owner").Ref)
+	assert.Equal(r.T(), "https://example.com/photo.json#/Photo", schema.Properties.Get("photo").Ref)
+
+	item := &PathItem{}
+	err = DecodeFragment("https://example.com/paths/pets.json", []byte(`{
+		"get": {"responses": {"200": {"$ref": "../responses.json#/Pets"}}}
+	}`), item)
+	assert.Nil(r.T(), err)
+	assert.Equal(r.T(), "https://example.com/responses.json#/Pets", item.Get.Responses.Get("200").Ref)
+
+	assert.NotNil(r.T(), DecodeFragment("pet.yaml", []byte("type: [object"), &Schema{}))
+	assert.NotNil(r.T(), DecodeFragment("pet.yaml", []byte("type: object"), &Components{}))
+}
+
+func (r *FragmentSuite) TestEncodeFragment() {
+	schema := &Schema{
+		Type: "object",
+		Properties: NewProperties(map[string]*Schema{
+			"tag":   {Ref: "schemas/pet.yaml#/Tag"},
+			"owner": {Ref: "schemas/owner.yaml"},
+			"error": {Ref: "common.yaml#/Error"},
+		}),
+	}
+	rbytes, err := EncodeFragment("schemas/pet.yaml", schema)
+	assert.Nil(r.T(), err)
+	assert.Equal(r.T(), `type: object
+properties:
+  error:
+    $ref: common.yaml#/Error
+  owner:
+    $ref: owner.yaml
+  tag:
+    $ref: '#/Tag'
+`, string(rbytes))
+	assert.Equal(r.T(), "schemas/pet.yaml#/Tag", schema.Properties.Get("tag").Ref)
+
+	rbytes, err = EncodeFragment("schemas/tag.json", &Schema{Type: "string"})
+	assert.Nil(r.T(), err)
+	assert.Equal(r.T(), "{\n  \"type\": \"string\"\n}\n", string(rbytes))
+
+	_, err = EncodeFragment("tag.yaml", &Info{})
+	assert.NotNil(r.T(), err)
+}
+
+func (r *FragmentSuite) TestReadFragment() {
+	dir, err := ioutil.TempDir("", "fragment")
+	assert.Nil(r.T(), err)
+	defer os.RemoveAll(dir)
+
+	location := filepath.ToSlash(filepath.Join(dir, "pet.yaml"))
+	assert.Nil(r.T(), ioutil.WriteFile(location, []byte("$ref: tag.yaml\n"), 0644))
+	schema := &Schema{}
+	assert.Nil(r.T(), ReadFragment(context.Background(), location, schema))
+	assert.Equal(r.T(), filepath.ToSlash(filepath.Join(dir, "tag.yaml")), schema.Ref)
+
+	assert.NotNil(r.T(), ReadFragment(context.Background(), filepath.Join(dir, "missing.yaml"), schema))
+}
+
+func TestFragmentSuite(t *testing.T) {
+	suite.Run(t, new(FragmentSuite))
+}
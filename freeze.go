@@ -0,0 +1,163 @@
+package oas
+
+import (
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+// FrozenExtension names the specification extension marking a document as
+// frozen. A frozen document may only evolve through additive changes, which
+// CheckFrozen enforces.
+const FrozenExtension = "x-frozen"
+
+// RuleFrozenChange is the rule reported by CheckFrozen.
+const RuleFrozenChange = "frozen-change"
+
+// frozenCollections holds the fields whose values are maps keyed by names,
+// such as paths, properties or responses, where adding entries is additive.
+var frozenCollections = map[string]bool{
+	"paths":           true,
+	"properties":      true,
+	"responses":       true,
+	"content":         true,
+	"headers":         true,
+	"callbacks":       true,
+	"links":           true,
+	"examples":        true,
+	"variables":       true,
+	"encoding":        true,
+	"mapping":         true,
+	"schemas":         true,
+	"parameters":      true,
+	"requestBodies":   true,
+	"securitySchemes": true,
+	"scopes":          true,
+}
+
+// frozenDocumentation holds the fields which only document the API and may
+// be added, changed or removed in frozen documents.
+var frozenDocumentation = map[string]bool{
+	"description":  true,
+	"summary":      true,
+	"title":        true,
+	"example":      true,
+	"examples":     true,
+	"externalDocs": true,
+	"deprecated":   true,
+}
+
+// IsFrozen reports whether the document is marked frozen.
+func (r OpenAPI) IsFrozen() bool {
+	frozen, _ := r.Extensions[FrozenExtension].(bool)
+	return frozen
+}
+
+// Freeze marks the document frozen.
+func (r *OpenAPI) Freeze() {
+	setExtension(&r.Extensions, FrozenExtension, true)
+}
+
+// CheckFrozen compares the next version of a frozen document against it and
+// reports every change which is not additive, enforcing policies such as "v1
+// is frozen" in code. New paths, operations, properties, responses, media
+// types and other named entries may be added. Documentation, specification
+// extensions and the version of the document may change freely. Anything
+// else, including new constraints such as added required fields or a changed
+// parameter list, is reported. Nothing is reported for documents which are
+// not frozen.
+func (r OpenAPI) CheckFrozen(next OpenAPI) ([]*Finding, error) {
+	findings := make([]*Finding, 0)
+	if !r.IsFrozen() {
+		return findings, nil
+	}
+
+	frozen, err := genericValue(r)
+	if err != nil {
+		return nil, err
+	}
+	value, err := genericValue(next)
+	if err != nil {
+		return nil, err
+	}
+	added := false
+	checkFrozenValue("", "", frozen, value, false, &findings, &added)
+	if !next.IsFrozen() {
+		findings = append(findings, &Finding{
+			Pointer:  join("", FrozenExtension),
+			Rule:     RuleFrozenChange,
+			Severity: SeverityError,
+			Message:  "removes the " + FrozenExtension + " mark",
+		})
+	}
+	sortFindings(findings)
+	return findings, nil
+}
+
+// checkFrozenValue appends findings for the non-additive differences between
+// the frozen and the next generic values found under the key, and sets added
+// when the next value adds entries. Collection tells whether the values are
+// maps keyed by names.
+func checkFrozenValue(ptr string, key string, frozen interface{}, next interface{}, collection bool, findings *[]*Finding, added *bool) {
+	report := func(ptr string, message string) {
+		*findings = append(*findings, &Finding{
+			Pointer:  ptr,
+			Rule:     RuleFrozenChange,
+			Severity: SeverityError,
+			Message:  message + " in a document marked " + FrozenExtension,
+		})
+	}
+
+	switch frozen := frozen.(type) {
+	case map[string]interface{}:
+		next, ok := next.(map[string]interface{})
+		if !ok {
+			report(ptr, "changes the value")
+			return
+		}
+		for _, child := range sortedStrings(frozen) {
+			if !collection && (isFrozenExempt(child) || join(ptr, child) == "/info/version") {
+				continue
+			}
+			value, ok := next[child]
+			if !ok {
+				report(join(ptr, child), "removes the value")
+				continue
+			}
+			childCollection := frozenCollections[child]
+			if collection {
+				childCollection = key == "callbacks"
+			}
+			checkFrozenValue(join(ptr, child), child, frozen[child], value, childCollection, findings, added)
+		}
+		for _, child := range sortedStrings(next) {
+			if _, ok := frozen[child]; ok || isFrozenExempt(child) {
+				continue
+			}
+			if collection || frozenCollections[child] || isOperationMethod(child) {
+				*added = true
+				continue
+			}
+			report(join(ptr, child), "adds the "+child+" field")
+		}
+	case []interface{}:
+		next, ok := next.([]interface{})
+		if !ok || len(frozen) != len(next) {
+			report(ptr, "changes the list")
+			return
+		}
+		for i := range frozen {
+			checkFrozenValue(join(ptr, strconv.Itoa(i)), "", frozen[i], next[i], false, findings, added)
+		}
+	default:
+		if !reflect.DeepEqual(frozen, next) {
+			report(ptr, "changes the value")
+		}
+	}
+}
+
+// isFrozenExempt reports whether the field may change freely in frozen
+// documents.
+func isFrozenExempt(key string) bool {
+	return frozenDocumentation[key] || strings.HasPrefix(strings.ToLower(key), "x-")
+}
@@ -0,0 +1,131 @@
+package oas
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+)
+
+type FreezeSuite struct {
+	suite.Suite
+}
+
+// freezePatch is the patch of the petstore fixture freezing it.
+const freezePatch = `
+paths:
+  /pets:
+    get:
+      parameters:
+        - name: limit
+          in: query
+x-frozen: true
+`
+
+func (r *FreezeSuite) TestCheckFrozen() {
+	testCases := []struct {
+		next     string
+		expected []string
+	}{
+		{
+			`
+info:
+  version: 1.1.0
+  description: Pets.
+paths:
+  /pets:
+    get:
+      responses:
+        "404":
+          description: Not found.
+      x-owner: pets
+    put:
+      responses:
+        "200":
+          description: Replaced.
+  /stores:
+    get:
+      responses:
+        "200":
+          description: The stores.
+components:
+  schemas:
+    Pet:
+      properties:
+        age:
+          type: integer
+    Owner:
+      type: object
+`,
+			[]string{},
+		},
+		{
+			`
+paths:
+  /pets:
+    get:
+      parameters:
+        - name: limit
+          in: query
+        - name: offset
+          in: query
+      responses:
+        "200":
+          content:
+            application/json:
+              schema:
+                items:
+                  $ref: "#/components/schemas/Owner"
+components:
+  schemas:
+    Pet:
+      required: [name, tag]
+      properties:
+        name: null
+`,
+			[]string{
+				"/components/schemas/Pet/properties/name",
+				"/components/schemas/Pet/required",
+				"/paths/~1pets/get/parameters",
+				"/paths/~1pets/get/responses/200/content/application~1json/schema/items/$ref",
+			},
+		},
+		{
+			`
+paths:
+  /pets: null
+x-frozen: null
+`,
+			[]string{"/paths/~1pets", "/x-frozen"},
+		},
+	}
+
+	for i, testCase := range testCases {
+		failMsg := "test case %d failed"
+		next := petstore(r.T(), freezePatch, testCase.next)
+		findings, err := petstore(r.T(), freezePatch).CheckFrozen(*next)
+		assert.Nil(r.T(), err, failMsg, i)
+		pointers := make([]string, 0)
+		for _, finding := range findings {
+			assert.Equal(r.T(), RuleFrozenChange, finding.Rule, failMsg, i)
+			pointers = append(pointers, finding.Pointer)
+		}
+		assert.Equal(r.T(), testCase.expected, pointers, failMsg, i)
+	}
+}
+
+func (r *FreezeSuite) TestNotFrozen() {
+	doc := petstore(r.T())
+	assert.False(r.T(), doc.IsFrozen())
+	doc.Freeze()
+	assert.True(r.T(), doc.IsFrozen())
+	assert.Equal(r.T(), petstore(r.T(), "x-frozen: true"), doc)
+
+	findings, err := petstore(r.T()).CheckFrozen(OpenAPI{})
+	assert.Nil(r.T(), err)
+	assert.Empty(r.T(), findings)
+}
+
+func TestFreezeSuite(t *testing.T) {
+	suite.Run(t, new(FreezeSuite))
+}
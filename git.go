@@ -0,0 +1,67 @@
+package oas
+
+import (
+	"bytes"
+	"context"
+	"os/exec"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// GitRevision addresses a file of a Git repository as of a revision.
+type GitRevision struct {
+	// Dir describes a directory inside the work tree of the repository.
+	Dir string
+
+	// Revision describes any revision understood by git (e.g. "main",
+	// "HEAD~1" or a commit hash).
+	Revision string
+}
+
+// ReadFile returns the content of the file as of the revision. The path is
+// resolved relative to Dir.
+func (r GitRevision) ReadFile(ctx context.Context, name string) ([]byte, error) {
+	if r.Revision == "" {
+		return nil, errors.New("missing git revision")
+	}
+
+	if filepath.IsAbs(name) {
+		return nil, errors.Errorf("path %q must be relative", name)
+	}
+	name = path.Clean(filepath.ToSlash(name))
+	if !strings.HasPrefix(name, "../") {
+		name = "./" + name
+	}
+	object := r.Revision + ":" + name
+
+	stdout, stderr := &bytes.Buffer{}, &bytes.Buffer{}
+	cmd := exec.CommandContext(ctx, "git", "show", object)
+	cmd.Dir = r.Dir
+	cmd.Stdout = stdout
+	cmd.Stderr = stderr
+	if err := cmd.Run(); err != nil {
+		return nil, errors.Wrapf(err, "git show %s: %s", object, strings.TrimSpace(stderr.String()))
+	}
+	return stdout.Bytes(), nil
+}
+
+// LoadRef reads the file at the location as of the revision, so the
+// revision can serve as the RefLoader of a Loader. URLs are refused.
+func (r GitRevision) LoadRef(ctx context.Context, location string) ([]byte, error) {
+	if isURL(location) {
+		return nil, errors.Errorf("location %q is not a file of the repository", location)
+	}
+	return r.ReadFile(ctx, location)
+}
+
+// Load reads and decodes the document stored at the path as of the
+// revision, so documents of two revisions can be compared without checking
+// out files. The files it references are read as of the same revision and
+// bundled into it as Loader.LoadBundle does. Errors are returned as
+// *LoadError.
+func (r GitRevision) Load(ctx context.Context, name string) (*OpenAPI, error) {
+	return Loader{RefLoader: r}.LoadBundle(ctx, name)
+}
@@ -0,0 +1,140 @@
+package oas
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+)
+
+type GitSuite struct {
+	suite.Suite
+	dir string
+}
+
+func (r *GitSuite) SetupSuite() {
+	if _, err := exec.LookPath("git"); err != nil {
+		r.T().Skip("git is not installed")
+	}
+
+	dir, err := ioutil.TempDir("", "oas-git")
+	if err != nil {
+		r.T().Fatal(err)
+	}
+	r.dir = dir
+
+	commit := func(files map[string]string) {
+		for name, content := range files {
+			if err := os.MkdirAll(filepath.Dir(filepath.Join(dir, name)), 0755); err != nil {
+				r.T().Fatal(err)
+			}
+			if err := ioutil.WriteFile(filepath.Join(dir, name), []byte(content), 0644); err != nil {
+				r.T().Fatal(err)
+			}
+		}
+		for _, args := range [][]string{
+			{"add", "-A"},
+			{"-c", "user.name=test", "-c", "user.email=test@example.com", "commit", "-q", "-m", "update"},
+		} {
+			cmd := exec.Command("git", args...)
+			cmd.Dir = dir
+			if out, err := cmd.CombinedOutput(); err != nil {
+				r.T().Fatal(string(out))
+			}
+		}
+	}
+
+	cmd := exec.Command("git", "init", "-q")
+	cmd.Dir = dir
+	if out, err := cmd.CombinedOutput(); err != nil {
+		r.T().Fatal(string(out))
+	}
+	commit(map[string]string{
+		"api/openapi.yaml": "openapi: 3.0.0\ninfo:\n  title: Petstore\n  version: 1.0.0\npaths: {}\n",
+	})
+	commit(map[string]string{
+		"api/openapi.yaml": `{"openapi": "3.0.0", "info": {"title": "Petstore", "version": "2.0.0"}, "paths": {}}`,
+	})
+	commit(map[string]string{
+		"api/openapi.yaml": "openapi: 3.0.0\ninfo:\n  title: Petstore\n  version: 3.0.0\npaths: {}\n" +
+			"components:\n  schemas:\n    Pets:\n      type: array\n      items:\n        $ref: ./schemas/pet.yaml#/Pet\n",
+		"api/schemas/pet.yaml": "Pet:\n  type: object\n",
+	})
+}
+
+func (r *GitSuite) TearDownSuite() {
+	if r.dir != "" {
+		os.RemoveAll(r.dir)
+	}
+}
+
+func (r *GitSuite) TestLoad() {
+	testCases := []struct {
+		shouldFail bool
+		revision   GitRevision
+		path       string
+		expected   *OpenAPI
+	}{
+		{
+			false,
+			GitRevision{Dir: r.dir, Revision: "HEAD~2"},
+			"api/openapi.yaml",
+			&OpenAPI{OpenAPI: "3.0.0", Info: Info{Title: "Petstore", Version: "1.0.0"}},
+		},
+		{
+			false,
+			GitRevision{Dir: filepath.Join(r.dir, "api"), Revision: "HEAD~1"},
+			"openapi.yaml",
+			&OpenAPI{OpenAPI: "3.0.0", Info: Info{Title: "Petstore", Version: "2.0.0"}},
+		},
+		{
+			false,
+			GitRevision{Dir: r.dir, Revision: "HEAD"},
+			"api/openapi.yaml",
+			&OpenAPI{
+				OpenAPI: "3.0.0",
+				Info:    Info{Title: "Petstore", Version: "3.0.0"},
+				Components: &Components{Schemas: map[string]*Schema{
+					"Pets": {Type: "array", Items: &Schema{Ref: "#/components/schemas/Pet"}},
+					"Pet":  {Type: "object"},
+				}},
+			},
+		},
+		{
+			true,
+			GitRevision{Dir: r.dir, Revision: "HEAD"},
+			"missing.yaml",
+			nil,
+		},
+		{
+			true,
+			GitRevision{Dir: r.dir},
+			"api/openapi.yaml",
+			nil,
+		},
+	}
+
+	for i, testCase := range testCases {
+		failMsg := fmt.Sprintf("testCase: %d %v", i, testCase)
+
+		actual, err := testCase.revision.Load(context.Background(), testCase.path)
+		if (err != nil) != testCase.shouldFail {
+			assert.Fail(r.T(), failMsg, err)
+		}
+		if err != nil {
+			_, ok := err.(*LoadError)
+			assert.True(r.T(), ok, failMsg)
+		}
+		assert.EqualValues(r.T(), testCase.expected, actual, failMsg)
+	}
+}
+
+func TestGitSuite(t *testing.T) {
+	suite.Run(t, new(GitSuite))
+}
@@ -0,0 +1,299 @@
+package oas
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+const (
+	// GoogleBackendExtension names the specification extension declaring the
+	// backend Google Cloud Endpoints and API Gateway route an operation to.
+	// It may be declared on the operation, on the path item or on the
+	// document root, the closest declaration taking precedence.
+	GoogleBackendExtension = "x-google-backend"
+
+	// GoogleEndpointsExtension names the specification extension declaring
+	// the Endpoints service of the document.
+	GoogleEndpointsExtension = "x-google-endpoints"
+
+	// GoogleAPINameExtension names the specification extension declaring the
+	// name of the API managed by API Gateway.
+	GoogleAPINameExtension = "x-google-api-name"
+)
+
+// Path translations of Google backends.
+const (
+	// AppendPathToAddress appends the path of the request to the address of
+	// the backend.
+	AppendPathToAddress = "APPEND_PATH_TO_ADDRESS"
+
+	// ConstantAddress sends every request to the address of the backend,
+	// passing the path parameters as query parameters.
+	ConstantAddress = "CONSTANT_ADDRESS"
+)
+
+// GoogleBackend describes the backend requests are routed to by Google Cloud
+// Endpoints and API Gateway.
+type GoogleBackend struct {
+	// Address describes the URL of the backend.
+	Address string `json:"address" yaml:"address"`
+
+	// PathTranslation describes how the path of the request is translated,
+	// either AppendPathToAddress or ConstantAddress.
+	PathTranslation string `json:"path_translation,omitempty" yaml:"path_translation,omitempty"`
+
+	// JWTAudience describes the audience of the ID token sent to the backend.
+	JWTAudience string `json:"jwt_audience,omitempty" yaml:"jwt_audience,omitempty"`
+
+	// DisableAuth disables sending an ID token to the backend.
+	DisableAuth bool `json:"disable_auth,omitempty" yaml:"disable_auth,omitempty"`
+
+	// Deadline describes the number of seconds to wait for the response.
+	Deadline float64 `json:"deadline,omitempty" yaml:"deadline,omitempty"`
+
+	// Protocol describes the protocol of the backend (e.g. "h2").
+	Protocol string `json:"protocol,omitempty" yaml:"protocol,omitempty"`
+}
+
+// validate reports whether the backend is usable.
+func (r GoogleBackend) validate() error {
+	if r.Address == "" {
+		return errors.New("missing address")
+	}
+	switch r.PathTranslation {
+	case "", AppendPathToAddress, ConstantAddress:
+	default:
+		return errors.Errorf("unknown path translation %q", r.PathTranslation)
+	}
+	if r.Deadline < 0 {
+		return errors.Errorf("negative deadline %v", r.Deadline)
+	}
+	return nil
+}
+
+// GoogleBackend returns the backend declared with the x-google-backend
+// extension, nil if it is not declared.
+func (r Extensions) GoogleBackend() (*GoogleBackend, error) {
+	backend := &GoogleBackend{}
+	ok, err := r.decode(GoogleBackendExtension, backend)
+	if !ok || err != nil {
+		return nil, err
+	}
+	if err := backend.validate(); err != nil {
+		return nil, errors.Wrap(err, GoogleBackendExtension)
+	}
+	return backend, nil
+}
+
+// SetGoogleBackend declares the backend with the x-google-backend extension,
+// or removes the extension when nil.
+func (r *Extensions) SetGoogleBackend(backend *GoogleBackend) {
+	if backend == nil {
+		delete(*r, GoogleBackendExtension)
+		return
+	}
+	value := map[string]interface{}{"address": backend.Address}
+	if backend.PathTranslation != "" {
+		value["path_translation"] = backend.PathTranslation
+	}
+	if backend.JWTAudience != "" {
+		value["jwt_audience"] = backend.JWTAudience
+	}
+	if backend.DisableAuth {
+		value["disable_auth"] = backend.DisableAuth
+	}
+	if backend.Deadline != 0 {
+		value["deadline"] = backend.Deadline
+	}
+	if backend.Protocol != "" {
+		value["protocol"] = backend.Protocol
+	}
+	setExtension(r, GoogleBackendExtension, value)
+}
+
+// GoogleEndpointsOptions describes the behavior of GoogleEndpoints and
+// GoogleServiceConfig.
+type GoogleEndpointsOptions struct {
+	// ServiceName describes the name of the Endpoints service (e.g.
+	// "petstore.endpoints.my-project.cloud.goog").
+	ServiceName string
+
+	// APIName describes the name of the API managed by API Gateway.
+	APIName string
+
+	// AllowCORS lets the proxy pass CORS preflight requests to the backend.
+	AllowCORS bool
+
+	// Backend describes the backend of the operations which declare none.
+	Backend *GoogleBackend
+}
+
+// GoogleEndpoints returns a copy of the document prepared for Google Cloud
+// Endpoints and API Gateway. Backends declared on path items, which Google
+// does not read, are pushed down to their operations and the default
+// backend of the options is declared on the document root. Operations
+// without operationId, which Google requires, receive one derived from
+// their method and path. Every operation must end up with a backend.
+func (r OpenAPI) GoogleEndpoints(opts GoogleEndpointsOptions) (*OpenAPI, error) {
+	if opts.ServiceName == "" {
+		return nil, errors.New("missing service name")
+	}
+	if opts.Backend != nil {
+		if err := opts.Backend.validate(); err != nil {
+			return nil, errors.Wrap(err, "default backend")
+		}
+	}
+	doc, err := r.Clone()
+	if err != nil {
+		return nil, err
+	}
+
+	root, err := doc.Extensions.GoogleBackend()
+	if err != nil {
+		return nil, err
+	}
+	if root == nil && opts.Backend != nil {
+		doc.Extensions.SetGoogleBackend(opts.Backend)
+		root = opts.Backend
+	}
+
+	ids := map[string]bool{}
+	for _, op := range doc.Paths.operations() {
+		if op.operation.OperationID != "" {
+			ids[op.operation.OperationID] = true
+		}
+	}
+	for _, op := range doc.Paths.operations() {
+		ptr := join("/paths", op.path, op.method)
+		backend, err := op.operation.Extensions.GoogleBackend()
+		if err != nil {
+			return nil, errors.Wrap(err, ptr)
+		}
+		if backend == nil {
+			item, err := op.item.Extensions.GoogleBackend()
+			if err != nil {
+				return nil, errors.Wrap(err, join("/paths", op.path))
+			}
+			if item != nil {
+				op.operation.Extensions.SetGoogleBackend(item)
+			} else if root == nil {
+				return nil, errors.Errorf("%s: missing backend", ptr)
+			}
+		}
+		if op.operation.OperationID == "" {
+			op.operation.OperationID = uniqueOperationID(CamelCase.Format(op.method+" "+op.path), ids)
+		}
+	}
+	for _, path := range doc.Paths.PathItems.Keys() {
+		delete(doc.Paths.PathItems.Get(path).Extensions, GoogleBackendExtension)
+	}
+
+	setExtension(&doc.Extensions, GoogleEndpointsExtension, []interface{}{
+		map[string]interface{}{"name": opts.ServiceName, "allowCors": opts.AllowCORS},
+	})
+	if opts.APIName != "" {
+		setExtension(&doc.Extensions, GoogleAPINameExtension, opts.APIName)
+	}
+	return doc, nil
+}
+
+// uniqueOperationID returns the operationId, suffixed with a number if it
+// is taken, and records it.
+func uniqueOperationID(id string, ids map[string]bool) string {
+	unique := id
+	for i := 2; ids[unique]; i++ {
+		unique = fmt.Sprintf("%s%d", id, i)
+	}
+	ids[unique] = true
+	return unique
+}
+
+// ServiceConfig describes a Google service configuration, the YAML file
+// deployed alongside the document to configure what the document cannot
+// express.
+type ServiceConfig struct {
+	// Type describes the type of the configuration, "google.api.Service".
+	Type string `json:"type" yaml:"type"`
+
+	// ConfigVersion describes the version of the configuration format.
+	ConfigVersion int `json:"config_version" yaml:"config_version"`
+
+	// Name describes the name of the service.
+	Name string `json:"name" yaml:"name"`
+
+	// Title describes the title of the service.
+	Title string `json:"title,omitempty" yaml:"title,omitempty"`
+
+	// Backend describes the backends of the operations.
+	Backend *ServiceBackend `json:"backend,omitempty" yaml:"backend,omitempty"`
+}
+
+// ServiceBackend describes the backend section of a service configuration.
+type ServiceBackend struct {
+	// Rules describes the backend of each operation.
+	Rules []*BackendRule `json:"rules" yaml:"rules"`
+}
+
+// BackendRule describes the backend of the operations matching the
+// selector.
+type BackendRule struct {
+	// Selector describes the operations the rule applies to, "*" for every
+	// operation.
+	Selector string `json:"selector" yaml:"selector"`
+
+	GoogleBackend `yaml:",inline"`
+}
+
+// GoogleServiceConfig returns the service configuration declaring the
+// backends of the operations. The backend of the root, or the default
+// backend of the options, is declared for every operation and operations
+// declaring their own backend, directly or through their path item, are
+// declared by operationId. Operations need an operationId to be selected,
+// which GoogleEndpoints provides.
+func (r OpenAPI) GoogleServiceConfig(opts GoogleEndpointsOptions) (*ServiceConfig, error) {
+	if opts.ServiceName == "" {
+		return nil, errors.New("missing service name")
+	}
+	config := &ServiceConfig{
+		Type:          "google.api.Service",
+		ConfigVersion: 3,
+		Name:          opts.ServiceName,
+		Title:         r.Info.Title,
+		Backend:       &ServiceBackend{Rules: make([]*BackendRule, 0)},
+	}
+
+	root, err := r.Extensions.GoogleBackend()
+	if err != nil {
+		return nil, err
+	}
+	if root == nil {
+		root = opts.Backend
+	}
+	if root != nil {
+		config.Backend.Rules = append(config.Backend.Rules, &BackendRule{Selector: "*", GoogleBackend: *root})
+	}
+
+	prefix := "1." + strings.NewReplacer(".", "_", "-", "_").Replace(opts.ServiceName) + "."
+	for _, op := range r.Paths.operations() {
+		backend, err := op.operation.Extensions.GoogleBackend()
+		if err == nil && backend == nil {
+			backend, err = op.item.Extensions.GoogleBackend()
+		}
+		if err != nil {
+			return nil, errors.Wrap(err, join("/paths", op.path, op.method))
+		}
+		if backend == nil {
+			continue
+		}
+		if op.operation.OperationID == "" {
+			return nil, errors.Errorf("%s: operations declaring a backend need an operationId", join("/paths", op.path, op.method))
+		}
+		config.Backend.Rules = append(config.Backend.Rules, &BackendRule{
+			Selector:      prefix + op.operation.OperationID,
+			GoogleBackend: *backend,
+		})
+	}
+	return config, nil
+}
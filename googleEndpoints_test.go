@@ -0,0 +1,133 @@
+package oas
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+)
+
+type GoogleEndpointsSuite struct {
+	suite.Suite
+}
+
+// googleEndpointsPatch is the patch of the petstore fixture the suite exports.
+const googleEndpointsPatch = `
+paths:
+  /pets:
+    post:
+      operationId: null
+  /pets/{petId}:
+    get:
+      x-google-backend:
+        address: https://pets.example.com
+        deadline: 5
+    delete:
+      operationId: getPetsPetId
+    x-google-backend:
+      address: https://legacy.example.com
+      path_translation: CONSTANT_ADDRESS
+`
+
+func (r *GoogleEndpointsSuite) options() GoogleEndpointsOptions {
+	return GoogleEndpointsOptions{
+		ServiceName: "petstore.endpoints.my-project.cloud.goog",
+		APIName:     "petstore",
+		Backend:     &GoogleBackend{Address: "https://api.example.com", PathTranslation: AppendPathToAddress},
+	}
+}
+
+func (r *GoogleEndpointsSuite) TestGoogleEndpoints() {
+	doc, err := petstore(r.T(), googleEndpointsPatch).GoogleEndpoints(r.options())
+	assert.Nil(r.T(), err)
+
+	backend, err := doc.Extensions.GoogleBackend()
+	assert.Nil(r.T(), err)
+	assert.Equal(r.T(), r.options().Backend, backend)
+	assert.Equal(r.T(), "petstore", doc.Extensions[GoogleAPINameExtension])
+	assert.Equal(r.T(), []interface{}{map[string]interface{}{
+		"name":      "petstore.endpoints.my-project.cloud.goog",
+		"allowCors": false,
+	}}, doc.Extensions[GoogleEndpointsExtension])
+
+	assert.Equal(r.T(), "postPets", doc.Paths.PathItems.Get("/pets").Post.OperationID)
+	item := doc.Paths.PathItems.Get("/pets/{petId}")
+	assert.Nil(r.T(), item.Extensions[GoogleBackendExtension])
+	backend, err = item.Delete.Extensions.GoogleBackend()
+	assert.Nil(r.T(), err)
+	assert.Equal(r.T(), &GoogleBackend{Address: "https://legacy.example.com", PathTranslation: ConstantAddress}, backend)
+	backend, err = item.Get.Extensions.GoogleBackend()
+	assert.Nil(r.T(), err)
+	assert.Equal(r.T(), &GoogleBackend{Address: "https://pets.example.com", Deadline: 5}, backend)
+	assert.NotNil(r.T(), petstore(r.T(), googleEndpointsPatch).Paths.PathItems.Get("/pets/{petId}").Extensions[GoogleBackendExtension])
+
+	doc, err = petstore(r.T(), googleEndpointsPatch, `
+paths:
+  /pets:
+    get:
+      operationId: null
+    post:
+      operationId: getPets
+`).GoogleEndpoints(r.options())
+	assert.Nil(r.T(), err)
+	assert.Equal(r.T(), "getPets2", doc.Paths.PathItems.Get("/pets").Get.OperationID)
+}
+
+func (r *GoogleEndpointsSuite) TestErrors() {
+	opts := r.options()
+	opts.Backend = nil
+	testCases := []GoogleEndpointsOptions{
+		{},
+		{ServiceName: "petstore", Backend: &GoogleBackend{}},
+		{ServiceName: "petstore", Backend: &GoogleBackend{Address: "https://api.example.com", PathTranslation: "APPEND"}},
+		opts,
+	}
+
+	for i, opts := range testCases {
+		failMsg := "test case %d failed"
+		_, err := petstore(r.T(), googleEndpointsPatch).GoogleEndpoints(opts)
+		assert.NotNil(r.T(), err, failMsg, i)
+	}
+}
+
+func (r *GoogleEndpointsSuite) TestGoogleServiceConfig() {
+	config, err := petstore(r.T(), googleEndpointsPatch).GoogleServiceConfig(r.options())
+	assert.Nil(r.T(), err)
+	data, err := marshalYAML(config)
+	assert.Nil(r.T(), err)
+	assert.Equal(r.T(), `type: google.api.Service
+config_version: 3
+name: petstore.endpoints.my-project.cloud.goog
+title: Petstore
+backend:
+  rules:
+    - selector: '*'
+      address: https://api.example.com
+      path_translation: APPEND_PATH_TO_ADDRESS
+    - selector: 1.petstore_endpoints_my_project_cloud_goog.getPet
+      address: https://pets.example.com
+      deadline: 5
+    - selector: 1.petstore_endpoints_my_project_cloud_goog.getPetsPetId
+      address: https://legacy.example.com
+      path_translation: CONSTANT_ADDRESS
+`, string(data))
+
+	data, err = json.Marshal(config.Backend.Rules[0])
+	assert.Nil(r.T(), err)
+	assert.JSONEq(r.T(), `{"selector": "*", "address": "https://api.example.com", "path_translation": "APPEND_PATH_TO_ADDRESS"}`, string(data))
+
+	_, err = petstore(r.T(), googleEndpointsPatch).GoogleServiceConfig(GoogleEndpointsOptions{})
+	assert.NotNil(r.T(), err)
+	_, err = petstore(r.T(), googleEndpointsPatch, `
+paths:
+  /pets/{petId}:
+    get:
+      operationId: null
+`).GoogleServiceConfig(r.options())
+	assert.NotNil(r.T(), err)
+}
+
+func TestGoogleEndpointsSuite(t *testing.T) {
+	suite.Run(t, new(GoogleEndpointsSuite))
+}
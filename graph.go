@@ -0,0 +1,223 @@
+package oas
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// graphColors maps node kinds to the fill colors used by WriteDOT.
+var graphColors = map[string]string{
+	"operation":       "white",
+	"schemas":         "lightblue",
+	"responses":       "lightsalmon",
+	"parameters":      "palegreen",
+	"requestBodies":   "khaki",
+	"headers":         "plum",
+	"examples":        "lightgrey",
+	"links":           "lightcyan",
+	"callbacks":       "pink",
+	"securitySchemes": "orange",
+	"pathItem":        "white",
+	"external":        "gray",
+}
+
+// GraphNode describes a component, an operation, a path item or an external
+// document of the reference graph.
+type GraphNode struct {
+	// ID describes the reference of the node (e.g. "#/components/schemas/Pet",
+	// "#/paths/~1pets/get" or an external URL).
+	ID string `json:"id" yaml:"id"`
+
+	// Kind describes the type of the node, the components section name for
+	// components (e.g. "schemas"), "operation", "pathItem" or "external".
+	Kind string `json:"kind" yaml:"kind"`
+
+	// Name describes a short human readable name of the node.
+	Name string `json:"name" yaml:"name"`
+
+	// References describes the number of distinct nodes referencing the node,
+	// a high value hints at a schema many parts of the API are coupled to.
+	References int `json:"references" yaml:"references"`
+}
+
+// GraphEdge describes that a node references another node.
+type GraphEdge struct {
+	// From describes the ID of the referencing node.
+	From string `json:"from" yaml:"from"`
+
+	// To describes the ID of the referenced node.
+	To string `json:"to" yaml:"to"`
+
+	// Count describes how many references the referencing node holds to the
+	// referenced node.
+	Count int `json:"count" yaml:"count"`
+}
+
+// Graph describes the reference structure of a document. It can be encoded
+// as JSON or written in the DOT language with WriteDOT.
+type Graph struct {
+	// Nodes describes the nodes sorted by ID.
+	Nodes []*GraphNode `json:"nodes" yaml:"nodes"`
+
+	// Edges describes the edges sorted by their from and to IDs.
+	Edges []*GraphEdge `json:"edges" yaml:"edges"`
+}
+
+// ReferenceGraph returns the graph of the components and operations of the
+// document and the $ref edges between them, including discriminator
+// mappings. References from nested locations are attributed to the
+// enclosing component or operation and references into nested locations to
+// the enclosing component.
+func (r OpenAPI) ReferenceGraph() *Graph {
+	nodes := map[string]*GraphNode{}
+	edges := map[[2]string]*GraphEdge{}
+	addNode := func(id string, kind string, name string) {
+		if nodes[id] == nil {
+			nodes[id] = &GraphNode{ID: id, Kind: kind, Name: name}
+		}
+	}
+
+	if r.Components != nil {
+		_ = (&walker{fn: func(ptr string, node interface{}) error {
+			if tokens := splitPointer(ptr); len(tokens) == 3 {
+				addNode("#"+ptr, tokens[1], tokens[2])
+			}
+			return nil
+		}}).components("/components", r.Components)
+	}
+	for _, op := range r.Paths.operations() {
+		addNode("#"+join("/paths", op.path, op.method), "operation", strings.ToUpper(op.method)+" "+op.path)
+	}
+
+	addEdge := func(ptr string, ref string) {
+		source := graphSource(ptr)
+		if source == nil || ref == "" {
+			return
+		}
+		from := source.ID
+		addNode(from, source.Kind, source.Name)
+		to := ref
+		if strings.HasPrefix(ref, "#/") {
+			tokens := splitPointer(ref[1:])
+			if len(tokens) < 3 || tokens[0] != "components" {
+				return
+			}
+			to = "#" + join("/components", tokens[1], tokens[2])
+			addNode(to, tokens[1], tokens[2])
+		} else {
+			addNode(to, "external", to)
+		}
+
+		key := [2]string{from, to}
+		if edges[key] == nil {
+			edges[key] = &GraphEdge{From: from, To: to}
+			nodes[to].References++
+		}
+		edges[key].Count++
+	}
+
+	_ = walk(&r, func(ptr string, node interface{}) error {
+		if ref := refOf(node); ref != nil && *ref != "" {
+			addEdge(ptr, *ref)
+		}
+		if schema, ok := node.(*Schema); ok && schema.Discriminator != nil {
+			for _, key := range sortedStrings(schema.Discriminator.Mapping) {
+				value := schema.Discriminator.Mapping[key]
+				if !strings.Contains(value, "/") {
+					value = "#/components/schemas/" + escapePointer(value)
+				}
+				addEdge(ptr, value)
+			}
+		}
+		return nil
+	})
+
+	graph := &Graph{
+		Nodes: make([]*GraphNode, 0, len(nodes)),
+		Edges: make([]*GraphEdge, 0, len(edges)),
+	}
+	for _, node := range nodes {
+		graph.Nodes = append(graph.Nodes, node)
+	}
+	for _, edge := range edges {
+		graph.Edges = append(graph.Edges, edge)
+	}
+	sort.Slice(graph.Nodes, func(i, j int) bool {
+		return graph.Nodes[i].ID < graph.Nodes[j].ID
+	})
+	sort.Slice(graph.Edges, func(i, j int) bool {
+		if graph.Edges[i].From != graph.Edges[j].From {
+			return graph.Edges[i].From < graph.Edges[j].From
+		}
+		return graph.Edges[i].To < graph.Edges[j].To
+	})
+	return graph
+}
+
+// graphSource returns the node of the component, operation or path item
+// enclosing the location addressed by the pointer, or nil for other
+// locations.
+func graphSource(ptr string) *GraphNode {
+	tokens := splitPointer(ptr)
+	switch {
+	case len(tokens) >= 3 && tokens[0] == "components":
+		return &GraphNode{ID: "#" + join("/components", tokens[1], tokens[2]), Kind: tokens[1], Name: tokens[2]}
+	case len(tokens) >= 3 && tokens[0] == "paths" && isOperationMethod(tokens[2]):
+		return &GraphNode{ID: "#" + join("/paths", tokens[1], tokens[2]), Kind: "operation", Name: strings.ToUpper(tokens[2]) + " " + tokens[1]}
+	case len(tokens) >= 2 && tokens[0] == "paths":
+		return &GraphNode{ID: "#" + join("/paths", tokens[1]), Kind: "pathItem", Name: tokens[1]}
+	}
+	return nil
+}
+
+// isOperationMethod reports whether the token names an operation of a path
+// item.
+func isOperationMethod(token string) bool {
+	switch token {
+	case "get", "put", "post", "delete", "options", "head", "patch", "trace":
+		return true
+	}
+	return false
+}
+
+// WriteDOT writes the graph in the DOT language of Graphviz. Nodes are
+// filled with a color per kind and labeled with their name and the number
+// of nodes referencing them, edges referencing a node multiple times are
+// labeled with the count.
+func (r *Graph) WriteDOT(w io.Writer) error {
+	lines := []string{
+		"digraph references {",
+		"  rankdir=LR;",
+		`  node [shape=box, style=filled, fontname="Helvetica"];`,
+	}
+	for _, node := range r.Nodes {
+		color := graphColors[node.Kind]
+		if color == "" {
+			color = "white"
+		}
+		label := node.Name
+		if node.References > 0 {
+			label += " (" + strconv.Itoa(node.References) + ")"
+		}
+		lines = append(lines, fmt.Sprintf("  %s [label=%s, fillcolor=%s, tooltip=%s];",
+			strconv.Quote(node.ID), strconv.Quote(label), color, strconv.Quote(node.Kind)))
+	}
+	for _, edge := range r.Edges {
+		attrs := ""
+		if edge.Count > 1 {
+			attrs = fmt.Sprintf(" [label=%q]", strconv.Itoa(edge.Count))
+		}
+		lines = append(lines, fmt.Sprintf("  %s -> %s%s;", strconv.Quote(edge.From), strconv.Quote(edge.To), attrs))
+	}
+	lines = append(lines, "}")
+
+	if _, err := io.WriteString(w, strings.Join(lines, "\n")+"\n"); err != nil {
+		return errors.WithStack(err)
+	}
+	return nil
+}
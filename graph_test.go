@@ -0,0 +1,103 @@
+package oas
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+)
+
+type GraphSuite struct {
+	suite.Suite
+}
+
+// graphPatch is the patch of the petstore fixture the suite draws.
+const graphPatch = `
+paths:
+  /pets:
+    get:
+      responses:
+        "404":
+          $ref: "#/components/responses/NotFound"
+components:
+  schemas:
+    Pet:
+      type: null
+      required: null
+      properties: null
+      oneOf:
+        - $ref: "#/components/schemas/Cat"
+        - $ref: "#/components/schemas/Dog"
+      discriminator:
+        propertyName: kind
+        mapping:
+          cat: Cat
+          dog: "#/components/schemas/Dog"
+    Cat:
+      type: object
+    Dog:
+      properties:
+        owner:
+          $ref: owner.yaml#/Owner
+  responses:
+    NotFound:
+      description: Not found.
+      content:
+        application/json:
+          schema:
+            $ref: "#/components/schemas/Pet"
+`
+
+func (r *GraphSuite) TestReferenceGraph() {
+	graph := petstore(r.T(), graphPatch).ReferenceGraph()
+
+	assert.Equal(r.T(), []*GraphNode{
+		{ID: "#/components/responses/NotFound", Kind: "responses", Name: "NotFound", References: 1},
+		{ID: "#/components/schemas/Cat", Kind: "schemas", Name: "Cat", References: 1},
+		{ID: "#/components/schemas/Dog", Kind: "schemas", Name: "Dog", References: 1},
+		{ID: "#/components/schemas/Pet", Kind: "schemas", Name: "Pet", References: 4},
+		{ID: "#/paths/~1pets/get", Kind: "operation", Name: "GET /pets"},
+		{ID: "#/paths/~1pets/post", Kind: "operation", Name: "POST /pets"},
+		{ID: "#/paths/~1pets~1{petId}/delete", Kind: "operation", Name: "DELETE /pets/{petId}"},
+		{ID: "#/paths/~1pets~1{petId}/get", Kind: "operation", Name: "GET /pets/{petId}"},
+		{ID: "owner.yaml#/Owner", Kind: "external", Name: "owner.yaml#/Owner", References: 1},
+	}, graph.Nodes)
+	assert.Equal(r.T(), []*GraphEdge{
+		{From: "#/components/responses/NotFound", To: "#/components/schemas/Pet", Count: 1},
+		{From: "#/components/schemas/Dog", To: "owner.yaml#/Owner", Count: 1},
+		{From: "#/components/schemas/Pet", To: "#/components/schemas/Cat", Count: 2},
+		{From: "#/components/schemas/Pet", To: "#/components/schemas/Dog", Count: 2},
+		{From: "#/paths/~1pets/get", To: "#/components/responses/NotFound", Count: 1},
+		{From: "#/paths/~1pets/get", To: "#/components/schemas/Pet", Count: 1},
+		{From: "#/paths/~1pets/post", To: "#/components/schemas/Pet", Count: 1},
+		{From: "#/paths/~1pets~1{petId}/get", To: "#/components/schemas/Pet", Count: 1},
+	}, graph.Edges)
+}
+
+func (r *GraphSuite) TestWriteDOT() {
+	graph := &Graph{
+		Nodes: []*GraphNode{
+			{ID: "#/components/schemas/Pet", Kind: "schemas", Name: "Pet", References: 1},
+			{ID: "#/paths/~1pets/get", Kind: "operation", Name: "GET /pets"},
+		},
+		Edges: []*GraphEdge{
+			{From: "#/paths/~1pets/get", To: "#/components/schemas/Pet", Count: 2},
+		},
+	}
+
+	buffer := &bytes.Buffer{}
+	assert.Nil(r.T(), graph.WriteDOT(buffer))
+	assert.Equal(r.T(), `digraph references {
+  rankdir=LR;
+  node [shape=box, style=filled, fontname="Helvetica"];
+  "#/components/schemas/Pet" [label="Pet (1)", fillcolor=lightblue, tooltip="schemas"];
+  "#/paths/~1pets/get" [label="GET /pets", fillcolor=white, tooltip="operation"];
+  "#/paths/~1pets/get" -> "#/components/schemas/Pet" [label="2"];
+}
+`, buffer.String())
+}
+
+func TestGraphSuite(t *testing.T) {
+	suite.Run(t, new(GraphSuite))
+}
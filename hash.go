@@ -0,0 +1,126 @@
+package oas
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"sort"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// HashOptions describes the behavior of ComponentHashes.
+type HashOptions struct {
+	// Transitive folds the hashes of the components a component references,
+	// directly or not, into its hash, so that it changes whenever anything
+	// the component depends on changes.
+	Transitive bool
+}
+
+// ComponentHashes returns the hex encoded SHA-256 hash of the content of
+// every component by pointer (e.g. "/components/schemas/Pet"). Hashes are
+// computed over the canonical JSON encoding of the components, so they are
+// stable across formatting, key order and the choice between JSON and YAML,
+// and change only when the content does. Code generators can compare them
+// between runs to skip regenerating unchanged types.
+func (r OpenAPI) ComponentHashes(opts HashOptions) (map[string]string, error) {
+	hashes := map[string]string{}
+	if r.Components == nil {
+		return hashes, nil
+	}
+	components, err := genericValue(r.Components)
+	if err != nil {
+		return nil, err
+	}
+
+	refs := map[string][]string{}
+	for kind, value := range components.(map[string]interface{}) {
+		entries, ok := value.(map[string]interface{})
+		if !ok || strings.HasPrefix(kind, "x-") {
+			continue
+		}
+		for name, component := range entries {
+			ptr := join("/components", kind, name)
+			hash, err := contentHash(component)
+			if err != nil {
+				return nil, errors.Wrap(err, ptr)
+			}
+			hashes[ptr] = hash
+			refs[ptr] = componentRefs(component)
+		}
+	}
+	if !opts.Transitive {
+		return hashes, nil
+	}
+
+	transitive := map[string]string{}
+	for ptr := range hashes {
+		reachable := map[string]bool{ptr: true}
+		queue := []string{ptr}
+		for len(queue) > 0 {
+			current := queue[0]
+			queue = queue[1:]
+			for _, ref := range refs[current] {
+				if _, ok := hashes[ref]; ok && !reachable[ref] {
+					reachable[ref] = true
+					queue = append(queue, ref)
+				}
+			}
+		}
+		entries := make([]string, 0, len(reachable))
+		for ref := range reachable {
+			entries = append(entries, ref+"="+hashes[ref])
+		}
+		sort.Strings(entries)
+		sum := sha256.Sum256([]byte(strings.Join(entries, "\n")))
+		transitive[ptr] = hex.EncodeToString(sum[:])
+	}
+	return transitive, nil
+}
+
+// contentHash returns the hex encoded SHA-256 hash of the canonical JSON
+// encoding of the generic value, whose object keys are sorted.
+func contentHash(value interface{}) (string, error) {
+	rbytes, err := json.Marshal(value)
+	if err != nil {
+		return "", errors.WithStack(err)
+	}
+	sum := sha256.Sum256(rbytes)
+	return hex.EncodeToString(sum[:]), nil
+}
+
+// componentRefs returns the sorted pointers of the components referenced
+// within the generic value.
+func componentRefs(value interface{}) []string {
+	set := map[string]bool{}
+	collectComponentRefs(value, set)
+	ptrs := make([]string, 0, len(set))
+	for ptr := range set {
+		ptrs = append(ptrs, ptr)
+	}
+	sort.Strings(ptrs)
+	return ptrs
+}
+
+// collectComponentRefs adds the pointers of the components referenced within
+// the generic value to the set. References into a component count as
+// references to the component.
+func collectComponentRefs(value interface{}, set map[string]bool) {
+	switch value := value.(type) {
+	case map[string]interface{}:
+		if ref, ok := value["$ref"].(string); ok && strings.HasPrefix(ref, "#/components/") {
+			tokens := strings.SplitN(strings.TrimPrefix(ref, "#"), "/", 5)
+			if len(tokens) >= 4 {
+				set[strings.Join(tokens[:4], "/")] = true
+			}
+		}
+		for _, item := range value {
+			collectComponentRefs(item, set)
+		}
+	case []interface{}:
+		for _, item := range value {
+			collectComponentRefs(item, set)
+		}
+	}
+}
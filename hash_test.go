@@ -0,0 +1,109 @@
+package oas
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+)
+
+type HashSuite struct {
+	suite.Suite
+}
+
+const hashJSON = `{
+  "openapi": "3.0.0",
+  "info": {"title": "Petstore", "version": "1.0.0"},
+  "paths": {},
+  "components": {
+    "schemas": {
+      "Pet": {"type": "object", "properties": {"name": {"type": "string"}, "age": {"type": "integer", "minimum": 0}}},
+      "Owner": {"type": "object", "properties": {"pets": {"type": "array", "items": {"$ref": "#/components/schemas/Pet"}}}},
+      "Tag": {"type": "string"}
+    },
+    "responses": {
+      "Pets": {"description": "Pets.", "content": {"application/json": {"schema": {"$ref": "#/components/schemas/Owner/properties/pets"}}}}
+    }
+  }
+}`
+
+const hashYAML = `
+openapi: 3.0.0
+info:
+  title: Petstore
+  version: 1.0.0
+paths: {}
+components:
+  responses:
+    Pets:
+      description: Pets.
+      content:
+        application/json:
+          schema:
+            $ref: '#/components/schemas/Owner/properties/pets'
+  schemas:
+    Tag:
+      type: string
+    Owner:
+      properties:
+        pets:
+          items:
+            $ref: '#/components/schemas/Pet'
+          type: array
+      type: object
+    Pet:
+      properties:
+        age:
+          minimum: 0
+          type: integer
+        name:
+          type: string
+      type: object
+`
+
+func (r *HashSuite) decode(data string) *OpenAPI {
+	doc := &OpenAPI{}
+	assert.Nil(r.T(), decodeDocument([]byte(data), doc))
+	return doc
+}
+
+func (r *HashSuite) TestComponentHashes() {
+	fromJSON, err := r.decode(hashJSON).ComponentHashes(HashOptions{})
+	assert.Nil(r.T(), err)
+	fromYAML, err := r.decode(hashYAML).ComponentHashes(HashOptions{})
+	assert.Nil(r.T(), err)
+	assert.Equal(r.T(), fromJSON, fromYAML)
+	assert.Len(r.T(), fromJSON, 4)
+	assert.Len(r.T(), fromJSON["/components/schemas/Pet"], 64)
+	assert.NotEqual(r.T(), fromJSON["/components/schemas/Pet"], fromJSON["/components/schemas/Owner"])
+
+	doc := r.decode(hashJSON)
+	doc.Components.Schemas["Pet"].Properties.Get("name").MaxLength = 64
+	changed, err := doc.ComponentHashes(HashOptions{})
+	assert.Nil(r.T(), err)
+	assert.NotEqual(r.T(), fromJSON["/components/schemas/Pet"], changed["/components/schemas/Pet"])
+	assert.Equal(r.T(), fromJSON["/components/schemas/Owner"], changed["/components/schemas/Owner"])
+}
+
+func (r *HashSuite) TestTransitiveHashes() {
+	before, err := r.decode(hashJSON).ComponentHashes(HashOptions{Transitive: true})
+	assert.Nil(r.T(), err)
+
+	doc := r.decode(hashJSON)
+	doc.Components.Schemas["Pet"].Properties.Get("name").MaxLength = 64
+	after, err := doc.ComponentHashes(HashOptions{Transitive: true})
+	assert.Nil(r.T(), err)
+
+	assert.NotEqual(r.T(), before["/components/schemas/Pet"], after["/components/schemas/Pet"])
+	assert.NotEqual(r.T(), before["/components/schemas/Owner"], after["/components/schemas/Owner"])
+	assert.NotEqual(r.T(), before["/components/responses/Pets"], after["/components/responses/Pets"])
+	assert.Equal(r.T(), before["/components/schemas/Tag"], after["/components/schemas/Tag"])
+
+	hashes, err := (&OpenAPI{}).ComponentHashes(HashOptions{Transitive: true})
+	assert.Nil(r.T(), err)
+	assert.Empty(r.T(), hashes)
+}
+
+func TestHashSuite(t *testing.T) {
+	suite.Run(t, new(HashSuite))
+}
@@ -4,7 +4,7 @@ import (
 	"encoding/json"
 
 	"github.com/pkg/errors"
-	"gopkg.in/yaml.v2"
+	"gopkg.in/yaml.v3"
 )
 
 // Header follows the structure of the Parameter with the following change.
@@ -106,22 +106,7 @@ func (r Header) MarshalJSON() ([]byte, error) {
 
 // UnmarshalJSON parses the JSON-encoded data and stores the result.
 func (r *Header) UnmarshalJSON(data []byte) error {
-	return r.UnmarshalYAML(func(in interface{}) error {
-		obj := make(map[string]interface{})
-		if err := json.Unmarshal(data, &obj); err != nil {
-			return errors.WithStack(err)
-		}
-
-		rbytes, err := yaml.Marshal(obj)
-		if err != nil {
-			return errors.WithStack(err)
-		}
-
-		if err := yaml.Unmarshal(rbytes, in); err != nil {
-			return errors.WithStack(err)
-		}
-		return nil
-	})
+	return decodeJSON(data, r)
 }
 
 // MarshalYAML returns the YAML encoding.
@@ -184,7 +169,13 @@ func (r Header) MarshalYAML() (interface{}, error) {
 }
 
 // UnmarshalYAML parses the YAML-encoded data and stores the result.
-func (r *Header) UnmarshalYAML(unmarshal func(interface{}) error) error {
+func (r *Header) UnmarshalYAML(node *yaml.Node) error {
+	return decodeYAML(node, r)
+}
+
+// unmarshalValue stores the result of the unmarshal function, which decodes
+// either JSON or YAML data.
+func (r *Header) unmarshalValue(d *valueDecoder, unmarshal func(interface{}) error) error {
 	obj := make(map[string]interface{})
 	if err := unmarshal(&obj); err != nil {
 		return errors.WithStack(err)
@@ -238,14 +229,10 @@ func (r *Header) UnmarshalYAML(unmarshal func(interface{}) error) error {
 		}
 	}
 
-	if value, ok := obj["schema"]; ok {
-		rbytes, err := yaml.Marshal(value)
-		if err != nil {
-			return errors.WithStack(err)
-		}
+	if raw, ok := obj["schema"]; ok {
 		value := Schema{}
-		if err := yaml.Unmarshal(rbytes, &value); err != nil {
-			return errors.WithStack(err)
+		if err := d.decodeMember("schema", raw, &value); err != nil {
+			return err
 		}
 		r.Schema = &value
 	}
@@ -254,26 +241,18 @@ func (r *Header) UnmarshalYAML(unmarshal func(interface{}) error) error {
 		r.Example = value
 	}
 
-	if value, ok := obj["examples"]; ok {
-		rbytes, err := yaml.Marshal(value)
-		if err != nil {
-			return errors.WithStack(err)
-		}
+	if raw, ok := obj["examples"]; ok {
 		value := map[string]*Example{}
-		if err := yaml.Unmarshal(rbytes, &value); err != nil {
-			return errors.WithStack(err)
+		if err := d.decodeMember("examples", raw, &value); err != nil {
+			return err
 		}
 		r.Examples = value
 	}
 
-	if value, ok := obj["content"]; ok {
-		rbytes, err := yaml.Marshal(value)
-		if err != nil {
-			return errors.WithStack(err)
-		}
+	if raw, ok := obj["content"]; ok {
 		value := map[string]*MediaType{}
-		if err := yaml.Unmarshal(rbytes, &value); err != nil {
-			return errors.WithStack(err)
+		if err := d.decodeMember("content", raw, &value); err != nil {
+			return err
 		}
 		r.Content = value
 	}
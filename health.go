@@ -0,0 +1,225 @@
+package oas
+
+import (
+	"context"
+	"math"
+)
+
+// Categories of the health score.
+const (
+	HealthLint          = "lint"
+	HealthDocumentation = "documentation"
+	HealthSecurity      = "security"
+	HealthSchemas       = "schemas"
+)
+
+// Rules of the findings reported by the documentation category of Health.
+const (
+	RuleDescriptionMissing = "description-missing"
+	RuleExampleMissing     = "example-missing"
+)
+
+// DefaultHealthWeights holds the weights of the categories used by Health
+// when none are given.
+var DefaultHealthWeights = map[string]float64{
+	HealthLint:          0.3,
+	HealthDocumentation: 0.2,
+	HealthSecurity:      0.3,
+	HealthSchemas:       0.2,
+}
+
+// severityPenalties holds how much a finding of each severity lowers the
+// score of its category, relative to the size of the document.
+var severityPenalties = map[Severity]float64{
+	SeverityError:   1,
+	SeverityWarning: 0.5,
+	SeverityInfo:    0.1,
+}
+
+// HealthOptions describes how Health scores a document.
+type HealthOptions struct {
+	// Weights describes the weight of each category in the overall score.
+	// Categories without a weight do not contribute. When nil,
+	// DefaultHealthWeights is used.
+	Weights map[string]float64
+
+	// Semantic describes the rules disabled in the lint category.
+	Semantic SemanticOptions
+
+	// Complexity describes the thresholds checked in the schemas category.
+	Complexity ComplexityThresholds
+}
+
+// CategoryHealth describes the score of a single category.
+type CategoryHealth struct {
+	// Category describes the name of the category (e.g. "lint").
+	Category string `json:"category" yaml:"category"`
+
+	// Score describes the score of the category from 0 to 100.
+	Score float64 `json:"score" yaml:"score"`
+
+	// Weight describes the share of the category in the overall score, the
+	// weights of all categories summing up to 1.
+	Weight float64 `json:"weight" yaml:"weight"`
+
+	// Total describes the number of items the category is measured against:
+	// operations for lint and security, schemas for schemas and documentable
+	// objects for documentation.
+	Total int `json:"total" yaml:"total"`
+
+	// Findings describes the findings which lowered the score.
+	Findings []*Finding `json:"findings" yaml:"findings"`
+}
+
+// Health describes the health score of a document.
+type Health struct {
+	// Score describes the weighted score of the categories from 0 to 100.
+	Score float64 `json:"score" yaml:"score"`
+
+	// Categories describes the score of each category, in the order lint,
+	// documentation, security and schemas.
+	Categories []*CategoryHealth `json:"categories" yaml:"categories"`
+}
+
+// Health scores the document from 0 to 100 so dashboards can track many
+// services side by side. The score weighs four categories: lint, counting
+// the violations of Validate and ValidateSemantics per operation;
+// documentation, the share of operations, parameters, component schemas and
+// their properties declaring a description and of media types declaring an
+// example; security, counting the findings of AuditSecurity and AuditOWASP
+// per operation; and schemas, counting the findings of AuditBounds,
+// AuditPresence and AuditComplexity per schema. Findings lower the score of
+// their category by 1 for errors, 0.5 for warnings and 0.1 for infos per
+// operation or schema, down to 0.
+func (r *OpenAPI) Health(ctx context.Context, opts HealthOptions) (*Health, error) {
+	weights := opts.Weights
+	if weights == nil {
+		weights = DefaultHealthWeights
+	}
+
+	operations := len(r.Paths.operations())
+	lint := make([]*Finding, 0)
+	for _, validate := range []func() error{
+		func() error { return r.Validate(ctx) },
+		func() error { return r.ValidateSemantics(ctx, opts.Semantic) },
+	} {
+		err := validate()
+		if verr, ok := err.(*ValidationError); ok {
+			lint = append(lint, verr.Violations...)
+		} else if err != nil {
+			return nil, err
+		}
+	}
+	sortFindings(lint)
+
+	security := append(r.AuditSecurity(), r.AuditOWASP()...)
+	sortFindings(security)
+
+	schemas := append(r.AuditBounds(), r.AuditPresence()...)
+	schemas = append(schemas, r.AuditComplexity(opts.Complexity)...)
+	sortFindings(schemas)
+
+	complexities := len(r.SchemaComplexities())
+	documented, documentable, documentation := r.documentation()
+	categories := []*CategoryHealth{
+		{Category: HealthLint, Score: findingsScore(lint, operations), Total: operations, Findings: lint},
+		{Category: HealthDocumentation, Score: coverageScore(documented, documentable), Total: documentable, Findings: documentation},
+		{Category: HealthSecurity, Score: findingsScore(security, operations), Total: operations, Findings: security},
+		{Category: HealthSchemas, Score: findingsScore(schemas, complexities), Total: complexities, Findings: schemas},
+	}
+
+	total := 0.0
+	for _, category := range categories {
+		if weight := weights[category.Category]; weight > 0 {
+			total += weight
+		}
+	}
+	health := &Health{Categories: categories}
+	for _, category := range categories {
+		if weight := weights[category.Category]; weight > 0 && total > 0 {
+			category.Weight = weight / total
+			health.Score += category.Score * category.Weight
+		}
+	}
+	health.Score = roundScore(health.Score)
+	return health, nil
+}
+
+// documentation returns the number of documented objects, the number of
+// documentable objects and a finding for every undocumented one.
+func (r *OpenAPI) documentation() (int, int, []*Finding) {
+	documented, documentable := 0, 0
+	findings := make([]*Finding, 0)
+	check := func(ptr string, ok bool, rule string, message string) {
+		documentable++
+		if ok {
+			documented++
+			return
+		}
+		findings = append(findings, &Finding{Pointer: ptr, Rule: rule, Severity: SeverityInfo, Message: message})
+	}
+
+	_ = walk(r, func(ptr string, node interface{}) error {
+		switch node := node.(type) {
+		case *Operation:
+			check(ptr, node.Summary != "" || node.Description != "", RuleDescriptionMissing,
+				"operation declares neither a summary nor a description")
+		case *Parameter:
+			if node.Ref == "" {
+				check(ptr, node.Description != "", RuleDescriptionMissing,
+					"parameter "+node.Name+" declares no description")
+			}
+		case *MediaType:
+			schema := NewResolver(r).schema(node.Schema)
+			check(ptr, node.Example != nil || len(node.Examples) > 0 || (schema != nil && schema.Example != nil),
+				RuleExampleMissing, "media type declares no example")
+		}
+		return nil
+	})
+
+	if r.Components != nil {
+		for _, name := range sortedKeys(r.Components.Schemas) {
+			schema := r.Components.Schemas[name]
+			if schema != nil && schema.Ref == "" {
+				check(join("/components/schemas", name), schema.Description != "", RuleDescriptionMissing,
+					"schema declares no description")
+			}
+		}
+	}
+	for _, property := range r.properties() {
+		if property.schema.Ref == "" {
+			check(property.field.Pointer, property.schema.Description != "", RuleDescriptionMissing,
+				"property "+property.field.Name+" declares no description")
+		}
+	}
+
+	sortFindings(findings)
+	return documented, documentable, findings
+}
+
+// findingsScore returns the score of findings reported against a number of
+// items. Documents without items score by their findings alone.
+func findingsScore(findings []*Finding, items int) float64 {
+	if items < 1 {
+		items = 1
+	}
+	penalty := 0.0
+	for _, finding := range findings {
+		penalty += severityPenalties[finding.Severity]
+	}
+	return roundScore(100 * math.Max(0, 1-penalty/float64(items)))
+}
+
+// coverageScore returns the share of covered items as a score. Documents
+// without items are fully covered.
+func coverageScore(covered int, items int) float64 {
+	if items == 0 {
+		return 100
+	}
+	return roundScore(100 * float64(covered) / float64(items))
+}
+
+// roundScore rounds the score to one decimal.
+func roundScore(score float64) float64 {
+	return math.Round(score*10) / 10
+}
@@ -0,0 +1,176 @@
+package oas
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+)
+
+type HealthSuite struct {
+	suite.Suite
+}
+
+// healthPatch is the patch of the petstore fixture the suite scores, leaving the
+// one operation the scores are computed for.
+const healthPatch = `
+servers:
+  - url: https://api.example.com
+security:
+  - apiKey: []
+paths:
+  /pets: null
+  /pets/{petId}:
+    parameters: null
+    get:
+      summary: Show a pet
+      parameters:
+        - name: petId
+          in: path
+          description: identifier of the pet
+          required: true
+          schema:
+            type: string
+            format: uuid
+      responses:
+        "200":
+          description: pet
+        "401":
+          description: unauthorized
+        "403":
+          description: forbidden
+        "429":
+          description: too many requests
+    delete: null
+components:
+  schemas:
+    Pet:
+      description: a pet
+      required: [id, name]
+      example:
+        id: 1
+        name: Rex
+      properties:
+        id:
+          type: integer
+          description: identifier of the pet
+        name:
+          minLength: 1
+        tag: null
+  securitySchemes:
+    apiKey:
+      type: apiKey
+      name: X-API-Key
+      in: header
+`
+
+func (r *HealthSuite) TestHealth() {
+	testCases := []struct {
+		patch    string
+		weights  map[string]float64
+		score    float64
+		scores   []float64
+		findings []string
+	}{
+		{
+			"",
+			nil,
+			96.7,
+			[]float64{100, 83.3, 100, 100},
+			[]string{
+				"documentation /components/schemas/Pet/properties/name " + RuleDescriptionMissing,
+			},
+		},
+		{
+			`
+security: null
+paths:
+  /pets/{petId}:
+    get:
+      summary: null
+      responses:
+        "401":
+          description: ""
+`,
+			nil,
+			48.3,
+			[]float64{0, 66.7, 50, 100},
+			[]string{
+				"lint /paths/~1pets~1{petId}/get/responses/401/description " + RuleFieldRequired,
+				"documentation /components/schemas/Pet/properties/name " + RuleDescriptionMissing,
+				"documentation /paths/~1pets~1{petId}/get " + RuleDescriptionMissing,
+				"security /paths/~1pets~1{petId}/get " + RuleSecurityMissing,
+			},
+		},
+		{
+			"security: null",
+			map[string]float64{HealthSecurity: 1, HealthSchemas: 1},
+			75,
+			[]float64{100, 83.3, 50, 100},
+			[]string{
+				"documentation /components/schemas/Pet/properties/name " + RuleDescriptionMissing,
+				"security /paths/~1pets~1{petId}/get " + RuleSecurityMissing,
+			},
+		},
+	}
+
+	for i, testCase := range testCases {
+		failMsg := "test case %d failed"
+		report, err := petstore(r.T(), healthPatch, testCase.patch).Health(context.Background(), HealthOptions{Weights: testCase.weights})
+		if !assert.Nil(r.T(), err, failMsg, i) {
+			continue
+		}
+		assert.Equal(r.T(), testCase.score, report.Score, failMsg, i)
+
+		scores := make([]float64, 0)
+		findings := make([]string, 0)
+		for _, category := range report.Categories {
+			scores = append(scores, category.Score)
+			for _, finding := range category.Findings {
+				findings = append(findings, category.Category+" "+finding.Pointer+" "+finding.Rule)
+			}
+		}
+		assert.Equal(r.T(), testCase.scores, scores, failMsg, i)
+		assert.Equal(r.T(), testCase.findings, findings, failMsg, i)
+	}
+}
+
+func (r *HealthSuite) TestHealthWeights() {
+	report, err := petstore(r.T(), healthPatch).Health(context.Background(), HealthOptions{
+		Weights: map[string]float64{HealthLint: 3, HealthDocumentation: 1},
+	})
+	if !assert.Nil(r.T(), err) {
+		return
+	}
+	weights := map[string]float64{}
+	totals := map[string]int{}
+	for _, category := range report.Categories {
+		weights[category.Category] = category.Weight
+		totals[category.Category] = category.Total
+	}
+	assert.Equal(r.T(), map[string]float64{
+		HealthLint:          0.75,
+		HealthDocumentation: 0.25,
+		HealthSecurity:      0,
+		HealthSchemas:       0,
+	}, weights)
+	assert.Equal(r.T(), map[string]int{
+		HealthLint:          1,
+		HealthDocumentation: 6,
+		HealthSecurity:      1,
+		HealthSchemas:       2,
+	}, totals)
+	assert.Equal(r.T(), 95.8, report.Score)
+}
+
+func (r *HealthSuite) TestHealthCanceled() {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	_, err := petstore(r.T(), healthPatch).Health(ctx, HealthOptions{})
+	assert.Equal(r.T(), context.Canceled, err)
+}
+
+func TestHealthSuite(t *testing.T) {
+	suite.Run(t, new(HealthSuite))
+}
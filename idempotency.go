@@ -0,0 +1,162 @@
+package oas
+
+import (
+	"context"
+	"net/http"
+	"strings"
+)
+
+// IdempotentExtension names the specification extension marking an
+// operation as safe to retry when the client sends the same idempotency key.
+const IdempotentExtension = "x-idempotent"
+
+// IdempotencyKeyHeader names the header carrying the idempotency key of a
+// request.
+const IdempotencyKeyHeader = "Idempotency-Key"
+
+// RuleIdempotencyKeyMissing names the rule flagging POST and PATCH
+// operations marked idempotent which do not declare the Idempotency-Key
+// header parameter.
+const RuleIdempotencyKeyMissing = "idempotency-key-missing"
+
+// Idempotent reports whether the x-idempotent extension marks the object as
+// idempotent.
+func (r Extensions) Idempotent() (bool, error) {
+	var idempotent bool
+	if _, err := r.decode(IdempotentExtension, &idempotent); err != nil {
+		return false, err
+	}
+	return idempotent, nil
+}
+
+// SetIdempotent marks the object as idempotent with the x-idempotent
+// extension, or removes the extension when false.
+func (r *Extensions) SetIdempotent(idempotent bool) {
+	if !idempotent {
+		delete(*r, IdempotentExtension)
+		return
+	}
+	setExtension(r, IdempotentExtension, true)
+}
+
+// DeclareIdempotent marks the operation under the path and lowercase method
+// as idempotent and declares the required Idempotency-Key header parameter,
+// unless the operation or its path item already declares it.
+func (r *OpenAPI) DeclareIdempotent(path string, method string) error {
+	target, err := r.Paths.operation(path, method)
+	if err != nil {
+		return err
+	}
+
+	target.operation.Extensions.SetIdempotent(true)
+	if r.idempotencyKeyParameter(target.item, target.operation) != nil {
+		return nil
+	}
+	target.operation.Parameters = append(target.operation.Parameters, &Parameter{
+		Name: IdempotencyKeyHeader,
+		In:   "header",
+		Header: Header{
+			Description: "Unique key identifying the request, so that retries are applied at most once.",
+			Required:    true,
+			Schema:      &Schema{Type: "string"},
+		},
+	})
+	return nil
+}
+
+// idempotencyKeyParameter returns the Idempotency-Key header parameter
+// declared by the operation or its path item, nil if there is none.
+func (r OpenAPI) idempotencyKeyParameter(item *PathItem, operation *Operation) *Parameter {
+	parameters := append([]*Parameter{}, operation.Parameters...)
+	if item != nil {
+		parameters = append(parameters, item.Parameters...)
+	}
+	for _, parameter := range parameters {
+		_, parameter = NewResolver(&r).parameter("", parameter)
+		if parameter != nil && parameter.In == "header" && strings.EqualFold(parameter.Name, IdempotencyKeyHeader) {
+			return parameter
+		}
+	}
+	return nil
+}
+
+// AuditIdempotency flags POST and PATCH operations marked idempotent with
+// the x-idempotent extension which do not declare the Idempotency-Key header
+// parameter clients retry them with. Malformed extensions are reported as
+// well.
+func (r OpenAPI) AuditIdempotency() []*Finding {
+	findings := make([]*Finding, 0)
+	for _, op := range r.Paths.operations() {
+		ptr := join("/paths", op.path, op.method)
+		idempotent, err := op.operation.Extensions.Idempotent()
+		if err != nil {
+			findings = append(findings, &Finding{
+				Pointer:  join(ptr, IdempotentExtension),
+				Rule:     RuleIdempotencyKeyMissing,
+				Severity: SeverityError,
+				Message:  err.Error(),
+			})
+			continue
+		}
+		if !idempotent || (op.method != "post" && op.method != "patch") {
+			continue
+		}
+		if r.idempotencyKeyParameter(op.item, op.operation) == nil {
+			findings = append(findings, &Finding{
+				Pointer:  join(ptr, "parameters"),
+				Rule:     RuleIdempotencyKeyMissing,
+				Severity: SeverityWarning,
+				Message:  "idempotent operation does not declare the " + IdempotencyKeyHeader + " header",
+			})
+		}
+	}
+	sortFindings(findings)
+	return findings
+}
+
+// IdempotencyKeys extracts the idempotency keys of requests matched to
+// operations marked idempotent.
+type IdempotencyKeys struct {
+	// Doc describes the document references of the parameters are resolved
+	// against.
+	Doc *OpenAPI
+}
+
+// Middleware returns a handler which stores the Idempotency-Key header of
+// requests matched to idempotent operations in the request context before
+// calling next. Requests omitting the header are answered with 400 when the
+// operation declares it required. It must run inside the router middleware,
+// requests carrying no route are passed through unchanged.
+func (r IdempotencyKeys) Middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		route, ok := RouteFromContext(req.Context())
+		if !ok {
+			next.ServeHTTP(w, req)
+			return
+		}
+		if idempotent, _ := route.Operation.Extensions.Idempotent(); !idempotent {
+			next.ServeHTTP(w, req)
+			return
+		}
+
+		key := req.Header.Get(IdempotencyKeyHeader)
+		if key == "" {
+			parameter := r.Doc.idempotencyKeyParameter(route.PathItem, route.Operation)
+			if parameter != nil && parameter.Required {
+				http.Error(w, "missing "+IdempotencyKeyHeader+" header", http.StatusBadRequest)
+				return
+			}
+			next.ServeHTTP(w, req)
+			return
+		}
+		next.ServeHTTP(w, req.WithContext(context.WithValue(req.Context(), idempotencyKeyContextKey, key)))
+	})
+}
+
+// IdempotencyKey returns the idempotency key stored in the context of the
+// request by the IdempotencyKeys middleware, or an empty string if there is
+// none.
+func IdempotencyKey(req *http.Request) string {
+	key, _ := req.Context().Value(idempotencyKeyContextKey).(string)
+	return key
+}
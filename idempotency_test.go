@@ -0,0 +1,120 @@
+package oas
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+)
+
+type IdempotencySuite struct {
+	suite.Suite
+}
+
+// idempotencyPatch is the patch of the petstore fixture declaring the
+// Idempotency-Key header on the path of the pet.
+const idempotencyPatch = `
+paths:
+  /pets/{petId}:
+    parameters:
+      - name: petId
+        in: path
+        required: true
+        schema:
+          type: integer
+      - name: idempotency-key
+        in: header
+    put:
+      operationId: updatePet
+      responses:
+        "200":
+          description: Replaced.
+`
+
+func (r *IdempotencySuite) TestDeclareIdempotent() {
+	doc := petstore(r.T(), idempotencyPatch)
+	assert.Nil(r.T(), doc.DeclareIdempotent("/pets", "post"))
+	assert.Nil(r.T(), doc.DeclareIdempotent("/pets", "post"))
+	assert.Nil(r.T(), doc.DeclareIdempotent("/pets/{petId}", "put"))
+	assert.NotNil(r.T(), doc.DeclareIdempotent("/pets", "put"))
+
+	operation := doc.Paths.PathItems.Get("/pets").Post
+	idempotent, err := operation.Extensions.Idempotent()
+	assert.Nil(r.T(), err)
+	assert.True(r.T(), idempotent)
+	assert.Len(r.T(), operation.Parameters, 1)
+	assert.Equal(r.T(), IdempotencyKeyHeader, operation.Parameters[0].Name)
+	assert.True(r.T(), operation.Parameters[0].Required)
+	assert.Empty(r.T(), doc.Paths.PathItems.Get("/pets/{petId}").Put.Parameters)
+	assert.Empty(r.T(), doc.AuditIdempotency())
+
+	operation.Extensions.SetIdempotent(false)
+	assert.Empty(r.T(), operation.Extensions)
+}
+
+func (r *IdempotencySuite) TestAuditIdempotency() {
+	findings := petstore(r.T(), idempotencyPatch, `
+paths:
+  /pets:
+    post:
+      x-idempotent: true
+  /pets/{petId}:
+    put:
+      x-idempotent: "yes"
+`).AuditIdempotency()
+	assert.Len(r.T(), findings, 2)
+	assert.Equal(r.T(), &Finding{
+		Pointer:  "/paths/~1pets/post/parameters",
+		Rule:     RuleIdempotencyKeyMissing,
+		Severity: SeverityWarning,
+		Message:  "idempotent operation does not declare the Idempotency-Key header",
+	}, findings[0])
+	assert.Equal(r.T(), "/paths/~1pets~1{petId}/put/x-idempotent", findings[1].Pointer)
+	assert.Equal(r.T(), SeverityError, findings[1].Severity)
+}
+
+func (r *IdempotencySuite) TestMiddleware() {
+	doc := petstore(r.T(), idempotencyPatch, `
+paths:
+  /pets/{petId}:
+    put:
+      x-idempotent: true
+`)
+	assert.Nil(r.T(), doc.DeclareIdempotent("/pets", "post"))
+
+	testCases := []struct {
+		method   string
+		path     string
+		key      string
+		status   int
+		expected string
+	}{
+		{http.MethodPost, "/pets", "abc", http.StatusOK, "abc"},
+		{http.MethodPost, "/pets", "", http.StatusBadRequest, ""},
+		{http.MethodPut, "/pets/1", "def", http.StatusOK, "def"},
+		{http.MethodPut, "/pets/1", "", http.StatusOK, ""},
+	}
+
+	for i, testCase := range testCases {
+		failMsg := "test case %d failed"
+		var key string
+		handler := http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+			key = IdempotencyKey(req)
+		})
+		req := httptest.NewRequest(testCase.method, testCase.path, nil)
+		if testCase.key != "" {
+			req.Header.Set(IdempotencyKeyHeader, testCase.key)
+		}
+		recorder := httptest.NewRecorder()
+		router := &Router{Doc: doc}
+		router.Middleware(IdempotencyKeys{Doc: doc}.Middleware(handler)).ServeHTTP(recorder, req)
+		assert.Equal(r.T(), testCase.status, recorder.Code, failMsg, i)
+		assert.Equal(r.T(), testCase.expected, key, failMsg, i)
+	}
+}
+
+func TestIdempotencySuite(t *testing.T) {
+	suite.Run(t, new(IdempotencySuite))
+}
@@ -4,7 +4,7 @@ import (
 	"encoding/json"
 
 	"github.com/pkg/errors"
-	"gopkg.in/yaml.v2"
+	"gopkg.in/yaml.v3"
 )
 
 // Info provides metadata about the API. The metadata MAY be used by the clients
@@ -61,22 +61,7 @@ func (r Info) MarshalJSON() ([]byte, error) {
 
 // UnmarshalJSON parses the JSON-encoded data and stores the result.
 func (r *Info) UnmarshalJSON(data []byte) error {
-	return r.UnmarshalYAML(func(in interface{}) error {
-		obj := make(map[string]interface{})
-		if err := json.Unmarshal(data, &obj); err != nil {
-			return errors.WithStack(err)
-		}
-
-		rbytes, err := yaml.Marshal(obj)
-		if err != nil {
-			return errors.WithStack(err)
-		}
-
-		if err := yaml.Unmarshal(rbytes, in); err != nil {
-			return errors.WithStack(err)
-		}
-		return nil
-	})
+	return decodeJSON(data, r)
 }
 
 // MarshalYAML returns the YAML encoding.
@@ -119,7 +104,13 @@ func (r Info) MarshalYAML() (interface{}, error) {
 }
 
 // UnmarshalYAML parses the YAML-encoded data and stores the result.
-func (r *Info) UnmarshalYAML(unmarshal func(interface{}) error) error {
+func (r *Info) UnmarshalYAML(node *yaml.Node) error {
+	return decodeYAML(node, r)
+}
+
+// unmarshalValue stores the result of the unmarshal function, which decodes
+// either JSON or YAML data.
+func (r *Info) unmarshalValue(d *valueDecoder, unmarshal func(interface{}) error) error {
 	obj := make(map[string]interface{})
 	if err := unmarshal(&obj); err != nil {
 		return errors.WithStack(err)
@@ -143,26 +134,18 @@ func (r *Info) UnmarshalYAML(unmarshal func(interface{}) error) error {
 		}
 	}
 
-	if value, ok := obj["contact"]; ok {
-		rbytes, err := yaml.Marshal(value)
-		if err != nil {
-			return errors.WithStack(err)
-		}
+	if raw, ok := obj["contact"]; ok {
 		value := Contact{}
-		if err := yaml.Unmarshal(rbytes, &value); err != nil {
-			return errors.WithStack(err)
+		if err := d.decodeMember("contact", raw, &value); err != nil {
+			return err
 		}
 		r.Contact = &value
 	}
 
-	if value, ok := obj["license"]; ok {
-		rbytes, err := yaml.Marshal(value)
-		if err != nil {
-			return errors.WithStack(err)
-		}
+	if raw, ok := obj["license"]; ok {
 		value := License{}
-		if err := yaml.Unmarshal(rbytes, &value); err != nil {
-			return errors.WithStack(err)
+		if err := d.decodeMember("license", raw, &value); err != nil {
+			return err
 		}
 		r.License = &value
 	}
@@ -0,0 +1,106 @@
+package oas
+
+import (
+	"sort"
+)
+
+// SchemaInheritance describes a component schema extending other component
+// schemas through the allOf idiom (e.g. "Pet" declared as the allOf of a
+// reference to "NewPet" and an object adding an "id" property), so type
+// generators can embed the parents instead of duplicating their fields.
+type SchemaInheritance struct {
+	// Name describes the name of the child component schema.
+	Name string `json:"name" yaml:"name"`
+
+	// Parents describes the names of the component schemas the child
+	// extends, in the order of the allOf list.
+	Parents []string `json:"parents" yaml:"parents"`
+
+	// Properties describes the properties the child adds to its parents.
+	Properties map[string]*Schema `json:"properties,omitempty" yaml:"properties,omitempty"`
+
+	// Required describes the names of the properties the child requires in
+	// addition to its parents.
+	Required []string `json:"required,omitempty" yaml:"required,omitempty"`
+}
+
+// Inheritance returns the component schemas following the inheritance
+// idiom, sorted by name. A schema qualifies when it composes only an allOf
+// list made of references to component schemas, at least one, and at most
+// one inline object schema declaring the properties of the child. Other
+// allOf compositions are left for generators to flatten.
+func (r OpenAPI) Inheritance() []*SchemaInheritance {
+	inheritance := make([]*SchemaInheritance, 0)
+	if r.Components == nil {
+		return inheritance
+	}
+	for _, name := range sortedKeys(r.Components.Schemas) {
+		if child := r.schemaInheritance(name, r.Components.Schemas[name]); child != nil {
+			inheritance = append(inheritance, child)
+		}
+	}
+	return inheritance
+}
+
+// SchemaChildren returns the names of the component schemas extending the
+// named component schema through the inheritance idiom, in sorted order.
+func (r OpenAPI) SchemaChildren(name string) []string {
+	children := make([]string, 0)
+	for _, child := range r.Inheritance() {
+		for _, parent := range child.Parents {
+			if parent == name {
+				children = append(children, child.Name)
+				break
+			}
+		}
+	}
+	return children
+}
+
+// schemaInheritance returns the inheritance of the named component schema
+// or nil if it does not follow the idiom.
+func (r OpenAPI) schemaInheritance(name string, schema *Schema) *SchemaInheritance {
+	if schema == nil || schema.Ref != "" || len(schema.AllOf) == 0 ||
+		len(schema.AnyOf) > 0 || len(schema.OneOf) > 0 || schema.Not != nil ||
+		schema.Properties.Len() > 0 || schema.Items != nil {
+		return nil
+	}
+
+	child := &SchemaInheritance{Name: name, Parents: make([]string, 0)}
+	own := 0
+	for _, member := range schema.AllOf {
+		switch {
+		case member == nil:
+			return nil
+		case member.Ref != "":
+			parent, ok := componentName(member.Ref, "schemas")
+			if !ok || r.Components.Schemas[parent] == nil {
+				return nil
+			}
+			child.Parents = append(child.Parents, parent)
+		case isInlineObject(member):
+			own++
+			child.Properties = make(map[string]*Schema, member.Properties.Len())
+			for _, name := range member.Properties.Keys() {
+				child.Properties[name] = member.Properties.Get(name)
+			}
+			if len(member.Required) > 0 {
+				child.Required = append([]string{}, member.Required...)
+				sort.Strings(child.Required)
+			}
+		default:
+			return nil
+		}
+	}
+	if len(child.Parents) == 0 || own > 1 {
+		return nil
+	}
+	return child
+}
+
+// isInlineObject reports whether the schema is an inline object schema
+// without compositions.
+func isInlineObject(schema *Schema) bool {
+	return (schema.Type == "object" || (schema.Type == "" && schema.Properties.Len() > 0)) &&
+		len(schema.AllOf) == 0 && len(schema.AnyOf) == 0 && len(schema.OneOf) == 0 && schema.Not == nil
+}
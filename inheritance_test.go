@@ -0,0 +1,101 @@
+package oas
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+)
+
+type InheritanceSuite struct {
+	suite.Suite
+}
+
+// inheritancePatch is the patch of the petstore fixture deriving the pet
+// from the new pet.
+const inheritancePatch = `
+components:
+  schemas:
+    NewPet:
+      type: object
+      required: [name]
+      properties:
+        name:
+          type: string
+        tag:
+          type: string
+    Pet:
+      type: null
+      required: null
+      properties: null
+      allOf:
+        - $ref: "#/components/schemas/NewPet"
+        - type: object
+          required: [id]
+          properties:
+            id:
+              type: integer
+    Dog:
+      description: A dog.
+      allOf:
+        - $ref: "#/components/schemas/Pet"
+        - $ref: "#/components/schemas/Tagged"
+    Tagged:
+      type: object
+      properties:
+        tags:
+          type: array
+    Mixed:
+      allOf:
+        - $ref: "#/components/schemas/Pet"
+        - type: string
+    Inline:
+      allOf:
+        - type: object
+          properties:
+            id:
+              type: integer
+    Dangling:
+      allOf:
+        - $ref: "#/components/schemas/Cat"
+    Extended:
+      properties:
+        color:
+          type: string
+      allOf:
+        - $ref: "#/components/schemas/Pet"
+`
+
+func (r *InheritanceSuite) TestInheritance() {
+	assert.Equal(r.T(), []*SchemaInheritance{
+		{Name: "Dog", Parents: []string{"Pet", "Tagged"}},
+		{
+			Name:       "Pet",
+			Parents:    []string{"NewPet"},
+			Properties: map[string]*Schema{"id": {Type: "integer"}},
+			Required:   []string{"id"},
+		},
+	}, petstore(r.T(), inheritancePatch).Inheritance())
+
+	assert.Equal(r.T(), []*SchemaInheritance{}, OpenAPI{}.Inheritance())
+}
+
+func (r *InheritanceSuite) TestSchemaChildren() {
+	testCases := []struct {
+		name     string
+		expected []string
+	}{
+		{"NewPet", []string{"Pet"}},
+		{"Pet", []string{"Dog"}},
+		{"Dog", []string{}},
+	}
+
+	for i, testCase := range testCases {
+		failMsg := "test case %d failed"
+		assert.Equal(r.T(), testCase.expected, petstore(r.T(), inheritancePatch).SchemaChildren(testCase.name), failMsg, i)
+	}
+}
+
+func TestInheritanceSuite(t *testing.T) {
+	suite.Run(t, new(InheritanceSuite))
+}
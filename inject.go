@@ -0,0 +1,123 @@
+package oas
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// FieldInjection describes the fields a platform mandates on every
+// operation, such as a request id header added by the gateway. It is
+// typically decoded from a configuration file.
+type FieldInjection struct {
+	// Parameters describes the parameters added to every operation. They may
+	// be references to the parameters of the components.
+	Parameters []*Parameter `json:"parameters,omitempty" yaml:"parameters,omitempty"`
+
+	// ResponseHeaders describes the headers added to every response, keyed
+	// by header name. They may be references to the headers of the
+	// components.
+	ResponseHeaders map[string]*Header `json:"responseHeaders,omitempty" yaml:"responseHeaders,omitempty"`
+}
+
+// InjectFields adds the parameters and response headers of the injection to
+// every operation of the document. Injecting is idempotent: operations whose
+// path item or themselves already declare a parameter with the same name and
+// location, and responses already declaring a header of the same name, are
+// left untouched. Responses referencing the components receive the headers
+// in the referenced component. The injected objects are copied, and the
+// pointers of the modified operations and component responses are returned
+// in sorted order.
+func (r *OpenAPI) InjectFields(injection *FieldInjection) ([]string, error) {
+	parameters := make([]*Parameter, 0, len(injection.Parameters))
+	for i, parameter := range injection.Parameters {
+		_, resolved := NewResolver(r).parameter("", parameter)
+		if resolved == nil || resolved.Name == "" || resolved.In == "" {
+			return nil, errors.Errorf("injected parameter %d must declare a name and a location or reference a component", i)
+		}
+		parameters = append(parameters, resolved)
+	}
+	names := make([]string, 0, len(injection.ResponseHeaders))
+	for name, header := range injection.ResponseHeaders {
+		if header == nil {
+			return nil, errors.Errorf("injected response header %q is missing", name)
+		}
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	modified := map[string]bool{}
+	for _, op := range r.Paths.operations() {
+		ptr := join("/paths", op.path, op.method)
+		declared := map[string]bool{}
+		for _, parameter := range append(append([]*Parameter{}, op.item.Parameters...), op.operation.Parameters...) {
+			if _, parameter = NewResolver(r).parameter("", parameter); parameter != nil {
+				declared[parameterKey(parameter)] = true
+			}
+		}
+		for i, parameter := range injection.Parameters {
+			if declared[parameterKey(parameters[i])] {
+				continue
+			}
+			clone, err := parameter.Clone()
+			if err != nil {
+				return nil, err
+			}
+			op.operation.Parameters = append(op.operation.Parameters, clone)
+			modified[ptr] = true
+		}
+
+		for _, code := range sortedKeys(op.operation.Responses) {
+			rptr, response := NewResolver(r).response(join(ptr, "responses", code), op.operation.Responses.Get(code))
+			if response == nil {
+				continue
+			}
+			for _, name := range names {
+				if hasHeader(response.Headers, name) {
+					continue
+				}
+				clone, err := injection.ResponseHeaders[name].Clone()
+				if err != nil {
+					return nil, err
+				}
+				if response.Headers == nil {
+					response.Headers = map[string]*Header{}
+				}
+				response.Headers[name] = clone
+				if strings.HasPrefix(rptr, "/components/") {
+					modified[rptr] = true
+				} else {
+					modified[ptr] = true
+				}
+			}
+		}
+	}
+
+	pointers := make([]string, 0, len(modified))
+	for ptr := range modified {
+		pointers = append(pointers, ptr)
+	}
+	sort.Strings(pointers)
+	return pointers, nil
+}
+
+// parameterKey identifies a parameter by its location and name. Header names
+// are case insensitive.
+func parameterKey(parameter *Parameter) string {
+	if parameter.In == "header" {
+		return parameter.In + ":" + strings.ToLower(parameter.Name)
+	}
+	return parameter.In + ":" + parameter.Name
+}
+
+// hasHeader reports whether the headers declare the name, case
+// insensitively.
+func hasHeader(headers map[string]*Header, name string) bool {
+	for key := range headers {
+		if strings.EqualFold(key, name) {
+			return true
+		}
+	}
+	return false
+}
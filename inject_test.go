@@ -0,0 +1,118 @@
+package oas
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+)
+
+type InjectSuite struct {
+	suite.Suite
+}
+
+// injectPatch is the patch of the petstore fixture the suite injects into.
+const injectPatch = `
+paths:
+  /pets:
+    get:
+      responses:
+        default:
+          $ref: "#/components/responses/Error"
+  /pets/{petId}:
+    parameters:
+      - name: petId
+        in: path
+        required: true
+        schema:
+          type: integer
+      - name: x-request-id
+        in: header
+    get:
+      responses:
+        "200":
+          headers:
+            x-request-id: {}
+        default:
+          $ref: "#/components/responses/Error"
+components:
+  parameters:
+    Tenant:
+      name: tenant
+      in: query
+  responses:
+    Error:
+      description: Error.
+`
+
+func (r *InjectSuite) injection() *FieldInjection {
+	return &FieldInjection{
+		Parameters: []*Parameter{
+			{Name: "X-Request-Id", In: "header", Header: Header{Schema: &Schema{Type: "string"}}},
+			{Header: Header{Ref: "#/components/parameters/Tenant"}},
+		},
+		ResponseHeaders: map[string]*Header{
+			"X-Request-Id": {Schema: &Schema{Type: "string"}},
+		},
+	}
+}
+
+func (r *InjectSuite) TestInjectFields() {
+	doc := petstore(r.T(), injectPatch)
+	injection := r.injection()
+	modified, err := doc.InjectFields(injection)
+	assert.Nil(r.T(), err)
+	assert.Equal(r.T(), []string{
+		"/components/responses/Error",
+		"/paths/~1pets/get",
+		"/paths/~1pets/post",
+		"/paths/~1pets~1{petId}/delete",
+		"/paths/~1pets~1{petId}/get",
+	}, modified)
+
+	pets := doc.Paths.PathItems.Get("/pets").Get
+	assert.Equal(r.T(), r.injection().Parameters, pets.Parameters)
+	assert.Equal(r.T(), &Schema{Type: "string"}, pets.Responses.Get("200").Headers["X-Request-Id"].Schema)
+	assert.NotNil(r.T(), doc.Components.Responses["Error"].Headers["X-Request-Id"])
+	assert.Nil(r.T(), pets.Responses.Get("default").Headers)
+
+	pet := doc.Paths.PathItems.Get("/pets/{petId}").Get
+	assert.Equal(r.T(), []*Parameter{{Header: Header{Ref: "#/components/parameters/Tenant"}}}, pet.Parameters)
+	assert.Equal(r.T(), []string{"x-request-id"}, sortedKeys(pet.Responses.Get("200").Headers))
+
+	pets.Parameters[0].Name = "changed"
+	assert.Equal(r.T(), "X-Request-Id", injection.Parameters[0].Name)
+}
+
+func (r *InjectSuite) TestIdempotent() {
+	doc := petstore(r.T(), injectPatch)
+	assert.Nil(r.T(), InjectFieldsTransform(r.injection())(doc))
+	expected, err := doc.Clone()
+	assert.Nil(r.T(), err)
+
+	modified, err := doc.InjectFields(r.injection())
+	assert.Nil(r.T(), err)
+	assert.Empty(r.T(), modified)
+	assert.Equal(r.T(), expected, doc)
+}
+
+func (r *InjectSuite) TestErrors() {
+	testCases := []*FieldInjection{
+		{Parameters: []*Parameter{{Name: "tenant"}}},
+		{Parameters: []*Parameter{{Header: Header{Ref: "#/components/parameters/Missing"}}}},
+		{Parameters: []*Parameter{nil}},
+		{ResponseHeaders: map[string]*Header{"X-Request-Id": nil}},
+	}
+
+	for i, injection := range testCases {
+		failMsg := "test case %d failed"
+		doc := petstore(r.T(), injectPatch)
+		_, err := doc.InjectFields(injection)
+		assert.NotNil(r.T(), err, failMsg, i)
+		assert.Equal(r.T(), petstore(r.T(), injectPatch), doc, failMsg, i)
+	}
+}
+
+func TestInjectSuite(t *testing.T) {
+	suite.Run(t, new(InjectSuite))
+}
@@ -0,0 +1,120 @@
+package oas
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// InlineOptions describes the behavior of InlineSchemas.
+type InlineOptions struct {
+	// SingleUse inlines the component schemas referenced exactly once.
+	SingleUse bool
+
+	// Threshold inlines the component schemas whose complexity, measured as
+	// the number of schemas in the subtree including the schema itself, does
+	// not exceed the threshold. Zero value disables the size criterion.
+	Threshold int
+}
+
+// InlineSchemas replaces references to component schemas with copies of the
+// referenced schemas and removes the inlined components. Schemas that are
+// never referenced, that are part of a reference cycle, that are targeted by
+// a discriminator mapping or that are addressed by a pointer into their
+// subtree are kept. The names of the inlined components are returned in
+// sorted order.
+func (r *OpenAPI) InlineSchemas(opts InlineOptions) ([]string, error) {
+	inlined := make([]string, 0)
+	if r.Components == nil || len(r.Components.Schemas) == 0 {
+		return inlined, nil
+	}
+
+	const prefix = "#/components/schemas/"
+	counts := map[string]int{}
+	pinned := map[string]bool{}
+	_ = walk(r, func(ptr string, node interface{}) error {
+		if ref := refOf(node); ref != nil && strings.HasPrefix(*ref, prefix) {
+			name := (*ref)[len(prefix):]
+			if strings.Contains(name, "/") {
+				pinned[unescapePointer(name[:strings.Index(name, "/")])] = true
+			} else {
+				counts[unescapePointer(name)]++
+			}
+		}
+		if schema, ok := node.(*Schema); ok && schema.Discriminator != nil {
+			for _, value := range schema.Discriminator.Mapping {
+				pinned[strings.TrimPrefix(value, prefix)] = true
+			}
+		}
+		return nil
+	})
+
+	candidates := map[string]*Schema{}
+	for name, schema := range r.Components.Schemas {
+		count := counts[name]
+		if schema == nil || count == 0 || pinned[name] || r.Components.isRecursive(name) {
+			continue
+		}
+		if (opts.SingleUse && count == 1) ||
+			(opts.Threshold > 0 && schema.complexity() <= opts.Threshold) {
+			candidates[name] = schema
+			inlined = append(inlined, name)
+		}
+	}
+
+	if err := walk(r, func(ptr string, node interface{}) error {
+		schema, ok := node.(*Schema)
+		if !ok || !strings.HasPrefix(schema.Ref, prefix) {
+			return nil
+		}
+		target, ok := candidates[unescapePointer(schema.Ref[len(prefix):])]
+		if !ok {
+			return nil
+		}
+		value, err := target.Clone()
+		if err != nil {
+			return errors.Wrap(err, ptr)
+		}
+		*schema = *value
+		return nil
+	}); err != nil {
+		return nil, err
+	}
+
+	for name := range candidates {
+		delete(r.Components.Schemas, name)
+	}
+
+	sort.Strings(inlined)
+	return inlined, nil
+}
+
+// isRecursive reports whether the component schema references itself,
+// directly or through other component schemas.
+func (r *Components) isRecursive(name string) bool {
+	visited := map[string]bool{}
+	var visit func(schema *Schema) bool
+	visit = func(schema *Schema) bool {
+		found := false
+		_ = (&walker{fn: func(ptr string, node interface{}) error {
+			child, ok := node.(*Schema)
+			if !ok || found {
+				return nil
+			}
+			target, ok := componentName(child.Ref, "schemas")
+			if !ok {
+				return nil
+			}
+			if target == name {
+				found = true
+			} else if !visited[target] {
+				visited[target] = true
+				found = visit(r.Schemas[target])
+			}
+			return nil
+		}}).schema("", schema)
+		return found
+	}
+	return visit(r.Schemas[name])
+}
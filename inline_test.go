@@ -0,0 +1,156 @@
+package oas
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+)
+
+type InlineSuite struct {
+	suite.Suite
+}
+
+func (r *InlineSuite) TestInlineSchemas() {
+	testCases := []struct {
+		shouldFail bool
+		opts       InlineOptions
+		input      *OpenAPI
+		expected   *OpenAPI
+		inlined    []string
+	}{
+		{
+			false,
+			InlineOptions{SingleUse: true},
+			&OpenAPI{
+				Paths: Paths{
+					PathItems: NewPathItems(map[string]*PathItem{
+						"/pets": {
+							Get: &Operation{
+								Responses: NewResponses(map[string]*Response{
+									"200": {
+										Content: map[string]*MediaType{
+											"application/json": {
+												Schema: &Schema{Type: "array", Items: &Schema{Ref: "#/components/schemas/Pet"}},
+											},
+										},
+									},
+								}),
+							},
+						},
+					}),
+				},
+				Components: &Components{
+					Schemas: map[string]*Schema{
+						"Pet": {
+							Type: "object",
+							Properties: NewProperties(map[string]*Schema{
+								"name":  {Ref: "#/components/schemas/Name"},
+								"owner": {Ref: "#/components/schemas/Person"},
+							}),
+						},
+						"Person": {
+							Type: "object",
+							Properties: NewProperties(map[string]*Schema{
+								"friend": {Ref: "#/components/schemas/Person"},
+								"name":   {Ref: "#/components/schemas/Name"},
+							}),
+						},
+						"Name":   {Type: "string"},
+						"Unused": {Type: "string"},
+					},
+				},
+			},
+			&OpenAPI{
+				Paths: Paths{
+					PathItems: NewPathItems(map[string]*PathItem{
+						"/pets": {
+							Get: &Operation{
+								Responses: NewResponses(map[string]*Response{
+									"200": {
+										Content: map[string]*MediaType{
+											"application/json": {
+												Schema: &Schema{
+													Type: "array",
+													Items: &Schema{
+														Type: "object",
+														Properties: NewProperties(map[string]*Schema{
+															"name":  {Ref: "#/components/schemas/Name"},
+															"owner": {Ref: "#/components/schemas/Person"},
+														}),
+													},
+												},
+											},
+										},
+									},
+								}),
+							},
+						},
+					}),
+				},
+				Components: &Components{
+					Schemas: map[string]*Schema{
+						"Person": {
+							Type: "object",
+							Properties: NewProperties(map[string]*Schema{
+								"friend": {Ref: "#/components/schemas/Person"},
+								"name":   {Ref: "#/components/schemas/Name"},
+							}),
+						},
+						"Name":   {Type: "string"},
+						"Unused": {Type: "string"},
+					},
+				},
+			},
+			[]string{"Pet"},
+		},
+		{
+			false,
+			InlineOptions{Threshold: 1},
+			&OpenAPI{
+				Components: &Components{
+					Schemas: map[string]*Schema{
+						"Pet": {
+							Type: "object",
+							Properties: NewProperties(map[string]*Schema{
+								"id":   {Ref: "#/components/schemas/ID"},
+								"tags": {Type: "array", Items: &Schema{Ref: "#/components/schemas/ID"}},
+							}),
+						},
+						"ID": {Type: "string", Format: "uuid"},
+					},
+				},
+			},
+			&OpenAPI{
+				Components: &Components{
+					Schemas: map[string]*Schema{
+						"Pet": {
+							Type: "object",
+							Properties: NewProperties(map[string]*Schema{
+								"id":   {Type: "string", Format: "uuid"},
+								"tags": {Type: "array", Items: &Schema{Type: "string", Format: "uuid"}},
+							}),
+						},
+					},
+				},
+			},
+			[]string{"ID"},
+		},
+	}
+
+	for i, testCase := range testCases {
+		failMsg := fmt.Sprintf("testCase: %d %v", i, testCase)
+
+		inlined, err := testCase.input.InlineSchemas(testCase.opts)
+		if (err != nil) != testCase.shouldFail {
+			assert.Fail(r.T(), failMsg, err)
+		}
+		assert.Equal(r.T(), testCase.inlined, inlined, failMsg)
+		assert.EqualValues(r.T(), testCase.expected, testCase.input, failMsg)
+	}
+}
+
+func TestInlineSuite(t *testing.T) {
+	suite.Run(t, new(InlineSuite))
+}
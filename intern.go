@@ -0,0 +1,123 @@
+package oas
+
+import (
+	"reflect"
+	"sync"
+)
+
+// Interner deduplicates the strings of decoded documents. Documents decoded
+// through the same interner share a single copy of every repeated string,
+// such as map keys, types ("string", "object"), formats, references and
+// media types, which considerably reduces the memory of read-only workloads
+// holding thousands of documents. It is safe for concurrent use.
+type Interner struct {
+	mutex   sync.Mutex
+	strings map[string]string
+}
+
+// String returns the interned copy of the string.
+func (r *Interner) String(s string) string {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	if r.strings == nil {
+		r.strings = map[string]string{}
+	}
+	if interned, ok := r.strings[s]; ok {
+		return interned
+	}
+	r.strings[s] = s
+	return s
+}
+
+// Len returns the number of distinct strings interned.
+func (r *Interner) Len() int {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	return len(r.strings)
+}
+
+// Decode decodes a JSON or YAML encoded document and interns its strings.
+func (r *Interner) Decode(data []byte) (*OpenAPI, error) {
+	doc := &OpenAPI{}
+	if err := decodeDocument(data, doc); err != nil {
+		return nil, err
+	}
+	doc.Intern(r)
+	return doc, nil
+}
+
+// Intern replaces every string of the document, including map keys and the
+// strings of extensions and examples, with its copy held by the interner.
+func (r *OpenAPI) Intern(interner *Interner) {
+	interner.value(reflect.ValueOf(r).Elem(), map[uintptr]bool{})
+}
+
+// value interns the strings of the addressable value. Objects referenced
+// multiple times are interned once.
+func (r *Interner) value(value reflect.Value, visited map[uintptr]bool) {
+	switch value.Kind() {
+	case reflect.String:
+		if value.CanSet() {
+			value.SetString(r.String(value.String()))
+		}
+	case reflect.Ptr:
+		if value.IsNil() || visited[value.Pointer()] {
+			return
+		}
+		visited[value.Pointer()] = true
+		r.value(value.Elem(), visited)
+	case reflect.Struct:
+		if ordered, ok := orderedValuerOf(value); ok {
+			r.orderedValues(ordered.orderedValues(), visited)
+			return
+		}
+		for i := 0; i < value.NumField(); i++ {
+			if value.Field(i).CanSet() {
+				r.value(value.Field(i), visited)
+			}
+		}
+	case reflect.Slice:
+		for i := 0; i < value.Len(); i++ {
+			r.value(value.Index(i), visited)
+		}
+	case reflect.Map:
+		if value.IsNil() || !value.CanSet() {
+			return
+		}
+		interned := reflect.MakeMapWithSize(value.Type(), value.Len())
+		for _, key := range value.MapKeys() {
+			item := reflect.New(value.Type().Elem()).Elem()
+			item.Set(value.MapIndex(key))
+			r.value(item, visited)
+			if key.Kind() == reflect.String {
+				key = reflect.ValueOf(r.String(key.String())).Convert(key.Type())
+			}
+			interned.SetMapIndex(key, item)
+		}
+		value.Set(interned)
+	case reflect.Interface:
+		if value.IsNil() || !value.CanSet() {
+			return
+		}
+		item := reflect.New(value.Elem().Type()).Elem()
+		item.Set(value.Elem())
+		r.value(item, visited)
+		value.Set(item)
+	}
+}
+
+// orderedValues interns the keys and the values of the values backing an
+// ordered map.
+func (r *Interner) orderedValues(values *orderedValues, visited map[uintptr]bool) {
+	if values == nil {
+		return
+	}
+	for i := range values.entries {
+		entry := &values.entries[i]
+		r.value(reflect.ValueOf(&entry.value).Elem(), visited)
+		delete(values.index, entry.key)
+		entry.key = r.String(entry.key)
+		values.index[entry.key] = i
+	}
+}
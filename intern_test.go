@@ -0,0 +1,69 @@
+package oas
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+)
+
+type InternSuite struct {
+	suite.Suite
+}
+
+func (r *InternSuite) TestDecode() {
+	data := []byte(`openapi: 3.0.0
+info:
+  title: Petstore
+  version: 1.0.0
+paths:
+  /pets:
+    get:
+      security:
+      - apiKey: []
+      responses:
+        "200":
+          description: Pets.
+          content:
+            application/json:
+              schema:
+                type: array
+                items:
+                  $ref: '#/components/schemas/Pet'
+components:
+  schemas:
+    Pet:
+      type: object
+      example:
+        name: Rex
+      properties:
+        name:
+          type: string
+        tag:
+          type: string
+`)
+
+	expected := &OpenAPI{}
+	assert.Nil(r.T(), decodeDocument(data, expected))
+
+	interner := &Interner{}
+	first, err := interner.Decode(data)
+	assert.Nil(r.T(), err)
+	assert.Equal(r.T(), expected, first)
+	count := interner.Len()
+
+	second, err := interner.Decode(data)
+	assert.Nil(r.T(), err)
+	assert.Equal(r.T(), expected, second)
+	assert.Equal(r.T(), count, interner.Len())
+	assert.Contains(r.T(), interner.strings, "string")
+	assert.Contains(r.T(), interner.strings, "Rex")
+	assert.Contains(r.T(), interner.strings, "apiKey")
+
+	_, err = interner.Decode([]byte("{"))
+	assert.NotNil(r.T(), err)
+}
+
+func TestInternSuite(t *testing.T) {
+	suite.Run(t, new(InternSuite))
+}
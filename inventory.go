@@ -0,0 +1,185 @@
+package oas
+
+import (
+	"encoding/csv"
+	"io"
+	"sort"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// Endpoint summarizes a single operation of the API surface.
+type Endpoint struct {
+	// Path describes the path template the operation is declared under.
+	Path string
+
+	// Method describes the uppercase HTTP method of the operation.
+	Method string
+
+	// OperationID describes the identifier of the operation.
+	OperationID string
+
+	// Summary describes the summary of the operation.
+	Summary string
+
+	// Tags describes the tags of the operation.
+	Tags []string
+
+	// Security describes the alternative security requirements which apply
+	// to the operation, each formatted as the scheme names joined with " + ".
+	// The value "none" indicates that security was explicitly disabled.
+	Security []string
+
+	// Requests describes the request body media types and their types,
+	// formatted as "media/type: Type".
+	Requests []string
+
+	// Responses describes the response status codes, media types and types,
+	// formatted as "code media/type: Type".
+	Responses []string
+}
+
+// Inventory returns a summary of every operation of the document ordered by
+// path and method.
+func (r OpenAPI) Inventory() []*Endpoint {
+	endpoints := make([]*Endpoint, 0)
+	for _, op := range r.Paths.operations() {
+		endpoint := &Endpoint{
+			Path:        op.path,
+			Method:      strings.ToUpper(op.method),
+			OperationID: op.operation.OperationID,
+			Summary:     op.operation.Summary,
+			Tags:        op.operation.Tags,
+			Security:    formatSecurity(r.Security, op.operation.Security),
+			Requests:    make([]string, 0),
+			Responses:   make([]string, 0),
+		}
+
+		if body := op.operation.RequestBody; body != nil {
+			if body.Ref != "" {
+				endpoint.Requests = append(endpoint.Requests, refName(body.Ref))
+			}
+			endpoint.Requests = append(endpoint.Requests, formatContent("", body.Content)...)
+		}
+
+		for _, code := range sortedKeys(op.operation.Responses) {
+			response := op.operation.Responses.Get(code)
+			switch {
+			case response == nil:
+				continue
+			case response.Ref != "":
+				endpoint.Responses = append(endpoint.Responses, code+": "+refName(response.Ref))
+			case len(response.Content) == 0:
+				endpoint.Responses = append(endpoint.Responses, code)
+			default:
+				endpoint.Responses = append(endpoint.Responses, formatContent(code+" ", response.Content)...)
+			}
+		}
+		endpoints = append(endpoints, endpoint)
+	}
+	return endpoints
+}
+
+// WriteCSV writes the inventory of the document as CSV with a header row.
+// Multi-valued columns are joined with "; " so the output can be reviewed in
+// spreadsheet applications.
+func (r OpenAPI) WriteCSV(w io.Writer) error {
+	writer := csv.NewWriter(w)
+	records := [][]string{{
+		"path", "method", "operationId", "summary",
+		"tags", "security", "requests", "responses",
+	}}
+	for _, endpoint := range r.Inventory() {
+		records = append(records, []string{
+			endpoint.Path,
+			endpoint.Method,
+			endpoint.OperationID,
+			endpoint.Summary,
+			strings.Join(endpoint.Tags, "; "),
+			strings.Join(endpoint.Security, "; "),
+			strings.Join(endpoint.Requests, "; "),
+			strings.Join(endpoint.Responses, "; "),
+		})
+	}
+	if err := writer.WriteAll(records); err != nil {
+		return errors.WithStack(err)
+	}
+	return nil
+}
+
+// formatSecurity formats the security requirements effective for an
+// operation given the document wide and operation level declarations.
+func formatSecurity(root []*SecurityRequirement, operation []*SecurityRequirement) []string {
+	requirements := root
+	if operation != nil {
+		if len(operation) == 0 {
+			return []string{"none"}
+		}
+		requirements = operation
+	}
+
+	result := make([]string, 0, len(requirements))
+	for _, requirement := range requirements {
+		if requirement == nil {
+			continue
+		}
+		names := make([]string, 0, len(*requirement))
+		for name := range *requirement {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		if len(names) == 0 {
+			result = append(result, "none")
+			continue
+		}
+		result = append(result, strings.Join(names, " + "))
+	}
+	return result
+}
+
+// formatContent formats the media types of the content and the types of
+// their schemas prefixed with the prefix.
+func formatContent(prefix string, content map[string]*MediaType) []string {
+	result := make([]string, 0, len(content))
+	for _, mediaType := range sortedKeys(content) {
+		value := prefix + mediaType
+		if content[mediaType] != nil && content[mediaType].Schema != nil {
+			value += ": " + content[mediaType].Schema.typeName()
+		}
+		result = append(result, value)
+	}
+	return result
+}
+
+// typeName returns a short human readable name for the type described by
+// the schema (e.g. "Pet", "[]Pet", "string(uuid)").
+func (r *Schema) typeName() string {
+	switch {
+	case r.Ref != "":
+		return refName(r.Ref)
+	case r.Type == "array" && r.Items != nil:
+		return "[]" + r.Items.typeName()
+	case r.Type == "object" && r.AdditionalProperties != nil && r.Properties.Len() == 0:
+		return "map[string]" + r.AdditionalProperties.typeName()
+	case r.Type != "" && r.Format != "":
+		return r.Type + "(" + r.Format + ")"
+	case r.Type != "":
+		return r.Type
+	case len(r.AllOf) > 0:
+		return "allOf"
+	case len(r.OneOf) > 0:
+		return "oneOf"
+	case len(r.AnyOf) > 0:
+		return "anyOf"
+	case r.Properties.Len() > 0:
+		return "object"
+	}
+	return "any"
+}
+
+// refName returns the last reference token of a reference.
+func refName(ref string) string {
+	tokens := strings.Split(ref, "/")
+	return unescapePointer(tokens[len(tokens)-1])
+}
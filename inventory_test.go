@@ -0,0 +1,86 @@
+package oas
+
+import (
+	"bytes"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+)
+
+type InventorySuite struct {
+	suite.Suite
+}
+
+func (r *InventorySuite) TestWriteCSV() {
+	testCases := []struct {
+		shouldFail bool
+		input      *OpenAPI
+		expected   string
+	}{
+		{
+			false,
+			&OpenAPI{
+				Paths: Paths{
+					PathItems: NewPathItems(map[string]*PathItem{
+						"/pets": {
+							Get: &Operation{
+								OperationID: "listPets",
+								Summary:     "List all pets",
+								Tags:        []string{"pets"},
+								Responses: NewResponses(map[string]*Response{
+									"200": {
+										Content: map[string]*MediaType{
+											"application/json": {
+												Schema: &Schema{Type: "array", Items: &Schema{Ref: "#/components/schemas/Pet"}},
+											},
+										},
+									},
+									"default": {Ref: "#/components/responses/Error"},
+								}),
+							},
+							Post: &Operation{
+								OperationID: "createPet",
+								Tags:        []string{"pets", "admin"},
+								Security:    []*SecurityRequirement{{"api_key": {}, "oauth": {"write"}}, {"basic": {}}},
+								RequestBody: &RequestBody{
+									Content: map[string]*MediaType{
+										"application/json": {Schema: &Schema{Ref: "#/components/schemas/NewPet"}},
+									},
+								},
+								Responses: NewResponses(map[string]*Response{"201": {}}),
+							},
+						},
+						"/health": {
+							Get: &Operation{
+								Security:  []*SecurityRequirement{},
+								Responses: NewResponses(map[string]*Response{"200": {Content: map[string]*MediaType{"text/plain": {Schema: &Schema{Type: "string"}}}}}),
+							},
+						},
+					}),
+				},
+				Security: []*SecurityRequirement{{"oauth": {"read"}}},
+			},
+			"path,method,operationId,summary,tags,security,requests,responses\n" +
+				"/health,GET,,,,none,,200 text/plain: string\n" +
+				"/pets,GET,listPets,List all pets,pets,oauth,,200 application/json: []Pet; default: Error\n" +
+				"/pets,POST,createPet,,pets; admin,api_key + oauth; basic,application/json: NewPet,201\n",
+		},
+	}
+
+	for i, testCase := range testCases {
+		failMsg := fmt.Sprintf("testCase: %d %v", i, testCase)
+
+		buffer := &bytes.Buffer{}
+		err := testCase.input.WriteCSV(buffer)
+		if (err != nil) != testCase.shouldFail {
+			assert.Fail(r.T(), failMsg, err)
+		}
+		assert.Equal(r.T(), testCase.expected, buffer.String(), failMsg)
+	}
+}
+
+func TestInventorySuite(t *testing.T) {
+	suite.Run(t, new(InventorySuite))
+}
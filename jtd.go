@@ -0,0 +1,409 @@
+package oas
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// RuleJTDUnsupported identifies the findings reported by JTD for constructs
+// JSON Type Definition cannot represent.
+const RuleJTDUnsupported = "jtd-unsupported"
+
+// JTDSchema describes a JSON Type Definition schema as defined by RFC 8927.
+// Exactly one form is set besides the definitions, the metadata and
+// nullable; a schema without form is the empty form accepting any value.
+type JTDSchema struct {
+	// Definitions describes the named schemas of the root schema.
+	Definitions map[string]*JTDSchema `json:"definitions,omitempty" yaml:"definitions,omitempty"`
+
+	// Metadata describes data ignored by validation (e.g. "description").
+	Metadata map[string]interface{} `json:"metadata,omitempty" yaml:"metadata,omitempty"`
+
+	// Nullable describes whether null is accepted as well.
+	Nullable bool `json:"nullable,omitempty" yaml:"nullable,omitempty"`
+
+	// Ref describes the name of the definition the schema refers to.
+	Ref string `json:"ref,omitempty" yaml:"ref,omitempty"`
+
+	// Type describes the primitive type of the type form (e.g. "string",
+	// "timestamp", "int32").
+	Type string `json:"type,omitempty" yaml:"type,omitempty"`
+
+	// Enum describes the strings accepted by the enum form.
+	Enum []string `json:"enum,omitempty" yaml:"enum,omitempty"`
+
+	// Elements describes the schema of the elements of the elements form.
+	Elements *JTDSchema `json:"elements,omitempty" yaml:"elements,omitempty"`
+
+	// Properties describes the required properties of the properties form.
+	Properties map[string]*JTDSchema `json:"properties,omitempty" yaml:"properties,omitempty"`
+
+	// OptionalProperties describes the optional properties of the
+	// properties form.
+	OptionalProperties map[string]*JTDSchema `json:"optionalProperties,omitempty" yaml:"optionalProperties,omitempty"`
+
+	// AdditionalProperties describes whether the properties form accepts
+	// undeclared properties.
+	AdditionalProperties bool `json:"additionalProperties,omitempty" yaml:"additionalProperties,omitempty"`
+
+	// Values describes the schema of the values of the values form.
+	Values *JTDSchema `json:"values,omitempty" yaml:"values,omitempty"`
+
+	// Discriminator describes the tag property of the discriminator form.
+	Discriminator string `json:"discriminator,omitempty" yaml:"discriminator,omitempty"`
+
+	// Mapping describes the properties form of each tag value of the
+	// discriminator form.
+	Mapping map[string]*JTDSchema `json:"mapping,omitempty" yaml:"mapping,omitempty"`
+}
+
+// jtdIntegers maps the integer types of JTD onto their bounds.
+var jtdIntegers = map[string][2]int64{
+	"int8":   {-128, 127},
+	"uint8":  {0, 255},
+	"int16":  {-32768, 32767},
+	"uint16": {0, 65535},
+	"int32":  {-2147483648, 2147483647},
+	"uint32": {0, 4294967295},
+}
+
+// JTD converts the schemas of the components into the definitions of a JTD
+// schema. The named schema, if any, also becomes the root form. Constructs
+// JTD cannot represent are reported as findings and degraded: validation
+// keywords such as pattern or bounds are dropped, int64 integers become
+// float64, untyped objects, allOf and oneOf or anyOf without discriminator
+// become the empty form.
+func (r OpenAPI) JTD(root string) (*JTDSchema, []*Finding) {
+	converter := &jtdConverter{doc: r, findings: make([]*Finding, 0)}
+	result := &JTDSchema{}
+	if r.Components != nil && len(r.Components.Schemas) > 0 {
+		result.Definitions = map[string]*JTDSchema{}
+		for _, name := range sortedKeys(r.Components.Schemas) {
+			ptr := join("/components/schemas", name)
+			result.Definitions[name] = converter.convert(ptr, r.Components.Schemas[name])
+		}
+		if definition, ok := result.Definitions[root]; ok {
+			definitions := result.Definitions
+			*result = *definition
+			result.Definitions = definitions
+		}
+	}
+	sortFindings(converter.findings)
+	return result, converter.findings
+}
+
+// jtdConverter collects the findings of a conversion to JTD.
+type jtdConverter struct {
+	doc      OpenAPI
+	findings []*Finding
+}
+
+// report records a construct JTD cannot represent. Constructs of the
+// options of discriminators are converted twice but reported once.
+func (r *jtdConverter) report(ptr string, format string, args ...interface{}) {
+	message := fmt.Sprintf(format, args...)
+	for _, finding := range r.findings {
+		if finding.Pointer == ptr && finding.Message == message {
+			return
+		}
+	}
+	r.findings = append(r.findings, &Finding{
+		Pointer:  ptr,
+		Rule:     RuleJTDUnsupported,
+		Severity: SeverityWarning,
+		Message:  message,
+	})
+}
+
+// convert converts the schema at the pointer.
+func (r *jtdConverter) convert(ptr string, schema *Schema) *JTDSchema {
+	result := &JTDSchema{}
+	if schema == nil {
+		return result
+	}
+	if schema.Ref != "" {
+		if name, ok := componentName(schema.Ref, "schemas"); ok {
+			result.Ref = name
+		} else {
+			r.report(ptr, "reference %q does not point to the schemas of the components", schema.Ref)
+		}
+		return result
+	}
+
+	result.Nullable = schema.Nullable
+	if schema.Description != "" {
+		result.Metadata = map[string]interface{}{"description": schema.Description}
+	}
+	if dropped := jtdDroppedKeywords(schema); len(dropped) > 0 {
+		r.report(ptr, "%s cannot be represented", strings.Join(dropped, ", "))
+	}
+
+	switch {
+	case len(schema.AllOf) > 0:
+		r.report(ptr, "allOf cannot be represented")
+	case len(schema.OneOf) > 0 && schema.Discriminator != nil:
+		r.convertDiscriminator(ptr, schema, result)
+	case len(schema.OneOf) > 0 || len(schema.AnyOf) > 0:
+		r.report(ptr, "oneOf and anyOf cannot be represented without discriminator")
+	case schema.Type == "boolean":
+		result.Type = "boolean"
+	case schema.Type == "integer" && schema.Format == "int64":
+		r.report(ptr, "int64 integers cannot be represented")
+		result.Type = "float64"
+	case schema.Type == "integer":
+		result.Type = "int32"
+	case schema.Type == "number" && schema.Format == "float":
+		result.Type = "float32"
+	case schema.Type == "number":
+		result.Type = "float64"
+	case schema.Type == "string" && len(schema.Enum) > 0:
+		for _, value := range schema.Enum {
+			result.Enum = append(result.Enum, fmt.Sprint(value))
+		}
+	case schema.Type == "string" && schema.Format == "date-time":
+		result.Type = "timestamp"
+	case schema.Type == "string":
+		result.Type = "string"
+	case schema.Type == "array":
+		result.Elements = r.convert(join(ptr, "items"), schema.Items)
+	case schema.Type == "object" && schema.Properties.Len() == 0 && schema.AdditionalProperties != nil:
+		result.Values = r.convert(join(ptr, "additionalProperties"), schema.AdditionalProperties)
+	case schema.Type == "object" && schema.Properties.Len() > 0:
+		r.convertProperties(ptr, schema, result, "")
+	case schema.Type == "object":
+		r.report(ptr, "objects without properties cannot be represented")
+	}
+	return result
+}
+
+// convertProperties converts the properties of the object schema into the
+// properties form, leaving out the tag property of a discriminator.
+func (r *jtdConverter) convertProperties(ptr string, schema *Schema, result *JTDSchema, tag string) {
+	required := map[string]bool{}
+	for _, property := range schema.Required {
+		required[property] = true
+	}
+	for _, property := range sortedKeys(schema.Properties) {
+		if property == tag {
+			continue
+		}
+		value := r.convert(join(ptr, "properties", property), schema.Properties.Get(property))
+		if required[property] {
+			if result.Properties == nil {
+				result.Properties = map[string]*JTDSchema{}
+			}
+			result.Properties[property] = value
+		} else {
+			if result.OptionalProperties == nil {
+				result.OptionalProperties = map[string]*JTDSchema{}
+			}
+			result.OptionalProperties[property] = value
+		}
+	}
+	if schema.AdditionalProperties != nil {
+		result.AdditionalProperties = true
+		r.report(join(ptr, "additionalProperties"), "the schema of additional properties cannot be represented")
+	}
+}
+
+// convertDiscriminator converts a oneOf with discriminator into the
+// discriminator form, inlining the object schemas of the options.
+func (r *jtdConverter) convertDiscriminator(ptr string, schema *Schema, result *JTDSchema) {
+	tags := map[string]string{}
+	for value, ref := range schema.Discriminator.Mapping {
+		if name, ok := componentName(ref, "schemas"); ok {
+			tags[name] = value
+		} else {
+			tags[ref] = value
+		}
+	}
+
+	result.Discriminator = schema.Discriminator.PropertyName
+	result.Mapping = map[string]*JTDSchema{}
+	for i, option := range schema.OneOf {
+		optionPtr := index(ptr, "oneOf", i)
+		name, ok := "", false
+		if option != nil {
+			name, ok = componentName(option.Ref, "schemas")
+		}
+		resolved := NewResolver(&r.doc).schema(option)
+		if !ok || resolved == nil || resolved.Properties.Len() == 0 {
+			r.report(optionPtr, "discriminated options must reference object schemas")
+			continue
+		}
+		value, ok := tags[name]
+		if !ok {
+			value = name
+		}
+		mapping := &JTDSchema{}
+		r.convertProperties(join("/components/schemas", name), resolved, mapping, result.Discriminator)
+		result.Mapping[value] = mapping
+	}
+}
+
+// jtdDroppedKeywords returns the validation keywords of the schema JTD has
+// no equivalent for.
+func jtdDroppedKeywords(schema *Schema) []string {
+	keywords := map[string]bool{
+		"pattern":       schema.Pattern != "",
+		"minLength":     schema.MinLength != nil,
+		"maxLength":     schema.MaxLength != nil,
+		"minimum":       schema.Minimum != nil,
+		"maximum":       schema.Maximum != nil,
+		"multipleOf":    schema.MultipleOf != nil,
+		"minItems":      schema.MinItems != nil,
+		"maxItems":      schema.MaxItems != nil,
+		"uniqueItems":   schema.UniqueItems,
+		"minProperties": schema.MinProperties != nil,
+		"maxProperties": schema.MaxProperties != nil,
+		"not":           schema.Not != nil,
+	}
+	dropped := make([]string, 0)
+	for keyword, ok := range keywords {
+		if ok {
+			dropped = append(dropped, keyword)
+		}
+	}
+	sort.Strings(dropped)
+	return dropped
+}
+
+// Schemas converts the JTD schema into component schemas: one per
+// definition and, when the root is named, one for the root form.
+// Timestamps become date-time strings and integer types carry their bounds.
+// Discriminator forms become a oneOf of component schemas named after the
+// schema and the tag value, since the discriminator of OpenAPI only
+// considers referenced options. Nullable references are wrapped into allOf
+// and the properties forms stay open to additional properties as Schema
+// cannot express a false additionalProperties.
+func (r JTDSchema) Schemas(root string) (map[string]*Schema, error) {
+	schemas := map[string]*Schema{}
+	for _, name := range sortedStrings(r.Definitions) {
+		schema, err := jtdToSchema(name, r.Definitions[name], r.Definitions, schemas)
+		if err != nil {
+			return nil, errors.Wrapf(err, "definition %q", name)
+		}
+		schemas[name] = schema
+	}
+	if root != "" {
+		if _, ok := schemas[root]; ok {
+			return nil, errors.Errorf("root name %q collides with a definition", root)
+		}
+		form := r
+		form.Definitions = nil
+		schema, err := jtdToSchema(root, &form, r.Definitions, schemas)
+		if err != nil {
+			return nil, err
+		}
+		schemas[root] = schema
+	}
+	return schemas, nil
+}
+
+// jtdToSchema converts the JTD schema named after the name, adding the
+// schemas of discriminator options to the schemas.
+func jtdToSchema(name string, jtd *JTDSchema, definitions map[string]*JTDSchema, schemas map[string]*Schema) (*Schema, error) {
+	if jtd == nil {
+		return &Schema{}, nil
+	}
+	if jtd.Ref != "" {
+		if _, ok := definitions[jtd.Ref]; !ok {
+			return nil, errors.Errorf("missing definition %q", jtd.Ref)
+		}
+		ref := &Schema{Ref: "#/components/schemas/" + jtd.Ref}
+		if jtd.Nullable {
+			return &Schema{Nullable: true, AllOf: []*Schema{ref}}, nil
+		}
+		return ref, nil
+	}
+
+	schema := &Schema{Nullable: jtd.Nullable}
+	if description, ok := jtd.Metadata["description"].(string); ok {
+		schema.Description = description
+	}
+	switch {
+	case jtd.Type == "boolean":
+		schema.Type = "boolean"
+	case jtd.Type == "string":
+		schema.Type = "string"
+	case jtd.Type == "timestamp":
+		schema.Type, schema.Format = "string", "date-time"
+	case jtd.Type == "float32":
+		schema.Type, schema.Format = "number", "float"
+	case jtd.Type == "float64":
+		schema.Type, schema.Format = "number", "double"
+	case jtd.Type != "":
+		bounds, ok := jtdIntegers[jtd.Type]
+		if !ok {
+			return nil, errors.Errorf("unknown type %q", jtd.Type)
+		}
+		schema.Type, schema.Format = "integer", "int32"
+		if jtd.Type == "uint32" {
+			schema.Format = "int64"
+		}
+		if jtd.Type != "int32" {
+			schema.Minimum, schema.Maximum = bounds[0], bounds[1]
+		}
+	case len(jtd.Enum) > 0:
+		schema.Type = "string"
+		for _, value := range jtd.Enum {
+			schema.Enum = append(schema.Enum, value)
+		}
+	case jtd.Elements != nil:
+		items, err := jtdToSchema(name+"Item", jtd.Elements, definitions, schemas)
+		if err != nil {
+			return nil, err
+		}
+		schema.Type, schema.Items = "array", items
+	case jtd.Values != nil:
+		values, err := jtdToSchema(name+"Value", jtd.Values, definitions, schemas)
+		if err != nil {
+			return nil, err
+		}
+		schema.Type, schema.AdditionalProperties = "object", values
+	case jtd.Discriminator != "":
+		schema.Discriminator = &Discriminator{PropertyName: jtd.Discriminator, Mapping: map[string]string{}}
+		for _, value := range sortedStrings(jtd.Mapping) {
+			option := name + PascalCase.Format(value)
+			if _, ok := schemas[option]; ok {
+				return nil, errors.Errorf("option schema %q collides with another schema", option)
+			}
+			converted, err := jtdToSchema(option, jtd.Mapping[value], definitions, schemas)
+			if err != nil {
+				return nil, err
+			}
+			properties := Properties{}
+			properties.Set(jtd.Discriminator, &Schema{Type: "string", Enum: []interface{}{value}})
+			for _, name := range converted.Properties.Keys() {
+				properties.Set(name, converted.Properties.Get(name))
+			}
+			converted.Type, converted.Properties = "object", properties
+			converted.Required = append([]string{jtd.Discriminator}, converted.Required...)
+			schemas[option] = converted
+			ref := "#/components/schemas/" + option
+			schema.OneOf = append(schema.OneOf, &Schema{Ref: ref})
+			schema.Discriminator.Mapping[value] = ref
+		}
+	case jtd.Properties != nil || jtd.OptionalProperties != nil:
+		schema.Type = "object"
+		for _, property := range sortedStrings(jtd.Properties) {
+			value, err := jtdToSchema(name+PascalCase.Format(property), jtd.Properties[property], definitions, schemas)
+			if err != nil {
+				return nil, err
+			}
+			schema.Properties.Set(property, value)
+			schema.Required = append(schema.Required, property)
+		}
+		for _, property := range sortedStrings(jtd.OptionalProperties) {
+			value, err := jtdToSchema(name+PascalCase.Format(property), jtd.OptionalProperties[property], definitions, schemas)
+			if err != nil {
+				return nil, err
+			}
+			schema.Properties.Set(property, value)
+		}
+	}
+	return schema, nil
+}
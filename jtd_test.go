@@ -0,0 +1,172 @@
+package oas
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+)
+
+type JTDSuite struct {
+	suite.Suite
+}
+
+// jtdPatch is the patch of the petstore fixture the suite converts.
+const jtdPatch = `
+components:
+  schemas:
+    Pet:
+      type: null
+      required: null
+      properties: null
+      oneOf:
+        - $ref: "#/components/schemas/Cat"
+        - $ref: "#/components/schemas/Dog"
+      discriminator:
+        propertyName: kind
+        mapping:
+          cat: "#/components/schemas/Cat"
+    Cat:
+      type: object
+      required: [kind, name]
+      properties:
+        kind:
+          type: string
+        name:
+          type: string
+          pattern: ^[a-z]+$
+          description: Name.
+        born:
+          type: string
+          format: date-time
+          nullable: true
+        lives:
+          type: integer
+          minimum: 0
+    Dog:
+      type: object
+      required: [kind]
+      properties:
+        kind:
+          type: string
+        tags:
+          type: array
+          items:
+            type: string
+            enum: [good]
+        owner:
+          $ref: "#/components/schemas/Owner"
+    Owner:
+      type: object
+      properties:
+        id:
+          type: integer
+          format: int64
+      additionalProperties:
+        type: string
+    Labels:
+      type: object
+      additionalProperties:
+        type: number
+        format: float
+    Any:
+      anyOf:
+        - type: string
+        - type: boolean
+`
+
+func (r *JTDSuite) TestJTD() {
+	schema, findings := petstore(r.T(), jtdPatch).JTD("Pet")
+	cat := &JTDSchema{
+		Properties: map[string]*JTDSchema{
+			"name": {Type: "string", Metadata: map[string]interface{}{"description": "Name."}},
+		},
+		OptionalProperties: map[string]*JTDSchema{
+			"born":  {Type: "timestamp", Nullable: true},
+			"lives": {Type: "int32"},
+		},
+	}
+	dog := &JTDSchema{
+		OptionalProperties: map[string]*JTDSchema{
+			"tags":  {Elements: &JTDSchema{Enum: []string{"good"}}},
+			"owner": {Ref: "Owner"},
+		},
+	}
+	assert.Equal(r.T(), &JTDSchema{Discriminator: "kind", Mapping: map[string]*JTDSchema{"cat": cat, "Dog": dog}}, schema.Definitions["Pet"])
+	assert.Equal(r.T(), "kind", schema.Discriminator)
+	assert.Equal(r.T(), &JTDSchema{Values: &JTDSchema{Type: "float32"}}, schema.Definitions["Labels"])
+	assert.Equal(r.T(), &JTDSchema{
+		OptionalProperties:   map[string]*JTDSchema{"id": {Type: "float64"}},
+		AdditionalProperties: true,
+	}, schema.Definitions["Owner"])
+	assert.Equal(r.T(), &JTDSchema{}, schema.Definitions["Any"])
+
+	assert.Len(r.T(), findings, 5)
+	messages := map[string]string{}
+	for _, finding := range findings {
+		assert.Equal(r.T(), RuleJTDUnsupported, finding.Rule)
+		messages[finding.Pointer] = finding.Message
+	}
+	assert.Equal(r.T(), map[string]string{
+		"/components/schemas/Any":                        "oneOf and anyOf cannot be represented without discriminator",
+		"/components/schemas/Cat/properties/lives":       "minimum cannot be represented",
+		"/components/schemas/Cat/properties/name":        "pattern cannot be represented",
+		"/components/schemas/Owner/additionalProperties": "the schema of additional properties cannot be represented",
+		"/components/schemas/Owner/properties/id":        "int64 integers cannot be represented",
+	}, messages)
+}
+
+func (r *JTDSuite) TestSchemas() {
+	jtd := JTDSchema{
+		Definitions: map[string]*JTDSchema{
+			"Owner": {Properties: map[string]*JTDSchema{"age": {Type: "uint8"}}},
+		},
+		Discriminator: "kind",
+		Mapping: map[string]*JTDSchema{
+			"cat": {
+				Properties:         map[string]*JTDSchema{"born": {Type: "timestamp"}},
+				OptionalProperties: map[string]*JTDSchema{"owner": {Ref: "Owner", Nullable: true}},
+			},
+		},
+	}
+	schemas, err := jtd.Schemas("Pet")
+	assert.Nil(r.T(), err)
+	properties := Properties{}
+	properties.Set("kind", &Schema{Type: "string", Enum: []interface{}{"cat"}})
+	properties.Set("born", &Schema{Type: "string", Format: "date-time"})
+	properties.Set("owner", &Schema{Nullable: true, AllOf: []*Schema{{Ref: "#/components/schemas/Owner"}}})
+	assert.Equal(r.T(), map[string]*Schema{
+		"Owner": {
+			Type:       "object",
+			Required:   []string{"age"},
+			Properties: NewProperties(map[string]*Schema{"age": {Type: "integer", Format: "int32", Minimum: int64(0), Maximum: int64(255)}}),
+		},
+		"Pet": {
+			OneOf: []*Schema{{Ref: "#/components/schemas/PetCat"}},
+			Discriminator: &Discriminator{
+				PropertyName: "kind",
+				Mapping:      map[string]string{"cat": "#/components/schemas/PetCat"},
+			},
+		},
+		"PetCat": {
+			Type:       "object",
+			Required:   []string{"kind", "born"},
+			Properties: properties,
+		},
+	}, schemas)
+
+	testCases := []JTDSchema{
+		{Ref: "Missing"},
+		{Type: "int64"},
+		{Definitions: map[string]*JTDSchema{"Pet": {}}},
+	}
+	for i, testCase := range testCases {
+		failMsg := "test case %d failed"
+		_, err := testCase.Schemas("Pet")
+		assert.NotNil(r.T(), err, failMsg, i)
+	}
+}
+
+func TestJTDSuite(t *testing.T) {
+	suite.Run(t, new(JTDSuite))
+}
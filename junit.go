@@ -0,0 +1,75 @@
+package oas
+
+import (
+	"encoding/xml"
+	"io"
+
+	"github.com/pkg/errors"
+)
+
+// WriteJUnit writes the findings as a JUnit XML report named after the
+// document, so the test summaries of Jenkins and GitLab show them without
+// custom glue. Every rule becomes a test suite and every finding a failed
+// test case named after its pointer, the severity being the type of the
+// failure. Callers wanting only some severities to fail the build filter
+// the findings first.
+func WriteJUnit(w io.Writer, name string, findings []*Finding) error {
+	type failure struct {
+		Message string `xml:"message,attr"`
+		Type    string `xml:"type,attr"`
+		Text    string `xml:",chardata"`
+	}
+	type testCase struct {
+		Name      string  `xml:"name,attr"`
+		ClassName string  `xml:"classname,attr"`
+		Failure   failure `xml:"failure"`
+	}
+	type testSuite struct {
+		Name      string     `xml:"name,attr"`
+		Tests     int        `xml:"tests,attr"`
+		Failures  int        `xml:"failures,attr"`
+		TestCases []testCase `xml:"testcase"`
+	}
+	type testSuites struct {
+		XMLName    xml.Name     `xml:"testsuites"`
+		Name       string       `xml:"name,attr"`
+		Tests      int          `xml:"tests,attr"`
+		Failures   int          `xml:"failures,attr"`
+		TestSuites []*testSuite `xml:"testsuite"`
+	}
+
+	suites := map[string]*testSuite{}
+	for _, finding := range findings {
+		suite, ok := suites[finding.Rule]
+		if !ok {
+			suite = &testSuite{Name: finding.Rule}
+			suites[finding.Rule] = suite
+		}
+		text := finding.Pointer
+		if finding.Location != nil {
+			text = finding.Location.String() + ": " + text
+		}
+		suite.Tests++
+		suite.Failures++
+		suite.TestCases = append(suite.TestCases, testCase{
+			Name:      finding.Pointer,
+			ClassName: finding.Rule,
+			Failure:   failure{Message: finding.Message, Type: string(finding.Severity), Text: text},
+		})
+	}
+
+	report := testSuites{Name: name, Tests: len(findings), Failures: len(findings)}
+	for _, rule := range sortedStrings(suites) {
+		report.TestSuites = append(report.TestSuites, suites[rule])
+	}
+	if _, err := io.WriteString(w, xml.Header); err != nil {
+		return errors.WithStack(err)
+	}
+	encoder := xml.NewEncoder(w)
+	encoder.Indent("", "  ")
+	if err := encoder.Encode(report); err != nil {
+		return errors.WithStack(err)
+	}
+	_, err := io.WriteString(w, "\n")
+	return errors.WithStack(err)
+}
@@ -0,0 +1,68 @@
+package oas
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+)
+
+type JUnitSuite struct {
+	suite.Suite
+}
+
+func (r *JUnitSuite) TestWriteJUnit() {
+	testCases := []struct {
+		findings []*Finding
+		expected string
+	}{
+		{
+			nil,
+			`<?xml version="1.0" encoding="UTF-8"?>
+<testsuites name="openapi.yaml" tests="0" failures="0"></testsuites>
+`,
+		},
+		{
+			[]*Finding{
+				{
+					Pointer:  "/paths/~1pets/get",
+					Rule:     RuleSecurityMissing,
+					Severity: SeverityWarning,
+					Message:  "operation declares no security",
+					Location: &Source{Location: "openapi.yaml", Line: 7, Column: 5},
+				},
+				{Pointer: "/info/title", Rule: RuleFieldRequired, Severity: SeverityError, Message: "title is required"},
+				{Pointer: "/info/version", Rule: RuleFieldRequired, Severity: SeverityError, Message: "version is <required>"},
+			},
+			`<?xml version="1.0" encoding="UTF-8"?>
+<testsuites name="openapi.yaml" tests="3" failures="3">
+  <testsuite name="field-required" tests="2" failures="2">
+    <testcase name="/info/title" classname="field-required">
+      <failure message="title is required" type="error">/info/title</failure>
+    </testcase>
+    <testcase name="/info/version" classname="field-required">
+      <failure message="version is &lt;required&gt;" type="error">/info/version</failure>
+    </testcase>
+  </testsuite>
+  <testsuite name="security-missing" tests="1" failures="1">
+    <testcase name="/paths/~1pets/get" classname="security-missing">
+      <failure message="operation declares no security" type="warning">openapi.yaml:7:5: /paths/~1pets/get</failure>
+    </testcase>
+  </testsuite>
+</testsuites>
+`,
+		},
+	}
+
+	for i, testCase := range testCases {
+		failMsg := "test case %d failed"
+		buf := &bytes.Buffer{}
+		assert.Nil(r.T(), WriteJUnit(buf, "openapi.yaml", testCase.findings), failMsg, i)
+		assert.Equal(r.T(), testCase.expected, buf.String(), failMsg, i)
+	}
+}
+
+func TestJUnitSuite(t *testing.T) {
+	suite.Run(t, new(JUnitSuite))
+}
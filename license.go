@@ -4,7 +4,7 @@ import (
 	"encoding/json"
 
 	"github.com/pkg/errors"
-	"gopkg.in/yaml.v2"
+	"gopkg.in/yaml.v3"
 )
 
 // License information for the exposed API.
@@ -12,6 +12,11 @@ type License struct {
 	// Name describes the license name used for the API.
 	Name string `json:"name" yaml:"name"`
 
+	// Identifier describes an SPDX license expression for the API (e.g.
+	// "Apache-2.0"), introduced by OpenAPI 3.1. Mutually exclusive with the
+	// url field.
+	Identifier string `json:"identifier,omitempty" yaml:"identifier,omitempty"`
+
 	// URL describes a URL to the license used for the API. MUST be in the
 	// format of a URL.
 	URL string `json:"url,omitempty" yaml:"url,omitempty"`
@@ -45,22 +50,7 @@ func (r License) MarshalJSON() ([]byte, error) {
 
 // UnmarshalJSON parses the JSON-encoded data and stores the result.
 func (r *License) UnmarshalJSON(data []byte) error {
-	return r.UnmarshalYAML(func(in interface{}) error {
-		obj := make(map[string]interface{})
-		if err := json.Unmarshal(data, &obj); err != nil {
-			return errors.WithStack(err)
-		}
-
-		rbytes, err := yaml.Marshal(obj)
-		if err != nil {
-			return errors.WithStack(err)
-		}
-
-		if err := yaml.Unmarshal(rbytes, in); err != nil {
-			return errors.WithStack(err)
-		}
-		return nil
-	})
+	return decodeJSON(data, r)
 }
 
 // MarshalYAML returns the YAML encoding.
@@ -69,6 +59,10 @@ func (r License) MarshalYAML() (interface{}, error) {
 
 	obj["name"] = r.Name
 
+	if r.Identifier != "" {
+		obj["identifier"] = r.Identifier
+	}
+
 	if r.URL != "" {
 		obj["url"] = r.URL
 	}
@@ -81,7 +75,13 @@ func (r License) MarshalYAML() (interface{}, error) {
 }
 
 // UnmarshalYAML parses the YAML-encoded data and stores the result.
-func (r *License) UnmarshalYAML(unmarshal func(interface{}) error) error {
+func (r *License) UnmarshalYAML(node *yaml.Node) error {
+	return decodeYAML(node, r)
+}
+
+// unmarshalValue stores the result of the unmarshal function, which decodes
+// either JSON or YAML data.
+func (r *License) unmarshalValue(d *valueDecoder, unmarshal func(interface{}) error) error {
 	obj := make(map[string]interface{})
 	if err := unmarshal(&obj); err != nil {
 		return errors.WithStack(err)
@@ -93,6 +93,12 @@ func (r *License) UnmarshalYAML(unmarshal func(interface{}) error) error {
 		}
 	}
 
+	if value, ok := obj["identifier"]; ok {
+		if value, ok := value.(string); ok {
+			r.Identifier = value
+		}
+	}
+
 	if value, ok := obj["url"]; ok {
 		if value, ok := value.(string); ok {
 			r.URL = value
@@ -0,0 +1,127 @@
+package oas
+
+import (
+	"strings"
+)
+
+// Rules of the findings reported by AuditLicense.
+const (
+	RuleLicenseMissing        = "license-missing"
+	RuleLicenseNotAllowed     = "license-not-allowed"
+	RuleTermsOfServiceMissing = "terms-of-service-missing"
+	RuleContactMissing        = "contact-missing"
+)
+
+// spdxNames maps the lowercase names commonly given to licenses to their
+// SPDX identifiers.
+var spdxNames = map[string]string{
+	"apache 2.0":                  "Apache-2.0",
+	"apache 2":                    "Apache-2.0",
+	"apache license 2.0":          "Apache-2.0",
+	"apache license, version 2.0": "Apache-2.0",
+	"mit":                         "MIT",
+	"mit license":                 "MIT",
+	"bsd 2-clause":                "BSD-2-Clause",
+	"bsd 3-clause":                "BSD-3-Clause",
+	"gpl 2.0":                     "GPL-2.0-only",
+	"gpl 3.0":                     "GPL-3.0-only",
+	"lgpl 2.1":                    "LGPL-2.1-only",
+	"lgpl 3.0":                    "LGPL-3.0-only",
+	"mpl 2.0":                     "MPL-2.0",
+	"isc":                         "ISC",
+	"unlicense":                   "Unlicense",
+	"cc0 1.0":                     "CC0-1.0",
+	"cc by 4.0":                   "CC-BY-4.0",
+}
+
+// LicensePolicy describes the compliance requirements checked by
+// AuditLicense.
+type LicensePolicy struct {
+	// Allowed describes the SPDX identifiers of the licenses the API may be
+	// published under (e.g. "Apache-2.0"). When empty, any license is
+	// accepted but one must be declared.
+	Allowed []string
+
+	// Public requires the terms of service and the contact information
+	// expected of APIs published to external consumers.
+	Public bool
+}
+
+// SPDX returns the SPDX identifier of the license: the identifier field when
+// set, and otherwise the identifier of a well-known license name (e.g.
+// "Apache 2.0"), or an empty string.
+func (r License) SPDX() string {
+	if r.Identifier != "" {
+		return r.Identifier
+	}
+	if identifier, ok := spdxNames[strings.ToLower(strings.TrimSpace(r.Name))]; ok {
+		return identifier
+	}
+	for _, identifier := range spdxNames {
+		if strings.EqualFold(identifier, strings.TrimSpace(r.Name)) {
+			return identifier
+		}
+	}
+	return ""
+}
+
+// AllowsLicense reports whether the policy allows the license, comparing
+// SPDX identifiers case-insensitively.
+func (r LicensePolicy) AllowsLicense(license License) bool {
+	if len(r.Allowed) == 0 {
+		return true
+	}
+	identifier := license.SPDX()
+	for _, allowed := range r.Allowed {
+		if identifier != "" && strings.EqualFold(allowed, identifier) {
+			return true
+		}
+	}
+	return false
+}
+
+// AuditLicense flags documents which declare no license or a license the
+// policy does not allow, and public documents without terms of service or
+// contact information, so compliance requirements are enforced in CI.
+func (r OpenAPI) AuditLicense(policy LicensePolicy) []*Finding {
+	findings := make([]*Finding, 0)
+	switch {
+	case r.Info.License == nil || (r.Info.License.Name == "" && r.Info.License.Identifier == ""):
+		findings = append(findings, &Finding{
+			Pointer:  "/info",
+			Rule:     RuleLicenseMissing,
+			Severity: SeverityError,
+			Message:  "document declares no license",
+		})
+	case !policy.AllowsLicense(*r.Info.License):
+		name := r.Info.License.SPDX()
+		if name == "" {
+			name = r.Info.License.Name
+		}
+		findings = append(findings, &Finding{
+			Pointer:  "/info/license",
+			Rule:     RuleLicenseNotAllowed,
+			Severity: SeverityError,
+			Message:  "license " + name + " is not allowed, expected one of " + strings.Join(policy.Allowed, ", "),
+		})
+	}
+
+	if policy.Public && r.Info.TermsOfService == "" {
+		findings = append(findings, &Finding{
+			Pointer:  "/info",
+			Rule:     RuleTermsOfServiceMissing,
+			Severity: SeverityError,
+			Message:  "public document declares no terms of service",
+		})
+	}
+	if policy.Public && (r.Info.Contact == nil || (r.Info.Contact.Email == "" && r.Info.Contact.URL == "")) {
+		findings = append(findings, &Finding{
+			Pointer:  "/info",
+			Rule:     RuleContactMissing,
+			Severity: SeverityError,
+			Message:  "public document declares no contact email or URL",
+		})
+	}
+	sortFindings(findings)
+	return findings
+}
@@ -0,0 +1,108 @@
+package oas
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+)
+
+type LicensePolicySuite struct {
+	suite.Suite
+}
+
+func (r *LicensePolicySuite) TestSPDX() {
+	testCases := []struct {
+		license  License
+		expected string
+	}{
+		{License{Name: "Apache 2.0", Identifier: "Apache-2.0"}, "Apache-2.0"},
+		{License{Name: "Apache License, Version 2.0"}, "Apache-2.0"},
+		{License{Name: "mit"}, "MIT"},
+		{License{Name: "bsd-3-clause"}, "BSD-3-Clause"},
+		{License{Name: "Proprietary"}, ""},
+	}
+
+	failMsg := "test case %d failed"
+	for i, tc := range testCases {
+		assert.Equal(r.T(), tc.expected, tc.license.SPDX(), failMsg, i)
+	}
+}
+
+func (r *LicensePolicySuite) TestAuditLicense() {
+	testCases := []struct {
+		info     Info
+		policy   LicensePolicy
+		expected []*Finding
+	}{
+		{
+			Info{License: &License{Name: "MIT"}},
+			LicensePolicy{Allowed: []string{"mit", "Apache-2.0"}},
+			[]*Finding{},
+		},
+		{
+			Info{
+				TermsOfService: "https://example.com/terms",
+				Contact:        &Contact{Email: "api@example.com"},
+				License:        &License{Name: "Apache 2.0", Identifier: "Apache-2.0"},
+			},
+			LicensePolicy{Allowed: []string{"Apache-2.0"}, Public: true},
+			[]*Finding{},
+		},
+		{
+			Info{License: &License{Name: "GNU GPL", Identifier: "GPL-3.0-only"}},
+			LicensePolicy{Allowed: []string{"MIT", "Apache-2.0"}},
+			[]*Finding{{
+				Pointer:  "/info/license",
+				Rule:     RuleLicenseNotAllowed,
+				Severity: SeverityError,
+				Message:  "license GPL-3.0-only is not allowed, expected one of MIT, Apache-2.0",
+			}},
+		},
+		{
+			Info{License: &License{Name: "Proprietary"}},
+			LicensePolicy{Allowed: []string{"MIT"}},
+			[]*Finding{{
+				Pointer:  "/info/license",
+				Rule:     RuleLicenseNotAllowed,
+				Severity: SeverityError,
+				Message:  "license Proprietary is not allowed, expected one of MIT",
+			}},
+		},
+		{
+			Info{Contact: &Contact{Name: "API Team"}},
+			LicensePolicy{Public: true},
+			[]*Finding{
+				{
+					Pointer:  "/info",
+					Rule:     RuleContactMissing,
+					Severity: SeverityError,
+					Message:  "public document declares no contact email or URL",
+				},
+				{
+					Pointer:  "/info",
+					Rule:     RuleLicenseMissing,
+					Severity: SeverityError,
+					Message:  "document declares no license",
+				},
+				{
+					Pointer:  "/info",
+					Rule:     RuleTermsOfServiceMissing,
+					Severity: SeverityError,
+					Message:  "public document declares no terms of service",
+				},
+			},
+		},
+	}
+
+	failMsg := "test case %d failed"
+	for i, tc := range testCases {
+		tc.info.Title, tc.info.Version = "Petstore", "1.0.0"
+		doc := OpenAPI{OpenAPI: "3.0.0", Info: tc.info}
+		assert.Equal(r.T(), tc.expected, doc.AuditLicense(tc.policy), failMsg, i)
+	}
+}
+
+func TestLicensePolicySuite(t *testing.T) {
+	suite.Run(t, new(LicensePolicySuite))
+}
@@ -7,7 +7,7 @@ import (
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/suite"
-	"gopkg.in/yaml.v2"
+	"gopkg.in/yaml.v3"
 )
 
 type LicenseSuite struct {
@@ -32,6 +32,13 @@ func (r *LicenseSuite) TestLicense() {
 				},
 			},
 		},
+		{
+			false,
+			&License{
+				Name:       "Apache 2.0",
+				Identifier: "Apache-2.0",
+			},
+		},
 	}
 
 	for i, testCase := range testCases {
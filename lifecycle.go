@@ -0,0 +1,267 @@
+package oas
+
+import (
+	"reflect"
+	"sort"
+
+	"github.com/pkg/errors"
+)
+
+// LifecycleExtension names the specification extension declaring the
+// lifecycle stage of an operation, a path item, a parameter, a schema or the
+// whole document. Objects without the extension are generally available,
+// operations inherit the stage of their path item and of the document.
+const LifecycleExtension = "x-lifecycle"
+
+// Lifecycle describes the maturity of a part of the API.
+type Lifecycle string
+
+// Lifecycle stages.
+const (
+	LifecycleDraft   Lifecycle = "draft"
+	LifecycleBeta    Lifecycle = "beta"
+	LifecycleGA      Lifecycle = "ga"
+	LifecycleRetired Lifecycle = "retired"
+)
+
+// Rules reported by AuditLifecycle.
+const (
+	RuleInvalidLifecycle = "invalid-lifecycle"
+	RuleGADependsOnNonGA = "ga-depends-on-non-ga"
+)
+
+// Lifecycle returns the stage declared by the x-lifecycle extension, or an
+// empty stage if the extension is absent.
+func (r Extensions) Lifecycle() (Lifecycle, error) {
+	value, ok := r[LifecycleExtension]
+	if !ok {
+		return "", nil
+	}
+	stage, _ := value.(string)
+	switch lifecycle := Lifecycle(stage); lifecycle {
+	case LifecycleDraft, LifecycleBeta, LifecycleGA, LifecycleRetired:
+		return lifecycle, nil
+	}
+	return "", errors.Errorf("%s: unknown lifecycle stage %v", LifecycleExtension, value)
+}
+
+// SetLifecycle declares the stage with the x-lifecycle extension, or removes
+// the extension when empty.
+func (r *Extensions) SetLifecycle(lifecycle Lifecycle) {
+	if lifecycle == "" {
+		delete(*r, LifecycleExtension)
+		return
+	}
+	setExtension(r, LifecycleExtension, string(lifecycle))
+}
+
+// lifecycleOf returns the declared stage, treating missing and invalid
+// declarations as generally available.
+func lifecycleOf(exts Extensions) Lifecycle {
+	if lifecycle, err := exts.Lifecycle(); err == nil && lifecycle != "" {
+		return lifecycle
+	}
+	return LifecycleGA
+}
+
+// OperationLifecycle returns the stage of the operation, taken from the
+// closest declaration on the operation, the path item or the document.
+func (r OpenAPI) OperationLifecycle(item *PathItem, operation *Operation) Lifecycle {
+	candidates := []Extensions{operation.Extensions}
+	if item != nil {
+		candidates = append(candidates, item.Extensions)
+	}
+	for _, exts := range append(candidates, r.Extensions) {
+		if lifecycle, err := exts.Lifecycle(); err == nil && lifecycle != "" {
+			return lifecycle
+		}
+	}
+	return LifecycleGA
+}
+
+// AuditLifecycle reports x-lifecycle extensions holding unknown stages and
+// generally available operations depending, directly or through other
+// components, on component schemas which are not generally available.
+func (r OpenAPI) AuditLifecycle() []*Finding {
+	findings := make([]*Finding, 0)
+	_ = walk(&r, func(ptr string, node interface{}) error {
+		exts, ok := extensionsOf(node)
+		if !ok {
+			return nil
+		}
+		if _, err := exts.Lifecycle(); err != nil {
+			findings = append(findings, &Finding{
+				Pointer:  join(ptr, LifecycleExtension),
+				Rule:     RuleInvalidLifecycle,
+				Severity: SeverityError,
+				Message:  errors.Cause(err).Error(),
+			})
+		}
+		return nil
+	})
+
+	graph := r.ReferenceGraph()
+	edges := map[string][]string{}
+	for _, edge := range graph.Edges {
+		edges[edge.From] = append(edges[edge.From], edge.To)
+	}
+	for _, op := range r.Paths.operations() {
+		if r.OperationLifecycle(op.item, op.operation) != LifecycleGA {
+			continue
+		}
+		ptr := join("/paths", op.path, op.method)
+		for _, name := range reachableSchemas(edges, "#"+join("/paths", op.path), "#"+ptr) {
+			schema := r.Components.Schemas[name]
+			if schema == nil {
+				continue
+			}
+			lifecycle := lifecycleOf(schema.Extensions)
+			if lifecycle == LifecycleGA {
+				continue
+			}
+			findings = append(findings, &Finding{
+				Pointer:  ptr,
+				Rule:     RuleGADependsOnNonGA,
+				Severity: SeverityError,
+				Message:  "generally available operation depends on " + string(lifecycle) + " schema " + name,
+			})
+		}
+	}
+	sortFindings(findings)
+	return findings
+}
+
+// reachableSchemas returns the sorted names of the component schemas
+// reachable from the nodes of the reference graph.
+func reachableSchemas(edges map[string][]string, roots ...string) []string {
+	visited := map[string]bool{}
+	queue := append([]string{}, roots...)
+	names := make([]string, 0)
+	for len(queue) > 0 {
+		id := queue[0]
+		queue = queue[1:]
+		for _, to := range edges[id] {
+			if visited[to] {
+				continue
+			}
+			visited[to] = true
+			queue = append(queue, to)
+			if name, ok := componentName(to, "schemas"); ok {
+				names = append(names, name)
+			}
+		}
+	}
+	sort.Strings(names)
+	return names
+}
+
+// extensionsOf returns the extensions of a document object.
+func extensionsOf(node interface{}) (Extensions, bool) {
+	value := reflect.ValueOf(node)
+	if value.Kind() != reflect.Ptr || value.IsNil() || value.Elem().Kind() != reflect.Struct {
+		return nil, false
+	}
+	field := value.Elem().FieldByName("Extensions")
+	if !field.IsValid() || field.Type() != reflect.TypeOf(Extensions{}) {
+		return nil, false
+	}
+	return field.Interface().(Extensions), true
+}
+
+// FilterLifecycle removes the parts of the API whose stage is not allowed,
+// so that e.g. public artifacts only expose the generally available surface:
+// operations, path items left without operations, parameters, component
+// schemas and schema properties. Objects without a stage count as generally
+// available. References to removed component schemas are left untouched,
+// AuditLifecycle reports the operations holding them beforehand. The
+// pointers of the removed objects are returned in sorted order.
+func (r *OpenAPI) FilterLifecycle(allowed ...Lifecycle) []string {
+	permitted := map[Lifecycle]bool{}
+	for _, lifecycle := range allowed {
+		permitted[lifecycle] = true
+	}
+	removed := make([]string, 0)
+	filterParameters := func(ptr string, parameters []*Parameter) []*Parameter {
+		kept := make([]*Parameter, 0, len(parameters))
+		for i, parameter := range parameters {
+			if parameter != nil && !permitted[lifecycleOf(parameter.Extensions)] {
+				removed = append(removed, index(ptr, "parameters", i))
+				continue
+			}
+			kept = append(kept, parameter)
+		}
+		if len(kept) == 0 {
+			return nil
+		}
+		return kept
+	}
+
+	for _, path := range sortedKeys(r.Paths.PathItems) {
+		item := r.Paths.PathItems.Get(path)
+		if item == nil {
+			continue
+		}
+		ops := item.operations()
+		remaining := len(ops)
+		for _, op := range ops {
+			ptr := join("/paths", path, op.method)
+			if !permitted[r.OperationLifecycle(item, op.operation)] {
+				item.setOperation(op.method, nil)
+				removed = append(removed, ptr)
+				remaining--
+				continue
+			}
+			op.operation.Parameters = filterParameters(ptr, op.operation.Parameters)
+		}
+		if len(ops) > 0 && remaining == 0 {
+			r.Paths.PathItems.Delete(path)
+			removed = append(removed, join("/paths", path))
+			continue
+		}
+		item.Parameters = filterParameters(join("/paths", path), item.Parameters)
+	}
+
+	if r.Components != nil {
+		for _, name := range sortedKeys(r.Components.Schemas) {
+			if schema := r.Components.Schemas[name]; schema != nil && !permitted[lifecycleOf(schema.Extensions)] {
+				delete(r.Components.Schemas, name)
+				removed = append(removed, join("/components/schemas", name))
+			}
+		}
+		for _, name := range sortedKeys(r.Components.Parameters) {
+			if parameter := r.Components.Parameters[name]; parameter != nil && !permitted[lifecycleOf(parameter.Extensions)] {
+				delete(r.Components.Parameters, name)
+				removed = append(removed, join("/components/parameters", name))
+			}
+		}
+	}
+
+	_ = walk(r, func(ptr string, node interface{}) error {
+		schema, ok := node.(*Schema)
+		if !ok {
+			return nil
+		}
+		for _, name := range sortedKeys(schema.Properties) {
+			property := schema.Properties.Get(name)
+			if property == nil || permitted[lifecycleOf(property.Extensions)] {
+				continue
+			}
+			schema.Properties.Delete(name)
+			removed = append(removed, join(ptr, "properties", name))
+			required := make([]string, 0, len(schema.Required))
+			for _, value := range schema.Required {
+				if value != name {
+					required = append(required, value)
+				}
+			}
+			if len(required) == 0 {
+				required = nil
+			}
+			schema.Required = required
+		}
+		return nil
+	})
+
+	sort.Strings(removed)
+	return removed
+}
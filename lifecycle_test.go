@@ -0,0 +1,126 @@
+package oas
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+)
+
+type LifecycleSuite struct {
+	suite.Suite
+}
+
+// lifecyclePatch is the patch of the petstore fixture declaring the
+// lifecycle stages the suite audits.
+const lifecyclePatch = `
+paths:
+  /pets:
+    get:
+      parameters:
+        - name: limit
+          in: query
+        - name: cursor
+          in: query
+          x-lifecycle: beta
+    post:
+      requestBody:
+        content:
+          application/json:
+            schema:
+              $ref: "#/components/schemas/NewPet"
+      x-lifecycle: draft
+  /pets/search:
+    get:
+      responses:
+        "200":
+          description: The pets found.
+    x-lifecycle: beta
+  /stores:
+    get:
+      responses:
+        "200":
+          description: The stores.
+      x-lifecycle: stable
+components:
+  schemas:
+    Pet:
+      required: [name, mood]
+      properties:
+        tag: null
+        mood:
+          type: string
+          x-lifecycle: beta
+        owner:
+          $ref: "#/components/schemas/Owner"
+    Owner:
+      type: object
+      x-lifecycle: beta
+    NewPet:
+      type: object
+      x-lifecycle: draft
+`
+
+func (r *LifecycleSuite) TestAccessors() {
+	exts := Extensions{}
+	exts.SetLifecycle(LifecycleBeta)
+	lifecycle, err := exts.Lifecycle()
+	assert.Nil(r.T(), err)
+	assert.Equal(r.T(), LifecycleBeta, lifecycle)
+
+	exts.SetLifecycle("")
+	lifecycle, err = exts.Lifecycle()
+	assert.Nil(r.T(), err)
+	assert.Equal(r.T(), Lifecycle(""), lifecycle)
+
+	_, err = Extensions{LifecycleExtension: "stable"}.Lifecycle()
+	assert.NotNil(r.T(), err)
+
+	doc := petstore(r.T(), lifecyclePatch)
+	item := doc.Paths.PathItems.Get("/pets/search")
+	assert.Equal(r.T(), LifecycleBeta, doc.OperationLifecycle(item, item.Get))
+	item = doc.Paths.PathItems.Get("/pets")
+	assert.Equal(r.T(), LifecycleGA, doc.OperationLifecycle(item, item.Get))
+	assert.Equal(r.T(), LifecycleDraft, doc.OperationLifecycle(item, item.Post))
+}
+
+func (r *LifecycleSuite) TestAuditLifecycle() {
+	findings := petstore(r.T(), lifecyclePatch).AuditLifecycle()
+	assert.Len(r.T(), findings, 3)
+	assert.Equal(r.T(), "/paths/~1pets/get", findings[0].Pointer)
+	assert.Equal(r.T(), RuleGADependsOnNonGA, findings[0].Rule)
+	assert.Contains(r.T(), findings[0].Message, "beta schema Owner")
+	assert.Equal(r.T(), "/paths/~1pets~1{petId}/get", findings[1].Pointer)
+	assert.Equal(r.T(), RuleGADependsOnNonGA, findings[1].Rule)
+	assert.Equal(r.T(), "/paths/~1stores/get/x-lifecycle", findings[2].Pointer)
+	assert.Equal(r.T(), RuleInvalidLifecycle, findings[2].Rule)
+}
+
+func (r *LifecycleSuite) TestFilterLifecycle() {
+	doc := petstore(r.T(), lifecyclePatch)
+	removed := doc.FilterLifecycle(LifecycleGA)
+	assert.Equal(r.T(), []string{
+		"/components/schemas/NewPet",
+		"/components/schemas/Owner",
+		"/components/schemas/Pet/properties/mood",
+		"/paths/~1pets/get/parameters/1",
+		"/paths/~1pets/post",
+		"/paths/~1pets~1search",
+		"/paths/~1pets~1search/get",
+	}, removed)
+
+	assert.Nil(r.T(), doc.Paths.PathItems.Get("/pets").Post)
+	assert.Len(r.T(), doc.Paths.PathItems.Get("/pets").Get.Parameters, 1)
+	assert.NotContains(r.T(), doc.Paths.PathItems.Keys(), "/pets/search")
+	assert.Contains(r.T(), doc.Paths.PathItems.Keys(), "/stores")
+	assert.Equal(r.T(), []string{"name"}, doc.Components.Schemas["Pet"].Required)
+	assert.NotContains(r.T(), doc.Components.Schemas["Pet"].Properties.Keys(), "mood")
+
+	doc = petstore(r.T(), lifecyclePatch)
+	assert.Nil(r.T(), FilterLifecycleTransform(LifecycleGA, LifecycleBeta, LifecycleDraft)(doc))
+	assert.Equal(r.T(), petstore(r.T(), lifecyclePatch), doc)
+}
+
+func TestLifecycleSuite(t *testing.T) {
+	suite.Run(t, new(LifecycleSuite))
+}
@@ -0,0 +1,230 @@
+package oas
+
+import (
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// DefaultLiftThreshold is the complexity from which inline schemas are lifted
+// into components when LiftOptions does not specify a threshold.
+const DefaultLiftThreshold = 3
+
+// LiftOptions describes the behavior of LiftSchemas.
+type LiftOptions struct {
+	// Threshold describes the minimum complexity, measured as the number of
+	// schemas in the subtree including the schema itself, an inline object or
+	// composition schema must reach to be lifted. Defaults to
+	// DefaultLiftThreshold.
+	Threshold int
+}
+
+// LiftSchemas moves inline object and composition schemas whose complexity
+// reaches the threshold into the components section and replaces them with
+// references. Component names are derived from the location of the schema
+// (e.g. the request body of operation "createPet" becomes
+// "CreatePetRequest") and suffixed with a number when already taken. Lifting
+// is repeated until no inline schema qualifies, so nested inline schemas are
+// named after their lifted parents. The names of the created components are
+// returned in sorted order.
+func (r *OpenAPI) LiftSchemas(opts LiftOptions) []string {
+	threshold := opts.Threshold
+	if threshold <= 0 {
+		threshold = DefaultLiftThreshold
+	}
+
+	lifted := make([]string, 0)
+	for {
+		names := locationNames{}
+		count := len(lifted)
+		_ = walk(r, func(ptr string, node interface{}) error {
+			names.add(ptr, node)
+
+			schema, ok := node.(*Schema)
+			if !ok || isComponentRoot(ptr, "schemas") || !liftable(schema, threshold) {
+				return nil
+			}
+
+			if r.Components == nil {
+				r.Components = &Components{}
+			}
+			if r.Components.Schemas == nil {
+				r.Components.Schemas = map[string]*Schema{}
+			}
+
+			name := uniqueName(names[ptr].name, r.Components.Schemas)
+			value := *schema
+			r.Components.Schemas[name] = &value
+			*schema = Schema{Ref: "#/components/schemas/" + escapePointer(name)}
+			lifted = append(lifted, name)
+			return nil
+		})
+		if len(lifted) == count {
+			break
+		}
+	}
+
+	sort.Strings(lifted)
+	return lifted
+}
+
+// liftable reports whether the schema is an inline object or composition
+// schema whose complexity reaches the threshold.
+func liftable(schema *Schema, threshold int) bool {
+	if schema.Ref != "" {
+		return false
+	}
+	if schema.Properties.Len() == 0 && len(schema.AllOf) == 0 &&
+		len(schema.AnyOf) == 0 && len(schema.OneOf) == 0 {
+		return false
+	}
+	return schema.complexity() >= threshold
+}
+
+// complexity returns the number of schemas in the subtree of the schema,
+// including the schema itself.
+func (r *Schema) complexity() int {
+	if r == nil {
+		return 0
+	}
+	count := 1 + r.Items.complexity() + r.AdditionalProperties.complexity() + r.Not.complexity()
+	for _, name := range r.Properties.Keys() {
+		count += r.Properties.Get(name).complexity()
+	}
+	for _, values := range [][]*Schema{r.AllOf, r.AnyOf, r.OneOf} {
+		for _, value := range values {
+			count += value.complexity()
+		}
+	}
+	return count
+}
+
+// isComponentRoot reports whether the pointer addresses an entry declared
+// directly under the components section of the given kind.
+func isComponentRoot(ptr string, kind string) bool {
+	tokens := splitPointer(ptr)
+	return len(tokens) == 3 && tokens[0] == "components" && tokens[1] == kind
+}
+
+// splitPointer splits a JSON Pointer into unescaped reference tokens.
+func splitPointer(ptr string) []string {
+	if ptr == "" {
+		return []string{}
+	}
+	tokens := strings.Split(strings.TrimPrefix(ptr, "/"), "/")
+	for i := range tokens {
+		tokens[i] = unescapePointer(tokens[i])
+	}
+	return tokens
+}
+
+// locationName pairs a visited node with the name derived for it.
+type locationName struct {
+	name string
+	node interface{}
+}
+
+// locationNames holds the names derived for the nodes visited so far keyed
+// by their JSON Pointer.
+type locationNames map[string]locationName
+
+// add derives a PascalCase name for the node from its location in the
+// document and records it. Nodes extend the name of their closest named
+// ancestor, so the ancestors must be added first.
+func (r locationNames) add(ptr string, node interface{}) {
+	r[ptr] = locationName{r.derive(ptr, node), node}
+}
+
+func (r locationNames) derive(ptr string, node interface{}) string {
+	tokens := splitPointer(ptr)
+	n := len(tokens)
+	pascal := PascalCase.Format
+	parent := func(up int) string {
+		return r[parentPointer(tokens, up)].name
+	}
+
+	if n == 3 && tokens[0] == "components" {
+		return pascal(tokens[2])
+	}
+
+	switch node := node.(type) {
+	case *Operation:
+		if node.OperationID != "" {
+			return pascal(node.OperationID)
+		}
+		return pascal(tokens[n-1] + " " + parent(1))
+	case *PathItem:
+		if n == 2 && tokens[0] == "paths" {
+			return pascal(tokens[1])
+		}
+		return parent(1)
+	case *Parameter:
+		return parent(2) + pascal(node.Name) + "Parameter"
+	case *Schema:
+		if n >= 2 && tokens[n-2] == "properties" {
+			if _, ok := r[parentPointer(tokens, 2)].node.(*Schema); ok {
+				return parent(2) + pascal(tokens[n-1])
+			}
+		}
+	}
+
+	if n == 0 {
+		return ""
+	}
+
+	switch tokens[n-1] {
+	case "schema":
+		return parent(1)
+	case "items":
+		return parent(1) + "Item"
+	case "additionalProperties":
+		return parent(1) + "Value"
+	case "not":
+		return parent(1) + "Not"
+	case "requestBody":
+		return parent(1) + "Request"
+	}
+
+	if n < 2 {
+		return pascal(tokens[n-1])
+	}
+
+	key := tokens[n-1]
+	switch tokens[n-2] {
+	case "responses":
+		return parent(2) + pascal(key) + "Response"
+	case "headers":
+		return parent(2) + pascal(key) + "Header"
+	case "content":
+		return parent(2)
+	case "allOf", "anyOf", "oneOf":
+		return parent(2) + pascal(tokens[n-2]) + key
+	}
+	return parent(2) + pascal(key)
+}
+
+// parentPointer returns the pointer of the ancestor the given number of
+// tokens above.
+func parentPointer(tokens []string, up int) string {
+	if up > len(tokens) {
+		up = len(tokens)
+	}
+	return join("", tokens[:len(tokens)-up]...)
+}
+
+// uniqueName returns the name, suffixed with the lowest free number starting
+// at 2 when the name is empty or already declared.
+func uniqueName(name string, taken map[string]*Schema) string {
+	if name == "" {
+		name = "Schema"
+	}
+	if _, ok := taken[name]; !ok {
+		return name
+	}
+	for i := 2; ; i++ {
+		candidate := name + strconv.Itoa(i)
+		if _, ok := taken[candidate]; !ok {
+			return candidate
+		}
+	}
+}
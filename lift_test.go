@@ -0,0 +1,190 @@
+package oas
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+)
+
+type LiftSuite struct {
+	suite.Suite
+}
+
+func (r *LiftSuite) TestLiftSchemas() {
+	address := func() *Schema {
+		return &Schema{
+			Type: "object",
+			Properties: NewProperties(map[string]*Schema{
+				"city":   {Type: "string"},
+				"street": {Type: "string"},
+			}),
+		}
+	}
+
+	testCases := []struct {
+		opts     LiftOptions
+		input    *OpenAPI
+		expected *OpenAPI
+		lifted   []string
+	}{
+		{
+			LiftOptions{},
+			&OpenAPI{
+				Paths: Paths{
+					PathItems: NewPathItems(map[string]*PathItem{
+						"/pets": {
+							Post: &Operation{
+								OperationID: "createPet",
+								RequestBody: &RequestBody{
+									Content: map[string]*MediaType{
+										"application/json": {
+											Schema: &Schema{
+												Type: "object",
+												Properties: NewProperties(map[string]*Schema{
+													"name":    {Type: "string"},
+													"address": address(),
+												}),
+											},
+										},
+									},
+								},
+								Responses: NewResponses(map[string]*Response{
+									"200": {
+										Content: map[string]*MediaType{
+											"application/json": {
+												Schema: &Schema{
+													Type:       "object",
+													Properties: NewProperties(map[string]*Schema{"id": {Type: "string"}}),
+												},
+											},
+										},
+									},
+								}),
+							},
+							Get: &Operation{
+								Responses: NewResponses(map[string]*Response{
+									"200": {
+										Content: map[string]*MediaType{
+											"application/json": {
+												Schema: &Schema{Type: "array", Items: address()},
+											},
+										},
+									},
+								}),
+							},
+						},
+					}),
+				},
+			},
+			&OpenAPI{
+				Paths: Paths{
+					PathItems: NewPathItems(map[string]*PathItem{
+						"/pets": {
+							Post: &Operation{
+								OperationID: "createPet",
+								RequestBody: &RequestBody{
+									Content: map[string]*MediaType{
+										"application/json": {
+											Schema: &Schema{Ref: "#/components/schemas/CreatePetRequest"},
+										},
+									},
+								},
+								Responses: NewResponses(map[string]*Response{
+									"200": {
+										Content: map[string]*MediaType{
+											"application/json": {
+												Schema: &Schema{
+													Type:       "object",
+													Properties: NewProperties(map[string]*Schema{"id": {Type: "string"}}),
+												},
+											},
+										},
+									},
+								}),
+							},
+							Get: &Operation{
+								Responses: NewResponses(map[string]*Response{
+									"200": {
+										Content: map[string]*MediaType{
+											"application/json": {
+												Schema: &Schema{
+													Type:  "array",
+													Items: &Schema{Ref: "#/components/schemas/GetPets200ResponseItem"},
+												},
+											},
+										},
+									},
+								}),
+							},
+						},
+					}),
+				},
+				Components: &Components{
+					Schemas: map[string]*Schema{
+						"CreatePetRequest": {
+							Type: "object",
+							Properties: NewProperties(map[string]*Schema{
+								"name":    {Type: "string"},
+								"address": {Ref: "#/components/schemas/CreatePetRequestAddress"},
+							}),
+						},
+						"CreatePetRequestAddress": address(),
+						"GetPets200ResponseItem":  address(),
+					},
+				},
+			},
+			[]string{"CreatePetRequest", "CreatePetRequestAddress", "GetPets200ResponseItem"},
+		},
+		{
+			LiftOptions{Threshold: 2},
+			&OpenAPI{
+				Components: &Components{
+					Schemas: map[string]*Schema{
+						"Pet": {
+							Type: "object",
+							Properties: NewProperties(map[string]*Schema{
+								"owner": {
+									Type:       "object",
+									Properties: NewProperties(map[string]*Schema{"name": {Type: "string"}}),
+								},
+							}),
+						},
+						"PetOwner": {Type: "string"},
+					},
+				},
+			},
+			&OpenAPI{
+				Components: &Components{
+					Schemas: map[string]*Schema{
+						"Pet": {
+							Type: "object",
+							Properties: NewProperties(map[string]*Schema{
+								"owner": {Ref: "#/components/schemas/PetOwner2"},
+							}),
+						},
+						"PetOwner": {Type: "string"},
+						"PetOwner2": {
+							Type:       "object",
+							Properties: NewProperties(map[string]*Schema{"name": {Type: "string"}}),
+						},
+					},
+				},
+			},
+			[]string{"PetOwner2"},
+		},
+	}
+
+	for i, testCase := range testCases {
+		failMsg := fmt.Sprintf("testCase: %d %v", i, testCase)
+
+		lifted := testCase.input.LiftSchemas(testCase.opts)
+		assert.Equal(r.T(), testCase.lifted, lifted, failMsg)
+		assert.EqualValues(r.T(), testCase.expected, testCase.input, failMsg)
+	}
+}
+
+func TestLiftSuite(t *testing.T) {
+	suite.Run(t, new(LiftSuite))
+}
@@ -4,7 +4,7 @@ import (
 	"encoding/json"
 
 	"github.com/pkg/errors"
-	"gopkg.in/yaml.v2"
+	"gopkg.in/yaml.v3"
 )
 
 // Link represents a possible design-time link for a response.
@@ -73,22 +73,7 @@ func (r Link) MarshalJSON() ([]byte, error) {
 
 // UnmarshalJSON parses the JSON-encoded data and stores the result.
 func (r *Link) UnmarshalJSON(data []byte) error {
-	return r.UnmarshalYAML(func(in interface{}) error {
-		obj := make(map[string]interface{})
-		if err := json.Unmarshal(data, &obj); err != nil {
-			return errors.WithStack(err)
-		}
-
-		rbytes, err := yaml.Marshal(obj)
-		if err != nil {
-			return errors.WithStack(err)
-		}
-
-		if err := yaml.Unmarshal(rbytes, in); err != nil {
-			return errors.WithStack(err)
-		}
-		return nil
-	})
+	return decodeJSON(data, r)
 }
 
 // MarshalYAML returns the YAML encoding.
@@ -131,7 +116,13 @@ func (r Link) MarshalYAML() (interface{}, error) {
 }
 
 // UnmarshalYAML parses the YAML-encoded data and stores the result.
-func (r *Link) UnmarshalYAML(unmarshal func(interface{}) error) error {
+func (r *Link) UnmarshalYAML(node *yaml.Node) error {
+	return decodeYAML(node, r)
+}
+
+// unmarshalValue stores the result of the unmarshal function, which decodes
+// either JSON or YAML data.
+func (r *Link) unmarshalValue(d *valueDecoder, unmarshal func(interface{}) error) error {
 	obj := make(map[string]interface{})
 	if err := unmarshal(&obj); err != nil {
 		return errors.WithStack(err)
@@ -155,14 +146,10 @@ func (r *Link) UnmarshalYAML(unmarshal func(interface{}) error) error {
 		}
 	}
 
-	if value, ok := obj["parameters"]; ok {
-		rbytes, err := yaml.Marshal(value)
-		if err != nil {
-			return errors.WithStack(err)
-		}
+	if raw, ok := obj["parameters"]; ok {
 		value := map[string]string{}
-		if err := yaml.Unmarshal(rbytes, &value); err != nil {
-			return errors.WithStack(err)
+		if err := d.decodeMember("parameters", raw, &value); err != nil {
+			return err
 		}
 		r.Parameters = value
 	}
@@ -179,14 +166,10 @@ func (r *Link) UnmarshalYAML(unmarshal func(interface{}) error) error {
 		}
 	}
 
-	if value, ok := obj["server"]; ok {
-		rbytes, err := yaml.Marshal(value)
-		if err != nil {
-			return errors.WithStack(err)
-		}
+	if raw, ok := obj["server"]; ok {
 		value := Server{}
-		if err := yaml.Unmarshal(rbytes, &value); err != nil {
-			return errors.WithStack(err)
+		if err := d.decodeMember("server", raw, &value); err != nil {
+			return err
 		}
 		r.Server = &value
 	}
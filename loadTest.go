@@ -0,0 +1,204 @@
+package oas
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"io"
+	"net/url"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// LoadTestOptions describes the behavior of LoadTestRequests, WriteHAR and
+// WriteVegetaTargets.
+type LoadTestOptions struct {
+	// BaseURL describes the URL of the API under test the paths are
+	// appended to.
+	BaseURL string
+
+	// Headers describes the headers sent with every request, such as
+	// authentication headers, overriding the examples of header parameters.
+	Headers map[string]string
+
+	// Weights describes the traffic profile: the relative share of requests
+	// per operation, keyed by operationId or by the uppercase method and path
+	// (e.g. "GET /pets") for operations without one. Operations default to a
+	// weight of 1, a weight of 0 leaves them out.
+	Weights map[string]int
+}
+
+// LoadTestRequest describes a request of a load test scenario.
+type LoadTestRequest struct {
+	// Operation describes the operationId of the operation, or its
+	// uppercase method and path when it has none.
+	Operation string `json:"operation" yaml:"operation"`
+
+	// Method describes the uppercase HTTP method.
+	Method string `json:"method" yaml:"method"`
+
+	// URL describes the URL of the request, including the query string.
+	URL string `json:"url" yaml:"url"`
+
+	// Headers describes the headers of the request.
+	Headers map[string]string `json:"headers,omitempty" yaml:"headers,omitempty"`
+
+	// Body describes the body of the request, nil when it has none.
+	Body *string `json:"body,omitempty" yaml:"body,omitempty"`
+
+	// Weight describes the relative share of the request in the traffic.
+	Weight int `json:"weight" yaml:"weight"`
+}
+
+// LoadTestRequests returns a request per operation, ordered by path and
+// method, built from the examples of the parameters and of the request body
+// like the test skeletons of WriteTestSkeletons, so performance tests stay
+// aligned with the contract. Operations whose required parameters or body
+// have no example are left out.
+func (r OpenAPI) LoadTestRequests(opts LoadTestOptions) ([]*LoadTestRequest, error) {
+	if opts.BaseURL == "" {
+		return nil, errors.New("missing base URL")
+	}
+	requests := make([]*LoadTestRequest, 0)
+	for _, op := range r.Paths.operations() {
+		name := op.operation.OperationID
+		if name == "" {
+			name = strings.ToUpper(op.method) + " " + op.path
+		}
+		weight, ok := opts.Weights[name]
+		if !ok {
+			weight = 1
+		}
+		sample, _ := r.sampleRequest(op)
+		if sample == nil || weight <= 0 {
+			continue
+		}
+		for header, value := range opts.Headers {
+			sample.headers[header] = value
+		}
+		requests = append(requests, &LoadTestRequest{
+			Operation: name,
+			Method:    strings.ToUpper(op.method),
+			URL:       strings.TrimSuffix(opts.BaseURL, "/") + sample.path,
+			Headers:   sample.headers,
+			Body:      sample.body,
+			Weight:    weight,
+		})
+	}
+	return requests, nil
+}
+
+// WriteHAR writes the load test requests as an HTTP archive (HAR 1.2), which
+// k6 and Gatling import as scenarios. Every request is repeated as many
+// times as its weight.
+func (r OpenAPI) WriteHAR(w io.Writer, opts LoadTestOptions) error {
+	requests, err := r.LoadTestRequests(opts)
+	if err != nil {
+		return err
+	}
+
+	type nameValue struct {
+		Name  string `json:"name"`
+		Value string `json:"value"`
+	}
+	entries := make([]interface{}, 0)
+	for _, request := range requests {
+		headers := make([]nameValue, 0, len(request.Headers))
+		for _, name := range sortedStrings(request.Headers) {
+			headers = append(headers, nameValue{name, request.Headers[name]})
+		}
+		query := make([]nameValue, 0)
+		if parsed, err := url.Parse(request.URL); err == nil {
+			values := parsed.Query()
+			for _, name := range sortedStrings(values) {
+				for _, value := range values[name] {
+					query = append(query, nameValue{name, value})
+				}
+			}
+		}
+		harRequest := map[string]interface{}{
+			"method":      request.Method,
+			"url":         request.URL,
+			"httpVersion": "HTTP/1.1",
+			"cookies":     []interface{}{},
+			"headers":     headers,
+			"queryString": query,
+			"headersSize": -1,
+			"bodySize":    0,
+		}
+		if request.Body != nil {
+			harRequest["postData"] = map[string]interface{}{
+				"mimeType": request.Headers["Content-Type"],
+				"text":     *request.Body,
+			}
+			harRequest["bodySize"] = len(*request.Body)
+		}
+		entry := map[string]interface{}{
+			"comment":         request.Operation,
+			"startedDateTime": "1970-01-01T00:00:00.000Z",
+			"time":            0,
+			"request":         harRequest,
+			"response": map[string]interface{}{
+				"status":      0,
+				"statusText":  "",
+				"httpVersion": "HTTP/1.1",
+				"cookies":     []interface{}{},
+				"headers":     []interface{}{},
+				"content":     map[string]interface{}{"size": 0, "mimeType": ""},
+				"redirectURL": "",
+				"headersSize": -1,
+				"bodySize":    -1,
+			},
+			"cache":   map[string]interface{}{},
+			"timings": map[string]interface{}{"send": 0, "wait": 0, "receive": 0},
+		}
+		for i := 0; i < request.Weight; i++ {
+			entries = append(entries, entry)
+		}
+	}
+
+	har := map[string]interface{}{
+		"log": map[string]interface{}{
+			"version": "1.2",
+			"creator": map[string]interface{}{"name": "oas", "version": "3"},
+			"comment": r.Info.Title + " " + r.Info.Version,
+			"entries": entries,
+		},
+	}
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return errors.WithStack(encoder.Encode(har))
+}
+
+// WriteVegetaTargets writes the load test requests as newline delimited
+// JSON targets of vegeta. Every request is repeated as many times as its
+// weight, since vegeta cycles through the targets in order.
+func (r OpenAPI) WriteVegetaTargets(w io.Writer, opts LoadTestOptions) error {
+	requests, err := r.LoadTestRequests(opts)
+	if err != nil {
+		return err
+	}
+	encoder := json.NewEncoder(w)
+	for _, request := range requests {
+		target := map[string]interface{}{
+			"method": request.Method,
+			"url":    request.URL,
+		}
+		if len(request.Headers) > 0 {
+			headers := map[string][]string{}
+			for name, value := range request.Headers {
+				headers[name] = []string{value}
+			}
+			target["header"] = headers
+		}
+		if request.Body != nil {
+			target["body"] = base64.StdEncoding.EncodeToString([]byte(*request.Body))
+		}
+		for i := 0; i < request.Weight; i++ {
+			if err := encoder.Encode(target); err != nil {
+				return errors.WithStack(err)
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,138 @@
+package oas
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+)
+
+type LoadTestSuite struct {
+	suite.Suite
+}
+
+// loadTestPatch is the patch of the petstore fixture giving examples to the
+// operations on the pets.
+const loadTestPatch = `
+paths:
+  /pets:
+    get:
+      parameters:
+        - name: limit
+          in: query
+          example: 20
+        - name: Authorization
+          in: header
+          example: Bearer example
+    post:
+      requestBody:
+        content:
+          application/json:
+            example:
+              name: Rex
+`
+
+func (r *LoadTestSuite) TestLoadTestRequests() {
+	body := `{"name":"Rex"}`
+	requests, err := petstore(r.T(), loadTestPatch).LoadTestRequests(LoadTestOptions{
+		BaseURL: "https://api.example.com/",
+		Headers: map[string]string{"Authorization": "Bearer token"},
+		Weights: map[string]int{"listPets": 8},
+	})
+	assert.Nil(r.T(), err)
+	assert.Equal(r.T(), []*LoadTestRequest{
+		{
+			Operation: "listPets",
+			Method:    "GET",
+			URL:       "https://api.example.com/pets?limit=20",
+			Headers:   map[string]string{"Authorization": "Bearer token"},
+			Weight:    8,
+		},
+		{
+			Operation: "createPet",
+			Method:    "POST",
+			URL:       "https://api.example.com/pets",
+			Headers:   map[string]string{"Authorization": "Bearer token", "Content-Type": "application/json"},
+			Body:      &body,
+			Weight:    1,
+		},
+	}, requests)
+
+	doc := petstore(r.T(), loadTestPatch, `
+paths:
+  /pets/{petId}:
+    parameters:
+      - name: petId
+        in: path
+        required: true
+        example: 7
+    delete:
+      operationId: null
+`)
+	requests, err = doc.LoadTestRequests(LoadTestOptions{
+		BaseURL: "http://localhost:8080",
+		Weights: map[string]int{"createPet": 0, "DELETE /pets/{petId}": 2},
+	})
+	assert.Nil(r.T(), err)
+	assert.Len(r.T(), requests, 3)
+	assert.Equal(r.T(), "getPet", requests[1].Operation)
+	assert.Equal(r.T(), "DELETE /pets/{petId}", requests[2].Operation)
+	assert.Equal(r.T(), "http://localhost:8080/pets/7", requests[2].URL)
+	assert.Equal(r.T(), 2, requests[2].Weight)
+
+	_, err = doc.LoadTestRequests(LoadTestOptions{})
+	assert.NotNil(r.T(), err)
+}
+
+func (r *LoadTestSuite) TestWriteHAR() {
+	buf := &bytes.Buffer{}
+	opts := LoadTestOptions{BaseURL: "https://api.example.com", Weights: map[string]int{"listPets": 3}}
+	assert.Nil(r.T(), petstore(r.T(), loadTestPatch).WriteHAR(buf, opts))
+
+	har := struct {
+		Log struct {
+			Version string `json:"version"`
+			Entries []struct {
+				Comment string `json:"comment"`
+				Request struct {
+					Method      string              `json:"method"`
+					URL         string              `json:"url"`
+					QueryString []map[string]string `json:"queryString"`
+					PostData    *struct {
+						MimeType string `json:"mimeType"`
+						Text     string `json:"text"`
+					} `json:"postData"`
+				} `json:"request"`
+			} `json:"entries"`
+		} `json:"log"`
+	}{}
+	assert.Nil(r.T(), json.Unmarshal(buf.Bytes(), &har))
+	assert.Equal(r.T(), "1.2", har.Log.Version)
+	assert.Len(r.T(), har.Log.Entries, 4)
+	assert.Equal(r.T(), "listPets", har.Log.Entries[2].Comment)
+	assert.Equal(r.T(), []map[string]string{{"name": "limit", "value": "20"}}, har.Log.Entries[0].Request.QueryString)
+	assert.Equal(r.T(), "POST", har.Log.Entries[3].Request.Method)
+	assert.Equal(r.T(), "application/json", har.Log.Entries[3].Request.PostData.MimeType)
+	assert.Equal(r.T(), `{"name":"Rex"}`, har.Log.Entries[3].Request.PostData.Text)
+}
+
+func (r *LoadTestSuite) TestWriteVegetaTargets() {
+	buf := &bytes.Buffer{}
+	opts := LoadTestOptions{BaseURL: "https://api.example.com", Weights: map[string]int{"listPets": 2}}
+	assert.Nil(r.T(), petstore(r.T(), loadTestPatch).WriteVegetaTargets(buf, opts))
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	assert.Equal(r.T(), []string{
+		`{"header":{"Authorization":["Bearer example"]},"method":"GET","url":"https://api.example.com/pets?limit=20"}`,
+		`{"header":{"Authorization":["Bearer example"]},"method":"GET","url":"https://api.example.com/pets?limit=20"}`,
+		`{"body":"eyJuYW1lIjoiUmV4In0=","header":{"Content-Type":["application/json"]},"method":"POST","url":"https://api.example.com/pets"}`,
+	}, lines)
+
+	assert.NotNil(r.T(), petstore(r.T(), loadTestPatch).WriteVegetaTargets(buf, LoadTestOptions{}))
+}
+
+func TestLoadTestSuite(t *testing.T) {
+	suite.Run(t, new(LoadTestSuite))
+}
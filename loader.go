@@ -0,0 +1,188 @@
+package oas
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// yamlErrorLine matches the line number of the errors of the YAML decoder.
+var yamlErrorLine = regexp.MustCompile(`line (\d+)`)
+
+// LoadError describes why a document could not be loaded.
+type LoadError struct {
+	// Location describes the file path or URL of the document, empty when
+	// read from a reader.
+	Location string
+
+	// Line describes the 1-based line of the error, 0 when unknown.
+	Line int
+
+	// Column describes the 1-based column of the error, 0 when unknown.
+	Column int
+
+	// Pointer describes the JSON Pointer of the value which could not be
+	// decoded, empty when the error does not concern a single value.
+	Pointer string
+
+	// Err describes the underlying error.
+	Err error
+}
+
+// Error returns the string representation of the error.
+func (e *LoadError) Error() string {
+	location := e.Location
+	if location == "" {
+		location = "<reader>"
+	}
+	switch {
+	case e.Column > 0:
+		location += fmt.Sprintf(":%d:%d", e.Line, e.Column)
+	case e.Line > 0:
+		location += fmt.Sprintf(":%d", e.Line)
+	}
+	return location + ": " + e.Err.Error()
+}
+
+// Cause returns the underlying error.
+func (e *LoadError) Cause() error {
+	return e.Err
+}
+
+// Loader reads documents from files, readers and URLs, detecting whether
+// they are written in JSON or YAML. The zero value accepts any version of
+// the specification.
+type Loader struct {
+	// Versions describes the accepted versions of the openapi field as
+	// prefixes (e.g. "3.0" accepts "3.0.0" and "3.0.3"). When empty, the
+	// field is not checked.
+	Versions []string
+
+	// RefLoader describes how LoadBundle reads the document and the files it
+	// references. Defaults to ReadLocation.
+	RefLoader RefLoader
+}
+
+// LoadFile reads the document from the file with the default loader.
+func LoadFile(path string) (*OpenAPI, error) {
+	return Loader{}.LoadFile(path)
+}
+
+// LoadReader reads the document from the reader with the default loader.
+func LoadReader(r io.Reader) (*OpenAPI, error) {
+	return Loader{}.LoadReader(r)
+}
+
+// LoadURL reads the document from the URL with the default loader.
+func LoadURL(ctx context.Context, url string) (*OpenAPI, error) {
+	return Loader{}.LoadURL(ctx, url)
+}
+
+// LoadFile reads the document from the file.
+func (r Loader) LoadFile(path string) (*OpenAPI, error) {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, &LoadError{Location: path, Err: errors.WithStack(err)}
+	}
+	return r.Load(path, data)
+}
+
+// LoadReader reads the document from the reader until EOF.
+func (r Loader) LoadReader(reader io.Reader) (*OpenAPI, error) {
+	data, err := ioutil.ReadAll(reader)
+	if err != nil {
+		return nil, &LoadError{Err: errors.WithStack(err)}
+	}
+	return r.Load("", data)
+}
+
+// LoadURL reads the document from the URL. Locations which are not URLs are
+// read as file paths.
+func (r Loader) LoadURL(ctx context.Context, url string) (*OpenAPI, error) {
+	data, err := ReadLocation(ctx, url)
+	if err != nil {
+		return nil, &LoadError{Location: url, Err: err}
+	}
+	return r.Load(url, data)
+}
+
+// Load decodes the document read from the location. Errors are returned as
+// *LoadError, holding the line and column of syntax errors and unsupported
+// versions when known. Values which cannot be decoded into the objects of
+// the specification (e.g. a sequence where a mapping is expected) are
+// reported with their pointer and position.
+func (r Loader) Load(location string, data []byte) (*OpenAPI, error) {
+	doc := &OpenAPI{}
+	if err := decodeDocument(data, doc); err != nil {
+		if verr, ok := errors.Cause(err).(*valueError); ok {
+			loadErr := &LoadError{Location: location, Pointer: verr.pointer, Err: err}
+			if provenance, err := ReadProvenance(location, data); err == nil {
+				if source, ok := provenance.Lookup(verr.pointer); ok {
+					loadErr.Line, loadErr.Column = source.Line, source.Column
+				}
+			}
+			return nil, loadErr
+		}
+		line, column := errorPosition(data, errors.Cause(err))
+		return nil, &LoadError{Location: location, Line: line, Column: column, Err: err}
+	}
+	if err := r.checkVersion(doc.OpenAPI); err != nil {
+		loadErr := &LoadError{Location: location, Err: err}
+		if provenance, err := ReadProvenance(location, data); err == nil {
+			if source, ok := provenance.Lookup("/openapi"); ok {
+				loadErr.Line, loadErr.Column = source.Line, source.Column
+			}
+		}
+		return nil, loadErr
+	}
+	return doc, nil
+}
+
+// checkVersion returns an error if the version is not accepted.
+func (r Loader) checkVersion(version string) error {
+	if len(r.Versions) == 0 {
+		return nil
+	}
+	if version == "" {
+		return errors.New("missing openapi version")
+	}
+	for _, prefix := range r.Versions {
+		if version == prefix || strings.HasPrefix(version, strings.TrimSuffix(prefix, ".")+".") {
+			return nil
+		}
+	}
+	return errors.Errorf("unsupported openapi version %q", version)
+}
+
+// errorPosition returns the line and column of a decoding error, zero when
+// unknown. JSON errors carry the offset in the data, YAML errors the line.
+func errorPosition(data []byte, err error) (int, int) {
+	offset := int64(-1)
+	switch err := err.(type) {
+	case *json.SyntaxError:
+		offset = err.Offset
+	case *json.UnmarshalTypeError:
+		offset = err.Offset
+	}
+	if offset >= 0 {
+		if offset > int64(len(data)) {
+			offset = int64(len(data))
+		}
+		prefix := data[:offset]
+		line := bytes.Count(prefix, []byte("\n")) + 1
+		return line, len(prefix) - bytes.LastIndexByte(prefix, '\n')
+	}
+	if match := yamlErrorLine.FindStringSubmatch(err.Error()); match != nil {
+		line, _ := strconv.Atoi(match[1])
+		return line, 0
+	}
+	return 0, 0
+}
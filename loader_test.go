@@ -0,0 +1,169 @@
+package oas
+
+import (
+	"context"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+)
+
+type LoaderSuite struct {
+	suite.Suite
+}
+
+func (r *LoaderSuite) TestLoad() {
+	testCases := []struct {
+		loader Loader
+		data   string
+		title  string
+		err    string
+	}{
+		{
+			Loader{},
+			`{"openapi": "3.0.0", "info": {"title": "JSON", "version": "1.0.0"}, "paths": {}}`,
+			"JSON",
+			"",
+		},
+		{
+			Loader{Versions: []string{"3.0"}},
+			"openapi: 3.0.3\ninfo:\n  title: YAML\n  version: 1.0.0\npaths: {}\n",
+			"YAML",
+			"",
+		},
+		{
+			Loader{Versions: []string{"3.0"}},
+			"openapi: 3.1.0\ninfo:\n  title: YAML\n  version: 1.0.0\npaths: {}\n",
+			"",
+			`spec.yaml:1:1: unsupported openapi version "3.1.0"`,
+		},
+		{
+			Loader{Versions: []string{"3.0", "3.1"}},
+			"info:\n  title: YAML\n  version: 1.0.0\npaths: {}\n",
+			"",
+			`spec.yaml:1:1: missing openapi version`,
+		},
+		{
+			Loader{},
+			"{\n  \"openapi\": \"3.0.0\",\n  \"info\" {}\n}",
+			"",
+			"spec.yaml:3:11: invalid character '{' after object key",
+		},
+		{
+			Loader{},
+			"openapi: 3.0.0\ninfo:\n  title: [\n",
+			"",
+			"spec.yaml:3: yaml: line 3: did not find expected node content",
+		},
+	}
+
+	failMsg := "test case %d failed"
+	for i, tc := range testCases {
+		doc, err := tc.loader.Load("spec.yaml", []byte(tc.data))
+		if tc.err != "" {
+			assert.Nil(r.T(), doc, failMsg, i)
+			assert.EqualError(r.T(), err, tc.err, failMsg, i)
+			_, ok := err.(*LoadError)
+			assert.True(r.T(), ok, failMsg, i)
+			continue
+		}
+		assert.Nil(r.T(), err, failMsg, i)
+		assert.Equal(r.T(), tc.title, doc.Info.Title, failMsg, i)
+	}
+}
+
+func (r *LoaderSuite) TestLoadNestedTypeErrors() {
+	testCases := []struct {
+		data    string
+		pointer string
+		line    int
+		column  int
+	}{
+		{
+			"openapi: 3.0.0\npaths:\n  /pets:\n    get:\n      parameters:\n        name: limit\n",
+			"/paths/~1pets/get/parameters",
+			5,
+			7,
+		},
+		{
+			"openapi: 3.0.0\ncomponents:\n  schemas:\n    Pet:\n      properties:\n        - id\n",
+			"/components/schemas/Pet/properties",
+			5,
+			7,
+		},
+		{
+			`{"openapi": "3.0.0", "info": []}`,
+			"/info",
+			1,
+			30,
+		},
+		{
+			"{\n  \"openapi\": \"3.0.0\",\n  \"servers\": [{\"url\": \"/\"}, \"api\"]\n}",
+			"/servers/1",
+			3,
+			29,
+		},
+	}
+
+	failMsg := "test case %d failed"
+	for i, tc := range testCases {
+		_, err := Loader{}.Load("spec", []byte(tc.data))
+		loadErr, ok := err.(*LoadError)
+		if !assert.True(r.T(), ok, failMsg, i) {
+			continue
+		}
+		assert.Equal(r.T(), tc.pointer, loadErr.Pointer, failMsg, i)
+		assert.Equal(r.T(), tc.line, loadErr.Line, failMsg, i)
+		assert.Equal(r.T(), tc.column, loadErr.Column, failMsg, i)
+		assert.Contains(r.T(), err.Error(), tc.pointer+": cannot unmarshal", failMsg, i)
+	}
+}
+
+func (r *LoaderSuite) TestSources() {
+	data := "openapi: 3.0.0\ninfo:\n  title: Petstore\n  version: 1.0.0\npaths: {}\n"
+
+	dir, err := ioutil.TempDir("", "loader")
+	assert.Nil(r.T(), err)
+	defer os.RemoveAll(dir)
+	file := filepath.Join(dir, "pets.yaml")
+	assert.Nil(r.T(), ioutil.WriteFile(file, []byte(data), 0644))
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		if req.URL.Path != "/pets.yaml" {
+			http.NotFound(w, req)
+			return
+		}
+		_, _ = w.Write([]byte(data))
+	}))
+	defer server.Close()
+
+	doc, err := LoadFile(file)
+	assert.Nil(r.T(), err)
+	assert.Equal(r.T(), "Petstore", doc.Info.Title)
+
+	doc, err = LoadReader(strings.NewReader(data))
+	assert.Nil(r.T(), err)
+	assert.Equal(r.T(), "Petstore", doc.Info.Title)
+
+	doc, err = LoadURL(context.Background(), server.URL+"/pets.yaml")
+	assert.Nil(r.T(), err)
+	assert.Equal(r.T(), "Petstore", doc.Info.Title)
+
+	_, err = LoadURL(context.Background(), server.URL+"/missing.yaml")
+	assert.EqualError(r.T(), err, server.URL+"/missing.yaml: unexpected status 404 Not Found")
+
+	_, err = LoadFile(filepath.Join(dir, "missing.yaml"))
+	assert.NotNil(r.T(), err)
+	assert.True(r.T(), os.IsNotExist(errors.Cause(err)))
+}
+
+func TestLoaderSuite(t *testing.T) {
+	suite.Run(t, new(LoaderSuite))
+}
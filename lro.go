@@ -0,0 +1,260 @@
+package oas
+
+import (
+	"github.com/pkg/errors"
+)
+
+// LongRunningExtension names the specification extension marking an
+// operation which accepts requests with 202 and completes them
+// asynchronously, clients polling a status operation until it finishes.
+const LongRunningExtension = "x-long-running"
+
+// Headers pointing clients at the status of a long-running operation.
+const (
+	LocationHeader          = "Location"
+	OperationLocationHeader = "Operation-Location"
+)
+
+// StatusLink names the link from the 202 response of a long-running
+// operation to its status operation.
+const StatusLink = "status"
+
+// Rules of AuditLongRunning.
+const (
+	RuleLROMissingLocation        = "lro-missing-location"
+	RuleLROMissingStatusLink      = "lro-missing-status-link"
+	RuleLROUnknownStatusOperation = "lro-unknown-status-operation"
+)
+
+// LongRunning describes how clients follow a long-running operation.
+type LongRunning struct {
+	// StatusOperationID describes the operationId of the GET operation
+	// reporting the status of the accepted request.
+	StatusOperationID string `json:"statusOperationId" yaml:"statusOperationId"`
+
+	// PollingHeader describes the header of the 202 response carrying the URL
+	// of the status, LocationHeader or OperationLocationHeader. Defaults to
+	// LocationHeader.
+	PollingHeader string `json:"pollingHeader,omitempty" yaml:"pollingHeader,omitempty"`
+}
+
+// validate checks that the long-running operation is well formed.
+func (r LongRunning) validate() error {
+	if r.StatusOperationID == "" {
+		return errors.New("missing statusOperationId")
+	}
+	switch r.PollingHeader {
+	case "", LocationHeader, OperationLocationHeader:
+	default:
+		return errors.Errorf("unknown polling header %q", r.PollingHeader)
+	}
+	return nil
+}
+
+// LongRunning returns the long-running operation declared by the
+// x-long-running extension, or nil if the extension is absent.
+func (r Extensions) LongRunning() (*LongRunning, error) {
+	lro := &LongRunning{}
+	ok, err := r.decode(LongRunningExtension, lro)
+	if !ok || err != nil {
+		return nil, err
+	}
+	if err := lro.validate(); err != nil {
+		return nil, errors.Wrap(err, LongRunningExtension)
+	}
+	if lro.PollingHeader == "" {
+		lro.PollingHeader = LocationHeader
+	}
+	return lro, nil
+}
+
+// SetLongRunning declares the long-running operation with the
+// x-long-running extension, or removes the extension when nil.
+func (r *Extensions) SetLongRunning(lro *LongRunning) {
+	if lro == nil {
+		delete(*r, LongRunningExtension)
+		return
+	}
+	value := map[string]interface{}{"statusOperationId": lro.StatusOperationID}
+	if lro.PollingHeader != "" {
+		value["pollingHeader"] = lro.PollingHeader
+	}
+	setExtension(r, LongRunningExtension, value)
+}
+
+// DeclareLongRunning declares the operation under the path and lowercase
+// method as long-running. Its 202 response, added if missing, documents the
+// polling header and links to the status operation, and the operation is
+// marked with the x-long-running extension.
+func (r *OpenAPI) DeclareLongRunning(path string, method string, lro *LongRunning) error {
+	if err := lro.validate(); err != nil {
+		return err
+	}
+	op, err := r.Paths.operation(path, method)
+	if err != nil {
+		return err
+	}
+	ptr := join("/paths", path, method)
+	if _, ok := r.operationByID(lro.StatusOperationID); !ok {
+		return errors.Errorf("%s: status operation %q not found", ptr, lro.StatusOperationID)
+	}
+	header := lro.PollingHeader
+	if header == "" {
+		header = LocationHeader
+	}
+
+	response := op.operation.Responses.Get("202")
+	if response != nil && response.Ref != "" {
+		return errors.Errorf("%s: the 202 response references a component", ptr)
+	}
+	if response == nil {
+		response = &Response{Description: "The request is accepted and completes asynchronously."}
+		op.operation.Responses.Set("202", response)
+	}
+	if !hasHeader(response.Headers, header) {
+		if response.Headers == nil {
+			response.Headers = map[string]*Header{}
+		}
+		response.Headers[header] = &Header{
+			Description: "URL of the status of the request.",
+			Required:    true,
+			Schema:      &Schema{Type: "string", Format: "uri"},
+		}
+	}
+	if response.Links == nil {
+		response.Links = map[string]*Link{}
+	}
+	response.Links[StatusLink] = &Link{
+		OperationID: lro.StatusOperationID,
+		Description: "Status of the request, polled at the URL of the " + header + " header.",
+	}
+
+	op.operation.Extensions.SetLongRunning(lro)
+	return nil
+}
+
+// operationByID returns the operation with the operationId.
+func (r OpenAPI) operationByID(id string) (pathOperation, bool) {
+	for _, op := range r.Paths.operations() {
+		if op.operation.OperationID == id {
+			return op, true
+		}
+	}
+	return pathOperation{}, false
+}
+
+// AuditLongRunning checks the 202 and status-monitor pattern. It flags 202
+// responses which document neither a Location nor an Operation-Location
+// header, long-running operations whose status operation is not a declared
+// GET operation and long-running operations whose 202 response does not
+// link to their status operation.
+func (r OpenAPI) AuditLongRunning() []*Finding {
+	findings := make([]*Finding, 0)
+	for _, op := range r.Paths.operations() {
+		ptr := join("/paths", op.path, op.method)
+		responsePtr, response := NewResolver(&r).response(join(ptr, "responses", "202"), op.operation.Responses.Get("202"))
+		if response != nil && !hasHeader(response.Headers, LocationHeader) && !hasHeader(response.Headers, OperationLocationHeader) {
+			findings = append(findings, &Finding{
+				Pointer:  join(responsePtr, "headers"),
+				Rule:     RuleLROMissingLocation,
+				Severity: SeverityWarning,
+				Message:  "202 response documents neither a Location nor an Operation-Location header",
+			})
+		}
+
+		lro, err := op.operation.Extensions.LongRunning()
+		if err != nil {
+			findings = append(findings, &Finding{
+				Pointer:  join(ptr, LongRunningExtension),
+				Rule:     RuleLROUnknownStatusOperation,
+				Severity: SeverityError,
+				Message:  err.Error(),
+			})
+			continue
+		}
+		if lro == nil {
+			continue
+		}
+		if status, ok := r.operationByID(lro.StatusOperationID); !ok || status.method != "get" {
+			findings = append(findings, &Finding{
+				Pointer:  join(ptr, LongRunningExtension),
+				Rule:     RuleLROUnknownStatusOperation,
+				Severity: SeverityError,
+				Message:  "status operation " + lro.StatusOperationID + " is not a declared GET operation",
+			})
+		}
+		if response == nil || !linksTo(response.Links, lro.StatusOperationID) {
+			findings = append(findings, &Finding{
+				Pointer:  join(ptr, "responses"),
+				Rule:     RuleLROMissingStatusLink,
+				Severity: SeverityWarning,
+				Message:  "202 response does not link to status operation " + lro.StatusOperationID,
+			})
+		}
+	}
+	sortFindings(findings)
+	return findings
+}
+
+// linksTo reports whether one of the links targets the operationId.
+func linksTo(links map[string]*Link, id string) bool {
+	for _, link := range links {
+		if link != nil && link.OperationID == id {
+			return true
+		}
+	}
+	return false
+}
+
+// LongRunningOperation describes what a client polling a long-running
+// operation needs to know.
+type LongRunningOperation struct {
+	// Path describes the path template of the operation.
+	Path string `json:"path" yaml:"path"`
+
+	// Method describes the lowercase HTTP method of the operation.
+	Method string `json:"method" yaml:"method"`
+
+	// OperationID describes the operationId of the operation.
+	OperationID string `json:"operationId,omitempty" yaml:"operationId,omitempty"`
+
+	// StatusPath describes the path template of the status operation.
+	StatusPath string `json:"statusPath" yaml:"statusPath"`
+
+	// StatusOperationID describes the operationId of the status operation.
+	StatusOperationID string `json:"statusOperationId" yaml:"statusOperationId"`
+
+	// PollingHeader describes the header of the 202 response carrying the URL
+	// to poll.
+	PollingHeader string `json:"pollingHeader" yaml:"pollingHeader"`
+}
+
+// LongRunningOperations returns the operations declaring the x-long-running
+// extension, ordered by path and method, with what client generators need
+// to emit polling code for them.
+func (r OpenAPI) LongRunningOperations() ([]*LongRunningOperation, error) {
+	operations := make([]*LongRunningOperation, 0)
+	for _, op := range r.Paths.operations() {
+		ptr := join("/paths", op.path, op.method)
+		lro, err := op.operation.Extensions.LongRunning()
+		if err != nil {
+			return nil, errors.Wrap(err, ptr)
+		}
+		if lro == nil {
+			continue
+		}
+		status, ok := r.operationByID(lro.StatusOperationID)
+		if !ok {
+			return nil, errors.Errorf("%s: status operation %q not found", ptr, lro.StatusOperationID)
+		}
+		operations = append(operations, &LongRunningOperation{
+			Path:              op.path,
+			Method:            op.method,
+			OperationID:       op.operation.OperationID,
+			StatusPath:        status.path,
+			StatusOperationID: lro.StatusOperationID,
+			PollingHeader:     lro.PollingHeader,
+		})
+	}
+	return operations, nil
+}
@@ -0,0 +1,136 @@
+package oas
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+)
+
+type LongRunningSuite struct {
+	suite.Suite
+}
+
+// lroPatch is the patch of the petstore fixture adding the operations
+// polling and cancelling long-running operations.
+const lroPatch = `
+paths:
+  /exports:
+    post:
+      operationId: createExport
+      responses:
+        "202":
+          description: Accepted.
+  /operations/{operationId}:
+    get:
+      operationId: getOperation
+      responses:
+        "200":
+          description: The status.
+    delete:
+      operationId: cancelOperation
+      responses:
+        "204":
+          description: Cancelled.
+`
+
+func (r *LongRunningSuite) TestDeclareLongRunning() {
+	doc := petstore(r.T(), lroPatch)
+	lro := &LongRunning{StatusOperationID: "getOperation", PollingHeader: OperationLocationHeader}
+	assert.Nil(r.T(), doc.DeclareLongRunning("/pets", "post", lro))
+
+	operation := doc.Paths.PathItems.Get("/pets").Post
+	response := operation.Responses.Get("202")
+	assert.NotNil(r.T(), response)
+	assert.NotNil(r.T(), response.Headers[OperationLocationHeader])
+	assert.Equal(r.T(), "getOperation", response.Links[StatusLink].OperationID)
+	declared, err := operation.Extensions.LongRunning()
+	assert.Nil(r.T(), err)
+	assert.Equal(r.T(), lro, declared)
+	assert.Len(r.T(), doc.AuditLongRunning(), 1)
+
+	operations, err := doc.LongRunningOperations()
+	assert.Nil(r.T(), err)
+	assert.Equal(r.T(), []*LongRunningOperation{{
+		Path:              "/pets",
+		Method:            "post",
+		OperationID:       "createPet",
+		StatusPath:        "/operations/{operationId}",
+		StatusOperationID: "getOperation",
+		PollingHeader:     OperationLocationHeader,
+	}}, operations)
+
+	assert.NotNil(r.T(), doc.DeclareLongRunning("/pets", "post", &LongRunning{StatusOperationID: "getStatus"}))
+	assert.NotNil(r.T(), doc.DeclareLongRunning("/pets", "put", lro))
+	assert.NotNil(r.T(), doc.DeclareLongRunning("/pets", "post", &LongRunning{}))
+}
+
+func (r *LongRunningSuite) TestAuditLongRunning() {
+	findings := petstore(r.T(), lroPatch, `
+paths:
+  /pets:
+    post:
+      x-long-running:
+        statusOperationId: cancelOperation
+  /operations/{operationId}:
+    get:
+      x-long-running: soon
+`).AuditLongRunning()
+	assert.Len(r.T(), findings, 4)
+	assert.Equal(r.T(), &Finding{
+		Pointer:  "/paths/~1exports/post/responses/202/headers",
+		Rule:     RuleLROMissingLocation,
+		Severity: SeverityWarning,
+		Message:  "202 response documents neither a Location nor an Operation-Location header",
+	}, findings[0])
+	assert.Equal(r.T(), "/paths/~1operations~1{operationId}/get/x-long-running", findings[1].Pointer)
+	assert.Equal(r.T(), RuleLROUnknownStatusOperation, findings[1].Rule)
+	assert.Equal(r.T(), &Finding{
+		Pointer:  "/paths/~1pets/post/responses",
+		Rule:     RuleLROMissingStatusLink,
+		Severity: SeverityWarning,
+		Message:  "202 response does not link to status operation cancelOperation",
+	}, findings[2])
+	assert.Equal(r.T(), &Finding{
+		Pointer:  "/paths/~1pets/post/x-long-running",
+		Rule:     RuleLROUnknownStatusOperation,
+		Severity: SeverityError,
+		Message:  "status operation cancelOperation is not a declared GET operation",
+	}, findings[3])
+}
+
+func (r *LongRunningSuite) TestLongRunning() {
+	testCases := []struct {
+		value    interface{}
+		expected *LongRunning
+		isErr    bool
+	}{
+		{nil, nil, false},
+		{
+			map[string]interface{}{"statusOperationId": "getOperation"},
+			&LongRunning{StatusOperationID: "getOperation", PollingHeader: LocationHeader},
+			false,
+		},
+		{map[string]interface{}{"pollingHeader": LocationHeader}, nil, true},
+		{map[string]interface{}{"statusOperationId": "getOperation", "pollingHeader": "Retry-After"}, nil, true},
+	}
+
+	for i, testCase := range testCases {
+		failMsg := "test case %d failed"
+		extensions := Extensions{}
+		if testCase.value != nil {
+			extensions[LongRunningExtension] = testCase.value
+		}
+		lro, err := extensions.LongRunning()
+		if testCase.isErr {
+			assert.NotNil(r.T(), err, failMsg, i)
+			continue
+		}
+		assert.Nil(r.T(), err, failMsg, i)
+		assert.Equal(r.T(), testCase.expected, lro, failMsg, i)
+	}
+}
+
+func TestLongRunningSuite(t *testing.T) {
+	suite.Run(t, new(LongRunningSuite))
+}
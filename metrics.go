@@ -0,0 +1,246 @@
+package oas
+
+import (
+	"fmt"
+	"net/http"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Metric names reported by the runtime subsystems.
+const (
+	// MetricRequests counts the routed requests by operation, method and
+	// status code.
+	MetricRequests = "oas_requests_total"
+
+	// MetricRequestDuration observes the handling time of routed requests in
+	// seconds by operation and method.
+	MetricRequestDuration = "oas_request_duration_seconds"
+
+	// MetricRoutingFailures counts the requests the router rejected by reason
+	// ("not_found", "method_not_allowed", "bad_request" or
+	// "payload_too_large").
+	MetricRoutingFailures = "oas_routing_failures_total"
+
+	// MetricUndeclaredResponses counts the responses a ResponseEnforcer found
+	// undeclared by operation and status code.
+	MetricUndeclaredResponses = "oas_undeclared_responses_total"
+)
+
+// DefaultBuckets describes the default upper bounds of histogram buckets in
+// seconds.
+var DefaultBuckets = []float64{.005, .01, .025, .05, .1, .25, .5, 1, 2.5, 5, 10}
+
+// Metrics receives the measurements of the runtime subsystems.
+type Metrics interface {
+	// IncCounter increments the named counter.
+	IncCounter(name string, labels map[string]string)
+
+	// Observe records the value in the named histogram.
+	Observe(name string, labels map[string]string, value float64)
+}
+
+// operationLabel returns the label identifying the operation of the route,
+// its operationId if set and the method and path template otherwise.
+func operationLabel(route *Route) string {
+	if route.Operation != nil && route.Operation.OperationID != "" {
+		return route.Operation.OperationID
+	}
+	return strings.ToUpper(route.Method) + " " + route.Path
+}
+
+// statusRecorder records the status code written through a response writer.
+type statusRecorder struct {
+	http.ResponseWriter
+	status int
+}
+
+// WriteHeader records the status code and sends the header.
+func (w *statusRecorder) WriteHeader(status int) {
+	if w.status == 0 {
+		w.status = status
+	}
+	w.ResponseWriter.WriteHeader(status)
+}
+
+// Write records an implicit 200 status code and writes the body.
+func (w *statusRecorder) Write(b []byte) (int, error) {
+	if w.status == 0 {
+		w.status = http.StatusOK
+	}
+	return w.ResponseWriter.Write(b)
+}
+
+// Flush implements http.Flusher if the wrapped writer does.
+func (w *statusRecorder) Flush() {
+	if flusher, ok := w.ResponseWriter.(http.Flusher); ok {
+		flusher.Flush()
+	}
+}
+
+// instrument calls next and reports the request to the metrics.
+func instrument(metrics Metrics, route *Route, w http.ResponseWriter, req *http.Request, next http.Handler) {
+	recorder := &statusRecorder{ResponseWriter: w}
+	start := time.Now()
+	next.ServeHTTP(recorder, req)
+	if recorder.status == 0 {
+		recorder.status = http.StatusOK
+	}
+
+	operation := operationLabel(route)
+	method := strings.ToUpper(route.Method)
+	metrics.IncCounter(MetricRequests, map[string]string{
+		"operation": operation,
+		"method":    method,
+		"status":    strconv.Itoa(recorder.status),
+	})
+	metrics.Observe(MetricRequestDuration, map[string]string{
+		"operation": operation,
+		"method":    method,
+	}, time.Since(start).Seconds())
+}
+
+// PrometheusMetrics collects metrics in memory and serves them in the
+// Prometheus text exposition format, so they can be scraped without the
+// Prometheus client library.
+type PrometheusMetrics struct {
+	// Buckets describes the upper bounds of the histogram buckets. When nil,
+	// DefaultBuckets are used.
+	Buckets []float64
+
+	mutex      sync.Mutex
+	counters   map[string]map[string]float64
+	histograms map[string]map[string]*histogram
+}
+
+// histogram holds the cumulative bucket counts of a histogram series.
+type histogram struct {
+	counts []uint64
+	count  uint64
+	sum    float64
+}
+
+// IncCounter increments the named counter.
+func (r *PrometheusMetrics) IncCounter(name string, labels map[string]string) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	if r.counters == nil {
+		r.counters = map[string]map[string]float64{}
+	}
+	if r.counters[name] == nil {
+		r.counters[name] = map[string]float64{}
+	}
+	r.counters[name][formatLabels(labels)]++
+}
+
+// Observe records the value in the named histogram.
+func (r *PrometheusMetrics) Observe(name string, labels map[string]string, value float64) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	buckets := r.buckets()
+	if r.histograms == nil {
+		r.histograms = map[string]map[string]*histogram{}
+	}
+	if r.histograms[name] == nil {
+		r.histograms[name] = map[string]*histogram{}
+	}
+	key := formatLabels(labels)
+	series := r.histograms[name][key]
+	if series == nil {
+		series = &histogram{counts: make([]uint64, len(buckets))}
+		r.histograms[name][key] = series
+	}
+	for i, bound := range buckets {
+		if value <= bound {
+			series.counts[i]++
+		}
+	}
+	series.count++
+	series.sum += value
+}
+
+// buckets returns the configured histogram buckets.
+func (r *PrometheusMetrics) buckets() []float64 {
+	if r.Buckets == nil {
+		return DefaultBuckets
+	}
+	return r.Buckets
+}
+
+// ServeHTTP writes the collected metrics in the Prometheus text exposition
+// format.
+func (r *PrometheusMetrics) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	w.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+	for _, name := range sortedStrings(r.counters) {
+		fmt.Fprintf(w, "# TYPE %s counter\n", name)
+		for _, labels := range sortedStrings(r.counters[name]) {
+			fmt.Fprintf(w, "%s%s %s\n", name, labels, formatFloat(r.counters[name][labels]))
+		}
+	}
+
+	buckets := r.buckets()
+	for _, name := range sortedStrings(r.histograms) {
+		fmt.Fprintf(w, "# TYPE %s histogram\n", name)
+		for _, labels := range sortedStrings(r.histograms[name]) {
+			series := r.histograms[name][labels]
+			for i, bound := range buckets {
+				fmt.Fprintf(w, "%s_bucket%s %d\n", name, withLabel(labels, "le", formatFloat(bound)), series.counts[i])
+			}
+			fmt.Fprintf(w, "%s_bucket%s %d\n", name, withLabel(labels, "le", "+Inf"), series.count)
+			fmt.Fprintf(w, "%s_sum%s %s\n", name, labels, formatFloat(series.sum))
+			fmt.Fprintf(w, "%s_count%s %d\n", name, labels, series.count)
+		}
+	}
+}
+
+// formatLabels formats the labels as a sorted Prometheus label set (e.g.
+// `{method="GET",operation="listPets"}`).
+func formatLabels(labels map[string]string) string {
+	if len(labels) == 0 {
+		return ""
+	}
+	names := make([]string, 0, len(labels))
+	for name := range labels {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	pairs := make([]string, 0, len(names))
+	for _, name := range names {
+		pairs = append(pairs, name+"="+strconv.Quote(labels[name]))
+	}
+	return "{" + strings.Join(pairs, ",") + "}"
+}
+
+// withLabel appends a label to a formatted label set.
+func withLabel(labels string, name string, value string) string {
+	pair := name + "=" + strconv.Quote(value)
+	if labels == "" {
+		return "{" + pair + "}"
+	}
+	return labels[:len(labels)-1] + "," + pair + "}"
+}
+
+// formatFloat formats a sample value.
+func formatFloat(value float64) string {
+	return strconv.FormatFloat(value, 'g', -1, 64)
+}
+
+// sortedStrings returns the keys of a map with string keys in sorted order.
+func sortedStrings(m interface{}) []string {
+	keys := make([]string, 0)
+	for _, key := range reflect.ValueOf(m).MapKeys() {
+		keys = append(keys, key.String())
+	}
+	sort.Strings(keys)
+	return keys
+}
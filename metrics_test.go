@@ -0,0 +1,73 @@
+package oas
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+)
+
+type MetricsSuite struct {
+	suite.Suite
+}
+
+func (r *MetricsSuite) TestRouterMetrics() {
+	doc := &OpenAPI{
+		OpenAPI: "3.0.0",
+		Info:    Info{Title: "Petstore", Version: "1.0.0"},
+		Paths: Paths{
+			PathItems: NewPathItems(map[string]*PathItem{
+				"/pets": {
+					Get: &Operation{
+						OperationID: "listPets",
+						Responses:   NewResponses(map[string]*Response{"200": {Description: "Pets."}}),
+					},
+				},
+				"/pets/{petId}": {
+					Delete: &Operation{},
+				},
+			}),
+		},
+	}
+
+	metrics := &PrometheusMetrics{Buckets: []float64{60}}
+	router := &Router{Doc: doc, Metrics: metrics}
+	enforcer := ResponseEnforcer{Doc: doc, Metrics: metrics, Report: func(*http.Request, error) {}}
+	handler := router.Middleware(enforcer.Middleware(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		if req.Method == http.MethodDelete {
+			w.WriteHeader(http.StatusNoContent)
+		}
+	})))
+
+	for _, target := range []string{"/pets", "/pets", "/owners"} {
+		handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, target, nil))
+	}
+	handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodPost, "/pets", nil))
+	handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodDelete, "/pets/7", nil))
+
+	recorder := httptest.NewRecorder()
+	metrics.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+	assert.Equal(r.T(), "text/plain; version=0.0.4; charset=utf-8", recorder.Header().Get("Content-Type"))
+	body := recorder.Body.String()
+	for _, line := range []string{
+		"# TYPE oas_requests_total counter\n",
+		`oas_requests_total{method="DELETE",operation="DELETE /pets/{petId}",status="204"} 1` + "\n",
+		`oas_requests_total{method="GET",operation="listPets",status="200"} 2` + "\n",
+		"# TYPE oas_routing_failures_total counter\n",
+		`oas_routing_failures_total{reason="method_not_allowed"} 1` + "\n",
+		`oas_routing_failures_total{reason="not_found"} 1` + "\n",
+		`oas_undeclared_responses_total{operation="DELETE /pets/{petId}",status="204"} 1` + "\n",
+		"# TYPE oas_request_duration_seconds histogram\n",
+		`oas_request_duration_seconds_bucket{method="GET",operation="listPets",le="60"} 2` + "\n",
+		`oas_request_duration_seconds_bucket{method="GET",operation="listPets",le="+Inf"} 2` + "\n",
+		`oas_request_duration_seconds_count{method="GET",operation="listPets"} 2` + "\n",
+	} {
+		assert.Contains(r.T(), body, line)
+	}
+}
+
+func TestMetricsSuite(t *testing.T) {
+	suite.Run(t, new(MetricsSuite))
+}
@@ -0,0 +1,213 @@
+package oas
+
+import (
+	"encoding/json"
+	"sort"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// MinifyOptions describes the parts of a document dropped by Minify.
+type MinifyOptions struct {
+	// Descriptions drops the documentation of the document: descriptions,
+	// summaries, schema titles and external documentation. The required
+	// descriptions of responses are emptied.
+	Descriptions bool
+
+	// Examples drops the examples of schemas, parameters, headers and media
+	// types, and the example components.
+	Examples bool
+
+	// Extensions drops the specification extensions other than those of
+	// KeepExtensions.
+	Extensions bool
+
+	// KeepExtensions describes the names of the extensions kept when
+	// dropping extensions (e.g. the x-ratelimit read by a gateway).
+	KeepExtensions []string
+}
+
+// Minify drops the parts of the document selected by the options, which
+// clients and servers do not need at runtime, to embed a slim document into
+// binaries or serve it from constrained edge runtimes.
+func (r *OpenAPI) Minify(opts MinifyOptions) {
+	keep := map[string]bool{}
+	for _, name := range opts.KeepExtensions {
+		keep[strings.ToLower(name)] = true
+	}
+	if opts.Extensions {
+		dropExtensions(r.Paths.Extensions, keep)
+	}
+	if opts.Examples && r.Components != nil {
+		r.Components.Examples = nil
+	}
+
+	_ = walk(r, func(ptr string, node interface{}) error {
+		if opts.Descriptions {
+			minifyDescriptions(node)
+		}
+		if opts.Examples {
+			minifyExamples(node)
+		}
+		if opts.Extensions {
+			if exts, ok := extensionsOf(node); ok {
+				dropExtensions(exts, keep)
+			}
+		}
+		return nil
+	})
+}
+
+// minifyDescriptions drops the documentation of the node.
+func minifyDescriptions(node interface{}) {
+	switch node := node.(type) {
+	case *OpenAPI:
+		node.ExternalDocs = nil
+	case *Info:
+		node.Description = ""
+	case *Server:
+		node.Description = ""
+	case *ServerVariable:
+		node.Description = ""
+	case *Tag:
+		node.Description = ""
+		node.ExternalDocs = nil
+	case *PathItem:
+		node.Summary = ""
+		node.Description = ""
+	case *Operation:
+		node.Summary = ""
+		node.Description = ""
+		node.ExternalDocs = nil
+	case *Parameter:
+		node.Description = ""
+	case *Header:
+		node.Description = ""
+	case *RequestBody:
+		node.Description = ""
+	case *Response:
+		node.Description = ""
+	case *Example:
+		node.Summary = ""
+		node.Description = ""
+	case *Link:
+		node.Description = ""
+	case *SecurityScheme:
+		node.Description = ""
+	case *Schema:
+		node.Title = ""
+		node.Description = ""
+		node.ExternalDocs = nil
+	}
+}
+
+// minifyExamples drops the examples of the node.
+func minifyExamples(node interface{}) {
+	switch node := node.(type) {
+	case *Parameter:
+		node.Example = nil
+		node.Examples = nil
+	case *Header:
+		node.Example = nil
+		node.Examples = nil
+	case *MediaType:
+		node.Example = nil
+		node.Examples = nil
+	case *Schema:
+		node.Example = nil
+	}
+}
+
+// dropExtensions deletes the extensions whose lowercase names are not kept.
+func dropExtensions(exts Extensions, keep map[string]bool) {
+	for key := range exts {
+		if !keep[strings.ToLower(key)] {
+			delete(exts, key)
+		}
+	}
+}
+
+// SectionSize describes the size of a section of a document.
+type SectionSize struct {
+	// Pointer describes the JSON Pointer of the section (e.g. "/paths" or
+	// "/components/schemas").
+	Pointer string
+
+	// Bytes describes the size of the compact JSON encoding of the section.
+	Bytes int
+
+	// Percent describes the share of the section in the size of the
+	// document.
+	Percent float64
+}
+
+// SizeReport describes how the size of a document is spread over its
+// sections.
+type SizeReport struct {
+	// Bytes describes the size of the compact JSON encoding of the document.
+	Bytes int
+
+	// Sections describes the top-level sections of the document and the
+	// sections of its components, largest first. Extensions of the document
+	// are reported together under "/x-".
+	Sections []*SectionSize
+}
+
+// SizeReport returns the size of the document by section, to find what to
+// minify.
+func (r OpenAPI) SizeReport() (*SizeReport, error) {
+	value, err := genericValue(r)
+	if err != nil {
+		return nil, err
+	}
+	obj, _ := value.(map[string]interface{})
+	total, err := jsonSize(obj)
+	if err != nil {
+		return nil, err
+	}
+
+	sizes := map[string]int{}
+	for key, item := range obj {
+		size, err := jsonSize(item)
+		if err != nil {
+			return nil, err
+		}
+		ptr := join("", key)
+		if strings.HasPrefix(strings.ToLower(key), "x-") {
+			ptr = "/x-"
+		}
+		sizes[ptr] += size
+		if components, ok := item.(map[string]interface{}); ok && key == "components" {
+			for name, section := range components {
+				size, err := jsonSize(section)
+				if err != nil {
+					return nil, err
+				}
+				sizes[join("/components", name)] = size
+			}
+		}
+	}
+
+	report := &SizeReport{Bytes: total, Sections: make([]*SectionSize, 0, len(sizes))}
+	for _, ptr := range sortedStrings(sizes) {
+		report.Sections = append(report.Sections, &SectionSize{
+			Pointer: ptr,
+			Bytes:   sizes[ptr],
+			Percent: 100 * float64(sizes[ptr]) / float64(total),
+		})
+	}
+	sort.SliceStable(report.Sections, func(i, j int) bool {
+		return report.Sections[i].Bytes > report.Sections[j].Bytes
+	})
+	return report, nil
+}
+
+// jsonSize returns the size of the compact JSON encoding of the value.
+func jsonSize(value interface{}) (int, error) {
+	rbytes, err := json.Marshal(value)
+	if err != nil {
+		return 0, errors.WithStack(err)
+	}
+	return len(rbytes), nil
+}
@@ -0,0 +1,187 @@
+package oas
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+)
+
+type MinifySuite struct {
+	suite.Suite
+}
+
+// minifyPatch is the patch of the petstore fixture documenting it with
+// descriptions, examples and extensions for the suite to strip.
+const minifyPatch = `
+info:
+  description: The petstore.
+paths:
+  /pets:
+    summary: Pets
+    get:
+      summary: List pets
+      externalDocs:
+        url: https://example.com/pets
+      parameters:
+        - name: limit
+          in: query
+          description: The limit.
+          example: 5
+          schema:
+            type: integer
+      responses:
+        "200":
+          content:
+            application/json:
+              example: []
+      x-ratelimit: 10
+      x-internal-owner: team
+  x-generated: true
+components:
+  schemas:
+    Pet:
+      title: Pet
+      description: A pet.
+      example:
+        name: Rex
+  examples:
+    Pets:
+      value: []
+tags:
+  - name: pets
+    description: Pets.
+x-logo: logo.png
+`
+
+func (r *MinifySuite) TestMinify() {
+	testCases := []struct {
+		opts     MinifyOptions
+		expected string
+	}{
+		{
+			MinifyOptions{Descriptions: true, Examples: true, Extensions: true, KeepExtensions: []string{"X-RateLimit"}},
+			`
+info:
+  description: null
+paths:
+  /pets:
+    summary: null
+    get:
+      summary: null
+      externalDocs: null
+      parameters:
+        - name: limit
+          in: query
+          schema:
+            type: integer
+      responses:
+        "200":
+          description: null
+          content:
+            application/json:
+              example: null
+      x-internal-owner: null
+    post:
+      responses:
+        "201":
+          description: null
+  /pets/{petId}:
+    get:
+      responses:
+        "200":
+          description: null
+    delete:
+      responses:
+        "204":
+          description: null
+  x-generated: null
+components:
+  schemas:
+    Pet:
+      title: null
+      description: null
+      example: null
+  examples: null
+tags:
+  - name: pets
+x-logo: null
+`,
+		},
+		{
+			MinifyOptions{Examples: true},
+			`
+paths:
+  /pets:
+    get:
+      parameters:
+        - name: limit
+          in: query
+          description: The limit.
+          schema:
+            type: integer
+      responses:
+        "200":
+          content:
+            application/json:
+              example: null
+components:
+  schemas:
+    Pet:
+      example: null
+  examples: null
+`,
+		},
+	}
+
+	for i, testCase := range testCases {
+		failMsg := "test case %d failed"
+		doc := petstore(r.T(), minifyPatch)
+		doc.Minify(testCase.opts)
+		actual, err := MarshalCanonicalYAML(doc)
+		assert.Nil(r.T(), err, failMsg, i)
+		expected, err := MarshalCanonicalYAML(petstore(r.T(), minifyPatch, testCase.expected))
+		assert.Nil(r.T(), err, failMsg, i)
+		assert.Equal(r.T(), string(expected), string(actual), failMsg, i)
+	}
+
+	reports, err := (&Pipeline{}).Add("minify", MinifyTransform(MinifyOptions{Extensions: true})).Run(petstore(r.T(), minifyPatch))
+	assert.Nil(r.T(), err)
+	assert.Equal(r.T(), []string{"/paths/x-generated", "/paths/~1pets/get/x-internal-owner", "/paths/~1pets/get/x-ratelimit", "/x-logo"}, reports[0].Changes)
+}
+
+func (r *MinifySuite) TestSizeReport() {
+	doc := petstore(r.T(), minifyPatch)
+	report, err := doc.SizeReport()
+	assert.Nil(r.T(), err)
+	total, err := jsonSize(doc)
+	assert.Nil(r.T(), err)
+	assert.Equal(r.T(), total, report.Bytes)
+
+	pointers := make([]string, 0)
+	sum := 0
+	for i, section := range report.Sections {
+		pointers = append(pointers, section.Pointer)
+		if i > 0 {
+			assert.True(r.T(), section.Bytes <= report.Sections[i-1].Bytes)
+		}
+		if len(splitPointer(section.Pointer)) == 1 {
+			sum += section.Bytes
+		}
+	}
+	assert.ElementsMatch(r.T(), []string{
+		"/openapi", "/info", "/paths", "/components", "/components/schemas",
+		"/components/examples", "/tags", "/x-",
+	}, pointers)
+	assert.Equal(r.T(), "/paths", report.Sections[0].Pointer)
+	assert.True(r.T(), sum < report.Bytes)
+
+	doc.Minify(MinifyOptions{Descriptions: true, Examples: true, Extensions: true})
+	minified, err := doc.SizeReport()
+	assert.Nil(r.T(), err)
+	assert.True(r.T(), minified.Bytes < report.Bytes)
+}
+
+func TestMinifySuite(t *testing.T) {
+	suite.Run(t, new(MinifySuite))
+}
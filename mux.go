@@ -0,0 +1,140 @@
+package oas
+
+import (
+	"net/http"
+	"sort"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// HandlerRegistration describes a handler registered with a router for a
+// method and a path template.
+type HandlerRegistration struct {
+	// Method describes the HTTP method of the handler, in any case.
+	Method string
+
+	// Path describes the path template of the handler (e.g.
+	// "/pets/{petId}"). The names of path parameters do not need to match
+	// those of the document.
+	Path string
+}
+
+// key returns the uppercase method and the path with the names of its
+// parameters removed, identifying the operation of the registration.
+func (r HandlerRegistration) key() string {
+	return strings.ToUpper(r.Method) + " " + templateParam.ReplaceAllString(r.Path, "{}")
+}
+
+// String returns the registration as uppercase method and path.
+func (r HandlerRegistration) String() string {
+	return strings.ToUpper(r.Method) + " " + r.Path
+}
+
+// CheckRegistrations checks that every operation of the document has a
+// handler among the registrations and every registration an operation.
+// Differences are returned as a *HandlerDriftError listing the operations
+// and registrations by uppercase method and path.
+func (r OpenAPI) CheckRegistrations(registrations []HandlerRegistration) error {
+	registered := make(map[string]HandlerRegistration, len(registrations))
+	for _, registration := range registrations {
+		registered[registration.key()] = registration
+	}
+
+	drift := &HandlerDriftError{}
+	declared := map[string]bool{}
+	for _, op := range r.Paths.operations() {
+		operation := HandlerRegistration{Method: op.method, Path: op.path}
+		declared[operation.key()] = true
+		if _, ok := registered[operation.key()]; !ok {
+			drift.Missing = append(drift.Missing, operation.String())
+		}
+	}
+	for key, registration := range registered {
+		if !declared[key] {
+			drift.Unknown = append(drift.Unknown, registration.String())
+		}
+	}
+	sort.Strings(drift.Unknown)
+	if len(drift.Missing) == 0 && len(drift.Unknown) == 0 {
+		return nil
+	}
+	return drift
+}
+
+// Mux dispatches the requests matched by a router to the handlers
+// registered for the method and path template of their operation.
+// Operations without a handler are answered with 501.
+type Mux struct {
+	// Router describes the router matching the requests against the
+	// document.
+	Router *Router
+
+	registrations []HandlerRegistration
+	handlers      map[string]http.Handler
+}
+
+// Handle registers the handler of the operation declared under the method
+// and path template. Registering the same operation twice replaces the
+// previous handler.
+func (r *Mux) Handle(method string, path string, handler http.Handler) {
+	registration := HandlerRegistration{Method: method, Path: path}
+	if r.handlers == nil {
+		r.handlers = map[string]http.Handler{}
+	}
+	if _, ok := r.handlers[registration.key()]; !ok {
+		r.registrations = append(r.registrations, registration)
+	}
+	r.handlers[registration.key()] = handler
+}
+
+// HandleFunc registers the handler function of the operation declared under
+// the method and path template.
+func (r *Mux) HandleFunc(method string, path string, handler func(http.ResponseWriter, *http.Request)) {
+	r.Handle(method, path, http.HandlerFunc(handler))
+}
+
+// Registrations returns the registrations of the handlers in the order they
+// were registered.
+func (r *Mux) Registrations() []HandlerRegistration {
+	return append([]HandlerRegistration{}, r.registrations...)
+}
+
+// Check checks that the handlers match the operations of the document of
+// the router, as CheckRegistrations.
+func (r *Mux) Check() error {
+	if r.Router == nil || r.Router.Doc == nil {
+		return errors.New("mux has no document to check the handlers against")
+	}
+	return r.Router.Doc.CheckRegistrations(r.registrations)
+}
+
+// Handler checks the handlers and returns the handler routing requests with
+// the router middleware and dispatching them to the registered handlers,
+// so serving a document with complete handlers is a single call in main.
+func (r *Mux) Handler() (http.Handler, error) {
+	if err := r.Check(); err != nil {
+		return nil, err
+	}
+	return r.Router.Middleware(r), nil
+}
+
+// ServeHTTP dispatches the request to the handler of its route. It must run
+// inside the router middleware, requests carrying no route are answered
+// with 404.
+func (r *Mux) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	route, ok := RouteFromContext(req.Context())
+	if !ok {
+		http.NotFound(w, req)
+		return
+	}
+	handler, ok := r.handlers[HandlerRegistration{Method: route.Method, Path: route.Path}.key()]
+	if !ok && req.Method == http.MethodHead {
+		handler, ok = r.handlers[HandlerRegistration{Method: http.MethodGet, Path: route.Path}.key()]
+	}
+	if !ok {
+		http.Error(w, http.StatusText(http.StatusNotImplemented), http.StatusNotImplemented)
+		return
+	}
+	handler.ServeHTTP(w, req)
+}
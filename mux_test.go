@@ -0,0 +1,109 @@
+package oas
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+)
+
+type MuxSuite struct {
+	suite.Suite
+}
+
+func (r *MuxSuite) TestCheckRegistrations() {
+	testCases := []struct {
+		registrations []HandlerRegistration
+		expected      error
+	}{
+		{
+			[]HandlerRegistration{
+				{Method: "GET", Path: "/pets"},
+				{Method: "post", Path: "/pets"},
+				{Method: "GET", Path: "/pets/{id}"},
+				{Method: "DELETE", Path: "/pets/{id}"},
+			},
+			nil,
+		},
+		{
+			[]HandlerRegistration{
+				{Method: "GET", Path: "/pets"},
+				{Method: "PUT", Path: "/pets/{id}"},
+				{Method: "GET", Path: "/owners"},
+			},
+			&HandlerDriftError{
+				Missing: []string{"POST /pets", "GET /pets/{petId}", "DELETE /pets/{petId}"},
+				Unknown: []string{"GET /owners", "PUT /pets/{id}"},
+			},
+		},
+	}
+
+	for i, testCase := range testCases {
+		failMsg := "test case %d failed"
+		err := petstore(r.T()).CheckRegistrations(testCase.registrations)
+		assert.Equal(r.T(), testCase.expected, err, failMsg, i)
+	}
+}
+
+func (r *MuxSuite) TestHandler() {
+	mux := &Mux{Router: &Router{Doc: petstore(r.T()), AutoHead: true}}
+	for _, registration := range []HandlerRegistration{
+		{Method: "GET", Path: "/pets"},
+		{Method: "GET", Path: "/pets/{id}"},
+		{Method: "DELETE", Path: "/pets/{id}"},
+	} {
+		registration := registration
+		mux.HandleFunc(registration.Method, registration.Path, func(w http.ResponseWriter, req *http.Request) {
+			route, _ := RouteFromContext(req.Context())
+			_, _ = w.Write([]byte(route.Operation.OperationID + " " + route.PathParams["petId"]))
+		})
+	}
+
+	_, err := mux.Handler()
+	assert.EqualError(r.T(), err, "operations without a handler: POST /pets")
+	assert.Equal(r.T(), []HandlerRegistration{
+		{Method: "GET", Path: "/pets"},
+		{Method: "GET", Path: "/pets/{id}"},
+		{Method: "DELETE", Path: "/pets/{id}"},
+	}, mux.Registrations())
+
+	mux.HandleFunc("POST", "/pets", func(w http.ResponseWriter, req *http.Request) {
+		w.WriteHeader(http.StatusCreated)
+	})
+	handler, err := mux.Handler()
+	if !assert.Nil(r.T(), err) {
+		return
+	}
+
+	testCases := []struct {
+		method string
+		target string
+		code   int
+		body   string
+	}{
+		{http.MethodGet, "/pets/7", http.StatusOK, "getPet 7"},
+		{http.MethodDelete, "/pets/7", http.StatusOK, "deletePet 7"},
+		{http.MethodHead, "/pets", http.StatusOK, "listPets "},
+		{http.MethodPost, "/pets", http.StatusCreated, ""},
+		{http.MethodGet, "/owners", http.StatusNotFound, "Not Found\n"},
+	}
+
+	for i, testCase := range testCases {
+		failMsg := "test case %d failed"
+		w := httptest.NewRecorder()
+		handler.ServeHTTP(w, httptest.NewRequest(testCase.method, testCase.target, nil))
+		assert.Equal(r.T(), testCase.code, w.Code, failMsg, i)
+		assert.Equal(r.T(), testCase.body, w.Body.String(), failMsg, i)
+	}
+
+	w := httptest.NewRecorder()
+	(&Mux{}).ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/pets", nil))
+	assert.Equal(r.T(), http.StatusNotFound, w.Code)
+	assert.NotNil(r.T(), (&Mux{}).Check())
+}
+
+func TestMuxSuite(t *testing.T) {
+	suite.Run(t, new(MuxSuite))
+}
@@ -0,0 +1,259 @@
+package oas
+
+import (
+	"strings"
+	"unicode"
+
+	"github.com/pkg/errors"
+)
+
+// NamingConvention describes a casing convention for identifiers.
+type NamingConvention string
+
+const (
+	// CamelCase joins words with the first word lowercased and every other
+	// word capitalized (e.g. "petName").
+	CamelCase NamingConvention = "camelCase"
+
+	// PascalCase joins capitalized words (e.g. "PetName").
+	PascalCase NamingConvention = "PascalCase"
+
+	// SnakeCase joins lowercased words with underscores (e.g. "pet_name").
+	SnakeCase NamingConvention = "snake_case"
+)
+
+// OriginalNameExtension is the specification extension used to record the
+// name an object had before it was rewritten by a naming transform.
+const OriginalNameExtension = "x-original-name"
+
+// Format returns the name rewritten in the naming convention. Names which do
+// not contain any letters or digits are returned unchanged.
+func (r NamingConvention) Format(name string) string {
+	words := splitWords(name)
+	if len(words) == 0 {
+		return name
+	}
+
+	switch r {
+	case CamelCase:
+		for i := range words {
+			if i == 0 {
+				words[i] = strings.ToLower(words[i])
+			} else {
+				words[i] = capitalize(words[i])
+			}
+		}
+		return strings.Join(words, "")
+	case PascalCase:
+		for i := range words {
+			words[i] = capitalize(words[i])
+		}
+		return strings.Join(words, "")
+	case SnakeCase:
+		for i := range words {
+			words[i] = strings.ToLower(words[i])
+		}
+		return strings.Join(words, "_")
+	default:
+		return name
+	}
+}
+
+// NamingOptions describes the naming conventions enforced by NormalizeNames.
+type NamingOptions struct {
+	// Schemas describes the convention applied to the names of the schemas
+	// declared under components. Empty value leaves the names untouched.
+	Schemas NamingConvention
+
+	// Properties describes the convention applied to the property names of
+	// every schema in the document. Empty value leaves the names untouched.
+	Properties NamingConvention
+
+	// PreserveOriginal records the previous name of every renamed schema or
+	// property under the x-original-name extension.
+	PreserveOriginal bool
+}
+
+// NormalizeNames rewrites component schema names and schema property names
+// according to the naming conventions. All references to renamed schemas,
+// required property lists and discriminators are updated accordingly. An
+// error is returned without modifying the document if two names collide
+// after conversion.
+func (r *OpenAPI) NormalizeNames(opts NamingOptions) error {
+	renames := map[string]string{}
+	if opts.Schemas != "" && r.Components != nil {
+		var err error
+		if renames, err = renameKeys(sortedKeys(r.Components.Schemas), opts.Schemas); err != nil {
+			return errors.Wrap(err, "components/schemas")
+		}
+	}
+
+	if opts.Properties != "" {
+		if err := walk(r, func(ptr string, node interface{}) error {
+			if schema, ok := node.(*Schema); ok && schema.Properties.Len() > 0 {
+				if _, err := renameKeys(sortedKeys(schema.Properties), opts.Properties); err != nil {
+					return errors.Wrap(err, ptr)
+				}
+			}
+			return nil
+		}); err != nil {
+			return err
+		}
+	}
+
+	if len(renames) > 0 {
+		schemas := make(map[string]*Schema, len(r.Components.Schemas))
+		for name, schema := range r.Components.Schemas {
+			if newName, ok := renames[name]; ok {
+				if opts.PreserveOriginal && schema != nil {
+					setExtension(&schema.Extensions, OriginalNameExtension, name)
+				}
+				name = newName
+			}
+			schemas[name] = schema
+		}
+		r.Components.Schemas = schemas
+	}
+
+	return walk(r, func(ptr string, node interface{}) error {
+		if ref := refOf(node); ref != nil {
+			*ref = renameSchemaRef(*ref, renames)
+		}
+
+		schema, ok := node.(*Schema)
+		if !ok {
+			return nil
+		}
+
+		if schema.Discriminator != nil {
+			for key, value := range schema.Discriminator.Mapping {
+				if newName, ok := renames[value]; ok {
+					schema.Discriminator.Mapping[key] = newName
+				} else {
+					schema.Discriminator.Mapping[key] = renameSchemaRef(value, renames)
+				}
+			}
+		}
+
+		if opts.Properties == "" {
+			return nil
+		}
+
+		if schema.Discriminator != nil {
+			schema.Discriminator.PropertyName = opts.Properties.Format(schema.Discriminator.PropertyName)
+		}
+
+		for i, name := range schema.Required {
+			schema.Required[i] = opts.Properties.Format(name)
+		}
+
+		if schema.Properties.Len() > 0 {
+			properties := Properties{}
+			for _, name := range schema.Properties.Keys() {
+				property := schema.Properties.Get(name)
+				newName := opts.Properties.Format(name)
+				if newName != name && opts.PreserveOriginal && property != nil {
+					setExtension(&property.Extensions, OriginalNameExtension, name)
+				}
+				properties.Set(newName, property)
+			}
+			schema.Properties = properties
+		}
+		return nil
+	})
+}
+
+// renameKeys computes the renames of the keys needed to comply with the
+// naming convention and reports collisions between the resulting names.
+func renameKeys(keys []string, convention NamingConvention) (map[string]string, error) {
+	renames := map[string]string{}
+	owners := map[string]string{}
+	for _, key := range keys {
+		newKey := convention.Format(key)
+		if owner, ok := owners[newKey]; ok {
+			return nil, errors.Errorf("names %q and %q both normalize to %q", owner, key, newKey)
+		}
+		owners[newKey] = key
+		if newKey != key {
+			renames[key] = newKey
+		}
+	}
+	return renames, nil
+}
+
+// renameSchemaRef rewrites a reference pointing into a renamed component
+// schema. References to other locations are returned unchanged.
+func renameSchemaRef(ref string, renames map[string]string) string {
+	const prefix = "#/components/schemas/"
+	if !strings.HasPrefix(ref, prefix) {
+		return ref
+	}
+
+	rest := ref[len(prefix):]
+	name, tail := rest, ""
+	if i := strings.Index(rest, "/"); i >= 0 {
+		name, tail = rest[:i], rest[i:]
+	}
+
+	if newName, ok := renames[unescapePointer(name)]; ok {
+		return prefix + escapePointer(newName) + tail
+	}
+	return ref
+}
+
+// setExtension assigns a specification extension, allocating the collection
+// when needed.
+func setExtension(exts *Extensions, key string, value interface{}) {
+	if *exts == nil {
+		*exts = Extensions{}
+	}
+	(*exts)[key] = value
+}
+
+// splitWords splits an identifier into words on separators, lower to upper
+// case transitions and at the end of acronyms (e.g. "HTTPServer").
+func splitWords(name string) []string {
+	runes := []rune(name)
+	words := make([]string, 0)
+	current := make([]rune, 0, len(runes))
+	flush := func() {
+		if len(current) > 0 {
+			words = append(words, string(current))
+			current = current[:0]
+		}
+	}
+
+	for i, c := range runes {
+		if !unicode.IsLetter(c) && !unicode.IsDigit(c) {
+			flush()
+			continue
+		}
+		if unicode.IsUpper(c) && len(current) > 0 {
+			prev := current[len(current)-1]
+			nextLower := i+1 < len(runes) && unicode.IsLower(runes[i+1])
+			if !unicode.IsUpper(prev) || nextLower {
+				flush()
+			}
+		}
+		current = append(current, c)
+	}
+	flush()
+	return words
+}
+
+// capitalize uppercases the first letter of the word and lowercases the rest.
+func capitalize(word string) string {
+	runes := []rune(strings.ToLower(word))
+	runes[0] = unicode.ToUpper(runes[0])
+	return string(runes)
+}
+
+// humanize converts an identifier into space separated capitalized words
+// (e.g. "pet_name" becomes "Pet Name").
+func humanize(name string) string {
+	words := splitWords(name)
+	for i := range words {
+		words[i] = capitalize(words[i])
+	}
+	return strings.Join(words, " ")
+}
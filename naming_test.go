@@ -0,0 +1,161 @@
+package oas
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+)
+
+type NamingSuite struct {
+	suite.Suite
+}
+
+func (r *NamingSuite) TestFormat() {
+	testCases := []struct {
+		convention NamingConvention
+		name       string
+		expected   string
+	}{
+		{CamelCase, "pet_name", "petName"},
+		{CamelCase, "PetName", "petName"},
+		{CamelCase, "HTTPServer", "httpServer"},
+		{PascalCase, "pet-owner", "PetOwner"},
+		{PascalCase, "userID", "UserId"},
+		{SnakeCase, "petName", "pet_name"},
+		{SnakeCase, "HTTPServerURL", "http_server_url"},
+		{SnakeCase, "v2Pets", "v2_pets"},
+		{SnakeCase, "--", "--"},
+	}
+
+	for i, testCase := range testCases {
+		failMsg := fmt.Sprintf("testCase: %d %v", i, testCase)
+		assert.Equal(r.T(), testCase.expected, testCase.convention.Format(testCase.name), failMsg)
+	}
+}
+
+func (r *NamingSuite) TestNormalizeNames() {
+	testCases := []struct {
+		shouldFail bool
+		opts       NamingOptions
+		input      *OpenAPI
+		expected   *OpenAPI
+	}{
+		{
+			false,
+			NamingOptions{Schemas: PascalCase, Properties: CamelCase, PreserveOriginal: true},
+			&OpenAPI{
+				Paths: Paths{
+					PathItems: NewPathItems(map[string]*PathItem{
+						"/pets": {
+							Get: &Operation{
+								Responses: NewResponses(map[string]*Response{
+									"200": {
+										Content: map[string]*MediaType{
+											"application/json": {
+												Schema: &Schema{Ref: "#/components/schemas/pet_entry"},
+											},
+										},
+									},
+								}),
+							},
+						},
+					}),
+				},
+				Components: &Components{
+					Schemas: map[string]*Schema{
+						"pet_entry": {
+							Type:     "object",
+							Required: []string{"pet_name"},
+							Discriminator: &Discriminator{
+								PropertyName: "pet_type",
+								Mapping:      map[string]string{"dog": "#/components/schemas/pet_entry"},
+							},
+							Properties: NewProperties(map[string]*Schema{
+								"pet_name": {Type: "string"},
+								"pet_type": {Type: "string"},
+							}),
+						},
+					},
+				},
+			},
+			&OpenAPI{
+				Paths: Paths{
+					PathItems: NewPathItems(map[string]*PathItem{
+						"/pets": {
+							Get: &Operation{
+								Responses: NewResponses(map[string]*Response{
+									"200": {
+										Content: map[string]*MediaType{
+											"application/json": {
+												Schema: &Schema{Ref: "#/components/schemas/PetEntry"},
+											},
+										},
+									},
+								}),
+							},
+						},
+					}),
+				},
+				Components: &Components{
+					Schemas: map[string]*Schema{
+						"PetEntry": {
+							Type:     "object",
+							Required: []string{"petName"},
+							Discriminator: &Discriminator{
+								PropertyName: "petType",
+								Mapping:      map[string]string{"dog": "#/components/schemas/PetEntry"},
+							},
+							Properties: NewProperties(map[string]*Schema{
+								"petName": {
+									Type:       "string",
+									Extensions: Extensions{OriginalNameExtension: "pet_name"},
+								},
+								"petType": {
+									Type:       "string",
+									Extensions: Extensions{OriginalNameExtension: "pet_type"},
+								},
+							}),
+							Extensions: Extensions{OriginalNameExtension: "pet_entry"},
+						},
+					},
+				},
+			},
+		},
+		{
+			true,
+			NamingOptions{Properties: SnakeCase},
+			&OpenAPI{
+				Components: &Components{
+					Schemas: map[string]*Schema{
+						"Pet": {
+							Properties: NewProperties(map[string]*Schema{
+								"petName":  {Type: "string"},
+								"pet_name": {Type: "string"},
+							}),
+						},
+					},
+				},
+			},
+			nil,
+		},
+	}
+
+	for i, testCase := range testCases {
+		failMsg := fmt.Sprintf("testCase: %d %v", i, testCase)
+
+		err := testCase.input.NormalizeNames(testCase.opts)
+		if (err != nil) != testCase.shouldFail {
+			assert.Fail(r.T(), failMsg, err)
+		}
+
+		if !testCase.shouldFail {
+			assert.EqualValues(r.T(), testCase.expected, testCase.input, failMsg)
+		}
+	}
+}
+
+func TestNamingSuite(t *testing.T) {
+	suite.Run(t, new(NamingSuite))
+}
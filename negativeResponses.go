@@ -0,0 +1,100 @@
+package oas
+
+// negativeResponse describes a standard negative response documented by
+// DocumentNegativeResponses.
+type negativeResponse struct {
+	code      string
+	component string
+	response  func() *Response
+	applies   func(r OpenAPI, operation *Operation) bool
+}
+
+// negativeResponses holds the standard negative responses in the order they
+// are documented.
+var negativeResponses = []negativeResponse{
+	{
+		code:      "405",
+		component: "MethodNotAllowed",
+		response: func() *Response {
+			return &Response{
+				Description: "Method Not Allowed. The path does not support the request method.",
+				Headers: map[string]*Header{
+					"Allow": {
+						Description: "Methods allowed on the path.",
+						Schema:      &Schema{Type: "string"},
+					},
+				},
+			}
+		},
+		applies: func(r OpenAPI, operation *Operation) bool {
+			return true
+		},
+	},
+	{
+		code:      "406",
+		component: "NotAcceptable",
+		response: func() *Response {
+			return &Response{
+				Description: "Not Acceptable. None of the media types in the Accept header can be produced.",
+			}
+		},
+		applies: func(r OpenAPI, operation *Operation) bool {
+			for _, code := range operation.Responses.Keys() {
+				if _, response := NewResolver(&r).response("", operation.Responses.Get(code)); response != nil && len(response.Content) > 0 {
+					return true
+				}
+			}
+			return false
+		},
+	},
+	{
+		code:      "415",
+		component: "UnsupportedMediaType",
+		response: func() *Response {
+			return &Response{
+				Description: "Unsupported Media Type. The request body media type is not accepted.",
+			}
+		},
+		applies: func(r OpenAPI, operation *Operation) bool {
+			return operation.RequestBody != nil
+		},
+	},
+}
+
+// DocumentNegativeResponses documents the responses frameworks answer with
+// on their own, keeping documents honest about them: 405 Method Not Allowed
+// on every operation, 406 Not Acceptable on operations producing content
+// and 415 Unsupported Media Type on operations accepting a request body. The
+// responses reference the "MethodNotAllowed", "NotAcceptable" and
+// "UnsupportedMediaType" component responses, which are added unless
+// components of the same name exist. Status codes an operation already
+// declares are left untouched. The pointers of the modified operations are
+// returned in sorted order.
+func (r *OpenAPI) DocumentNegativeResponses() []string {
+	modified := make([]string, 0)
+	for _, op := range r.Paths.operations() {
+		changed := false
+		for _, negative := range negativeResponses {
+			if _, ok := op.operation.Responses.Lookup(negative.code); ok || !negative.applies(*r, op.operation) {
+				continue
+			}
+
+			if r.Components == nil {
+				r.Components = &Components{}
+			}
+			if r.Components.Responses == nil {
+				r.Components.Responses = map[string]*Response{}
+			}
+			if r.Components.Responses[negative.component] == nil {
+				r.Components.Responses[negative.component] = negative.response()
+			}
+
+			op.operation.Responses.Set(negative.code, &Response{Ref: "#/components/responses/" + negative.component})
+			changed = true
+		}
+		if changed {
+			modified = append(modified, join("/paths", op.path, op.method))
+		}
+	}
+	return modified
+}
@@ -0,0 +1,63 @@
+package oas
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+)
+
+type NegativeResponsesSuite struct {
+	suite.Suite
+}
+
+func (r *NegativeResponsesSuite) TestDocumentNegativeResponses() {
+	doc := &OpenAPI{
+		OpenAPI: "3.0.0",
+		Info:    Info{Title: "Petstore", Version: "1.0.0"},
+		Paths: Paths{
+			PathItems: NewPathItems(map[string]*PathItem{
+				"/pets": {
+					Get: &Operation{
+						Responses: NewResponses(map[string]*Response{"200": {Ref: "#/components/responses/Pets"}}),
+					},
+					Post: &Operation{
+						RequestBody: &RequestBody{Content: map[string]*MediaType{"application/json": {}}},
+						Responses: NewResponses(map[string]*Response{
+							"201": {Description: "Created."},
+							"415": {Description: "Only JSON is accepted."},
+						}),
+					},
+				},
+			}),
+		},
+		Components: &Components{
+			Responses: map[string]*Response{
+				"Pets": {Description: "Pets.", Content: map[string]*MediaType{"application/json": {}}},
+			},
+		},
+	}
+
+	modified := doc.DocumentNegativeResponses()
+	assert.Equal(r.T(), []string{"/paths/~1pets/get", "/paths/~1pets/post"}, modified)
+	assert.Equal(r.T(), NewResponses(map[string]*Response{
+		"200": {Ref: "#/components/responses/Pets"},
+		"405": {Ref: "#/components/responses/MethodNotAllowed"},
+		"406": {Ref: "#/components/responses/NotAcceptable"},
+	}), doc.Paths.PathItems.Get("/pets").Get.Responses)
+	responses := NewResponses(map[string]*Response{
+		"201": {Description: "Created."},
+		"415": {Description: "Only JSON is accepted."},
+	})
+	responses.Set("405", &Response{Ref: "#/components/responses/MethodNotAllowed"})
+	assert.Equal(r.T(), responses, doc.Paths.PathItems.Get("/pets").Post.Responses)
+	assert.Contains(r.T(), doc.Components.Responses["MethodNotAllowed"].Headers, "Allow")
+	assert.Contains(r.T(), doc.Components.Responses, "NotAcceptable")
+	assert.NotContains(r.T(), doc.Components.Responses, "UnsupportedMediaType")
+
+	assert.Empty(r.T(), doc.DocumentNegativeResponses())
+}
+
+func TestNegativeResponsesSuite(t *testing.T) {
+	suite.Run(t, new(NegativeResponsesSuite))
+}
@@ -7,7 +7,7 @@ import (
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/suite"
-	"gopkg.in/yaml.v2"
+	"gopkg.in/yaml.v3"
 )
 
 type OpenAPISuite struct {
@@ -28,12 +28,12 @@ func (r *OpenAPISuite) TestOpenAPI() {
 					Version: "2.0.0",
 				},
 				Paths: Paths{
-					PathItems: PathItems{
+					PathItems: NewPathItems(map[string]*PathItem{
 						"/": {
 							Get: &Operation{
 								OperationID: "listVersionsv2",
 								Summary:     "List API versions",
-								Responses: map[string]*Response{
+								Responses: NewResponses(map[string]*Response{
 									"200": {
 										Description: "200 response",
 										Content: map[string]*MediaType{
@@ -83,14 +83,14 @@ func (r *OpenAPISuite) TestOpenAPI() {
 											},
 										},
 									},
-								},
+								}),
 							},
 						},
 						"/v2": {
 							Get: &Operation{
 								OperationID: "getVersionDetailsv2",
 								Summary:     "Show API version details",
-								Responses: map[string]*Response{
+								Responses: NewResponses(map[string]*Response{
 									"200": {
 										Description: "200 response",
 										Content: map[string]*MediaType{
@@ -184,10 +184,10 @@ func (r *OpenAPISuite) TestOpenAPI() {
 											},
 										},
 									},
-								},
+								}),
 							},
 						},
-					},
+					}),
 				},
 			},
 		},
@@ -200,7 +200,7 @@ func (r *OpenAPISuite) TestOpenAPI() {
 					Version: "1.0.0",
 				},
 				Paths: Paths{
-					PathItems: PathItems{
+					PathItems: NewPathItems(map[string]*PathItem{
 						"/streams": {
 							Post: &Operation{
 								Description: "subscribes a client to receive out-of-band data",
@@ -219,7 +219,7 @@ func (r *OpenAPISuite) TestOpenAPI() {
 										},
 									},
 								},
-								Responses: map[string]*Response{
+								Responses: NewResponses(map[string]*Response{
 									"201": {
 										Description: "subscription successfully created",
 										Content: map[string]*MediaType{
@@ -227,18 +227,18 @@ func (r *OpenAPISuite) TestOpenAPI() {
 												Schema: &Schema{
 													Description: "subscription information",
 													Required:    []string{"subscriptionId"},
-													Properties: map[string]*Schema{
+													Properties: NewProperties(map[string]*Schema{
 														"subscriptionId": {
 															Description: "this unique identifier allows management of the subscription",
 															Type:        "string",
 															Example:     "2531329f-fb09-4ef7-887e-84e648214436",
 														},
-													},
+													}),
 												},
 											},
 										},
 									},
-								},
+								}),
 								Callbacks: map[string]*Callback{
 									"onData": {
 										CallbackItems: CallbackItems{
@@ -249,7 +249,7 @@ func (r *OpenAPISuite) TestOpenAPI() {
 														Content: map[string]*MediaType{
 															"application/json": {
 																Schema: &Schema{
-																	Properties: map[string]*Schema{
+																	Properties: NewProperties(map[string]*Schema{
 																		"timestamp": {
 																			Type:   "string",
 																			Format: "date-time",
@@ -257,19 +257,19 @@ func (r *OpenAPISuite) TestOpenAPI() {
 																		"userData": {
 																			Type: "string",
 																		},
-																	},
+																	}),
 																},
 															},
 														},
 													},
-													Responses: map[string]*Response{
+													Responses: NewResponses(map[string]*Response{
 														"202": {
 															Description: "Your server implementation should return this HTTP status code\nif the data was received successfully\n",
 														},
 														"204": {
 															Description: "Your server should return this HTTP status code if no longer interested\nin further updates",
 														},
-													},
+													}),
 												},
 											},
 										},
@@ -277,7 +277,7 @@ func (r *OpenAPISuite) TestOpenAPI() {
 								},
 							},
 						},
-					},
+					}),
 				},
 			},
 		},
@@ -290,7 +290,7 @@ func (r *OpenAPISuite) TestOpenAPI() {
 					Version: "1.0.0",
 				},
 				Paths: Paths{
-					PathItems: PathItems{
+					PathItems: NewPathItems(map[string]*PathItem{
 						"/2.0/users/{username}": {
 							Get: &Operation{
 								OperationID: "getUserByName",
@@ -306,7 +306,7 @@ func (r *OpenAPISuite) TestOpenAPI() {
 										},
 									},
 								},
-								Responses: map[string]*Response{
+								Responses: NewResponses(map[string]*Response{
 									"200": {
 										Description: "The User",
 										Content: map[string]*MediaType{
@@ -322,7 +322,7 @@ func (r *OpenAPISuite) TestOpenAPI() {
 											},
 										},
 									},
-								},
+								}),
 							},
 						},
 						"/2.0/repositories/{username}": {
@@ -340,7 +340,7 @@ func (r *OpenAPISuite) TestOpenAPI() {
 										},
 									},
 								},
-								Responses: map[string]*Response{
+								Responses: NewResponses(map[string]*Response{
 									"200": {
 										Description: "repositories owned by the supplied user",
 										Content: map[string]*MediaType{
@@ -359,7 +359,7 @@ func (r *OpenAPISuite) TestOpenAPI() {
 											},
 										},
 									},
-								},
+								}),
 							},
 						},
 						"/2.0/repositories/{username}/{slug}": {
@@ -387,7 +387,7 @@ func (r *OpenAPISuite) TestOpenAPI() {
 										},
 									},
 								},
-								Responses: map[string]*Response{
+								Responses: NewResponses(map[string]*Response{
 									"200": {
 										Description: "The repository",
 										Content: map[string]*MediaType{
@@ -403,7 +403,7 @@ func (r *OpenAPISuite) TestOpenAPI() {
 											},
 										},
 									},
-								},
+								}),
 							},
 						},
 						"/2.0/repositories/{username}/{slug}/pullrequests": {
@@ -445,7 +445,7 @@ func (r *OpenAPISuite) TestOpenAPI() {
 										},
 									},
 								},
-								Responses: map[string]*Response{
+								Responses: NewResponses(map[string]*Response{
 									"200": {
 										Description: "an array of pull request objects",
 										Content: map[string]*MediaType{
@@ -459,7 +459,7 @@ func (r *OpenAPISuite) TestOpenAPI() {
 											},
 										},
 									},
-								},
+								}),
 							},
 						},
 						"/2.0/repositories/{username}/{slug}/pullrequests/{pid}": {
@@ -497,7 +497,7 @@ func (r *OpenAPISuite) TestOpenAPI() {
 										},
 									},
 								},
-								Responses: map[string]*Response{
+								Responses: NewResponses(map[string]*Response{
 									"200": {
 										Description: "a pull request object",
 										Content: map[string]*MediaType{
@@ -513,7 +513,7 @@ func (r *OpenAPISuite) TestOpenAPI() {
 											},
 										},
 									},
-								},
+								}),
 							},
 						},
 						"/2.0/repositories/{username}/{slug}/pullrequests/{pid}/merge": {
@@ -551,14 +551,14 @@ func (r *OpenAPISuite) TestOpenAPI() {
 										},
 									},
 								},
-								Responses: map[string]*Response{
+								Responses: NewResponses(map[string]*Response{
 									"204": {
 										Description: "the PR was successfully merged",
 									},
-								},
+								}),
 							},
 						},
-					},
+					}),
 				},
 				Components: &Components{
 					Links: map[string]*Link{
@@ -594,29 +594,29 @@ func (r *OpenAPISuite) TestOpenAPI() {
 					Schemas: map[string]*Schema{
 						"user": {
 							Type: "object",
-							Properties: map[string]*Schema{
+							Properties: NewProperties(map[string]*Schema{
 								"username": {
 									Type: "string",
 								},
 								"uuid": {
 									Type: "string",
 								},
-							},
+							}),
 						},
 						"repository": {
 							Type: "object",
-							Properties: map[string]*Schema{
+							Properties: NewProperties(map[string]*Schema{
 								"slug": {
 									Type: "string",
 								},
 								"owner": {
 									Ref: "#/components/schemas/user",
 								},
-							},
+							}),
 						},
 						"pullrequest": {
 							Type: "object",
-							Properties: map[string]*Schema{
+							Properties: NewProperties(map[string]*Schema{
 								"id": {
 									Type: "integer",
 								},
@@ -629,7 +629,7 @@ func (r *OpenAPISuite) TestOpenAPI() {
 								"author": {
 									Ref: "#/components/schemas/user",
 								},
-							},
+							}),
 						},
 					},
 				},
@@ -660,7 +660,7 @@ func (r *OpenAPISuite) TestOpenAPI() {
 					},
 				},
 				Paths: Paths{
-					PathItems: PathItems{
+					PathItems: NewPathItems(map[string]*PathItem{
 						"/pets": {
 							Get: &Operation{
 								Description: "Returns all pets from the system that the user has access to\nNam sed condimentum est. Maecenas tempor sagittis sapien, nec rhoncus sem sagittis sit amet. Aenean at gravida augue, ac iaculis sem. Curabitur odio lorem, ornare eget elementum nec, cursus id lectus. Duis mi turpis, pulvinar ac eros ac, tincidunt varius justo. In hac habitasse platea dictumst. Integer at adipiscing ante, a sagittis ligula. Aenean pharetra tempor ante molestie imperdiet. Vivamus id aliquam diam. Cras quis velit non tortor eleifend sagittis. Praesent at enim pharetra urna volutpat venenatis eget eget mauris. In eleifend fermentum facilisis. Praesent enim enim, gravida ac sodales sed, placerat id erat. Suspendisse lacus dolor, consectetur non augue vel, vehicula interdum libero. Morbi euismod sagittis libero sed lacinia.\n\nSed tempus felis lobortis leo pulvinar rutrum. Nam mattis velit nisl, eu condimentum ligula luctus nec. Phasellus semper velit eget aliquet faucibus. In a mattis elit. Phasellus vel urna viverra, condimentum lorem id, rhoncus nibh. Ut pellentesque posuere elementum. Sed a varius odio. Morbi rhoncus ligula libero, vel eleifend nunc tristique vitae. Fusce et sem dui. Aenean nec scelerisque tortor. Fusce malesuada accumsan magna vel tempus. Quisque mollis felis eu dolor tristique, sit amet auctor felis gravida. Sed libero lorem, molestie sed nisl in, accumsan tempor nisi. Fusce sollicitudin massa ut lacinia mattis. Sed vel eleifend lorem. Pellentesque vitae felis pretium, pulvinar elit eu, euismod sapien.\n",
@@ -694,7 +694,7 @@ func (r *OpenAPISuite) TestOpenAPI() {
 										},
 									},
 								},
-								Responses: map[string]*Response{
+								Responses: NewResponses(map[string]*Response{
 									"200": {
 										Description: "pet response",
 										Content: map[string]*MediaType{
@@ -718,7 +718,7 @@ func (r *OpenAPISuite) TestOpenAPI() {
 											},
 										},
 									},
-								},
+								}),
 							},
 							Post: &Operation{
 								Description: "Creates a new pet in the store.  Duplicates are allowed",
@@ -734,7 +734,7 @@ func (r *OpenAPISuite) TestOpenAPI() {
 										},
 									},
 								},
-								Responses: map[string]*Response{
+								Responses: NewResponses(map[string]*Response{
 									"200": {
 										Description: "pet response",
 										Content: map[string]*MediaType{
@@ -755,7 +755,7 @@ func (r *OpenAPISuite) TestOpenAPI() {
 											},
 										},
 									},
-								},
+								}),
 							},
 						},
 						"/pets/{id}": {
@@ -776,7 +776,7 @@ func (r *OpenAPISuite) TestOpenAPI() {
 										},
 									},
 								},
-								Responses: map[string]*Response{
+								Responses: NewResponses(map[string]*Response{
 									"200": {
 										Description: "pet response",
 										Content: map[string]*MediaType{
@@ -797,7 +797,7 @@ func (r *OpenAPISuite) TestOpenAPI() {
 											},
 										},
 									},
-								},
+								}),
 							},
 							Delete: &Operation{
 								Description: "deletes a single pet based on the ID supplied",
@@ -816,7 +816,7 @@ func (r *OpenAPISuite) TestOpenAPI() {
 										},
 									},
 								},
-								Responses: map[string]*Response{
+								Responses: NewResponses(map[string]*Response{
 									"204": {
 										Description: "pet deleted",
 									},
@@ -830,10 +830,10 @@ func (r *OpenAPISuite) TestOpenAPI() {
 											},
 										},
 									},
-								},
+								}),
 							},
 						},
-					},
+					}),
 				},
 				Components: &Components{
 					Schemas: map[string]*Schema{
@@ -844,29 +844,29 @@ func (r *OpenAPISuite) TestOpenAPI() {
 								},
 								{
 									Required: []string{"id"},
-									Properties: map[string]*Schema{
+									Properties: NewProperties(map[string]*Schema{
 										"id": {
 											Type:   "integer",
 											Format: "int64",
 										},
-									},
+									}),
 								},
 							},
 						},
 						"NewPet": {
 							Required: []string{"name"},
-							Properties: map[string]*Schema{
+							Properties: NewProperties(map[string]*Schema{
 								"name": {
 									Type: "string",
 								},
 								"tag": {
 									Type: "string",
 								},
-							},
+							}),
 						},
 						"Error": {
 							Required: []string{"code", "message"},
-							Properties: map[string]*Schema{
+							Properties: NewProperties(map[string]*Schema{
 								"code": {
 									Type:   "integer",
 									Format: "int32",
@@ -874,7 +874,7 @@ func (r *OpenAPISuite) TestOpenAPI() {
 								"message": {
 									Type: "string",
 								},
-							},
+							}),
 						},
 					},
 				},
@@ -897,7 +897,7 @@ func (r *OpenAPISuite) TestOpenAPI() {
 					},
 				},
 				Paths: Paths{
-					PathItems: PathItems{
+					PathItems: NewPathItems(map[string]*PathItem{
 						"/pets": {
 							Get: &Operation{
 								Summary:     "List all pets",
@@ -917,7 +917,7 @@ func (r *OpenAPISuite) TestOpenAPI() {
 										},
 									},
 								},
-								Responses: map[string]*Response{
+								Responses: NewResponses(map[string]*Response{
 									"200": {
 										Description: "A paged array of pets",
 										Headers: map[string]*Header{
@@ -946,13 +946,13 @@ func (r *OpenAPISuite) TestOpenAPI() {
 											},
 										},
 									},
-								},
+								}),
 							},
 							Post: &Operation{
 								Summary:     "Create a pet",
 								OperationID: "createPets",
 								Tags:        []string{"pets"},
-								Responses: map[string]*Response{
+								Responses: NewResponses(map[string]*Response{
 									"201": {
 										Description: "Null response",
 									},
@@ -966,7 +966,7 @@ func (r *OpenAPISuite) TestOpenAPI() {
 											},
 										},
 									},
-								},
+								}),
 							},
 						},
 						"/pets/{petId}": {
@@ -987,7 +987,7 @@ func (r *OpenAPISuite) TestOpenAPI() {
 										},
 									},
 								},
-								Responses: map[string]*Response{
+								Responses: NewResponses(map[string]*Response{
 									"200": {
 										Description: "Expected response to a valid request",
 										Content: map[string]*MediaType{
@@ -1008,16 +1008,16 @@ func (r *OpenAPISuite) TestOpenAPI() {
 											},
 										},
 									},
-								},
+								}),
 							},
 						},
-					},
+					}),
 				},
 				Components: &Components{
 					Schemas: map[string]*Schema{
 						"Pet": {
 							Required: []string{"id", "name"},
-							Properties: map[string]*Schema{
+							Properties: NewProperties(map[string]*Schema{
 								"id": {
 									Type:   "integer",
 									Format: "int64",
@@ -1028,7 +1028,7 @@ func (r *OpenAPISuite) TestOpenAPI() {
 								"tag": {
 									Type: "string",
 								},
-							},
+							}),
 						},
 						"Pets": {
 							Type: "array",
@@ -1038,7 +1038,7 @@ func (r *OpenAPISuite) TestOpenAPI() {
 						},
 						"Error": {
 							Required: []string{"code", "message"},
-							Properties: map[string]*Schema{
+							Properties: NewProperties(map[string]*Schema{
 								"code": {
 									Type:   "integer",
 									Format: "int32",
@@ -1046,7 +1046,7 @@ func (r *OpenAPISuite) TestOpenAPI() {
 								"message": {
 									Type: "string",
 								},
-							},
+							}),
 						},
 					},
 				},
@@ -1089,13 +1089,13 @@ func (r *OpenAPISuite) TestOpenAPI() {
 					},
 				},
 				Paths: Paths{
-					PathItems: PathItems{
+					PathItems: NewPathItems(map[string]*PathItem{
 						"/": {
 							Get: &Operation{
 								Tags:        []string{"metadata"},
 								OperationID: "list-data-sets",
 								Summary:     "List available data sets",
-								Responses: map[string]*Response{
+								Responses: NewResponses(map[string]*Response{
 									"200": {
 										Description: "Returns a list of data sets",
 										Content: map[string]*MediaType{
@@ -1123,7 +1123,7 @@ func (r *OpenAPISuite) TestOpenAPI() {
 											},
 										},
 									},
-								},
+								}),
 							},
 						},
 						"/{dataset}/{version}/fields": {
@@ -1158,7 +1158,7 @@ func (r *OpenAPISuite) TestOpenAPI() {
 										},
 									},
 								},
-								Responses: map[string]*Response{
+								Responses: NewResponses(map[string]*Response{
 									"200": {
 										Description: "The dataset API for the given version is found and it is accessible to consume.",
 										Content: map[string]*MediaType{
@@ -1179,7 +1179,7 @@ func (r *OpenAPISuite) TestOpenAPI() {
 											},
 										},
 									},
-								},
+								}),
 							},
 						},
 						"/{dataset}/{version}/records": {
@@ -1214,7 +1214,7 @@ func (r *OpenAPISuite) TestOpenAPI() {
 										},
 									},
 								},
-								Responses: map[string]*Response{
+								Responses: NewResponses(map[string]*Response{
 									"200": {
 										Description: "successful operation",
 										Content: map[string]*MediaType{
@@ -1234,13 +1234,13 @@ func (r *OpenAPISuite) TestOpenAPI() {
 									"404": {
 										Description: "No matching record found for the given criteria.",
 									},
-								},
+								}),
 								RequestBody: &RequestBody{
 									Content: map[string]*MediaType{
 										"application/x-www-form-urlencoded": {
 											Schema: &Schema{
 												Type: "object",
-												Properties: map[string]*Schema{
+												Properties: NewProperties(map[string]*Schema{
 													"criteria": {
 														Description: "Uses Lucene Query Syntax in the format of propertyName:value, propertyName:[num1 TO num2] and date range format: propertyName:[yyyyMMdd TO yyyyMMdd]. In the response please see the 'docs' element which has the list of record objects. Each record structure would consist of all the fields and their corresponding values.",
 														Type:        "string",
@@ -1256,7 +1256,7 @@ func (r *OpenAPISuite) TestOpenAPI() {
 														Type:        "integer",
 														Default:     100,
 													},
-												},
+												}),
 												Required: []string{"criteria"},
 											},
 										},
@@ -1264,13 +1264,13 @@ func (r *OpenAPISuite) TestOpenAPI() {
 								},
 							},
 						},
-					},
+					}),
 				},
 				Components: &Components{
 					Schemas: map[string]*Schema{
 						"dataSetList": {
 							Type: "object",
-							Properties: map[string]*Schema{
+							Properties: NewProperties(map[string]*Schema{
 								"total": {
 									Type: "integer",
 								},
@@ -1278,7 +1278,7 @@ func (r *OpenAPISuite) TestOpenAPI() {
 									Type: "array",
 									Items: &Schema{
 										Type: "object",
-										Properties: map[string]*Schema{
+										Properties: NewProperties(map[string]*Schema{
 											"apiKey": {
 												Type:        "string",
 												Description: "To be used as a dataset parameter value",
@@ -1297,10 +1297,10 @@ func (r *OpenAPISuite) TestOpenAPI() {
 												Format:      "uriref",
 												Description: "A URL to the API console for each API",
 											},
-										},
+										}),
 									},
 								},
-							},
+							}),
 						},
 					},
 				},
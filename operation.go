@@ -5,7 +5,7 @@ import (
 	"fmt"
 
 	"github.com/pkg/errors"
-	"gopkg.in/yaml.v2"
+	"gopkg.in/yaml.v3"
 )
 
 // Operation describes a single API operation on a path.
@@ -51,7 +51,7 @@ type Operation struct {
 
 	// Responses describes the list of possible responses as they are returned
 	// from executing this operation.
-	Responses map[string]*Response `json:"responses" yaml:"responses"`
+	Responses Responses `json:"responses" yaml:"responses"`
 
 	// Callback describes a map of possible out-of band callbacks related to
 	// the parent operation. The key is a unique identifier for the Callback
@@ -108,22 +108,7 @@ func (r Operation) MarshalJSON() ([]byte, error) {
 
 // UnmarshalJSON parses the JSON-encoded data and stores the result.
 func (r *Operation) UnmarshalJSON(data []byte) error {
-	return r.UnmarshalYAML(func(in interface{}) error {
-		obj := make(map[string]interface{})
-		if err := json.Unmarshal(data, &obj); err != nil {
-			return errors.WithStack(err)
-		}
-
-		rbytes, err := yaml.Marshal(obj)
-		if err != nil {
-			return errors.WithStack(err)
-		}
-
-		if err := yaml.Unmarshal(rbytes, in); err != nil {
-			return errors.WithStack(err)
-		}
-		return nil
-	})
+	return decodeJSON(data, r)
 }
 
 // MarshalYAML returns the YAML encoding.
@@ -168,7 +153,7 @@ func (r Operation) MarshalYAML() (interface{}, error) {
 		obj["deprecated"] = r.Deprecated
 	}
 
-	if len(r.Security) > 0 {
+	if r.Security != nil {
 		obj["security"] = r.Security
 	}
 
@@ -184,7 +169,13 @@ func (r Operation) MarshalYAML() (interface{}, error) {
 }
 
 // UnmarshalYAML parses the YAML-encoded data and stores the result.
-func (r *Operation) UnmarshalYAML(unmarshal func(interface{}) error) error {
+func (r *Operation) UnmarshalYAML(node *yaml.Node) error {
+	return decodeYAML(node, r)
+}
+
+// unmarshalValue stores the result of the unmarshal function, which decodes
+// either JSON or YAML data.
+func (r *Operation) unmarshalValue(d *valueDecoder, unmarshal func(interface{}) error) error {
 	obj := make(map[string]interface{})
 	if err := unmarshal(&obj); err != nil {
 		return errors.WithStack(err)
@@ -212,14 +203,10 @@ func (r *Operation) UnmarshalYAML(unmarshal func(interface{}) error) error {
 		}
 	}
 
-	if value, ok := obj["externalDocs"]; ok {
-		rbytes, err := yaml.Marshal(value)
-		if err != nil {
-			return errors.WithStack(err)
-		}
+	if raw, ok := obj["externalDocs"]; ok {
 		value := ExternalDocumentation{}
-		if err := yaml.Unmarshal(rbytes, &value); err != nil {
-			return errors.WithStack(err)
+		if err := d.decodeMember("externalDocs", raw, &value); err != nil {
+			return err
 		}
 		r.ExternalDocs = &value
 	}
@@ -230,50 +217,34 @@ func (r *Operation) UnmarshalYAML(unmarshal func(interface{}) error) error {
 		}
 	}
 
-	if value, ok := obj["parameters"]; ok {
-		rbytes, err := yaml.Marshal(value)
-		if err != nil {
-			return errors.WithStack(err)
-		}
+	if raw, ok := obj["parameters"]; ok {
 		value := make([]*Parameter, 0)
-		if err := yaml.Unmarshal(rbytes, &value); err != nil {
-			return errors.WithStack(err)
+		if err := d.decodeMember("parameters", raw, &value); err != nil {
+			return err
 		}
 		r.Parameters = value
 	}
 
-	if value, ok := obj["requestBody"]; ok {
-		rbytes, err := yaml.Marshal(value)
-		if err != nil {
-			return errors.WithStack(err)
-		}
+	if raw, ok := obj["requestBody"]; ok {
 		value := RequestBody{}
-		if err := yaml.Unmarshal(rbytes, &value); err != nil {
-			return errors.WithStack(err)
+		if err := d.decodeMember("requestBody", raw, &value); err != nil {
+			return err
 		}
 		r.RequestBody = &value
 	}
 
-	if value, ok := obj["responses"]; ok {
-		rbytes, err := yaml.Marshal(value)
-		if err != nil {
-			return errors.WithStack(err)
-		}
-		value := map[string]*Response{}
-		if err := yaml.Unmarshal(rbytes, &value); err != nil {
-			return errors.WithStack(err)
+	if raw, ok := obj["responses"]; ok {
+		value := Responses{}
+		if err := d.decodeMember("responses", raw, &value); err != nil {
+			return err
 		}
 		r.Responses = value
 	}
 
-	if value, ok := obj["callbacks"]; ok {
-		rbytes, err := yaml.Marshal(value)
-		if err != nil {
-			return errors.WithStack(err)
-		}
+	if raw, ok := obj["callbacks"]; ok {
 		value := map[string]*Callback{}
-		if err := yaml.Unmarshal(rbytes, &value); err != nil {
-			return errors.WithStack(err)
+		if err := d.decodeMember("callbacks", raw, &value); err != nil {
+			return err
 		}
 		r.Callbacks = value
 	}
@@ -284,26 +255,18 @@ func (r *Operation) UnmarshalYAML(unmarshal func(interface{}) error) error {
 		}
 	}
 
-	if value, ok := obj["security"]; ok {
-		rbytes, err := yaml.Marshal(value)
-		if err != nil {
-			return errors.WithStack(err)
-		}
+	if raw, ok := obj["security"]; ok {
 		value := make([]*SecurityRequirement, 0)
-		if err := yaml.Unmarshal(rbytes, &value); err != nil {
-			return errors.WithStack(err)
+		if err := d.decodeMember("security", raw, &value); err != nil {
+			return err
 		}
 		r.Security = value
 	}
 
-	if value, ok := obj["servers"]; ok {
-		rbytes, err := yaml.Marshal(value)
-		if err != nil {
-			return errors.WithStack(err)
-		}
+	if raw, ok := obj["servers"]; ok {
 		value := make([]*Server, 0)
-		if err := yaml.Unmarshal(rbytes, &value); err != nil {
-			return errors.WithStack(err)
+		if err := d.decodeMember("servers", raw, &value); err != nil {
+			return err
 		}
 		r.Servers = value
 	}
@@ -313,5 +276,9 @@ func (r *Operation) UnmarshalYAML(unmarshal func(interface{}) error) error {
 		return errors.WithStack(err)
 	}
 
+	if len(exts) > 0 {
+		r.Extensions = exts
+	}
+
 	return nil
 }
@@ -7,7 +7,7 @@ import (
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/suite"
-	"gopkg.in/yaml.v2"
+	"gopkg.in/yaml.v3"
 )
 
 type OperationSuite struct {
@@ -43,7 +43,7 @@ func (r *OperationSuite) TestOperation() {
 						"application/x-www-form-urlencoded": {
 							Schema: &Schema{
 								Type: "object",
-								Properties: map[string]*Schema{
+								Properties: NewProperties(map[string]*Schema{
 									"name": {
 										Description: "Updated name of the pet",
 										Type:        "string",
@@ -52,13 +52,13 @@ func (r *OperationSuite) TestOperation() {
 										Description: "Updated status of the pet",
 										Type:        "string",
 									},
-								},
+								}),
 								Required: []string{"status"},
 							},
 						},
 					},
 				},
-				Responses: map[string]*Response{
+				Responses: NewResponses(map[string]*Response{
 					"200": {
 						Description: "Pet updated.",
 						Content: map[string]*MediaType{
@@ -73,7 +73,7 @@ func (r *OperationSuite) TestOperation() {
 							"application/xml":  {},
 						},
 					},
-				},
+				}),
 				Security: []*SecurityRequirement{
 					{
 						"petstore_auth": {
@@ -84,6 +84,20 @@ func (r *OperationSuite) TestOperation() {
 				},
 			},
 		},
+		{
+			false,
+			&Operation{
+				OperationID: "getPet",
+				Responses: NewResponses(map[string]*Response{
+					"200": {
+						Description: "Pet found.",
+					},
+				}),
+				Extensions: Extensions{
+					"x-grpc-method": "GetPet",
+				},
+			},
+		},
 	}
 
 	for i, testCase := range testCases {
@@ -0,0 +1,110 @@
+package oas
+
+import "reflect"
+
+// orderedValues holds values by key in a slice of entries, in the order
+// their keys were first set, with the position of every key in an index. It
+// backs the ordered maps of the package, such as PathItems, which keep the
+// order of the keys of the documents they are read from.
+type orderedValues struct {
+	entries orderedMap
+	index   map[string]int
+}
+
+// newOrderedValues returns empty ordered values.
+func newOrderedValues() *orderedValues {
+	return &orderedValues{entries: orderedMap{}, index: map[string]int{}}
+}
+
+// len returns the number of values.
+func (r *orderedValues) len() int {
+	if r == nil {
+		return 0
+	}
+	return len(r.entries)
+}
+
+// keys returns the keys of the values in order.
+func (r *orderedValues) keys() []string {
+	keys := make([]string, 0, r.len())
+	if r == nil {
+		return keys
+	}
+	for _, entry := range r.entries {
+		keys = append(keys, entry.key)
+	}
+	return keys
+}
+
+// get returns the value of the key and reports whether the key is set.
+func (r *orderedValues) get(key string) (interface{}, bool) {
+	if r == nil {
+		return nil, false
+	}
+	i, ok := r.index[key]
+	if !ok {
+		return nil, false
+	}
+	return r.entries[i].value, true
+}
+
+// set sets the value of the key, appending the key when it is not set.
+func (r *orderedValues) set(key string, value interface{}) {
+	if i, ok := r.index[key]; ok {
+		r.entries[i].value = value
+		return
+	}
+	r.index[key] = len(r.entries)
+	r.entries = append(r.entries, orderedEntry{key: key, value: value})
+}
+
+// remove removes the key, moving the keys following it up.
+func (r *orderedValues) remove(key string) {
+	if r == nil {
+		return
+	}
+	i, ok := r.index[key]
+	if !ok {
+		return
+	}
+	r.entries = append(r.entries[:i], r.entries[i+1:]...)
+	delete(r.index, key)
+	for ; i < len(r.entries); i++ {
+		r.index[r.entries[i].key] = i
+	}
+}
+
+// copy returns a copy of the values sharing the values themselves.
+func (r *orderedValues) copy() *orderedValues {
+	if r == nil {
+		return nil
+	}
+	copied := &orderedValues{
+		entries: append(orderedMap{}, r.entries...),
+		index:   make(map[string]int, len(r.index)),
+	}
+	for key, i := range r.index {
+		copied.index[key] = i
+	}
+	return copied
+}
+
+// orderedValuer is implemented by pointers to the ordered maps of the
+// package, giving the code handling objects by reflection, such as Session
+// and Interner, access to the values backing them.
+type orderedValuer interface {
+	orderedValues() *orderedValues
+	setOrderedValues(values *orderedValues)
+}
+
+// orderedValuerType is the type of the ordered valuers.
+var orderedValuerType = reflect.TypeOf((*orderedValuer)(nil)).Elem()
+
+// orderedValuerOf returns the ordered valuer of the value, which must be an
+// addressable ordered map.
+func orderedValuerOf(value reflect.Value) (orderedValuer, bool) {
+	if !value.CanAddr() || !value.Addr().Type().Implements(orderedValuerType) {
+		return nil, false
+	}
+	return value.Addr().Interface().(orderedValuer), true
+}
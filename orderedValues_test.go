@@ -0,0 +1,115 @@
+package oas
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+	"gopkg.in/yaml.v3"
+)
+
+type OrderedValuesSuite struct {
+	suite.Suite
+}
+
+func (r *OrderedValuesSuite) TestSetAndDelete() {
+	properties := Properties{}
+	properties.Set("name", &Schema{Type: "string"})
+	properties.Set("id", &Schema{Type: "integer"})
+	properties.Set("age", &Schema{Type: "integer"})
+	properties.Set("name", &Schema{Type: "string", Format: "uuid"})
+	assert.Equal(r.T(), []string{"name", "id", "age"}, properties.Keys())
+	assert.Equal(r.T(), "uuid", properties.Get("name").Format)
+
+	properties.Delete("id")
+	properties.Delete("missing")
+	assert.Equal(r.T(), []string{"name", "age"}, properties.Keys())
+	assert.Equal(r.T(), "integer", properties.Get("age").Type)
+	_, ok := properties.Lookup("id")
+	assert.False(r.T(), ok)
+
+	empty := Properties{}
+	empty.Delete("name")
+	assert.Equal(r.T(), 0, empty.Len())
+	assert.Nil(r.T(), empty.Get("name"))
+	assert.Equal(r.T(), []string{"b", "c"}, NewResponses(map[string]*Response{"c": {}, "b": {}}).Keys())
+}
+
+func (r *OrderedValuesSuite) TestDeclarationOrder() {
+	data := []byte(`openapi: 3.0.0
+info:
+  title: Petstore
+  version: 1.0.0
+paths:
+  /pets:
+    post:
+      responses:
+        "201":
+          description: Created.
+        default:
+          description: Unexpected error.
+        "400":
+          description: Invalid pet.
+  /owners:
+    get:
+      responses:
+        "200":
+          description: Owners.
+  /a:
+    get:
+      responses:
+        "200":
+          description: A.
+components:
+  schemas:
+    Pet:
+      type: object
+      properties:
+        name:
+          type: string
+        id:
+          type: integer
+        age:
+          type: integer
+`)
+
+	ordered := func(doc *OpenAPI) {
+		assert.Equal(r.T(), []string{"/pets", "/owners", "/a"}, doc.Paths.PathItems.Keys())
+		responses := doc.Paths.PathItems.Get("/pets").Post.Responses
+		assert.Equal(r.T(), []string{"201", "default", "400"}, responses.Keys())
+		properties := doc.Components.Schemas["Pet"].Properties
+		assert.Equal(r.T(), []string{"name", "id", "age"}, properties.Keys())
+	}
+	before := func(data []byte, keys ...string) {
+		text := string(data)
+		for i := 1; i < len(keys); i++ {
+			assert.True(r.T(), strings.Index(text, keys[i-1]) < strings.Index(text, keys[i]), "%s before %s", keys[i-1], keys[i])
+		}
+	}
+
+	doc := &OpenAPI{}
+	assert.Nil(r.T(), yaml.Unmarshal(data, doc))
+	ordered(doc)
+
+	rbytesYAML, err := yaml.Marshal(doc)
+	assert.Nil(r.T(), err)
+	before(rbytesYAML, "/pets:", "/owners:", "/a:")
+	before(rbytesYAML, `"201":`, "default:", `"400":`)
+	before(rbytesYAML, "name:", "id:", "age:")
+
+	rbytesJSON, err := json.Marshal(doc)
+	assert.Nil(r.T(), err)
+	before(rbytesJSON, `"/pets"`, `"/owners"`, `"/a"`)
+	before(rbytesJSON, `"201"`, `"default"`, `"400"`)
+	before(rbytesJSON, `"name"`, `"id"`, `"age"`)
+
+	decoded := &OpenAPI{}
+	assert.Nil(r.T(), json.Unmarshal(rbytesJSON, decoded))
+	ordered(decoded)
+}
+
+func TestOrderedValuesSuite(t *testing.T) {
+	suite.Run(t, new(OrderedValuesSuite))
+}
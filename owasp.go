@@ -0,0 +1,157 @@
+package oas
+
+import (
+	"strings"
+)
+
+// Rules reported by AuditOWASP, named after the OWASP API Security Top 10
+// (2019) categories they map to.
+const (
+	RuleExcessiveDataExposure    = "owasp-api3-excessive-data-exposure"
+	RuleLackOfRateLimiting       = "owasp-api4-lack-of-rate-limiting"
+	RuleMassAssignment           = "owasp-api6-mass-assignment"
+	RuleSecurityMisconfiguration = "owasp-api7-security-misconfiguration"
+)
+
+// sensitiveProperties holds the normalized names of properties which should
+// not be returned to clients.
+var sensitiveProperties = map[string]bool{
+	"password":             true,
+	"passwd":               true,
+	"passwordhash":         true,
+	"secret":               true,
+	"clientsecret":         true,
+	"privatekey":           true,
+	"apikey":               true,
+	"ssn":                  true,
+	"socialsecuritynumber": true,
+	"creditcard":           true,
+	"creditcardnumber":     true,
+	"cardnumber":           true,
+	"cvv":                  true,
+	"pin":                  true,
+}
+
+// privilegedProperties holds the normalized names of properties granting
+// privileges which clients should not be able to assign.
+var privilegedProperties = map[string]bool{
+	"role":        true,
+	"roles":       true,
+	"admin":       true,
+	"isadmin":     true,
+	"permissions": true,
+	"privileges":  true,
+	"scopes":      true,
+	"verified":    true,
+	"isverified":  true,
+}
+
+// AuditOWASP runs heuristic checks mapped to the OWASP API Security Top 10
+// categories as a first-pass scan of a document. It flags response schemas
+// exposing sensitive fields (e.g. password, ssn), operations which do not
+// document a 429 response, writable privileged fields in request schemas
+// (e.g. role, isAdmin) and servers reachable over plain HTTP. Schemas
+// referenced from multiple locations are reported once at the component.
+func (r OpenAPI) AuditOWASP() []*Finding {
+	findings := make([]*Finding, 0)
+	responseVisited := map[string]bool{}
+	requestVisited := map[string]bool{}
+
+	findings = append(findings, auditServers("", r.Servers)...)
+	for _, path := range sortedKeys(r.Paths.PathItems) {
+		if item := r.Paths.PathItems.Get(path); item != nil {
+			findings = append(findings, auditServers(join("/paths", path), item.Servers)...)
+		}
+	}
+
+	for _, op := range r.Paths.operations() {
+		ptr := join("/paths", op.path, op.method)
+		findings = append(findings, auditServers(ptr, op.operation.Servers)...)
+
+		bodyPtr, body := NewResolver(&r).requestBody(join(ptr, "requestBody"), op.operation.RequestBody)
+		if body != nil {
+			for _, mediaType := range sortedKeys(body.Content) {
+				if body.Content[mediaType] == nil {
+					continue
+				}
+				schemaPtr := join(bodyPtr, "content", mediaType, "schema")
+				walkSchemas(r.Components, schemaPtr, body.Content[mediaType].Schema, requestVisited, func(ptr string, schema *Schema) {
+					for _, name := range sortedKeys(schema.Properties) {
+						property := schema.Properties.Get(name)
+						if privilegedProperties[normalizedName(name)] && (property == nil || !property.ReadOnly) {
+							findings = append(findings, &Finding{
+								Pointer:  join(ptr, "properties", name),
+								Rule:     RuleMassAssignment,
+								Severity: SeverityWarning,
+								Message:  "privileged property " + name + " can be assigned by clients; mark it readOnly or remove it from the request",
+							})
+						}
+					}
+				})
+			}
+		}
+
+		for _, code := range sortedKeys(op.operation.Responses) {
+			responsePtr, response := NewResolver(&r).response(join(ptr, "responses", code), op.operation.Responses.Get(code))
+			if response == nil {
+				continue
+			}
+			for _, mediaType := range sortedKeys(response.Content) {
+				if response.Content[mediaType] == nil {
+					continue
+				}
+				schemaPtr := join(responsePtr, "content", mediaType, "schema")
+				walkSchemas(r.Components, schemaPtr, response.Content[mediaType].Schema, responseVisited, func(ptr string, schema *Schema) {
+					for _, name := range sortedKeys(schema.Properties) {
+						property := schema.Properties.Get(name)
+						if sensitiveProperties[normalizedName(name)] && (property == nil || !property.WriteOnly) {
+							findings = append(findings, &Finding{
+								Pointer:  join(ptr, "properties", name),
+								Rule:     RuleExcessiveDataExposure,
+								Severity: SeverityWarning,
+								Message:  "sensitive property " + name + " is returned to clients",
+							})
+						}
+					}
+				})
+			}
+		}
+
+		_, limited := op.operation.Responses.Lookup("429")
+		_, clientError := op.operation.Responses.Lookup("4XX")
+		if !limited && !clientError {
+			findings = append(findings, &Finding{
+				Pointer:  join(ptr, "responses"),
+				Rule:     RuleLackOfRateLimiting,
+				Severity: SeverityInfo,
+				Message:  "operation does not document a 429 response",
+			})
+		}
+	}
+
+	sortFindings(findings)
+	return findings
+}
+
+// auditServers flags the servers declared by the object at the pointer which
+// are reachable over plain HTTP.
+func auditServers(ptr string, servers []*Server) []*Finding {
+	findings := make([]*Finding, 0)
+	for i, server := range servers {
+		if server != nil && strings.HasPrefix(strings.ToLower(server.URL), "http://") {
+			findings = append(findings, &Finding{
+				Pointer:  index(ptr, "servers", i),
+				Rule:     RuleSecurityMisconfiguration,
+				Severity: SeverityWarning,
+				Message:  "server " + server.URL + " is reachable over plain HTTP",
+			})
+		}
+	}
+	return findings
+}
+
+// normalizedName returns the lowercase concatenated words of an identifier so
+// that "isAdmin", "is_admin" and "IsAdmin" compare equal.
+func normalizedName(name string) string {
+	return strings.ToLower(strings.Join(splitWords(name), ""))
+}
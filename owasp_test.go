@@ -0,0 +1,113 @@
+package oas
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+)
+
+type OWASPSuite struct {
+	suite.Suite
+}
+
+func (r *OWASPSuite) TestAuditOWASP() {
+	doc := OpenAPI{
+		OpenAPI: "3.0.0",
+		Info:    Info{Title: "Petstore", Version: "1.0.0"},
+		Servers: []*Server{{URL: "https://api.example.com"}, {URL: "http://api.example.com"}},
+		Paths: Paths{
+			PathItems: NewPathItems(map[string]*PathItem{
+				"/users": {
+					Get: &Operation{
+						Responses: NewResponses(map[string]*Response{
+							"200": {Ref: "#/components/responses/Users"},
+							"429": {Description: "Too many requests."},
+						}),
+					},
+					Post: &Operation{
+						RequestBody: &RequestBody{
+							Content: map[string]*MediaType{
+								"application/json": {Schema: &Schema{Ref: "#/components/schemas/User"}},
+							},
+						},
+						Responses: NewResponses(map[string]*Response{
+							"201": {
+								Description: "Created.",
+								Content: map[string]*MediaType{
+									"application/json": {Schema: &Schema{Ref: "#/components/schemas/User"}},
+								},
+							},
+							"4XX": {Description: "Client error."},
+						}),
+					},
+				},
+			}),
+		},
+		Components: &Components{
+			Responses: map[string]*Response{
+				"Users": {
+					Description: "Users.",
+					Content: map[string]*MediaType{
+						"application/json": {
+							Schema: &Schema{Type: "array", Items: &Schema{Ref: "#/components/schemas/User"}},
+						},
+					},
+				},
+			},
+			Schemas: map[string]*Schema{
+				"User": {
+					Type: "object",
+					Properties: NewProperties(map[string]*Schema{
+						"name":     {Type: "string"},
+						"password": {Type: "string"},
+						"pin_code": {Type: "string", WriteOnly: true},
+						"SSN":      {Type: "string"},
+						"is_admin": {Type: "boolean"},
+						"roles":    {Type: "array", ReadOnly: true, Items: &Schema{Type: "string"}},
+					}),
+				},
+			},
+		},
+	}
+
+	assert.Equal(r.T(), []*Finding{
+		{
+			Pointer:  "/components/schemas/User/properties/SSN",
+			Rule:     RuleExcessiveDataExposure,
+			Severity: SeverityWarning,
+			Message:  "sensitive property SSN is returned to clients",
+		},
+		{
+			Pointer:  "/components/schemas/User/properties/is_admin",
+			Rule:     RuleMassAssignment,
+			Severity: SeverityWarning,
+			Message:  "privileged property is_admin can be assigned by clients; mark it readOnly or remove it from the request",
+		},
+		{
+			Pointer:  "/components/schemas/User/properties/password",
+			Rule:     RuleExcessiveDataExposure,
+			Severity: SeverityWarning,
+			Message:  "sensitive property password is returned to clients",
+		},
+		{
+			Pointer:  "/servers/1",
+			Rule:     RuleSecurityMisconfiguration,
+			Severity: SeverityWarning,
+			Message:  "server http://api.example.com is reachable over plain HTTP",
+		},
+	}, doc.AuditOWASP())
+
+	doc.Paths.PathItems.Get("/users").Get.Responses = NewResponses(map[string]*Response{"200": {Description: "Users."}})
+	findings := doc.AuditOWASP()
+	assert.Contains(r.T(), findings, &Finding{
+		Pointer:  "/paths/~1users/get/responses",
+		Rule:     RuleLackOfRateLimiting,
+		Severity: SeverityInfo,
+		Message:  "operation does not document a 429 response",
+	})
+}
+
+func TestOWASPSuite(t *testing.T) {
+	suite.Run(t, new(OWASPSuite))
+}
@@ -0,0 +1,175 @@
+package oas
+
+import (
+	"sort"
+	"strings"
+)
+
+// Specification extensions describing who owns a part of the API. They may
+// be declared on operations, path items, tags and the document root.
+const (
+	// OwnerExtension names the individual or group responsible for the API.
+	OwnerExtension = "x-owner"
+
+	// TeamExtension names the team maintaining the API.
+	TeamExtension = "x-team"
+
+	// SlackExtension names the Slack channel where the owners can be reached
+	// (e.g. "#team-pets").
+	SlackExtension = "x-slack"
+)
+
+// Rules reported by AuditOwnership.
+const (
+	RuleTagWithoutOwner       = "tag-without-owner"
+	RuleOperationWithoutOwner = "operation-without-owner"
+)
+
+// Ownership describes the owners of a part of the API.
+type Ownership struct {
+	// Owner describes the value of the x-owner extension.
+	Owner string `json:"owner,omitempty" yaml:"owner,omitempty"`
+
+	// Team describes the value of the x-team extension.
+	Team string `json:"team,omitempty" yaml:"team,omitempty"`
+
+	// Slack describes the value of the x-slack extension.
+	Slack string `json:"slack,omitempty" yaml:"slack,omitempty"`
+}
+
+// IsZero reports whether no owner nor team is declared. A Slack channel
+// alone does not make anybody responsible.
+func (r Ownership) IsZero() bool {
+	return r.Owner == "" && r.Team == ""
+}
+
+// merge returns the ownership with empty fields taken from the fallback.
+func (r Ownership) merge(fallback Ownership) Ownership {
+	if r.Owner == "" {
+		r.Owner = fallback.Owner
+	}
+	if r.Team == "" {
+		r.Team = fallback.Team
+	}
+	if r.Slack == "" {
+		r.Slack = fallback.Slack
+	}
+	return r
+}
+
+// Ownership returns the ownership declared by the extensions.
+func (r Extensions) Ownership() Ownership {
+	owner, _ := r.stringValue(OwnerExtension)
+	team, _ := r.stringValue(TeamExtension)
+	slack, _ := r.stringValue(SlackExtension)
+	return Ownership{Owner: owner, Team: team, Slack: slack}
+}
+
+// SetOwnership declares the ownership in the extensions. Empty fields remove
+// the corresponding extension.
+func (r *Extensions) SetOwnership(ownership Ownership) {
+	for key, value := range map[string]string{
+		OwnerExtension: ownership.Owner,
+		TeamExtension:  ownership.Team,
+		SlackExtension: ownership.Slack,
+	} {
+		if value == "" {
+			delete(*r, key)
+			continue
+		}
+		setExtension(r, key, value)
+	}
+}
+
+// OperationOwnership returns the ownership applying to an operation. Every
+// field is taken from the closest declaration: the operation, the path item,
+// the first tag of the operation declaring ownership and the document root.
+func (r OpenAPI) OperationOwnership(item *PathItem, operation *Operation) Ownership {
+	ownership := operation.Extensions.Ownership()
+	if item != nil {
+		ownership = ownership.merge(item.Extensions.Ownership())
+	}
+	tags := map[string]*Tag{}
+	for _, tag := range r.Tags {
+		if tag != nil {
+			tags[tag.Name] = tag
+		}
+	}
+	for _, name := range operation.Tags {
+		if tag := tags[name]; tag != nil && !tag.Extensions.Ownership().IsZero() {
+			ownership = ownership.merge(tag.Extensions.Ownership())
+			break
+		}
+	}
+	return ownership.merge(r.Extensions.Ownership())
+}
+
+// OwnerGroup describes the operations sharing the same ownership.
+type OwnerGroup struct {
+	// Ownership describes the ownership of the operations, zero for the
+	// operations nobody owns.
+	Ownership Ownership `json:"ownership" yaml:"ownership"`
+
+	// Operations describes the pointers of the operations in sorted order.
+	Operations []string `json:"operations" yaml:"operations"`
+}
+
+// OwnershipReport groups the operations of the document by their ownership.
+// Groups are ordered by team, owner and Slack channel, with the group of
+// unowned operations last.
+func (r OpenAPI) OwnershipReport() []*OwnerGroup {
+	groups := map[Ownership]*OwnerGroup{}
+	for _, op := range r.Paths.operations() {
+		ownership := r.OperationOwnership(op.item, op.operation)
+		if ownership.IsZero() {
+			ownership = Ownership{}
+		}
+		if groups[ownership] == nil {
+			groups[ownership] = &OwnerGroup{Ownership: ownership, Operations: make([]string, 0)}
+		}
+		groups[ownership].Operations = append(groups[ownership].Operations, join("/paths", op.path, op.method))
+	}
+
+	report := make([]*OwnerGroup, 0, len(groups))
+	for _, group := range groups {
+		report = append(report, group)
+	}
+	sort.Slice(report, func(i, j int) bool {
+		a, b := report[i].Ownership, report[j].Ownership
+		if a.IsZero() != b.IsZero() {
+			return b.IsZero()
+		}
+		return strings.Join([]string{a.Team, a.Owner, a.Slack}, "\x00") < strings.Join([]string{b.Team, b.Owner, b.Slack}, "\x00")
+	})
+	return report
+}
+
+// AuditOwnership requires every declared tag and every operation to have an
+// owner or a team, so every part of the API has somebody responsible for
+// it. Operations inherit the ownership of their path item, their tags and
+// the document root.
+func (r OpenAPI) AuditOwnership() []*Finding {
+	findings := make([]*Finding, 0)
+	for i, tag := range r.Tags {
+		if tag != nil && tag.Extensions.Ownership().IsZero() {
+			findings = append(findings, &Finding{
+				Pointer:  index("", "tags", i),
+				Rule:     RuleTagWithoutOwner,
+				Severity: SeverityWarning,
+				Message:  "tag " + tag.Name + " declares neither " + OwnerExtension + " nor " + TeamExtension,
+			})
+		}
+	}
+	for _, op := range r.Paths.operations() {
+		if r.OperationOwnership(op.item, op.operation).IsZero() {
+			findings = append(findings, &Finding{
+				Pointer:  join("/paths", op.path, op.method),
+				Rule:     RuleOperationWithoutOwner,
+				Severity: SeverityError,
+				Message:  "nobody owns the operation, declare " + OwnerExtension + " or " + TeamExtension + " on it, its path item, one of its tags or the document",
+			})
+		}
+	}
+	sortFindings(findings)
+	return findings
+}
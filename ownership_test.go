@@ -0,0 +1,99 @@
+package oas
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+)
+
+type OwnershipSuite struct {
+	suite.Suite
+}
+
+// ownershipPatch is the patch of the petstore fixture assigning owners to
+// the operations.
+const ownershipPatch = `
+paths:
+  /pets:
+    get:
+      tags: [pets]
+    post:
+      tags: [pets]
+      x-owner: alice
+  /pets/{petId}:
+    get:
+      tags: [pets]
+    delete:
+      tags: [pets]
+  /stores:
+    get:
+      tags: [stores]
+      responses:
+        "200":
+          description: The stores.
+    x-team: retail
+  /health:
+    get:
+      responses:
+        "200":
+          description: Healthy.
+      x-slack: "#ops"
+tags:
+  - name: pets
+    x-team: pets
+    x-slack: "#team-pets"
+  - name: stores
+`
+
+func (r *OwnershipSuite) TestAccessors() {
+	exts := Extensions{"x-custom": true}
+	exts.SetOwnership(Ownership{Owner: "alice", Slack: "#pets"})
+	assert.Equal(r.T(), Extensions{"x-custom": true, OwnerExtension: "alice", SlackExtension: "#pets"}, exts)
+	assert.Equal(r.T(), Ownership{Owner: "alice", Slack: "#pets"}, exts.Ownership())
+
+	exts.SetOwnership(Ownership{Team: "pets"})
+	assert.Equal(r.T(), Extensions{"x-custom": true, TeamExtension: "pets"}, exts)
+
+	var empty Extensions
+	empty.SetOwnership(Ownership{Team: "pets"})
+	assert.Equal(r.T(), Extensions{TeamExtension: "pets"}, empty)
+}
+
+func (r *OwnershipSuite) TestOwnershipReport() {
+	assert.Equal(r.T(), []*OwnerGroup{
+		{
+			Ownership:  Ownership{Team: "pets", Slack: "#team-pets"},
+			Operations: []string{"/paths/~1pets/get", "/paths/~1pets~1{petId}/get", "/paths/~1pets~1{petId}/delete"},
+		},
+		{
+			Ownership:  Ownership{Owner: "alice", Team: "pets", Slack: "#team-pets"},
+			Operations: []string{"/paths/~1pets/post"},
+		},
+		{
+			Ownership:  Ownership{Team: "retail"},
+			Operations: []string{"/paths/~1stores/get"},
+		},
+		{
+			Ownership:  Ownership{},
+			Operations: []string{"/paths/~1health/get"},
+		},
+	}, petstore(r.T(), ownershipPatch).OwnershipReport())
+}
+
+func (r *OwnershipSuite) TestAuditOwnership() {
+	findings := petstore(r.T(), ownershipPatch).AuditOwnership()
+	assert.Len(r.T(), findings, 2)
+	assert.Equal(r.T(), "/paths/~1health/get", findings[0].Pointer)
+	assert.Equal(r.T(), RuleOperationWithoutOwner, findings[0].Rule)
+	assert.Equal(r.T(), "/tags/1", findings[1].Pointer)
+	assert.Equal(r.T(), RuleTagWithoutOwner, findings[1].Rule)
+
+	findings = petstore(r.T(), ownershipPatch, "x-team: platform").AuditOwnership()
+	assert.Len(r.T(), findings, 1)
+	assert.Equal(r.T(), RuleTagWithoutOwner, findings[0].Rule)
+}
+
+func TestOwnershipSuite(t *testing.T) {
+	suite.Run(t, new(OwnershipSuite))
+}
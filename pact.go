@@ -0,0 +1,303 @@
+package oas
+
+import (
+	"encoding/json"
+	"fmt"
+	"mime"
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// Rules of the findings reported by VerifyPact.
+const (
+	RulePactUnknownRoute         = "pact-unknown-route"
+	RulePactMissingParameter     = "pact-missing-parameter"
+	RulePactUndeclaredResponse   = "pact-undeclared-response"
+	RulePactUndeclaredMediaType  = "pact-undeclared-media-type"
+	RulePactNonConformingPayload = "pact-non-conforming-payload"
+)
+
+// Pact describes a consumer-driven contract: the interactions a consumer
+// expects a provider to honor, as recorded by the Pact tooling (version 2 of
+// the Pact specification).
+type Pact struct {
+	// Consumer describes the consumer of the API.
+	Consumer PactParticipant `json:"consumer" yaml:"consumer"`
+
+	// Provider describes the provider of the API.
+	Provider PactParticipant `json:"provider" yaml:"provider"`
+
+	// Interactions describes the interactions the consumer expects.
+	Interactions []*PactInteraction `json:"interactions" yaml:"interactions"`
+
+	// Metadata describes the metadata of the file, such as the version of
+	// the Pact specification.
+	Metadata map[string]interface{} `json:"metadata,omitempty" yaml:"metadata,omitempty"`
+}
+
+// PactParticipant describes the consumer or the provider of a pact.
+type PactParticipant struct {
+	// Name describes the name of the participant.
+	Name string `json:"name" yaml:"name"`
+}
+
+// PactInteraction describes a request of the consumer together with the
+// response it expects.
+type PactInteraction struct {
+	// Description describes the interaction, unique within the pact.
+	Description string `json:"description" yaml:"description"`
+
+	// ProviderState describes the state the provider must be in.
+	ProviderState string `json:"providerState,omitempty" yaml:"providerState,omitempty"`
+
+	// Request describes the request of the consumer.
+	Request *PactRequest `json:"request" yaml:"request"`
+
+	// Response describes the response the consumer expects.
+	Response *PactResponse `json:"response" yaml:"response"`
+}
+
+// PactRequest describes the request of a pact interaction.
+type PactRequest struct {
+	// Method describes the HTTP method.
+	Method string `json:"method" yaml:"method"`
+
+	// Path describes the escaped request path.
+	Path string `json:"path" yaml:"path"`
+
+	// Query describes the encoded query string, without the leading "?".
+	Query string `json:"query,omitempty" yaml:"query,omitempty"`
+
+	// Headers describes the request headers.
+	Headers map[string]string `json:"headers,omitempty" yaml:"headers,omitempty"`
+
+	// Body describes the request body.
+	Body interface{} `json:"body,omitempty" yaml:"body,omitempty"`
+}
+
+// PactResponse describes the response of a pact interaction.
+type PactResponse struct {
+	// Status describes the HTTP status code.
+	Status int `json:"status" yaml:"status"`
+
+	// Headers describes the response headers.
+	Headers map[string]string `json:"headers,omitempty" yaml:"headers,omitempty"`
+
+	// Body describes the response body.
+	Body interface{} `json:"body,omitempty" yaml:"body,omitempty"`
+}
+
+// VerifyPact reports the interactions of the pact the document does not
+// honor: requests matching no operation, missing required query or header
+// parameters, responses with status codes or media types the operation does
+// not declare and bodies which do not conform to their schemas. Bodies are
+// validated with ValidatePayload when validate is nil. Bodies without a
+// Content-Type header are taken as JSON.
+func (r OpenAPI) VerifyPact(pact *Pact, validate PayloadValidator) []*Finding {
+	if validate == nil {
+		validate = ValidatePayload
+	}
+	router := &Router{Doc: &r}
+	findings := make([]*Finding, 0)
+	for _, interaction := range pact.Interactions {
+		if interaction == nil || interaction.Request == nil {
+			continue
+		}
+		name := interaction.Description
+		request := interaction.Request
+		path, err := url.PathUnescape(request.Path)
+		if err != nil {
+			path = request.Path
+		}
+		req := &http.Request{
+			Method: strings.ToUpper(request.Method),
+			URL:    &url.URL{Path: path, RawPath: request.Path, RawQuery: request.Query},
+		}
+		route, err := router.Match(req)
+		if err != nil {
+			findings = append(findings, &Finding{
+				Pointer:  "/paths",
+				Rule:     RulePactUnknownRoute,
+				Severity: SeverityError,
+				Message:  fmt.Sprintf("interaction %q: %s %s: %s", name, req.Method, request.Path, err),
+			})
+			continue
+		}
+		ptr := join("/paths", route.Path, route.Method)
+		failed := func(pointer string, rule string, format string, args ...interface{}) {
+			findings = append(findings, &Finding{
+				Pointer:  pointer,
+				Rule:     rule,
+				Severity: SeverityError,
+				Message:  fmt.Sprintf("interaction %q ", name) + fmt.Sprintf(format, args...),
+			})
+		}
+
+		query, _ := url.ParseQuery(request.Query)
+		headers := pactHeaders(request.Headers)
+		for _, parameter := range append(append([]*Parameter{}, route.PathItem.Parameters...), route.Operation.Parameters...) {
+			_, parameter = NewResolver(&r).parameter("", parameter)
+			if parameter == nil || !parameter.Required {
+				continue
+			}
+			missing := false
+			switch parameter.In {
+			case "query":
+				_, ok := query[parameter.Name]
+				missing = !ok
+			case "header":
+				missing = headers.Get(parameter.Name) == ""
+			}
+			if missing {
+				failed(join(ptr, "parameters"), RulePactMissingParameter, "omits required %s parameter %s", parameter.In, parameter.Name)
+			}
+		}
+
+		if request.Body != nil {
+			mediaType := pactMediaType(headers)
+			_, body := NewResolver(&r).requestBody("", route.Operation.RequestBody)
+			if body == nil || !declaresMediaType(body.Content, mediaType) {
+				failed(join(ptr, "requestBody"), RulePactUndeclaredMediaType, "sends an undeclared %s request body", mediaType)
+			} else if schema, _ := payloadSchema(body.Content, mediaType); schema != nil {
+				if err := validate(&r, schema, request.Body); err != nil {
+					failed(join(ptr, "requestBody"), RulePactNonConformingPayload, "sends a request body which does not conform: %s", err)
+				}
+			}
+		}
+
+		if interaction.Response == nil {
+			continue
+		}
+		_, response := NewResolver(&r).response("", declaredResponse(route.Operation.Responses, interaction.Response.Status))
+		if response == nil {
+			failed(join(ptr, "responses"), RulePactUndeclaredResponse, "expects undeclared status %d", interaction.Response.Status)
+			continue
+		}
+		if interaction.Response.Body == nil {
+			continue
+		}
+		mediaType := pactMediaType(pactHeaders(interaction.Response.Headers))
+		if !declaresMediaType(response.Content, mediaType) {
+			failed(join(ptr, "responses"), RulePactUndeclaredMediaType, "expects an undeclared %s body", mediaType)
+		} else if schema, _ := payloadSchema(response.Content, mediaType); schema != nil {
+			if err := validate(&r, schema, interaction.Response.Body); err != nil {
+				failed(join(ptr, "responses"), RulePactNonConformingPayload, "expects a response body which does not conform: %s", err)
+			}
+		}
+	}
+	sortFindings(findings)
+	return findings
+}
+
+// PactStubs returns a pact between the consumer and the provider holding an
+// interaction per operation, ordered by path and method, built from the
+// examples of the parameters, of the request body and of the success
+// response with the lowest status code. Operations without a success
+// response, or whose required parameters or body have no example, are left
+// out.
+func (r OpenAPI) PactStubs(consumer string, provider string) (*Pact, error) {
+	if consumer == "" || provider == "" {
+		return nil, errors.New("missing consumer or provider name")
+	}
+	pact := &Pact{
+		Consumer:     PactParticipant{Name: consumer},
+		Provider:     PactParticipant{Name: provider},
+		Interactions: make([]*PactInteraction, 0),
+		Metadata: map[string]interface{}{
+			"pactSpecification": map[string]interface{}{"version": "2.0.0"},
+		},
+	}
+	for _, op := range r.Paths.operations() {
+		sample, _ := r.sampleRequest(op)
+		status, response := r.successResponse(op.operation)
+		if sample == nil || response == nil {
+			continue
+		}
+		name := op.operation.OperationID
+		if name == "" {
+			name = strings.ToUpper(op.method) + " " + op.path
+		}
+
+		request := &PactRequest{Method: strings.ToUpper(op.method), Path: sample.path}
+		if i := strings.Index(sample.path, "?"); i >= 0 {
+			request.Path, request.Query = sample.path[:i], sample.path[i+1:]
+		}
+		if len(sample.headers) > 0 {
+			request.Headers = sample.headers
+		}
+		if sample.body != nil {
+			request.Body = pactBody(sample.headers["Content-Type"], *sample.body)
+		}
+
+		stub := &PactResponse{Status: status}
+		if mediaType, payload, ok := requestExample(&RequestBody{Content: response.Content}); ok {
+			stub.Headers = map[string]string{"Content-Type": mediaType}
+			stub.Body = pactBody(mediaType, payload)
+		}
+		pact.Interactions = append(pact.Interactions, &PactInteraction{
+			Description: "a request to " + name,
+			Request:     request,
+			Response:    stub,
+		})
+	}
+	return pact, nil
+}
+
+// successResponse returns the 2XX response of the operation with the lowest
+// status code, resolved, or nil if it declares none.
+func (r OpenAPI) successResponse(operation *Operation) (int, *Response) {
+	codes := make([]int, 0)
+	for _, code := range operation.Responses.Keys() {
+		if status, err := strconv.Atoi(code); err == nil && status >= 200 && status < 300 {
+			codes = append(codes, status)
+		}
+	}
+	sort.Ints(codes)
+	for _, status := range codes {
+		if _, response := NewResolver(&r).response("", operation.Responses.Get(strconv.Itoa(status))); response != nil {
+			return status, response
+		}
+	}
+	return 0, nil
+}
+
+// pactHeaders returns the headers of a pact request or response with
+// canonical names.
+func pactHeaders(headers map[string]string) http.Header {
+	canonical := http.Header{}
+	for name, value := range headers {
+		canonical.Set(name, value)
+	}
+	return canonical
+}
+
+// pactMediaType returns the media type of the Content-Type header, JSON
+// when absent.
+func pactMediaType(headers http.Header) string {
+	contentType := headers.Get("Content-Type")
+	if contentType == "" {
+		return "application/json"
+	}
+	if mediaType, _, err := mime.ParseMediaType(contentType); err == nil {
+		return mediaType
+	}
+	return contentType
+}
+
+// pactBody returns the encoded body as a generic value for JSON media types
+// and as a string otherwise.
+func pactBody(mediaType string, payload string) interface{} {
+	if !isJSONMediaType(mediaType) {
+		return payload
+	}
+	var value interface{}
+	if err := json.Unmarshal([]byte(payload), &value); err != nil {
+		return payload
+	}
+	return value
+}
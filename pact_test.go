@@ -0,0 +1,197 @@
+package oas
+
+import (
+	"testing"
+
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+)
+
+type PactSuite struct {
+	suite.Suite
+}
+
+// pactPatch is the patch of the petstore fixture giving examples to the
+// operations on the pets.
+const pactPatch = `
+paths:
+  /pets:
+    get:
+      parameters:
+        - name: limit
+          in: query
+          required: true
+          example: 20
+      responses:
+        "200":
+          content:
+            application/json:
+              example:
+                - name: Rex
+    post:
+      requestBody:
+        content:
+          application/json:
+            example:
+              name: Rex
+      responses:
+        default:
+          description: Error.
+`
+
+func (r *PactSuite) TestPactStubs() {
+	pact, err := petstore(r.T(), pactPatch).PactStubs("web", "petstore")
+	assert.Nil(r.T(), err)
+	assert.Equal(r.T(), PactParticipant{Name: "web"}, pact.Consumer)
+	assert.Equal(r.T(), PactParticipant{Name: "petstore"}, pact.Provider)
+	assert.Equal(r.T(), []*PactInteraction{
+		{
+			Description: "a request to listPets",
+			Request:     &PactRequest{Method: "GET", Path: "/pets", Query: "limit=20"},
+			Response: &PactResponse{
+				Status:  200,
+				Headers: map[string]string{"Content-Type": "application/json"},
+				Body:    []interface{}{map[string]interface{}{"name": "Rex"}},
+			},
+		},
+		{
+			Description: "a request to createPet",
+			Request: &PactRequest{
+				Method:  "POST",
+				Path:    "/pets",
+				Headers: map[string]string{"Content-Type": "application/json"},
+				Body:    map[string]interface{}{"name": "Rex"},
+			},
+			Response: &PactResponse{Status: 201},
+		},
+	}, pact.Interactions)
+
+	_, err = petstore(r.T(), pactPatch).PactStubs("", "petstore")
+	assert.NotNil(r.T(), err)
+}
+
+func (r *PactSuite) TestVerifyPact() {
+	validate := func(doc *OpenAPI, schema *Schema, value interface{}) error {
+		schema = NewResolver(doc).schema(schema)
+		if object, ok := value.(map[string]interface{}); ok && schema.Type == "object" && object["name"] == nil {
+			return errors.New("missing name")
+		}
+		return nil
+	}
+
+	testCases := []struct {
+		interaction *PactInteraction
+		findings    []*Finding
+	}{
+		{
+			&PactInteraction{
+				Description: "list",
+				Request:     &PactRequest{Method: "get", Path: "/pets", Query: "limit=5"},
+				Response:    &PactResponse{Status: 200, Body: []interface{}{}},
+			},
+			[]*Finding{},
+		},
+		{
+			&PactInteraction{
+				Description: "delete",
+				Request:     &PactRequest{Method: "DELETE", Path: "/pets/a%20b"},
+				Response:    &PactResponse{Status: 204},
+			},
+			[]*Finding{},
+		},
+		{
+			&PactInteraction{
+				Description: "unknown",
+				Request:     &PactRequest{Method: "GET", Path: "/owners"},
+			},
+			[]*Finding{{
+				Pointer:  "/paths",
+				Rule:     RulePactUnknownRoute,
+				Severity: SeverityError,
+				Message:  `interaction "unknown": GET /owners: route not found`,
+			}},
+		},
+		{
+			&PactInteraction{
+				Description: "no limit",
+				Request:     &PactRequest{Method: "GET", Path: "/pets"},
+				Response:    &PactResponse{Status: 404},
+			},
+			[]*Finding{
+				{
+					Pointer:  "/paths/~1pets/get/parameters",
+					Rule:     RulePactMissingParameter,
+					Severity: SeverityError,
+					Message:  `interaction "no limit" omits required query parameter limit`,
+				},
+				{
+					Pointer:  "/paths/~1pets/get/responses",
+					Rule:     RulePactUndeclaredResponse,
+					Severity: SeverityError,
+					Message:  `interaction "no limit" expects undeclared status 404`,
+				},
+			},
+		},
+		{
+			&PactInteraction{
+				Description: "create",
+				Request:     &PactRequest{Method: "POST", Path: "/pets", Body: map[string]interface{}{}},
+				Response: &PactResponse{
+					Status:  400,
+					Headers: map[string]string{"content-type": "text/plain; charset=utf-8"},
+					Body:    "bad request",
+				},
+			},
+			[]*Finding{
+				{
+					Pointer:  "/paths/~1pets/post/requestBody",
+					Rule:     RulePactNonConformingPayload,
+					Severity: SeverityError,
+					Message:  `interaction "create" sends a request body which does not conform: missing name`,
+				},
+				{
+					Pointer:  "/paths/~1pets/post/responses",
+					Rule:     RulePactUndeclaredMediaType,
+					Severity: SeverityError,
+					Message:  `interaction "create" expects an undeclared text/plain body`,
+				},
+			},
+		},
+	}
+
+	failMsg := "test case %d failed"
+	for i, tc := range testCases {
+		pact := &Pact{Interactions: []*PactInteraction{tc.interaction}}
+		findings := petstore(r.T(), pactPatch).VerifyPact(pact, validate)
+		assert.Equal(r.T(), tc.findings, findings, failMsg, i)
+	}
+}
+
+func (r *PactSuite) TestRoundTrip() {
+	doc := petstore(r.T(), pactPatch)
+	pact, err := doc.PactStubs("web", "petstore")
+	assert.Nil(r.T(), err)
+	assert.Equal(r.T(), []*Finding{}, doc.VerifyPact(pact, nil))
+}
+
+func (r *PactSuite) TestVerifyPactDefaultValidator() {
+	pact := &Pact{Interactions: []*PactInteraction{{
+		Description: "create",
+		Request: &PactRequest{
+			Method:  "POST",
+			Path:    "/pets",
+			Headers: map[string]string{"Content-Type": "application/json"},
+			Body:    map[string]interface{}{"name": 5},
+		},
+		Response: &PactResponse{Status: 201},
+	}}}
+	findings := petstore(r.T(), pactPatch).VerifyPact(pact, nil)
+	if assert.Len(r.T(), findings, 1) {
+		assert.Equal(r.T(), RulePactNonConformingPayload, findings[0].Rule)
+	}
+}
+
+func TestPactSuite(t *testing.T) {
+	suite.Run(t, new(PactSuite))
+}
@@ -0,0 +1,245 @@
+package oas
+
+import (
+	"github.com/pkg/errors"
+)
+
+// PaginationExtension names the specification extension describing how the
+// results of an operation are paginated, so that clients can be generated
+// with iterators walking through every page.
+const PaginationExtension = "x-pagination"
+
+// PaginationStyle describes how a client requests the next page.
+type PaginationStyle string
+
+// Pagination styles.
+const (
+	// CursorPagination passes the opaque cursor returned with a page to
+	// request the next one.
+	CursorPagination PaginationStyle = "cursor"
+
+	// OffsetPagination passes the number of items to skip to request the
+	// next page.
+	OffsetPagination PaginationStyle = "offset"
+)
+
+// Pagination describes how the results of an operation are paginated.
+type Pagination struct {
+	// Style describes how the next page is requested.
+	Style PaginationStyle `json:"style" yaml:"style"`
+
+	// ItemsProperty describes the property of the envelope holding the items
+	// of the page.
+	ItemsProperty string `json:"itemsProperty" yaml:"itemsProperty"`
+
+	// LimitParameter describes the query parameter bounding the number of
+	// items of a page.
+	LimitParameter string `json:"limitParameter,omitempty" yaml:"limitParameter,omitempty"`
+
+	// CursorParameter describes the query parameter carrying the cursor of
+	// the requested page, for CursorPagination.
+	CursorParameter string `json:"cursorParameter,omitempty" yaml:"cursorParameter,omitempty"`
+
+	// NextCursorProperty describes the property of the envelope holding the
+	// cursor of the next page, null on the last page, for CursorPagination.
+	NextCursorProperty string `json:"nextCursorProperty,omitempty" yaml:"nextCursorProperty,omitempty"`
+
+	// OffsetParameter describes the query parameter carrying the number of
+	// items to skip, for OffsetPagination.
+	OffsetParameter string `json:"offsetParameter,omitempty" yaml:"offsetParameter,omitempty"`
+
+	// TotalProperty describes the property of the envelope holding the total
+	// number of items, for OffsetPagination.
+	TotalProperty string `json:"totalProperty,omitempty" yaml:"totalProperty,omitempty"`
+}
+
+// NewPagination returns the pagination of the style with the conventional
+// parameter and property names.
+func NewPagination(style PaginationStyle) *Pagination {
+	pagination := &Pagination{Style: style, ItemsProperty: "items", LimitParameter: "limit"}
+	switch style {
+	case CursorPagination:
+		pagination.CursorParameter = "cursor"
+		pagination.NextCursorProperty = "nextCursor"
+	case OffsetPagination:
+		pagination.OffsetParameter = "offset"
+		pagination.TotalProperty = "total"
+	}
+	return pagination
+}
+
+// validate checks that the pagination declares what its style needs.
+func (r Pagination) validate() error {
+	if r.ItemsProperty == "" {
+		return errors.New("missing itemsProperty")
+	}
+	switch r.Style {
+	case CursorPagination:
+		if r.CursorParameter == "" || r.NextCursorProperty == "" {
+			return errors.New("cursor pagination needs cursorParameter and nextCursorProperty")
+		}
+	case OffsetPagination:
+		if r.OffsetParameter == "" {
+			return errors.New("offset pagination needs offsetParameter")
+		}
+	default:
+		return errors.Errorf("unknown style %q", r.Style)
+	}
+	return nil
+}
+
+// Pagination returns the pagination declared by the x-pagination extension,
+// or nil if the extension is absent.
+func (r Extensions) Pagination() (*Pagination, error) {
+	pagination := &Pagination{}
+	ok, err := r.decode(PaginationExtension, pagination)
+	if !ok || err != nil {
+		return nil, err
+	}
+	if err := pagination.validate(); err != nil {
+		return nil, errors.Wrap(err, PaginationExtension)
+	}
+	return pagination, nil
+}
+
+// SetPagination declares the pagination with the x-pagination extension, or
+// removes the extension when nil.
+func (r *Extensions) SetPagination(pagination *Pagination) {
+	if pagination == nil {
+		delete(*r, PaginationExtension)
+		return
+	}
+	value := map[string]interface{}{
+		"style":         string(pagination.Style),
+		"itemsProperty": pagination.ItemsProperty,
+	}
+	optional := map[string]string{
+		"limitParameter":     pagination.LimitParameter,
+		"cursorParameter":    pagination.CursorParameter,
+		"nextCursorProperty": pagination.NextCursorProperty,
+		"offsetParameter":    pagination.OffsetParameter,
+		"totalProperty":      pagination.TotalProperty,
+	}
+	for key, name := range optional {
+		if name != "" {
+			value[key] = name
+		}
+	}
+	setExtension(r, PaginationExtension, value)
+}
+
+// PageSchema registers the envelope of a page of the named component schema
+// in the components, once per item schema and style, and returns a reference
+// to it. Cursor pages are named after the item schema suffixed with
+// "CursorPage" and offset pages with "OffsetPage".
+func (r *OpenAPI) PageSchema(item string, pagination *Pagination) (*Schema, error) {
+	if err := pagination.validate(); err != nil {
+		return nil, err
+	}
+	if r.Components == nil || r.Components.Schemas[item] == nil {
+		return nil, errors.Errorf("schema %q not found", item)
+	}
+
+	name := item + "CursorPage"
+	if pagination.Style == OffsetPagination {
+		name = item + "OffsetPage"
+	}
+	if existing, ok := r.Components.Schemas[name]; ok {
+		if existing == nil || existing.Properties.Get(pagination.ItemsProperty) == nil {
+			return nil, errors.Errorf("schema %q is already declared", name)
+		}
+		return &Schema{Ref: "#/components/schemas/" + name}, nil
+	}
+
+	envelope := &Schema{
+		Type: "object",
+		Properties: NewProperties(map[string]*Schema{
+			pagination.ItemsProperty: {
+				Type:  "array",
+				Items: &Schema{Ref: "#/components/schemas/" + item},
+			},
+		}),
+		Required: []string{pagination.ItemsProperty},
+	}
+	switch pagination.Style {
+	case CursorPagination:
+		envelope.Properties.Set(pagination.NextCursorProperty, &Schema{
+			Type:        "string",
+			Nullable:    true,
+			Description: "Cursor of the next page, null on the last page.",
+		})
+		envelope.Required = append(envelope.Required, pagination.NextCursorProperty)
+	case OffsetPagination:
+		if pagination.TotalProperty != "" {
+			envelope.Properties.Set(pagination.TotalProperty, &Schema{
+				Type:        "integer",
+				Minimum:     0,
+				Description: "Total number of items.",
+			})
+		}
+	}
+	r.Components.Schemas[name] = envelope
+	return &Schema{Ref: "#/components/schemas/" + name}, nil
+}
+
+// Paginate declares that the operation under the path and lowercase method
+// returns pages of the named component schema. The page envelope becomes the
+// JSON schema of the 200 response, the query parameters of the pagination
+// are declared unless the operation or its path item already declares them,
+// and the pagination is recorded with the x-pagination extension.
+func (r *OpenAPI) Paginate(path string, method string, item string, pagination *Pagination) error {
+	op, err := r.Paths.operation(path, method)
+	if err != nil {
+		return err
+	}
+	ptr := join("/paths", path, method)
+	response := op.operation.Responses.Get("200")
+	if response != nil && response.Ref != "" {
+		return errors.Errorf("%s: the 200 response references a component", ptr)
+	}
+	schema, err := r.PageSchema(item, pagination)
+	if err != nil {
+		return errors.Wrap(err, ptr)
+	}
+
+	if response == nil {
+		response = &Response{Description: "A page of " + item + " items."}
+		op.operation.Responses.Set("200", response)
+	}
+	if response.Content == nil {
+		response.Content = map[string]*MediaType{}
+	}
+	if response.Content["application/json"] == nil {
+		response.Content["application/json"] = &MediaType{}
+	}
+	response.Content["application/json"].Schema = schema
+
+	declared := map[string]bool{}
+	for _, parameter := range append(append([]*Parameter{}, op.item.Parameters...), op.operation.Parameters...) {
+		if _, parameter = NewResolver(r).parameter("", parameter); parameter != nil {
+			declared[parameterKey(parameter)] = true
+		}
+	}
+	parameters := []*Parameter{
+		{Name: pagination.LimitParameter, In: "query", Header: Header{
+			Description: "Maximum number of items of the page.",
+			Schema:      &Schema{Type: "integer", Minimum: 1},
+		}},
+		{Name: pagination.CursorParameter, In: "query", Header: Header{
+			Description: "Cursor of the page, as returned with the previous page.",
+			Schema:      &Schema{Type: "string"},
+		}},
+		{Name: pagination.OffsetParameter, In: "query", Header: Header{
+			Description: "Number of items to skip.",
+			Schema:      &Schema{Type: "integer", Minimum: 0},
+		}},
+	}
+	for _, parameter := range parameters {
+		if parameter.Name != "" && !declared[parameterKey(parameter)] {
+			op.operation.Parameters = append(op.operation.Parameters, parameter)
+		}
+	}
+
+	op.operation.Extensions.SetPagination(pagination)
+	return nil
+}
@@ -0,0 +1,147 @@
+package oas
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+)
+
+type PaginationSuite struct {
+	suite.Suite
+}
+
+// paginationPatch is the patch of the petstore fixture adding the owners
+// listed in pages.
+const paginationPatch = `
+paths:
+  /pets:
+    parameters:
+      - name: limit
+        in: query
+        schema:
+          type: integer
+  /owners:
+    get:
+      operationId: listOwners
+components:
+  schemas:
+    Owner:
+      type: object
+      properties:
+        name:
+          type: string
+`
+
+func (r *PaginationSuite) TestPaginate() {
+	doc := petstore(r.T(), paginationPatch)
+	assert.Nil(r.T(), doc.Paginate("/pets", "get", "Pet", NewPagination(CursorPagination)))
+	assert.Nil(r.T(), doc.Paginate("/owners", "get", "Owner", NewPagination(OffsetPagination)))
+
+	operation := doc.Paths.PathItems.Get("/pets").Get
+	assert.Equal(r.T(), &Schema{Ref: "#/components/schemas/PetCursorPage"}, operation.Responses.Get("200").Content["application/json"].Schema)
+	assert.Equal(r.T(), "The pets.", operation.Responses.Get("200").Description)
+	assert.Len(r.T(), operation.Parameters, 1)
+	assert.Equal(r.T(), "cursor", operation.Parameters[0].Name)
+	pagination, err := operation.Extensions.Pagination()
+	assert.Nil(r.T(), err)
+	assert.Equal(r.T(), NewPagination(CursorPagination), pagination)
+
+	page := doc.Components.Schemas["PetCursorPage"]
+	assert.Equal(r.T(), []string{"items", "nextCursor"}, page.Required)
+	assert.Equal(r.T(), &Schema{Ref: "#/components/schemas/Pet"}, page.Properties.Get("items").Items)
+	assert.True(r.T(), page.Properties.Get("nextCursor").Nullable)
+
+	operation = doc.Paths.PathItems.Get("/owners").Get
+	assert.Equal(r.T(), "A page of Owner items.", operation.Responses.Get("200").Description)
+	names := make([]string, 0)
+	for _, parameter := range operation.Parameters {
+		names = append(names, parameter.Name)
+	}
+	assert.Equal(r.T(), []string{"limit", "offset"}, names)
+	page = doc.Components.Schemas["OwnerOffsetPage"]
+	assert.Equal(r.T(), []string{"items"}, page.Required)
+	assert.Equal(r.T(), "integer", page.Properties.Get("total").Type)
+}
+
+func (r *PaginationSuite) TestPageSchema() {
+	doc := petstore(r.T(), paginationPatch)
+	first, err := doc.PageSchema("Pet", NewPagination(CursorPagination))
+	assert.Nil(r.T(), err)
+	second, err := doc.PageSchema("Pet", NewPagination(CursorPagination))
+	assert.Nil(r.T(), err)
+	assert.Equal(r.T(), first, second)
+	assert.Len(r.T(), doc.Components.Schemas, 3)
+
+	testCases := []struct {
+		item       string
+		pagination *Pagination
+	}{
+		{"Cat", NewPagination(CursorPagination)},
+		{"Pet", &Pagination{Style: "page", ItemsProperty: "items"}},
+		{"Pet", &Pagination{Style: CursorPagination, ItemsProperty: "items"}},
+		{"Pet", &Pagination{Style: OffsetPagination}},
+		{"Owner", &Pagination{Style: CursorPagination, ItemsProperty: "data", CursorParameter: "c", NextCursorProperty: "next"}},
+	}
+
+	doc.Components.Schemas["OwnerCursorPage"] = &Schema{Type: "object"}
+	for i, testCase := range testCases {
+		failMsg := "test case %d failed"
+		_, err := doc.PageSchema(testCase.item, testCase.pagination)
+		assert.NotNil(r.T(), err, failMsg, i)
+	}
+}
+
+func (r *PaginationSuite) TestPagination() {
+	testCases := []struct {
+		value    interface{}
+		expected *Pagination
+		isErr    bool
+	}{
+		{nil, nil, false},
+		{
+			map[string]interface{}{"style": "offset", "itemsProperty": "data", "offsetParameter": "skip"},
+			&Pagination{Style: OffsetPagination, ItemsProperty: "data", OffsetParameter: "skip"},
+			false,
+		},
+		{map[string]interface{}{"style": "cursor", "itemsProperty": "data"}, nil, true},
+		{map[string]interface{}{"style": "page", "itemsProperty": "data"}, nil, true},
+		{"cursor", nil, true},
+	}
+
+	for i, testCase := range testCases {
+		failMsg := "test case %d failed"
+		extensions := Extensions{}
+		if testCase.value != nil {
+			extensions[PaginationExtension] = testCase.value
+		}
+		pagination, err := extensions.Pagination()
+		if testCase.isErr {
+			assert.NotNil(r.T(), err, failMsg, i)
+			continue
+		}
+		assert.Nil(r.T(), err, failMsg, i)
+		assert.Equal(r.T(), testCase.expected, pagination, failMsg, i)
+	}
+}
+
+func (r *PaginationSuite) TestPaginateErrors() {
+	doc := petstore(r.T(), paginationPatch)
+	assert.NotNil(r.T(), doc.Paginate("/pets", "put", "Pet", NewPagination(CursorPagination)))
+	assert.NotNil(r.T(), doc.Paginate("/pets", "get", "Cat", NewPagination(CursorPagination)))
+	doc = petstore(r.T(), paginationPatch, `
+paths:
+  /pets:
+    get:
+      responses:
+        "200":
+          description: null
+          content: null
+          $ref: "#/components/responses/Pets"
+`)
+	assert.NotNil(r.T(), doc.Paginate("/pets", "get", "Pet", NewPagination(CursorPagination)))
+}
+
+func TestPaginationSuite(t *testing.T) {
+	suite.Run(t, new(PaginationSuite))
+}
@@ -0,0 +1,131 @@
+package oas
+
+import (
+	"github.com/pkg/errors"
+)
+
+// Repetitions of the fields of a Parquet schema.
+const (
+	ParquetRequired = "REQUIRED"
+	ParquetOptional = "OPTIONAL"
+	ParquetRepeated = "REPEATED"
+)
+
+// ParquetField describes a field of a Parquet schema. Arrow schemas are
+// derived from Parquet schemas the same way, so the mapping also describes
+// the Arrow fields of the schema.
+type ParquetField struct {
+	// Name describes the name of the field.
+	Name string `json:"name" yaml:"name"`
+
+	// Repetition describes whether the field is ParquetRequired,
+	// ParquetOptional or ParquetRepeated.
+	Repetition string `json:"repetition" yaml:"repetition"`
+
+	// Type describes the physical type of the field (e.g. "INT64",
+	// "BYTE_ARRAY"). It is empty for groups.
+	Type string `json:"type,omitempty" yaml:"type,omitempty"`
+
+	// LogicalType describes how the physical type is interpreted (e.g.
+	// "STRING", "DATE").
+	LogicalType string `json:"logicalType,omitempty" yaml:"logicalType,omitempty"`
+
+	// Fields describes the fields of a group.
+	Fields []*ParquetField `json:"fields,omitempty" yaml:"fields,omitempty"`
+}
+
+// ParquetFields maps the properties of the named object schema of the
+// components onto Parquet fields ordered by name. Required properties which
+// are not nullable are required fields, arrays are repeated fields of their
+// item type and nested objects are groups. Maps, arrays of arrays and
+// composed schemas are stored as JSON. Recursive schemas cannot be
+// represented and yield an error.
+func (r OpenAPI) ParquetFields(name string) ([]*ParquetField, error) {
+	var schema *Schema
+	if r.Components != nil {
+		schema = NewResolver(&r).schema(r.Components.Schemas[name])
+	}
+	if schema == nil || schema.Properties.Len() == 0 {
+		return nil, errors.Errorf("schema %q is not an object schema with properties", name)
+	}
+	return r.parquetGroup(schema, map[*Schema]bool{})
+}
+
+// parquetGroup maps the properties of the object schema onto fields.
+func (r OpenAPI) parquetGroup(schema *Schema, visiting map[*Schema]bool) ([]*ParquetField, error) {
+	if visiting[schema] {
+		return nil, errors.New("recursive schemas cannot be represented in Parquet")
+	}
+	visiting[schema] = true
+	defer delete(visiting, schema)
+
+	required := map[string]bool{}
+	for _, property := range schema.Required {
+		required[property] = true
+	}
+	fields := make([]*ParquetField, 0, schema.Properties.Len())
+	for _, property := range sortedKeys(schema.Properties) {
+		resolved := NewResolver(&r).schema(schema.Properties.Get(property))
+		if resolved == nil {
+			return nil, errors.Errorf("property %q: unresolvable schema", property)
+		}
+		field := &ParquetField{Name: property, Repetition: ParquetOptional}
+		if required[property] && !resolved.Nullable {
+			field.Repetition = ParquetRequired
+		}
+		if resolved.Type == "array" {
+			field.Repetition = ParquetRepeated
+			resolved = NewResolver(&r).schema(resolved.Items)
+			if resolved == nil {
+				return nil, errors.Errorf("property %q: array without items", property)
+			}
+			if resolved.Type == "array" {
+				resolved = &Schema{}
+			}
+		}
+		if err := r.parquetType(field, resolved, visiting); err != nil {
+			return nil, errors.Wrapf(err, "property %q", property)
+		}
+		fields = append(fields, field)
+	}
+	return fields, nil
+}
+
+// parquetType sets the physical and logical types of the field, or its
+// fields for object schemas.
+func (r OpenAPI) parquetType(field *ParquetField, schema *Schema, visiting map[*Schema]bool) error {
+	composed := len(schema.AllOf) > 0 || len(schema.OneOf) > 0 || len(schema.AnyOf) > 0
+	switch {
+	case composed:
+		field.Type, field.LogicalType = "BYTE_ARRAY", "JSON"
+	case schema.Type == "boolean":
+		field.Type = "BOOLEAN"
+	case schema.Type == "integer" && schema.Format == "int64":
+		field.Type = "INT64"
+	case schema.Type == "integer":
+		field.Type = "INT32"
+	case schema.Type == "number" && schema.Format == "float":
+		field.Type = "FLOAT"
+	case schema.Type == "number":
+		field.Type = "DOUBLE"
+	case schema.Type == "string" && schema.Format == "date":
+		field.Type, field.LogicalType = "INT32", "DATE"
+	case schema.Type == "string" && schema.Format == "date-time":
+		field.Type, field.LogicalType = "INT64", "TIMESTAMP_MILLIS"
+	case schema.Type == "string" && (schema.Format == "byte" || schema.Format == "binary"):
+		field.Type = "BYTE_ARRAY"
+	case schema.Type == "string" && len(schema.Enum) > 0:
+		field.Type, field.LogicalType = "BYTE_ARRAY", "ENUM"
+	case schema.Type == "string":
+		field.Type, field.LogicalType = "BYTE_ARRAY", "STRING"
+	case schema.Type == "object" && schema.Properties.Len() > 0:
+		fields, err := r.parquetGroup(schema, visiting)
+		if err != nil {
+			return err
+		}
+		field.Fields = fields
+	default:
+		field.Type, field.LogicalType = "BYTE_ARRAY", "JSON"
+	}
+	return nil
+}
@@ -0,0 +1,94 @@
+package oas
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+)
+
+type ParquetSuite struct {
+	suite.Suite
+}
+
+// parquetPatch is the patch of the petstore fixture the suite converts.
+const parquetPatch = `
+components:
+  schemas:
+    Pet:
+      required: [id, name, nickname]
+      properties:
+        tag: null
+        born:
+          type: string
+          format: date-time
+        grid:
+          type: array
+          items:
+            type: array
+            items:
+              type: integer
+        id:
+          type: integer
+          format: int64
+        labels:
+          type: object
+          additionalProperties:
+            type: string
+        nickname:
+          type: string
+          nullable: true
+        owner:
+          $ref: "#/components/schemas/Owner"
+        status:
+          type: string
+          enum: [available]
+        weights:
+          type: array
+          items:
+            type: number
+    Owner:
+      type: object
+      properties:
+        vip:
+          type: boolean
+    Node:
+      type: object
+      properties:
+        next:
+          $ref: "#/components/schemas/Node"
+    Name:
+      type: string
+`
+
+func (r *ParquetSuite) TestParquetFields() {
+	fields, err := petstore(r.T(), parquetPatch).ParquetFields("Pet")
+	assert.Nil(r.T(), err)
+	assert.Equal(r.T(), []*ParquetField{
+		{Name: "born", Repetition: ParquetOptional, Type: "INT64", LogicalType: "TIMESTAMP_MILLIS"},
+		{Name: "grid", Repetition: ParquetRepeated, Type: "BYTE_ARRAY", LogicalType: "JSON"},
+		{Name: "id", Repetition: ParquetRequired, Type: "INT64"},
+		{Name: "labels", Repetition: ParquetOptional, Type: "BYTE_ARRAY", LogicalType: "JSON"},
+		{Name: "name", Repetition: ParquetRequired, Type: "BYTE_ARRAY", LogicalType: "STRING"},
+		{Name: "nickname", Repetition: ParquetOptional, Type: "BYTE_ARRAY", LogicalType: "STRING"},
+		{Name: "owner", Repetition: ParquetOptional, Fields: []*ParquetField{
+			{Name: "vip", Repetition: ParquetOptional, Type: "BOOLEAN"},
+		}},
+		{Name: "status", Repetition: ParquetOptional, Type: "BYTE_ARRAY", LogicalType: "ENUM"},
+		{Name: "weights", Repetition: ParquetRepeated, Type: "DOUBLE"},
+	}, fields)
+}
+
+func (r *ParquetSuite) TestErrors() {
+	testCases := []string{"Missing", "Name", "Node"}
+
+	for i, name := range testCases {
+		failMsg := "test case %d failed"
+		_, err := petstore(r.T(), parquetPatch).ParquetFields(name)
+		assert.NotNil(r.T(), err, failMsg, i)
+	}
+}
+
+func TestParquetSuite(t *testing.T) {
+	suite.Run(t, new(ParquetSuite))
+}
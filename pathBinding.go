@@ -0,0 +1,94 @@
+package oas
+
+import (
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// PathBinding describes a templated segment of a path (e.g. "{petId}") and
+// the type of the path parameter bound to it, so generated routers and
+// clients can use typed signatures (e.g. GetPet(petId int64)) instead of
+// strings.
+type PathBinding struct {
+	// Name describes the name of the path parameter.
+	Name string `json:"name" yaml:"name"`
+
+	// Segment describes the zero based index of the path segment holding
+	// the parameter, not counting the leading slash.
+	Segment int `json:"segment" yaml:"segment"`
+
+	// Type describes the type of the parameter schema, "string" when the
+	// parameter declares no schema type.
+	Type string `json:"type" yaml:"type"`
+
+	// Format describes the format of the parameter schema.
+	Format string `json:"format,omitempty" yaml:"format,omitempty"`
+
+	// GoType describes the Go type values of the parameter are bound to
+	// (e.g. "int64", "float64", "bool", "time.Time" or "string").
+	GoType string `json:"goType" yaml:"goType"`
+}
+
+// PathBindings returns the bindings of the templated segments of the path in
+// the order they appear. The parameters are looked up among the parameters of
+// the operation of the lowercase HTTP method (e.g. "get"), which override
+// those of the path item. An error is returned when the path or the
+// operation is not declared or when a template expression has no matching
+// path parameter.
+func (r OpenAPI) PathBindings(path string, method string) ([]*PathBinding, error) {
+	op, err := r.Paths.operation(path, method)
+	if err != nil {
+		return nil, err
+	}
+
+	params := map[string]*Parameter{}
+	for _, values := range [][]*Parameter{op.item.Parameters, op.operation.Parameters} {
+		for _, value := range values {
+			if _, param := NewResolver(&r).parameter("", value); param != nil && param.In == "path" {
+				params[param.Name] = param
+			}
+		}
+	}
+
+	bindings := make([]*PathBinding, 0)
+	for i, segment := range strings.Split(strings.TrimPrefix(path, "/"), "/") {
+		for _, match := range templateParam.FindAllStringSubmatch(segment, -1) {
+			param := params[match[1]]
+			if param == nil {
+				return nil, errors.Errorf("path %q has no parameter declared for %q", path, match[0])
+			}
+			binding := &PathBinding{Name: param.Name, Segment: i, Type: "string"}
+			if schema := NewResolver(&r).schema(param.Schema); schema != nil {
+				if schema.Type != "" {
+					binding.Type = schema.Type
+				}
+				binding.Format = schema.Format
+			}
+			binding.GoType = goPathType(binding.Type, binding.Format)
+			bindings = append(bindings, binding)
+		}
+	}
+	return bindings, nil
+}
+
+// goPathType returns the Go type of a path parameter of the schema type and
+// format. Structured types are passed as strings since path segments hold
+// their serialized form.
+func goPathType(schemaType string, format string) string {
+	switch {
+	case schemaType == "integer" && format == "int32":
+		return "int32"
+	case schemaType == "integer":
+		return "int64"
+	case schemaType == "number" && format == "float":
+		return "float32"
+	case schemaType == "number":
+		return "float64"
+	case schemaType == "boolean":
+		return "bool"
+	case schemaType == "string" && (format == "date" || format == "date-time"):
+		return "time.Time"
+	}
+	return "string"
+}
@@ -0,0 +1,127 @@
+package oas
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+)
+
+type PathBindingSuite struct {
+	suite.Suite
+}
+
+// pathBindingPatch is the patch of the petstore fixture adding a path with
+// parameters sharing a segment.
+const pathBindingPatch = `
+paths:
+  /owners/{ownerId}/pets/{petId}.{format}:
+    parameters:
+      - name: ownerId
+        in: path
+        required: true
+        schema:
+          type: string
+          format: uuid
+      - name: petId
+        in: path
+        required: true
+        schema:
+          type: string
+    get:
+      parameters:
+        - $ref: "#/components/parameters/PetId"
+        - name: format
+          in: path
+          required: true
+        - name: petId
+          in: query
+          schema:
+            type: boolean
+      responses:
+        "200":
+          description: The pet.
+    delete:
+      responses:
+        "204":
+          description: Deleted.
+components:
+  parameters:
+    PetId:
+      name: petId
+      in: path
+      required: true
+      schema:
+        $ref: "#/components/schemas/Id"
+  schemas:
+    Id:
+      type: integer
+      format: int64
+`
+
+func (r *PathBindingSuite) TestPathBindings() {
+	testCases := []struct {
+		path     string
+		method   string
+		expected []*PathBinding
+		isErr    bool
+	}{
+		{
+			"/pets/{petId}",
+			"get",
+			[]*PathBinding{
+				{Name: "petId", Segment: 1, Type: "integer", GoType: "int64"},
+			},
+			false,
+		},
+		{
+			"/owners/{ownerId}/pets/{petId}.{format}",
+			"get",
+			[]*PathBinding{
+				{Name: "ownerId", Segment: 1, Type: "string", Format: "uuid", GoType: "string"},
+				{Name: "petId", Segment: 3, Type: "integer", Format: "int64", GoType: "int64"},
+				{Name: "format", Segment: 3, Type: "string", GoType: "string"},
+			},
+			false,
+		},
+		{"/owners/{ownerId}/pets/{petId}.{format}", "delete", nil, true},
+		{"/owners/{ownerId}/pets/{petId}.{format}", "put", nil, true},
+	}
+
+	for i, testCase := range testCases {
+		failMsg := "test case %d failed"
+		bindings, err := petstore(r.T(), pathBindingPatch).PathBindings(testCase.path, testCase.method)
+		if testCase.isErr {
+			assert.NotNil(r.T(), err, failMsg, i)
+			continue
+		}
+		assert.Nil(r.T(), err, failMsg, i)
+		assert.Equal(r.T(), testCase.expected, bindings, failMsg, i)
+	}
+}
+
+func (r *PathBindingSuite) TestGoPathType() {
+	testCases := []struct {
+		schemaType string
+		format     string
+		expected   string
+	}{
+		{"integer", "int32", "int32"},
+		{"integer", "", "int64"},
+		{"number", "float", "float32"},
+		{"number", "double", "float64"},
+		{"boolean", "", "bool"},
+		{"string", "date-time", "time.Time"},
+		{"string", "uuid", "string"},
+		{"array", "", "string"},
+	}
+
+	for i, testCase := range testCases {
+		failMsg := "test case %d failed"
+		assert.Equal(r.T(), testCase.expected, goPathType(testCase.schemaType, testCase.format), failMsg, i)
+	}
+}
+
+func TestPathBindingSuite(t *testing.T) {
+	suite.Run(t, new(PathBindingSuite))
+}
@@ -4,7 +4,7 @@ import (
 	"encoding/json"
 
 	"github.com/pkg/errors"
-	"gopkg.in/yaml.v2"
+	"gopkg.in/yaml.v3"
 )
 
 // PathItem describes the operations available on a single path. A Path Item
@@ -91,22 +91,7 @@ func (r PathItem) MarshalJSON() ([]byte, error) {
 
 // UnmarshalJSON parses the JSON-encoded data and stores the result.
 func (r *PathItem) UnmarshalJSON(data []byte) error {
-	return r.UnmarshalYAML(func(in interface{}) error {
-		obj := make(map[string]interface{})
-		if err := json.Unmarshal(data, &obj); err != nil {
-			return errors.WithStack(err)
-		}
-
-		rbytes, err := yaml.Marshal(obj)
-		if err != nil {
-			return errors.WithStack(err)
-		}
-
-		if err := yaml.Unmarshal(rbytes, in); err != nil {
-			return errors.WithStack(err)
-		}
-		return nil
-	})
+	return decodeJSON(data, r)
 }
 
 // MarshalYAML returns the YAML encoding.
@@ -173,7 +158,13 @@ func (r PathItem) MarshalYAML() (interface{}, error) {
 }
 
 // UnmarshalYAML parses the YAML-encoded data and stores the result.
-func (r *PathItem) UnmarshalYAML(unmarshal func(interface{}) error) error {
+func (r *PathItem) UnmarshalYAML(node *yaml.Node) error {
+	return decodeYAML(node, r)
+}
+
+// unmarshalValue stores the result of the unmarshal function, which decodes
+// either JSON or YAML data.
+func (r *PathItem) unmarshalValue(d *valueDecoder, unmarshal func(interface{}) error) error {
 	obj := make(map[string]interface{})
 	if err := unmarshal(&obj); err != nil {
 		return errors.WithStack(err)
@@ -197,122 +188,82 @@ func (r *PathItem) UnmarshalYAML(unmarshal func(interface{}) error) error {
 		}
 	}
 
-	if value, ok := obj["get"]; ok {
-		rbytes, err := yaml.Marshal(value)
-		if err != nil {
-			return errors.WithStack(err)
-		}
+	if raw, ok := obj["get"]; ok {
 		value := Operation{}
-		if err := yaml.Unmarshal(rbytes, &value); err != nil {
-			return errors.WithStack(err)
+		if err := d.decodeMember("get", raw, &value); err != nil {
+			return err
 		}
 		r.Get = &value
 	}
 
-	if value, ok := obj["put"]; ok {
-		rbytes, err := yaml.Marshal(value)
-		if err != nil {
-			return errors.WithStack(err)
-		}
+	if raw, ok := obj["put"]; ok {
 		value := Operation{}
-		if err := yaml.Unmarshal(rbytes, &value); err != nil {
-			return errors.WithStack(err)
+		if err := d.decodeMember("put", raw, &value); err != nil {
+			return err
 		}
 		r.Put = &value
 	}
 
-	if value, ok := obj["post"]; ok {
-		rbytes, err := yaml.Marshal(value)
-		if err != nil {
-			return errors.WithStack(err)
-		}
+	if raw, ok := obj["post"]; ok {
 		value := Operation{}
-		if err := yaml.Unmarshal(rbytes, &value); err != nil {
-			return errors.WithStack(err)
+		if err := d.decodeMember("post", raw, &value); err != nil {
+			return err
 		}
 		r.Post = &value
 	}
 
-	if value, ok := obj["delete"]; ok {
-		rbytes, err := yaml.Marshal(value)
-		if err != nil {
-			return errors.WithStack(err)
-		}
+	if raw, ok := obj["delete"]; ok {
 		value := Operation{}
-		if err := yaml.Unmarshal(rbytes, &value); err != nil {
-			return errors.WithStack(err)
+		if err := d.decodeMember("delete", raw, &value); err != nil {
+			return err
 		}
 		r.Delete = &value
 	}
 
-	if value, ok := obj["options"]; ok {
-		rbytes, err := yaml.Marshal(value)
-		if err != nil {
-			return errors.WithStack(err)
-		}
+	if raw, ok := obj["options"]; ok {
 		value := Operation{}
-		if err := yaml.Unmarshal(rbytes, &value); err != nil {
-			return errors.WithStack(err)
+		if err := d.decodeMember("options", raw, &value); err != nil {
+			return err
 		}
 		r.Options = &value
 	}
 
-	if value, ok := obj["head"]; ok {
-		rbytes, err := yaml.Marshal(value)
-		if err != nil {
-			return errors.WithStack(err)
-		}
+	if raw, ok := obj["head"]; ok {
 		value := Operation{}
-		if err := yaml.Unmarshal(rbytes, &value); err != nil {
-			return errors.WithStack(err)
+		if err := d.decodeMember("head", raw, &value); err != nil {
+			return err
 		}
 		r.Head = &value
 	}
 
-	if value, ok := obj["patch"]; ok {
-		rbytes, err := yaml.Marshal(value)
-		if err != nil {
-			return errors.WithStack(err)
-		}
+	if raw, ok := obj["patch"]; ok {
 		value := Operation{}
-		if err := yaml.Unmarshal(rbytes, &value); err != nil {
-			return errors.WithStack(err)
+		if err := d.decodeMember("patch", raw, &value); err != nil {
+			return err
 		}
 		r.Patch = &value
 	}
 
-	if value, ok := obj["trace"]; ok {
-		rbytes, err := yaml.Marshal(value)
-		if err != nil {
-			return errors.WithStack(err)
-		}
+	if raw, ok := obj["trace"]; ok {
 		value := Operation{}
-		if err := yaml.Unmarshal(rbytes, &value); err != nil {
-			return errors.WithStack(err)
+		if err := d.decodeMember("trace", raw, &value); err != nil {
+			return err
 		}
 		r.Trace = &value
 	}
 
-	if value, ok := obj["servers"]; ok {
-		rbytes, err := yaml.Marshal(value)
-		if err != nil {
-			return errors.WithStack(err)
-		}
+	if raw, ok := obj["servers"]; ok {
 		value := make([]*Server, 0)
-		if err := yaml.Unmarshal(rbytes, &value); err != nil {
-			return errors.WithStack(err)
+		if err := d.decodeMember("servers", raw, &value); err != nil {
+			return err
 		}
 		r.Servers = value
 	}
 
-	if value, ok := obj["parameters"]; ok {
-		rbytes, err := yaml.Marshal(value)
-		if err != nil {
-			return errors.WithStack(err)
-		}
+	if raw, ok := obj["parameters"]; ok {
 		value := make([]*Parameter, 0)
-		if err := yaml.Unmarshal(rbytes, &value); err != nil {
-			return errors.WithStack(err)
+		if err := d.decodeMember("parameters", raw, &value); err != nil {
+			return err
 		}
 		r.Parameters = value
 	}
@@ -328,3 +279,55 @@ func (r *PathItem) UnmarshalYAML(unmarshal func(interface{}) error) error {
 
 	return nil
 }
+
+// methodOperation pairs an operation with the lowercase HTTP method it is
+// declared under.
+type methodOperation struct {
+	method    string
+	operation *Operation
+}
+
+// operations returns the operations declared on the path item in the order
+// the fields are defined by the specification.
+func (r PathItem) operations() []methodOperation {
+	candidates := []methodOperation{
+		{"get", r.Get},
+		{"put", r.Put},
+		{"post", r.Post},
+		{"delete", r.Delete},
+		{"options", r.Options},
+		{"head", r.Head},
+		{"patch", r.Patch},
+		{"trace", r.Trace},
+	}
+	ops := make([]methodOperation, 0, len(candidates))
+	for _, candidate := range candidates {
+		if candidate.operation != nil {
+			ops = append(ops, candidate)
+		}
+	}
+	return ops
+}
+
+// setOperation declares the operation under the lowercase HTTP method, a nil
+// operation removes the declared one.
+func (r *PathItem) setOperation(method string, operation *Operation) {
+	switch method {
+	case "get":
+		r.Get = operation
+	case "put":
+		r.Put = operation
+	case "post":
+		r.Post = operation
+	case "delete":
+		r.Delete = operation
+	case "options":
+		r.Options = operation
+	case "head":
+		r.Head = operation
+	case "patch":
+		r.Patch = operation
+	case "trace":
+		r.Trace = operation
+	}
+}
@@ -7,7 +7,7 @@ import (
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/suite"
-	"gopkg.in/yaml.v2"
+	"gopkg.in/yaml.v3"
 )
 
 type PathItemSuite struct {
@@ -26,7 +26,7 @@ func (r *PathItemSuite) TestPathItem() {
 					Description: "Returns pets based on ID",
 					Summary:     "Find pets by ID",
 					OperationID: "getPetsById",
-					Responses: map[string]*Response{
+					Responses: NewResponses(map[string]*Response{
 						"200": {
 							Description: "pet response",
 							Content: map[string]*MediaType{
@@ -50,7 +50,7 @@ func (r *PathItemSuite) TestPathItem() {
 								},
 							},
 						},
-					},
+					}),
 				},
 				Parameters: []*Parameter{
 					{
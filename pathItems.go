@@ -2,14 +2,81 @@ package oas
 
 import (
 	"encoding/json"
+	"sort"
 	"strings"
 
-	"github.com/pkg/errors"
-	"gopkg.in/yaml.v2"
+	"gopkg.in/yaml.v3"
 )
 
-// PathItems represents the collection of PathItem.
-type PathItems map[string]*PathItem
+// PathItems represents the collection of PathItem by their relative path,
+// in the order the paths were declared. Paths decoded from a document keep
+// the order they were written in, so the document is encoded back in the
+// author's order.
+type PathItems struct {
+	values *orderedValues
+}
+
+// NewPathItems returns the path items of the map in alphabetical order of
+// their paths.
+func NewPathItems(items map[string]*PathItem) PathItems {
+	keys := make([]string, 0, len(items))
+	for key := range items {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	r := PathItems{}
+	for _, key := range keys {
+		r.Set(key, items[key])
+	}
+	return r
+}
+
+// Len returns the number of path items.
+func (r PathItems) Len() int {
+	return r.values.len()
+}
+
+// Keys returns the paths in order.
+func (r PathItems) Keys() []string {
+	return r.values.keys()
+}
+
+// Get returns the path item of the path, or nil if there is none.
+func (r PathItems) Get(path string) *PathItem {
+	item, _ := r.Lookup(path)
+	return item
+}
+
+// Lookup returns the path item of the path and reports whether the path is
+// declared.
+func (r PathItems) Lookup(path string) (*PathItem, bool) {
+	value, ok := r.values.get(path)
+	item, _ := value.(*PathItem)
+	return item, ok
+}
+
+// Set sets the path item of the path. Paths not declared yet are appended.
+func (r *PathItems) Set(path string, item *PathItem) {
+	if r.values == nil {
+		r.values = newOrderedValues()
+	}
+	r.values.set(path, item)
+}
+
+// Delete removes the path.
+func (r *PathItems) Delete(path string) {
+	r.values.remove(path)
+}
+
+// orderedValues returns the values backing the path items.
+func (r *PathItems) orderedValues() *orderedValues {
+	return r.values
+}
+
+// setOrderedValues replaces the values backing the path items.
+func (r *PathItems) setOrderedValues(values *orderedValues) {
+	r.values = values
+}
 
 // MarshalJSON returns the JSON encoding.
 func (r PathItems) MarshalJSON() ([]byte, error) {
@@ -22,45 +89,41 @@ func (r PathItems) MarshalJSON() ([]byte, error) {
 
 // UnmarshalJSON parses the JSON-encoded data and stores the result.
 func (r *PathItems) UnmarshalJSON(data []byte) error {
-	return r.UnmarshalYAML(func(in interface{}) error {
-		obj := make(map[string]interface{})
-		if err := json.Unmarshal(data, &obj); err != nil {
-			return errors.WithStack(err)
-		}
-
-		rbytes, err := yaml.Marshal(obj)
-		if err != nil {
-			return errors.WithStack(err)
-		}
-
-		if err := yaml.Unmarshal(rbytes, in); err != nil {
-			return errors.WithStack(err)
-		}
-		return nil
-	})
+	return decodeJSON(data, r)
 }
 
 // MarshalYAML returns the YAML encoding.
 func (r PathItems) MarshalYAML() (interface{}, error) {
-	obj := make(map[string]interface{})
-	for k := range r {
+	obj := make(orderedMap, 0, r.Len())
+	for _, k := range r.Keys() {
 		if !strings.HasPrefix(strings.ToLower(k), "x-") {
-			obj[k] = r[k]
+			obj = append(obj, orderedEntry{key: k, value: r.Get(k)})
 		}
 	}
 	return obj, nil
 }
 
 // UnmarshalYAML parses the YAML-encoded data and stores the result.
-func (r *PathItems) UnmarshalYAML(unmarshal func(interface{}) error) error {
-	obj := make(map[string]*PathItem)
-	if err := unmarshal(&obj); err != nil {
-		return errors.WithStack(err)
+func (r *PathItems) UnmarshalYAML(node *yaml.Node) error {
+	return decodeYAML(node, r)
+}
+
+// unmarshalValue stores the result of the unmarshal function, which decodes
+// either JSON or YAML data.
+func (r *PathItems) unmarshalValue(d *valueDecoder, unmarshal func(interface{}) error) error {
+	obj, keys, err := d.members(unmarshal)
+	if err != nil {
+		return err
 	}
-	for k := range obj {
-		if !strings.HasPrefix(strings.ToLower(k), "x-") {
-			(*r)[k] = obj[k]
+	for _, k := range keys {
+		if strings.HasPrefix(strings.ToLower(k), "x-") {
+			continue
+		}
+		var value *PathItem
+		if err := d.decodeMember(k, obj[k], &value); err != nil {
+			return err
 		}
+		r.Set(k, value)
 	}
 	return nil
 }
@@ -0,0 +1,122 @@
+package oas
+
+import (
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// PathPrefixOptions describes the behavior of RewritePathPrefix.
+type PathPrefixOptions struct {
+	// Prefix describes the path prefix (e.g. "/api/v1").
+	Prefix string
+
+	// Strip removes the prefix from the paths instead of prepending it.
+	Strip bool
+
+	// Servers moves the prefix between the paths and the server URLs so the
+	// URLs of the operations stay the same, emulating the basePath of Swagger
+	// 2.0. Prepending the prefix removes it from the server URLs ending with
+	// it, stripping it appends it to every server URL and declares it as the
+	// server URL of documents without servers. The servers of callbacks are
+	// left untouched.
+	Servers bool
+}
+
+// RewritePathPrefix prepends the prefix to or strips it from every path of
+// the document, e.g. to mount a documented API under a gateway route. Link
+// operationRef references into the paths are updated. Stripping fails if a
+// path does not start with the prefix, and both directions fail if rewritten
+// paths collide; the document is left unchanged in that case.
+func (r *OpenAPI) RewritePathPrefix(opts PathPrefixOptions) error {
+	prefix := "/" + strings.Trim(opts.Prefix, "/")
+	if prefix == "/" {
+		return errors.New("missing path prefix")
+	}
+
+	renames := map[string]string{}
+	rewritten := PathItems{}
+	for _, path := range sortedKeys(r.Paths.PathItems) {
+		next := prefix + path
+		if path == "/" {
+			next = prefix
+		}
+		if opts.Strip {
+			if path != prefix && !strings.HasPrefix(path, prefix+"/") {
+				return errors.Errorf("path %q does not start with %q", path, prefix)
+			}
+			next = strings.TrimPrefix(path, prefix)
+			if next == "" {
+				next = "/"
+			}
+		}
+		if _, ok := rewritten.Lookup(next); ok {
+			return errors.Errorf("path %q collides after rewriting %q", next, path)
+		}
+		rewritten.Set(next, r.Paths.PathItems.Get(path))
+		renames[path] = next
+	}
+	if rewritten.Len() > 0 {
+		r.Paths.PathItems = rewritten
+	}
+
+	_ = walk(r, func(ptr string, node interface{}) error {
+		switch node := node.(type) {
+		case *Link:
+			node.OperationRef = renamePathRef(node.OperationRef, renames)
+		case *Server:
+			if opts.Servers && !isCallbackPointer(ptr) {
+				node.URL = rewriteServerURL(node.URL, prefix, opts.Strip)
+			}
+		}
+		return nil
+	})
+	if opts.Servers && opts.Strip && len(r.Servers) == 0 {
+		r.Servers = []*Server{{URL: prefix}}
+	}
+	return nil
+}
+
+// renamePathRef returns the operationRef with the path it points to renamed.
+// References to other locations are returned unchanged.
+func renamePathRef(ref string, renames map[string]string) string {
+	i := strings.Index(ref, "#/paths/")
+	if i < 0 {
+		return ref
+	}
+	tokens := splitPointer(ref[i+1:])
+	if len(tokens) < 2 {
+		return ref
+	}
+	next, ok := renames[tokens[1]]
+	if !ok {
+		return ref
+	}
+	return ref[:i+1] + join("/paths", append([]string{next}, tokens[2:]...)...)
+}
+
+// rewriteServerURL appends the prefix to the server URL when stripping it
+// from the paths, and removes it from the end of the URL otherwise.
+func rewriteServerURL(rawurl string, prefix string, strip bool) string {
+	trimmed := strings.TrimSuffix(rawurl, "/")
+	switch {
+	case strip:
+		return trimmed + prefix
+	case trimmed == prefix:
+		return "/"
+	case strings.HasSuffix(trimmed, prefix):
+		return strings.TrimSuffix(trimmed, prefix)
+	}
+	return rawurl
+}
+
+// isCallbackPointer reports whether the pointer addresses a location inside a
+// callback.
+func isCallbackPointer(ptr string) bool {
+	for _, token := range splitPointer(ptr) {
+		if token == "callbacks" {
+			return true
+		}
+	}
+	return false
+}
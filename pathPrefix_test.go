@@ -0,0 +1,84 @@
+package oas
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+)
+
+type PathPrefixSuite struct {
+	suite.Suite
+}
+
+// pathPrefixPatch is the patch of the petstore fixture adding the servers,
+// links and callbacks the suite rewrites.
+const pathPrefixPatch = `
+servers:
+  - url: https://api.example.com/v1
+  - url: https://staging.example.com/
+paths:
+  /pets:
+    get:
+      responses:
+        "200":
+          links:
+            pet:
+              operationRef: "#/paths/~1pets~1{petId}/get"
+      callbacks:
+        onEvent:
+          "{$request.body#/url}":
+            servers:
+              - url: https://hooks.example.com/v1
+  /pets/{petId}:
+    servers:
+      - url: /v1/
+`
+
+func (r *PathPrefixSuite) TestPrepend() {
+	doc := petstore(r.T(), pathPrefixPatch)
+	assert.Nil(r.T(), doc.RewritePathPrefix(PathPrefixOptions{Prefix: "v1/", Servers: true}))
+
+	assert.Equal(r.T(), []string{"/v1/pets", "/v1/pets/{petId}"}, sortedKeys(doc.Paths.PathItems))
+	assert.Equal(r.T(), "https://api.example.com", doc.Servers[0].URL)
+	assert.Equal(r.T(), "https://staging.example.com/", doc.Servers[1].URL)
+	assert.Equal(r.T(), "/", doc.Paths.PathItems.Get("/v1/pets/{petId}").Servers[0].URL)
+
+	get := doc.Paths.PathItems.Get("/v1/pets").Get
+	assert.Equal(r.T(), "#/paths/~1v1~1pets~1{petId}/get", get.Responses.Get("200").Links["pet"].OperationRef)
+	assert.Equal(r.T(), "https://hooks.example.com/v1", get.Callbacks["onEvent"].CallbackItems["{$request.body#/url}"].Servers[0].URL)
+}
+
+func (r *PathPrefixSuite) TestStrip() {
+	doc := petstore(r.T(), pathPrefixPatch)
+	assert.Nil(r.T(), doc.RewritePathPrefix(PathPrefixOptions{Prefix: "/pets", Strip: true, Servers: true}))
+	assert.Equal(r.T(), []string{"/", "/{petId}"}, sortedKeys(doc.Paths.PathItems))
+	assert.Equal(r.T(), "https://api.example.com/v1/pets", doc.Servers[0].URL)
+	assert.Equal(r.T(), "https://staging.example.com/pets", doc.Servers[1].URL)
+	assert.Equal(r.T(), "#/paths/~1{petId}/get", doc.Paths.PathItems.Get("/").Get.Responses.Get("200").Links["pet"].OperationRef)
+
+	doc = &OpenAPI{Paths: Paths{PathItems: NewPathItems(map[string]*PathItem{"/api/pets": {}})}}
+	assert.Nil(r.T(), RewritePathPrefixTransform(PathPrefixOptions{Prefix: "/api", Strip: true, Servers: true})(doc))
+	assert.Equal(r.T(), []*Server{{URL: "/api"}}, doc.Servers)
+}
+
+func (r *PathPrefixSuite) TestErrors() {
+	testCases := []PathPrefixOptions{
+		{Prefix: "/"},
+		{Prefix: "/v1", Strip: true},
+	}
+
+	for i, opts := range testCases {
+		failMsg := "test case %d failed"
+		doc := petstore(r.T(), pathPrefixPatch)
+		assert.NotNil(r.T(), doc.RewritePathPrefix(opts), failMsg, i)
+		assert.Equal(r.T(), petstore(r.T(), pathPrefixPatch), doc, failMsg, i)
+	}
+
+	doc := &OpenAPI{Paths: Paths{PathItems: NewPathItems(map[string]*PathItem{"/v1": {}, "/v1/": {}})}}
+	assert.NotNil(r.T(), doc.RewritePathPrefix(PathPrefixOptions{Prefix: "/v1", Strip: true}))
+}
+
+func TestPathPrefixSuite(t *testing.T) {
+	suite.Run(t, new(PathPrefixSuite))
+}
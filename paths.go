@@ -2,9 +2,10 @@ package oas
 
 import (
 	"encoding/json"
+	"strings"
 
 	"github.com/pkg/errors"
-	"gopkg.in/yaml.v2"
+	"gopkg.in/yaml.v3"
 )
 
 // Paths holds the relative paths to the individual endpoints and their
@@ -50,41 +51,33 @@ func (r Paths) MarshalJSON() ([]byte, error) {
 
 // UnmarshalJSON parses the JSON-encoded data and stores the result.
 func (r *Paths) UnmarshalJSON(data []byte) error {
-	return r.UnmarshalYAML(func(in interface{}) error {
-		obj := make(map[string]interface{})
-		if err := json.Unmarshal(data, &obj); err != nil {
-			return errors.WithStack(err)
-		}
-
-		rbytes, err := yaml.Marshal(obj)
-		if err != nil {
-			return errors.WithStack(err)
-		}
-
-		if err := yaml.Unmarshal(rbytes, in); err != nil {
-			return errors.WithStack(err)
-		}
-		return nil
-	})
+	return decodeJSON(data, r)
 }
 
-// MarshalYAML returns the YAML encoding.
+// MarshalYAML returns the YAML encoding. Paths are encoded in order,
+// followed by the extensions.
 func (r Paths) MarshalYAML() (interface{}, error) {
-	obj := make(map[string]interface{})
+	obj := make(orderedMap, 0, r.PathItems.Len()+len(r.Extensions))
 
-	for key, val := range r.PathItems {
-		obj[key] = val
+	for _, key := range r.PathItems.Keys() {
+		obj = append(obj, orderedEntry{key: key, value: r.PathItems.Get(key)})
 	}
 
-	for key, val := range r.Extensions {
-		obj[key] = val
+	for _, key := range sortedStrings(r.Extensions) {
+		obj = append(obj, orderedEntry{key: key, value: r.Extensions[key]})
 	}
 
 	return obj, nil
 }
 
 // UnmarshalYAML parses the YAML-encoded data and stores the result.
-func (r *Paths) UnmarshalYAML(unmarshal func(interface{}) error) error {
+func (r *Paths) UnmarshalYAML(node *yaml.Node) error {
+	return decodeYAML(node, r)
+}
+
+// unmarshalValue stores the result of the unmarshal function, which decodes
+// either JSON or YAML data.
+func (r *Paths) unmarshalValue(d *valueDecoder, unmarshal func(interface{}) error) error {
 	obj := make(map[string]interface{})
 	if err := unmarshal(&obj); err != nil {
 		return errors.WithStack(err)
@@ -95,7 +88,7 @@ func (r *Paths) UnmarshalYAML(unmarshal func(interface{}) error) error {
 		return errors.WithStack(err)
 	}
 
-	if len(paths) > 0 {
+	if paths.Len() > 0 {
 		r.PathItems = paths
 	}
 
@@ -110,3 +103,41 @@ func (r *Paths) UnmarshalYAML(unmarshal func(interface{}) error) error {
 
 	return nil
 }
+
+// pathOperation pairs an operation with the path and the lowercase HTTP
+// method it is declared under.
+type pathOperation struct {
+	path      string
+	method    string
+	item      *PathItem
+	operation *Operation
+}
+
+// operations returns every operation declared in the paths, ordered by path
+// and then by the order the methods are defined by the specification.
+func (r Paths) operations() []pathOperation {
+	ops := make([]pathOperation, 0)
+	for _, path := range sortedKeys(r.PathItems) {
+		item := r.PathItems.Get(path)
+		if item == nil {
+			continue
+		}
+		for _, op := range item.operations() {
+			ops = append(ops, pathOperation{path, op.method, item, op.operation})
+		}
+	}
+	return ops
+}
+
+// operation returns the operation declared under the path and lowercase HTTP
+// method, or an error if there is none.
+func (r Paths) operation(path string, method string) (pathOperation, error) {
+	if item := r.PathItems.Get(path); item != nil {
+		for _, op := range item.operations() {
+			if op.method == method {
+				return pathOperation{path, method, item, op.operation}, nil
+			}
+		}
+	}
+	return pathOperation{}, errors.Errorf("operation %s %s not found", strings.ToUpper(method), path)
+}
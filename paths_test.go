@@ -7,7 +7,7 @@ import (
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/suite"
-	"gopkg.in/yaml.v2"
+	"gopkg.in/yaml.v3"
 )
 
 type PathsSuite struct {
@@ -22,11 +22,11 @@ func (r *PathsSuite) TestPaths() {
 		{
 			false,
 			&Paths{
-				PathItems: PathItems{
+				PathItems: NewPathItems(map[string]*PathItem{
 					"/pets": {
 						Get: &Operation{
 							Description: "Returns all pets from the system that the user has access to",
-							Responses: map[string]*Response{
+							Responses: NewResponses(map[string]*Response{
 								"200": {
 									Description: "A list of pets.",
 									Content: map[string]*MediaType{
@@ -40,10 +40,10 @@ func (r *PathsSuite) TestPaths() {
 										},
 									},
 								},
-							},
+							}),
 						},
 					},
-				},
+				}),
 				Extensions: Extensions{
 					"x-unit": map[string]interface{}{
 						"unit": "test",
@@ -52,6 +52,15 @@ func (r *PathsSuite) TestPaths() {
 				},
 			},
 		},
+		{
+			false,
+			&Paths{
+				PathItems: NewPathItems(map[string]*PathItem{
+					"/pets": {Summary: "Pets"},
+				}),
+				Extensions: Extensions{"x-generated": true},
+			},
+		},
 	}
 
 	for i, testCase := range testCases {
@@ -0,0 +1,71 @@
+package oas
+
+import (
+	"io/ioutil"
+	"path/filepath"
+	"testing"
+
+	"gopkg.in/yaml.v3"
+)
+
+// petstore returns the document of testdata/petstore.yaml, the fixture the
+// suites share, with the YAML merge patches applied in turn. Patches merge
+// into the fixture as RFC 7386 merge patches do into JSON documents:
+// mappings are merged key by key, null removes a key and any other value
+// replaces the one of the fixture. Empty patches leave the fixture as is.
+// Every call returns a new document.
+func petstore(t *testing.T, patches ...string) *OpenAPI {
+	t.Helper()
+	data, err := ioutil.ReadFile(filepath.Join("testdata", "petstore.yaml"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	root := &yaml.Node{}
+	if err := yaml.Unmarshal(data, root); err != nil {
+		t.Fatal(err)
+	}
+	for _, patch := range patches {
+		node := &yaml.Node{}
+		if err := yaml.Unmarshal([]byte(patch), node); err != nil {
+			t.Fatal(err)
+		}
+		if len(node.Content) == 0 {
+			continue
+		}
+		root.Content[0] = mergePatch(root.Content[0], node.Content[0])
+	}
+
+	doc := &OpenAPI{}
+	if err := root.Decode(doc); err != nil {
+		t.Fatal(err)
+	}
+	return doc
+}
+
+// mergePatch returns the node with the merge patch applied, keeping the
+// order of the keys of the node and appending new keys.
+func mergePatch(node *yaml.Node, patch *yaml.Node) *yaml.Node {
+	if patch.Kind != yaml.MappingNode {
+		return patch
+	}
+	if node == nil || node.Kind != yaml.MappingNode {
+		node = &yaml.Node{Kind: yaml.MappingNode, Tag: "!!map"}
+	}
+	for i := 0; i+1 < len(patch.Content); i += 2 {
+		key, value := patch.Content[i], patch.Content[i+1]
+		j := 0
+		for j < len(node.Content) && node.Content[j].Value != key.Value {
+			j += 2
+		}
+		switch {
+		case value.Tag == "!!null" && j < len(node.Content):
+			node.Content = append(node.Content[:j], node.Content[j+2:]...)
+		case value.Tag == "!!null":
+		case j < len(node.Content):
+			node.Content[j+1] = mergePatch(node.Content[j+1], value)
+		default:
+			node.Content = append(node.Content, key, mergePatch(nil, value))
+		}
+	}
+	return node
+}
@@ -0,0 +1,232 @@
+package oas
+
+import (
+	"fmt"
+	"log"
+	"net/http"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// PreloadExtension names the specification extension of a response listing
+// the related resources clients should fetch early, sent as Link headers
+// and optionally pushed over HTTP/2.
+const PreloadExtension = "x-preload"
+
+// Relations of resource hints.
+const (
+	RelPreload       = "preload"
+	RelPrefetch      = "prefetch"
+	RelPreconnect    = "preconnect"
+	RelDNSPrefetch   = "dns-prefetch"
+	RelModulePreload = "modulepreload"
+)
+
+// ResourceHint describes a related resource of a response.
+type ResourceHint struct {
+	// Href describes the URL of the resource. Parameters of the path template
+	// of the operation (e.g. "/pets/{petId}/photo") are replaced with the
+	// values of the request.
+	Href string `json:"href" yaml:"href"`
+
+	// Rel describes the relation of the hint, one of RelPreload,
+	// RelPrefetch, RelPreconnect, RelDNSPrefetch and RelModulePreload.
+	// Defaults to RelPreload.
+	Rel string `json:"rel,omitempty" yaml:"rel,omitempty"`
+
+	// As describes the destination of the preloaded resource (e.g. "style",
+	// "script", "image", "fetch"), required by RelPreload.
+	As string `json:"as,omitempty" yaml:"as,omitempty"`
+
+	// Type describes the media type of the resource.
+	Type string `json:"type,omitempty" yaml:"type,omitempty"`
+
+	// CrossOrigin describes the CORS mode of the fetch, "anonymous" or
+	// "use-credentials".
+	CrossOrigin string `json:"crossorigin,omitempty" yaml:"crossorigin,omitempty"`
+}
+
+// validate checks that the hint is well formed.
+func (r ResourceHint) validate() error {
+	if r.Href == "" {
+		return errors.New("missing href")
+	}
+	switch r.Rel {
+	case "", RelPreload:
+		if r.As == "" {
+			return errors.Errorf("preload of %s is missing as", r.Href)
+		}
+	case RelPrefetch, RelPreconnect, RelDNSPrefetch, RelModulePreload:
+	default:
+		return errors.Errorf("unknown relation %q of %s", r.Rel, r.Href)
+	}
+	switch r.CrossOrigin {
+	case "", "anonymous", "use-credentials":
+	default:
+		return errors.Errorf("unknown crossorigin %q of %s", r.CrossOrigin, r.Href)
+	}
+	return nil
+}
+
+// LinkValue returns the value of the Link header announcing the hint with
+// the path parameters replaced (e.g. `</app.css>; rel=preload; as=style`).
+func (r ResourceHint) LinkValue(params map[string]string) string {
+	rel := r.Rel
+	if rel == "" {
+		rel = RelPreload
+	}
+	value := fmt.Sprintf("<%s>; rel=%s", r.href(params), rel)
+	if r.As != "" {
+		value += "; as=" + r.As
+	}
+	if r.Type != "" {
+		value += fmt.Sprintf("; type=%q", r.Type)
+	}
+	if r.CrossOrigin != "" {
+		value += "; crossorigin=" + r.CrossOrigin
+	}
+	return value
+}
+
+// href returns the URL of the resource with the path parameters replaced.
+// Parameters without a value are left unchanged.
+func (r ResourceHint) href(params map[string]string) string {
+	return templateParam.ReplaceAllStringFunc(r.Href, func(param string) string {
+		if value, ok := params[param[1:len(param)-1]]; ok {
+			return value
+		}
+		return param
+	})
+}
+
+// Preload returns the resource hints declared by the x-preload extension,
+// or nil if the extension is absent.
+func (r Extensions) Preload() ([]*ResourceHint, error) {
+	hints := make([]*ResourceHint, 0)
+	ok, err := r.decode(PreloadExtension, &hints)
+	if !ok || err != nil {
+		return nil, err
+	}
+	for _, hint := range hints {
+		if hint == nil {
+			return nil, errors.Errorf("%s: missing hint", PreloadExtension)
+		}
+		if err := hint.validate(); err != nil {
+			return nil, errors.Wrap(err, PreloadExtension)
+		}
+	}
+	return hints, nil
+}
+
+// SetPreload declares the resource hints with the x-preload extension, or
+// removes the extension when empty.
+func (r *Extensions) SetPreload(hints []*ResourceHint) {
+	if len(hints) == 0 {
+		delete(*r, PreloadExtension)
+		return
+	}
+	setExtension(r, PreloadExtension, hints)
+}
+
+// PreloadLinks emits Link headers for the resource hints of the response
+// of the matched operation, so clients fetch related resources early.
+// With Push set, preloaded resources of the same origin are also pushed
+// when the connection supports HTTP/2 server push.
+type PreloadLinks struct {
+	// Doc describes the document references of the responses are resolved
+	// against.
+	Doc *OpenAPI
+
+	// Push describes whether preloaded resources are pushed over HTTP/2.
+	Push bool
+
+	// Report is called for every malformed x-preload extension. When nil,
+	// they are logged with the standard logger.
+	Report func(req *http.Request, err error)
+}
+
+// Middleware returns a handler which adds the Link headers when the
+// response header is written. It must run inside the router middleware,
+// requests carrying no route are passed through unchanged.
+func (r PreloadLinks) Middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		if route, ok := RouteFromContext(req.Context()); ok {
+			w = &preloadWriter{ResponseWriter: w, links: r, req: req, route: route}
+		}
+		next.ServeHTTP(w, req)
+	})
+}
+
+// preloadWriter implements the response writer of the PreloadLinks
+// middleware.
+type preloadWriter struct {
+	http.ResponseWriter
+	links       PreloadLinks
+	req         *http.Request
+	route       *Route
+	wroteHeader bool
+}
+
+// WriteHeader adds the Link headers of the response before sending the
+// header.
+func (w *preloadWriter) WriteHeader(status int) {
+	if w.wroteHeader {
+		return
+	}
+	w.wroteHeader = true
+
+	response := declaredResponse(w.route.Operation.Responses, status)
+	if response != nil && w.links.Doc != nil {
+		_, response = NewResolver(w.links.Doc).response("", response)
+	}
+	if response != nil {
+		hints, err := response.Extensions.Preload()
+		if err != nil {
+			if w.links.Report != nil {
+				w.links.Report(w.req, err)
+			} else {
+				log.Printf("oas: %v", err)
+			}
+		}
+		w.announce(hints)
+	}
+	w.ResponseWriter.WriteHeader(status)
+}
+
+// announce adds the Link headers of the hints and pushes the preloaded
+// resources of the same origin.
+func (w *preloadWriter) announce(hints []*ResourceHint) {
+	existing := strings.Join(w.Header()["Link"], ", ")
+	pusher, push := w.ResponseWriter.(http.Pusher)
+	for _, hint := range hints {
+		value := hint.LinkValue(w.route.PathParams)
+		if !strings.Contains(existing, value) {
+			w.Header().Add("Link", value)
+		}
+		href := hint.href(w.route.PathParams)
+		if !w.links.Push || !push || (hint.Rel != "" && hint.Rel != RelPreload) || !strings.HasPrefix(href, "/") || strings.HasPrefix(href, "//") {
+			continue
+		}
+		if err := pusher.Push(href, nil); err != nil && err != http.ErrNotSupported {
+			if w.links.Report != nil {
+				w.links.Report(w.req, errors.WithStack(err))
+			}
+		}
+	}
+}
+
+// Write writes the body, implicitly sending a 200 header first.
+func (w *preloadWriter) Write(b []byte) (int, error) {
+	if !w.wroteHeader {
+		w.WriteHeader(http.StatusOK)
+	}
+	return w.ResponseWriter.Write(b)
+}
+
+// Flush implements http.Flusher if the wrapped writer does.
+func (w *preloadWriter) Flush() {
+	if flusher, ok := w.ResponseWriter.(http.Flusher); ok {
+		flusher.Flush()
+	}
+}
@@ -0,0 +1,144 @@
+package oas
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+)
+
+type PreloadSuite struct {
+	suite.Suite
+}
+
+type pushRecorder struct {
+	*httptest.ResponseRecorder
+	pushed []string
+}
+
+func (r *pushRecorder) Push(target string, opts *http.PushOptions) error {
+	r.pushed = append(r.pushed, target)
+	return nil
+}
+
+// preloadPatch is the patch of the petstore fixture declaring the resources
+// to preload with the pet.
+const preloadPatch = `
+paths:
+  /pets/{petId}:
+    get:
+      responses:
+        "200":
+          x-preload:
+            - href: /pets/{petId}/photo
+              as: image
+              type: image/png
+            - href: /app.css
+              as: style
+            - href: https://cdn.example.com
+              rel: preconnect
+              crossorigin: anonymous
+        default:
+          $ref: "#/components/responses/Error"
+components:
+  responses:
+    Error:
+      description: An error.
+      x-preload:
+        - href: /error.css
+`
+
+func (r *PreloadSuite) TestPreload() {
+	testCases := []struct {
+		exts     Extensions
+		expected []*ResourceHint
+		err      bool
+	}{
+		{Extensions{}, nil, false},
+		{
+			Extensions{PreloadExtension: []interface{}{
+				map[string]interface{}{"href": "/app.js", "rel": "modulepreload"},
+			}},
+			[]*ResourceHint{{Href: "/app.js", Rel: RelModulePreload}},
+			false,
+		},
+		{Extensions{PreloadExtension: []interface{}{map[string]interface{}{"href": "/app.css"}}}, nil, true},
+		{Extensions{PreloadExtension: []interface{}{map[string]interface{}{"href": "/a", "rel": "next"}}}, nil, true},
+		{Extensions{PreloadExtension: []interface{}{map[string]interface{}{"rel": "prefetch"}}}, nil, true},
+		{Extensions{PreloadExtension: "/app.css"}, nil, true},
+	}
+
+	for i, testCase := range testCases {
+		failMsg := "test case %d failed"
+		hints, err := testCase.exts.Preload()
+		assert.Equal(r.T(), testCase.err, err != nil, failMsg, i+1)
+		assert.Equal(r.T(), testCase.expected, hints, failMsg, i+1)
+	}
+
+	assert.Equal(r.T(),
+		`</pets/7/photo>; rel=preload; as=image; type="image/png"`,
+		ResourceHint{Href: "/pets/{petId}/photo", As: "image", Type: "image/png"}.LinkValue(map[string]string{"petId": "7"}),
+	)
+}
+
+func (r *PreloadSuite) TestMiddleware() {
+	testCases := []struct {
+		status int
+		push   bool
+		links  []string
+		pushed []string
+		errs   int
+	}{
+		{
+			http.StatusOK,
+			false,
+			[]string{
+				`</pets/7/photo>; rel=preload; as=image; type="image/png"`,
+				"</app.css>; rel=preload; as=style",
+				"<https://cdn.example.com>; rel=preconnect; crossorigin=anonymous",
+			},
+			nil,
+			0,
+		},
+		{
+			http.StatusOK,
+			true,
+			[]string{
+				`</pets/7/photo>; rel=preload; as=image; type="image/png"`,
+				"</app.css>; rel=preload; as=style",
+				"<https://cdn.example.com>; rel=preconnect; crossorigin=anonymous",
+			},
+			[]string{"/pets/7/photo", "/app.css"},
+			0,
+		},
+		{http.StatusNotFound, true, nil, nil, 1},
+	}
+
+	for i, testCase := range testCases {
+		failMsg := "test case %d failed"
+		doc := petstore(r.T(), preloadPatch)
+		errs := make([]error, 0)
+		links := PreloadLinks{
+			Doc:    doc,
+			Push:   testCase.push,
+			Report: func(req *http.Request, err error) { errs = append(errs, err) },
+		}
+		router := &Router{Doc: doc}
+		recorder := &pushRecorder{ResponseRecorder: httptest.NewRecorder()}
+		handler := http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+			w.WriteHeader(testCase.status)
+		})
+		router.Middleware(links.Middleware(handler)).
+			ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/pets/7", nil))
+		assert.Equal(r.T(), testCase.status, recorder.Code, failMsg, i+1)
+		assert.Equal(r.T(), testCase.links, recorder.Header()["Link"], failMsg, i+1)
+		assert.Equal(r.T(), testCase.pushed, recorder.pushed, failMsg, i+1)
+		assert.Len(r.T(), errs, testCase.errs, failMsg, i+1)
+	}
+}
+
+func TestPreloadSuite(t *testing.T) {
+	suite.Run(t, new(PreloadSuite))
+}
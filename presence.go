@@ -0,0 +1,218 @@
+package oas
+
+// Presence describes whether a property of an object may be absent and
+// whether it may be null, which decides how type generators represent it:
+// a plain value, a pointer, a sql.Null type or an optional wrapper.
+type Presence string
+
+// Presences of properties.
+const (
+	// PresenceRequired describes a property always present with a value,
+	// represented by a plain value.
+	PresenceRequired Presence = "required"
+
+	// PresenceNullable describes a property always present, whose value may
+	// be null, represented by a pointer or a sql.Null type.
+	PresenceNullable Presence = "nullable"
+
+	// PresenceOptional describes a property which may be absent but is
+	// never null, represented by a pointer or an optional wrapper.
+	PresenceOptional Presence = "optional"
+
+	// PresenceOptionalNullable describes a property which may be absent or
+	// null, three states which only an optional wrapper of a nullable value
+	// tells apart.
+	PresenceOptionalNullable Presence = "optional-nullable"
+)
+
+// Rules of the findings reported by AuditPresence.
+const (
+	RulePresenceZeroAmbiguous    = "presence-zero-ambiguous"
+	RulePresenceNullableRef      = "presence-nullable-ref"
+	RulePresenceNullableUntyped  = "presence-nullable-untyped"
+	RulePresenceReadOnlyRequired = "presence-read-only-required"
+	RulePresenceDefaultRequired  = "presence-default-required"
+)
+
+// FieldPresence describes the presence semantics of a property of an
+// object schema.
+type FieldPresence struct {
+	// Pointer describes the JSON Pointer of the schema of the property.
+	Pointer string `json:"pointer" yaml:"pointer"`
+
+	// Name describes the name of the property.
+	Name string `json:"name" yaml:"name"`
+
+	// Required describes whether the object requires the property.
+	Required bool `json:"required" yaml:"required"`
+
+	// Nullable describes whether the value of the property may be null,
+	// declared by the property or by the schema it references.
+	Nullable bool `json:"nullable" yaml:"nullable"`
+
+	// Presence describes the combination of Required and Nullable.
+	Presence Presence `json:"presence" yaml:"presence"`
+
+	// ZeroValid describes whether the zero value of the type of the
+	// property (false, 0, "", an empty array or object) is a valid value,
+	// in which case an absent property cannot be represented by the zero
+	// value without losing information.
+	ZeroValid bool `json:"zeroValid" yaml:"zeroValid"`
+}
+
+// Presence returns the presence semantics of the properties of the
+// component schemas and of the inline object schemas they hold, ordered by
+// schema name and then by property name, so type generators choose between
+// plain values, pointers, sql.Null types and optional wrappers
+// consistently.
+func (r OpenAPI) Presence() []*FieldPresence {
+	fields := make([]*FieldPresence, 0)
+	for _, property := range r.properties() {
+		fields = append(fields, property.field)
+	}
+	return fields
+}
+
+// propertyPresence pairs the presence of a property with its schema.
+type propertyPresence struct {
+	field  *FieldPresence
+	schema *Schema
+}
+
+// properties returns the presence of the properties of the component
+// schemas.
+func (r OpenAPI) properties() []propertyPresence {
+	properties := make([]propertyPresence, 0)
+	if r.Components == nil {
+		return properties
+	}
+	for _, name := range sortedKeys(r.Components.Schemas) {
+		r.presence(join("/components/schemas", name), r.Components.Schemas[name], &properties)
+	}
+	return properties
+}
+
+// presence adds the presence of the properties of the inline schema at the
+// pointer and of the inline schemas it holds.
+func (r OpenAPI) presence(ptr string, schema *Schema, properties *[]propertyPresence) {
+	if schema == nil || schema.Ref != "" {
+		return
+	}
+	required := make(map[string]bool, len(schema.Required))
+	for _, name := range schema.Required {
+		required[name] = true
+	}
+	for _, name := range sortedKeys(schema.Properties) {
+		property := schema.Properties.Get(name)
+		if property == nil {
+			continue
+		}
+		field := &FieldPresence{
+			Pointer:   join(ptr, "properties", name),
+			Name:      name,
+			Required:  required[name],
+			Nullable:  r.isNullable(property),
+			ZeroValid: r.zeroValid(property),
+		}
+		switch {
+		case field.Required && field.Nullable:
+			field.Presence = PresenceNullable
+		case field.Required:
+			field.Presence = PresenceRequired
+		case field.Nullable:
+			field.Presence = PresenceOptionalNullable
+		default:
+			field.Presence = PresenceOptional
+		}
+		*properties = append(*properties, propertyPresence{field, property})
+		r.presence(field.Pointer, property, properties)
+	}
+	r.presence(join(ptr, "items"), schema.Items, properties)
+	r.presence(join(ptr, "additionalProperties"), schema.AdditionalProperties, properties)
+}
+
+// isNullable reports whether the value of the schema may be null.
+func (r OpenAPI) isNullable(schema *Schema) bool {
+	if schema.Nullable {
+		return true
+	}
+	resolved := NewResolver(&r).schema(schema)
+	if resolved == nil {
+		return false
+	}
+	if resolved.Nullable {
+		return true
+	}
+	for _, value := range resolved.Enum {
+		if value == nil {
+			return true
+		}
+	}
+	return false
+}
+
+// zeroValid reports whether the zero value of the type of the schema
+// conforms to the schema. Schemas without a type have no zero value.
+func (r OpenAPI) zeroValid(schema *Schema) bool {
+	resolved := NewResolver(&r).schema(schema)
+	if resolved == nil {
+		return false
+	}
+	var zero interface{}
+	switch resolved.Type {
+	case "boolean":
+		zero = false
+	case "integer", "number":
+		zero = 0
+	case "string":
+		zero = ""
+	case "array":
+		zero = []interface{}{}
+	case "object":
+		zero = map[string]interface{}{}
+	default:
+		return false
+	}
+	validator, err := NewSchemaValidator(&r, resolved)
+	return err == nil && validator.Validate(zero) == nil
+}
+
+// AuditPresence reports the properties whose representation is ambiguous
+// for type generators: optional properties whose zero value is valid,
+// which a plain value with omitempty would drop, nullable properties
+// referencing another schema, whose nullable is ignored next to $ref in
+// OpenAPI 3.0, nullable properties without a type, required properties
+// which are read only and so absent from requests, and required properties
+// declaring a default, which never applies.
+func (r OpenAPI) AuditPresence() []*Finding {
+	findings := make([]*Finding, 0)
+	report := func(ptr string, rule string, severity Severity, message string) {
+		findings = append(findings, &Finding{Pointer: ptr, Rule: rule, Severity: severity, Message: message})
+	}
+	for _, entry := range r.properties() {
+		field, property := entry.field, entry.schema
+		resolved := NewResolver(&r).schema(property)
+		if field.Presence == PresenceOptional && field.ZeroValid {
+			report(field.Pointer, RulePresenceZeroAmbiguous, SeverityInfo,
+				"optional property accepts its zero value, an absent property cannot be told apart from it without a pointer or an optional wrapper")
+		}
+		if property.Ref != "" && property.Nullable {
+			report(field.Pointer, RulePresenceNullableRef, SeverityWarning,
+				"nullable is ignored next to $ref, wrap the reference in allOf to make the property nullable")
+		}
+		if field.Nullable && resolved != nil && resolved.Type == "" && len(resolved.AllOf) == 0 {
+			report(field.Pointer, RulePresenceNullableUntyped, SeverityWarning,
+				"nullable property declares no type, generators cannot pick a nullable representation")
+		}
+		if field.Required && resolved != nil && resolved.ReadOnly {
+			report(field.Pointer, RulePresenceReadOnlyRequired, SeverityInfo,
+				"required property is read only, it is required in responses but absent from requests")
+		}
+		if field.Required && property.Default != nil {
+			report(field.Pointer, RulePresenceDefaultRequired, SeverityWarning,
+				"required property declares a default, which never applies")
+		}
+	}
+	sortFindings(findings)
+	return findings
+}
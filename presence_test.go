@@ -0,0 +1,97 @@
+package oas
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+)
+
+type PresenceSuite struct {
+	suite.Suite
+}
+
+// presencePatch is the patch of the petstore fixture the suite inspects.
+const presencePatch = `
+components:
+  schemas:
+    Owner:
+      type: object
+      nullable: true
+      properties:
+        name:
+          type: string
+          minLength: 1
+    Pet:
+      required: [id, name, owner, status]
+      properties:
+        id:
+          type: integer
+          readOnly: true
+        name:
+          minLength: 1
+        owner:
+          $ref: "#/components/schemas/Owner"
+        status:
+          type: string
+          enum: [available, null]
+          default: available
+        age:
+          type: integer
+          minimum: 1
+        vaccine:
+          type: boolean
+        tag:
+          nullable: true
+        friend:
+          $ref: "#/components/schemas/Pet"
+          nullable: true
+        extra:
+          nullable: true
+        photos:
+          type: array
+          items:
+            type: object
+            properties:
+              url:
+                type: string
+                format: uri
+`
+
+func (r *PresenceSuite) TestPresence() {
+	assert.Equal(r.T(), []*FieldPresence{
+		{Pointer: "/components/schemas/Owner/properties/name", Name: "name", Presence: PresenceOptional},
+		{Pointer: "/components/schemas/Pet/properties/age", Name: "age", Presence: PresenceOptional},
+		{Pointer: "/components/schemas/Pet/properties/extra", Name: "extra", Nullable: true, Presence: PresenceOptionalNullable},
+		{Pointer: "/components/schemas/Pet/properties/friend", Name: "friend", Nullable: true, Presence: PresenceOptionalNullable},
+		{Pointer: "/components/schemas/Pet/properties/id", Name: "id", Required: true, Presence: PresenceRequired, ZeroValid: true},
+		{Pointer: "/components/schemas/Pet/properties/name", Name: "name", Required: true, Presence: PresenceRequired},
+		{Pointer: "/components/schemas/Pet/properties/owner", Name: "owner", Required: true, Nullable: true, Presence: PresenceNullable, ZeroValid: true},
+		{Pointer: "/components/schemas/Pet/properties/photos", Name: "photos", Presence: PresenceOptional, ZeroValid: true},
+		{Pointer: "/components/schemas/Pet/properties/photos/items/properties/url", Name: "url", Presence: PresenceOptional},
+		{Pointer: "/components/schemas/Pet/properties/status", Name: "status", Required: true, Nullable: true, Presence: PresenceNullable},
+		{Pointer: "/components/schemas/Pet/properties/tag", Name: "tag", Nullable: true, Presence: PresenceOptionalNullable, ZeroValid: true},
+		{Pointer: "/components/schemas/Pet/properties/vaccine", Name: "vaccine", Presence: PresenceOptional, ZeroValid: true},
+	}, petstore(r.T(), presencePatch).Presence())
+
+	assert.Equal(r.T(), []*FieldPresence{}, (&OpenAPI{}).Presence())
+}
+
+func (r *PresenceSuite) TestAuditPresence() {
+	actual := make([]string, 0)
+	for _, finding := range petstore(r.T(), presencePatch).AuditPresence() {
+		actual = append(actual, finding.Pointer+" "+finding.Rule)
+	}
+	assert.Equal(r.T(), []string{
+		"/components/schemas/Pet/properties/extra " + RulePresenceNullableUntyped,
+		"/components/schemas/Pet/properties/friend " + RulePresenceNullableRef,
+		"/components/schemas/Pet/properties/id " + RulePresenceReadOnlyRequired,
+		"/components/schemas/Pet/properties/photos " + RulePresenceZeroAmbiguous,
+		"/components/schemas/Pet/properties/status " + RulePresenceDefaultRequired,
+		"/components/schemas/Pet/properties/vaccine " + RulePresenceZeroAmbiguous,
+	}, actual)
+}
+
+func TestPresenceSuite(t *testing.T) {
+	suite.Run(t, new(PresenceSuite))
+}
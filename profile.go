@@ -0,0 +1,114 @@
+package oas
+
+import (
+	"encoding/json"
+	"sort"
+	"strconv"
+
+	"github.com/pkg/errors"
+)
+
+// ProfilesExtension names the specification extension restricting an object
+// to the listed profiles (e.g. ["internal"]), so a single source document
+// can serve several audiences. Objects without the extension belong to
+// every profile.
+const ProfilesExtension = "x-profiles"
+
+// ResolveProfiles removes the objects whose x-profiles extension lists none
+// of the active profiles, wherever they appear in the document (operations,
+// parameters, responses, schema properties, tags, servers, ...), and strips
+// the extension from the objects kept. Removed properties are dropped from
+// the required properties of their schema. The pointers of the removed
+// objects are returned in sorted order.
+func (r *OpenAPI) ResolveProfiles(profiles ...string) ([]string, error) {
+	active := map[string]bool{}
+	for _, profile := range profiles {
+		active[profile] = true
+	}
+
+	value, err := genericValue(r)
+	if err != nil {
+		return nil, err
+	}
+	removed := make([]string, 0)
+	value = resolveProfiles("", value, active, &removed)
+	rbytes, err := json.Marshal(value)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+	doc := &OpenAPI{}
+	if err := json.Unmarshal(rbytes, doc); err != nil {
+		return nil, errors.WithStack(err)
+	}
+	*r = *doc
+	sort.Strings(removed)
+	return removed, nil
+}
+
+// resolveProfiles returns the generic value without the objects excluded
+// from the active profiles, appending the pointers of the removed objects.
+func resolveProfiles(ptr string, value interface{}, active map[string]bool, removed *[]string) interface{} {
+	switch value := value.(type) {
+	case map[string]interface{}:
+		delete(value, ProfilesExtension)
+		dropped := map[string]bool{}
+		for _, key := range sortedStrings(value) {
+			if excludedProfile(value[key], active) {
+				delete(value, key)
+				*removed = append(*removed, join(ptr, key))
+				continue
+			}
+			if properties, ok := value[key].(map[string]interface{}); ok && key == "properties" {
+				for _, name := range sortedStrings(properties) {
+					if excludedProfile(properties[name], active) {
+						dropped[name] = true
+					}
+				}
+			}
+			value[key] = resolveProfiles(join(ptr, key), value[key], active, removed)
+		}
+		if required, ok := value["required"].([]interface{}); ok && len(dropped) > 0 {
+			kept := make([]interface{}, 0, len(required))
+			for _, name := range required {
+				if name, ok := name.(string); !ok || !dropped[name] {
+					kept = append(kept, name)
+				}
+			}
+			value["required"] = kept
+			if len(kept) == 0 {
+				delete(value, "required")
+			}
+		}
+		return value
+	case []interface{}:
+		kept := make([]interface{}, 0, len(value))
+		for i, item := range value {
+			if excludedProfile(item, active) {
+				*removed = append(*removed, join(ptr, strconv.Itoa(i)))
+				continue
+			}
+			kept = append(kept, resolveProfiles(join(ptr, strconv.Itoa(i)), item, active, removed))
+		}
+		return kept
+	}
+	return value
+}
+
+// excludedProfile reports whether the generic value is an object whose
+// x-profiles extension lists none of the active profiles.
+func excludedProfile(value interface{}, active map[string]bool) bool {
+	object, ok := value.(map[string]interface{})
+	if !ok {
+		return false
+	}
+	profiles, ok := object[ProfilesExtension].([]interface{})
+	if !ok {
+		return false
+	}
+	for _, profile := range profiles {
+		if profile, ok := profile.(string); ok && active[profile] {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,96 @@
+package oas
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+)
+
+type ProfileSuite struct {
+	suite.Suite
+}
+
+// profilePatch is the patch of the petstore fixture restricting parts of it
+// to profiles.
+const profilePatch = `
+servers:
+  - url: https://api.example.com
+  - url: https://internal.example.com
+    x-profiles: [internal]
+tags:
+  - name: pets
+  - name: admin
+    x-profiles: [internal, partner]
+paths:
+  /pets:
+    get:
+      parameters:
+        - name: debug
+          in: query
+          x-profiles: [internal]
+    delete:
+      x-profiles: [internal]
+      responses:
+        "204":
+          description: Deleted.
+components:
+  schemas:
+    Pet:
+      x-profiles: [public, internal]
+      required: [name, cost]
+      properties:
+        cost:
+          type: number
+          x-profiles: [internal]
+`
+
+func (r *ProfileSuite) TestResolveProfiles() {
+	doc := petstore(r.T(), profilePatch)
+	removed, err := doc.ResolveProfiles("public")
+	assert.Nil(r.T(), err)
+	assert.Equal(r.T(), []string{
+		"/components/schemas/Pet/properties/cost",
+		"/paths/~1pets/delete",
+		"/paths/~1pets/get/parameters/0",
+		"/servers/1",
+		"/tags/1",
+	}, removed)
+	assert.Len(r.T(), doc.Servers, 1)
+	assert.Equal(r.T(), []string{"name"}, doc.Components.Schemas["Pet"].Required)
+	assert.Nil(r.T(), doc.Components.Schemas["Pet"].Extensions[ProfilesExtension])
+	assert.Nil(r.T(), doc.Paths.PathItems.Get("/pets").Delete)
+	assert.Empty(r.T(), doc.Paths.PathItems.Get("/pets").Get.Parameters)
+
+	doc = petstore(r.T(), profilePatch)
+	removed, err = doc.ResolveProfiles("partner")
+	assert.Nil(r.T(), err)
+	assert.Contains(r.T(), removed, "/components/schemas/Pet")
+	assert.NotContains(r.T(), removed, "/tags/1")
+	assert.Equal(r.T(), "admin", doc.Tags[1].Name)
+	assert.Nil(r.T(), doc.Tags[1].Extensions[ProfilesExtension])
+
+	doc = petstore(r.T(), profilePatch)
+	removed, err = doc.ResolveProfiles("internal")
+	assert.Nil(r.T(), err)
+	assert.Empty(r.T(), removed)
+	assert.Equal(r.T(), []string{"name", "cost"}, doc.Components.Schemas["Pet"].Required)
+}
+
+func (r *ProfileSuite) TestWorkspaceProfiles() {
+	workspace := &Workspace{
+		Profiles: []string{"public"},
+		Read: func(ctx context.Context, location string) ([]byte, error) {
+			return MarshalCanonicalYAML(petstore(r.T(), profilePatch))
+		},
+	}
+	assert.Nil(r.T(), workspace.Load(context.Background(), "petstore.yaml"))
+	doc, ok := workspace.Document("petstore.yaml")
+	assert.True(r.T(), ok)
+	assert.Len(r.T(), doc.Servers, 1)
+}
+
+func TestProfileSuite(t *testing.T) {
+	suite.Run(t, new(ProfileSuite))
+}
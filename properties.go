@@ -0,0 +1,123 @@
+package oas
+
+import (
+	"encoding/json"
+	"sort"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Properties represents the properties of a schema by their name, in the
+// order the properties were declared. Properties decoded from a document keep
+// the order they were written in, so the document is encoded back in the
+// author's order.
+type Properties struct {
+	values *orderedValues
+}
+
+// NewProperties returns the properties of the map in alphabetical order of
+// their names.
+func NewProperties(properties map[string]*Schema) Properties {
+	keys := make([]string, 0, len(properties))
+	for key := range properties {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	r := Properties{}
+	for _, key := range keys {
+		r.Set(key, properties[key])
+	}
+	return r
+}
+
+// Len returns the number of properties.
+func (r Properties) Len() int {
+	return r.values.len()
+}
+
+// Keys returns the names in order.
+func (r Properties) Keys() []string {
+	return r.values.keys()
+}
+
+// Get returns the property of the name, or nil if there is none.
+func (r Properties) Get(name string) *Schema {
+	property, _ := r.Lookup(name)
+	return property
+}
+
+// Lookup returns the property of the name and reports whether the name is
+// declared.
+func (r Properties) Lookup(name string) (*Schema, bool) {
+	value, ok := r.values.get(name)
+	property, _ := value.(*Schema)
+	return property, ok
+}
+
+// Set sets the property of the name. Names not declared yet are appended.
+func (r *Properties) Set(name string, property *Schema) {
+	if r.values == nil {
+		r.values = newOrderedValues()
+	}
+	r.values.set(name, property)
+}
+
+// Delete removes the property of the name.
+func (r *Properties) Delete(name string) {
+	r.values.remove(name)
+}
+
+// orderedValues returns the values backing the properties.
+func (r *Properties) orderedValues() *orderedValues {
+	return r.values
+}
+
+// setOrderedValues replaces the values backing the properties.
+func (r *Properties) setOrderedValues(values *orderedValues) {
+	r.values = values
+}
+
+// MarshalJSON returns the JSON encoding.
+func (r Properties) MarshalJSON() ([]byte, error) {
+	obj, err := r.MarshalYAML()
+	if err != nil {
+		return nil, err
+	}
+	return json.Marshal(obj)
+}
+
+// UnmarshalJSON parses the JSON-encoded data and stores the result.
+func (r *Properties) UnmarshalJSON(data []byte) error {
+	return decodeJSON(data, r)
+}
+
+// MarshalYAML returns the YAML encoding.
+func (r Properties) MarshalYAML() (interface{}, error) {
+	obj := make(orderedMap, 0, r.Len())
+	for _, k := range r.Keys() {
+		obj = append(obj, orderedEntry{key: k, value: r.Get(k)})
+	}
+	return obj, nil
+}
+
+// UnmarshalYAML parses the YAML-encoded data and stores the result.
+func (r *Properties) UnmarshalYAML(node *yaml.Node) error {
+	return decodeYAML(node, r)
+}
+
+// unmarshalValue stores the result of the unmarshal function, which decodes
+// either JSON or YAML data.
+func (r *Properties) unmarshalValue(d *valueDecoder, unmarshal func(interface{}) error) error {
+	obj, keys, err := d.members(unmarshal)
+	if err != nil {
+		return err
+	}
+	for _, k := range keys {
+		var value *Schema
+		if err := d.decodeMember(k, obj[k], &value); err != nil {
+			return err
+		}
+		r.Set(k, value)
+	}
+	return nil
+}
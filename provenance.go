@@ -0,0 +1,207 @@
+package oas
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"sort"
+	"strconv"
+
+	"github.com/pkg/errors"
+	"gopkg.in/yaml.v3"
+)
+
+// Source describes where a node of a document was read from.
+type Source struct {
+	// Location describes the file or URL the node was read from.
+	Location string `json:"location" yaml:"location"`
+
+	// Line describes the 1-based line the node starts at, 0 when unknown.
+	Line int `json:"line,omitempty" yaml:"line,omitempty"`
+
+	// Column describes the 1-based column the node starts at, 0 when
+	// unknown.
+	Column int `json:"column,omitempty" yaml:"column,omitempty"`
+}
+
+// String returns the source as "location:line:column", or the location
+// alone when the line is unknown. Documents read from a reader have no
+// location and are written as "<reader>".
+func (r Source) String() string {
+	location := r.Location
+	if location == "" {
+		location = "<reader>"
+	}
+	if r.Line == 0 {
+		return location
+	}
+	return location + ":" + strconv.Itoa(r.Line) + ":" + strconv.Itoa(r.Column)
+}
+
+// Provenance maps the JSON Pointers of the nodes of a document to their
+// sources.
+type Provenance map[string]*Source
+
+// Lookup returns the source of the node addressed by the pointer, or of its
+// closest ancestor holding a source when the node has none recorded.
+func (r Provenance) Lookup(ptr string) (*Source, bool) {
+	for {
+		if source, ok := r[ptr]; ok {
+			return source, true
+		}
+		if ptr == "" {
+			return nil, false
+		}
+		ptr = parentPointer(splitPointer(ptr), 1)
+	}
+}
+
+// Pointers returns the pointers holding a source in sorted order.
+func (r Provenance) Pointers() []string {
+	return sortedStrings(r)
+}
+
+// Annotate sets the location of the findings to the source of their
+// pointers, so audits and validations of large documents point at the
+// offending lines.
+func (r Provenance) Annotate(findings []*Finding) {
+	for _, finding := range findings {
+		if source, ok := r.Lookup(finding.Pointer); ok {
+			location := *source
+			finding.Location = &location
+		}
+	}
+}
+
+// ReadProvenance returns the provenance of the nodes of the document data
+// read from the location. Every value of a JSON document is recorded with
+// the line and column it starts at. Every node of a YAML document is
+// recorded as well, members of mappings at their key since block values
+// start on the following line.
+func ReadProvenance(location string, data []byte) (Provenance, error) {
+	trimmed := bytes.TrimSpace(data)
+	if len(trimmed) == 0 || (trimmed[0] != '{' && trimmed[0] != '[') {
+		node := &yaml.Node{}
+		if err := yaml.Unmarshal(data, node); err != nil {
+			return nil, errors.Wrap(err, location)
+		}
+		if node.Kind == yaml.DocumentNode && len(node.Content) > 0 {
+			node = node.Content[0]
+		}
+		provenance := Provenance{}
+		provenance.readNode(location, "", node, node)
+		return provenance, nil
+	}
+
+	lines := []int{0}
+	for i, c := range data {
+		if c == '\n' {
+			lines = append(lines, i+1)
+		}
+	}
+	locate := func(offset int64) *Source {
+		for offset < int64(len(data)) && bytes.IndexByte([]byte(" \t\r\n:,"), data[offset]) >= 0 {
+			offset++
+		}
+		line := sort.Search(len(lines), func(i int) bool { return int64(lines[i]) > offset })
+		return &Source{Location: location, Line: line, Column: int(offset) - lines[line-1] + 1}
+	}
+
+	type frame struct {
+		ptr   string
+		array bool
+		index int
+		key   *string
+	}
+	provenance := Provenance{}
+	stack := make([]*frame, 0)
+	next := func() {
+		if len(stack) == 0 {
+			return
+		}
+		top := stack[len(stack)-1]
+		top.index++
+		top.key = nil
+	}
+
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	for {
+		offset := decoder.InputOffset()
+		token, err := decoder.Token()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, errors.Wrap(err, location)
+		}
+
+		if delim, ok := token.(json.Delim); ok && (delim == '}' || delim == ']') {
+			stack = stack[:len(stack)-1]
+			next()
+			continue
+		}
+
+		ptr := ""
+		if len(stack) > 0 {
+			top := stack[len(stack)-1]
+			switch {
+			case top.array:
+				ptr = join(top.ptr, strconv.Itoa(top.index))
+			case top.key == nil:
+				key := token.(string)
+				top.key = &key
+				continue
+			default:
+				ptr = join(top.ptr, *top.key)
+			}
+		}
+		provenance[ptr] = locate(offset)
+
+		if delim, ok := token.(json.Delim); ok {
+			stack = append(stack, &frame{ptr: ptr, array: delim == '['})
+			continue
+		}
+		next()
+	}
+	return provenance, nil
+}
+
+// readNode records the source of the YAML node under the pointer,
+// positioned at the node at, and of the nodes it holds.
+func (r Provenance) readNode(location string, ptr string, at *yaml.Node, node *yaml.Node) {
+	r[ptr] = &Source{Location: location, Line: at.Line, Column: at.Column}
+	switch node.Kind {
+	case yaml.AliasNode:
+		if node.Alias != nil {
+			r.readNode(location, ptr, at, node.Alias)
+		}
+	case yaml.SequenceNode:
+		for i, child := range node.Content {
+			r.readNode(location, join(ptr, strconv.Itoa(i)), child, child)
+		}
+	case yaml.MappingNode:
+		for i := 0; i+1 < len(node.Content); i += 2 {
+			key := node.Content[i]
+			r.readNode(location, join(ptr, key.Value), key, node.Content[i+1])
+		}
+	}
+}
+
+// Source returns the source of the error, with a zero line when unknown.
+func (e *LoadError) Source() Source {
+	return Source{Location: e.Location, Line: e.Line, Column: e.Column}
+}
+
+// LoadLocated decodes the document read from the location like Load and
+// returns the provenance of its nodes.
+func (r Loader) LoadLocated(location string, data []byte) (*OpenAPI, Provenance, error) {
+	doc, err := r.Load(location, data)
+	if err != nil {
+		return nil, nil, err
+	}
+	provenance, err := ReadProvenance(location, data)
+	if err != nil {
+		return nil, nil, &LoadError{Location: location, Err: err}
+	}
+	return doc, provenance, nil
+}
@@ -0,0 +1,149 @@
+package oas
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+)
+
+type ProvenanceSuite struct {
+	suite.Suite
+}
+
+func (r *ProvenanceSuite) TestReadProvenance() {
+	data := []byte(`{
+  "openapi": "3.0.0",
+  "info": {"title": "Petstore", "version": "1.0.0"},
+  "paths": {
+    "/pets": {
+      "get": {
+        "tags": ["pets", "animals"],
+        "responses": {}
+      }
+    }
+  }
+}`)
+	provenance, err := ReadProvenance("petstore.json", data)
+	assert.Nil(r.T(), err)
+	assert.Equal(r.T(), &Source{Location: "petstore.json", Line: 1, Column: 1}, provenance[""])
+	assert.Equal(r.T(), &Source{Location: "petstore.json", Line: 3, Column: 11}, provenance["/info"])
+	assert.Equal(r.T(), &Source{Location: "petstore.json", Line: 3, Column: 21}, provenance["/info/title"])
+	assert.Equal(r.T(), &Source{Location: "petstore.json", Line: 7, Column: 26}, provenance["/paths/~1pets/get/tags/1"])
+	assert.Equal(r.T(), &Source{Location: "petstore.json", Line: 8, Column: 22}, provenance["/paths/~1pets/get/responses"])
+	assert.Len(r.T(), provenance.Pointers(), 12)
+
+	_, err = ReadProvenance("broken.json", []byte(`{"openapi": }`))
+	assert.NotNil(r.T(), err)
+}
+
+func (r *ProvenanceSuite) yaml() []byte {
+	return []byte(`openapi: 3.0.3
+info:
+  title: Petstore
+  version: 1.0.0
+paths:
+  /pets:
+    get:
+      tags: [pets, animals]
+      responses:
+        "200":
+          description: The pets.
+`)
+}
+
+func (r *ProvenanceSuite) TestReadProvenanceYAML() {
+	provenance, err := ReadProvenance("spec.yaml", r.yaml())
+	assert.Nil(r.T(), err)
+
+	testCases := []struct {
+		ptr      string
+		expected string
+	}{
+		{"", "spec.yaml:1:1"},
+		{"/info/version", "spec.yaml:4:3"},
+		{"/paths/~1pets/get", "spec.yaml:7:5"},
+		{"/paths/~1pets/get/tags/1", "spec.yaml:8:20"},
+		{"/paths/~1pets/get/responses/200/description", "spec.yaml:11:11"},
+		{"/paths/~1pets/get/responses/404", "spec.yaml:9:7"},
+	}
+
+	for i, testCase := range testCases {
+		failMsg := "test case %d failed"
+		source, ok := provenance.Lookup(testCase.ptr)
+		if assert.True(r.T(), ok, failMsg, i) {
+			assert.Equal(r.T(), testCase.expected, source.String(), failMsg, i)
+		}
+	}
+	assert.Len(r.T(), provenance.Pointers(), 14)
+
+	_, err = ReadProvenance("spec.yaml", []byte("a: [b"))
+	assert.NotNil(r.T(), err)
+	assert.Equal(r.T(), "<reader>:1:1", Source{Line: 1, Column: 1}.String())
+}
+
+func (r *ProvenanceSuite) TestAnnotate() {
+	doc, provenance, err := Loader{}.LoadLocated("spec.yaml", r.yaml())
+	assert.Nil(r.T(), err)
+	findings := []*Finding{
+		{Pointer: "/paths/~1pets/get", Rule: "rule"},
+		{Pointer: "/components/schemas/Pet", Rule: "rule"},
+	}
+	provenance.Annotate(findings)
+	assert.Equal(r.T(), &Source{Location: "spec.yaml", Line: 7, Column: 5}, findings[0].Location)
+	assert.Equal(r.T(), &Source{Location: "spec.yaml", Line: 1, Column: 1}, findings[1].Location)
+	assert.Equal(r.T(), "Petstore", doc.Info.Title)
+
+	_, _, err = Loader{}.LoadLocated("spec.yaml", []byte("openapi: [3"))
+	loadErr, ok := err.(*LoadError)
+	assert.True(r.T(), ok)
+	assert.Equal(r.T(), Source{Location: "spec.yaml", Line: 1}, loadErr.Source())
+}
+
+func (r *ProvenanceSuite) TestLookup() {
+	provenance := Provenance{
+		"":          {Location: "petstore.json", Line: 1, Column: 1},
+		"/info":     {Location: "petstore.json", Line: 3, Column: 11},
+		"/paths/~1": {Location: "petstore.json", Line: 5, Column: 10},
+	}
+	testCases := []struct {
+		ptr      string
+		expected string
+	}{
+		{"/info", "petstore.json:3:11"},
+		{"/info/title", "petstore.json:3:11"},
+		{"/paths/~1/get", "petstore.json:5:10"},
+		{"/components", "petstore.json:1:1"},
+	}
+
+	for i, testCase := range testCases {
+		failMsg := "test case %d failed"
+		source, ok := provenance.Lookup(testCase.ptr)
+		assert.True(r.T(), ok, failMsg, i)
+		assert.Equal(r.T(), testCase.expected, source.String(), failMsg, i)
+	}
+
+	_, ok := Provenance{}.Lookup("/info")
+	assert.False(r.T(), ok)
+}
+
+func (r *ProvenanceSuite) TestWorkspaceProvenance() {
+	workspace := &Workspace{
+		Read: func(ctx context.Context, location string) ([]byte, error) {
+			return []byte("{\n  \"openapi\": \"3.0.0\"\n}"), nil
+		},
+	}
+	assert.Nil(r.T(), workspace.Load(context.Background(), "petstore.json"))
+	provenance, ok := workspace.Provenance("petstore.json")
+	assert.True(r.T(), ok)
+	source, _ := provenance.Lookup("/openapi")
+	assert.Equal(r.T(), "petstore.json:2:14", source.String())
+
+	_, ok = workspace.Provenance("missing.json")
+	assert.False(r.T(), ok)
+}
+
+func TestProvenanceSuite(t *testing.T) {
+	suite.Run(t, new(ProvenanceSuite))
+}
@@ -0,0 +1,535 @@
+package oas
+
+import (
+	"bytes"
+	"net/http"
+	"reflect"
+	"strconv"
+	"strings"
+
+	"github.com/pkg/errors"
+	"gopkg.in/yaml.v3"
+)
+
+// ramlTypes maps the built-in types of RAML onto schemas.
+var ramlTypes = map[string]Schema{
+	"string":        {Type: "string"},
+	"number":        {Type: "number"},
+	"integer":       {Type: "integer"},
+	"boolean":       {Type: "boolean"},
+	"date-only":     {Type: "string", Format: "date"},
+	"datetime":      {Type: "string", Format: "date-time"},
+	"datetime-only": {Type: "string", Format: "date-time"},
+	"time-only":     {Type: "string"},
+	"file":          {Type: "string", Format: "binary"},
+	"object":        {Type: "object"},
+	"array":         {Type: "array"},
+	"any":           {},
+}
+
+// ramlMethods lists the methods of RAML resources.
+var ramlMethods = []string{"get", "put", "post", "delete", "options", "head", "patch"}
+
+// ImportRAML converts a RAML 1.0 document into a document to help migrating
+// off RAML tooling. Resources become paths, their methods operations and the
+// types schemas of the components. The parameters of traits become shared
+// parameters of the components named after the trait and the parameter
+// (e.g. "paged.page") which the operations using the trait reference, and
+// the responses of traits are merged into them. Resource types, libraries,
+// annotations and security schemes are not converted.
+func ImportRAML(data []byte) (*OpenAPI, error) {
+	if !bytes.HasPrefix(bytes.TrimSpace(data), []byte("#%RAML 1.0")) {
+		return nil, errors.New("not a RAML 1.0 document")
+	}
+	node := &yaml.Node{}
+	if err := yaml.Unmarshal(data, node); err != nil {
+		return nil, errors.WithStack(err)
+	}
+	value, err := nodeValue(node)
+	if err != nil {
+		return nil, err
+	}
+	root, _ := value.(map[string]interface{})
+
+	importer := &ramlImporter{
+		doc: &OpenAPI{
+			OpenAPI: "3.0.0",
+			Info: Info{
+				Title:       ramlString(root, "title"),
+				Description: ramlString(root, "description"),
+				Version:     ramlString(root, "version"),
+			},
+			Components: &Components{},
+		},
+		mediaType: "application/json",
+		traits:    map[string]map[string]interface{}{},
+	}
+	doc := importer.doc
+	if doc.Info.Version == "" {
+		doc.Info.Version = "1.0.0"
+	}
+	if mediaType, ok := root["mediaType"].(string); ok {
+		importer.mediaType = mediaType
+	}
+	if baseURI := ramlString(root, "baseUri"); baseURI != "" {
+		doc.Servers = []*Server{importer.server(baseURI, ramlMap(root, "baseUriParameters"))}
+	}
+
+	types := ramlMap(root, "types")
+	if types == nil {
+		types = ramlMap(root, "schemas")
+	}
+	for _, name := range sortedStrings(types) {
+		if doc.Components.Schemas == nil {
+			doc.Components.Schemas = map[string]*Schema{}
+		}
+		doc.Components.Schemas[name] = importer.typeDecl(types[name])
+	}
+
+	traits := ramlMap(root, "traits")
+	for _, name := range sortedStrings(traits) {
+		trait, _ := traits[name].(map[string]interface{})
+		importer.traits[name] = trait
+		for _, parameter := range importer.parameters(ramlMap(trait, "queryParameters"), "query", true) {
+			importer.share(name, parameter)
+		}
+		for _, parameter := range importer.parameters(ramlMap(trait, "headers"), "header", true) {
+			importer.share(name, parameter)
+		}
+	}
+
+	for _, key := range sortedStrings(root) {
+		if strings.HasPrefix(key, "/") {
+			resource, _ := root[key].(map[string]interface{})
+			if err := importer.resource(key, resource, nil); err != nil {
+				return nil, err
+			}
+		}
+	}
+	return doc, nil
+}
+
+// ramlImporter holds the state of a RAML conversion.
+type ramlImporter struct {
+	doc       *OpenAPI
+	mediaType string
+	traits    map[string]map[string]interface{}
+}
+
+// server converts the base URI, declaring a variable for each of its
+// parameters other than the version, which is substituted.
+func (r *ramlImporter) server(baseURI string, parameters map[string]interface{}) *Server {
+	server := &Server{URL: strings.Replace(baseURI, "{version}", r.doc.Info.Version, -1)}
+	for _, match := range templateParam.FindAllStringSubmatch(server.URL, -1) {
+		if server.Variables == nil {
+			server.Variables = map[string]*ServerVariable{}
+		}
+		variable := &ServerVariable{Default: match[1]}
+		if decl, ok := parameters[match[1]].(map[string]interface{}); ok {
+			if value, ok := decl["default"]; ok {
+				variable.Default = ramlScalar(value)
+			}
+			if enum, ok := decl["enum"].([]interface{}); ok {
+				for _, value := range enum {
+					variable.Enum = append(variable.Enum, ramlScalar(value))
+				}
+				if _, ok := decl["default"]; !ok && len(variable.Enum) > 0 {
+					variable.Default = variable.Enum[0]
+				}
+			}
+			variable.Description = ramlString(decl, "description")
+		}
+		server.Variables[match[1]] = variable
+	}
+	return server
+}
+
+// share declares the parameter of the trait in the components.
+func (r *ramlImporter) share(trait string, parameter *Parameter) {
+	if r.doc.Components.Parameters == nil {
+		r.doc.Components.Parameters = map[string]*Parameter{}
+	}
+	r.doc.Components.Parameters[trait+"."+parameter.Name] = parameter
+}
+
+// resource converts the resource and its nested resources. Path parameters
+// of the parent resources are inherited while their traits only apply to
+// their own methods.
+func (r *ramlImporter) resource(path string, decl map[string]interface{}, inherited []*Parameter) error {
+	if _, ok := r.doc.Paths.PathItems.Lookup(path); ok {
+		return errors.Errorf("resource %q is declared more than once", path)
+	}
+	item := &PathItem{Description: ramlString(decl, "description")}
+
+	declared := map[string]bool{}
+	pathParams := append([]*Parameter{}, inherited...)
+	for _, parameter := range r.parameters(ramlMap(decl, "uriParameters"), "path", true) {
+		pathParams = append(pathParams, parameter)
+	}
+	for _, parameter := range pathParams {
+		declared[parameter.Name] = true
+	}
+	for _, match := range templateParam.FindAllStringSubmatch(path, -1) {
+		if !declared[match[1]] {
+			declared[match[1]] = true
+			pathParams = append(pathParams, &Parameter{
+				Name:   match[1],
+				In:     "path",
+				Header: Header{Required: true, Schema: &Schema{Type: "string"}},
+			})
+		}
+	}
+	for _, parameter := range pathParams {
+		if strings.Contains(path, "{"+parameter.Name+"}") {
+			item.Parameters = append(item.Parameters, parameter)
+		}
+	}
+
+	traits := ramlStrings(decl["is"])
+	for _, method := range ramlMethods {
+		if value, ok := decl[method]; ok {
+			operation, _ := value.(map[string]interface{})
+			op, err := r.operation(operation, traits)
+			if err != nil {
+				return errors.Wrapf(err, "%s %s", strings.ToUpper(method), path)
+			}
+			item.setOperation(method, op)
+		}
+	}
+	if len(item.operations()) > 0 {
+		r.doc.Paths.PathItems.Set(path, item)
+	}
+
+	for _, key := range sortedStrings(decl) {
+		if strings.HasPrefix(key, "/") {
+			nested, _ := decl[key].(map[string]interface{})
+			if err := r.resource(path+key, nested, pathParams); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// operation converts the method of a resource with the traits applied to
+// the resource.
+func (r *ramlImporter) operation(decl map[string]interface{}, traits []string) (*Operation, error) {
+	op := &Operation{
+		Summary:     ramlString(decl, "displayName"),
+		Description: ramlString(decl, "description"),
+	}
+
+	traits = append(append([]string{}, traits...), ramlStrings(decl["is"])...)
+	applied := map[string]bool{}
+	for _, name := range traits {
+		trait, ok := r.traits[name]
+		if !ok {
+			return nil, errors.Errorf("unknown trait %q", name)
+		}
+		if applied[name] {
+			continue
+		}
+		applied[name] = true
+		parameters := append(
+			r.parameters(ramlMap(trait, "queryParameters"), "query", true),
+			r.parameters(ramlMap(trait, "headers"), "header", true)...,
+		)
+		for _, parameter := range parameters {
+			op.Parameters = append(op.Parameters, &Parameter{
+				Header: Header{Ref: "#/components/parameters/" + name + "." + parameter.Name},
+			})
+		}
+		r.responses(op, ramlMap(trait, "responses"))
+	}
+
+	op.Parameters = append(op.Parameters, r.parameters(ramlMap(decl, "queryParameters"), "query", true)...)
+	op.Parameters = append(op.Parameters, r.parameters(ramlMap(decl, "headers"), "header", true)...)
+	if body, ok := decl["body"]; ok {
+		op.RequestBody = &RequestBody{Content: r.content(body)}
+	}
+	r.responses(op, ramlMap(decl, "responses"))
+	if op.Responses.Len() == 0 {
+		op.Responses.Set("default", &Response{Description: "Undocumented response."})
+	}
+	return op, nil
+}
+
+// responses converts the responses of a method or trait, replacing the
+// responses of the operation with the same status code.
+func (r *ramlImporter) responses(op *Operation, responses map[string]interface{}) {
+	for _, code := range sortedStrings(responses) {
+		decl, _ := responses[code].(map[string]interface{})
+		response := &Response{Description: ramlString(decl, "description")}
+		if response.Description == "" {
+			if status, err := strconv.Atoi(code); err == nil {
+				response.Description = http.StatusText(status)
+			}
+		}
+		for _, header := range r.parameters(ramlMap(decl, "headers"), "header", true) {
+			if response.Headers == nil {
+				response.Headers = map[string]*Header{}
+			}
+			response.Headers[header.Name] = &Header{
+				Description: header.Description,
+				Required:    header.Required,
+				Schema:      header.Schema,
+			}
+		}
+		if body, ok := decl["body"]; ok {
+			response.Content = r.content(body)
+		}
+		op.Responses.Set(code, response)
+	}
+}
+
+// content converts a body declaration, keyed by media type or declaring the
+// type of the default media type.
+func (r *ramlImporter) content(body interface{}) map[string]*MediaType {
+	content := map[string]*MediaType{}
+	if decls, ok := body.(map[string]interface{}); ok {
+		for _, key := range sortedStrings(decls) {
+			if strings.Contains(key, "/") {
+				content[key] = r.mediaTypeDecl(decls[key])
+			}
+		}
+	}
+	if len(content) == 0 {
+		content[r.mediaType] = r.mediaTypeDecl(body)
+	}
+	return content
+}
+
+// mediaTypeDecl converts the type of a body and its example.
+func (r *ramlImporter) mediaTypeDecl(decl interface{}) *MediaType {
+	mediaType := &MediaType{}
+	if decl == nil {
+		return mediaType
+	}
+	schema := r.typeDecl(decl)
+	if schema.Example != nil {
+		mediaType.Example, schema.Example = schema.Example, nil
+		schema = ramlUnwrap(schema)
+	}
+	if schema.Ref != "" || schema.Type != "" || schema.Properties.Len() > 0 || len(schema.AllOf) > 0 || len(schema.OneOf) > 0 {
+		mediaType.Schema = schema
+	}
+	return mediaType
+}
+
+// parameters converts the parameter declarations in the location, ordered
+// by name. Names suffixed with a question mark are optional and so are
+// parameters declaring required to be false; path parameters are always
+// required.
+func (r *ramlImporter) parameters(decls map[string]interface{}, in string, required bool) []*Parameter {
+	parameters := make([]*Parameter, 0, len(decls))
+	for _, key := range sortedStrings(decls) {
+		name, optional := strings.TrimSuffix(key, "?"), strings.HasSuffix(key, "?")
+		schema := r.typeDecl(decls[key])
+		parameter := &Parameter{
+			Name: name,
+			In:   in,
+			Header: Header{
+				Required:    in == "path" || (required && !optional),
+				Description: schema.Description,
+				Example:     schema.Example,
+				Schema:      schema,
+			},
+		}
+		schema.Description, schema.Example = "", nil
+		parameter.Schema = ramlUnwrap(schema)
+		if decl, ok := decls[key].(map[string]interface{}); ok {
+			if value, ok := decl["required"].(bool); ok && in != "path" {
+				parameter.Required = value
+			}
+		}
+		parameters = append(parameters, parameter)
+	}
+	return parameters
+}
+
+// typeDecl converts a type declaration, either a type expression or a map
+// of facets.
+func (r *ramlImporter) typeDecl(decl interface{}) *Schema {
+	facets, ok := decl.(map[string]interface{})
+	if !ok {
+		if expr, ok := decl.(string); ok {
+			return r.typeExpr(expr)
+		}
+		return &Schema{Type: "string"}
+	}
+
+	properties := ramlMap(facets, "properties")
+	base, ok := facets["type"]
+	if !ok {
+		base = facets["schema"]
+	}
+	var schema *Schema
+	switch base := base.(type) {
+	case string:
+		schema = r.typeExpr(base)
+		if schema.Ref != "" && properties != nil {
+			schema = &Schema{AllOf: []*Schema{schema}}
+		}
+	case []interface{}:
+		schema = &Schema{}
+		for _, parent := range ramlStrings(base) {
+			schema.AllOf = append(schema.AllOf, r.typeExpr(parent))
+		}
+	default:
+		schema = &Schema{Type: "string"}
+		if properties != nil {
+			schema.Type = "object"
+		}
+	}
+	if schema.Ref != "" {
+		schema = &Schema{AllOf: []*Schema{schema}}
+	}
+
+	schema.Title = ramlString(facets, "displayName")
+	schema.Description = ramlString(facets, "description")
+	schema.Pattern = ramlString(facets, "pattern")
+	schema.Format = ramlFormat(schema.Format, ramlString(facets, "format"))
+	schema.Example = facets["example"]
+	schema.Default = facets["default"]
+	schema.MinLength, schema.MaxLength = facets["minLength"], facets["maxLength"]
+	schema.Minimum, schema.Maximum = facets["minimum"], facets["maximum"]
+	schema.MultipleOf = facets["multipleOf"]
+	schema.MinItems, schema.MaxItems = facets["minItems"], facets["maxItems"]
+	schema.MinProperties, schema.MaxProperties = facets["minProperties"], facets["maxProperties"]
+	if value, ok := facets["uniqueItems"].(bool); ok {
+		schema.UniqueItems = value
+	}
+	if enum, ok := facets["enum"].([]interface{}); ok {
+		schema.Enum = enum
+	}
+	if items, ok := facets["items"]; ok {
+		schema.Items = r.typeDecl(items)
+	}
+
+	target := schema
+	if len(schema.AllOf) > 0 && properties != nil {
+		target = &Schema{Type: "object"}
+		schema.AllOf = append(schema.AllOf, target)
+	}
+	for _, key := range sortedStrings(properties) {
+		name, optional := strings.TrimSuffix(key, "?"), strings.HasSuffix(key, "?")
+		target.Properties.Set(name, r.typeDecl(properties[key]))
+		if property, ok := properties[key].(map[string]interface{}); ok {
+			if value, ok := property["required"].(bool); ok {
+				optional = !value
+			}
+		}
+		if !optional {
+			target.Required = append(target.Required, name)
+		}
+	}
+	return ramlUnwrap(schema)
+}
+
+// ramlUnwrap returns the reference an allOf wraps when the wrapping schema
+// declares nothing else.
+func ramlUnwrap(schema *Schema) *Schema {
+	if len(schema.AllOf) == 1 && schema.AllOf[0].Ref != "" {
+		facetless := *schema
+		facetless.AllOf = nil
+		if reflect.DeepEqual(facetless, Schema{}) {
+			return schema.AllOf[0]
+		}
+	}
+	return schema
+}
+
+// typeExpr converts a type expression: a built-in type, the name of a
+// declared type, an array of a type (e.g. "Pet[]") or a union (e.g.
+// "Cat | Dog"). Unions with nil become nullable.
+func (r *ramlImporter) typeExpr(expr string) *Schema {
+	expr = strings.TrimSpace(expr)
+	if strings.HasSuffix(expr, "[]") {
+		items := strings.TrimSuffix(expr, "[]")
+		if strings.HasPrefix(items, "(") && strings.HasSuffix(items, ")") {
+			items = items[1 : len(items)-1]
+		}
+		return &Schema{Type: "array", Items: r.typeExpr(items)}
+	}
+
+	if strings.Contains(expr, "|") {
+		nullable := false
+		options := make([]*Schema, 0)
+		for _, option := range strings.Split(expr, "|") {
+			if strings.TrimSpace(option) == "nil" {
+				nullable = true
+				continue
+			}
+			options = append(options, r.typeExpr(option))
+		}
+		schema := &Schema{OneOf: options}
+		if len(options) == 1 {
+			schema = options[0]
+			if schema.Ref != "" {
+				schema = &Schema{AllOf: options}
+			}
+		}
+		schema.Nullable = nullable
+		return schema
+	}
+
+	if schema, ok := ramlTypes[expr]; ok {
+		return &schema
+	}
+	if expr == "" {
+		return &Schema{Type: "string"}
+	}
+	return &Schema{Ref: "#/components/schemas/" + expr}
+}
+
+// ramlFormat returns the format of a number facet (e.g. "int64") or the
+// format of the built-in type.
+func ramlFormat(builtin string, format string) string {
+	switch format {
+	case "int32", "int64", "float", "double":
+		return format
+	case "int", "int8", "int16":
+		return "int32"
+	case "long":
+		return "int64"
+	}
+	return builtin
+}
+
+// ramlMap returns the map value of the key.
+func ramlMap(m map[string]interface{}, key string) map[string]interface{} {
+	value, _ := m[key].(map[string]interface{})
+	return value
+}
+
+// ramlString returns the string value of the key.
+func ramlString(m map[string]interface{}, key string) string {
+	value, _ := m[key].(string)
+	return strings.TrimSpace(value)
+}
+
+// ramlScalar returns the scalar as a string.
+func ramlScalar(value interface{}) string {
+	if value, ok := value.(string); ok {
+		return value
+	}
+	rbytes, _ := yaml.Marshal(value)
+	return strings.TrimSpace(string(rbytes))
+}
+
+// ramlStrings returns the names of a list of traits or types in order,
+// ignoring parameterized entries.
+func ramlStrings(value interface{}) []string {
+	var names []string
+	switch value := value.(type) {
+	case string:
+		names = append(names, value)
+	case []interface{}:
+		for _, item := range value {
+			if name, ok := item.(string); ok {
+				names = append(names, name)
+			}
+		}
+	}
+	return names
+}
@@ -0,0 +1,172 @@
+package oas
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+)
+
+type RAMLSuite struct {
+	suite.Suite
+}
+
+const petstoreRAML = `#%RAML 1.0
+title: Petstore
+version: v1
+baseUri: https://{region}.example.com/{version}
+baseUriParameters:
+  region:
+    enum: [eu, us]
+mediaType: application/json
+types:
+  Pet:
+    type: object
+    properties:
+      id: integer
+      name:
+        type: string
+        maxLength: 64
+      tag?: string
+      kind: Cat | Dog
+      parent: Pet | nil
+  Cat:
+    type: Pet
+    properties:
+      lives:
+        type: integer
+        format: int8
+  Dog: Pet
+  Pets: Pet[]
+traits:
+  paged:
+    queryParameters:
+      page?:
+        type: integer
+        default: 1
+    responses:
+      400:
+        description: Bad page.
+/pets:
+  description: All pets.
+  is: [paged]
+  get:
+    displayName: List pets
+    headers:
+      X-Request-Id:
+        required: false
+    responses:
+      200:
+        headers:
+          X-Total: integer
+        body:
+          type: Pets
+          example: []
+  post:
+    body:
+      application/json: Pet
+      application/xml:
+    responses:
+      201:
+  /{petId}:
+    uriParameters:
+      petId: integer
+    get:
+      responses:
+        200:
+          body: Pet
+    /owners/{ownerId}:
+      delete:
+`
+
+func (r *RAMLSuite) TestImportRAML() {
+	doc, err := ImportRAML([]byte(petstoreRAML))
+	assert.Nil(r.T(), err)
+
+	assert.Equal(r.T(), Info{Title: "Petstore", Version: "v1"}, doc.Info)
+	assert.Equal(r.T(), []*Server{{
+		URL: "https://{region}.example.com/v1",
+		Variables: map[string]*ServerVariable{
+			"region": {Default: "eu", Enum: []string{"eu", "us"}},
+		},
+	}}, doc.Servers)
+	assert.Equal(r.T(), []string{"/pets", "/pets/{petId}", "/pets/{petId}/owners/{ownerId}"}, sortedKeys(doc.Paths.PathItems))
+
+	assert.Equal(r.T(), map[string]*Schema{
+		"Pet": {
+			Type:     "object",
+			Required: []string{"id", "kind", "name", "parent"},
+			Properties: NewProperties(map[string]*Schema{
+				"id":     {Type: "integer"},
+				"name":   {Type: "string", MaxLength: 64},
+				"tag":    {Type: "string"},
+				"kind":   {OneOf: []*Schema{{Ref: "#/components/schemas/Cat"}, {Ref: "#/components/schemas/Dog"}}},
+				"parent": {Nullable: true, AllOf: []*Schema{{Ref: "#/components/schemas/Pet"}}},
+			}),
+		},
+		"Cat": {
+			AllOf: []*Schema{
+				{Ref: "#/components/schemas/Pet"},
+				{
+					Type:       "object",
+					Required:   []string{"lives"},
+					Properties: NewProperties(map[string]*Schema{"lives": {Type: "integer", Format: "int32"}}),
+				},
+			},
+		},
+		"Dog":  {Ref: "#/components/schemas/Pet"},
+		"Pets": {Type: "array", Items: &Schema{Ref: "#/components/schemas/Pet"}},
+	}, doc.Components.Schemas)
+	assert.Equal(r.T(), map[string]*Parameter{
+		"paged.page": {Name: "page", In: "query", Header: Header{Schema: &Schema{Type: "integer", Default: 1}}},
+	}, doc.Components.Parameters)
+
+	pets := doc.Paths.PathItems.Get("/pets")
+	assert.Equal(r.T(), "All pets.", pets.Description)
+	assert.Equal(r.T(), "List pets", pets.Get.Summary)
+	assert.Equal(r.T(), []*Parameter{
+		{Header: Header{Ref: "#/components/parameters/paged.page"}},
+		{Name: "X-Request-Id", In: "header", Header: Header{Schema: &Schema{Type: "string"}}},
+	}, pets.Get.Parameters)
+	assert.Equal(r.T(), []string{"200", "400"}, sortedKeys(pets.Get.Responses))
+	assert.Equal(r.T(), "Bad page.", pets.Get.Responses.Get("400").Description)
+	assert.Equal(r.T(), "OK", pets.Get.Responses.Get("200").Description)
+	assert.Equal(r.T(), &Schema{Type: "integer"}, pets.Get.Responses.Get("200").Headers["X-Total"].Schema)
+	assert.Equal(r.T(), &MediaType{
+		Schema:  &Schema{Ref: "#/components/schemas/Pets"},
+		Example: []interface{}{},
+	}, pets.Get.Responses.Get("200").Content["application/json"])
+	assert.Equal(r.T(), map[string]*MediaType{
+		"application/json": {Schema: &Schema{Ref: "#/components/schemas/Pet"}},
+		"application/xml":  {},
+	}, pets.Post.RequestBody.Content)
+
+	pet := doc.Paths.PathItems.Get("/pets/{petId}")
+	assert.Equal(r.T(), []*Parameter{
+		{Name: "petId", In: "path", Header: Header{Required: true, Schema: &Schema{Type: "integer"}}},
+	}, pet.Parameters)
+	assert.Len(r.T(), pet.Get.Parameters, 0)
+	assert.Equal(r.T(), &Schema{Ref: "#/components/schemas/Pet"}, pet.Get.Responses.Get("200").Content["application/json"].Schema)
+
+	owners := doc.Paths.PathItems.Get("/pets/{petId}/owners/{ownerId}")
+	assert.Equal(r.T(), []string{"petId", "ownerId"}, []string{owners.Parameters[0].Name, owners.Parameters[1].Name})
+	assert.Equal(r.T(), "Undocumented response.", owners.Delete.Responses.Get("default").Description)
+}
+
+func (r *RAMLSuite) TestErrors() {
+	testCases := []string{
+		"title: Petstore",
+		"#%RAML 1.0\ntitle: [",
+		"#%RAML 1.0\n/pets:\n  get:\n    is: [missing]\n",
+	}
+
+	for i, testCase := range testCases {
+		failMsg := "test case %d failed"
+		_, err := ImportRAML([]byte(testCase))
+		assert.NotNil(r.T(), err, failMsg, i)
+	}
+}
+
+func TestRAMLSuite(t *testing.T) {
+	suite.Run(t, new(RAMLSuite))
+}
@@ -0,0 +1,153 @@
+package oas
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// RateLimitExtension names the specification extension declaring the rate
+// limit enforced on an operation. It may be declared on the operation, on
+// the path item or on the document root, the closest declaration taking
+// precedence.
+const RateLimitExtension = "x-ratelimit"
+
+// Keys counting the requests of a rate limit.
+const (
+	// RateLimitByIP counts the requests of each client address.
+	RateLimitByIP = "ip"
+
+	// RateLimitByHeader prefixes the name of the header whose values are
+	// counted separately (e.g. "header:X-API-Key").
+	RateLimitByHeader = "header:"
+)
+
+// RateLimit describes the number of requests a client may send within a
+// window of time.
+type RateLimit struct {
+	// Limit describes the number of requests allowed within the window.
+	Limit int `json:"limit" yaml:"limit"`
+
+	// Window describes the length of the window as a duration (e.g. "1m").
+	Window string `json:"window" yaml:"window"`
+
+	// Key describes what the requests are counted by, either RateLimitByIP
+	// or RateLimitByHeader followed by a header name. Defaults to
+	// RateLimitByIP.
+	Key string `json:"key,omitempty" yaml:"key,omitempty"`
+}
+
+// WindowDuration returns the parsed window.
+func (r RateLimit) WindowDuration() (time.Duration, error) {
+	window, err := time.ParseDuration(r.Window)
+	if err != nil {
+		return 0, errors.WithStack(err)
+	}
+	if window <= 0 {
+		return 0, errors.Errorf("window %q must be positive", r.Window)
+	}
+	return window, nil
+}
+
+// Header returns the name of the header the requests are counted by, empty
+// when they are not counted by header.
+func (r RateLimit) Header() string {
+	if strings.HasPrefix(r.Key, RateLimitByHeader) {
+		return strings.TrimPrefix(r.Key, RateLimitByHeader)
+	}
+	return ""
+}
+
+// String returns a description of the rate limit suitable for
+// documentation (e.g. "100 requests per 1m0s per client address").
+func (r RateLimit) String() string {
+	by := "client address"
+	if header := r.Header(); header != "" {
+		by = header + " header"
+	}
+	window := r.Window
+	if duration, err := r.WindowDuration(); err == nil {
+		window = duration.String()
+	}
+	return fmt.Sprintf("%d requests per %s per %s", r.Limit, window, by)
+}
+
+// validate checks that the rate limit is well formed.
+func (r RateLimit) validate() error {
+	if r.Limit <= 0 {
+		return errors.Errorf("limit %d must be positive", r.Limit)
+	}
+	if _, err := r.WindowDuration(); err != nil {
+		return err
+	}
+	if r.Key != "" && r.Key != RateLimitByIP && r.Header() == "" {
+		return errors.Errorf("unknown key %q", r.Key)
+	}
+	return nil
+}
+
+// RateLimit returns the rate limit declared by the x-ratelimit extension, or
+// nil if the extension is absent.
+func (r Extensions) RateLimit() (*RateLimit, error) {
+	limit := &RateLimit{}
+	ok, err := r.decode(RateLimitExtension, limit)
+	if !ok || err != nil {
+		return nil, err
+	}
+	if err := limit.validate(); err != nil {
+		return nil, errors.Wrap(err, RateLimitExtension)
+	}
+	if limit.Key == "" {
+		limit.Key = RateLimitByIP
+	}
+	return limit, nil
+}
+
+// SetRateLimit declares the rate limit with the x-ratelimit extension, or
+// removes the extension when nil.
+func (r *Extensions) SetRateLimit(limit *RateLimit) {
+	if limit == nil {
+		delete(*r, RateLimitExtension)
+		return
+	}
+	value := map[string]interface{}{
+		"limit":  limit.Limit,
+		"window": limit.Window,
+	}
+	if limit.Key != "" {
+		value["key"] = limit.Key
+	}
+	setExtension(r, RateLimitExtension, value)
+}
+
+// RateLimits returns the rate limit of every operation, declared on the
+// operation, its path item or the document root, by operation pointer.
+// Operations without rate limit are omitted.
+func (r OpenAPI) RateLimits() (map[string]*RateLimit, error) {
+	root, err := r.Extensions.RateLimit()
+	if err != nil {
+		return nil, err
+	}
+	limits := map[string]*RateLimit{}
+	for _, op := range r.Paths.operations() {
+		ptr := join("/paths", op.path, op.method)
+		limit, err := op.operation.Extensions.RateLimit()
+		if err != nil {
+			return nil, errors.Wrap(err, ptr)
+		}
+		if limit == nil {
+			if limit, err = op.item.Extensions.RateLimit(); err != nil {
+				return nil, errors.Wrap(err, join("/paths", op.path))
+			}
+		}
+		if limit == nil {
+			limit = root
+		}
+		if limit != nil {
+			limits[ptr] = limit
+		}
+	}
+	return limits, nil
+}
@@ -0,0 +1,119 @@
+package oas
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+)
+
+type RateLimitSuite struct {
+	suite.Suite
+}
+
+// ratelimitPatch is the patch of the petstore fixture limiting the rate of
+// requests at every level.
+const ratelimitPatch = `
+paths:
+  /pets:
+    get:
+      x-ratelimit:
+        limit: 10
+        window: 1s
+        key: header:X-API-Key
+  /pets/{petId}:
+    x-ratelimit:
+      limit: 50
+      window: 1m
+x-ratelimit:
+  limit: 1000
+  window: 1h
+`
+
+func (r *RateLimitSuite) TestRateLimit() {
+	testCases := []struct {
+		value    interface{}
+		expected *RateLimit
+		isErr    bool
+	}{
+		{nil, nil, false},
+		{
+			map[string]interface{}{"limit": 100, "window": "1m"},
+			&RateLimit{Limit: 100, Window: "1m", Key: RateLimitByIP},
+			false,
+		},
+		{
+			map[string]interface{}{"limit": 5, "window": "10s", "key": "header:Authorization"},
+			&RateLimit{Limit: 5, Window: "10s", Key: "header:Authorization"},
+			false,
+		},
+		{map[string]interface{}{"limit": 0, "window": "1m"}, nil, true},
+		{map[string]interface{}{"limit": 10, "window": "soon"}, nil, true},
+		{map[string]interface{}{"limit": 10, "window": "-1m"}, nil, true},
+		{map[string]interface{}{"limit": 10, "window": "1m", "key": "user"}, nil, true},
+		{map[string]interface{}{"limit": 10, "window": "1m", "key": "header:"}, nil, true},
+		{"often", nil, true},
+	}
+
+	for i, testCase := range testCases {
+		failMsg := "test case %d failed"
+		extensions := Extensions{}
+		if testCase.value != nil {
+			extensions[RateLimitExtension] = testCase.value
+		}
+		limit, err := extensions.RateLimit()
+		if testCase.isErr {
+			assert.NotNil(r.T(), err, failMsg, i)
+			continue
+		}
+		assert.Nil(r.T(), err, failMsg, i)
+		assert.Equal(r.T(), testCase.expected, limit, failMsg, i)
+	}
+}
+
+func (r *RateLimitSuite) TestSetRateLimit() {
+	extensions := Extensions{}
+	limit := &RateLimit{Limit: 100, Window: "1m", Key: "header:X-API-Key"}
+	extensions.SetRateLimit(limit)
+	decoded, err := extensions.RateLimit()
+	assert.Nil(r.T(), err)
+	assert.Equal(r.T(), limit, decoded)
+
+	extensions.SetRateLimit(nil)
+	assert.Empty(r.T(), extensions)
+}
+
+func (r *RateLimitSuite) TestWindowDuration() {
+	window, err := RateLimit{Limit: 1, Window: "1m30s"}.WindowDuration()
+	assert.Nil(r.T(), err)
+	assert.Equal(r.T(), 90*time.Second, window)
+}
+
+func (r *RateLimitSuite) TestString() {
+	assert.Equal(r.T(), "100 requests per 1m0s per client address", RateLimit{Limit: 100, Window: "1m", Key: RateLimitByIP}.String())
+	assert.Equal(r.T(), "5 requests per 1s per X-API-Key header", RateLimit{Limit: 5, Window: "1s", Key: "header:X-API-Key"}.String())
+}
+
+func (r *RateLimitSuite) TestRateLimits() {
+	limits, err := petstore(r.T(), ratelimitPatch).RateLimits()
+	assert.Nil(r.T(), err)
+	assert.Equal(r.T(), map[string]*RateLimit{
+		"/paths/~1pets/get":             {Limit: 10, Window: "1s", Key: "header:X-API-Key"},
+		"/paths/~1pets/post":            {Limit: 1000, Window: "1h", Key: RateLimitByIP},
+		"/paths/~1pets~1{petId}/get":    {Limit: 50, Window: "1m", Key: RateLimitByIP},
+		"/paths/~1pets~1{petId}/delete": {Limit: 50, Window: "1m", Key: RateLimitByIP},
+	}, limits)
+
+	_, err = petstore(r.T(), ratelimitPatch, `
+paths:
+  /pets/{petId}:
+    x-ratelimit:
+      window: null
+`).RateLimits()
+	assert.NotNil(r.T(), err)
+}
+
+func TestRateLimitSuite(t *testing.T) {
+	suite.Run(t, new(RateLimitSuite))
+}
@@ -0,0 +1,434 @@
+package oas
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/pkg/errors"
+)
+
+// LatestVersion is the version alias resolving to the highest published
+// version of a document.
+const LatestVersion = "latest"
+
+// ErrVersionExists is returned when publishing a version which was already
+// published with a different content. Published versions are immutable.
+var ErrVersionExists = errors.New("version already published with different content")
+
+// ErrVersionNotFound is returned when fetching a version which was never
+// published.
+var ErrVersionNotFound = errors.New("version not found")
+
+// Registry describes a store of versioned API documents.
+type Registry interface {
+	// Publish stores the document under the name and the version found in
+	// its info object. Publishing an identical document again succeeds,
+	// publishing a different document under an existing version fails with
+	// ErrVersionExists.
+	Publish(ctx context.Context, name string, doc *OpenAPI) error
+
+	// Fetch returns the document published under the name and version. The
+	// LatestVersion alias resolves to the highest published version.
+	Fetch(ctx context.Context, name string, version string) (*OpenAPI, error)
+
+	// Versions returns the published versions of the named document ordered
+	// from lowest to highest.
+	Versions(ctx context.Context, name string) ([]string, error)
+}
+
+// FileRegistry is a Registry storing documents on the filesystem using the
+// "<root>/<name>/<version>.json" layout.
+type FileRegistry struct {
+	// Root describes the directory holding the documents.
+	Root string
+}
+
+// Publish stores the document under the name and its info version.
+func (r FileRegistry) Publish(ctx context.Context, name string, doc *OpenAPI) error {
+	version := doc.Info.Version
+	if err := checkRegistryName(name, version); err != nil {
+		return err
+	}
+
+	rbytes, err := json.Marshal(doc)
+	if err != nil {
+		return errors.WithStack(err)
+	}
+
+	path := filepath.Join(r.Root, name, version+".json")
+	existing, err := ioutil.ReadFile(path)
+	switch {
+	case err == nil && bytes.Equal(existing, rbytes):
+		return nil
+	case err == nil:
+		return errors.Wrapf(ErrVersionExists, "%s@%s", name, version)
+	case !os.IsNotExist(err):
+		return errors.WithStack(err)
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return errors.WithStack(err)
+	}
+	file, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0644)
+	if err != nil {
+		if os.IsExist(err) {
+			return errors.Wrapf(ErrVersionExists, "%s@%s", name, version)
+		}
+		return errors.WithStack(err)
+	}
+	if _, err := file.Write(rbytes); err != nil {
+		file.Close()
+		return errors.WithStack(err)
+	}
+	return errors.WithStack(file.Close())
+}
+
+// Fetch returns the document published under the name and version.
+func (r FileRegistry) Fetch(ctx context.Context, name string, version string) (*OpenAPI, error) {
+	version, err := resolveVersion(ctx, r, name, version)
+	if err != nil {
+		return nil, err
+	}
+
+	rbytes, err := ioutil.ReadFile(filepath.Join(r.Root, name, version+".json"))
+	if os.IsNotExist(err) {
+		return nil, errors.Wrapf(ErrVersionNotFound, "%s@%s", name, version)
+	}
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+
+	doc := &OpenAPI{}
+	if err := json.Unmarshal(rbytes, doc); err != nil {
+		return nil, errors.WithStack(err)
+	}
+	return doc, nil
+}
+
+// Versions returns the published versions of the named document.
+func (r FileRegistry) Versions(ctx context.Context, name string) ([]string, error) {
+	if err := checkRegistryName(name, "0"); err != nil {
+		return nil, err
+	}
+
+	files, err := ioutil.ReadDir(filepath.Join(r.Root, name))
+	if os.IsNotExist(err) {
+		return []string{}, nil
+	}
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+
+	versions := make([]string, 0, len(files))
+	for _, file := range files {
+		if !file.IsDir() && strings.HasSuffix(file.Name(), ".json") {
+			versions = append(versions, strings.TrimSuffix(file.Name(), ".json"))
+		}
+	}
+	sortVersions(versions)
+	return versions, nil
+}
+
+// HTTPRegistry is a Registry backed by an HTTP server exposing the
+// documents at "<base>/<name>/<version>" and the JSON array of published
+// versions at "<base>/<name>". Documents are published with PUT requests
+// carrying "If-None-Match: *" so the server can reject overwrites with
+// 412 Precondition Failed. Fetched documents are cached by ETag and
+// revalidated with conditional requests.
+type HTTPRegistry struct {
+	// BaseURL describes the URL of the registry.
+	BaseURL string
+
+	// Client describes the HTTP client used to reach the registry. Defaults
+	// to http.DefaultClient.
+	Client *http.Client
+
+	mutex sync.Mutex
+	cache map[string]httpRegistryEntry
+}
+
+// httpRegistryEntry holds a fetched document along with its ETag.
+type httpRegistryEntry struct {
+	etag string
+	data []byte
+}
+
+// Publish stores the document under the name and its info version.
+func (r *HTTPRegistry) Publish(ctx context.Context, name string, doc *OpenAPI) error {
+	version := doc.Info.Version
+	if err := checkRegistryName(name, version); err != nil {
+		return err
+	}
+
+	rbytes, err := json.Marshal(doc)
+	if err != nil {
+		return errors.WithStack(err)
+	}
+
+	req, err := http.NewRequest(http.MethodPut, r.url(name, version), bytes.NewReader(rbytes))
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("If-None-Match", "*")
+
+	resp, err := r.client().Do(req.WithContext(ctx))
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	defer resp.Body.Close()
+
+	switch {
+	case resp.StatusCode == http.StatusPreconditionFailed:
+		existing, err := r.Fetch(ctx, name, version)
+		if err != nil {
+			return err
+		}
+		if ebytes, err := json.Marshal(existing); err == nil && bytes.Equal(ebytes, rbytes) {
+			return nil
+		}
+		return errors.Wrapf(ErrVersionExists, "%s@%s", name, version)
+	case resp.StatusCode < 200 || resp.StatusCode > 299:
+		return errors.Errorf("%s@%s: unexpected status %s", name, version, resp.Status)
+	}
+	return nil
+}
+
+// Fetch returns the document published under the name and version.
+func (r *HTTPRegistry) Fetch(ctx context.Context, name string, version string) (*OpenAPI, error) {
+	version, err := resolveVersion(ctx, r, name, version)
+	if err != nil {
+		return nil, err
+	}
+
+	rbytes, err := r.get(ctx, r.url(name, version))
+	if err != nil {
+		return nil, errors.Wrapf(err, "%s@%s", name, version)
+	}
+
+	doc := &OpenAPI{}
+	if err := json.Unmarshal(rbytes, doc); err != nil {
+		return nil, errors.WithStack(err)
+	}
+	return doc, nil
+}
+
+// Versions returns the published versions of the named document.
+func (r *HTTPRegistry) Versions(ctx context.Context, name string) ([]string, error) {
+	if err := checkRegistryName(name, "0"); err != nil {
+		return nil, err
+	}
+
+	rbytes, err := r.get(ctx, r.url(name))
+	if errors.Cause(err) == ErrVersionNotFound {
+		return []string{}, nil
+	}
+	if err != nil {
+		return nil, errors.Wrap(err, name)
+	}
+
+	versions := make([]string, 0)
+	if err := json.Unmarshal(rbytes, &versions); err != nil {
+		return nil, errors.WithStack(err)
+	}
+	sortVersions(versions)
+	return versions, nil
+}
+
+// get retrieves the resource at the URL, revalidating cached content.
+func (r *HTTPRegistry) get(ctx context.Context, rawurl string) ([]byte, error) {
+	req, err := http.NewRequest(http.MethodGet, rawurl, nil)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+
+	r.mutex.Lock()
+	cached, ok := r.cache[rawurl]
+	r.mutex.Unlock()
+	if ok {
+		req.Header.Set("If-None-Match", cached.etag)
+	}
+
+	resp, err := r.client().Do(req.WithContext(ctx))
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+	defer resp.Body.Close()
+
+	switch {
+	case resp.StatusCode == http.StatusNotModified && ok:
+		return cached.data, nil
+	case resp.StatusCode == http.StatusNotFound:
+		return nil, errors.WithStack(ErrVersionNotFound)
+	case resp.StatusCode < 200 || resp.StatusCode > 299:
+		return nil, errors.Errorf("unexpected status %s", resp.Status)
+	}
+
+	rbytes, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+
+	if etag := resp.Header.Get("ETag"); etag != "" {
+		r.mutex.Lock()
+		if r.cache == nil {
+			r.cache = map[string]httpRegistryEntry{}
+		}
+		r.cache[rawurl] = httpRegistryEntry{etag: etag, data: rbytes}
+		r.mutex.Unlock()
+	}
+	return rbytes, nil
+}
+
+func (r *HTTPRegistry) url(segments ...string) string {
+	for i := range segments {
+		segments[i] = url.PathEscape(segments[i])
+	}
+	return strings.TrimSuffix(r.BaseURL, "/") + "/" + strings.Join(segments, "/")
+}
+
+func (r *HTTPRegistry) client() *http.Client {
+	if r.Client != nil {
+		return r.Client
+	}
+	return http.DefaultClient
+}
+
+// resolveVersion resolves the LatestVersion alias against the registry.
+func resolveVersion(ctx context.Context, registry Registry, name string, version string) (string, error) {
+	if err := checkRegistryName(name, version); err != nil {
+		return "", err
+	}
+	if version != LatestVersion {
+		return version, nil
+	}
+
+	versions, err := registry.Versions(ctx, name)
+	if err != nil {
+		return "", err
+	}
+	if len(versions) == 0 {
+		return "", errors.Wrapf(ErrVersionNotFound, "%s@%s", name, version)
+	}
+	return versions[len(versions)-1], nil
+}
+
+// checkRegistryName rejects names and versions which cannot be safely used
+// as a path segment.
+func checkRegistryName(name string, version string) error {
+	for _, value := range []string{name, version} {
+		if value == "" || value == "." || value == ".." || strings.ContainsAny(value, `/\`) {
+			return errors.Errorf("invalid registry name or version %q", value)
+		}
+	}
+	return nil
+}
+
+// sortVersions sorts the versions in ascending semantic version precedence.
+func sortVersions(versions []string) {
+	sort.SliceStable(versions, func(i, j int) bool {
+		return compareVersions(versions[i], versions[j]) < 0
+	})
+}
+
+// compareVersions compares two semantic versions returning -1, 0 or 1.
+// Versions which are not semantic versions sort before the ones that are and
+// are compared lexically amongst themselves.
+func compareVersions(a string, b string) int {
+	av, aok := parseVersion(a)
+	bv, bok := parseVersion(b)
+	switch {
+	case !aok && !bok:
+		return strings.Compare(a, b)
+	case !aok:
+		return -1
+	case !bok:
+		return 1
+	}
+
+	for i := 0; i < 3; i++ {
+		if av.numbers[i] != bv.numbers[i] {
+			if av.numbers[i] < bv.numbers[i] {
+				return -1
+			}
+			return 1
+		}
+	}
+
+	switch {
+	case av.prerelease == bv.prerelease:
+		return 0
+	case av.prerelease == "":
+		return 1
+	case bv.prerelease == "":
+		return -1
+	}
+
+	ap, bp := strings.Split(av.prerelease, "."), strings.Split(bv.prerelease, ".")
+	for i := 0; i < len(ap) && i < len(bp); i++ {
+		an, aerr := strconv.ParseUint(ap[i], 10, 64)
+		bn, berr := strconv.ParseUint(bp[i], 10, 64)
+		switch {
+		case aerr == nil && berr == nil && an != bn:
+			if an < bn {
+				return -1
+			}
+			return 1
+		case aerr == nil && berr != nil:
+			return -1
+		case aerr != nil && berr == nil:
+			return 1
+		case ap[i] != bp[i]:
+			return strings.Compare(ap[i], bp[i])
+		}
+	}
+	switch {
+	case len(ap) < len(bp):
+		return -1
+	case len(ap) > len(bp):
+		return 1
+	}
+	return 0
+}
+
+// semanticVersion holds the parsed components of a semantic version.
+type semanticVersion struct {
+	numbers    [3]uint64
+	prerelease string
+}
+
+// parseVersion parses a semantic version with an optional "v" prefix. The
+// build metadata is ignored.
+func parseVersion(value string) (semanticVersion, bool) {
+	version := semanticVersion{}
+	value = strings.TrimPrefix(value, "v")
+	if i := strings.Index(value, "+"); i >= 0 {
+		value = value[:i]
+	}
+	if i := strings.Index(value, "-"); i >= 0 {
+		value, version.prerelease = value[:i], value[i+1:]
+	}
+
+	parts := strings.Split(value, ".")
+	if len(parts) != 3 {
+		return version, false
+	}
+	for i, part := range parts {
+		number, err := strconv.ParseUint(part, 10, 64)
+		if err != nil {
+			return version, false
+		}
+		version.numbers[i] = number
+	}
+	return version, true
+}
@@ -0,0 +1,140 @@
+package oas
+
+import (
+	"context"
+	"crypto/sha1"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+)
+
+type RegistrySuite struct {
+	suite.Suite
+}
+
+// testRegistryServer implements the HTTPRegistry protocol in memory.
+type testRegistryServer struct {
+	mutex       sync.Mutex
+	documents   map[string][]byte
+	notModified int
+}
+
+func (r *testRegistryServer) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	path := strings.Trim(req.URL.Path, "/")
+	switch req.Method {
+	case http.MethodPut:
+		if _, ok := r.documents[path]; ok && req.Header.Get("If-None-Match") == "*" {
+			w.WriteHeader(http.StatusPreconditionFailed)
+			return
+		}
+		rbytes, _ := ioutil.ReadAll(req.Body)
+		r.documents[path] = rbytes
+		w.WriteHeader(http.StatusCreated)
+	case http.MethodGet:
+		rbytes, ok := r.documents[path]
+		if !ok {
+			versions := make([]string, 0)
+			for key := range r.documents {
+				if strings.HasPrefix(key, path+"/") {
+					versions = append(versions, strings.TrimPrefix(key, path+"/"))
+				}
+			}
+			if len(versions) == 0 {
+				w.WriteHeader(http.StatusNotFound)
+				return
+			}
+			rbytes, _ = json.Marshal(versions)
+		}
+		etag := fmt.Sprintf(`"%x"`, sha1.Sum(rbytes))
+		if req.Header.Get("If-None-Match") == etag {
+			r.notModified++
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+		w.Header().Set("ETag", etag)
+		w.Write(rbytes)
+	}
+}
+
+func (r *RegistrySuite) registryDocument(version string, title string) *OpenAPI {
+	return &OpenAPI{
+		OpenAPI: "3.0.0",
+		Info:    Info{Title: title, Version: version},
+		Paths:   Paths{PathItems: NewPathItems(map[string]*PathItem{"/pets": {Get: &Operation{OperationID: "listPets"}}})},
+	}
+}
+
+func (r *RegistrySuite) testRegistry(registry Registry) {
+	ctx := context.Background()
+
+	for _, version := range []string{"1.2.0", "1.10.0", "2.0.0-beta.1", "1.9.3"} {
+		assert.NoError(r.T(), registry.Publish(ctx, "petstore", r.registryDocument(version, "Petstore")))
+	}
+	assert.NoError(r.T(), registry.Publish(ctx, "petstore", r.registryDocument("1.2.0", "Petstore")))
+
+	err := registry.Publish(ctx, "petstore", r.registryDocument("1.2.0", "Changed"))
+	assert.Equal(r.T(), ErrVersionExists, errors.Cause(err))
+
+	versions, err := registry.Versions(ctx, "petstore")
+	assert.NoError(r.T(), err)
+	assert.Equal(r.T(), []string{"1.2.0", "1.9.3", "1.10.0", "2.0.0-beta.1"}, versions)
+
+	versions, err = registry.Versions(ctx, "unknown")
+	assert.NoError(r.T(), err)
+	assert.Equal(r.T(), []string{}, versions)
+
+	doc, err := registry.Fetch(ctx, "petstore", LatestVersion)
+	assert.NoError(r.T(), err)
+	assert.EqualValues(r.T(), r.registryDocument("2.0.0-beta.1", "Petstore"), doc)
+
+	doc, err = registry.Fetch(ctx, "petstore", "1.9.3")
+	assert.NoError(r.T(), err)
+	assert.EqualValues(r.T(), r.registryDocument("1.9.3", "Petstore"), doc)
+
+	_, err = registry.Fetch(ctx, "petstore", "3.0.0")
+	assert.Equal(r.T(), ErrVersionNotFound, errors.Cause(err))
+
+	_, err = registry.Fetch(ctx, "../petstore", "1.9.3")
+	assert.Error(r.T(), err)
+}
+
+func (r *RegistrySuite) TestFileRegistry() {
+	root, err := ioutil.TempDir("", "oas-registry")
+	if err != nil {
+		r.T().Fatal(err)
+	}
+	defer os.RemoveAll(root)
+
+	r.testRegistry(FileRegistry{Root: root})
+}
+
+func (r *RegistrySuite) TestHTTPRegistry() {
+	handler := &testRegistryServer{documents: map[string][]byte{}}
+	server := httptest.NewServer(handler)
+	defer server.Close()
+
+	registry := &HTTPRegistry{BaseURL: server.URL + "/specs/"}
+	r.testRegistry(registry)
+
+	notModified := handler.notModified
+	_, err := registry.Fetch(context.Background(), "petstore", "1.9.3")
+	assert.NoError(r.T(), err)
+	assert.Equal(r.T(), notModified+1, handler.notModified)
+}
+
+func TestRegistrySuite(t *testing.T) {
+	suite.Run(t, new(RegistrySuite))
+}
@@ -0,0 +1,289 @@
+package oas
+
+import (
+	"github.com/pkg/errors"
+)
+
+// ErrRefUnresolved is returned by Resolver when a reference does not point
+// to a component of the expected kind declared by the document.
+var ErrRefUnresolved = errors.New("reference cannot be resolved")
+
+// ErrRefCycle is returned by Resolver when following a reference leads back
+// to a reference already followed.
+var ErrRefCycle = errors.New("reference cycle")
+
+// Resolver resolves local references to the components of a document (e.g.
+// "#/components/schemas/Pet"). Components which are references themselves
+// are followed until a component declaring its content is reached.
+type Resolver struct {
+	doc *OpenAPI
+}
+
+// NewResolver returns a resolver of the references to the components of the
+// document. The document is read on every call, so components added after
+// the resolver is created are resolved as well.
+func NewResolver(doc *OpenAPI) *Resolver {
+	return &Resolver{doc: doc}
+}
+
+// ResolveSchema returns the schema the reference points to.
+func (r *Resolver) ResolveSchema(ref string) (*Schema, error) {
+	_, node, err := r.resolveRef(ref, "schemas")
+	if err != nil {
+		return nil, err
+	}
+	return node.(*Schema), nil
+}
+
+// ResolveResponse returns the response the reference points to.
+func (r *Resolver) ResolveResponse(ref string) (*Response, error) {
+	_, node, err := r.resolveRef(ref, "responses")
+	if err != nil {
+		return nil, err
+	}
+	return node.(*Response), nil
+}
+
+// ResolveParameter returns the parameter the reference points to.
+func (r *Resolver) ResolveParameter(ref string) (*Parameter, error) {
+	_, node, err := r.resolveRef(ref, "parameters")
+	if err != nil {
+		return nil, err
+	}
+	return node.(*Parameter), nil
+}
+
+// ResolveExample returns the example the reference points to.
+func (r *Resolver) ResolveExample(ref string) (*Example, error) {
+	_, node, err := r.resolveRef(ref, "examples")
+	if err != nil {
+		return nil, err
+	}
+	return node.(*Example), nil
+}
+
+// ResolveRequestBody returns the request body the reference points to.
+func (r *Resolver) ResolveRequestBody(ref string) (*RequestBody, error) {
+	_, node, err := r.resolveRef(ref, "requestBodies")
+	if err != nil {
+		return nil, err
+	}
+	return node.(*RequestBody), nil
+}
+
+// ResolveHeader returns the header the reference points to.
+func (r *Resolver) ResolveHeader(ref string) (*Header, error) {
+	_, node, err := r.resolveRef(ref, "headers")
+	if err != nil {
+		return nil, err
+	}
+	return node.(*Header), nil
+}
+
+// ResolveSecurityScheme returns the security scheme the reference points to.
+func (r *Resolver) ResolveSecurityScheme(ref string) (*SecurityScheme, error) {
+	_, node, err := r.resolveRef(ref, "securitySchemes")
+	if err != nil {
+		return nil, err
+	}
+	return node.(*SecurityScheme), nil
+}
+
+// ResolveLink returns the link the reference points to.
+func (r *Resolver) ResolveLink(ref string) (*Link, error) {
+	_, node, err := r.resolveRef(ref, "links")
+	if err != nil {
+		return nil, err
+	}
+	return node.(*Link), nil
+}
+
+// ResolveCallback returns the callback the reference points to.
+func (r *Resolver) ResolveCallback(ref string) (*Callback, error) {
+	_, node, err := r.resolveRef(ref, "callbacks")
+	if err != nil {
+		return nil, err
+	}
+	return node.(*Callback), nil
+}
+
+// Resolve returns the target of an object holding a $ref field (e.g.
+// *Schema, *Parameter or *Response), which is of the same type as the
+// object. Objects without a reference are returned as they are.
+func (r *Resolver) Resolve(node interface{}) (interface{}, error) {
+	ref := refOf(node)
+	if ref == nil || *ref == "" {
+		return node, nil
+	}
+	kind, ok := componentKind(node)
+	if !ok {
+		return nil, errors.Wrapf(ErrRefUnresolved, "%q", *ref)
+	}
+	_, node, err := r.resolveRef(*ref, kind)
+	return node, err
+}
+
+// schema returns the schema, following its reference. Unresolvable
+// references yield nil.
+func (r *Resolver) schema(schema *Schema) *Schema {
+	if schema == nil || schema.Ref == "" {
+		return schema
+	}
+	resolved, err := r.ResolveSchema(schema.Ref)
+	if err != nil {
+		return nil
+	}
+	return resolved
+}
+
+// parameter returns the parameter and its pointer, following its reference
+// to the parameter of the components. Unresolvable references yield nil.
+func (r *Resolver) parameter(ptr string, parameter *Parameter) (string, *Parameter) {
+	if parameter == nil || parameter.Ref == "" {
+		return ptr, parameter
+	}
+	target, node, err := r.resolveRef(parameter.Ref, "parameters")
+	if err != nil {
+		return ptr, nil
+	}
+	return target, node.(*Parameter)
+}
+
+// requestBody returns the request body and its pointer, following its
+// reference to the request body of the components. Unresolvable references
+// yield nil.
+func (r *Resolver) requestBody(ptr string, body *RequestBody) (string, *RequestBody) {
+	if body == nil || body.Ref == "" {
+		return ptr, body
+	}
+	target, node, err := r.resolveRef(body.Ref, "requestBodies")
+	if err != nil {
+		return ptr, nil
+	}
+	return target, node.(*RequestBody)
+}
+
+// response returns the response and its pointer, following its reference to
+// the response of the components. Unresolvable references yield nil.
+func (r *Resolver) response(ptr string, response *Response) (string, *Response) {
+	if response == nil || response.Ref == "" {
+		return ptr, response
+	}
+	target, node, err := r.resolveRef(response.Ref, "responses")
+	if err != nil {
+		return ptr, nil
+	}
+	return target, node.(*Response)
+}
+
+// header returns the header and its pointer, following its reference to the
+// header of the components. Unresolvable references yield nil.
+func (r *Resolver) header(ptr string, header *Header) (string, *Header) {
+	if header == nil || header.Ref == "" {
+		return ptr, header
+	}
+	target, node, err := r.resolveRef(header.Ref, "headers")
+	if err != nil {
+		return ptr, nil
+	}
+	return target, node.(*Header)
+}
+
+// componentKind returns the name of the components map holding objects of
+// the type of the node. Path items are not components.
+func componentKind(node interface{}) (string, bool) {
+	switch node.(type) {
+	case *Schema:
+		return "schemas", true
+	case *Response:
+		return "responses", true
+	case *Parameter:
+		return "parameters", true
+	case *Example:
+		return "examples", true
+	case *RequestBody:
+		return "requestBodies", true
+	case *Header:
+		return "headers", true
+	case *SecurityScheme:
+		return "securitySchemes", true
+	case *Link:
+		return "links", true
+	case *Callback:
+		return "callbacks", true
+	}
+	return "", false
+}
+
+// resolveRef returns the component of the kind the reference points to and
+// its pointer, following components which are references themselves.
+func (r *Resolver) resolveRef(ref string, kind string) (string, interface{}, error) {
+	visited := map[string]bool{}
+	for {
+		name, ok := componentName(ref, kind)
+		if !ok {
+			return "", nil, errors.Wrapf(ErrRefUnresolved, "%q is not a reference to %s", ref, kind)
+		}
+		if visited[name] {
+			return "", nil, errors.Wrapf(ErrRefCycle, "%q", ref)
+		}
+		visited[name] = true
+		node := r.component(kind, name)
+		if node == nil {
+			return "", nil, errors.Wrapf(ErrRefUnresolved, "%q", ref)
+		}
+		if next := refOf(node); *next != "" {
+			ref = *next
+			continue
+		}
+		return join("/components", kind, name), node, nil
+	}
+}
+
+// component returns the component of the kind declared under the name, or
+// nil if the document declares none.
+func (r *Resolver) component(kind string, name string) interface{} {
+	if r.doc == nil || r.doc.Components == nil {
+		return nil
+	}
+	components := r.doc.Components
+	switch kind {
+	case "schemas":
+		if node := components.Schemas[name]; node != nil {
+			return node
+		}
+	case "responses":
+		if node := components.Responses[name]; node != nil {
+			return node
+		}
+	case "parameters":
+		if node := components.Parameters[name]; node != nil {
+			return node
+		}
+	case "examples":
+		if node := components.Examples[name]; node != nil {
+			return node
+		}
+	case "requestBodies":
+		if node := components.RequestBodies[name]; node != nil {
+			return node
+		}
+	case "headers":
+		if node := components.Headers[name]; node != nil {
+			return node
+		}
+	case "securitySchemes":
+		if node := components.SecuritySchemes[name]; node != nil {
+			return node
+		}
+	case "links":
+		if node := components.Links[name]; node != nil {
+			return node
+		}
+	case "callbacks":
+		if node := components.Callbacks[name]; node != nil {
+			return node
+		}
+	}
+	return nil
+}
@@ -0,0 +1,152 @@
+package oas
+
+import (
+	"testing"
+
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+)
+
+type ResolverSuite struct {
+	suite.Suite
+}
+
+// resolverPatch is the patch of the petstore fixture adding the components
+// the suite resolves, references to them and cycles of references.
+const resolverPatch = `
+components:
+  schemas:
+    Animal:
+      $ref: "#/components/schemas/Pet"
+    Loop:
+      $ref: "#/components/schemas/Cycle"
+    Cycle:
+      $ref: "#/components/schemas/Loop"
+  responses:
+    NotFound:
+      description: not found
+  parameters:
+    limit:
+      name: limit
+      in: query
+    offset:
+      $ref: "#/components/parameters/limit"
+    loop:
+      $ref: "#/components/parameters/loop"
+  headers:
+    X-Rate-Limit:
+      description: rate limit
+  securitySchemes:
+    apiKey:
+      type: apiKey
+      name: X-API-Key
+      in: header
+`
+
+func (r *ResolverSuite) TestResolveSchema() {
+	doc := petstore(r.T(), resolverPatch)
+	resolver := NewResolver(doc)
+
+	testCases := []struct {
+		ref      string
+		expected *Schema
+		err      error
+	}{
+		{"#/components/schemas/Pet", doc.Components.Schemas["Pet"], nil},
+		{"#/components/schemas/Animal", doc.Components.Schemas["Pet"], nil},
+		{"#/components/schemas/Owner", nil, ErrRefUnresolved},
+		{"#/components/responses/NotFound", nil, ErrRefUnresolved},
+		{"#/components/schemas/Pet/properties/id", nil, ErrRefUnresolved},
+		{"#/components/schemas/Loop", nil, ErrRefCycle},
+	}
+
+	for i, testCase := range testCases {
+		failMsg := "test case %d failed"
+		schema, err := resolver.ResolveSchema(testCase.ref)
+		assert.Equal(r.T(), testCase.err, errors.Cause(err), failMsg, i)
+		assert.Equal(r.T(), testCase.expected, schema, failMsg, i)
+	}
+}
+
+func (r *ResolverSuite) TestResolveComponents() {
+	doc := petstore(r.T(), resolverPatch)
+	resolver := NewResolver(doc)
+
+	response, err := resolver.ResolveResponse("#/components/responses/NotFound")
+	assert.Nil(r.T(), err)
+	assert.Equal(r.T(), doc.Components.Responses["NotFound"], response)
+
+	parameter, err := resolver.ResolveParameter("#/components/parameters/limit")
+	assert.Nil(r.T(), err)
+	assert.Equal(r.T(), doc.Components.Parameters["limit"], parameter)
+
+	header, err := resolver.ResolveHeader("#/components/headers/X-Rate-Limit")
+	assert.Nil(r.T(), err)
+	assert.Equal(r.T(), doc.Components.Headers["X-Rate-Limit"], header)
+
+	scheme, err := resolver.ResolveSecurityScheme("#/components/securitySchemes/apiKey")
+	assert.Nil(r.T(), err)
+	assert.Equal(r.T(), doc.Components.SecuritySchemes["apiKey"], scheme)
+
+	_, err = resolver.ResolveRequestBody("#/components/requestBodies/Pet")
+	assert.Equal(r.T(), ErrRefUnresolved, errors.Cause(err))
+
+	_, err = NewResolver(&OpenAPI{}).ResolveExample("#/components/examples/Pet")
+	assert.Equal(r.T(), ErrRefUnresolved, errors.Cause(err))
+}
+
+func (r *ResolverSuite) TestResolve() {
+	doc := petstore(r.T(), resolverPatch)
+	resolver := NewResolver(doc)
+
+	inline := &Schema{Type: "string"}
+	testCases := []struct {
+		node     interface{}
+		expected interface{}
+		err      error
+	}{
+		{inline, inline, nil},
+		{&Schema{Ref: "#/components/schemas/Animal"}, doc.Components.Schemas["Pet"], nil},
+		{&Parameter{Header: Header{Ref: "#/components/parameters/limit"}}, doc.Components.Parameters["limit"], nil},
+		{&Response{Ref: "#/components/responses/NotFound"}, doc.Components.Responses["NotFound"], nil},
+		{&Response{Ref: "#/components/schemas/Pet"}, nil, ErrRefUnresolved},
+		{&PathItem{Ref: "#/components/pathItems/pets"}, nil, ErrRefUnresolved},
+		{"pets", "pets", nil},
+	}
+
+	for i, testCase := range testCases {
+		failMsg := "test case %d failed"
+		node, err := resolver.Resolve(testCase.node)
+		assert.Equal(r.T(), testCase.err, errors.Cause(err), failMsg, i)
+		assert.Equal(r.T(), testCase.expected, node, failMsg, i)
+	}
+}
+
+func (r *ResolverSuite) TestResolveWithPointer() {
+	doc := petstore(r.T(), resolverPatch)
+	resolver := NewResolver(doc)
+
+	ptr, parameter := resolver.parameter("/paths/~1pets/get/parameters/0", &Parameter{Header: Header{Ref: "#/components/parameters/offset"}})
+	assert.Equal(r.T(), "/components/parameters/limit", ptr)
+	assert.Equal(r.T(), doc.Components.Parameters["limit"], parameter)
+
+	ptr, parameter = resolver.parameter("/paths/~1pets/get/parameters/0", &Parameter{Header: Header{Ref: "#/components/parameters/loop"}})
+	assert.Equal(r.T(), "/paths/~1pets/get/parameters/0", ptr)
+	assert.Nil(r.T(), parameter)
+
+	ptr, response := resolver.response("/paths/~1pets/get/responses/404", &Response{Ref: "#/components/responses/NotFound"})
+	assert.Equal(r.T(), "/components/responses/NotFound", ptr)
+	assert.Equal(r.T(), doc.Components.Responses["NotFound"], response)
+
+	ptr, body := resolver.requestBody("/paths/~1pets/post/requestBody", nil)
+	assert.Equal(r.T(), "/paths/~1pets/post/requestBody", ptr)
+	assert.Nil(r.T(), body)
+
+	assert.Equal(r.T(), doc.Components.Schemas["Pet"], resolver.schema(&Schema{Ref: "#/components/schemas/Animal"}))
+	assert.Nil(r.T(), resolver.schema(&Schema{Ref: "#/components/schemas/Loop"}))
+}
+
+func TestResolverSuite(t *testing.T) {
+	suite.Run(t, new(ResolverSuite))
+}
@@ -0,0 +1,160 @@
+package oas
+
+import (
+	"github.com/pkg/errors"
+)
+
+// ResourceOptions describes the resource declared by DeclareResource.
+type ResourceOptions struct {
+	// Name describes the singular name of the resource (e.g. "Pet"), used as
+	// the name of its component schema and in the operationIds.
+	Name string
+
+	// Plural describes the plural name of the resource. Defaults to the name
+	// suffixed with "s".
+	Plural string
+
+	// Schema describes the schema of the resource, registered in the
+	// components under the name. Defaults to the component schema already
+	// declared under the name.
+	Schema *Schema
+
+	// Path describes the path of the collection. Defaults to the camelCase
+	// plural name (e.g. "/pets"), items are addressed by the collection path
+	// followed by the identifier parameter (e.g. "/pets/{petId}").
+	Path string
+
+	// IDParameter describes the name of the path parameter identifying an
+	// item. Defaults to the camelCase name suffixed with "Id" (e.g. "petId").
+	IDParameter string
+
+	// IDSchema describes the schema of the identifier. Defaults to a string.
+	IDSchema *Schema
+
+	// Pagination describes the pagination of the list operation. Defaults to
+	// cursor pagination with the conventional names.
+	Pagination *Pagination
+}
+
+// DeclareResource declares the five standard operations of a resource:
+// list (GET on the collection, paginated), create (POST on the collection,
+// 201 with a Location header), get (GET on the item), update (PUT on the
+// item) and delete (DELETE on the item, 204). Operations on an item answer
+// 404 with the "NotFound" component response, which is added unless a
+// component of the same name exists. The operations are named after the
+// resource (e.g. "listPets", "createPet", "getPet", "updatePet" and
+// "deletePet"). An error is returned when a path or an operationId of the
+// resource is already declared.
+func (r *OpenAPI) DeclareResource(opts ResourceOptions) error {
+	if opts.Name == "" {
+		return errors.New("missing resource name")
+	}
+	name := PascalCase.Format(opts.Name)
+	if opts.Plural == "" {
+		opts.Plural = name + "s"
+	}
+	plural := PascalCase.Format(opts.Plural)
+	if opts.Path == "" {
+		opts.Path = "/" + CamelCase.Format(plural)
+	}
+	if opts.IDParameter == "" {
+		opts.IDParameter = CamelCase.Format(name) + "Id"
+	}
+	if opts.IDSchema == nil {
+		opts.IDSchema = &Schema{Type: "string"}
+	}
+	if opts.Pagination == nil {
+		opts.Pagination = NewPagination(CursorPagination)
+	}
+	itemPath := opts.Path + "/{" + opts.IDParameter + "}"
+
+	for _, path := range []string{opts.Path, itemPath} {
+		if r.Paths.PathItems.Get(path) != nil {
+			return errors.Errorf("path %q is already declared", path)
+		}
+	}
+	ids := []string{"list" + plural, "create" + name, "get" + name, "update" + name, "delete" + name}
+	for _, id := range ids {
+		if _, ok := r.operationByID(id); ok {
+			return errors.Errorf("operation %q is already declared", id)
+		}
+	}
+	if err := opts.Pagination.validate(); err != nil {
+		return err
+	}
+
+	if r.Components == nil {
+		r.Components = &Components{}
+	}
+	if r.Components.Schemas == nil {
+		r.Components.Schemas = map[string]*Schema{}
+	}
+	switch {
+	case opts.Schema != nil:
+		r.Components.Schemas[name] = opts.Schema
+	case r.Components.Schemas[name] == nil:
+		return errors.Errorf("schema %q not found", name)
+	}
+	if r.Components.Responses == nil {
+		r.Components.Responses = map[string]*Response{}
+	}
+	if _, ok := r.Components.Responses["NotFound"]; !ok {
+		r.Components.Responses["NotFound"] = &Response{Description: "Not Found. The resource does not exist."}
+	}
+
+	ref := "#/components/schemas/" + name
+	body := func() *RequestBody {
+		return &RequestBody{
+			Required: true,
+			Content:  map[string]*MediaType{"application/json": {Schema: &Schema{Ref: ref}}},
+		}
+	}
+	response := func(description string) *Response {
+		return &Response{
+			Description: description,
+			Content:     map[string]*MediaType{"application/json": {Schema: &Schema{Ref: ref}}},
+		}
+	}
+	notFound := &Response{Ref: "#/components/responses/NotFound"}
+
+	created := response("The created " + name + ".")
+	created.Headers = map[string]*Header{
+		LocationHeader: {Description: "URL of the created " + name + ".", Schema: &Schema{Type: "string"}},
+	}
+	r.Paths.PathItems.Set(opts.Path, &PathItem{
+		Get: &Operation{
+			OperationID: ids[0],
+			Summary:     "List " + plural + ".",
+		},
+		Post: &Operation{
+			OperationID: ids[1],
+			Summary:     "Create a " + name + ".",
+			RequestBody: body(),
+			Responses:   NewResponses(map[string]*Response{"201": created}),
+		},
+	})
+	r.Paths.PathItems.Set(itemPath, &PathItem{
+		Parameters: []*Parameter{{
+			Name:   opts.IDParameter,
+			In:     "path",
+			Header: Header{Required: true, Schema: opts.IDSchema},
+		}},
+		Get: &Operation{
+			OperationID: ids[2],
+			Summary:     "Get a " + name + ".",
+			Responses:   NewResponses(map[string]*Response{"200": response("The " + name + "."), "404": notFound}),
+		},
+		Put: &Operation{
+			OperationID: ids[3],
+			Summary:     "Update a " + name + ".",
+			RequestBody: body(),
+			Responses:   NewResponses(map[string]*Response{"200": response("The updated " + name + "."), "404": notFound}),
+		},
+		Delete: &Operation{
+			OperationID: ids[4],
+			Summary:     "Delete a " + name + ".",
+			Responses:   NewResponses(map[string]*Response{"204": {Description: "The " + name + " is deleted."}, "404": notFound}),
+		},
+	})
+	return r.Paginate(opts.Path, "get", name, opts.Pagination)
+}
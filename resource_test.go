@@ -0,0 +1,97 @@
+package oas
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+)
+
+type ResourceSuite struct {
+	suite.Suite
+}
+
+func (r *ResourceSuite) TestDeclareResource() {
+	doc := petstore(r.T())
+	owner := &Schema{Type: "object", Properties: NewProperties(map[string]*Schema{"name": {Type: "string"}})}
+	assert.Nil(r.T(), doc.DeclareResource(ResourceOptions{Name: "owner", Schema: owner}))
+
+	assert.Equal(r.T(), owner, doc.Components.Schemas["Owner"])
+	assert.NotNil(r.T(), doc.Components.Schemas["OwnerCursorPage"])
+	assert.NotNil(r.T(), doc.Components.Responses["NotFound"])
+
+	ids := make([]string, 0)
+	for _, op := range doc.Paths.operations() {
+		ids = append(ids, op.method+" "+op.path+" "+op.operation.OperationID)
+	}
+	assert.Equal(r.T(), []string{
+		"get /owners listOwners",
+		"post /owners createOwner",
+		"get /owners/{ownerId} getOwner",
+		"put /owners/{ownerId} updateOwner",
+		"delete /owners/{ownerId} deleteOwner",
+		"get /pets listPets",
+		"post /pets createPet",
+		"get /pets/{petId} getPet",
+		"delete /pets/{petId} deletePet",
+	}, ids)
+
+	list := doc.Paths.PathItems.Get("/owners").Get
+	assert.Equal(r.T(), "#/components/schemas/OwnerCursorPage", list.Responses.Get("200").Content["application/json"].Schema.Ref)
+	assert.Len(r.T(), list.Parameters, 2)
+	pagination, err := list.Extensions.Pagination()
+	assert.Nil(r.T(), err)
+	assert.Equal(r.T(), CursorPagination, pagination.Style)
+
+	create := doc.Paths.PathItems.Get("/owners").Post
+	assert.True(r.T(), create.RequestBody.Required)
+	assert.NotNil(r.T(), create.Responses.Get("201").Headers[LocationHeader])
+
+	item := doc.Paths.PathItems.Get("/owners/{ownerId}")
+	assert.Equal(r.T(), "ownerId", item.Parameters[0].Name)
+	assert.True(r.T(), item.Parameters[0].Required)
+	assert.Equal(r.T(), "#/components/responses/NotFound", item.Delete.Responses.Get("404").Ref)
+	assert.Equal(r.T(), "The Owner is deleted.", item.Delete.Responses.Get("204").Description)
+
+	assert.NotNil(r.T(), doc.DeclareResource(ResourceOptions{Name: "Owner", Path: "/people"}))
+	assert.NotNil(r.T(), doc.DeclareResource(ResourceOptions{Name: "Pet"}))
+}
+
+func (r *ResourceSuite) TestDeclareResourceOptions() {
+	doc := petstore(r.T(), `
+components:
+  schemas:
+    Category:
+      type: object
+  responses:
+    NotFound:
+      description: Missing.
+`)
+	assert.Nil(r.T(), doc.DeclareResource(ResourceOptions{
+		Name:        "Category",
+		Plural:      "Categories",
+		IDParameter: "slug",
+		IDSchema:    &Schema{Type: "integer"},
+		Pagination:  NewPagination(OffsetPagination),
+	}))
+	assert.NotNil(r.T(), doc.Paths.PathItems.Get("/categories/{slug}"))
+	assert.Equal(r.T(), "listCategories", doc.Paths.PathItems.Get("/categories").Get.OperationID)
+	assert.Equal(r.T(), "integer", doc.Paths.PathItems.Get("/categories/{slug}").Parameters[0].Schema.Type)
+	assert.NotNil(r.T(), doc.Components.Schemas["CategoryOffsetPage"])
+	assert.Equal(r.T(), "Missing.", doc.Components.Responses["NotFound"].Description)
+
+	testCases := []ResourceOptions{
+		{},
+		{Name: "Owner"},
+		{Name: "Category", Path: "/kinds"},
+		{Name: "Tag", Schema: &Schema{Type: "object"}, Pagination: &Pagination{Style: "page"}},
+	}
+	for i, testCase := range testCases {
+		failMsg := "test case %d failed"
+		assert.NotNil(r.T(), doc.DeclareResource(testCase), failMsg, i)
+	}
+}
+
+func TestResourceSuite(t *testing.T) {
+	suite.Run(t, new(ResourceSuite))
+}
@@ -0,0 +1,225 @@
+package oas
+
+import (
+	"fmt"
+	"log"
+	"mime"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+// ResponsePolicy describes how a ResponseEnforcer handles responses the
+// matched operation does not declare.
+type ResponsePolicy int
+
+const (
+	// WarnUndeclaredResponses reports undeclared responses and writes them
+	// unchanged.
+	WarnUndeclaredResponses ResponsePolicy = iota
+
+	// RejectUndeclaredResponses reports undeclared responses and replaces
+	// them with 500 Internal Server Error.
+	RejectUndeclaredResponses
+)
+
+// UndeclaredResponseError describes a response whose status code or content
+// type the matched operation does not declare.
+type UndeclaredResponseError struct {
+	// Path describes the path template of the matched operation.
+	Path string
+
+	// Method describes the lowercase HTTP method of the matched operation.
+	Method string
+
+	// Status describes the status code written by the handler.
+	Status int
+
+	// ContentType describes the media type written by the handler, if the
+	// status code is declared.
+	ContentType string
+}
+
+// Error returns the string representation of the error.
+func (e *UndeclaredResponseError) Error() string {
+	operation := strings.ToUpper(e.Method) + " " + e.Path
+	if e.ContentType != "" {
+		return fmt.Sprintf("%s: undeclared content type %q for status %d", operation, e.ContentType, e.Status)
+	}
+	return fmt.Sprintf("%s: undeclared status %d", operation, e.Status)
+}
+
+// ResponseEnforcer restricts handlers to the status codes and content types
+// declared for the matched operation, catching undocumented responses during
+// development.
+type ResponseEnforcer struct {
+	// Doc describes the document references of the responses are resolved
+	// against.
+	Doc *OpenAPI
+
+	// Policy describes how undeclared responses are handled.
+	Policy ResponsePolicy
+
+	// Report is called for every undeclared response. When nil, undeclared
+	// responses are logged with the standard logger.
+	Report func(req *http.Request, err error)
+
+	// Metrics describes where undeclared responses are counted. When nil,
+	// they are not counted.
+	Metrics Metrics
+}
+
+// Middleware returns a handler which wraps the response writer passed to
+// next. It must run inside the router middleware, requests carrying no
+// route are passed through unchanged.
+func (r ResponseEnforcer) Middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		if route, ok := RouteFromContext(req.Context()); ok {
+			w = r.Wrap(w, req, route)
+		}
+		next.ServeHTTP(w, req)
+	})
+}
+
+// Wrap returns a response writer which checks the status code and the
+// Content-Type header against the responses of the route's operation when
+// the header is written.
+func (r ResponseEnforcer) Wrap(w http.ResponseWriter, req *http.Request, route *Route) http.ResponseWriter {
+	return &enforcingWriter{ResponseWriter: w, enforcer: r, req: req, route: route}
+}
+
+// enforcingWriter implements the response writer returned by Wrap.
+type enforcingWriter struct {
+	http.ResponseWriter
+	enforcer    ResponseEnforcer
+	req         *http.Request
+	route       *Route
+	wroteHeader bool
+	rejected    bool
+}
+
+// WriteHeader checks the response before sending the header.
+func (w *enforcingWriter) WriteHeader(status int) {
+	if w.wroteHeader {
+		return
+	}
+	w.wroteHeader = true
+
+	err := w.check(status)
+	if err == nil {
+		w.ResponseWriter.WriteHeader(status)
+		return
+	}
+
+	if w.enforcer.Metrics != nil {
+		w.enforcer.Metrics.IncCounter(MetricUndeclaredResponses, map[string]string{
+			"operation": operationLabel(w.route),
+			"status":    strconv.Itoa(status),
+		})
+	}
+	if w.enforcer.Report != nil {
+		w.enforcer.Report(w.req, err)
+	} else {
+		log.Printf("oas: %v", err)
+	}
+	if w.enforcer.Policy != RejectUndeclaredResponses {
+		w.ResponseWriter.WriteHeader(status)
+		return
+	}
+	w.rejected = true
+	w.Header().Del("Content-Length")
+	http.Error(w.ResponseWriter, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+}
+
+// Write writes the body, implicitly sending a 200 header first. The body of
+// rejected responses is discarded.
+func (w *enforcingWriter) Write(b []byte) (int, error) {
+	if !w.wroteHeader {
+		w.WriteHeader(http.StatusOK)
+	}
+	if w.rejected {
+		return len(b), nil
+	}
+	return w.ResponseWriter.Write(b)
+}
+
+// Flush implements http.Flusher if the wrapped writer does.
+func (w *enforcingWriter) Flush() {
+	if flusher, ok := w.ResponseWriter.(http.Flusher); ok {
+		flusher.Flush()
+	}
+}
+
+// check returns an error if the operation does not declare the status code
+// or the content type of the response.
+func (w *enforcingWriter) check(status int) error {
+	response := declaredResponse(w.route.Operation.Responses, status)
+	if response != nil && w.enforcer.Doc != nil {
+		_, response = NewResolver(w.enforcer.Doc).response("", response)
+	}
+	if response == nil {
+		return &UndeclaredResponseError{Path: w.route.Path, Method: w.route.Method, Status: status}
+	}
+
+	contentType := w.Header().Get("Content-Type")
+	if contentType == "" {
+		return nil
+	}
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		mediaType = contentType
+	}
+	if !declaresMediaType(response.Content, mediaType) {
+		return &UndeclaredResponseError{Path: w.route.Path, Method: w.route.Method, Status: status, ContentType: mediaType}
+	}
+	return nil
+}
+
+// declaredResponse returns the response declared for the status code,
+// falling back to the status code range (e.g. "4XX") and to the default
+// response.
+func declaredResponse(responses Responses, status int) *Response {
+	code := strconv.Itoa(status)
+	for _, key := range []string{code, code[:1] + "XX", "default"} {
+		if response := responses.Get(key); response != nil {
+			return response
+		}
+	}
+	return nil
+}
+
+// declaresMediaType reports whether the content declares the media type
+// either exactly or through a media type range (e.g. "image/*", "*/*").
+func declaresMediaType(content map[string]*MediaType, mediaType string) bool {
+	_, ok := declaredMediaType(content, mediaType)
+	return ok
+}
+
+// declaredMediaType returns the media type object the content declares for
+// the media type and reports whether there is one. Exact matches take
+// precedence over ranges such as "image/*", which take precedence over
+// "*/*".
+func declaredMediaType(content map[string]*MediaType, mediaType string) (*MediaType, bool) {
+	mediaType = strings.ToLower(mediaType)
+	matches := map[string]*MediaType{}
+	for key, value := range content {
+		key = strings.ToLower(key)
+		if parsed, _, err := mime.ParseMediaType(key); err == nil {
+			key = parsed
+		}
+		switch {
+		case key == mediaType:
+			matches["exact"] = value
+		case key == "*/*":
+			matches["any"] = value
+		case strings.HasSuffix(key, "/*") && strings.HasPrefix(mediaType, strings.TrimSuffix(key, "*")):
+			matches["range"] = value
+		}
+	}
+	for _, kind := range []string{"exact", "range", "any"} {
+		if value, ok := matches[kind]; ok {
+			return value, true
+		}
+	}
+	return nil, false
+}
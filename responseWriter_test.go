@@ -0,0 +1,117 @@
+package oas
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+)
+
+type ResponseWriterSuite struct {
+	suite.Suite
+}
+
+func (r *ResponseWriterSuite) responseDocument() *OpenAPI {
+	return &OpenAPI{
+		OpenAPI: "3.0.0",
+		Info:    Info{Title: "Petstore", Version: "1.0.0"},
+		Paths: Paths{
+			PathItems: NewPathItems(map[string]*PathItem{
+				"/pets/{petId}": {
+					Get: &Operation{
+						Responses: NewResponses(map[string]*Response{
+							"200": {
+								Description: "A pet.",
+								Content: map[string]*MediaType{
+									"application/json": {},
+									"image/*":          {},
+								},
+							},
+							"4XX": {Ref: "#/components/responses/Error"},
+						}),
+					},
+				},
+			}),
+		},
+		Components: &Components{
+			Responses: map[string]*Response{
+				"Error": {
+					Description: "An error.",
+					Content: map[string]*MediaType{
+						"application/problem+json": {},
+					},
+				},
+			},
+		},
+	}
+}
+
+func (r *ResponseWriterSuite) serve(policy ResponsePolicy, handler http.HandlerFunc) (*httptest.ResponseRecorder, []error) {
+	doc := r.responseDocument()
+	errs := make([]error, 0)
+	enforcer := ResponseEnforcer{
+		Doc:    doc,
+		Policy: policy,
+		Report: func(req *http.Request, err error) { errs = append(errs, err) },
+	}
+	router := &Router{Doc: doc}
+	recorder := httptest.NewRecorder()
+	router.Middleware(enforcer.Middleware(handler)).
+		ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/pets/7", nil))
+	return recorder, errs
+}
+
+func (r *ResponseWriterSuite) TestDeclared() {
+	testCases := []struct {
+		status      int
+		contentType string
+	}{
+		{http.StatusOK, "application/json; charset=utf-8"},
+		{http.StatusOK, "image/png"},
+		{http.StatusOK, ""},
+		{http.StatusNotFound, "application/problem+json"},
+	}
+
+	for i, testCase := range testCases {
+		recorder, errs := r.serve(RejectUndeclaredResponses, func(w http.ResponseWriter, req *http.Request) {
+			if testCase.contentType != "" {
+				w.Header().Set("Content-Type", testCase.contentType)
+			}
+			w.WriteHeader(testCase.status)
+			w.Write([]byte("body"))
+		})
+		assert.Empty(r.T(), errs, "test case %d", i)
+		assert.Equal(r.T(), testCase.status, recorder.Code, "test case %d", i)
+		assert.Equal(r.T(), "body", recorder.Body.String(), "test case %d", i)
+	}
+}
+
+func (r *ResponseWriterSuite) TestUndeclared() {
+	handler := func(w http.ResponseWriter, req *http.Request) {
+		w.Header().Set("Content-Type", "application/xml")
+		w.Write([]byte("<pet/>"))
+	}
+
+	recorder, errs := r.serve(WarnUndeclaredResponses, handler)
+	assert.Equal(r.T(), http.StatusOK, recorder.Code)
+	assert.Equal(r.T(), "<pet/>", recorder.Body.String())
+	if assert.Len(r.T(), errs, 1) {
+		assert.EqualError(r.T(), errs[0], `GET /pets/{petId}: undeclared content type "application/xml" for status 200`)
+	}
+
+	recorder, errs = r.serve(RejectUndeclaredResponses, func(w http.ResponseWriter, req *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+		w.Write([]byte("boom"))
+	})
+	assert.Equal(r.T(), http.StatusInternalServerError, recorder.Code)
+	assert.Equal(r.T(), "Internal Server Error\n", recorder.Body.String())
+	if assert.Len(r.T(), errs, 1) {
+		assert.EqualError(r.T(), errs[0], "GET /pets/{petId}: undeclared status 500")
+	}
+}
+
+func TestResponseWriterSuite(t *testing.T) {
+	suite.Run(t, new(ResponseWriterSuite))
+}
@@ -0,0 +1,123 @@
+package oas
+
+import (
+	"encoding/json"
+	"sort"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Responses represents the responses of an operation by their HTTP status
+// code or "default", in the order the responses were declared. Responses
+// decoded from a document keep the order they were written in, so the
+// document is encoded back in the author's order.
+type Responses struct {
+	values *orderedValues
+}
+
+// NewResponses returns the responses of the map in alphabetical order of
+// their codes.
+func NewResponses(responses map[string]*Response) Responses {
+	keys := make([]string, 0, len(responses))
+	for key := range responses {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	r := Responses{}
+	for _, key := range keys {
+		r.Set(key, responses[key])
+	}
+	return r
+}
+
+// Len returns the number of responses.
+func (r Responses) Len() int {
+	return r.values.len()
+}
+
+// Keys returns the codes in order.
+func (r Responses) Keys() []string {
+	return r.values.keys()
+}
+
+// Get returns the response of the code, or nil if there is none.
+func (r Responses) Get(code string) *Response {
+	response, _ := r.Lookup(code)
+	return response
+}
+
+// Lookup returns the response of the code and reports whether the code is
+// declared.
+func (r Responses) Lookup(code string) (*Response, bool) {
+	value, ok := r.values.get(code)
+	response, _ := value.(*Response)
+	return response, ok
+}
+
+// Set sets the response of the code. Codes not declared yet are appended.
+func (r *Responses) Set(code string, response *Response) {
+	if r.values == nil {
+		r.values = newOrderedValues()
+	}
+	r.values.set(code, response)
+}
+
+// Delete removes the response of the code.
+func (r *Responses) Delete(code string) {
+	r.values.remove(code)
+}
+
+// orderedValues returns the values backing the responses.
+func (r *Responses) orderedValues() *orderedValues {
+	return r.values
+}
+
+// setOrderedValues replaces the values backing the responses.
+func (r *Responses) setOrderedValues(values *orderedValues) {
+	r.values = values
+}
+
+// MarshalJSON returns the JSON encoding.
+func (r Responses) MarshalJSON() ([]byte, error) {
+	obj, err := r.MarshalYAML()
+	if err != nil {
+		return nil, err
+	}
+	return json.Marshal(obj)
+}
+
+// UnmarshalJSON parses the JSON-encoded data and stores the result.
+func (r *Responses) UnmarshalJSON(data []byte) error {
+	return decodeJSON(data, r)
+}
+
+// MarshalYAML returns the YAML encoding.
+func (r Responses) MarshalYAML() (interface{}, error) {
+	obj := make(orderedMap, 0, r.Len())
+	for _, k := range r.Keys() {
+		obj = append(obj, orderedEntry{key: k, value: r.Get(k)})
+	}
+	return obj, nil
+}
+
+// UnmarshalYAML parses the YAML-encoded data and stores the result.
+func (r *Responses) UnmarshalYAML(node *yaml.Node) error {
+	return decodeYAML(node, r)
+}
+
+// unmarshalValue stores the result of the unmarshal function, which decodes
+// either JSON or YAML data.
+func (r *Responses) unmarshalValue(d *valueDecoder, unmarshal func(interface{}) error) error {
+	obj, keys, err := d.members(unmarshal)
+	if err != nil {
+		return err
+	}
+	for _, k := range keys {
+		var value *Response
+		if err := d.decodeMember(k, obj[k], &value); err != nil {
+			return err
+		}
+		r.Set(k, value)
+	}
+	return nil
+}
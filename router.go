@@ -0,0 +1,299 @@
+package oas
+
+import (
+	"bytes"
+	"encoding/json"
+	"io/ioutil"
+	"mime"
+	"net/http"
+	"net/url"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/pkg/errors"
+)
+
+// ErrRouteNotFound is returned by Match when no path of the document matches
+// the request path.
+var ErrRouteNotFound = errors.New("route not found")
+
+// ErrMethodNotAllowed is returned by Match when a path matches the request
+// path but declares no operation for the request method.
+var ErrMethodNotAllowed = errors.New("method not allowed")
+
+// DefaultMaxBodyBytes is the size in bytes of the largest JSON request body
+// Router.Middleware decodes when the router does not specify a limit.
+const DefaultMaxBodyBytes = 1 << 20
+
+// templateParam matches a parameter of a path template.
+var templateParam = regexp.MustCompile(`\{([^{}/]+)\}`)
+
+// Route describes the operation a request was matched to.
+type Route struct {
+	// Path describes the matched path template (e.g. "/pets/{petId}").
+	Path string
+
+	// Method describes the lowercase HTTP method of the matched operation.
+	Method string
+
+	// PathItem describes the path item the path template is declared with.
+	PathItem *PathItem
+
+	// Operation describes the matched operation.
+	Operation *Operation
+
+	// PathParams describes the unescaped values of the path parameters.
+	PathParams map[string]string
+
+	// Body describes the request body decoded into generic maps, slices and
+	// scalars. It is only set for JSON request bodies.
+	Body interface{}
+}
+
+// Router matches requests against the path templates of a document. Paths
+// without parameters take precedence over templated paths and templates
+// with fewer parameters take precedence over those with more.
+type Router struct {
+	// Doc describes the document requests are routed against. It must not be
+	// modified once the router is in use.
+	Doc *OpenAPI
+
+	// AutoHead routes HEAD requests to the GET operation of paths which
+	// declare no HEAD operation.
+	AutoHead bool
+
+	// AutoOptions answers OPTIONS requests to paths which declare no OPTIONS
+	// operation with 204 and an Allow header listing the methods of the path.
+	AutoOptions bool
+
+	// Metrics describes where request and routing failure metrics are
+	// reported. When nil, no metrics are reported.
+	Metrics Metrics
+
+	// MaxBodyBytes describes the size in bytes of the largest JSON request
+	// body Middleware decodes. Larger bodies are answered with 413. Defaults
+	// to DefaultMaxBodyBytes, a negative value disables the limit.
+	MaxBodyBytes int64
+
+	once   sync.Once
+	err    error
+	routes []*compiledRoute
+}
+
+// compiledRoute holds a path template compiled into a regular expression.
+type compiledRoute struct {
+	path    string
+	item    *PathItem
+	params  []string
+	pattern *regexp.Regexp
+}
+
+// compile compiles the path templates of the document.
+func (r *Router) compile() error {
+	r.once.Do(func() {
+		for _, path := range sortedKeys(r.Doc.Paths.PathItems) {
+			item := r.Doc.Paths.PathItems.Get(path)
+			if item == nil {
+				continue
+			}
+			pattern, params, err := compileTemplate(path)
+			if err != nil {
+				r.err = err
+				return
+			}
+			r.routes = append(r.routes, &compiledRoute{
+				path:    path,
+				item:    item,
+				params:  params,
+				pattern: pattern,
+			})
+		}
+		sort.SliceStable(r.routes, func(i, j int) bool {
+			if len(r.routes[i].params) != len(r.routes[j].params) {
+				return len(r.routes[i].params) < len(r.routes[j].params)
+			}
+			return len(r.routes[i].path) > len(r.routes[j].path)
+		})
+	})
+	return r.err
+}
+
+// compileTemplate compiles a path template into a regular expression
+// matching escaped request paths and returns the names of its parameters in
+// the order of the capturing groups.
+func compileTemplate(path string) (*regexp.Regexp, []string, error) {
+	params := make([]string, 0)
+	expr := "^"
+	last := 0
+	for _, match := range templateParam.FindAllStringSubmatchIndex(path, -1) {
+		expr += regexp.QuoteMeta(path[last:match[0]]) + "([^/]+)"
+		params = append(params, path[match[2]:match[3]])
+		last = match[1]
+	}
+	expr += regexp.QuoteMeta(path[last:]) + "$"
+	pattern, err := regexp.Compile(expr)
+	if err != nil {
+		return nil, nil, errors.Wrapf(err, "path %q", path)
+	}
+	return pattern, params, nil
+}
+
+// Match returns the route of the request. ErrRouteNotFound is returned when
+// no path matches and ErrMethodNotAllowed when the matching path declares no
+// operation for the request method. The body of the route is not decoded.
+func (r *Router) Match(req *http.Request) (*Route, error) {
+	if err := r.compile(); err != nil {
+		return nil, err
+	}
+
+	path := req.URL.EscapedPath()
+	found := false
+	for _, route := range r.routes {
+		matches := route.pattern.FindStringSubmatch(path)
+		if matches == nil {
+			continue
+		}
+		found = true
+
+		var operation *Operation
+		for _, op := range route.item.operations() {
+			if strings.EqualFold(op.method, req.Method) {
+				operation = op.operation
+			}
+		}
+		if operation == nil && r.AutoHead && req.Method == http.MethodHead {
+			operation = route.item.Get
+		}
+		if operation == nil {
+			continue
+		}
+
+		params := make(map[string]string, len(route.params))
+		for i, name := range route.params {
+			value, err := url.PathUnescape(matches[i+1])
+			if err != nil {
+				return nil, errors.WithStack(err)
+			}
+			params[name] = value
+		}
+		return &Route{
+			Path:       route.path,
+			Method:     strings.ToLower(req.Method),
+			PathItem:   route.item,
+			Operation:  operation,
+			PathParams: params,
+		}, nil
+	}
+
+	if found {
+		return nil, ErrMethodNotAllowed
+	}
+	return nil, ErrRouteNotFound
+}
+
+// allowedMethods returns the uppercase methods declared by the paths
+// matching the request path, including the methods the router synthesizes.
+func (r *Router) allowedMethods(req *http.Request) []string {
+	methods := make([]string, 0)
+	seen := map[string]bool{}
+	allow := func(method string) {
+		if !seen[method] {
+			seen[method] = true
+			methods = append(methods, method)
+		}
+	}
+	for _, route := range r.routes {
+		if !route.pattern.MatchString(req.URL.EscapedPath()) {
+			continue
+		}
+		for _, op := range route.item.operations() {
+			allow(strings.ToUpper(op.method))
+		}
+		if r.AutoHead && route.item.Get != nil {
+			allow(http.MethodHead)
+		}
+		if r.AutoOptions {
+			allow(http.MethodOptions)
+		}
+	}
+	return methods
+}
+
+// Middleware returns a handler which matches requests against the document
+// and stores the route in the request context before calling next. Requests
+// which match no path are answered with 404 and requests using a method the
+// path does not declare with 405 and an Allow header, unless the router
+// synthesizes the method. JSON request bodies are decoded into the route and
+// restored for the handler, malformed JSON is answered with 400 and bodies
+// larger than MaxBodyBytes with 413.
+func (r *Router) Middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		route, err := r.Match(req)
+		switch errors.Cause(err) {
+		case nil:
+		case ErrMethodNotAllowed:
+			w.Header().Set("Allow", strings.Join(r.allowedMethods(req), ", "))
+			if r.AutoOptions && req.Method == http.MethodOptions {
+				w.WriteHeader(http.StatusNoContent)
+				return
+			}
+			r.routingFailure("method_not_allowed")
+			http.Error(w, http.StatusText(http.StatusMethodNotAllowed), http.StatusMethodNotAllowed)
+			return
+		case ErrRouteNotFound:
+			r.routingFailure("not_found")
+			http.Error(w, http.StatusText(http.StatusNotFound), http.StatusNotFound)
+			return
+		default:
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+
+		mediaType, _, _ := mime.ParseMediaType(req.Header.Get("Content-Type"))
+		if route.Operation.RequestBody != nil && req.Body != nil && isJSONMediaType(mediaType) {
+			limit := r.MaxBodyBytes
+			if limit == 0 {
+				limit = DefaultMaxBodyBytes
+			}
+			body := req.Body
+			if limit > 0 {
+				body = http.MaxBytesReader(w, req.Body, limit)
+			}
+			rbytes, err := ioutil.ReadAll(body)
+			if err != nil && limit > 0 && int64(len(rbytes)) >= limit {
+				r.routingFailure("payload_too_large")
+				http.Error(w, http.StatusText(http.StatusRequestEntityTooLarge), http.StatusRequestEntityTooLarge)
+				return
+			}
+			if err != nil {
+				r.routingFailure("bad_request")
+				http.Error(w, err.Error(), http.StatusBadRequest)
+				return
+			}
+			req.Body = ioutil.NopCloser(bytes.NewReader(rbytes))
+			if len(rbytes) > 0 {
+				if err := json.Unmarshal(rbytes, &route.Body); err != nil {
+					r.routingFailure("bad_request")
+					http.Error(w, err.Error(), http.StatusBadRequest)
+					return
+				}
+			}
+		}
+
+		req = req.WithContext(NewRouteContext(req.Context(), route))
+		if r.Metrics == nil {
+			next.ServeHTTP(w, req)
+			return
+		}
+		instrument(r.Metrics, route, w, req, next)
+	})
+}
+
+// routingFailure reports a rejected request to the metrics.
+func (r *Router) routingFailure(reason string) {
+	if r.Metrics != nil {
+		r.Metrics.IncCounter(MetricRoutingFailures, map[string]string{"reason": reason})
+	}
+}
@@ -0,0 +1,171 @@
+package oas
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+)
+
+type RouterSuite struct {
+	suite.Suite
+}
+
+func (r *RouterSuite) routerDocument() *OpenAPI {
+	return &OpenAPI{
+		OpenAPI: "3.0.0",
+		Info:    Info{Title: "Petstore", Version: "1.0.0"},
+		Paths: Paths{
+			PathItems: NewPathItems(map[string]*PathItem{
+				"/pets": {
+					Get:  &Operation{OperationID: "listPets"},
+					Post: &Operation{OperationID: "createPet", RequestBody: &RequestBody{}},
+				},
+				"/pets/{petId}": {
+					Get:    &Operation{OperationID: "getPet"},
+					Delete: &Operation{OperationID: "deletePet"},
+				},
+				"/pets/mine": {
+					Get: &Operation{OperationID: "listMyPets"},
+				},
+				"/pets/{petId}/photos/{photoId}.{format}": {
+					Get: &Operation{OperationID: "getPhoto"},
+				},
+			}),
+		},
+	}
+}
+
+func (r *RouterSuite) TestMatch() {
+	router := &Router{Doc: r.routerDocument()}
+	testCases := []struct {
+		method     string
+		target     string
+		operation  string
+		pathParams map[string]string
+		err        error
+	}{
+		{http.MethodGet, "/pets", "listPets", map[string]string{}, nil},
+		{http.MethodGet, "/pets/mine", "listMyPets", map[string]string{}, nil},
+		{http.MethodGet, "/pets/a%2Fb", "getPet", map[string]string{"petId": "a/b"}, nil},
+		{http.MethodGet, "/pets/7/photos/1.png", "getPhoto", map[string]string{"petId": "7", "photoId": "1", "format": "png"}, nil},
+		{http.MethodDelete, "/pets/mine", "deletePet", map[string]string{"petId": "mine"}, nil},
+		{http.MethodPut, "/pets/7", "", nil, ErrMethodNotAllowed},
+		{http.MethodGet, "/owners", "", nil, ErrRouteNotFound},
+	}
+
+	for i, testCase := range testCases {
+		route, err := router.Match(httptest.NewRequest(testCase.method, testCase.target, nil))
+		if testCase.err != nil {
+			assert.Equal(r.T(), testCase.err, errors.Cause(err), "test case %d", i)
+			continue
+		}
+		if assert.NoError(r.T(), err, "test case %d", i) {
+			assert.Equal(r.T(), testCase.operation, route.Operation.OperationID, "test case %d", i)
+			assert.Equal(r.T(), testCase.pathParams, route.PathParams, "test case %d", i)
+		}
+	}
+}
+
+func (r *RouterSuite) TestMiddleware() {
+	router := &Router{Doc: r.routerDocument()}
+	handler := router.Middleware(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		route, ok := RouteFromContext(req.Context())
+		assert.True(r.T(), ok)
+		assert.Equal(r.T(), route.Operation, OperationFromContext(req.Context()))
+		w.Write([]byte(OperationFromContext(req.Context()).OperationID + " " + PathParam(req, "petId")))
+		if body, ok := BodyFromContext(req.Context()).(map[string]interface{}); ok {
+			w.Write([]byte(" " + body["name"].(string)))
+		}
+	}))
+
+	recorder := httptest.NewRecorder()
+	handler.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/pets/7", nil))
+	assert.Equal(r.T(), http.StatusOK, recorder.Code)
+	assert.Equal(r.T(), "getPet 7", recorder.Body.String())
+
+	recorder = httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodPost, "/pets", strings.NewReader(`{"name":"Rex"}`))
+	req.Header.Set("Content-Type", "application/json")
+	handler.ServeHTTP(recorder, req)
+	assert.Equal(r.T(), "createPet  Rex", recorder.Body.String())
+
+	recorder = httptest.NewRecorder()
+	req = httptest.NewRequest(http.MethodPost, "/pets", strings.NewReader(`{"name"`))
+	req.Header.Set("Content-Type", "application/json")
+	handler.ServeHTTP(recorder, req)
+	assert.Equal(r.T(), http.StatusBadRequest, recorder.Code)
+
+	recorder = httptest.NewRecorder()
+	handler.ServeHTTP(recorder, httptest.NewRequest(http.MethodPut, "/pets/mine", nil))
+	assert.Equal(r.T(), http.StatusMethodNotAllowed, recorder.Code)
+	assert.Equal(r.T(), "GET, DELETE", recorder.Header().Get("Allow"))
+
+	recorder = httptest.NewRecorder()
+	handler.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/owners", nil))
+	assert.Equal(r.T(), http.StatusNotFound, recorder.Code)
+}
+
+func (r *RouterSuite) TestMaxBodyBytes() {
+	testCases := []struct {
+		limit int64
+		body  string
+		code  int
+	}{
+		{0, `{"name":"Rex"}`, http.StatusOK},
+		{0, `{"name":"` + strings.Repeat("x", DefaultMaxBodyBytes) + `"}`, http.StatusRequestEntityTooLarge},
+		{14, `{"name":"Rex"}`, http.StatusOK},
+		{13, `{"name":"Rex"}`, http.StatusRequestEntityTooLarge},
+		{-1, `{"name":"` + strings.Repeat("x", DefaultMaxBodyBytes) + `"}`, http.StatusOK},
+	}
+
+	for i, testCase := range testCases {
+		failMsg := "test case %d failed"
+		router := &Router{Doc: r.routerDocument(), MaxBodyBytes: testCase.limit}
+		handler := router.Middleware(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {}))
+		req := httptest.NewRequest(http.MethodPost, "/pets", strings.NewReader(testCase.body))
+		req.Header.Set("Content-Type", "application/json")
+		recorder := httptest.NewRecorder()
+		handler.ServeHTTP(recorder, req)
+		assert.Equal(r.T(), testCase.code, recorder.Code, failMsg, i)
+	}
+}
+
+func (r *RouterSuite) TestSynthesizedMethods() {
+	router := &Router{Doc: r.routerDocument(), AutoHead: true, AutoOptions: true}
+	handler := router.Middleware(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		w.Write([]byte(OperationFromContext(req.Context()).OperationID))
+	}))
+
+	recorder := httptest.NewRecorder()
+	handler.ServeHTTP(recorder, httptest.NewRequest(http.MethodHead, "/pets/mine", nil))
+	assert.Equal(r.T(), http.StatusOK, recorder.Code)
+	assert.Equal(r.T(), "listMyPets", recorder.Body.String())
+
+	recorder = httptest.NewRecorder()
+	handler.ServeHTTP(recorder, httptest.NewRequest(http.MethodOptions, "/pets", nil))
+	assert.Equal(r.T(), http.StatusNoContent, recorder.Code)
+	assert.Equal(r.T(), "GET, POST, HEAD, OPTIONS", recorder.Header().Get("Allow"))
+
+	recorder = httptest.NewRecorder()
+	handler.ServeHTTP(recorder, httptest.NewRequest(http.MethodPut, "/pets", nil))
+	assert.Equal(r.T(), http.StatusMethodNotAllowed, recorder.Code)
+	assert.Equal(r.T(), "GET, POST, HEAD, OPTIONS", recorder.Header().Get("Allow"))
+}
+
+func (r *RouterSuite) TestContext() {
+	req := httptest.NewRequest(http.MethodGet, "/pets/7", nil)
+	_, ok := RouteFromContext(req.Context())
+	assert.False(r.T(), ok)
+	assert.Nil(r.T(), OperationFromContext(req.Context()))
+	assert.Nil(r.T(), BodyFromContext(req.Context()))
+	assert.Equal(r.T(), "", PathParam(req, "petId"))
+}
+
+func TestRouterSuite(t *testing.T) {
+	suite.Run(t, new(RouterSuite))
+}
@@ -0,0 +1,82 @@
+package oas
+
+import (
+	"encoding/json"
+	"io"
+
+	"github.com/pkg/errors"
+)
+
+// sarifLevels maps the severities of findings to the levels of SARIF
+// results.
+var sarifLevels = map[Severity]string{
+	SeverityError:   "error",
+	SeverityWarning: "warning",
+	SeverityInfo:    "note",
+}
+
+// WriteSARIF writes the findings as a SARIF 2.1.0 log, which GitHub code
+// scanning and other CI services display inline on pull requests. Findings
+// are reported against the file of their location, or against the location
+// of the document when they have none (e.g. findings of a document which was
+// not loaded with LoadLocated), and carry their pointer as the logical
+// location.
+func WriteSARIF(w io.Writer, location string, findings []*Finding) error {
+	rules := map[string]bool{}
+	results := make([]interface{}, 0, len(findings))
+	for _, finding := range findings {
+		rules[finding.Rule] = true
+		physical := map[string]interface{}{
+			"artifactLocation": map[string]interface{}{"uri": location},
+		}
+		if finding.Location != nil {
+			if finding.Location.Location != "" {
+				physical["artifactLocation"] = map[string]interface{}{"uri": finding.Location.Location}
+			}
+			if finding.Location.Line > 0 {
+				region := map[string]interface{}{"startLine": finding.Location.Line}
+				if finding.Location.Column > 0 {
+					region["startColumn"] = finding.Location.Column
+				}
+				physical["region"] = region
+			}
+		}
+		level := sarifLevels[finding.Severity]
+		if level == "" {
+			level = "warning"
+		}
+		results = append(results, map[string]interface{}{
+			"ruleId":  finding.Rule,
+			"level":   level,
+			"message": map[string]interface{}{"text": finding.Message},
+			"locations": []interface{}{map[string]interface{}{
+				"physicalLocation": physical,
+				"logicalLocations": []interface{}{map[string]interface{}{
+					"fullyQualifiedName": finding.Pointer,
+				}},
+			}},
+		})
+	}
+
+	descriptors := make([]interface{}, 0, len(rules))
+	for _, rule := range sortedStrings(rules) {
+		descriptors = append(descriptors, map[string]interface{}{"id": rule})
+	}
+	log := map[string]interface{}{
+		"$schema": "https://json.schemastore.org/sarif-2.1.0.json",
+		"version": "2.1.0",
+		"runs": []interface{}{map[string]interface{}{
+			"tool": map[string]interface{}{
+				"driver": map[string]interface{}{
+					"name":           "oas",
+					"informationUri": "https://github.com/trivigy/oas",
+					"rules":          descriptors,
+				},
+			},
+			"results": results,
+		}},
+	}
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return errors.WithStack(encoder.Encode(log))
+}
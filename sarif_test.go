@@ -0,0 +1,125 @@
+package oas
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+)
+
+type SARIFSuite struct {
+	suite.Suite
+}
+
+func (r *SARIFSuite) TestWriteSARIF() {
+	findings := []*Finding{
+		{
+			Pointer:  "/paths/~1pets/get",
+			Rule:     RuleSecurityMissing,
+			Severity: SeverityWarning,
+			Message:  "operation declares no security",
+			Location: &Source{Location: "api/openapi.yaml", Line: 7, Column: 5},
+		},
+		{
+			Pointer:  "/info/title",
+			Rule:     RuleFieldRequired,
+			Severity: SeverityError,
+			Message:  "title is required",
+		},
+		{
+			Pointer:  "/paths/~1pets/get",
+			Rule:     RuleDescriptionMissing,
+			Severity: SeverityInfo,
+			Message:  "operation declares neither a summary nor a description",
+			Location: &Source{Location: "api/openapi.yaml"},
+		},
+	}
+
+	buf := &bytes.Buffer{}
+	if !assert.Nil(r.T(), WriteSARIF(buf, "openapi.yaml", findings)) {
+		return
+	}
+	log := struct {
+		Version string `json:"version"`
+		Runs    []struct {
+			Tool struct {
+				Driver struct {
+					Name  string `json:"name"`
+					Rules []struct {
+						ID string `json:"id"`
+					} `json:"rules"`
+				} `json:"driver"`
+			} `json:"tool"`
+			Results []struct {
+				RuleID    string                `json:"ruleId"`
+				Level     string                `json:"level"`
+				Message   struct{ Text string } `json:"message"`
+				Locations []struct {
+					PhysicalLocation struct {
+						ArtifactLocation struct{ URI string } `json:"artifactLocation"`
+						Region           *struct {
+							StartLine   int `json:"startLine"`
+							StartColumn int `json:"startColumn"`
+						} `json:"region"`
+					} `json:"physicalLocation"`
+					LogicalLocations []struct {
+						FullyQualifiedName string `json:"fullyQualifiedName"`
+					} `json:"logicalLocations"`
+				} `json:"locations"`
+			} `json:"results"`
+		} `json:"runs"`
+	}{}
+	if !assert.Nil(r.T(), json.Unmarshal(buf.Bytes(), &log)) {
+		return
+	}
+
+	assert.Equal(r.T(), "2.1.0", log.Version)
+	run := log.Runs[0]
+	assert.Equal(r.T(), "oas", run.Tool.Driver.Name)
+	rules := make([]string, 0)
+	for _, rule := range run.Tool.Driver.Rules {
+		rules = append(rules, rule.ID)
+	}
+	assert.Equal(r.T(), []string{RuleDescriptionMissing, RuleFieldRequired, RuleSecurityMissing}, rules)
+
+	testCases := []struct {
+		rule    string
+		level   string
+		uri     string
+		line    int
+		column  int
+		pointer string
+	}{
+		{RuleSecurityMissing, "warning", "api/openapi.yaml", 7, 5, "/paths/~1pets/get"},
+		{RuleFieldRequired, "error", "openapi.yaml", 0, 0, "/info/title"},
+		{RuleDescriptionMissing, "note", "api/openapi.yaml", 0, 0, "/paths/~1pets/get"},
+	}
+	for i, testCase := range testCases {
+		failMsg := "test case %d failed"
+		result := run.Results[i]
+		assert.Equal(r.T(), testCase.rule, result.RuleID, failMsg, i)
+		assert.Equal(r.T(), testCase.level, result.Level, failMsg, i)
+		assert.Equal(r.T(), findings[i].Message, result.Message.Text, failMsg, i)
+		location := result.Locations[0]
+		assert.Equal(r.T(), testCase.uri, location.PhysicalLocation.ArtifactLocation.URI, failMsg, i)
+		if testCase.line > 0 && assert.NotNil(r.T(), location.PhysicalLocation.Region, failMsg, i) {
+			assert.Equal(r.T(), testCase.line, location.PhysicalLocation.Region.StartLine, failMsg, i)
+			assert.Equal(r.T(), testCase.column, location.PhysicalLocation.Region.StartColumn, failMsg, i)
+		} else {
+			assert.Nil(r.T(), location.PhysicalLocation.Region, failMsg, i)
+		}
+		assert.Equal(r.T(), testCase.pointer, location.LogicalLocations[0].FullyQualifiedName, failMsg, i)
+	}
+}
+
+func (r *SARIFSuite) TestWriteSARIFEmpty() {
+	buf := &bytes.Buffer{}
+	assert.Nil(r.T(), WriteSARIF(buf, "openapi.yaml", nil))
+	assert.Contains(r.T(), buf.String(), `"results": []`)
+}
+
+func TestSARIFSuite(t *testing.T) {
+	suite.Run(t, new(SARIFSuite))
+}
@@ -0,0 +1,140 @@
+package oas
+
+import (
+	"github.com/pkg/errors"
+)
+
+// Security schemes declared by NewScaffold.
+const (
+	// ScaffoldBearer declares an HTTP bearer authentication scheme carrying
+	// a JWT.
+	ScaffoldBearer = "bearer"
+
+	// ScaffoldAPIKey declares an API key passed in the X-API-Key header.
+	ScaffoldAPIKey = "apiKey"
+
+	// ScaffoldNoSecurity declares no security scheme.
+	ScaffoldNoSecurity = "none"
+)
+
+// ScaffoldOptions describes the starter document produced by NewScaffold.
+type ScaffoldOptions struct {
+	// Title describes the title of the API.
+	Title string
+
+	// Version describes the version of the API. Defaults to "0.1.0".
+	Version string
+
+	// Description describes the API.
+	Description string
+
+	// ServerURL describes the URL of the server. Defaults to
+	// "http://localhost:8080".
+	ServerURL string
+
+	// Security describes the security scheme protecting the API,
+	// ScaffoldBearer, ScaffoldAPIKey or ScaffoldNoSecurity. Defaults to
+	// ScaffoldBearer.
+	Security string
+
+	// HealthPath describes the path of the health endpoint. Defaults to
+	// "/health".
+	HealthPath string
+}
+
+// NewScaffold returns a well-formed starter document for a new API, as
+// produced by an init command: its info, a server, a health endpoint, an
+// "Error" schema returned by the "Error" default response, and a security
+// scheme required at the top level. The health endpoint opts out of the
+// security requirement with an empty one so probes need no credentials.
+func NewScaffold(opts ScaffoldOptions) (*OpenAPI, error) {
+	if opts.Title == "" {
+		return nil, errors.New("missing title")
+	}
+	if opts.Version == "" {
+		opts.Version = "0.1.0"
+	}
+	if opts.ServerURL == "" {
+		opts.ServerURL = "http://localhost:8080"
+	}
+	if opts.Security == "" {
+		opts.Security = ScaffoldBearer
+	}
+	if opts.HealthPath == "" {
+		opts.HealthPath = "/health"
+	}
+
+	doc := &OpenAPI{
+		OpenAPI: "3.0.0",
+		Info: Info{
+			Title:       opts.Title,
+			Description: opts.Description,
+			Version:     opts.Version,
+		},
+		Servers: []*Server{{URL: opts.ServerURL}},
+		Paths: Paths{
+			PathItems: NewPathItems(map[string]*PathItem{
+				opts.HealthPath: {
+					Get: &Operation{
+						OperationID: "getHealth",
+						Summary:     "Report the health of the service.",
+						Tags:        []string{"health"},
+						Security:    []*SecurityRequirement{},
+						Responses: NewResponses(map[string]*Response{
+							"200": {
+								Description: "The service is healthy.",
+								Content: map[string]*MediaType{"application/json": {
+									Schema: &Schema{
+										Type:       "object",
+										Required:   []string{"status"},
+										Properties: NewProperties(map[string]*Schema{"status": {Type: "string", Enum: []interface{}{"ok"}}}),
+									},
+								}},
+							},
+							"default": {Ref: "#/components/responses/Error"},
+						}),
+					},
+				},
+			}),
+		},
+		Components: &Components{
+			Schemas: map[string]*Schema{
+				"Error": {
+					Type:     "object",
+					Required: []string{"code", "message"},
+					Properties: NewProperties(map[string]*Schema{
+						"code":    {Type: "string", Description: "Machine readable error code."},
+						"message": {Type: "string", Description: "Human readable error message."},
+					}),
+				},
+			},
+			Responses: map[string]*Response{
+				"Error": {
+					Description: "Unexpected error.",
+					Content: map[string]*MediaType{"application/json": {
+						Schema: &Schema{Ref: "#/components/schemas/Error"},
+					}},
+				},
+			},
+		},
+		Tags: []*Tag{{Name: "health", Description: "Service health."}},
+	}
+
+	switch opts.Security {
+	case ScaffoldBearer:
+		doc.Components.SecuritySchemes = map[string]*SecurityScheme{
+			"bearerAuth": {Type: "http", Scheme: "bearer", BearerFormat: "JWT"},
+		}
+		doc.Security = []*SecurityRequirement{{"bearerAuth": []string{}}}
+	case ScaffoldAPIKey:
+		doc.Components.SecuritySchemes = map[string]*SecurityScheme{
+			"apiKey": {Type: "apiKey", Name: "X-API-Key", In: "header"},
+		}
+		doc.Security = []*SecurityRequirement{{"apiKey": []string{}}}
+	case ScaffoldNoSecurity:
+		doc.Paths.PathItems.Get(opts.HealthPath).Get.Security = nil
+	default:
+		return nil, errors.Errorf("unknown security scheme %q", opts.Security)
+	}
+	return doc, nil
+}
@@ -0,0 +1,70 @@
+package oas
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+	"gopkg.in/yaml.v3"
+)
+
+type ScaffoldSuite struct {
+	suite.Suite
+}
+
+func (r *ScaffoldSuite) TestNewScaffold() {
+	doc, err := NewScaffold(ScaffoldOptions{Title: "Petstore"})
+	assert.Nil(r.T(), err)
+	assert.Equal(r.T(), Info{Title: "Petstore", Version: "0.1.0"}, doc.Info)
+	assert.Equal(r.T(), []*Server{{URL: "http://localhost:8080"}}, doc.Servers)
+	assert.Equal(r.T(), []*SecurityRequirement{{"bearerAuth": []string{}}}, doc.Security)
+	assert.Equal(r.T(), "bearer", doc.Components.SecuritySchemes["bearerAuth"].Scheme)
+
+	health := doc.Paths.PathItems.Get("/health").Get
+	assert.Equal(r.T(), "getHealth", health.OperationID)
+	assert.Equal(r.T(), []*SecurityRequirement{}, health.Security)
+	assert.Equal(r.T(), "#/components/responses/Error", health.Responses.Get("default").Ref)
+	findings := doc.AuditSecurity()
+	assert.Len(r.T(), findings, 1)
+	assert.Equal(r.T(), RuleSecurityDisabled, findings[0].Rule)
+
+	rbytes, err := yaml.Marshal(doc)
+	assert.Nil(r.T(), err)
+	decoded := &OpenAPI{}
+	assert.Nil(r.T(), yaml.Unmarshal(rbytes, decoded))
+	assert.Equal(r.T(), []*SecurityRequirement{}, decoded.Paths.PathItems.Get("/health").Get.Security)
+}
+
+func (r *ScaffoldSuite) TestNewScaffoldOptions() {
+	testCases := []struct {
+		opts  ScaffoldOptions
+		isErr bool
+	}{
+		{ScaffoldOptions{Title: "Petstore", Security: ScaffoldAPIKey, HealthPath: "/healthz"}, false},
+		{ScaffoldOptions{Title: "Petstore", Security: ScaffoldNoSecurity}, false},
+		{ScaffoldOptions{Title: "Petstore", Security: "mtls"}, true},
+		{ScaffoldOptions{}, true},
+	}
+
+	for i, testCase := range testCases {
+		failMsg := "test case %d failed"
+		doc, err := NewScaffold(testCase.opts)
+		if testCase.isErr {
+			assert.NotNil(r.T(), err, failMsg, i)
+			continue
+		}
+		assert.Nil(r.T(), err, failMsg, i)
+		switch testCase.opts.Security {
+		case ScaffoldAPIKey:
+			assert.NotNil(r.T(), doc.Paths.PathItems.Get("/healthz"), failMsg, i)
+			assert.Equal(r.T(), "X-API-Key", doc.Components.SecuritySchemes["apiKey"].Name, failMsg, i)
+		case ScaffoldNoSecurity:
+			assert.Nil(r.T(), doc.Security, failMsg, i)
+			assert.Nil(r.T(), doc.Paths.PathItems.Get("/health").Get.Security, failMsg, i)
+		}
+	}
+}
+
+func TestScaffoldSuite(t *testing.T) {
+	suite.Run(t, new(ScaffoldSuite))
+}
@@ -5,7 +5,7 @@ import (
 	"fmt"
 
 	"github.com/pkg/errors"
-	"gopkg.in/yaml.v2"
+	"gopkg.in/yaml.v3"
 )
 
 // Schema allows the definition of input and output data types. These types can
@@ -130,13 +130,19 @@ type Schema struct {
 	// Property definitions MUST be a Schema Object and not a standard JSON
 	// Schema (inline or referenced).
 	// https://tools.ietf.org/html/draft-wright-json-schema-validation-00#section-5.16
-	Properties map[string]*Schema `json:"properties,omitempty" yaml:"properties,omitempty"`
+	Properties Properties `json:"properties,omitempty" yaml:"properties,omitempty"`
 
 	// AdditionalProperties value can be boolean or object. Inline or referenced
 	// schema MUST be of a Schema Object and not a standard JSON Schema.
 	// https://tools.ietf.org/html/draft-wright-json-schema-validation-00#section-5.18
 	AdditionalProperties *Schema `json:"additionalProperties,omitempty" yaml:"additionalProperties,omitempty"`
 
+	// AdditionalPropertiesAllowed describes the boolean form of
+	// additionalProperties: false rejects properties not declared by
+	// Properties and true accepts any. It is ignored when
+	// AdditionalProperties is set.
+	AdditionalPropertiesAllowed *bool `json:"-" yaml:"-"`
+
 	// Enum validates successfully if on of its values is equal to the instance
 	// elements. https://tools.ietf.org/html/draft-wright-json-schema-validation-00#section-5.20
 	Enum []interface{} `json:"enum,omitempty" yaml:"enum,omitempty"`
@@ -186,6 +192,16 @@ type Schema struct {
 	// the type of the instance to validate is not in this set, validation for
 	// this format attribute and instance SHOULD succeed.
 	Format string `json:"format,omitempty" yaml:"format,omitempty"`
+
+	// ContentMediaType describes the media type of the contents of a string
+	// instance (e.g. "image/png"). Introduced by OpenAPI 3.1 in place of the
+	// binary format.
+	ContentMediaType string `json:"contentMediaType,omitempty" yaml:"contentMediaType,omitempty"`
+
+	// ContentEncoding describes the encoding of the contents of a string
+	// instance (e.g. "base64"). Introduced by OpenAPI 3.1 in place of the byte
+	// format.
+	ContentEncoding string `json:"contentEncoding,omitempty" yaml:"contentEncoding,omitempty"`
 }
 
 // Clone returns a new deep copied instance of the object.
@@ -212,22 +228,7 @@ func (r Schema) MarshalJSON() ([]byte, error) {
 
 // UnmarshalJSON parses the JSON-encoded data and stores the result.
 func (r *Schema) UnmarshalJSON(data []byte) error {
-	return r.UnmarshalYAML(func(in interface{}) error {
-		obj := make(map[string]interface{})
-		if err := json.Unmarshal(data, &obj); err != nil {
-			return errors.WithStack(err)
-		}
-
-		rbytes, err := yaml.Marshal(obj)
-		if err != nil {
-			return errors.WithStack(err)
-		}
-
-		if err := yaml.Unmarshal(rbytes, in); err != nil {
-			return errors.WithStack(err)
-		}
-		return nil
-	})
+	return decodeJSON(data, r)
 }
 
 // MarshalYAML returns the YAML encoding.
@@ -334,12 +335,14 @@ func (r Schema) MarshalYAML() (interface{}, error) {
 		obj["required"] = r.Required
 	}
 
-	if len(r.Properties) > 0 {
+	if r.Properties.Len() > 0 {
 		obj["properties"] = r.Properties
 	}
 
 	if r.AdditionalProperties != nil {
 		obj["additionalProperties"] = r.AdditionalProperties
+	} else if r.AdditionalPropertiesAllowed != nil {
+		obj["additionalProperties"] = *r.AdditionalPropertiesAllowed
 	}
 
 	if len(r.Enum) > 0 {
@@ -382,11 +385,25 @@ func (r Schema) MarshalYAML() (interface{}, error) {
 		obj["format"] = r.Format
 	}
 
+	if r.ContentMediaType != "" {
+		obj["contentMediaType"] = r.ContentMediaType
+	}
+
+	if r.ContentEncoding != "" {
+		obj["contentEncoding"] = r.ContentEncoding
+	}
+
 	return obj, nil
 }
 
 // UnmarshalYAML parses the YAML-encoded data and stores the result.
-func (r *Schema) UnmarshalYAML(unmarshal func(interface{}) error) error {
+func (r *Schema) UnmarshalYAML(node *yaml.Node) error {
+	return decodeYAML(node, r)
+}
+
+// unmarshalValue stores the result of the unmarshal function, which decodes
+// either JSON or YAML data.
+func (r *Schema) unmarshalValue(d *valueDecoder, unmarshal func(interface{}) error) error {
 	obj := make(map[string]interface{})
 	if err := unmarshal(&obj); err != nil {
 		return errors.WithStack(err)
@@ -404,14 +421,10 @@ func (r *Schema) UnmarshalYAML(unmarshal func(interface{}) error) error {
 		}
 	}
 
-	if value, ok := obj["discriminator"]; ok {
-		rbytes, err := yaml.Marshal(value)
-		if err != nil {
-			return errors.WithStack(err)
-		}
+	if raw, ok := obj["discriminator"]; ok {
 		value := Discriminator{}
-		if err := yaml.Unmarshal(rbytes, &value); err != nil {
-			return errors.WithStack(err)
+		if err := d.decodeMember("discriminator", raw, &value); err != nil {
+			return err
 		}
 		r.Discriminator = &value
 	}
@@ -428,32 +441,24 @@ func (r *Schema) UnmarshalYAML(unmarshal func(interface{}) error) error {
 		}
 	}
 
-	if value, ok := obj["xml"]; ok {
-		rbytes, err := yaml.Marshal(value)
-		if err != nil {
-			return errors.WithStack(err)
-		}
+	if raw, ok := obj["xml"]; ok {
 		value := XML{}
-		if err := yaml.Unmarshal(rbytes, &value); err != nil {
-			return errors.WithStack(err)
+		if err := d.decodeMember("xml", raw, &value); err != nil {
+			return err
 		}
 		r.XML = &value
 	}
 
-	if value, ok := obj["externalDocs"]; ok {
-		rbytes, err := yaml.Marshal(value)
-		if err != nil {
-			return errors.WithStack(err)
-		}
+	if raw, ok := obj["externalDocs"]; ok {
 		value := ExternalDocumentation{}
-		if err := yaml.Unmarshal(rbytes, &value); err != nil {
-			return errors.WithStack(err)
+		if err := d.decodeMember("externalDocs", raw, &value); err != nil {
+			return err
 		}
 		r.ExternalDocs = &value
 	}
 
 	if value, ok := obj["example"]; ok {
-		r.Example = cleanupMapValue(value)
+		r.Example = value
 	}
 
 	if value, ok := obj["deprecated"]; ok {
@@ -509,14 +514,10 @@ func (r *Schema) UnmarshalYAML(unmarshal func(interface{}) error) error {
 		}
 	}
 
-	if value, ok := obj["items"]; ok {
-		rbytes, err := yaml.Marshal(value)
-		if err != nil {
-			return errors.WithStack(err)
-		}
+	if raw, ok := obj["items"]; ok {
 		value := Schema{}
-		if err := yaml.Unmarshal(rbytes, &value); err != nil {
-			return errors.WithStack(err)
+		if err := d.decodeMember("items", raw, &value); err != nil {
+			return err
 		}
 		r.Items = &value
 	}
@@ -553,28 +554,24 @@ func (r *Schema) UnmarshalYAML(unmarshal func(interface{}) error) error {
 		}
 	}
 
-	if value, ok := obj["properties"]; ok {
-		rbytes, err := yaml.Marshal(value)
-		if err != nil {
-			return errors.WithStack(err)
-		}
-		value := map[string]*Schema{}
-		if err := yaml.Unmarshal(rbytes, &value); err != nil {
-			return errors.WithStack(err)
+	if raw, ok := obj["properties"]; ok {
+		value := Properties{}
+		if err := d.decodeMember("properties", raw, &value); err != nil {
+			return err
 		}
 		r.Properties = value
 	}
 
-	if value, ok := obj["additionalProperties"]; ok {
-		rbytes, err := yaml.Marshal(value)
-		if err != nil {
-			return errors.WithStack(err)
-		}
-		value := Schema{}
-		if err := yaml.Unmarshal(rbytes, &value); err != nil {
-			return errors.WithStack(err)
+	if raw, ok := obj["additionalProperties"]; ok {
+		if allowed, ok := raw.(bool); ok {
+			r.AdditionalPropertiesAllowed = &allowed
+		} else {
+			value := Schema{}
+			if err := d.decodeMember("additionalProperties", raw, &value); err != nil {
+				return err
+			}
+			r.AdditionalProperties = &value
 		}
-		r.AdditionalProperties = &value
 	}
 
 	if value, ok := obj["enum"]; ok {
@@ -589,50 +586,34 @@ func (r *Schema) UnmarshalYAML(unmarshal func(interface{}) error) error {
 		}
 	}
 
-	if value, ok := obj["allOf"]; ok {
-		rbytes, err := yaml.Marshal(value)
-		if err != nil {
-			return errors.WithStack(err)
-		}
+	if raw, ok := obj["allOf"]; ok {
 		value := make([]*Schema, 0)
-		if err := yaml.Unmarshal(rbytes, &value); err != nil {
-			return errors.WithStack(err)
+		if err := d.decodeMember("allOf", raw, &value); err != nil {
+			return err
 		}
 		r.AllOf = value
 	}
 
-	if value, ok := obj["anyOf"]; ok {
-		rbytes, err := yaml.Marshal(value)
-		if err != nil {
-			return errors.WithStack(err)
-		}
+	if raw, ok := obj["anyOf"]; ok {
 		value := make([]*Schema, 0)
-		if err := yaml.Unmarshal(rbytes, &value); err != nil {
-			return errors.WithStack(err)
+		if err := d.decodeMember("anyOf", raw, &value); err != nil {
+			return err
 		}
 		r.AnyOf = value
 	}
 
-	if value, ok := obj["oneOf"]; ok {
-		rbytes, err := yaml.Marshal(value)
-		if err != nil {
-			return errors.WithStack(err)
-		}
+	if raw, ok := obj["oneOf"]; ok {
 		value := make([]*Schema, 0)
-		if err := yaml.Unmarshal(rbytes, &value); err != nil {
-			return errors.WithStack(err)
+		if err := d.decodeMember("oneOf", raw, &value); err != nil {
+			return err
 		}
 		r.OneOf = value
 	}
 
-	if value, ok := obj["not"]; ok {
-		rbytes, err := yaml.Marshal(value)
-		if err != nil {
-			return errors.WithStack(err)
-		}
+	if raw, ok := obj["not"]; ok {
 		value := Schema{}
-		if err := yaml.Unmarshal(rbytes, &value); err != nil {
-			return errors.WithStack(err)
+		if err := d.decodeMember("not", raw, &value); err != nil {
+			return err
 		}
 		r.Not = &value
 	}
@@ -659,5 +640,17 @@ func (r *Schema) UnmarshalYAML(unmarshal func(interface{}) error) error {
 		}
 	}
 
+	if value, ok := obj["contentMediaType"]; ok {
+		if value, ok := value.(string); ok {
+			r.ContentMediaType = value
+		}
+	}
+
+	if value, ok := obj["contentEncoding"]; ok {
+		if value, ok := value.(string); ok {
+			r.ContentEncoding = value
+		}
+	}
+
 	return nil
 }
@@ -0,0 +1,186 @@
+package oas
+
+import (
+	"reflect"
+)
+
+// DiffStatus describes how a schema or a property changed between two
+// versions of a document.
+type DiffStatus string
+
+// Diff statuses.
+const (
+	DiffAdded     DiffStatus = "added"
+	DiffRemoved   DiffStatus = "removed"
+	DiffChanged   DiffStatus = "changed"
+	DiffUnchanged DiffStatus = "unchanged"
+)
+
+// SchemaDiff describes a component schema, or a property of one, side by
+// side in two versions of a document. It is designed to be rendered, e.g.
+// as an HTML diff view, so unchanged properties are listed as well.
+type SchemaDiff struct {
+	// Name describes the name of the component schema or of the property.
+	Name string `json:"name" yaml:"name"`
+
+	// Status describes how the schema changed.
+	Status DiffStatus `json:"status" yaml:"status"`
+
+	// RequiredBefore describes whether the property was required, false for
+	// component schemas.
+	RequiredBefore bool `json:"requiredBefore,omitempty" yaml:"requiredBefore,omitempty"`
+
+	// RequiredAfter describes whether the property is required, false for
+	// component schemas.
+	RequiredAfter bool `json:"requiredAfter,omitempty" yaml:"requiredAfter,omitempty"`
+
+	// Before describes the keywords of the schema in the first version,
+	// except its properties and required list, or nil if it was absent.
+	Before map[string]interface{} `json:"before,omitempty" yaml:"before,omitempty"`
+
+	// After describes the keywords of the schema in the second version,
+	// except its properties and required list, or nil if it is absent.
+	After map[string]interface{} `json:"after,omitempty" yaml:"after,omitempty"`
+
+	// Constraints describes the keywords whose values differ, sorted by
+	// keyword.
+	Constraints []*ConstraintDiff `json:"constraints" yaml:"constraints"`
+
+	// Properties describes the properties of the schema in either version,
+	// sorted by name.
+	Properties []*SchemaDiff `json:"properties" yaml:"properties"`
+}
+
+// ConstraintDiff describes a schema keyword whose value differs between two
+// versions of a document.
+type ConstraintDiff struct {
+	// Keyword describes the schema keyword (e.g. "maxLength").
+	Keyword string `json:"keyword" yaml:"keyword"`
+
+	// Before describes the value in the first version, nil when unset.
+	Before interface{} `json:"before,omitempty" yaml:"before,omitempty"`
+
+	// After describes the value in the second version, nil when unset.
+	After interface{} `json:"after,omitempty" yaml:"after,omitempty"`
+}
+
+// DiffSchemas compares the component schemas of the document with those of
+// the next version and returns a diff per schema declared in either version,
+// sorted by name. Properties are compared recursively, other keywords such
+// as items or allOf are compared as a whole.
+func (r OpenAPI) DiffSchemas(next OpenAPI) ([]*SchemaDiff, error) {
+	before := map[string]*Schema{}
+	if r.Components != nil {
+		before = r.Components.Schemas
+	}
+	after := map[string]*Schema{}
+	if next.Components != nil {
+		after = next.Components.Schemas
+	}
+
+	diffs := make([]*SchemaDiff, 0)
+	for _, name := range unionKeys(before, after) {
+		diff, err := diffSchema(name, before[name], after[name])
+		if err != nil {
+			return nil, err
+		}
+		diffs = append(diffs, diff)
+	}
+	return diffs, nil
+}
+
+// diffSchema returns the diff of the schema in the two versions, either of
+// which may be nil.
+func diffSchema(name string, before *Schema, after *Schema) (*SchemaDiff, error) {
+	diff := &SchemaDiff{
+		Name:        name,
+		Status:      DiffUnchanged,
+		Constraints: make([]*ConstraintDiff, 0),
+		Properties:  make([]*SchemaDiff, 0),
+	}
+	var err error
+	if diff.Before, err = schemaKeywords(before); err != nil {
+		return nil, err
+	}
+	if diff.After, err = schemaKeywords(after); err != nil {
+		return nil, err
+	}
+
+	for _, keyword := range unionKeys(diff.Before, diff.After) {
+		if !reflect.DeepEqual(diff.Before[keyword], diff.After[keyword]) {
+			diff.Constraints = append(diff.Constraints, &ConstraintDiff{
+				Keyword: keyword,
+				Before:  diff.Before[keyword],
+				After:   diff.After[keyword],
+			})
+		}
+	}
+
+	properties := [2]map[string]*Schema{{}, {}}
+	required := [2]map[string]bool{{}, {}}
+	for i, schema := range []*Schema{before, after} {
+		if schema == nil {
+			continue
+		}
+		for _, name := range schema.Properties.Keys() {
+			properties[i][name] = schema.Properties.Get(name)
+		}
+		for _, name := range schema.Required {
+			required[i][name] = true
+		}
+	}
+	changed := len(diff.Constraints) > 0
+	for _, name := range unionKeys(properties[0], properties[1]) {
+		property, err := diffSchema(name, properties[0][name], properties[1][name])
+		if err != nil {
+			return nil, err
+		}
+		property.RequiredBefore, property.RequiredAfter = required[0][name], required[1][name]
+		if property.Status == DiffUnchanged && property.RequiredBefore != property.RequiredAfter {
+			property.Status = DiffChanged
+		}
+		changed = changed || property.Status != DiffUnchanged
+		diff.Properties = append(diff.Properties, property)
+	}
+
+	switch {
+	case before == nil:
+		diff.Status = DiffAdded
+	case after == nil:
+		diff.Status = DiffRemoved
+	case changed:
+		diff.Status = DiffChanged
+	}
+	return diff, nil
+}
+
+// schemaKeywords returns the keywords of the schema as generic values,
+// without its properties and required list, or nil for a nil schema.
+func schemaKeywords(schema *Schema) (map[string]interface{}, error) {
+	if schema == nil {
+		return nil, nil
+	}
+	value, err := genericValue(schema)
+	if err != nil {
+		return nil, err
+	}
+	keywords, _ := value.(map[string]interface{})
+	if keywords == nil {
+		keywords = map[string]interface{}{}
+	}
+	delete(keywords, "properties")
+	delete(keywords, "required")
+	return keywords, nil
+}
+
+// unionKeys returns the keys of both string keyed maps in sorted order.
+func unionKeys(a interface{}, b interface{}) []string {
+	keys := map[string]bool{}
+	for _, key := range sortedStrings(a) {
+		keys[key] = true
+	}
+	for _, key := range sortedStrings(b) {
+		keys[key] = true
+	}
+	return sortedStrings(keys)
+}
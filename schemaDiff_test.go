@@ -0,0 +1,120 @@
+package oas
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+)
+
+type SchemaDiffSuite struct {
+	suite.Suite
+}
+
+// schemaDiffPatch is the patch of the petstore fixture the suite compares
+// the next version against.
+const schemaDiffPatch = `
+components:
+  schemas:
+    Pet:
+      properties:
+        name:
+          maxLength: 50
+        age:
+          type: integer
+    Legacy:
+      type: string
+`
+
+func (r *SchemaDiffSuite) TestDiffSchemas() {
+	next := petstore(r.T(), schemaDiffPatch, `
+components:
+  schemas:
+    Legacy: null
+    Tag:
+      type: string
+    Pet:
+      required: [name, tag]
+      properties:
+        name:
+          maxLength: 100
+        age: null
+        color:
+          type: string
+          enum: [black, white]
+`)
+
+	diffs, err := petstore(r.T(), schemaDiffPatch).DiffSchemas(*next)
+	assert.Nil(r.T(), err)
+	assert.Equal(r.T(), []*SchemaDiff{
+		{
+			Name:        "Legacy",
+			Status:      DiffRemoved,
+			Before:      map[string]interface{}{"type": "string"},
+			Constraints: []*ConstraintDiff{{Keyword: "type", Before: "string"}},
+			Properties:  []*SchemaDiff{},
+		},
+		{
+			Name:        "Pet",
+			Status:      DiffChanged,
+			Before:      map[string]interface{}{"type": "object"},
+			After:       map[string]interface{}{"type": "object"},
+			Constraints: []*ConstraintDiff{},
+			Properties: []*SchemaDiff{
+				{
+					Name:        "age",
+					Status:      DiffRemoved,
+					Before:      map[string]interface{}{"type": "integer"},
+					Constraints: []*ConstraintDiff{{Keyword: "type", Before: "integer"}},
+					Properties:  []*SchemaDiff{},
+				},
+				{
+					Name:   "color",
+					Status: DiffAdded,
+					After:  map[string]interface{}{"type": "string", "enum": []interface{}{"black", "white"}},
+					Constraints: []*ConstraintDiff{
+						{Keyword: "enum", After: []interface{}{"black", "white"}},
+						{Keyword: "type", After: "string"},
+					},
+					Properties: []*SchemaDiff{},
+				},
+				{
+					Name:           "name",
+					Status:         DiffChanged,
+					RequiredBefore: true,
+					RequiredAfter:  true,
+					Before:         map[string]interface{}{"type": "string", "maxLength": float64(50)},
+					After:          map[string]interface{}{"type": "string", "maxLength": float64(100)},
+					Constraints:    []*ConstraintDiff{{Keyword: "maxLength", Before: float64(50), After: float64(100)}},
+					Properties:     []*SchemaDiff{},
+				},
+				{
+					Name:          "tag",
+					Status:        DiffChanged,
+					RequiredAfter: true,
+					Before:        map[string]interface{}{"type": "string"},
+					After:         map[string]interface{}{"type": "string"},
+					Constraints:   []*ConstraintDiff{},
+					Properties:    []*SchemaDiff{},
+				},
+			},
+		},
+		{
+			Name:        "Tag",
+			Status:      DiffAdded,
+			After:       map[string]interface{}{"type": "string"},
+			Constraints: []*ConstraintDiff{{Keyword: "type", After: "string"}},
+			Properties:  []*SchemaDiff{},
+		},
+	}, diffs)
+
+	diffs, err = petstore(r.T(), schemaDiffPatch).DiffSchemas(*petstore(r.T(), schemaDiffPatch))
+	assert.Nil(r.T(), err)
+	for _, diff := range diffs {
+		assert.Equal(r.T(), DiffUnchanged, diff.Status)
+	}
+}
+
+func TestSchemaDiffSuite(t *testing.T) {
+	suite.Run(t, new(SchemaDiffSuite))
+}
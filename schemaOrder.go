@@ -0,0 +1,136 @@
+package oas
+
+import (
+	"sort"
+)
+
+// SchemaOrder returns the names of the component schemas ordered by their
+// references, so that type generators can emit every declaration after the
+// declarations it depends on. Schemas referencing each other in a cycle are
+// grouped together, every other schema forms a group of its own. The names
+// within a group are sorted and among the groups whose dependencies are
+// emitted the one with the lowest name comes first, making the order stable
+// across runs. References to unknown components are ignored.
+func (r OpenAPI) SchemaOrder() [][]string {
+	if r.Components == nil || len(r.Components.Schemas) == 0 {
+		return [][]string{}
+	}
+
+	names := sortedStrings(r.Components.Schemas)
+	deps := make(map[string][]string, len(names))
+	for _, name := range names {
+		deps[name] = schemaDependencies(r.Components, name)
+	}
+
+	groups := stronglyConnected(names, deps)
+	groupOf := make(map[string]int, len(names))
+	for i, group := range groups {
+		for _, name := range group {
+			groupOf[name] = i
+		}
+	}
+
+	order := make([][]string, 0, len(groups))
+	emitted := make([]bool, len(groups))
+	for len(order) < len(groups) {
+		next := -1
+		for i, group := range groups {
+			if emitted[i] || !groupReady(group, deps, groupOf, emitted, i) {
+				continue
+			}
+			if next == -1 || group[0] < groups[next][0] {
+				next = i
+			}
+		}
+		emitted[next] = true
+		order = append(order, groups[next])
+	}
+	return order
+}
+
+// schemaDependencies returns the sorted names of the component schemas the
+// named component schema references, excluding unknown components.
+func schemaDependencies(components *Components, name string) []string {
+	set := map[string]bool{}
+	ptr := join("/components/schemas", name)
+	_ = (&walker{fn: func(ptr string, node interface{}) error {
+		if schema, ok := node.(*Schema); ok {
+			if dep, ok := componentName(schema.Ref, "schemas"); ok && components.Schemas[dep] != nil {
+				set[dep] = true
+			}
+		}
+		return nil
+	}}).schema(ptr, components.Schemas[name])
+
+	deps := make([]string, 0, len(set))
+	for dep := range set {
+		deps = append(deps, dep)
+	}
+	sort.Strings(deps)
+	return deps
+}
+
+// groupReady reports whether every dependency outside the group at index i
+// belongs to an emitted group.
+func groupReady(group []string, deps map[string][]string, groupOf map[string]int, emitted []bool, i int) bool {
+	for _, name := range group {
+		for _, dep := range deps[name] {
+			if j := groupOf[dep]; j != i && !emitted[j] {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// stronglyConnected returns the strongly connected components of the
+// dependency graph using Tarjan's algorithm, with the names of every
+// component sorted.
+func stronglyConnected(names []string, deps map[string][]string) [][]string {
+	index := map[string]int{}
+	lowlink := map[string]int{}
+	onStack := map[string]bool{}
+	stack := make([]string, 0)
+	groups := make([][]string, 0)
+
+	var visit func(name string)
+	visit = func(name string) {
+		index[name] = len(index)
+		lowlink[name] = index[name]
+		stack = append(stack, name)
+		onStack[name] = true
+
+		for _, dep := range deps[name] {
+			if _, ok := index[dep]; !ok {
+				visit(dep)
+				if lowlink[dep] < lowlink[name] {
+					lowlink[name] = lowlink[dep]
+				}
+			} else if onStack[dep] && index[dep] < lowlink[name] {
+				lowlink[name] = index[dep]
+			}
+		}
+
+		if lowlink[name] == index[name] {
+			group := make([]string, 0)
+			for {
+				top := stack[len(stack)-1]
+				stack = stack[:len(stack)-1]
+				onStack[top] = false
+				group = append(group, top)
+				if top == name {
+					break
+				}
+			}
+			sort.Strings(group)
+			groups = append(groups, group)
+		}
+	}
+
+	for _, name := range names {
+		if _, ok := index[name]; !ok {
+			visit(name)
+		}
+	}
+	return groups
+}
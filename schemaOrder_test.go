@@ -0,0 +1,70 @@
+package oas
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+)
+
+type SchemaOrderSuite struct {
+	suite.Suite
+}
+
+func (r *SchemaOrderSuite) TestSchemaOrder() {
+	testCases := []struct {
+		schemas  map[string]*Schema
+		expected [][]string
+	}{
+		{
+			nil,
+			[][]string{},
+		},
+		{
+			map[string]*Schema{
+				"Pet": {
+					Properties: NewProperties(map[string]*Schema{
+						"owner": {Ref: "#/components/schemas/Owner"},
+						"tags": {
+							Type:  "array",
+							Items: &Schema{Ref: "#/components/schemas/Tag"},
+						},
+					}),
+				},
+				"Owner":   {Properties: NewProperties(map[string]*Schema{"address": {Ref: "#/components/schemas/Address"}})},
+				"Tag":     {Type: "string"},
+				"Address": {Properties: NewProperties(map[string]*Schema{"zip": {Ref: "#/components/schemas/Missing"}})},
+			},
+			[][]string{{"Address"}, {"Owner"}, {"Tag"}, {"Pet"}},
+		},
+		{
+			map[string]*Schema{
+				"Node": {
+					Properties: NewProperties(map[string]*Schema{
+						"children": {Type: "array", Items: &Schema{Ref: "#/components/schemas/Node"}},
+					}),
+				},
+				"Employee": {Properties: NewProperties(map[string]*Schema{"team": {Ref: "#/components/schemas/Team"}})},
+				"Team": {
+					Properties: NewProperties(map[string]*Schema{
+						"lead":   {Ref: "#/components/schemas/Employee"},
+						"parent": {Ref: "#/components/schemas/Company"},
+					}),
+				},
+				"Company": {Type: "object"},
+				"Audit":   {AllOf: []*Schema{{Ref: "#/components/schemas/Team"}}},
+			},
+			[][]string{{"Company"}, {"Employee", "Team"}, {"Audit"}, {"Node"}},
+		},
+	}
+
+	for i, testCase := range testCases {
+		doc := OpenAPI{Components: &Components{Schemas: testCase.schemas}}
+		failMsg := "test case %d failed"
+		assert.Equal(r.T(), testCase.expected, doc.SchemaOrder(), failMsg, i)
+	}
+}
+
+func TestSchemaOrderSuite(t *testing.T) {
+	suite.Run(t, new(SchemaOrderSuite))
+}
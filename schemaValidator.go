@@ -0,0 +1,518 @@
+package oas
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"reflect"
+	"regexp"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/pkg/errors"
+)
+
+// ValueError describes why a value does not conform to a schema.
+type ValueError struct {
+	// Pointer describes the JSON Pointer of the offending value within the
+	// validated value, empty for the value itself.
+	Pointer string
+
+	// Keyword describes the keyword of the schema the value violates (e.g.
+	// "maxLength" or "required").
+	Keyword string
+
+	// Message describes the violation in a human readable form.
+	Message string
+}
+
+// Error returns the string representation of the error.
+func (e *ValueError) Error() string {
+	ptr := e.Pointer
+	if ptr == "" {
+		ptr = "/"
+	}
+	return ptr + ": " + e.Message
+}
+
+// ValueErrors describes every violation of a schema by a value, ordered by
+// the position of the offending values.
+type ValueErrors []*ValueError
+
+// Error returns the string representation of the error.
+func (e ValueErrors) Error() string {
+	messages := make([]string, 0, len(e))
+	for _, err := range e {
+		messages = append(messages, err.Error())
+	}
+	return strings.Join(messages, "; ")
+}
+
+// SchemaValidator validates values against a schema compiled once, with its
+// references resolved and its patterns compiled, so it can be reused for
+// every payload of an operation. It is safe for concurrent use.
+type SchemaValidator struct {
+	doc      *OpenAPI
+	schema   *Schema
+	patterns map[string]*regexp.Regexp
+}
+
+// NewSchemaValidator compiles the schema, whose references are resolved
+// against the document. The document may be nil for schemas without
+// references. An error is returned for unresolvable references and invalid
+// patterns.
+func NewSchemaValidator(doc *OpenAPI, schema *Schema) (*SchemaValidator, error) {
+	v := &SchemaValidator{doc: doc, schema: schema, patterns: map[string]*regexp.Regexp{}}
+	if err := v.compile(schema, map[*Schema]bool{}); err != nil {
+		return nil, err
+	}
+	return v, nil
+}
+
+// compile resolves the references and compiles the patterns of the schema
+// and of the schemas it holds.
+func (v *SchemaValidator) compile(schema *Schema, visited map[*Schema]bool) error {
+	if schema == nil || visited[schema] {
+		return nil
+	}
+	visited[schema] = true
+	if schema.Ref != "" {
+		resolved := v.resolve(schema)
+		if resolved == nil {
+			return errors.Errorf("unresolved reference %q", schema.Ref)
+		}
+		return v.compile(resolved, visited)
+	}
+	if schema.Pattern != "" {
+		if _, ok := v.patterns[schema.Pattern]; !ok {
+			pattern, err := regexp.Compile(schema.Pattern)
+			if err != nil {
+				return errors.Wrapf(err, "pattern %q", schema.Pattern)
+			}
+			v.patterns[schema.Pattern] = pattern
+		}
+	}
+
+	children := []*Schema{schema.Items, schema.AdditionalProperties, schema.Not}
+	children = append(children, schema.AllOf...)
+	children = append(children, schema.AnyOf...)
+	children = append(children, schema.OneOf...)
+	for _, key := range sortedKeys(schema.Properties) {
+		children = append(children, schema.Properties.Get(key))
+	}
+	for _, child := range children {
+		if err := v.compile(child, visited); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// resolve returns the schema with its references followed, or nil when a
+// reference cannot be resolved.
+func (v *SchemaValidator) resolve(schema *Schema) *Schema {
+	if schema == nil || schema.Ref == "" {
+		return schema
+	}
+	if v.doc == nil {
+		return nil
+	}
+	return NewResolver(v.doc).schema(schema)
+}
+
+// Validate reports every violation of the schema by the value as
+// ValueErrors, or returns nil if the value conforms. Values are those
+// decoded from JSON or YAML into generic maps, slices and scalars; integers
+// of any size and json.Number are accepted as numbers. Formats are checked
+// with the validators registered with RegisterFormat.
+func (v *SchemaValidator) Validate(value interface{}) error {
+	errs := make(ValueErrors, 0)
+	v.validate(v.schema, "", value, &errs, map[schemaCheck]bool{})
+	if len(errs) == 0 {
+		return nil
+	}
+	return errs
+}
+
+// schemaCheck identifies the check of the value at a pointer against a
+// schema.
+type schemaCheck struct {
+	schema *Schema
+	ptr    string
+}
+
+// validate adds the violations of the schema by the value at the pointer
+// and reports whether the value was checked. Checks already in progress are
+// skipped, so schemas referring to themselves through allOf, anyOf or oneOf
+// do not recurse endlessly.
+func (v *SchemaValidator) validate(schema *Schema, ptr string, value interface{}, errs *ValueErrors, checking map[schemaCheck]bool) bool {
+	if schema == nil {
+		return true
+	}
+	report := func(keyword string, format string, args ...interface{}) {
+		*errs = append(*errs, &ValueError{Pointer: ptr, Keyword: keyword, Message: fmt.Sprintf(format, args...)})
+	}
+	ref := schema.Ref
+	if schema = v.resolve(schema); schema == nil {
+		report("$ref", "unresolved reference %q", ref)
+		return true
+	}
+	check := schemaCheck{schema: schema, ptr: ptr}
+	if checking[check] {
+		return false
+	}
+	checking[check] = true
+	defer delete(checking, check)
+
+	if len(schema.Enum) > 0 && !enumContains(schema.Enum, value) {
+		report("enum", "value must be one of the enum values")
+	}
+	if value == nil {
+		if !schema.Nullable && schema.Type != "" {
+			report("nullable", "value must not be null")
+		}
+		return true
+	}
+
+	switch kind := valueKind(value); {
+	case schema.Type == "" || kind == schema.Type:
+	case schema.Type == "number" && kind == "integer":
+	default:
+		report("type", "value must be of type %s, not %s", schema.Type, kind)
+		return true
+	}
+
+	if number, ok := numberValue(value); ok {
+		v.validateNumber(schema, number, report)
+	}
+	if schema.Format != "" {
+		v.validateFormat(schema, value, report)
+	}
+	switch value := normalizeValue(value).(type) {
+	case string:
+		v.validateString(schema, value, report)
+	case []interface{}:
+		v.validateArray(schema, ptr, value, report, errs, checking)
+	case map[string]interface{}:
+		v.validateObject(schema, ptr, value, report, errs, checking)
+	}
+
+	for _, member := range schema.AllOf {
+		v.validate(member, ptr, value, errs, checking)
+	}
+	if len(schema.AnyOf) > 0 && v.matches(schema.AnyOf, ptr, value, checking) == 0 {
+		report("anyOf", "value must match at least one of the anyOf schemas")
+	}
+	if len(schema.OneOf) > 0 {
+		if count := v.matches(schema.OneOf, ptr, value, checking); count != 1 {
+			report("oneOf", "value must match exactly one of the oneOf schemas, matches %d", count)
+		}
+	}
+	if schema.Not != nil && v.matches([]*Schema{schema.Not}, ptr, value, checking) == 1 {
+		report("not", "value must not match the not schema")
+	}
+	return true
+}
+
+// matches returns the number of schemas the value conforms to, leaving out
+// those skipped as already in progress.
+func (v *SchemaValidator) matches(schemas []*Schema, ptr string, value interface{}, checking map[schemaCheck]bool) int {
+	count := 0
+	for _, schema := range schemas {
+		errs := make(ValueErrors, 0)
+		if checked := v.validate(schema, ptr, value, &errs, checking); checked && len(errs) == 0 {
+			count++
+		}
+	}
+	return count
+}
+
+// validateNumber adds the violations of the numeric keywords.
+func (v *SchemaValidator) validateNumber(schema *Schema, number float64, report func(string, string, ...interface{})) {
+	if multipleOf, ok := numberValue(schema.MultipleOf); ok && multipleOf > 0 {
+		if quotient := number / multipleOf; math.Abs(quotient-math.Round(quotient)) > 1e-9 {
+			report("multipleOf", "value must be a multiple of %v", multipleOf)
+		}
+	}
+	if maximum, ok := numberValue(schema.Maximum); ok {
+		if number > maximum || (schema.ExclusiveMaximum && number == maximum) {
+			report("maximum", "value must be less than %s%v", orEqual(!schema.ExclusiveMaximum), maximum)
+		}
+	}
+	if minimum, ok := numberValue(schema.Minimum); ok {
+		if number < minimum || (schema.ExclusiveMinimum && number == minimum) {
+			report("minimum", "value must be greater than %s%v", orEqual(!schema.ExclusiveMinimum), minimum)
+		}
+	}
+}
+
+// orEqual returns "or equal to " when inclusive.
+func orEqual(inclusive bool) string {
+	if inclusive {
+		return "or equal to "
+	}
+	return ""
+}
+
+// validateFormat adds the violation of the format of the schema by a string
+// or a number, when a validator is registered for the format.
+func (v *SchemaValidator) validateFormat(schema *Schema, value interface{}, report func(string, string, ...interface{})) {
+	validator := formatValidator(schema.Format)
+	if validator == nil {
+		return
+	}
+	text, ok := normalizeValue(value).(string)
+	if number, isNumber := numberText(value); isNumber {
+		text, ok = number, true
+	}
+	if !ok {
+		return
+	}
+	if err := validator(text); err != nil {
+		report("format", "%s", err.Error())
+	}
+}
+
+// validateString adds the violations of the string keywords.
+func (v *SchemaValidator) validateString(schema *Schema, value string, report func(string, string, ...interface{})) {
+	length := utf8.RuneCountInString(value)
+	if maxLength, ok := numberValue(schema.MaxLength); ok && float64(length) > maxLength {
+		report("maxLength", "value must be at most %v characters long", maxLength)
+	}
+	if minLength, ok := numberValue(schema.MinLength); ok && float64(length) < minLength {
+		report("minLength", "value must be at least %v characters long", minLength)
+	}
+	if pattern := v.patterns[schema.Pattern]; pattern != nil && !pattern.MatchString(value) {
+		report("pattern", "value must match the pattern %s", schema.Pattern)
+	}
+}
+
+// validateArray adds the violations of the array keywords and of the items.
+func (v *SchemaValidator) validateArray(schema *Schema, ptr string, items []interface{}, report func(string, string, ...interface{}), errs *ValueErrors, checking map[schemaCheck]bool) {
+	if maxItems, ok := numberValue(schema.MaxItems); ok && float64(len(items)) > maxItems {
+		report("maxItems", "value must hold at most %v items", maxItems)
+	}
+	if minItems, ok := numberValue(schema.MinItems); ok && float64(len(items)) < minItems {
+		report("minItems", "value must hold at least %v items", minItems)
+	}
+	if schema.UniqueItems {
+		for i := range items {
+			for j := 0; j < i; j++ {
+				if equalValues(items[i], items[j]) {
+					report("uniqueItems", "items %d and %d must not be equal", j, i)
+				}
+			}
+		}
+	}
+	for i, item := range items {
+		v.validate(schema.Items, join(ptr, strconv.Itoa(i)), item, errs, checking)
+	}
+}
+
+// validateObject adds the violations of the object keywords and of the
+// properties.
+func (v *SchemaValidator) validateObject(schema *Schema, ptr string, obj map[string]interface{}, report func(string, string, ...interface{}), errs *ValueErrors, checking map[schemaCheck]bool) {
+	if maxProperties, ok := numberValue(schema.MaxProperties); ok && float64(len(obj)) > maxProperties {
+		report("maxProperties", "value must hold at most %v properties", maxProperties)
+	}
+	if minProperties, ok := numberValue(schema.MinProperties); ok && float64(len(obj)) < minProperties {
+		report("minProperties", "value must hold at least %v properties", minProperties)
+	}
+	for _, name := range schema.Required {
+		if _, ok := obj[name]; !ok {
+			report("required", "missing required property %q", name)
+		}
+	}
+	for _, name := range sortedStrings(obj) {
+		if property, ok := schema.Properties.Lookup(name); ok {
+			v.validate(property, join(ptr, name), obj[name], errs, checking)
+		} else if schema.AdditionalProperties != nil {
+			v.validate(schema.AdditionalProperties, join(ptr, name), obj[name], errs, checking)
+		} else if schema.AdditionalPropertiesAllowed != nil && !*schema.AdditionalPropertiesAllowed {
+			report("additionalProperties", "property %q is not allowed", name)
+		}
+	}
+}
+
+// valueKind returns the type of the schema the value is an instance of.
+func valueKind(value interface{}) string {
+	if number, ok := numberValue(value); ok {
+		if number == math.Trunc(number) && !math.IsInf(number, 0) {
+			return "integer"
+		}
+		return "number"
+	}
+	switch normalizeValue(value).(type) {
+	case string:
+		return "string"
+	case bool:
+		return "boolean"
+	case []interface{}:
+		return "array"
+	case map[string]interface{}:
+		return "object"
+	}
+	return fmt.Sprintf("%T", value)
+}
+
+// numberValue returns the value as a float64 if it is a number.
+func numberValue(value interface{}) (float64, bool) {
+	switch value := value.(type) {
+	case nil:
+		return 0, false
+	case json.Number:
+		number, err := value.Float64()
+		return number, err == nil
+	}
+	rv := reflect.ValueOf(value)
+	switch rv.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return float64(rv.Int()), true
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return float64(rv.Uint()), true
+	case reflect.Float32, reflect.Float64:
+		return rv.Float(), true
+	}
+	return 0, false
+}
+
+// numberText returns the decimal representation of a number without an
+// exponent, so integral floats decoded by encoding/json (e.g. 1e+08) read as
+// integers.
+func numberText(value interface{}) (string, bool) {
+	switch value := value.(type) {
+	case nil:
+		return "", false
+	case json.Number:
+		if number, err := value.Int64(); err == nil {
+			return strconv.FormatInt(number, 10), true
+		}
+		number, err := value.Float64()
+		if err != nil {
+			return "", false
+		}
+		return strconv.FormatFloat(number, 'f', -1, 64), true
+	}
+	rv := reflect.ValueOf(value)
+	switch rv.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return strconv.FormatInt(rv.Int(), 10), true
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return strconv.FormatUint(rv.Uint(), 10), true
+	case reflect.Float32, reflect.Float64:
+		return strconv.FormatFloat(rv.Float(), 'f', -1, 64), true
+	}
+	return "", false
+}
+
+// normalizeValue returns the maps and slices of the value as generic maps
+// and slices, leaving other values unchanged.
+func normalizeValue(value interface{}) interface{} {
+	switch value := value.(type) {
+	case map[string]interface{}, []interface{}:
+		return value
+	case map[interface{}]interface{}:
+		obj := make(map[string]interface{}, len(value))
+		for key, item := range value {
+			obj[fmt.Sprint(key)] = item
+		}
+		return obj
+	}
+	rv := reflect.ValueOf(value)
+	switch rv.Kind() {
+	case reflect.Map:
+		if rv.Type().Key().Kind() != reflect.String {
+			return value
+		}
+		obj := make(map[string]interface{}, rv.Len())
+		iter := rv.MapRange()
+		for iter.Next() {
+			obj[iter.Key().String()] = iter.Value().Interface()
+		}
+		return obj
+	case reflect.Slice, reflect.Array:
+		if rv.Kind() == reflect.Slice && rv.Type().Elem().Kind() == reflect.Uint8 {
+			return value
+		}
+		items := make([]interface{}, rv.Len())
+		for i := range items {
+			items[i] = rv.Index(i).Interface()
+		}
+		return items
+	case reflect.String:
+		return rv.String()
+	case reflect.Bool:
+		return rv.Bool()
+	}
+	return value
+}
+
+// equalValues reports whether the values are equal as JSON values, numbers
+// being compared by value whatever their Go type.
+func equalValues(a interface{}, b interface{}) bool {
+	if x, ok := numberValue(a); ok {
+		y, ok := numberValue(b)
+		return ok && x == y
+	}
+	switch a := normalizeValue(a).(type) {
+	case []interface{}:
+		b, ok := normalizeValue(b).([]interface{})
+		if !ok || len(a) != len(b) {
+			return false
+		}
+		for i := range a {
+			if !equalValues(a[i], b[i]) {
+				return false
+			}
+		}
+		return true
+	case map[string]interface{}:
+		b, ok := normalizeValue(b).(map[string]interface{})
+		if !ok || len(a) != len(b) {
+			return false
+		}
+		for key, item := range a {
+			other, ok := b[key]
+			if !ok || !equalValues(item, other) {
+				return false
+			}
+		}
+		return true
+	default:
+		return reflect.DeepEqual(a, normalizeValue(b))
+	}
+}
+
+// enumContains reports whether the value equals one of the enum values.
+func enumContains(enum []interface{}, value interface{}) bool {
+	for _, item := range enum {
+		if equalValues(item, value) {
+			return true
+		}
+	}
+	return false
+}
+
+// ValidateValue reports every violation of the schema by the value as
+// ValueErrors, or returns nil if the value conforms. The schema must not
+// hold references; use NewSchemaValidator with the document to resolve
+// them, and to validate many values against the same schema.
+func (r *Schema) ValidateValue(value interface{}) error {
+	validator, err := NewSchemaValidator(nil, r)
+	if err != nil {
+		return err
+	}
+	return validator.Validate(value)
+}
+
+// ValidatePayload is the PayloadValidator validating the value against the
+// schema with a SchemaValidator. It is used by the functions taking a
+// PayloadValidator when none is given.
+func ValidatePayload(doc *OpenAPI, schema *Schema, value interface{}) error {
+	validator, err := NewSchemaValidator(doc, schema)
+	if err != nil {
+		return err
+	}
+	return validator.Validate(value)
+}
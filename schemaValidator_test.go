@@ -0,0 +1,182 @@
+package oas
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+)
+
+type SchemaValidatorSuite struct {
+	suite.Suite
+}
+
+func (r *SchemaValidatorSuite) TestValidateValue() {
+	pet := &Schema{
+		Type:     "object",
+		Required: []string{"name"},
+		Properties: NewProperties(map[string]*Schema{
+			"name": {Type: "string", MinLength: 1, MaxLength: 8, Pattern: "^[A-Z]"},
+			"age":  {Type: "integer", Minimum: 0, Maximum: 30, ExclusiveMaximum: true},
+			"tags": {Type: "array", Items: &Schema{Type: "string"}, MaxItems: 2, UniqueItems: true},
+			"kind": {Type: "string", Enum: []interface{}{"cat", "dog"}},
+		}),
+		AdditionalProperties: &Schema{Type: "number", MultipleOf: 0.5},
+	}
+
+	testCases := []struct {
+		schema   *Schema
+		value    interface{}
+		expected []string
+	}{
+		{pet, map[string]interface{}{"name": "Rex", "age": 3, "tags": []interface{}{"a"}, "kind": "dog"}, nil},
+		{pet, map[string]interface{}{"name": "Rex", "weight": 2.5}, nil},
+		{pet, "Rex", []string{" type"}},
+		{pet, map[string]interface{}{}, []string{" required"}},
+		{
+			pet,
+			map[string]interface{}{
+				"name":   "rex the great",
+				"age":    30.5,
+				"tags":   []interface{}{"a", "a", 1},
+				"kind":   "bird",
+				"weight": 2.2,
+			},
+			[]string{
+				"/age type",
+				"/kind enum",
+				"/name maxLength",
+				"/name pattern",
+				"/tags maxItems",
+				"/tags uniqueItems",
+				"/tags/2 type",
+				"/weight multipleOf",
+			},
+		},
+		{
+			&Schema{Type: "object", Properties: NewProperties(map[string]*Schema{"name": {Type: "string"}}), AdditionalPropertiesAllowed: new(bool)},
+			map[string]interface{}{"name": "Rex", "age": 3},
+			[]string{" additionalProperties"},
+		},
+		{
+			&Schema{Type: "object", AdditionalPropertiesAllowed: &[]bool{true}[0]},
+			map[string]interface{}{"name": "Rex", "age": 3},
+			nil,
+		},
+		{&Schema{Type: "number", Minimum: 1, ExclusiveMinimum: true}, 1, []string{" minimum"}},
+		{&Schema{Type: "number"}, json.Number("1.5"), nil},
+		{&Schema{Type: "integer"}, int64(7), nil},
+		{&Schema{Type: "string"}, nil, []string{" nullable"}},
+		{&Schema{Type: "string", Nullable: true}, nil, nil},
+		{&Schema{Type: "object", MinProperties: 1, MaxProperties: 1}, map[string]interface{}{}, []string{" minProperties"}},
+		{&Schema{Type: "array", MinItems: 1}, []string{}, []string{" minItems"}},
+		{
+			&Schema{AllOf: []*Schema{{Type: "string"}, {MinLength: 2}}},
+			"a",
+			[]string{" minLength"},
+		},
+		{&Schema{AnyOf: []*Schema{{Type: "string"}, {Type: "integer"}}}, 1, nil},
+		{&Schema{AnyOf: []*Schema{{Type: "string"}, {Type: "integer"}}}, true, []string{" anyOf"}},
+		{&Schema{OneOf: []*Schema{{Type: "number"}, {Type: "integer"}}}, 1, []string{" oneOf"}},
+		{&Schema{OneOf: []*Schema{{Type: "number"}, {Type: "integer"}}}, 1.5, nil},
+		{&Schema{Not: &Schema{Type: "string"}}, "a", []string{" not"}},
+		{&Schema{Type: "boolean", Enum: []interface{}{true}}, false, []string{" enum"}},
+	}
+
+	for i, testCase := range testCases {
+		failMsg := "test case %d failed"
+		err := testCase.schema.ValidateValue(testCase.value)
+		if testCase.expected == nil {
+			assert.Nil(r.T(), err, failMsg, i)
+			continue
+		}
+		if !assert.IsType(r.T(), ValueErrors{}, err, failMsg, i) {
+			continue
+		}
+		actual := make([]string, 0)
+		for _, valueErr := range err.(ValueErrors) {
+			actual = append(actual, valueErr.Pointer+" "+valueErr.Keyword)
+		}
+		assert.Equal(r.T(), testCase.expected, actual, failMsg, i)
+	}
+}
+
+func (r *SchemaValidatorSuite) TestSchemaValidator() {
+	doc := &OpenAPI{Components: &Components{Schemas: map[string]*Schema{
+		"Node": {
+			Type:     "object",
+			Required: []string{"value"},
+			Properties: NewProperties(map[string]*Schema{
+				"value":    {Type: "integer"},
+				"children": {Type: "array", Items: &Schema{Ref: "#/components/schemas/Node"}},
+			}),
+		},
+	}}}
+	validator, err := NewSchemaValidator(doc, &Schema{Ref: "#/components/schemas/Node"})
+	if !assert.Nil(r.T(), err) {
+		return
+	}
+	assert.Nil(r.T(), validator.Validate(map[string]interface{}{
+		"value":    1,
+		"children": []interface{}{map[string]interface{}{"value": 2}},
+	}))
+	err = validator.Validate(map[string]interface{}{
+		"value":    1,
+		"children": []interface{}{map[string]interface{}{"value": "2"}, map[string]interface{}{}},
+	})
+	assert.EqualError(r.T(), err, "/children/0/value: value must be of type integer, not string; "+
+		`/children/1: missing required property "value"`)
+	assert.Nil(r.T(), ValidatePayload(doc, &Schema{Ref: "#/components/schemas/Node"}, map[string]interface{}{"value": 3}))
+
+	_, err = NewSchemaValidator(nil, &Schema{Ref: "#/components/schemas/Node"})
+	assert.NotNil(r.T(), err)
+	_, err = NewSchemaValidator(nil, &Schema{Type: "string", Pattern: "("})
+	assert.NotNil(r.T(), err)
+}
+
+func (r *SchemaValidatorSuite) TestSelfReference() {
+	doc := &OpenAPI{Components: &Components{Schemas: map[string]*Schema{
+		"A": {Type: "object", Required: []string{"id"}, AllOf: []*Schema{{Ref: "#/components/schemas/A"}}},
+		"B": {AnyOf: []*Schema{{Ref: "#/components/schemas/B"}, {Type: "string"}}},
+		"C": {Type: "object", OneOf: []*Schema{{AllOf: []*Schema{{Ref: "#/components/schemas/C"}}}}},
+	}}}
+
+	testCases := []struct {
+		schema   string
+		value    interface{}
+		expected string
+	}{
+		{"A", map[string]interface{}{"id": 1}, ""},
+		{"A", map[string]interface{}{}, `/: missing required property "id"`},
+		{"B", "pets", ""},
+		{"B", 1, "/: value must match at least one of the anyOf schemas"},
+		{"C", map[string]interface{}{}, ""},
+		{"C", "pets", "/: value must be of type object, not string"},
+	}
+
+	failMsg := "test case %d failed"
+	for i, testCase := range testCases {
+		validator, err := NewSchemaValidator(doc, &Schema{Ref: "#/components/schemas/" + testCase.schema})
+		if !assert.Nil(r.T(), err, failMsg, i) {
+			continue
+		}
+		err = validator.Validate(testCase.value)
+		if testCase.expected == "" {
+			assert.Nil(r.T(), err, failMsg, i)
+		} else {
+			assert.EqualError(r.T(), err, testCase.expected, failMsg, i)
+		}
+	}
+
+	validator, err := NewSchemaValidator(doc, &Schema{Ref: "#/components/schemas/A"})
+	if !assert.Nil(r.T(), err) {
+		return
+	}
+	delete(doc.Components.Schemas, "A")
+	assert.EqualError(r.T(), validator.Validate(map[string]interface{}{}), `/: unresolved reference "#/components/schemas/A"`)
+}
+
+func TestSchemaValidatorSuite(t *testing.T) {
+	suite.Run(t, new(SchemaValidatorSuite))
+}
@@ -7,7 +7,7 @@ import (
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/suite"
-	"gopkg.in/yaml.v2"
+	"gopkg.in/yaml.v3"
 )
 
 type SchemaSuite struct {
@@ -32,12 +32,20 @@ func (r *SchemaSuite) TestSchema() {
 				Default:          20,
 			},
 		},
+		{
+			false,
+			&Schema{
+				Type:             "string",
+				ContentMediaType: "image/png",
+				ContentEncoding:  "base64",
+			},
+		},
 		{
 			false,
 			&Schema{
 				Type:     "object",
 				Required: []string{"name"},
-				Properties: map[string]*Schema{
+				Properties: NewProperties(map[string]*Schema{
 					"name": {
 						Type: "string",
 					},
@@ -49,7 +57,7 @@ func (r *SchemaSuite) TestSchema() {
 						Format:  "int32",
 						Minimum: 0,
 					},
-				},
+				}),
 				Extensions: Extensions{
 					"x-unit": map[string]interface{}{
 						"unit": "test",
@@ -76,11 +84,25 @@ func (r *SchemaSuite) TestSchema() {
 				},
 			},
 		},
+		{
+			false,
+			&Schema{
+				Type:                        "object",
+				AdditionalPropertiesAllowed: new(bool),
+			},
+		},
+		{
+			false,
+			&Schema{
+				Type:                        "object",
+				AdditionalPropertiesAllowed: &[]bool{true}[0],
+			},
+		},
 		{
 			false,
 			&Schema{
 				Type: "object",
-				Properties: map[string]*Schema{
+				Properties: NewProperties(map[string]*Schema{
 					"id": {
 						Type:   "integer",
 						Format: "int64",
@@ -88,7 +110,7 @@ func (r *SchemaSuite) TestSchema() {
 					"name": {
 						Type: "string",
 					},
-				},
+				}),
 				Required: []string{"name"},
 				Example: map[string]interface{}{
 					"name": "Puma",
@@ -135,6 +157,35 @@ func (r *SchemaSuite) TestSchema() {
 	}
 }
 
+func (r *SchemaSuite) TestAdditionalProperties() {
+	allowed, denied := true, false
+	testCases := []struct {
+		data     string
+		expected *Schema
+	}{
+		{"type: object\nadditionalProperties: false\n", &Schema{Type: "object", AdditionalPropertiesAllowed: &denied}},
+		{"type: object\nadditionalProperties: true\n", &Schema{Type: "object", AdditionalPropertiesAllowed: &allowed}},
+		{`{"type": "object", "additionalProperties": false}`, &Schema{Type: "object", AdditionalPropertiesAllowed: &denied}},
+		{
+			"type: object\nadditionalProperties:\n  type: string\n",
+			&Schema{Type: "object", AdditionalProperties: &Schema{Type: "string"}},
+		},
+	}
+
+	for i, testCase := range testCases {
+		failMsg := "test case %d failed"
+		actual := &Schema{}
+		if !assert.Nil(r.T(), decodeDocument([]byte(testCase.data), actual), failMsg, i) {
+			continue
+		}
+		assert.Equal(r.T(), testCase.expected, actual, failMsg, i)
+	}
+
+	rbytes, err := json.Marshal(&Schema{Type: "object", AdditionalPropertiesAllowed: &denied})
+	assert.Nil(r.T(), err)
+	assert.JSONEq(r.T(), `{"type": "object", "additionalProperties": false}`, string(rbytes))
+}
+
 func TestSchemaSuite(t *testing.T) {
 	suite.Run(t, new(SchemaSuite))
 }
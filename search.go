@@ -0,0 +1,168 @@
+package oas
+
+import (
+	"reflect"
+	"sort"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// SearchQuery describes what Search looks for. Empty criteria match
+// everything, so a query for the "description" field of "operation" nodes
+// without text returns every operation description.
+type SearchQuery struct {
+	// Text describes the text searched for in the values of the fields,
+	// case-insensitively. For maps, such as schema properties, the keys are
+	// searched.
+	Text string
+
+	// Exact requires the whole value to match Text instead of containing it.
+	Exact bool
+
+	// Field restricts the search to the fields of the given name (e.g.
+	// "description", "summary", "properties" or an "x-" extension).
+	Field string
+
+	// Kind restricts the search to the objects of the given kind, the
+	// lowerCamelCase name of their type (e.g. "operation", "schema",
+	// "parameter" or "pathItem").
+	Kind string
+}
+
+// SearchHit describes a value matching a search query.
+type SearchHit struct {
+	// Location describes the workspace location of the document the hit was
+	// found in. It is empty when searching a single document.
+	Location string `json:"location,omitempty" yaml:"location,omitempty"`
+
+	// Pointer describes the JSON pointer addressing the value, or the map
+	// entry for hits on keys.
+	Pointer string `json:"pointer" yaml:"pointer"`
+
+	// Kind describes the kind of the object holding the value.
+	Kind string `json:"kind" yaml:"kind"`
+
+	// Field describes the name of the field holding the value.
+	Field string `json:"field" yaml:"field"`
+
+	// Value describes the matching value.
+	Value string `json:"value" yaml:"value"`
+}
+
+// Search returns the string values and map keys of the document matching the
+// query, ordered by pointer. Nested objects are reported under their own
+// kind, e.g. a schema property under "schema" rather than under the
+// operation using the schema.
+func (r OpenAPI) Search(query SearchQuery) []*SearchHit {
+	hits := make([]*SearchHit, 0)
+	text := strings.ToLower(query.Text)
+	match := func(value string) bool {
+		if query.Exact {
+			return strings.ToLower(value) == text
+		}
+		return strings.Contains(strings.ToLower(value), text)
+	}
+
+	_ = walk(&r, func(ptr string, node interface{}) error {
+		value := reflect.ValueOf(node)
+		if value.Kind() != reflect.Ptr || value.Elem().Kind() != reflect.Struct {
+			return nil
+		}
+		kind := lowerFirst(value.Elem().Type().Name())
+		if query.Kind != "" && query.Kind != kind {
+			return nil
+		}
+		searchFields(ptr, value.Elem(), func(field string, pointer string, found string) {
+			if (query.Field == "" || query.Field == field) && match(found) {
+				hits = append(hits, &SearchHit{Pointer: pointer, Kind: kind, Field: field, Value: found})
+			}
+		})
+		return nil
+	})
+
+	sort.SliceStable(hits, func(i, j int) bool {
+		return hits[i].Pointer < hits[j].Pointer
+	})
+	return hits
+}
+
+// Search returns the hits of the query across the loaded documents, ordered
+// by location and pointer.
+func (r *Workspace) Search(query SearchQuery) []*SearchHit {
+	hits := make([]*SearchHit, 0)
+	for _, location := range r.Locations() {
+		doc, _ := r.Document(location)
+		for _, hit := range doc.Search(query) {
+			hit.Location = location
+			hits = append(hits, hit)
+		}
+	}
+	return hits
+}
+
+// searchFields calls fn for the string values, string slice elements and
+// map keys held directly by the struct value. Nested objects are left to
+// the walk reporting them.
+func searchFields(ptr string, value reflect.Value, fn func(field string, pointer string, found string)) {
+	for i := 0; i < value.NumField(); i++ {
+		field := value.Type().Field(i)
+		if field.PkgPath != "" {
+			continue
+		}
+		if field.Anonymous && field.Type.Kind() == reflect.Struct {
+			searchFields(ptr, value.Field(i), fn)
+			continue
+		}
+
+		item := value.Field(i)
+		if field.Type == reflect.TypeOf(Extensions{}) {
+			for _, key := range sortedStrings(item.Interface()) {
+				if s, ok := item.MapIndex(reflect.ValueOf(key)).Interface().(string); ok {
+					fn(key, join(ptr, key), s)
+				}
+			}
+			continue
+		}
+
+		name := strings.Split(field.Tag.Get("json"), ",")[0]
+		if name == "" || name == "-" {
+			continue
+		}
+		if item.Kind() == reflect.Interface && !item.IsNil() {
+			item = item.Elem()
+		}
+		switch item.Kind() {
+		case reflect.String:
+			if item.String() != "" {
+				fn(name, join(ptr, name), item.String())
+			}
+		case reflect.Slice:
+			if item.Type().Elem().Kind() != reflect.String {
+				continue
+			}
+			for j := 0; j < item.Len(); j++ {
+				fn(name, index(ptr, name, j), item.Index(j).String())
+			}
+		case reflect.Map:
+			if item.Type().Key().Kind() != reflect.String {
+				continue
+			}
+			for _, key := range sortedStrings(item.Interface()) {
+				fn(name, join(ptr, name, key), key)
+			}
+		case reflect.Struct:
+			if _, ok := orderedValuerOf(item); ok {
+				for _, key := range sortedKeys(item.Interface()) {
+					fn(name, join(ptr, name, key), key)
+				}
+			}
+		}
+	}
+}
+
+// lowerFirst returns the string with its first letter lowercased.
+func lowerFirst(s string) string {
+	first, size := utf8.DecodeRuneInString(s)
+	return string(unicode.ToLower(first)) + s[size:]
+}
@@ -0,0 +1,112 @@
+package oas
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+)
+
+type SearchSuite struct {
+	suite.Suite
+}
+
+// searchPatch is the patch of the petstore fixture the suite searches.
+const searchPatch = `
+info:
+  description: Not deprecated.
+paths:
+  /pets:
+    get:
+      description: Deprecated, use searchPets.
+      parameters:
+        - name: email
+          in: query
+          description: Owner email.
+      x-owner: team-pets
+    post:
+      description: Creates a pet.
+components:
+  schemas:
+    Owner:
+      type: object
+      required: [email]
+      properties:
+        email:
+          type: string
+          format: email
+        emailBackup:
+          type: string
+`
+
+func (r *SearchSuite) TestSearch() {
+	testCases := []struct {
+		query    SearchQuery
+		expected []*SearchHit
+	}{
+		{
+			SearchQuery{Text: "deprecated", Field: "description", Kind: "operation"},
+			[]*SearchHit{
+				{Pointer: "/paths/~1pets/get/description", Kind: "operation", Field: "description", Value: "Deprecated, use searchPets."},
+			},
+		},
+		{
+			SearchQuery{Text: "email", Exact: true, Field: "properties", Kind: "schema"},
+			[]*SearchHit{
+				{Pointer: "/components/schemas/Owner/properties/email", Kind: "schema", Field: "properties", Value: "email"},
+			},
+		},
+		{
+			SearchQuery{Text: "EMAIL", Kind: "schema"},
+			[]*SearchHit{
+				{Pointer: "/components/schemas/Owner/properties/email", Kind: "schema", Field: "properties", Value: "email"},
+				{Pointer: "/components/schemas/Owner/properties/email/format", Kind: "schema", Field: "format", Value: "email"},
+				{Pointer: "/components/schemas/Owner/properties/emailBackup", Kind: "schema", Field: "properties", Value: "emailBackup"},
+				{Pointer: "/components/schemas/Owner/required/0", Kind: "schema", Field: "required", Value: "email"},
+			},
+		},
+		{
+			SearchQuery{Text: "email", Kind: "parameter"},
+			[]*SearchHit{
+				{Pointer: "/paths/~1pets/get/parameters/0/description", Kind: "parameter", Field: "description", Value: "Owner email."},
+				{Pointer: "/paths/~1pets/get/parameters/0/name", Kind: "parameter", Field: "name", Value: "email"},
+			},
+		},
+		{
+			SearchQuery{Field: "x-owner"},
+			[]*SearchHit{
+				{Pointer: "/paths/~1pets/get/x-owner", Kind: "operation", Field: "x-owner", Value: "team-pets"},
+			},
+		},
+		{
+			SearchQuery{Text: "missing"},
+			[]*SearchHit{},
+		},
+	}
+
+	for i, testCase := range testCases {
+		failMsg := "test case %d failed"
+		assert.Equal(r.T(), testCase.expected, petstore(r.T(), searchPatch).Search(testCase.query), failMsg, i)
+	}
+}
+
+func (r *SearchSuite) TestWorkspaceSearch() {
+	docs := map[string]*OpenAPI{"pets": petstore(r.T(), searchPatch), "owners": petstore(r.T(), searchPatch)}
+	workspace := &Workspace{
+		Read: func(ctx context.Context, location string) ([]byte, error) {
+			return docs[location].MarshalJSON()
+		},
+	}
+	assert.Nil(r.T(), workspace.Load(context.Background(), "pets", "owners"))
+
+	hits := workspace.Search(SearchQuery{Text: "deprecated", Kind: "operation"})
+	assert.Len(r.T(), hits, 2)
+	assert.Equal(r.T(), "owners", hits[0].Location)
+	assert.Equal(r.T(), "pets", hits[1].Location)
+	assert.Equal(r.T(), "/paths/~1pets/get/description", hits[1].Pointer)
+}
+
+func TestSearchSuite(t *testing.T) {
+	suite.Run(t, new(SearchSuite))
+}
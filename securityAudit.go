@@ -0,0 +1,136 @@
+package oas
+
+import (
+	"sort"
+	"strings"
+)
+
+// Rules reported by AuditSecurity.
+const (
+	RuleSecurityMissing     = "security-missing"
+	RuleSecurityDisabled    = "security-disabled"
+	RuleAPIKeyInQuery       = "apikey-in-query"
+	RuleBasicOverHTTP       = "basic-over-http"
+	RuleMissingUnauthorized = "missing-unauthorized-response"
+	RuleMissingForbidden    = "missing-forbidden-response"
+)
+
+// AuditSecurity checks the document against secure-by-default practices. It
+// flags operations without any security requirement or with security
+// disabled through an empty array, API keys passed in the query string, HTTP
+// basic authentication offered on non-HTTPS servers and secured operations
+// which do not document their 401 and 403 responses.
+func (r OpenAPI) AuditSecurity() []*Finding {
+	findings := make([]*Finding, 0)
+
+	schemes := map[string]*SecurityScheme{}
+	if r.Components != nil {
+		schemes = r.Components.SecuritySchemes
+	}
+	for _, name := range sortedKeys(schemes) {
+		scheme := schemes[name]
+		if scheme != nil && scheme.Type == "apiKey" && scheme.In == "query" {
+			findings = append(findings, &Finding{
+				Pointer:  join("/components/securitySchemes", name),
+				Rule:     RuleAPIKeyInQuery,
+				Severity: SeverityWarning,
+				Message:  "API key " + scheme.Name + " is passed in the query string where it ends up in logs and caches",
+			})
+		}
+	}
+
+	for _, op := range r.Paths.operations() {
+		ptr := join("/paths", op.path, op.method)
+
+		requirements := r.Security
+		if op.operation.Security != nil {
+			requirements = op.operation.Security
+		}
+
+		switch {
+		case op.operation.Security != nil && len(op.operation.Security) == 0:
+			findings = append(findings, &Finding{
+				Pointer:  join(ptr, "security"),
+				Rule:     RuleSecurityDisabled,
+				Severity: SeverityWarning,
+				Message:  "security is disabled for the operation through an empty security array",
+			})
+			continue
+		case len(requirements) == 0:
+			findings = append(findings, &Finding{
+				Pointer:  ptr,
+				Rule:     RuleSecurityMissing,
+				Severity: SeverityWarning,
+				Message:  "operation has no security requirement",
+			})
+			continue
+		}
+
+		servers := r.Servers
+		if len(op.item.Servers) > 0 {
+			servers = op.item.Servers
+		}
+		if len(op.operation.Servers) > 0 {
+			servers = op.operation.Servers
+		}
+		for _, name := range requirementSchemes(requirements) {
+			scheme := schemes[name]
+			if scheme == nil || scheme.Type != "http" || !strings.EqualFold(scheme.Scheme, "basic") {
+				continue
+			}
+			for _, server := range servers {
+				if server != nil && strings.HasPrefix(strings.ToLower(server.URL), "http://") {
+					findings = append(findings, &Finding{
+						Pointer:  ptr,
+						Rule:     RuleBasicOverHTTP,
+						Severity: SeverityError,
+						Message:  "basic authentication scheme " + name + " is offered on non-HTTPS server " + server.URL,
+					})
+				}
+			}
+		}
+
+		responses := op.operation.Responses
+		if _, ok := responses.Lookup("4XX"); !ok {
+			if _, ok := responses.Lookup("401"); !ok {
+				findings = append(findings, &Finding{
+					Pointer:  join(ptr, "responses"),
+					Rule:     RuleMissingUnauthorized,
+					Severity: SeverityInfo,
+					Message:  "secured operation does not document a 401 response",
+				})
+			}
+			if _, ok := responses.Lookup("403"); !ok {
+				findings = append(findings, &Finding{
+					Pointer:  join(ptr, "responses"),
+					Rule:     RuleMissingForbidden,
+					Severity: SeverityInfo,
+					Message:  "secured operation does not document a 403 response",
+				})
+			}
+		}
+	}
+
+	sortFindings(findings)
+	return findings
+}
+
+// requirementSchemes returns the sorted names of the security schemes
+// referenced by any of the requirements.
+func requirementSchemes(requirements []*SecurityRequirement) []string {
+	seen := map[string]bool{}
+	names := make([]string, 0)
+	for _, requirement := range requirements {
+		if requirement == nil {
+			continue
+		}
+		for name := range *requirement {
+			if !seen[name] {
+				seen[name] = true
+				names = append(names, name)
+			}
+		}
+	}
+	sort.Strings(names)
+	return names
+}
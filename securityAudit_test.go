@@ -0,0 +1,95 @@
+package oas
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+)
+
+type SecurityAuditSuite struct {
+	suite.Suite
+}
+
+func (r *SecurityAuditSuite) TestAuditSecurity() {
+	doc := OpenAPI{
+		OpenAPI: "3.0.0",
+		Info:    Info{Title: "Petstore", Version: "1.0.0"},
+		Servers: []*Server{{URL: "https://api.example.com"}},
+		Paths: Paths{
+			PathItems: NewPathItems(map[string]*PathItem{
+				"/health": {
+					Get: &Operation{Security: []*SecurityRequirement{}},
+				},
+				"/pets": {
+					Get: &Operation{},
+					Post: &Operation{
+						Security: []*SecurityRequirement{{"basic": {}}},
+						Servers:  []*Server{{URL: "http://legacy.example.com"}},
+						Responses: NewResponses(map[string]*Response{
+							"401": {Description: "Unauthorized."},
+							"403": {Description: "Forbidden."},
+						}),
+					},
+				},
+				"/owners": {
+					Get: &Operation{
+						Security: []*SecurityRequirement{{"key": {}}},
+						Responses: NewResponses(map[string]*Response{
+							"4XX": {Description: "Client error."},
+						}),
+					},
+					Put: &Operation{
+						Security: []*SecurityRequirement{{"key": {}}},
+						Responses: NewResponses(map[string]*Response{
+							"401": {Description: "Unauthorized."},
+						}),
+					},
+				},
+			}),
+		},
+		Components: &Components{
+			SecuritySchemes: map[string]*SecurityScheme{
+				"basic": {Type: "http", Scheme: "basic"},
+				"key":   {Type: "apiKey", Name: "api_key", In: "query"},
+			},
+		},
+	}
+
+	assert.Equal(r.T(), []*Finding{
+		{
+			Pointer:  "/components/securitySchemes/key",
+			Rule:     RuleAPIKeyInQuery,
+			Severity: SeverityWarning,
+			Message:  "API key api_key is passed in the query string where it ends up in logs and caches",
+		},
+		{
+			Pointer:  "/paths/~1health/get/security",
+			Rule:     RuleSecurityDisabled,
+			Severity: SeverityWarning,
+			Message:  "security is disabled for the operation through an empty security array",
+		},
+		{
+			Pointer:  "/paths/~1owners/put/responses",
+			Rule:     RuleMissingForbidden,
+			Severity: SeverityInfo,
+			Message:  "secured operation does not document a 403 response",
+		},
+		{
+			Pointer:  "/paths/~1pets/get",
+			Rule:     RuleSecurityMissing,
+			Severity: SeverityWarning,
+			Message:  "operation has no security requirement",
+		},
+		{
+			Pointer:  "/paths/~1pets/post",
+			Rule:     RuleBasicOverHTTP,
+			Severity: SeverityError,
+			Message:  "basic authentication scheme basic is offered on non-HTTPS server http://legacy.example.com",
+		},
+	}, doc.AuditSecurity())
+}
+
+func TestSecurityAuditSuite(t *testing.T) {
+	suite.Run(t, new(SecurityAuditSuite))
+}
@@ -2,7 +2,7 @@ package oas
 
 import (
 	"github.com/pkg/errors"
-	"gopkg.in/yaml.v2"
+	"gopkg.in/yaml.v3"
 )
 
 // SecurityRequirement lists the required security schemes to execute this
@@ -0,0 +1,123 @@
+package oas
+
+import (
+	"context"
+)
+
+// Rules of the violations reported by ValidateSemantics.
+const (
+	RuleOperationIDDuplicate = "operation-id-duplicate"
+	RulePathParamUndeclared  = "path-param-undeclared"
+	RulePathParamOptional    = "path-param-optional"
+	RulePathParamUnused      = "path-param-unused"
+	RuleParameterDuplicate   = "parameter-duplicate"
+)
+
+// SemanticOptions describes the behavior of ValidateSemantics.
+type SemanticOptions struct {
+	// Disabled describes the rules which are not checked (e.g.
+	// RulePathParamUnused while paths are being migrated).
+	Disabled []string
+}
+
+// ValidateSemantics checks the consistency of the operations of the
+// document, beyond its structure: operationIds are unique document-wide,
+// every parameter of a path template is declared as a required path
+// parameter and every path parameter appears in the template, and no
+// parameter is declared twice with the same name and location in a path
+// item or an operation. Parameters are resolved through their references
+// and those of an operation are checked together with those of its path
+// item. Every violation of the enabled rules is returned as a
+// *ValidationError.
+func (r *OpenAPI) ValidateSemantics(ctx context.Context, opts SemanticOptions) error {
+	v := &validator{doc: r}
+	disabled := make(map[string]bool, len(opts.Disabled))
+	for _, rule := range opts.Disabled {
+		disabled[rule] = true
+	}
+
+	operationIDs := map[string]string{}
+	for _, op := range r.Paths.operations() {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		ptr := join("/paths", op.path, op.method)
+		v.pathParams(ptr, op)
+		v.duplicateParams(join("/paths", op.path), op.item.Parameters)
+		v.duplicateParams(ptr, op.operation.Parameters)
+		if op.operation.OperationID == "" {
+			continue
+		}
+		if first, ok := operationIDs[op.operation.OperationID]; ok {
+			v.report(join(ptr, "operationId"), RuleOperationIDDuplicate,
+				"operationId %q is already used by %s", op.operation.OperationID, first)
+			continue
+		}
+		operationIDs[op.operation.OperationID] = ptr
+	}
+
+	violations := make([]*Finding, 0, len(v.violations))
+	seen := map[string]bool{}
+	for _, violation := range v.violations {
+		key := violation.Pointer + " " + violation.Rule + " " + violation.Message
+		if disabled[violation.Rule] || seen[key] {
+			continue
+		}
+		seen[key] = true
+		violations = append(violations, violation)
+	}
+	if len(violations) == 0 {
+		return nil
+	}
+	sortFindings(violations)
+	return &ValidationError{Violations: violations}
+}
+
+// pathParams checks that the parameters of the path template of the
+// operation are declared as required path parameters, and that every path
+// parameter appears in the template. Parameters of the operation override
+// those of its path item.
+func (v *validator) pathParams(ptr string, op pathOperation) {
+	declared := map[string]*Parameter{}
+	for _, params := range [][]*Parameter{op.item.Parameters, op.operation.Parameters} {
+		for _, param := range params {
+			if _, param = NewResolver(v.doc).parameter("", param); param != nil && param.In == "path" {
+				declared[param.Name] = param
+			}
+		}
+	}
+
+	used := map[string]bool{}
+	for _, match := range templateParam.FindAllStringSubmatch(op.path, -1) {
+		name := match[1]
+		used[name] = true
+		switch param, ok := declared[name]; {
+		case !ok:
+			v.report(ptr, RulePathParamUndeclared, "path parameter %q is not declared", name)
+		case !param.Required:
+			v.report(ptr, RulePathParamOptional, "path parameter %q must be required", name)
+		}
+	}
+	for _, name := range sortedStrings(declared) {
+		if !used[name] {
+			v.report(ptr, RulePathParamUnused, "path parameter %q is not in the path %s", name, op.path)
+		}
+	}
+}
+
+// duplicateParams checks that the parameters of the list at the pointer
+// have distinct names and locations.
+func (v *validator) duplicateParams(ptr string, params []*Parameter) {
+	seen := map[string]bool{}
+	for i, param := range params {
+		if _, param = NewResolver(v.doc).parameter("", param); param == nil {
+			continue
+		}
+		key := param.In + " " + param.Name
+		if seen[key] {
+			v.report(index(ptr, "parameters", i), RuleParameterDuplicate,
+				"%s parameter %q is already declared", param.In, param.Name)
+		}
+		seen[key] = true
+	}
+}
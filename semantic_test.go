@@ -0,0 +1,168 @@
+package oas
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+)
+
+type SemanticSuite struct {
+	suite.Suite
+}
+
+// semanticPatch is the patch of the petstore fixture the suite validates,
+// declaring the pet identifier as a shared parameter.
+const semanticPatch = `
+paths:
+  /pets/{petId}:
+    parameters:
+      - $ref: "#/components/parameters/PetID"
+    get:
+      parameters:
+        - name: fields
+          in: query
+          schema:
+            type: string
+components:
+  parameters:
+    PetID:
+      name: petId
+      in: path
+      required: true
+      schema:
+        type: string
+`
+
+func (r *SemanticSuite) TestValidateSemantics() {
+	testCases := []struct {
+		patch    string
+		opts     SemanticOptions
+		expected []string
+	}{
+		{
+			"",
+			SemanticOptions{},
+			nil,
+		},
+		{
+			`
+paths:
+  /pets:
+    get:
+      operationId: getPet
+    post:
+      operationId: getPet
+`,
+			SemanticOptions{},
+			[]string{
+				"/paths/~1pets/post/operationId " + RuleOperationIDDuplicate,
+				"/paths/~1pets~1{petId}/get/operationId " + RuleOperationIDDuplicate,
+			},
+		},
+		{
+			`
+paths:
+  /pets:
+    get:
+      parameters:
+        - name: petId
+          in: path
+          schema:
+            type: string
+  /pets/{petId}:
+    parameters: null
+    delete: null
+  /pets/{petId}/photos/{photoId}:
+    get:
+      parameters:
+        - $ref: "#/components/parameters/PetID"
+        - name: photoId
+          in: path
+          schema:
+            type: string
+`,
+			SemanticOptions{},
+			[]string{
+				"/paths/~1pets/get " + RulePathParamUnused,
+				"/paths/~1pets~1{petId}/get " + RulePathParamUndeclared,
+				"/paths/~1pets~1{petId}~1photos~1{photoId}/get " + RulePathParamOptional,
+			},
+		},
+		{
+			`
+paths:
+  /pets/{petId}:
+    parameters:
+      - $ref: "#/components/parameters/PetID"
+      - $ref: "#/components/parameters/PetID"
+    get:
+      parameters:
+        - name: fields
+          in: query
+          schema:
+            type: string
+        - name: fields
+          in: query
+          schema:
+            type: string
+        - name: fields
+          in: header
+          schema:
+            type: string
+`,
+			SemanticOptions{},
+			[]string{
+				"/paths/~1pets~1{petId}/get/parameters/1 " + RuleParameterDuplicate,
+				"/paths/~1pets~1{petId}/parameters/1 " + RuleParameterDuplicate,
+			},
+		},
+		{
+			`
+paths:
+  /pets:
+    get:
+      operationId: getPet
+      parameters:
+        - name: petId
+          in: path
+          required: true
+          schema:
+            type: string
+`,
+			SemanticOptions{Disabled: []string{RulePathParamUnused}},
+			[]string{
+				"/paths/~1pets~1{petId}/get/operationId " + RuleOperationIDDuplicate,
+			},
+		},
+	}
+
+	for i, testCase := range testCases {
+		failMsg := "test case %d failed"
+		err := petstore(r.T(), semanticPatch, testCase.patch).ValidateSemantics(context.Background(), testCase.opts)
+		if testCase.expected == nil {
+			assert.Nil(r.T(), err, failMsg, i)
+			continue
+		}
+		if !assert.IsType(r.T(), &ValidationError{}, err, failMsg, i) {
+			continue
+		}
+		actual := make([]string, 0)
+		for _, violation := range err.(*ValidationError).Violations {
+			actual = append(actual, violation.Pointer+" "+violation.Rule)
+		}
+		assert.Equal(r.T(), testCase.expected, actual, failMsg, i)
+	}
+}
+
+func (r *SemanticSuite) TestValidateSemanticsCanceled() {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	err := petstore(r.T(), semanticPatch).ValidateSemantics(ctx, SemanticOptions{})
+	assert.Equal(r.T(), context.Canceled, err)
+}
+
+func TestSemanticSuite(t *testing.T) {
+	suite.Run(t, new(SemanticSuite))
+}
@@ -0,0 +1,208 @@
+package oas
+
+import (
+	"reflect"
+	"strconv"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// Session edits a new version of a document while sharing every unchanged
+// object with the version it started from. Objects are copied on write: the
+// objects along the pointer passed to Mutable are shallow copied once per
+// version, everything else keeps pointing into the previous version. This
+// keeps memory proportional to the changes when many versions of a large
+// document are held, e.g. for diffing.
+//
+// Shared objects must be treated as read-only. Only objects returned by
+// Mutable may be modified, and only their own fields and the maps and slices
+// they hold directly; nested objects have to be obtained through Mutable as
+// well.
+type Session struct {
+	doc   *OpenAPI
+	owned map[uintptr]bool
+}
+
+// Edit returns a session editing a new version of the document. The document
+// itself is never modified by the session.
+func (r *OpenAPI) Edit() *Session {
+	return &Session{doc: r, owned: map[uintptr]bool{}}
+}
+
+// Mutable returns a pointer to the object addressed by the JSON pointer in the
+// edited version (e.g. *Schema for "/components/schemas/Pet" or *Operation
+// for "/paths/~1pets/get"), copying it and its ancestors unless they were
+// copied before in this version.
+func (r *Session) Mutable(ptr string) (interface{}, error) {
+	holder := reflect.New(reflect.TypeOf(r.doc)).Elem()
+	holder.Set(reflect.ValueOf(r.doc))
+	defer func() {
+		r.doc = holder.Interface().(*OpenAPI)
+	}()
+
+	current := holder
+	for _, token := range splitPointer(ptr) {
+		value, err := r.deref(current)
+		if err != nil {
+			return nil, errors.Wrapf(err, "cannot resolve %q", ptr)
+		}
+		current, err = r.child(value, token)
+		if err != nil {
+			return nil, errors.Wrapf(err, "cannot resolve %q", ptr)
+		}
+	}
+	value, err := r.deref(current)
+	if err != nil {
+		return nil, errors.Wrapf(err, "cannot resolve %q", ptr)
+	}
+	return value.Addr().Interface(), nil
+}
+
+// Commit returns the edited version. Later calls to Mutable start a new
+// version sharing its objects with the returned one, which stays unchanged.
+func (r *Session) Commit() *OpenAPI {
+	r.owned = map[uintptr]bool{}
+	return r.doc
+}
+
+// deref returns the addressable value the pointer value points to, copying it
+// first unless it is owned by the edited version.
+func (r *Session) deref(value reflect.Value) (reflect.Value, error) {
+	if value.Kind() != reflect.Ptr {
+		return value, nil
+	}
+	if value.IsNil() {
+		return reflect.Value{}, errors.New("nil object")
+	}
+	owned := r.own(value)
+	if value.CanSet() {
+		value.Set(owned)
+	}
+	return owned.Elem(), nil
+}
+
+// own returns a copy of the object the pointer value points to, holding
+// copies of the maps and slices of the object, or the object itself if it was
+// copied before in this version.
+func (r *Session) own(value reflect.Value) reflect.Value {
+	if r.owned[value.Pointer()] {
+		return value
+	}
+	owned := reflect.New(value.Type().Elem())
+	owned.Elem().Set(value.Elem())
+	copyContainers(owned.Elem())
+	r.owned[owned.Pointer()] = true
+	return owned
+}
+
+// child returns the value addressed by the token within the value, which
+// must be owned by the edited version.
+func (r *Session) child(value reflect.Value, token string) (reflect.Value, error) {
+	switch value.Kind() {
+	case reflect.Struct:
+		if ordered, ok := orderedValuerOf(value); ok {
+			item, ok := ordered.orderedValues().get(token)
+			if !ok || reflect.ValueOf(item).Kind() != reflect.Ptr || reflect.ValueOf(item).IsNil() {
+				break
+			}
+			owned := r.own(reflect.ValueOf(item))
+			ordered.orderedValues().set(token, owned.Interface())
+			return owned, nil
+		}
+		if field, ok := fieldByTag(value, token); ok {
+			return field, nil
+		}
+		if items, ok := itemsField(value); ok {
+			return r.child(items, token)
+		}
+	case reflect.Map:
+		if value.Type().Key().Kind() != reflect.String {
+			break
+		}
+		key := reflect.ValueOf(token).Convert(value.Type().Key())
+		item := value.MapIndex(key)
+		if !item.IsValid() || item.Kind() != reflect.Ptr {
+			break
+		}
+		owned := r.own(item)
+		value.SetMapIndex(key, owned)
+		return owned, nil
+	case reflect.Slice:
+		i, err := strconv.Atoi(token)
+		if err != nil || i < 0 || i >= value.Len() {
+			break
+		}
+		return value.Index(i), nil
+	}
+	return reflect.Value{}, errors.Errorf("unknown token %q", token)
+}
+
+// fieldByTag returns the field of the struct value, or of its embedded
+// structs, whose json name matches the token.
+func fieldByTag(value reflect.Value, token string) (reflect.Value, bool) {
+	for i := 0; i < value.NumField(); i++ {
+		field := value.Type().Field(i)
+		if field.PkgPath != "" {
+			continue
+		}
+		if field.Anonymous && field.Type.Kind() == reflect.Struct {
+			if found, ok := fieldByTag(value.Field(i), token); ok {
+				return found, true
+			}
+			continue
+		}
+		if name := strings.Split(field.Tag.Get("json"), ",")[0]; name == token {
+			return value.Field(i), true
+		}
+	}
+	return reflect.Value{}, false
+}
+
+// itemsField returns the inlined map of the struct value holding its items,
+// such as the path items of Paths and Callback.
+func itemsField(value reflect.Value) (reflect.Value, bool) {
+	for i := 0; i < value.NumField(); i++ {
+		field := value.Type().Field(i)
+		if field.Tag.Get("json") != "-" || field.Type == reflect.TypeOf(Extensions{}) {
+			continue
+		}
+		if field.Type.Kind() == reflect.Map || reflect.PtrTo(field.Type).Implements(orderedValuerType) {
+			return value.Field(i), true
+		}
+	}
+	return reflect.Value{}, false
+}
+
+// copyContainers replaces the maps and slices held by the addressable value,
+// including those of nested struct values, with shallow copies.
+func copyContainers(value reflect.Value) {
+	switch value.Kind() {
+	case reflect.Map:
+		if value.IsNil() {
+			return
+		}
+		copied := reflect.MakeMapWithSize(value.Type(), value.Len())
+		for _, key := range value.MapKeys() {
+			copied.SetMapIndex(key, value.MapIndex(key))
+		}
+		value.Set(copied)
+	case reflect.Slice:
+		if value.IsNil() {
+			return
+		}
+		copied := reflect.MakeSlice(value.Type(), value.Len(), value.Len())
+		reflect.Copy(copied, value)
+		value.Set(copied)
+	case reflect.Struct:
+		if ordered, ok := orderedValuerOf(value); ok {
+			ordered.setOrderedValues(ordered.orderedValues().copy())
+			return
+		}
+		for i := 0; i < value.NumField(); i++ {
+			if value.Field(i).CanSet() {
+				copyContainers(value.Field(i))
+			}
+		}
+	}
+}
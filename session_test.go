@@ -0,0 +1,99 @@
+package oas
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+)
+
+type SessionSuite struct {
+	suite.Suite
+}
+
+// sessionPatch is the patch of the petstore fixture the suite edits.
+const sessionPatch = `
+paths:
+  /pets:
+    get:
+      parameters:
+        - name: limit
+          in: query
+  /owners:
+    get:
+      operationId: listOwners
+components:
+  schemas:
+    Tag:
+      type: string
+`
+
+func (r *SessionSuite) TestMutable() {
+	base := petstore(r.T(), sessionPatch)
+	session := base.Edit()
+
+	node, err := session.Mutable("/components/schemas/Pet")
+	assert.Nil(r.T(), err)
+	node.(*Schema).Description = "A pet."
+	node.(*Schema).Properties = NewProperties(map[string]*Schema{"name": {Type: "string"}})
+
+	node, err = session.Mutable("/paths/~1pets/get/parameters/0")
+	assert.Nil(r.T(), err)
+	node.(*Parameter).Required = true
+
+	node, err = session.Mutable("/components/schemas")
+	assert.Nil(r.T(), err)
+	(*node.(*map[string]*Schema))["Owner"] = &Schema{Type: "object"}
+
+	node, err = session.Mutable("/info")
+	assert.Nil(r.T(), err)
+	node.(*Info).Version = "1.1.0"
+
+	next := session.Commit()
+	assert.Equal(r.T(), petstore(r.T(), sessionPatch), base)
+	assert.Equal(r.T(), "A pet.", next.Components.Schemas["Pet"].Description)
+	assert.True(r.T(), next.Paths.PathItems.Get("/pets").Get.Parameters[0].Required)
+	assert.Contains(r.T(), next.Components.Schemas, "Owner")
+	assert.Equal(r.T(), "1.1.0", next.Info.Version)
+
+	assert.True(r.T(), base.Components.Schemas["Tag"] == next.Components.Schemas["Tag"])
+	assert.True(r.T(), base.Paths.PathItems.Get("/owners") == next.Paths.PathItems.Get("/owners"))
+	assert.False(r.T(), base.Paths.PathItems.Get("/pets") == next.Paths.PathItems.Get("/pets"))
+}
+
+func (r *SessionSuite) TestCopyOnce() {
+	session := petstore(r.T(), sessionPatch).Edit()
+	first, err := session.Mutable("/components/schemas/Pet")
+	assert.Nil(r.T(), err)
+	second, err := session.Mutable("/components/schemas/Pet")
+	assert.Nil(r.T(), err)
+	assert.True(r.T(), first == second)
+
+	version := session.Commit()
+	third, err := session.Mutable("/components/schemas/Pet")
+	assert.Nil(r.T(), err)
+	third.(*Schema).Description = "Changed."
+	assert.True(r.T(), first == version.Components.Schemas["Pet"])
+	assert.Empty(r.T(), version.Components.Schemas["Pet"].Description)
+	assert.Equal(r.T(), "Changed.", session.Commit().Components.Schemas["Pet"].Description)
+}
+
+func (r *SessionSuite) TestUnknownPointer() {
+	testCases := []string{
+		"/components/schemas/Missing",
+		"/paths/~1missing",
+		"/paths/~1pets/get/parameters/1",
+		"/unknown",
+		"/externalDocs",
+	}
+
+	for i, ptr := range testCases {
+		failMsg := "test case %d failed"
+		_, err := petstore(r.T(), sessionPatch).Edit().Mutable(ptr)
+		assert.NotNil(r.T(), err, failMsg, i)
+	}
+}
+
+func TestSessionSuite(t *testing.T) {
+	suite.Run(t, new(SessionSuite))
+}
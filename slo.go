@@ -0,0 +1,307 @@
+package oas
+
+import (
+	"fmt"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// SLOExtension names the specification extension declaring the service level
+// objectives of an operation.
+const SLOExtension = "x-slo"
+
+// SLO describes the service level objectives of an operation.
+type SLO struct {
+	// Latency describes the maximum response time as a duration (e.g.
+	// "250ms").
+	Latency string `json:"latency,omitempty" yaml:"latency,omitempty"`
+
+	// Percentile describes the percentile of requests the latency objective
+	// applies to (e.g. 99). Defaults to all requests when zero.
+	Percentile float64 `json:"percentile,omitempty" yaml:"percentile,omitempty"`
+
+	// Availability describes the targeted percentage of successful requests
+	// (e.g. 99.9).
+	Availability float64 `json:"availability,omitempty" yaml:"availability,omitempty"`
+}
+
+// LatencyDuration returns the parsed latency objective, zero when unset.
+func (r SLO) LatencyDuration() (time.Duration, error) {
+	if r.Latency == "" {
+		return 0, nil
+	}
+	latency, err := time.ParseDuration(r.Latency)
+	if err != nil {
+		return 0, errors.WithStack(err)
+	}
+	if latency <= 0 {
+		return 0, errors.Errorf("latency %q must be positive", r.Latency)
+	}
+	return latency, nil
+}
+
+// validate checks that the objectives are well formed.
+func (r SLO) validate() error {
+	if _, err := r.LatencyDuration(); err != nil {
+		return err
+	}
+	if r.Percentile < 0 || r.Percentile > 100 {
+		return errors.Errorf("percentile %v must be between 0 and 100", r.Percentile)
+	}
+	if r.Availability < 0 || r.Availability > 100 {
+		return errors.Errorf("availability %v must be between 0 and 100", r.Availability)
+	}
+	return nil
+}
+
+// SLO returns the objectives declared by the x-slo extension, or nil if the
+// extension is absent.
+func (r Extensions) SLO() (*SLO, error) {
+	slo := &SLO{}
+	ok, err := r.decode(SLOExtension, slo)
+	if !ok || err != nil {
+		return nil, err
+	}
+	if err := slo.validate(); err != nil {
+		return nil, errors.Wrap(err, SLOExtension)
+	}
+	return slo, nil
+}
+
+// SetSLO declares the objectives with the x-slo extension, or removes the
+// extension when nil.
+func (r *Extensions) SetSLO(slo *SLO) {
+	if slo == nil {
+		delete(*r, SLOExtension)
+		return
+	}
+	value := map[string]interface{}{}
+	if slo.Latency != "" {
+		value["latency"] = slo.Latency
+	}
+	if slo.Percentile != 0 {
+		value["percentile"] = slo.Percentile
+	}
+	if slo.Availability != 0 {
+		value["availability"] = slo.Availability
+	}
+	setExtension(r, SLOExtension, value)
+}
+
+// SyntheticCheckOptions describes the behavior of SyntheticChecks.
+type SyntheticCheckOptions struct {
+	// BaseURL describes the URL the paths are appended to. Defaults to the
+	// URL of the first server of the document, with its variables replaced
+	// by their default values.
+	BaseURL string
+
+	// Unsafe includes operations with methods other than GET and HEAD, which
+	// are skipped by default as probing them may change state.
+	Unsafe bool
+}
+
+// SyntheticCheck describes an HTTP check periodically probing an operation,
+// in a form close to the configuration of synthetic monitoring services.
+type SyntheticCheck struct {
+	// Name describes the operationId of the operation, or its method and path
+	// template.
+	Name string `json:"name" yaml:"name"`
+
+	// Method describes the uppercase HTTP method of the request.
+	Method string `json:"method" yaml:"method"`
+
+	// URL describes the URL of the request.
+	URL string `json:"url" yaml:"url"`
+
+	// Headers describes the header parameters of the request.
+	Headers map[string]string `json:"headers,omitempty" yaml:"headers,omitempty"`
+
+	// SLO describes the objectives the check monitors.
+	SLO *SLO `json:"slo" yaml:"slo"`
+
+	// Assertions describes the conditions the response must meet.
+	Assertions []*CheckAssertion `json:"assertions" yaml:"assertions"`
+}
+
+// Assertion types of synthetic checks.
+const (
+	AssertStatusCode   = "statusCode"
+	AssertResponseTime = "responseTime"
+	AssertHeader       = "header"
+	AssertBody         = "body"
+)
+
+// CheckAssertion describes a condition the response of a synthetic check
+// must meet.
+type CheckAssertion struct {
+	// Type describes what the assertion checks, one of AssertStatusCode,
+	// AssertResponseTime in milliseconds, AssertHeader or AssertBody.
+	Type string `json:"type" yaml:"type"`
+
+	// Property describes the name of the checked header.
+	Property string `json:"property,omitempty" yaml:"property,omitempty"`
+
+	// Operator describes the comparison ("is", "lessThan", "contains" or
+	// "validatesJSONSchema").
+	Operator string `json:"operator" yaml:"operator"`
+
+	// Target describes the expected value.
+	Target string `json:"target,omitempty" yaml:"target,omitempty"`
+
+	// Schema describes the schema the body must validate against.
+	Schema *Schema `json:"schema,omitempty" yaml:"schema,omitempty"`
+}
+
+// SyntheticChecks returns a check for every operation declaring an x-slo
+// extension, ordered by path and method. Each check asserts the lowest
+// successful status code the operation declares, a response time within
+// the latency objective and, for JSON responses, the content type and the
+// response schema. Required parameters are filled from their examples,
+// defaults or enumerations; an operation whose required parameter has none
+// of them yields an error.
+func (r OpenAPI) SyntheticChecks(opts SyntheticCheckOptions) ([]*SyntheticCheck, error) {
+	base := opts.BaseURL
+	if base == "" && len(r.Servers) > 0 && r.Servers[0] != nil {
+		base = r.Servers[0].URL
+		for name, variable := range r.Servers[0].Variables {
+			if variable != nil {
+				base = strings.Replace(base, "{"+name+"}", variable.Default, -1)
+			}
+		}
+	}
+	base = strings.TrimSuffix(base, "/")
+
+	checks := make([]*SyntheticCheck, 0)
+	for _, op := range r.Paths.operations() {
+		ptr := join("/paths", op.path, op.method)
+		slo, err := op.operation.Extensions.SLO()
+		if err != nil {
+			return nil, errors.Wrap(err, ptr)
+		}
+		if slo == nil || (!opts.Unsafe && op.method != "get" && op.method != "head") {
+			continue
+		}
+
+		check, err := r.syntheticCheck(base, op, slo)
+		if err != nil {
+			return nil, errors.Wrap(err, ptr)
+		}
+		checks = append(checks, check)
+	}
+	return checks, nil
+}
+
+// syntheticCheck builds the check of a single operation.
+func (r OpenAPI) syntheticCheck(base string, op pathOperation, slo *SLO) (*SyntheticCheck, error) {
+	name := op.operation.OperationID
+	if name == "" {
+		name = strings.ToUpper(op.method) + " " + op.path
+	}
+	check := &SyntheticCheck{
+		Name:       name,
+		Method:     strings.ToUpper(op.method),
+		SLO:        slo,
+		Assertions: make([]*CheckAssertion, 0),
+	}
+
+	path := op.path
+	query := url.Values{}
+	for _, parameter := range append(append([]*Parameter{}, op.item.Parameters...), op.operation.Parameters...) {
+		_, parameter = NewResolver(&r).parameter("", parameter)
+		if parameter == nil || !parameter.Required {
+			continue
+		}
+		value, ok := parameterExample(parameter)
+		if !ok {
+			return nil, errors.Errorf("no example value for required %s parameter %q", parameter.In, parameter.Name)
+		}
+		switch parameter.In {
+		case "path":
+			path = strings.Replace(path, "{"+parameter.Name+"}", url.PathEscape(value), -1)
+		case "query":
+			query.Set(parameter.Name, value)
+		case "header":
+			if check.Headers == nil {
+				check.Headers = map[string]string{}
+			}
+			check.Headers[parameter.Name] = value
+		}
+	}
+	check.URL = base + path
+	if len(query) > 0 {
+		check.URL += "?" + query.Encode()
+	}
+
+	codes := make([]string, 0)
+	for _, code := range op.operation.Responses.Keys() {
+		if len(code) == 3 && code[0] == '2' {
+			codes = append(codes, code)
+		}
+	}
+	if len(codes) == 0 {
+		return nil, errors.New("no successful response declared")
+	}
+	sort.Strings(codes)
+	check.Assertions = append(check.Assertions, &CheckAssertion{Type: AssertStatusCode, Operator: "is", Target: codes[0]})
+
+	if latency, _ := slo.LatencyDuration(); latency > 0 {
+		check.Assertions = append(check.Assertions, &CheckAssertion{
+			Type:     AssertResponseTime,
+			Operator: "lessThan",
+			Target:   strconv.FormatInt(int64(latency/time.Millisecond), 10),
+		})
+	}
+
+	_, response := NewResolver(&r).response("", op.operation.Responses.Get(codes[0]))
+	if response != nil {
+		for _, mediaType := range sortedKeys(response.Content) {
+			if !isJSONMediaType(mediaType) {
+				continue
+			}
+			check.Assertions = append(check.Assertions, &CheckAssertion{
+				Type:     AssertHeader,
+				Property: "content-type",
+				Operator: "contains",
+				Target:   mediaType,
+			})
+			if schema := NewResolver(&r).schema(response.Content[mediaType].Schema); schema != nil {
+				check.Assertions = append(check.Assertions, &CheckAssertion{
+					Type:     AssertBody,
+					Operator: "validatesJSONSchema",
+					Schema:   schema,
+				})
+			}
+			break
+		}
+	}
+	return check, nil
+}
+
+// parameterExample returns a value of the parameter taken from its example,
+// its first named example, the example, default or first enumerated value
+// of its schema.
+func parameterExample(parameter *Parameter) (string, bool) {
+	candidates := []interface{}{parameter.Example}
+	for _, key := range sortedKeys(parameter.Examples) {
+		if example := parameter.Examples[key]; example != nil {
+			candidates = append(candidates, example.Value)
+		}
+	}
+	if schema := parameter.Schema; schema != nil {
+		candidates = append(candidates, schema.Example, schema.Default)
+		if len(schema.Enum) > 0 {
+			candidates = append(candidates, schema.Enum[0])
+		}
+	}
+	for _, candidate := range candidates {
+		if candidate != nil {
+			return fmt.Sprint(candidate), true
+		}
+	}
+	return "", false
+}
@@ -0,0 +1,140 @@
+package oas
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+)
+
+type SLOSuite struct {
+	suite.Suite
+}
+
+func (r *SLOSuite) TestAccessors() {
+	testCases := []struct {
+		shouldFail bool
+		exts       Extensions
+		expected   *SLO
+	}{
+		{
+			false,
+			Extensions{},
+			nil,
+		},
+		{
+			false,
+			Extensions{SLOExtension: map[string]interface{}{"latency": "250ms", "percentile": 99, "availability": 99.9}},
+			&SLO{Latency: "250ms", Percentile: 99, Availability: 99.9},
+		},
+		{
+			true,
+			Extensions{SLOExtension: map[string]interface{}{"latency": "fast"}},
+			nil,
+		},
+		{
+			true,
+			Extensions{SLOExtension: map[string]interface{}{"availability": 120}},
+			nil,
+		},
+	}
+
+	for i, testCase := range testCases {
+		failMsg := "test case %d failed"
+		slo, err := testCase.exts.SLO()
+		if testCase.shouldFail {
+			assert.NotNil(r.T(), err, failMsg, i)
+			continue
+		}
+		assert.Nil(r.T(), err, failMsg, i)
+		assert.Equal(r.T(), testCase.expected, slo, failMsg, i)
+	}
+
+	exts := Extensions{}
+	exts.SetSLO(&SLO{Latency: "1s", Availability: 99.5})
+	slo, err := exts.SLO()
+	assert.Nil(r.T(), err)
+	assert.Equal(r.T(), &SLO{Latency: "1s", Availability: 99.5}, slo)
+	exts.SetSLO(nil)
+	assert.Empty(r.T(), exts)
+}
+
+func (r *SLOSuite) TestSyntheticChecks() {
+	slo := Extensions{SLOExtension: map[string]interface{}{"latency": "300ms", "availability": 99.9}}
+	doc := &OpenAPI{
+		OpenAPI: "3.0.0",
+		Info:    Info{Title: "Petstore", Version: "1.0.0"},
+		Servers: []*Server{
+			{URL: "https://{env}.example.com/v1/", Variables: map[string]*ServerVariable{"env": {Default: "api"}}},
+		},
+		Paths: Paths{
+			PathItems: NewPathItems(map[string]*PathItem{
+				"/pets/{petId}": {
+					Parameters: []*Parameter{
+						{Name: "petId", In: "path", Header: Header{Required: true, Example: 7}},
+					},
+					Get: &Operation{
+						OperationID: "showPet",
+						Parameters: []*Parameter{
+							{Name: "fields", In: "query", Header: Header{Required: true, Schema: &Schema{Default: "name"}}},
+							{Name: "X-Tenant", In: "header", Header: Header{Required: true, Schema: &Schema{Enum: []interface{}{"acme"}}}},
+							{Name: "verbose", In: "query"},
+						},
+						Responses: NewResponses(map[string]*Response{
+							"200": {
+								Description: "A pet.",
+								Content: map[string]*MediaType{
+									"application/json": {Schema: &Schema{Ref: "#/components/schemas/Pet"}},
+								},
+							},
+							"404": {Description: "Not found."},
+						}),
+						Extensions: slo,
+					},
+					Delete: &Operation{
+						Responses:  NewResponses(map[string]*Response{"204": {Description: "Deleted."}}),
+						Extensions: slo,
+					},
+				},
+				"/pets": {
+					Get: &Operation{Responses: NewResponses(map[string]*Response{"200": {Description: "Pets."}})},
+				},
+			}),
+		},
+		Components: &Components{
+			Schemas: map[string]*Schema{"Pet": {Type: "object"}},
+		},
+	}
+
+	checks, err := doc.SyntheticChecks(SyntheticCheckOptions{})
+	assert.Nil(r.T(), err)
+	assert.Equal(r.T(), []*SyntheticCheck{
+		{
+			Name:    "showPet",
+			Method:  "GET",
+			URL:     "https://api.example.com/v1/pets/7?fields=name",
+			Headers: map[string]string{"X-Tenant": "acme"},
+			SLO:     &SLO{Latency: "300ms", Availability: 99.9},
+			Assertions: []*CheckAssertion{
+				{Type: AssertStatusCode, Operator: "is", Target: "200"},
+				{Type: AssertResponseTime, Operator: "lessThan", Target: "300"},
+				{Type: AssertHeader, Property: "content-type", Operator: "contains", Target: "application/json"},
+				{Type: AssertBody, Operator: "validatesJSONSchema", Schema: &Schema{Type: "object"}},
+			},
+		},
+	}, checks)
+
+	checks, err = doc.SyntheticChecks(SyntheticCheckOptions{BaseURL: "http://localhost:8080", Unsafe: true})
+	assert.Nil(r.T(), err)
+	assert.Len(r.T(), checks, 2)
+	assert.Equal(r.T(), "DELETE /pets/{petId}", checks[1].Name)
+	assert.Equal(r.T(), "http://localhost:8080/pets/7", checks[1].URL)
+
+	doc.Paths.PathItems.Get("/pets/{petId}").Parameters[0].Example = nil
+	_, err = doc.SyntheticChecks(SyntheticCheckOptions{})
+	assert.NotNil(r.T(), err)
+}
+
+func TestSLOSuite(t *testing.T) {
+	suite.Run(t, new(SLOSuite))
+}
@@ -0,0 +1,192 @@
+package oas
+
+import (
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// SQLDialect describes the SQL dialect DDL statements are written in.
+type SQLDialect string
+
+// Dialects supported by WriteSQL.
+const (
+	PostgreSQL SQLDialect = "postgresql"
+	MySQL      SQLDialect = "mysql"
+	SQLite     SQLDialect = "sqlite"
+)
+
+// sqlTypes maps "type" and "type/format" keys onto the column types of each
+// dialect. Strings with a maxLength are mapped to VARCHAR by sqlColumnType.
+var sqlTypes = map[SQLDialect]map[string]string{
+	PostgreSQL: {
+		"integer":          "INTEGER",
+		"integer/int64":    "BIGINT",
+		"number":           "NUMERIC",
+		"number/float":     "REAL",
+		"number/double":    "DOUBLE PRECISION",
+		"boolean":          "BOOLEAN",
+		"string":           "TEXT",
+		"string/date":      "DATE",
+		"string/date-time": "TIMESTAMP WITH TIME ZONE",
+		"string/uuid":      "UUID",
+		"string/byte":      "BYTEA",
+		"string/binary":    "BYTEA",
+		"object":           "JSONB",
+		"array":            "JSONB",
+	},
+	MySQL: {
+		"integer":          "INT",
+		"integer/int64":    "BIGINT",
+		"number":           "DECIMAL",
+		"number/float":     "FLOAT",
+		"number/double":    "DOUBLE",
+		"boolean":          "BOOLEAN",
+		"string":           "TEXT",
+		"string/date":      "DATE",
+		"string/date-time": "DATETIME",
+		"string/uuid":      "CHAR(36)",
+		"string/byte":      "BLOB",
+		"string/binary":    "BLOB",
+		"object":           "JSON",
+		"array":            "JSON",
+	},
+	SQLite: {
+		"integer":       "INTEGER",
+		"number":        "REAL",
+		"boolean":       "INTEGER",
+		"string":        "TEXT",
+		"string/byte":   "BLOB",
+		"string/binary": "BLOB",
+		"object":        "TEXT",
+		"array":         "TEXT",
+	},
+}
+
+// SQLOptions describes the behavior of WriteSQL.
+type SQLOptions struct {
+	// Dialect describes the SQL dialect of the statements, PostgreSQL when
+	// empty.
+	Dialect SQLDialect
+
+	// Types overrides the column types of the dialect. Keys are either a
+	// schema type (e.g. "string") or a type and a format separated by a slash
+	// (e.g. "string/uuid"), the latter taking precedence.
+	Types map[string]string
+
+	// Schemas describes the names of the component schemas tables are created
+	// for. Every object schema of the components is exported when empty.
+	Schemas []string
+
+	// PrimaryKey describes the name of the property declared as the primary
+	// key of the tables having it (e.g. "id").
+	PrimaryKey string
+}
+
+// WriteSQL writes a CREATE TABLE statement for the object schemas of the
+// components. Tables and columns are named after the schemas and their
+// properties in snake case, required properties are declared NOT NULL unless
+// nullable, and properties holding objects or arrays are stored as JSON
+// where the dialect supports it. Composed schemas are not flattened.
+func (r OpenAPI) WriteSQL(w io.Writer, opts SQLOptions) error {
+	if opts.Dialect == "" {
+		opts.Dialect = PostgreSQL
+	}
+	if _, ok := sqlTypes[opts.Dialect]; !ok {
+		return errors.Errorf("unsupported SQL dialect %q", opts.Dialect)
+	}
+
+	names := opts.Schemas
+	if len(names) == 0 && r.Components != nil {
+		for _, name := range sortedKeys(r.Components.Schemas) {
+			if schema := r.Components.Schemas[name]; schema != nil && schema.Type == "object" && schema.Properties.Len() > 0 {
+				names = append(names, name)
+			}
+		}
+	}
+
+	for i, name := range names {
+		var schema *Schema
+		if r.Components != nil {
+			schema = NewResolver(&r).schema(r.Components.Schemas[name])
+		}
+		if schema == nil || schema.Properties.Len() == 0 {
+			return errors.Errorf("schema %q is not an object schema with properties", name)
+		}
+
+		required := map[string]bool{}
+		for _, property := range schema.Required {
+			required[property] = true
+		}
+		columns := make([]string, 0, schema.Properties.Len())
+		for _, property := range sortedKeys(schema.Properties) {
+			resolved := NewResolver(&r).schema(schema.Properties.Get(property))
+			column := fmt.Sprintf("  %s %s",
+				sqlIdentifier(opts.Dialect, SnakeCase.Format(property)),
+				sqlColumnType(opts, resolved),
+			)
+			switch {
+			case property == opts.PrimaryKey:
+				column += " PRIMARY KEY"
+			case required[property] && (resolved == nil || !resolved.Nullable):
+				column += " NOT NULL"
+			}
+			columns = append(columns, column)
+		}
+
+		if i > 0 {
+			if _, err := io.WriteString(w, "\n"); err != nil {
+				return errors.WithStack(err)
+			}
+		}
+		_, err := fmt.Fprintf(w, "CREATE TABLE %s (\n%s\n);\n",
+			sqlIdentifier(opts.Dialect, SnakeCase.Format(name)),
+			strings.Join(columns, ",\n"),
+		)
+		if err != nil {
+			return errors.WithStack(err)
+		}
+	}
+	return nil
+}
+
+// sqlColumnType returns the column type of the schema in the dialect of the
+// options. Missing and untyped schemas are mapped to the column type of
+// strings.
+func sqlColumnType(opts SQLOptions, schema *Schema) string {
+	if schema == nil || schema.Type == "" {
+		schema = &Schema{Type: "string"}
+	}
+
+	keys := []string{schema.Type}
+	if schema.Format != "" {
+		keys = []string{schema.Type + "/" + schema.Format, schema.Type}
+	}
+	for _, key := range keys {
+		if column, ok := opts.Types[key]; ok {
+			return column
+		}
+	}
+	if schema.Type == "string" && schema.Format == "" && opts.Dialect != SQLite {
+		if length, err := strconv.Atoi(fmt.Sprint(schema.MaxLength)); err == nil && length > 0 {
+			return fmt.Sprintf("VARCHAR(%d)", length)
+		}
+	}
+	for _, key := range keys {
+		if column, ok := sqlTypes[opts.Dialect][key]; ok {
+			return column
+		}
+	}
+	return sqlTypes[opts.Dialect]["string"]
+}
+
+// sqlIdentifier returns the identifier quoted for the dialect.
+func sqlIdentifier(dialect SQLDialect, name string) string {
+	if dialect == MySQL {
+		return "`" + strings.Replace(name, "`", "``", -1) + "`"
+	}
+	return `"` + strings.Replace(name, `"`, `""`, -1) + `"`
+}
@@ -0,0 +1,124 @@
+package oas
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+)
+
+type SQLSuite struct {
+	suite.Suite
+}
+
+// sqlPatch is the patch of the petstore fixture the suite writes tables for.
+const sqlPatch = `
+components:
+  schemas:
+    Name:
+      type: string
+    Pet:
+      required: [id]
+      properties:
+        id:
+          type: integer
+          format: int64
+        name: null
+        owner:
+          $ref: "#/components/schemas/PetOwner"
+        tag: null
+        vaccine:
+          type: boolean
+        weight:
+          type: number
+          format: double
+    PetOwner:
+      type: object
+      required: [id, name, nickname]
+      properties:
+        birthDate:
+          type: string
+          format: date
+        id:
+          type: string
+          format: uuid
+        name:
+          type: string
+          maxLength: 64
+        nickname:
+          type: string
+          nullable: true
+        pets:
+          type: array
+          items:
+            $ref: "#/components/schemas/Pet"
+`
+
+func (r *SQLSuite) TestWriteSQL() {
+	testCases := []struct {
+		opts     SQLOptions
+		expected string
+	}{
+		{
+			SQLOptions{PrimaryKey: "id"},
+			"CREATE TABLE \"pet\" (\n" +
+				"  \"id\" BIGINT PRIMARY KEY,\n" +
+				"  \"owner\" JSONB,\n" +
+				"  \"vaccine\" BOOLEAN,\n" +
+				"  \"weight\" DOUBLE PRECISION\n" +
+				");\n" +
+				"\n" +
+				"CREATE TABLE \"pet_owner\" (\n" +
+				"  \"birth_date\" DATE,\n" +
+				"  \"id\" UUID PRIMARY KEY,\n" +
+				"  \"name\" VARCHAR(64) NOT NULL,\n" +
+				"  \"nickname\" TEXT,\n" +
+				"  \"pets\" JSONB\n" +
+				");\n",
+		},
+		{
+			SQLOptions{Dialect: MySQL, Schemas: []string{"PetOwner"}, Types: map[string]string{"string/date": "VARCHAR(10)"}},
+			"CREATE TABLE `pet_owner` (\n" +
+				"  `birth_date` VARCHAR(10),\n" +
+				"  `id` CHAR(36) NOT NULL,\n" +
+				"  `name` VARCHAR(64) NOT NULL,\n" +
+				"  `nickname` TEXT,\n" +
+				"  `pets` JSON\n" +
+				");\n",
+		},
+		{
+			SQLOptions{Dialect: SQLite, Schemas: []string{"Pet"}},
+			"CREATE TABLE \"pet\" (\n" +
+				"  \"id\" INTEGER NOT NULL,\n" +
+				"  \"owner\" TEXT,\n" +
+				"  \"vaccine\" INTEGER,\n" +
+				"  \"weight\" REAL\n" +
+				");\n",
+		},
+	}
+
+	for i, testCase := range testCases {
+		failMsg := "test case %d failed"
+		buf := &bytes.Buffer{}
+		assert.Nil(r.T(), petstore(r.T(), sqlPatch).WriteSQL(buf, testCase.opts), failMsg, i)
+		assert.Equal(r.T(), testCase.expected, buf.String(), failMsg, i)
+	}
+}
+
+func (r *SQLSuite) TestErrors() {
+	testCases := []SQLOptions{
+		{Dialect: "oracle"},
+		{Schemas: []string{"Name"}},
+		{Schemas: []string{"Missing"}},
+	}
+
+	for i, opts := range testCases {
+		failMsg := "test case %d failed"
+		assert.NotNil(r.T(), petstore(r.T(), sqlPatch).WriteSQL(&bytes.Buffer{}, opts), failMsg, i)
+	}
+}
+
+func TestSQLSuite(t *testing.T) {
+	suite.Run(t, new(SQLSuite))
+}
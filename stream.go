@@ -0,0 +1,328 @@
+package oas
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"io"
+	"mime"
+	"strconv"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// StreamItemExtension names the specification extension of a streaming
+// media type (e.g. text/event-stream) declaring the schema of the items of
+// the stream, the data of server-sent events or the lines of NDJSON.
+const StreamItemExtension = "x-stream-item"
+
+// Streaming media types.
+const (
+	EventStreamMediaType = "text/event-stream"
+	NDJSONMediaType      = "application/x-ndjson"
+)
+
+// Rules of the findings reported by AuditStreams.
+const (
+	RuleStreamItemMissing     = "stream-item-missing"
+	RuleStreamItemNotStreamed = "stream-item-not-streamed"
+)
+
+// IsStreamingMediaType reports whether the media type streams items:
+// server-sent events or newline delimited JSON.
+func IsStreamingMediaType(mediaType string) bool {
+	if parsed, _, err := mime.ParseMediaType(mediaType); err == nil {
+		mediaType = parsed
+	}
+	switch strings.ToLower(mediaType) {
+	case EventStreamMediaType, NDJSONMediaType, "application/jsonl", "application/stream+json":
+		return true
+	}
+	return false
+}
+
+// StreamItem returns the schema of the stream items declared by the
+// x-stream-item extension, or nil if the extension is absent.
+func (r Extensions) StreamItem() (*Schema, error) {
+	item := &Schema{}
+	ok, err := r.decode(StreamItemExtension, item)
+	if !ok || err != nil {
+		return nil, err
+	}
+	return item, nil
+}
+
+// SetStreamItem declares the schema of the stream items with the
+// x-stream-item extension, or removes the extension when nil.
+func (r *Extensions) SetStreamItem(item *Schema) {
+	if item == nil {
+		delete(*r, StreamItemExtension)
+		return
+	}
+	setExtension(r, StreamItemExtension, item)
+}
+
+// DeclareStream declares the response of the operation under the path and
+// lowercase method with the status code as a stream of the streaming media
+// type, whose items conform to the item schema. The response is added unless
+// the operation declares it, and the media type replaces any content of the
+// same type.
+func (r *OpenAPI) DeclareStream(path string, method string, status string, mediaType string, item *Schema) error {
+	if !IsStreamingMediaType(mediaType) {
+		return errors.Errorf("%s is not a streaming media type", mediaType)
+	}
+	if item == nil {
+		return errors.New("missing stream item schema")
+	}
+	op, err := r.Paths.operation(path, method)
+	if err != nil {
+		return err
+	}
+	response := op.operation.Responses.Get(status)
+	if response == nil {
+		response = &Response{Description: "A stream of items."}
+		op.operation.Responses.Set(status, response)
+	}
+	if response.Ref != "" {
+		return errors.Errorf("response %s of %s %s is a reference", status, strings.ToUpper(method), path)
+	}
+	if response.Content == nil {
+		response.Content = map[string]*MediaType{}
+	}
+	media := &MediaType{Schema: &Schema{Type: "string"}}
+	media.Extensions.SetStreamItem(item)
+	response.Content[mediaType] = media
+	return nil
+}
+
+// StreamEvent describes an item of a stream. For NDJSON streams only the
+// data is set.
+type StreamEvent struct {
+	// ID describes the id field of the server-sent event.
+	ID string
+
+	// Event describes the event field of the server-sent event, the type of
+	// the event.
+	Event string
+
+	// Retry describes the reconnection time of the server-sent event in
+	// milliseconds, 0 when unset.
+	Retry int
+
+	// Data describes the data of the item decoded from JSON, or the raw
+	// data of server-sent events which are not JSON.
+	Data interface{}
+}
+
+// StreamDecoder reads the items of a streaming response body one at a
+// time, so clients iterate over the stream as it arrives.
+type StreamDecoder struct {
+	scanner     *bufio.Scanner
+	eventStream bool
+}
+
+// NewStreamDecoder returns a decoder of the body of the streaming media
+// type.
+func NewStreamDecoder(body io.Reader, mediaType string) (*StreamDecoder, error) {
+	if !IsStreamingMediaType(mediaType) {
+		return nil, errors.Errorf("%s is not a streaming media type", mediaType)
+	}
+	if parsed, _, err := mime.ParseMediaType(mediaType); err == nil {
+		mediaType = parsed
+	}
+	scanner := bufio.NewScanner(body)
+	scanner.Buffer(make([]byte, 0, 64*1024), 16*1024*1024)
+	return &StreamDecoder{
+		scanner:     scanner,
+		eventStream: strings.EqualFold(mediaType, EventStreamMediaType),
+	}, nil
+}
+
+// Next returns the next item of the stream, or io.EOF at the end of the
+// stream.
+func (r *StreamDecoder) Next() (*StreamEvent, error) {
+	if r.eventStream {
+		return r.nextEvent()
+	}
+	for r.scanner.Scan() {
+		line := bytes.TrimSpace(r.scanner.Bytes())
+		if len(line) == 0 {
+			continue
+		}
+		event := &StreamEvent{}
+		if err := decodeJSON(line, &event.Data); err != nil {
+			return nil, err
+		}
+		return event, nil
+	}
+	if err := r.scanner.Err(); err != nil {
+		return nil, errors.WithStack(err)
+	}
+	return nil, io.EOF
+}
+
+// nextEvent returns the next server-sent event, skipping comments and
+// events without data.
+func (r *StreamDecoder) nextEvent() (*StreamEvent, error) {
+	event := &StreamEvent{}
+	data := make([]string, 0)
+	for r.scanner.Scan() {
+		line := r.scanner.Text()
+		if line == "" {
+			if len(data) == 0 {
+				event = &StreamEvent{}
+				continue
+			}
+			event.Data = eventData(strings.Join(data, "\n"))
+			return event, nil
+		}
+		if strings.HasPrefix(line, ":") {
+			continue
+		}
+		field, value := line, ""
+		if i := strings.Index(line, ":"); i >= 0 {
+			field, value = line[:i], strings.TrimPrefix(line[i+1:], " ")
+		}
+		switch field {
+		case "id":
+			event.ID = value
+		case "event":
+			event.Event = value
+		case "retry":
+			event.Retry, _ = strconv.Atoi(value)
+		case "data":
+			data = append(data, value)
+		}
+	}
+	if err := r.scanner.Err(); err != nil {
+		return nil, errors.WithStack(err)
+	}
+	return nil, io.EOF
+}
+
+// eventData returns the data of a server-sent event decoded from JSON, or
+// the raw data if it is not JSON.
+func eventData(data string) interface{} {
+	var value interface{}
+	if err := decodeJSON([]byte(data), &value); err != nil {
+		return data
+	}
+	return value
+}
+
+// StreamItemError describes a stream item which does not conform to the
+// item schema.
+type StreamItemError struct {
+	// Index describes the 0-based position of the item in the stream.
+	Index int
+
+	// Err describes why the item does not conform.
+	Err error
+}
+
+// Error returns the string representation of the error.
+func (e *StreamItemError) Error() string {
+	return fmt.Sprintf("stream item %d: %v", e.Index, e.Err)
+}
+
+// ValidateStream reads the streaming response body the operation under the
+// path and lowercase method answered with the status code, and validates
+// every item against the item schema of the media type. It returns the
+// number of items read. Validation stops at the first item which does not
+// conform, reported as *StreamItemError. Items are validated with
+// ValidatePayload when validate is nil.
+func (r OpenAPI) ValidateStream(path string, method string, status int, mediaType string, body io.Reader, validate PayloadValidator) (int, error) {
+	if validate == nil {
+		validate = ValidatePayload
+	}
+	op, err := r.Paths.operation(path, method)
+	if err != nil {
+		return 0, err
+	}
+	_, response := NewResolver(&r).response("", declaredResponse(op.operation.Responses, status))
+	if response == nil {
+		return 0, errors.Errorf("%s %s declares no response %d", strings.ToUpper(method), path, status)
+	}
+	if parsed, _, err := mime.ParseMediaType(mediaType); err == nil {
+		mediaType = parsed
+	}
+	var media *MediaType
+	for key, value := range response.Content {
+		if strings.EqualFold(key, mediaType) {
+			media = value
+		}
+	}
+	if media == nil {
+		return 0, errors.Errorf("response %d declares no %s content", status, mediaType)
+	}
+	item, err := media.Extensions.StreamItem()
+	if err != nil {
+		return 0, err
+	}
+	if item == nil {
+		return 0, errors.Errorf("%s content declares no %s", mediaType, StreamItemExtension)
+	}
+
+	decoder, err := NewStreamDecoder(body, mediaType)
+	if err != nil {
+		return 0, err
+	}
+	count := 0
+	for {
+		event, err := decoder.Next()
+		if err == io.EOF {
+			return count, nil
+		}
+		if err != nil {
+			return count, err
+		}
+		if err := validate(&r, item, event.Data); err != nil {
+			return count, &StreamItemError{Index: count, Err: err}
+		}
+		count++
+	}
+}
+
+// AuditStreams flags streaming media types which do not declare the schema
+// of their items with the x-stream-item extension, and item schemas
+// declared on media types which do not stream. Malformed extensions are
+// reported as well.
+func (r OpenAPI) AuditStreams() []*Finding {
+	findings := make([]*Finding, 0)
+	_ = walk(&r, func(ptr string, node interface{}) error {
+		media, ok := node.(*MediaType)
+		if !ok {
+			return nil
+		}
+		tokens := splitPointer(ptr)
+		mediaType := tokens[len(tokens)-1]
+		item, err := media.Extensions.StreamItem()
+		switch {
+		case err != nil:
+			findings = append(findings, &Finding{
+				Pointer:  join(ptr, StreamItemExtension),
+				Rule:     RuleStreamItemMissing,
+				Severity: SeverityError,
+				Message:  err.Error(),
+			})
+		case IsStreamingMediaType(mediaType) && item == nil:
+			findings = append(findings, &Finding{
+				Pointer:  ptr,
+				Rule:     RuleStreamItemMissing,
+				Severity: SeverityWarning,
+				Message:  "streaming media type " + mediaType + " does not declare the schema of its items with " + StreamItemExtension,
+			})
+		case !IsStreamingMediaType(mediaType) && item != nil:
+			findings = append(findings, &Finding{
+				Pointer:  join(ptr, StreamItemExtension),
+				Rule:     RuleStreamItemNotStreamed,
+				Severity: SeverityError,
+				Message:  "media type " + mediaType + " does not stream items",
+			})
+		}
+		return nil
+	})
+	sortFindings(findings)
+	return findings
+}
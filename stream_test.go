@@ -0,0 +1,186 @@
+package oas
+
+import (
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+)
+
+type StreamSuite struct {
+	suite.Suite
+}
+
+// streamPatch is the patch of the petstore fixture the suite streams from.
+const streamPatch = `
+paths:
+  /pets/events:
+    get:
+      operationId: watchPets
+`
+
+func (r *StreamSuite) TestIsStreamingMediaType() {
+	testCases := []struct {
+		mediaType string
+		expected  bool
+	}{
+		{"text/event-stream", true},
+		{"text/event-stream; charset=utf-8", true},
+		{"application/x-ndjson", true},
+		{"Application/JSONL", true},
+		{"application/json", false},
+		{"text/plain", false},
+	}
+
+	for i, testCase := range testCases {
+		failMsg := "test case %d failed"
+		assert.Equal(r.T(), testCase.expected, IsStreamingMediaType(testCase.mediaType), failMsg, i+1)
+	}
+}
+
+func (r *StreamSuite) TestDeclareStream() {
+	item := &Schema{Type: "object", Required: []string{"name"}}
+	doc := petstore(r.T(), streamPatch)
+	assert.Nil(r.T(), doc.DeclareStream("/pets/events", "get", "200", EventStreamMediaType, item))
+	response := doc.Paths.PathItems.Get("/pets/events").Get.Responses.Get("200")
+	media := response.Content[EventStreamMediaType]
+	assert.Equal(r.T(), &Schema{Type: "string"}, media.Schema)
+	declared, err := media.Extensions.StreamItem()
+	assert.Nil(r.T(), err)
+	assert.Equal(r.T(), item, declared)
+	assert.Equal(r.T(), []*Finding{}, doc.AuditStreams())
+
+	assert.NotNil(r.T(), doc.DeclareStream("/pets/events", "get", "200", "application/json", item))
+	assert.NotNil(r.T(), doc.DeclareStream("/pets/events", "get", "200", NDJSONMediaType, nil))
+	assert.NotNil(r.T(), doc.DeclareStream("/unknown", "get", "200", NDJSONMediaType, item))
+
+	media.Extensions.SetStreamItem(nil)
+	declared, err = media.Extensions.StreamItem()
+	assert.Nil(r.T(), err)
+	assert.Nil(r.T(), declared)
+}
+
+func (r *StreamSuite) TestStreamDecoder() {
+	testCases := []struct {
+		mediaType string
+		body      string
+		expected  []*StreamEvent
+	}{
+		{
+			NDJSONMediaType,
+			"{\"name\":\"Rex\"}\n\n{\"name\":\"Tom\",\"age\":3}\n",
+			[]*StreamEvent{
+				{Data: map[string]interface{}{"name": "Rex"}},
+				{Data: map[string]interface{}{"name": "Tom", "age": 3}},
+			},
+		},
+		{
+			EventStreamMediaType,
+			": keep alive\n\nid: 1\nevent: created\ndata: {\"name\":\n" +
+				"data: \"Rex\"}\n\nretry: 1000\ndata: ping\n\n",
+			[]*StreamEvent{
+				{ID: "1", Event: "created", Data: map[string]interface{}{"name": "Rex"}},
+				{Retry: 1000, Data: "ping"},
+			},
+		},
+	}
+
+	for i, testCase := range testCases {
+		failMsg := "test case %d failed"
+		decoder, err := NewStreamDecoder(strings.NewReader(testCase.body), testCase.mediaType)
+		assert.Nil(r.T(), err, failMsg, i+1)
+		events := make([]*StreamEvent, 0)
+		for {
+			event, err := decoder.Next()
+			if err == io.EOF {
+				break
+			}
+			assert.Nil(r.T(), err, failMsg, i+1)
+			events = append(events, event)
+		}
+		assert.Equal(r.T(), testCase.expected, events, failMsg, i+1)
+	}
+
+	_, err := NewStreamDecoder(strings.NewReader(""), "application/json")
+	assert.NotNil(r.T(), err)
+	decoder, err := NewStreamDecoder(strings.NewReader("{\n"), NDJSONMediaType)
+	assert.Nil(r.T(), err)
+	_, err = decoder.Next()
+	assert.NotNil(r.T(), err)
+}
+
+func (r *StreamSuite) TestValidateStream() {
+	validate := func(doc *OpenAPI, schema *Schema, value interface{}) error {
+		if object, ok := value.(map[string]interface{}); !ok || object["name"] == nil {
+			return errors.New("missing name")
+		}
+		return nil
+	}
+	doc := petstore(r.T(), streamPatch)
+	item := &Schema{Type: "object", Required: []string{"name"}}
+	assert.Nil(r.T(), doc.DeclareStream("/pets/events", "get", "200", NDJSONMediaType, item))
+
+	count, err := doc.ValidateStream("/pets/events", "get", 200, NDJSONMediaType, strings.NewReader("{\"name\":\"Rex\"}\n{\"name\":\"Tom\"}\n"), validate)
+	assert.Nil(r.T(), err)
+	assert.Equal(r.T(), 2, count)
+
+	count, err = doc.ValidateStream("/pets/events", "get", 200, NDJSONMediaType, strings.NewReader("{\"name\":\"Rex\"}\n{}\n"), validate)
+	assert.Equal(r.T(), 1, count)
+	itemErr, ok := err.(*StreamItemError)
+	assert.True(r.T(), ok)
+	assert.Equal(r.T(), 1, itemErr.Index)
+	assert.Equal(r.T(), "stream item 1: missing name", err.Error())
+
+	_, err = doc.ValidateStream("/pets/events", "get", 200, EventStreamMediaType, strings.NewReader(""), validate)
+	assert.NotNil(r.T(), err)
+	_, err = doc.ValidateStream("/pets/events", "get", 404, NDJSONMediaType, strings.NewReader(""), validate)
+	assert.NotNil(r.T(), err)
+	count, err = doc.ValidateStream("/pets/events", "get", 200, NDJSONMediaType, strings.NewReader("{\"name\":\"Rex\"}\n{}\n"), nil)
+	assert.Equal(r.T(), 1, count)
+	assert.EqualError(r.T(), err, `stream item 1: /: missing required property "name"`)
+}
+
+func (r *StreamSuite) TestAuditStreams() {
+	doc := petstore(r.T(), streamPatch, `
+paths:
+  /pets:
+    get:
+      responses:
+        "200":
+          content:
+            application/json:
+              x-stream-item:
+                type: object
+  /pets/events:
+    get:
+      responses:
+        "200":
+          description: The events.
+          content:
+            text/event-stream:
+              schema:
+                type: string
+`)
+
+	assert.Equal(r.T(), []*Finding{
+		{
+			Pointer:  "/paths/~1pets/get/responses/200/content/application~1json/x-stream-item",
+			Rule:     RuleStreamItemNotStreamed,
+			Severity: SeverityError,
+			Message:  "media type application/json does not stream items",
+		},
+		{
+			Pointer:  "/paths/~1pets~1events/get/responses/200/content/text~1event-stream",
+			Rule:     RuleStreamItemMissing,
+			Severity: SeverityWarning,
+			Message:  "streaming media type text/event-stream does not declare the schema of its items with x-stream-item",
+		},
+	}, doc.AuditStreams())
+}
+
+func TestStreamSuite(t *testing.T) {
+	suite.Run(t, new(StreamSuite))
+}
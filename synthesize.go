@@ -0,0 +1,93 @@
+package oas
+
+import (
+	"strings"
+)
+
+// SynthesizedExtension describes the extension marking operations added by
+// SynthesizeMethods, so they can be told apart from authored operations.
+const SynthesizedExtension = "x-synthesized"
+
+// SynthesizeOptions describes the operations SynthesizeMethods adds.
+type SynthesizeOptions struct {
+	// Head adds a HEAD operation mirroring the GET operation of every path
+	// which declares no HEAD operation.
+	Head bool
+
+	// Options adds an OPTIONS operation answering with 204 and an Allow
+	// header to every path which declares no OPTIONS operation.
+	Options bool
+}
+
+// SynthesizeMethods adds HEAD and OPTIONS operations to the paths of the
+// document, for servers handling every method of a path. HEAD operations
+// copy the parameters, security and response headers of the GET operation
+// without response content and derive their operationId by appending "Head".
+// The pointers of the added operations are returned in sorted order.
+func (r *OpenAPI) SynthesizeMethods(opts SynthesizeOptions) ([]string, error) {
+	added := make([]string, 0)
+	for _, path := range sortedKeys(r.Paths.PathItems) {
+		item := r.Paths.PathItems.Get(path)
+		if item == nil {
+			continue
+		}
+
+		if opts.Head && item.Head == nil && item.Get != nil {
+			head, err := item.Get.Clone()
+			if err != nil {
+				return nil, err
+			}
+			if head.OperationID != "" {
+				head.OperationID += "Head"
+			}
+			head.RequestBody = nil
+			head.Callbacks = nil
+			for _, code := range head.Responses.Keys() {
+				response := head.Responses.Get(code)
+				if response != nil && response.Ref != "" {
+					_, response = NewResolver(r).response("", response)
+					if response != nil {
+						if response, err = response.Clone(); err != nil {
+							return nil, err
+						}
+					}
+				}
+				if response != nil {
+					response.Content = nil
+					response.Links = nil
+				}
+				head.Responses.Set(code, response)
+			}
+			setExtension(&head.Extensions, SynthesizedExtension, true)
+			item.Head = head
+			added = append(added, join("/paths", path, "head"))
+		}
+
+		if opts.Options && item.Options == nil {
+			methods := make([]string, 0)
+			for _, op := range item.operations() {
+				methods = append(methods, strings.ToUpper(op.method))
+			}
+			methods = append(methods, "OPTIONS")
+			options := &Operation{
+				Summary: "Lists the methods allowed on the path.",
+				Responses: NewResponses(map[string]*Response{
+					"204": {
+						Description: "No Content.",
+						Headers: map[string]*Header{
+							"Allow": {
+								Description: "Methods allowed on the path.",
+								Schema:      &Schema{Type: "string"},
+								Example:     strings.Join(methods, ", "),
+							},
+						},
+					},
+				}),
+			}
+			setExtension(&options.Extensions, SynthesizedExtension, true)
+			item.Options = options
+			added = append(added, join("/paths", path, "options"))
+		}
+	}
+	return added, nil
+}
@@ -0,0 +1,76 @@
+package oas
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+)
+
+type SynthesizeSuite struct {
+	suite.Suite
+}
+
+func (r *SynthesizeSuite) TestSynthesizeMethods() {
+	doc := &OpenAPI{
+		OpenAPI: "3.0.0",
+		Info:    Info{Title: "Petstore", Version: "1.0.0"},
+		Paths: Paths{
+			PathItems: NewPathItems(map[string]*PathItem{
+				"/pets": {
+					Get: &Operation{
+						OperationID: "listPets",
+						Parameters:  []*Parameter{{Name: "limit", In: "query"}},
+						Responses: NewResponses(map[string]*Response{
+							"200": {
+								Description: "Pets.",
+								Headers:     map[string]*Header{"X-Total-Count": {}},
+								Content:     map[string]*MediaType{"application/json": {}},
+							},
+							"default": {Ref: "#/components/responses/Error"},
+						}),
+					},
+					Post: &Operation{OperationID: "createPet"},
+				},
+				"/health": {
+					Get:     &Operation{},
+					Head:    &Operation{},
+					Options: &Operation{},
+				},
+			}),
+		},
+		Components: &Components{
+			Responses: map[string]*Response{
+				"Error": {
+					Description: "An error.",
+					Content:     map[string]*MediaType{"application/json": {}},
+				},
+			},
+		},
+	}
+
+	added, err := doc.SynthesizeMethods(SynthesizeOptions{Head: true, Options: true})
+	assert.NoError(r.T(), err)
+	assert.Equal(r.T(), []string{"/paths/~1pets/head", "/paths/~1pets/options"}, added)
+
+	item := doc.Paths.PathItems.Get("/pets")
+	assert.Equal(r.T(), &Operation{
+		OperationID: "listPetsHead",
+		Parameters:  []*Parameter{{Name: "limit", In: "query"}},
+		Responses: NewResponses(map[string]*Response{
+			"200": {
+				Description: "Pets.",
+				Headers:     map[string]*Header{"X-Total-Count": {}},
+			},
+			"default": {Description: "An error."},
+		}),
+		Extensions: Extensions{SynthesizedExtension: true},
+	}, item.Head)
+	assert.Equal(r.T(), "GET, POST, HEAD, OPTIONS", item.Options.Responses.Get("204").Headers["Allow"].Example)
+	assert.Len(r.T(), doc.Components.Responses["Error"].Content, 1)
+	assert.Len(r.T(), item.Get.Responses.Get("200").Content, 1)
+}
+
+func TestSynthesizeSuite(t *testing.T) {
+	suite.Run(t, new(SynthesizeSuite))
+}
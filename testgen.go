@@ -0,0 +1,253 @@
+package oas
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"go/format"
+	"io"
+	"net/url"
+	"sort"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// TestSkeletonOptions describes the behavior of WriteTestSkeletons.
+type TestSkeletonOptions struct {
+	// Package describes the package clause of the generated file. Defaults
+	// to "api_test".
+	Package string
+
+	// BaseURLEnv describes the environment variable holding the URL of the
+	// API under test. Defaults to "API_BASE_URL".
+	BaseURLEnv string
+}
+
+// WriteTestSkeletons writes a Go test file holding a test per operation,
+// ordered by path and method, to jump-start a contract test suite. Each test
+// sends a request built from the examples of the parameters and of the
+// request body to the API at the URL of the environment variable, skipping
+// when it is unset, and asserts the lowest successful status code of the
+// operation and, for JSON responses, the shape and required properties of
+// the body. Tests of operations whose required parameters or body have no
+// example are skipped until completed by hand.
+func (r OpenAPI) WriteTestSkeletons(w io.Writer, opts TestSkeletonOptions) error {
+	if opts.Package == "" {
+		opts.Package = "api_test"
+	}
+	if opts.BaseURLEnv == "" {
+		opts.BaseURLEnv = "API_BASE_URL"
+	}
+
+	tests := &bytes.Buffer{}
+	sends, decodes := false, false
+	names := map[string]bool{}
+	for _, op := range r.Paths.operations() {
+		name := op.operation.OperationID
+		if name == "" {
+			name = op.method + " " + op.path
+		}
+		name = uniqueOperationID("Test"+PascalCase.Format(name), names)
+		sent, decoded := r.writeTestSkeleton(tests, name, op)
+		sends, decodes = sends || sent, decodes || decoded
+	}
+
+	buf := &bytes.Buffer{}
+	fmt.Fprintf(buf, "// Code generated from %q. Complete the skeletons into contract tests.\n\n", r.Info.Title)
+	fmt.Fprintf(buf, "package %s\n\nimport (\n", opts.Package)
+	if decodes {
+		buf.WriteString("\t\"encoding/json\"\n")
+	}
+	if sends {
+		buf.WriteString("\t\"net/http\"\n")
+	}
+	buf.WriteString("\t\"os\"\n\t\"strings\"\n\t\"testing\"\n)\n\n")
+	fmt.Fprintf(buf, "// baseURL returns the URL of the API under test, skipping the test when\n// %s is not set.\n", opts.BaseURLEnv)
+	fmt.Fprintf(buf, "func baseURL(t *testing.T) string {\n\tbase := os.Getenv(%q)\n", opts.BaseURLEnv)
+	fmt.Fprintf(buf, "\tif base == \"\" {\n\t\tt.Skip(%q)\n\t}\n", opts.BaseURLEnv+" is not set")
+	buf.WriteString("\treturn strings.TrimSuffix(base, \"/\")\n}\n")
+	buf.Write(tests.Bytes())
+
+	source, err := format.Source(buf.Bytes())
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	_, err = w.Write(source)
+	return errors.WithStack(err)
+}
+
+// writeTestSkeleton writes the test of the operation and reports whether it
+// sends a request and whether it decodes a JSON body.
+func (r OpenAPI) writeTestSkeleton(buf *bytes.Buffer, name string, op pathOperation) (bool, bool) {
+	fmt.Fprintf(buf, "\nfunc %s(t *testing.T) {\n", name)
+
+	sample, missing := r.sampleRequest(op)
+	if sample == nil {
+		fmt.Fprintf(buf, "\tt.Skip(%q)\n}\n", missing)
+		return false, false
+	}
+	body := "nil"
+	if sample.body != nil {
+		body = fmt.Sprintf("strings.NewReader(%q)", *sample.body)
+	}
+
+	fmt.Fprintf(buf, "\treq, err := http.NewRequest(%q, baseURL(t)+%q, %s)\n", strings.ToUpper(op.method), sample.path, body)
+	buf.WriteString("\tif err != nil {\n\t\tt.Fatal(err)\n\t}\n")
+	for _, header := range sortedStrings(sample.headers) {
+		fmt.Fprintf(buf, "\treq.Header.Set(%q, %q)\n", header, sample.headers[header])
+	}
+	buf.WriteString("\tresp, err := http.DefaultClient.Do(req)\n\tif err != nil {\n\t\tt.Fatal(err)\n\t}\n")
+	buf.WriteString("\tdefer resp.Body.Close()\n")
+
+	codes := make([]string, 0)
+	for _, code := range op.operation.Responses.Keys() {
+		if len(code) == 3 && code[0] == '2' && code != "2XX" {
+			codes = append(codes, code)
+		}
+	}
+	if len(codes) == 0 {
+		buf.WriteString("\t// TODO: assert the response, the operation declares no successful status code.\n}\n")
+		return true, false
+	}
+	sort.Strings(codes)
+	fmt.Fprintf(buf, "\n\tif resp.StatusCode != %s {\n\t\tt.Fatalf(\"expected status %s, got %%d\", resp.StatusCode)\n\t}\n", codes[0], codes[0])
+
+	_, response := NewResolver(&r).response("", op.operation.Responses.Get(codes[0]))
+	var schema *Schema
+	if response != nil {
+		for _, mediaType := range sortedKeys(response.Content) {
+			if isJSONMediaType(mediaType) && response.Content[mediaType] != nil {
+				schema = NewResolver(&r).schema(response.Content[mediaType].Schema)
+				break
+			}
+		}
+	}
+	if schema == nil {
+		buf.WriteString("}\n")
+		return true, false
+	}
+
+	switch {
+	case schema.Type == "array":
+		buf.WriteString("\n\tvar payload []interface{}\n")
+	case schema.Type == "object" || schema.Properties.Len() > 0 || len(schema.Required) > 0:
+		buf.WriteString("\n\tvar payload map[string]interface{}\n")
+	default:
+		buf.WriteString("\n\tvar payload interface{}\n")
+	}
+	buf.WriteString("\tif err := json.NewDecoder(resp.Body).Decode(&payload); err != nil {\n\t\tt.Fatal(err)\n\t}\n")
+	if len(schema.Required) > 0 && schema.Type != "array" {
+		required := append([]string{}, schema.Required...)
+		sort.Strings(required)
+		fmt.Fprintf(buf, "\tfor _, name := range %#v {\n", required)
+		buf.WriteString("\t\tif _, ok := payload[name]; !ok {\n\t\t\tt.Errorf(\"missing required property %q\", name)\n\t\t}\n\t}\n")
+	}
+	buf.WriteString("}\n")
+	return true, true
+}
+
+// sampleRequest describes a request to an operation built from the
+// examples of its parameters and request body.
+type sampleRequest struct {
+	// path holds the expanded path and the query string.
+	path string
+
+	// headers holds the header values, including cookies and the content
+	// type of the body.
+	headers map[string]string
+
+	// body holds the encoded body, nil when the request has none.
+	body *string
+}
+
+// sampleRequest returns the request to the operation built from the
+// examples of its parameters and request body, or nil and the reason when a
+// required parameter or body has no example.
+func (r OpenAPI) sampleRequest(op pathOperation) (*sampleRequest, string) {
+	path := op.path
+	query := url.Values{}
+	headers := map[string]string{}
+	cookies := make([]string, 0)
+	for _, parameter := range append(append([]*Parameter{}, op.item.Parameters...), op.operation.Parameters...) {
+		_, parameter = NewResolver(&r).parameter("", parameter)
+		if parameter == nil {
+			continue
+		}
+		value, ok := parameterExample(parameter)
+		if !ok {
+			if parameter.Required {
+				return nil, "no example value for required " + parameter.In + " parameter " + parameter.Name
+			}
+			continue
+		}
+		switch parameter.In {
+		case "path":
+			path = strings.Replace(path, "{"+parameter.Name+"}", url.PathEscape(value), -1)
+		case "query":
+			query.Set(parameter.Name, value)
+		case "header":
+			headers[parameter.Name] = value
+		case "cookie":
+			cookies = append(cookies, parameter.Name+"="+value)
+		}
+	}
+	if len(cookies) > 0 {
+		sort.Strings(cookies)
+		headers["Cookie"] = strings.Join(cookies, "; ")
+	}
+	if len(query) > 0 {
+		path += "?" + query.Encode()
+	}
+
+	sample := &sampleRequest{path: path, headers: headers}
+	if _, requestBody := NewResolver(&r).requestBody("", op.operation.RequestBody); requestBody != nil {
+		mediaType, payload, ok := requestExample(requestBody)
+		switch {
+		case ok:
+			sample.body = &payload
+			headers["Content-Type"] = mediaType
+		case requestBody.Required:
+			return nil, "no example value for the required request body"
+		}
+	}
+	return sample, ""
+}
+
+// requestExample returns the media type and the encoded example of the
+// request body, preferring JSON media types.
+func requestExample(body *RequestBody) (string, string, bool) {
+	mediaTypes := sortedKeys(body.Content)
+	sort.SliceStable(mediaTypes, func(i, j int) bool {
+		return isJSONMediaType(mediaTypes[i]) && !isJSONMediaType(mediaTypes[j])
+	})
+	for _, mediaType := range mediaTypes {
+		content := body.Content[mediaType]
+		if content == nil {
+			continue
+		}
+		candidates := []interface{}{content.Example}
+		for _, key := range sortedKeys(content.Examples) {
+			if example := content.Examples[key]; example != nil {
+				candidates = append(candidates, example.Value)
+			}
+		}
+		if content.Schema != nil {
+			candidates = append(candidates, content.Schema.Example)
+		}
+		for _, candidate := range candidates {
+			if candidate == nil {
+				continue
+			}
+			if text, ok := candidate.(string); ok && !isJSONMediaType(mediaType) {
+				return mediaType, text, true
+			}
+			rbytes, err := json.Marshal(candidate)
+			if err != nil {
+				continue
+			}
+			return mediaType, string(rbytes), true
+		}
+	}
+	return "", "", false
+}
@@ -0,0 +1,186 @@
+package oas
+
+import (
+	"bytes"
+	"go/ast"
+	"go/importer"
+	"go/parser"
+	"go/token"
+	"go/types"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+)
+
+type TestGenSuite struct {
+	suite.Suite
+}
+
+// testGenPatch is the patch of the petstore fixture the suite writes test
+// skeletons for.
+const testGenPatch = `
+paths:
+  /pets:
+    get:
+      parameters:
+        - name: limit
+          in: query
+          schema:
+            type: integer
+            default: 20
+        - name: X-Request-Id
+          in: header
+          example: abc
+    post:
+      requestBody:
+        content:
+          application/json:
+            schema: null
+            example:
+              name: Rex
+      responses:
+        "201":
+          content:
+            application/json:
+              schema:
+                $ref: "#/components/schemas/Pet"
+        "202":
+          description: Accepted.
+  /pets/{petId}:
+    parameters:
+      - name: petId
+        in: path
+        required: true
+    get: null
+    delete:
+      operationId: null
+components:
+  schemas:
+    Pet:
+      required: [name, id]
+      properties:
+        id:
+          type: integer
+`
+
+func (r *TestGenSuite) TestWriteTestSkeletons() {
+	buf := &bytes.Buffer{}
+	assert.Nil(r.T(), petstore(r.T(), testGenPatch).WriteTestSkeletons(buf, TestSkeletonOptions{Package: "petstore_test"}))
+	source := buf.String()
+
+	assert.Contains(r.T(), source, "package petstore_test\n")
+	assert.Contains(r.T(), source, "\t\"encoding/json\"\n")
+	assert.Contains(r.T(), source, `base := os.Getenv("API_BASE_URL")`)
+
+	assert.Contains(r.T(), source, `func TestListPets(t *testing.T) {
+	req, err := http.NewRequest("GET", baseURL(t)+"/pets?limit=20", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	req.Header.Set("X-Request-Id", "abc")
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != 200 {
+		t.Fatalf("expected status 200, got %d", resp.StatusCode)
+	}
+
+	var payload []interface{}
+	if err := json.NewDecoder(resp.Body).Decode(&payload); err != nil {
+		t.Fatal(err)
+	}
+}`)
+
+	assert.Contains(r.T(), source, `req, err := http.NewRequest("POST", baseURL(t)+"/pets", strings.NewReader("{\"name\":\"Rex\"}"))`)
+	assert.Contains(r.T(), source, `req.Header.Set("Content-Type", "application/json")`)
+	assert.Contains(r.T(), source, `	var payload map[string]interface{}
+	if err := json.NewDecoder(resp.Body).Decode(&payload); err != nil {
+		t.Fatal(err)
+	}
+	for _, name := range []string{"id", "name"} {`)
+
+	assert.Contains(r.T(), source, `func TestDeletePetsPetId(t *testing.T) {
+	t.Skip("no example value for required path parameter petId")
+}`)
+}
+
+func (r *TestGenSuite) TestWriteTestSkeletonsWithoutBodies() {
+	doc := petstore(r.T(), testGenPatch, `
+paths:
+  /pets: null
+  /pets/{petId}:
+    parameters:
+      - name: petId
+        in: path
+        required: true
+        example: 7
+`)
+
+	buf := &bytes.Buffer{}
+	assert.Nil(r.T(), doc.WriteTestSkeletons(buf, TestSkeletonOptions{BaseURLEnv: "PETSTORE_URL"}))
+	source := buf.String()
+	assert.Contains(r.T(), source, "package api_test\n")
+	assert.NotContains(r.T(), source, "encoding/json")
+	assert.Contains(r.T(), source, `os.Getenv("PETSTORE_URL")`)
+	assert.Contains(r.T(), source, `http.NewRequest("DELETE", baseURL(t)+"/pets/7", nil)`)
+
+	doc = petstore(r.T(), testGenPatch, `
+paths:
+  /pets: null
+`)
+	buf.Reset()
+	assert.Nil(r.T(), doc.WriteTestSkeletons(buf, TestSkeletonOptions{}))
+	assert.NotContains(r.T(), buf.String(), "net/http")
+}
+
+func (r *TestGenSuite) TestWriteTestSkeletonsTypeCheck() {
+	doc := petstore(r.T(), testGenPatch, `
+paths:
+  /pets/{petId}:
+    parameters:
+      - name: petId
+        in: path
+        required: true
+        example: 7
+    get:
+      operationId: showPet
+      responses:
+        "200":
+          description: Pet.
+          content:
+            application/json:
+              schema:
+                required: [name]
+  /owners:
+    get:
+      operationId: showOwner
+      responses:
+        "200":
+          description: Owner.
+          content:
+            application/json:
+              schema:
+                type: string
+`)
+
+	buf := &bytes.Buffer{}
+	assert.Nil(r.T(), doc.WriteTestSkeletons(buf, TestSkeletonOptions{}))
+	assert.Contains(r.T(), buf.String(), `func TestShowPet(t *testing.T) {`)
+
+	fset := token.NewFileSet()
+	file, err := parser.ParseFile(fset, "api_test.go", buf.Bytes(), 0)
+	if !assert.Nil(r.T(), err) {
+		return
+	}
+	config := types.Config{Importer: importer.Default()}
+	_, err = config.Check("api_test", fset, []*ast.File{file}, nil)
+	assert.Nil(r.T(), err, buf.String())
+}
+
+func TestTestGenSuite(t *testing.T) {
+	suite.Run(t, new(TestGenSuite))
+}
@@ -0,0 +1,207 @@
+package oas
+
+import (
+	"sort"
+
+	"github.com/pkg/errors"
+)
+
+// Specification extensions of traits.
+const (
+	// TraitsExtension names the extension of the document declaring the
+	// traits by name.
+	TraitsExtension = "x-traits"
+
+	// ApplyTraitsExtension names the extension of an operation listing the
+	// names of the traits applied to it.
+	ApplyTraitsExtension = "x-apply-traits"
+)
+
+// Trait describes a named fragment shared by many operations, such as
+// common parameters, error responses or security requirements, expanded into
+// the operations applying it by ExpandTraits.
+type Trait struct {
+	// Parameters describes the parameters added to the operations. They may
+	// be references to the parameters of the components.
+	Parameters []*Parameter `json:"parameters,omitempty" yaml:"parameters,omitempty"`
+
+	// Responses describes the responses added to the operations, keyed by
+	// status code.
+	Responses map[string]*Response `json:"responses,omitempty" yaml:"responses,omitempty"`
+
+	// Security describes the security requirements of the operations.
+	Security []*SecurityRequirement `json:"security,omitempty" yaml:"security,omitempty"`
+
+	// Tags describes the tags added to the operations.
+	Tags []string `json:"tags,omitempty" yaml:"tags,omitempty"`
+}
+
+// Traits returns the traits declared by the x-traits extension, or nil if
+// the extension is absent.
+func (r Extensions) Traits() (map[string]*Trait, error) {
+	traits := map[string]*Trait{}
+	ok, err := r.decode(TraitsExtension, &traits)
+	if !ok || err != nil {
+		return nil, err
+	}
+	for name, trait := range traits {
+		if trait == nil {
+			return nil, errors.Errorf("%s: trait %q is empty", TraitsExtension, name)
+		}
+	}
+	return traits, nil
+}
+
+// SetTraits declares the traits with the x-traits extension, or removes the
+// extension when empty.
+func (r *Extensions) SetTraits(traits map[string]*Trait) {
+	if len(traits) == 0 {
+		delete(*r, TraitsExtension)
+		return
+	}
+	setExtension(r, TraitsExtension, traits)
+}
+
+// AppliedTraits returns the names of the traits listed by the x-apply-traits
+// extension, or nil if the extension is absent.
+func (r Extensions) AppliedTraits() ([]string, error) {
+	names := make([]string, 0)
+	ok, err := r.decode(ApplyTraitsExtension, &names)
+	if !ok || err != nil {
+		return nil, err
+	}
+	return names, nil
+}
+
+// SetAppliedTraits lists the names of the traits with the x-apply-traits
+// extension, or removes the extension when empty.
+func (r *Extensions) SetAppliedTraits(names []string) {
+	if len(names) == 0 {
+		delete(*r, ApplyTraitsExtension)
+		return
+	}
+	setExtension(r, ApplyTraitsExtension, names)
+}
+
+// ApplyTrait applies the named trait of the document to the operation under
+// the path and lowercase method by listing it in the x-apply-traits extension
+// of the operation, once.
+func (r *OpenAPI) ApplyTrait(path string, method string, name string) error {
+	traits, err := r.Extensions.Traits()
+	if err != nil {
+		return err
+	}
+	if traits[name] == nil {
+		return errors.Errorf("trait %q not found", name)
+	}
+	op, err := r.Paths.operation(path, method)
+	if err != nil {
+		return err
+	}
+	names, err := op.operation.Extensions.AppliedTraits()
+	if err != nil {
+		return err
+	}
+	for _, applied := range names {
+		if applied == name {
+			return nil
+		}
+	}
+	op.operation.Extensions.SetAppliedTraits(append(names, name))
+	return nil
+}
+
+// ExpandTraits copies the traits listed by the x-apply-traits extension of
+// every operation into it, in the listed order, and removes the x-traits
+// and x-apply-traits extensions. Parameters are added unless the operation
+// or its path item declares a parameter of the same name and location,
+// responses unless the operation declares the status code, and tags unless
+// already listed. Security requirements are set on operations declaring
+// none. The pointers of the modified operations are returned in sorted
+// order.
+func (r *OpenAPI) ExpandTraits() ([]string, error) {
+	traits, err := r.Extensions.Traits()
+	if err != nil {
+		return nil, err
+	}
+
+	modified := make([]string, 0)
+	for _, op := range r.Paths.operations() {
+		ptr := join("/paths", op.path, op.method)
+		names, err := op.operation.Extensions.AppliedTraits()
+		if err != nil {
+			return nil, errors.Wrap(err, ptr)
+		}
+		for _, name := range names {
+			trait := traits[name]
+			if trait == nil {
+				return nil, errors.Errorf("%s: trait %q not found", ptr, name)
+			}
+			if err := r.expandTrait(op, trait); err != nil {
+				return nil, errors.Wrap(err, ptr)
+			}
+		}
+		if names != nil {
+			op.operation.Extensions.SetAppliedTraits(nil)
+			modified = append(modified, ptr)
+		}
+	}
+	r.Extensions.SetTraits(nil)
+	sort.Strings(modified)
+	return modified, nil
+}
+
+// expandTrait copies the trait into the operation.
+func (r *OpenAPI) expandTrait(op pathOperation, trait *Trait) error {
+	declared := map[string]bool{}
+	for _, parameter := range append(append([]*Parameter{}, op.item.Parameters...), op.operation.Parameters...) {
+		if _, parameter = NewResolver(r).parameter("", parameter); parameter != nil {
+			declared[parameterKey(parameter)] = true
+		}
+	}
+	for _, parameter := range trait.Parameters {
+		_, resolved := NewResolver(r).parameter("", parameter)
+		if resolved == nil || declared[parameterKey(resolved)] {
+			continue
+		}
+		clone, err := parameter.Clone()
+		if err != nil {
+			return err
+		}
+		op.operation.Parameters = append(op.operation.Parameters, clone)
+		declared[parameterKey(resolved)] = true
+	}
+
+	for _, code := range sortedKeys(trait.Responses) {
+		if _, ok := op.operation.Responses.Lookup(code); ok || trait.Responses[code] == nil {
+			continue
+		}
+		clone, err := trait.Responses[code].Clone()
+		if err != nil {
+			return err
+		}
+		op.operation.Responses.Set(code, clone)
+	}
+
+	if op.operation.Security == nil && trait.Security != nil {
+		op.operation.Security = make([]*SecurityRequirement, 0, len(trait.Security))
+		for _, requirement := range trait.Security {
+			clone, err := requirement.Clone()
+			if err != nil {
+				return err
+			}
+			op.operation.Security = append(op.operation.Security, clone)
+		}
+	}
+
+	for _, tag := range trait.Tags {
+		found := false
+		for _, existing := range op.operation.Tags {
+			found = found || existing == tag
+		}
+		if !found {
+			op.operation.Tags = append(op.operation.Tags, tag)
+		}
+	}
+	return nil
+}
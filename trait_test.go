@@ -0,0 +1,105 @@
+package oas
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+)
+
+type TraitSuite struct {
+	suite.Suite
+}
+
+// traitPatch is the patch of the petstore fixture the suite expands traits of.
+const traitPatch = `
+x-traits:
+  pageable:
+    parameters:
+      - name: limit
+        in: query
+        schema:
+          type: integer
+    tags: [paged]
+  secured:
+    security:
+      - bearerAuth: []
+    responses:
+      "401":
+        description: Unauthorized.
+paths:
+  /pets:
+    get:
+      x-apply-traits: [pageable, secured]
+      tags: [pets]
+    post:
+      x-apply-traits: [secured]
+      security: []
+      responses:
+        "401":
+          description: Not logged in.
+`
+
+func (r *TraitSuite) TestTraits() {
+	traits, err := petstore(r.T(), traitPatch).Extensions.Traits()
+	assert.Nil(r.T(), err)
+	assert.Equal(r.T(), []string{"pageable", "secured"}, sortedStrings(traits))
+	assert.Equal(r.T(), "limit", traits["pageable"].Parameters[0].Name)
+
+	extensions := Extensions{}
+	extensions.SetTraits(traits)
+	decoded, err := extensions.Traits()
+	assert.Nil(r.T(), err)
+	assert.Equal(r.T(), traits, decoded)
+	extensions.SetTraits(nil)
+	assert.Empty(r.T(), extensions)
+
+	_, err = Extensions{TraitsExtension: map[string]interface{}{"empty": nil}}.Traits()
+	assert.NotNil(r.T(), err)
+}
+
+func (r *TraitSuite) TestApplyTrait() {
+	doc := petstore(r.T(), traitPatch)
+	assert.Nil(r.T(), doc.ApplyTrait("/pets/{petId}", "delete", "secured"))
+	assert.Nil(r.T(), doc.ApplyTrait("/pets/{petId}", "delete", "secured"))
+	names, err := doc.Paths.PathItems.Get("/pets/{petId}").Delete.Extensions.AppliedTraits()
+	assert.Nil(r.T(), err)
+	assert.Equal(r.T(), []string{"secured"}, names)
+
+	assert.NotNil(r.T(), doc.ApplyTrait("/pets/{petId}", "delete", "cached"))
+	assert.NotNil(r.T(), doc.ApplyTrait("/pets", "put", "secured"))
+}
+
+func (r *TraitSuite) TestExpandTraits() {
+	doc := petstore(r.T(), traitPatch)
+	modified, err := doc.ExpandTraits()
+	assert.Nil(r.T(), err)
+	assert.Equal(r.T(), []string{"/paths/~1pets/get", "/paths/~1pets/post"}, modified)
+	assert.Nil(r.T(), doc.Extensions[TraitsExtension])
+
+	list := doc.Paths.PathItems.Get("/pets").Get
+	assert.Nil(r.T(), list.Extensions[ApplyTraitsExtension])
+	assert.Equal(r.T(), "limit", list.Parameters[0].Name)
+	assert.Equal(r.T(), []string{"pets", "paged"}, list.Tags)
+	assert.Equal(r.T(), []*SecurityRequirement{{"bearerAuth": []string{}}}, list.Security)
+	assert.Equal(r.T(), "Unauthorized.", list.Responses.Get("401").Description)
+
+	create := doc.Paths.PathItems.Get("/pets").Post
+	assert.Equal(r.T(), []*SecurityRequirement{}, create.Security)
+	assert.Equal(r.T(), "Not logged in.", create.Responses.Get("401").Description)
+
+	assert.Nil(r.T(), doc.Paths.PathItems.Get("/pets/{petId}").Delete.Security)
+
+	doc = petstore(r.T(), traitPatch, `
+paths:
+  /pets/{petId}:
+    delete:
+      x-apply-traits: [cached]
+`)
+	_, err = doc.ExpandTraits()
+	assert.NotNil(r.T(), err)
+}
+
+func TestTraitSuite(t *testing.T) {
+	suite.Run(t, new(TraitSuite))
+}
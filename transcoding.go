@@ -0,0 +1,141 @@
+package oas
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+const (
+	// GRPCServiceExtension names the fully qualified gRPC service (e.g.
+	// "petstore.v1.PetService") an operation is served by. It may be declared
+	// on the operation, on the path item or on the document root, the closest
+	// declaration taking precedence.
+	GRPCServiceExtension = "x-grpc-service"
+
+	// GRPCMethodExtension names the gRPC method of the service an operation
+	// is transcoded to. Operations without the extension are not transcoded.
+	GRPCMethodExtension = "x-grpc-method"
+)
+
+// HTTPRule describes the mapping of a gRPC method onto an HTTP endpoint. It
+// follows the google.api.HttpRule format consumed by gRPC-JSON transcoders
+// such as the one built into Envoy and can be marshalled directly into the
+// http.rules section of a gRPC service configuration.
+type HTTPRule struct {
+	// Selector describes the fully qualified name of the gRPC method.
+	Selector string `json:"selector,omitempty" yaml:"selector,omitempty"`
+
+	// Get describes the path template of a GET endpoint.
+	Get string `json:"get,omitempty" yaml:"get,omitempty"`
+
+	// Put describes the path template of a PUT endpoint.
+	Put string `json:"put,omitempty" yaml:"put,omitempty"`
+
+	// Post describes the path template of a POST endpoint.
+	Post string `json:"post,omitempty" yaml:"post,omitempty"`
+
+	// Delete describes the path template of a DELETE endpoint.
+	Delete string `json:"delete,omitempty" yaml:"delete,omitempty"`
+
+	// Patch describes the path template of a PATCH endpoint.
+	Patch string `json:"patch,omitempty" yaml:"patch,omitempty"`
+
+	// Custom describes an endpoint whose HTTP method has no dedicated field.
+	Custom *CustomHTTPPattern `json:"custom,omitempty" yaml:"custom,omitempty"`
+
+	// Body describes the request message field the request body is mapped
+	// to. The value "*" maps the whole body onto the request message.
+	Body string `json:"body,omitempty" yaml:"body,omitempty"`
+
+	// AdditionalBindings describes additional endpoints served by the same
+	// gRPC method.
+	AdditionalBindings []*HTTPRule `json:"additional_bindings,omitempty" yaml:"additional_bindings,omitempty"`
+}
+
+// CustomHTTPPattern describes an HTTP method and path template pair for
+// methods without a dedicated HTTPRule field.
+type CustomHTTPPattern struct {
+	// Kind describes the uppercase HTTP method.
+	Kind string `json:"kind" yaml:"kind"`
+
+	// Path describes the path template.
+	Path string `json:"path" yaml:"path"`
+}
+
+// TranscodingRules returns the HTTP rules of every operation annotated with
+// the x-grpc-method extension, sorted by selector. Operations transcoded to
+// an already mapped method become additional bindings of its rule. Paths are
+// used as is since OpenAPI path templates share the {field} syntax of HTTP
+// rule templates. Operations carrying a request body map the whole body onto
+// the request message.
+func (r OpenAPI) TranscodingRules() ([]*HTTPRule, error) {
+	rules := map[string]*HTTPRule{}
+	rootService, _ := r.Extensions.stringValue(GRPCServiceExtension)
+
+	for _, path := range sortedKeys(r.Paths.PathItems) {
+		item := r.Paths.PathItems.Get(path)
+		if item == nil {
+			continue
+		}
+		itemService, ok := item.Extensions.stringValue(GRPCServiceExtension)
+		if !ok {
+			itemService = rootService
+		}
+
+		for _, op := range item.operations() {
+			method, ok := op.operation.Extensions.stringValue(GRPCMethodExtension)
+			if !ok {
+				continue
+			}
+			service, ok := op.operation.Extensions.stringValue(GRPCServiceExtension)
+			if !ok {
+				service = itemService
+			}
+			if service == "" {
+				return nil, errors.Errorf("%s: missing %s for method %q",
+					join("", "paths", path, op.method), GRPCServiceExtension, method)
+			}
+
+			rule := &HTTPRule{}
+			switch op.method {
+			case "get":
+				rule.Get = path
+			case "put":
+				rule.Put = path
+			case "post":
+				rule.Post = path
+			case "delete":
+				rule.Delete = path
+			case "patch":
+				rule.Patch = path
+			default:
+				rule.Custom = &CustomHTTPPattern{Kind: strings.ToUpper(op.method), Path: path}
+			}
+			if op.operation.RequestBody != nil {
+				rule.Body = "*"
+			}
+
+			selector := service + "." + method
+			if primary, ok := rules[selector]; ok {
+				primary.AdditionalBindings = append(primary.AdditionalBindings, rule)
+				continue
+			}
+			rule.Selector = selector
+			rules[selector] = rule
+		}
+	}
+
+	selectors := make([]string, 0, len(rules))
+	for selector := range rules {
+		selectors = append(selectors, selector)
+	}
+	sort.Strings(selectors)
+
+	result := make([]*HTTPRule, len(selectors))
+	for i, selector := range selectors {
+		result[i] = rules[selector]
+	}
+	return result, nil
+}
@@ -0,0 +1,99 @@
+package oas
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+)
+
+type TranscodingSuite struct {
+	suite.Suite
+}
+
+func (r *TranscodingSuite) TestTranscodingRules() {
+	testCases := []struct {
+		shouldFail bool
+		input      *OpenAPI
+		expected   []*HTTPRule
+	}{
+		{
+			false,
+			&OpenAPI{
+				Paths: Paths{
+					PathItems: NewPathItems(map[string]*PathItem{
+						"/v1/pets": {
+							Get: &Operation{
+								Extensions: Extensions{GRPCMethodExtension: "ListPets"},
+							},
+							Post: &Operation{
+								RequestBody: &RequestBody{},
+								Extensions:  Extensions{GRPCMethodExtension: "CreatePet"},
+							},
+						},
+						"/v1/pets/{pet_id}": {
+							Get: &Operation{
+								Extensions: Extensions{GRPCMethodExtension: "GetPet"},
+							},
+							Head: &Operation{
+								Extensions: Extensions{GRPCMethodExtension: "GetPet"},
+							},
+							Delete: &Operation{},
+						},
+						"/v1/owners": {
+							Get: &Operation{
+								Extensions: Extensions{
+									GRPCServiceExtension: "petstore.v1.OwnerService",
+									GRPCMethodExtension:  "ListOwners",
+								},
+							},
+						},
+					}),
+				},
+				Extensions: Extensions{GRPCServiceExtension: "petstore.v1.PetService"},
+			},
+			[]*HTTPRule{
+				{Selector: "petstore.v1.OwnerService.ListOwners", Get: "/v1/owners"},
+				{Selector: "petstore.v1.PetService.CreatePet", Post: "/v1/pets", Body: "*"},
+				{
+					Selector: "petstore.v1.PetService.GetPet",
+					Get:      "/v1/pets/{pet_id}",
+					AdditionalBindings: []*HTTPRule{
+						{Custom: &CustomHTTPPattern{Kind: "HEAD", Path: "/v1/pets/{pet_id}"}},
+					},
+				},
+				{Selector: "petstore.v1.PetService.ListPets", Get: "/v1/pets"},
+			},
+		},
+		{
+			true,
+			&OpenAPI{
+				Paths: Paths{
+					PathItems: NewPathItems(map[string]*PathItem{
+						"/v1/pets": {
+							Get: &Operation{
+								Extensions: Extensions{GRPCMethodExtension: "ListPets"},
+							},
+						},
+					}),
+				},
+			},
+			nil,
+		},
+	}
+
+	for i, testCase := range testCases {
+		failMsg := fmt.Sprintf("testCase: %d %v", i, testCase)
+
+		actual, err := testCase.input.TranscodingRules()
+		if (err != nil) != testCase.shouldFail {
+			assert.Fail(r.T(), failMsg, err)
+		}
+		assert.EqualValues(r.T(), testCase.expected, actual, failMsg)
+	}
+}
+
+func TestTranscodingSuite(t *testing.T) {
+	suite.Run(t, new(TranscodingSuite))
+}
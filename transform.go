@@ -0,0 +1,282 @@
+package oas
+
+import (
+	"context"
+	"encoding/json"
+	"reflect"
+	"sort"
+	"strconv"
+
+	"github.com/pkg/errors"
+)
+
+// Transform describes a pass rewriting a document in place.
+type Transform func(doc *OpenAPI) error
+
+// TransformReport describes the outcome of a single pipeline step.
+type TransformReport struct {
+	// Name describes the name the step was added to the pipeline under.
+	Name string
+
+	// Changes describes the JSON Pointers of the document locations that were
+	// added, removed or modified by the step, in sorted order.
+	Changes []string
+}
+
+// Pipeline runs an ordered sequence of transforms over a document.
+type Pipeline struct {
+	// DryRun runs the transforms and produces the reports without modifying
+	// the document passed to Run.
+	DryRun bool
+
+	steps []pipelineStep
+}
+
+// pipelineStep holds a named transform.
+type pipelineStep struct {
+	name      string
+	transform Transform
+}
+
+// Add appends a named transform to the pipeline and returns the pipeline so
+// calls can be chained.
+func (r *Pipeline) Add(name string, transform Transform) *Pipeline {
+	r.steps = append(r.steps, pipelineStep{name: name, transform: transform})
+	return r
+}
+
+// Run applies the transforms in the order they were added and returns a
+// report per step. The transforms operate on a copy of the document which
+// replaces the document only once every step succeeded, so a failing step
+// leaves the document untouched. The reports of the steps which completed
+// are returned along with the error.
+func (r *Pipeline) Run(doc *OpenAPI) ([]*TransformReport, error) {
+	reports := make([]*TransformReport, 0, len(r.steps))
+
+	work, err := doc.Clone()
+	if err != nil {
+		return reports, err
+	}
+
+	before, err := genericValue(work)
+	if err != nil {
+		return reports, err
+	}
+
+	for _, step := range r.steps {
+		if err := step.transform(work); err != nil {
+			return reports, errors.Wrap(err, step.name)
+		}
+
+		after, err := genericValue(work)
+		if err != nil {
+			return reports, errors.Wrap(err, step.name)
+		}
+
+		changes := make([]string, 0)
+		diffValues("", before, after, &changes)
+		sort.Strings(changes)
+		reports = append(reports, &TransformReport{Name: step.name, Changes: changes})
+		before = after
+	}
+
+	if !r.DryRun {
+		*doc = *work
+	}
+	return reports, nil
+}
+
+// NormalizeNamesTransform returns a transform running NormalizeNames.
+func NormalizeNamesTransform(opts NamingOptions) Transform {
+	return func(doc *OpenAPI) error {
+		return doc.NormalizeNames(opts)
+	}
+}
+
+// AnnotateTransform returns a transform running Annotate.
+func AnnotateTransform() Transform {
+	return func(doc *OpenAPI) error {
+		doc.Annotate()
+		return nil
+	}
+}
+
+// LiftSchemasTransform returns a transform running LiftSchemas.
+func LiftSchemasTransform(opts LiftOptions) Transform {
+	return func(doc *OpenAPI) error {
+		doc.LiftSchemas(opts)
+		return nil
+	}
+}
+
+// InlineSchemasTransform returns a transform running InlineSchemas.
+func InlineSchemasTransform(opts InlineOptions) Transform {
+	return func(doc *OpenAPI) error {
+		_, err := doc.InlineSchemas(opts)
+		return err
+	}
+}
+
+// EmbedExternalValuesTransform returns a transform running
+// EmbedExternalValues.
+func EmbedExternalValuesTransform(ctx context.Context, opts ExternalValueOptions) Transform {
+	return func(doc *OpenAPI) error {
+		return doc.EmbedExternalValues(ctx, opts)
+	}
+}
+
+// ApplyBoundsTransform returns a transform running ApplyBounds.
+func ApplyBoundsTransform(opts BoundsOptions) Transform {
+	return func(doc *OpenAPI) error {
+		doc.ApplyBounds(opts)
+		return nil
+	}
+}
+
+// DeclareConditionalRequestsTransform returns a transform running
+// DeclareConditionalRequests.
+func DeclareConditionalRequestsTransform(opts ConditionalOptions) Transform {
+	return func(doc *OpenAPI) error {
+		doc.DeclareConditionalRequests(opts)
+		return nil
+	}
+}
+
+// SynthesizeMethodsTransform returns a transform running SynthesizeMethods.
+func SynthesizeMethodsTransform(opts SynthesizeOptions) Transform {
+	return func(doc *OpenAPI) error {
+		_, err := doc.SynthesizeMethods(opts)
+		return err
+	}
+}
+
+// DocumentNegativeResponsesTransform returns a transform running
+// DocumentNegativeResponses.
+func DocumentNegativeResponsesTransform() Transform {
+	return func(doc *OpenAPI) error {
+		doc.DocumentNegativeResponses()
+		return nil
+	}
+}
+
+// FilterLifecycleTransform returns a transform running FilterLifecycle.
+func FilterLifecycleTransform(allowed ...Lifecycle) Transform {
+	return func(doc *OpenAPI) error {
+		doc.FilterLifecycle(allowed...)
+		return nil
+	}
+}
+
+// RewritePathPrefixTransform returns a transform running RewritePathPrefix.
+func RewritePathPrefixTransform(opts PathPrefixOptions) Transform {
+	return func(doc *OpenAPI) error {
+		return doc.RewritePathPrefix(opts)
+	}
+}
+
+// InjectFieldsTransform returns a transform running InjectFields.
+func InjectFieldsTransform(injection *FieldInjection) Transform {
+	return func(doc *OpenAPI) error {
+		_, err := doc.InjectFields(injection)
+		return err
+	}
+}
+
+// ApplyExamplesTransform returns a transform running ApplyExamples.
+func ApplyExamplesTransform(store ExampleStore) Transform {
+	return func(doc *OpenAPI) error {
+		doc.ApplyExamples(store)
+		return nil
+	}
+}
+
+// DeclareBatchTransform returns a transform running DeclareBatch.
+func DeclareBatchTransform(opts BatchOptions) Transform {
+	return func(doc *OpenAPI) error {
+		return doc.DeclareBatch(opts)
+	}
+}
+
+// ExpandTraitsTransform returns a transform running ExpandTraits.
+func ExpandTraitsTransform() Transform {
+	return func(doc *OpenAPI) error {
+		_, err := doc.ExpandTraits()
+		return err
+	}
+}
+
+// ResolveProfilesTransform returns a transform running ResolveProfiles.
+func ResolveProfilesTransform(profiles ...string) Transform {
+	return func(doc *OpenAPI) error {
+		_, err := doc.ResolveProfiles(profiles...)
+		return err
+	}
+}
+
+// AnonymizeTransform returns a transform running Anonymize, storing the
+// mapping reversing the renames into mapping.
+func AnonymizeTransform(mapping *Anonymization) Transform {
+	return func(doc *OpenAPI) error {
+		result, err := doc.Anonymize()
+		if err != nil {
+			return err
+		}
+		*mapping = *result
+		return nil
+	}
+}
+
+// MinifyTransform returns a transform running Minify.
+func MinifyTransform(opts MinifyOptions) Transform {
+	return func(doc *OpenAPI) error {
+		doc.Minify(opts)
+		return nil
+	}
+}
+
+// genericValue returns the JSON encoding of the value decoded into generic
+// maps, slices and scalars.
+func genericValue(value interface{}) (interface{}, error) {
+	rbytes, err := json.Marshal(value)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+	var result interface{}
+	if err := json.Unmarshal(rbytes, &result); err != nil {
+		return nil, errors.WithStack(err)
+	}
+	return result, nil
+}
+
+// diffValues appends the pointers of the locations where the generic values
+// differ. Differences are reported at the outermost differing location.
+func diffValues(ptr string, a interface{}, b interface{}, changes *[]string) {
+	switch a := a.(type) {
+	case map[string]interface{}:
+		if b, ok := b.(map[string]interface{}); ok {
+			for key, value := range a {
+				if other, ok := b[key]; ok {
+					diffValues(join(ptr, key), value, other, changes)
+				} else {
+					*changes = append(*changes, join(ptr, key))
+				}
+			}
+			for key := range b {
+				if _, ok := a[key]; !ok {
+					*changes = append(*changes, join(ptr, key))
+				}
+			}
+			return
+		}
+	case []interface{}:
+		if b, ok := b.([]interface{}); ok && len(a) == len(b) {
+			for i := range a {
+				diffValues(join(ptr, strconv.Itoa(i)), a[i], b[i], changes)
+			}
+			return
+		}
+	}
+	if !reflect.DeepEqual(a, b) {
+		*changes = append(*changes, ptr)
+	}
+}
@@ -0,0 +1,110 @@
+package oas
+
+import (
+	"testing"
+
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+)
+
+type TransformSuite struct {
+	suite.Suite
+}
+
+func (r *TransformSuite) transformDocument() *OpenAPI {
+	return &OpenAPI{
+		OpenAPI: "3.0.0",
+		Info:    Info{Title: "Petstore", Version: "1.0.0"},
+		Paths: Paths{
+			PathItems: NewPathItems(map[string]*PathItem{
+				"/pets": {
+					Get: &Operation{
+						Responses: NewResponses(map[string]*Response{
+							"200": {
+								Description: "A pet.",
+								Content: map[string]*MediaType{
+									"application/json": {
+										Schema: &Schema{Ref: "#/components/schemas/pet_owner"},
+									},
+								},
+							},
+						}),
+					},
+				},
+			}),
+		},
+		Components: &Components{
+			Schemas: map[string]*Schema{
+				"pet_owner": {
+					Type: "object",
+					Properties: NewProperties(map[string]*Schema{
+						"name": {Type: "string"},
+					}),
+				},
+			},
+		},
+	}
+}
+
+func (r *TransformSuite) TestRun() {
+	doc := r.transformDocument()
+	pipeline := &Pipeline{}
+	pipeline.
+		Add("normalize", NormalizeNamesTransform(NamingOptions{Schemas: PascalCase})).
+		Add("annotate", AnnotateTransform()).
+		Add("noop", func(doc *OpenAPI) error { return nil })
+
+	reports, err := pipeline.Run(doc)
+	assert.NoError(r.T(), err)
+	assert.Equal(r.T(), []*TransformReport{
+		{
+			Name: "normalize",
+			Changes: []string{
+				"/components/schemas/PetOwner",
+				"/components/schemas/pet_owner",
+				"/paths/~1pets/get/responses/200/content/application~1json/schema/$ref",
+			},
+		},
+		{
+			Name: "annotate",
+			Changes: []string{
+				"/components/schemas/PetOwner/properties/name/title",
+				"/components/schemas/PetOwner/title",
+			},
+		},
+		{Name: "noop", Changes: []string{}},
+	}, reports)
+	assert.Contains(r.T(), doc.Components.Schemas, "PetOwner")
+	assert.Equal(r.T(), "Pet Owner", doc.Components.Schemas["PetOwner"].Title)
+}
+
+func (r *TransformSuite) TestDryRun() {
+	doc := r.transformDocument()
+	pipeline := &Pipeline{DryRun: true}
+	pipeline.Add("annotate", AnnotateTransform())
+
+	reports, err := pipeline.Run(doc)
+	assert.NoError(r.T(), err)
+	assert.Len(r.T(), reports, 1)
+	assert.Len(r.T(), reports[0].Changes, 2)
+	assert.Equal(r.T(), r.transformDocument(), doc)
+}
+
+func (r *TransformSuite) TestFailure() {
+	doc := r.transformDocument()
+	failure := errors.New("failure")
+	pipeline := &Pipeline{}
+	pipeline.
+		Add("annotate", AnnotateTransform()).
+		Add("fail", func(doc *OpenAPI) error { return failure })
+
+	reports, err := pipeline.Run(doc)
+	assert.Equal(r.T(), failure, errors.Cause(err))
+	assert.Len(r.T(), reports, 1)
+	assert.Equal(r.T(), r.transformDocument(), doc)
+}
+
+func TestTransformSuite(t *testing.T) {
+	suite.Run(t, new(TransformSuite))
+}
@@ -0,0 +1,187 @@
+package oas
+
+import (
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// UnionStrategyExtension names the specification extension overriding, for
+// a single oneOf or anyOf schema, how a value is matched to a member.
+const UnionStrategyExtension = "x-union-strategy"
+
+// UnionStrategy describes how the member of a oneOf or anyOf schema a value
+// belongs to is selected. Validators, type generators and example
+// generators select members through SelectUnionMember so they agree.
+type UnionStrategy string
+
+// Union strategies.
+const (
+	// UnionTagged selects the member named by the value of the discriminator
+	// property, resolved through the discriminator mapping.
+	UnionTagged UnionStrategy = "tagged"
+
+	// UnionBestMatch selects, among the members the value conforms to, the
+	// member declaring most of the properties of the value, the first one in
+	// order on ties.
+	UnionBestMatch UnionStrategy = "best-match"
+
+	// UnionPassthrough selects no member, the value is passed through as is.
+	UnionPassthrough UnionStrategy = "passthrough"
+)
+
+// validate checks that the strategy is known.
+func (r UnionStrategy) validate() error {
+	switch r {
+	case UnionTagged, UnionBestMatch, UnionPassthrough:
+		return nil
+	}
+	return errors.Errorf("unknown union strategy %q", r)
+}
+
+// UnionStrategy returns the strategy declared by the x-union-strategy
+// extension, or an empty strategy if the extension is absent.
+func (r Extensions) UnionStrategy() (UnionStrategy, error) {
+	var strategy UnionStrategy
+	ok, err := r.decode(UnionStrategyExtension, &strategy)
+	if !ok || err != nil {
+		return "", err
+	}
+	if err := strategy.validate(); err != nil {
+		return "", errors.Wrap(err, UnionStrategyExtension)
+	}
+	return strategy, nil
+}
+
+// SetUnionStrategy declares the strategy with the x-union-strategy
+// extension, or removes the extension when empty.
+func (r *Extensions) SetUnionStrategy(strategy UnionStrategy) {
+	if strategy == "" {
+		delete(*r, UnionStrategyExtension)
+		return
+	}
+	setExtension(r, UnionStrategyExtension, string(strategy))
+}
+
+// UnionOf returns the strategy applying to the oneOf or anyOf schema: the
+// strategy of its x-union-strategy extension, UnionTagged when it declares
+// a discriminator and the fallback otherwise, UnionBestMatch when the
+// fallback is empty. An error is returned when the schema is not a union or
+// when UnionTagged applies to a schema without a discriminator.
+func (r OpenAPI) UnionOf(schema *Schema, fallback UnionStrategy) (UnionStrategy, error) {
+	schema = NewResolver(&r).schema(schema)
+	if schema == nil || len(unionMembers(schema)) == 0 {
+		return "", errors.New("schema is not a oneOf or anyOf union")
+	}
+	strategy, err := schema.Extensions.UnionStrategy()
+	switch {
+	case err != nil:
+		return "", err
+	case strategy != "":
+	case schema.Discriminator != nil:
+		strategy = UnionTagged
+	case fallback != "":
+		if err := fallback.validate(); err != nil {
+			return "", err
+		}
+		strategy = fallback
+	default:
+		strategy = UnionBestMatch
+	}
+	if strategy == UnionTagged && (schema.Discriminator == nil || schema.Discriminator.PropertyName == "") {
+		return "", errors.New("tagged union declares no discriminator")
+	}
+	return strategy, nil
+}
+
+// SelectUnionMember returns the member of the oneOf or anyOf schema the value
+// belongs to according to the strategy UnionOf returns, or nil for
+// UnionPassthrough. UnionBestMatch checks the members with validate, or
+// with ValidatePayload when nil. An error is returned when no member can be
+// selected.
+func (r OpenAPI) SelectUnionMember(schema *Schema, value interface{}, fallback UnionStrategy, validate PayloadValidator) (*Schema, error) {
+	strategy, err := r.UnionOf(schema, fallback)
+	if err != nil {
+		return nil, err
+	}
+	schema = NewResolver(&r).schema(schema)
+	members := unionMembers(schema)
+
+	switch strategy {
+	case UnionTagged:
+		property := schema.Discriminator.PropertyName
+		object, _ := value.(map[string]interface{})
+		tag, ok := object[property].(string)
+		if !ok {
+			return nil, errors.Errorf("missing discriminator property %q", property)
+		}
+		ref := schema.Discriminator.Mapping[tag]
+		if ref == "" {
+			ref = tag
+		}
+		if !strings.Contains(ref, "/") {
+			ref = "#/components/schemas/" + escapePointer(ref)
+		}
+		for _, member := range members {
+			if member != nil && member.Ref == ref {
+				return member, nil
+			}
+		}
+		return nil, errors.Errorf("discriminator value %q matches no member", tag)
+	case UnionBestMatch:
+		if validate == nil {
+			validate = ValidatePayload
+		}
+		var best *Schema
+		score := -1
+		for _, member := range members {
+			if member == nil || validate(&r, member, value) != nil {
+				continue
+			}
+			if declared := r.declaredProperties(member, value); declared > score {
+				best, score = member, declared
+			}
+		}
+		if best == nil {
+			return nil, errors.New("value matches no member")
+		}
+		return best, nil
+	}
+	return nil, nil
+}
+
+// declaredProperties returns the number of properties of the object value
+// the schema, or any schema it composes with allOf, declares.
+func (r OpenAPI) declaredProperties(schema *Schema, value interface{}) int {
+	object, ok := value.(map[string]interface{})
+	if !ok {
+		return 0
+	}
+	declared := map[string]bool{}
+	visited := map[*Schema]bool{}
+	pending := []*Schema{schema}
+	for len(pending) > 0 {
+		current := NewResolver(&r).schema(pending[0])
+		pending = pending[1:]
+		if current == nil || visited[current] {
+			continue
+		}
+		visited[current] = true
+		for _, name := range current.Properties.Keys() {
+			if _, ok := object[name]; ok {
+				declared[name] = true
+			}
+		}
+		pending = append(pending, current.AllOf...)
+	}
+	return len(declared)
+}
+
+// unionMembers returns the members of the oneOf or anyOf schema, oneOf
+// taking precedence when both are declared.
+func unionMembers(schema *Schema) []*Schema {
+	if len(schema.OneOf) > 0 {
+		return schema.OneOf
+	}
+	return schema.AnyOf
+}
@@ -0,0 +1,164 @@
+package oas
+
+import (
+	"testing"
+
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+)
+
+type UnionSuite struct {
+	suite.Suite
+}
+
+// unionPatch is the patch of the petstore fixture the suite selects union
+// members from, turning the pet into a union of cats and dogs.
+const unionPatch = `
+components:
+  schemas:
+    Pet:
+      type: null
+      required: null
+      properties: null
+      oneOf:
+        - $ref: "#/components/schemas/Cat"
+        - $ref: "#/components/schemas/Dog"
+      discriminator:
+        propertyName: kind
+        mapping:
+          doggo: "#/components/schemas/Dog"
+    Animal:
+      anyOf:
+        - $ref: "#/components/schemas/Cat"
+        - $ref: "#/components/schemas/Dog"
+    Raw:
+      anyOf:
+        - type: string
+        - type: object
+      x-union-strategy: passthrough
+    Cat:
+      type: object
+      properties:
+        kind:
+          type: string
+        lives:
+          type: integer
+    Dog:
+      allOf:
+        - $ref: "#/components/schemas/Cat"
+        - type: object
+          properties:
+            bark:
+              type: string
+`
+
+// validate accepts objects for object schemas and anything else otherwise,
+// which is enough to exercise member selection.
+func (r *UnionSuite) validate(doc *OpenAPI, schema *Schema, value interface{}) error {
+	if schema = NewResolver(doc).schema(schema); schema.Type == "object" || len(schema.AllOf) > 0 {
+		if _, ok := value.(map[string]interface{}); !ok {
+			return errors.New("expected an object")
+		}
+	}
+	return nil
+}
+
+func (r *UnionSuite) TestUnionStrategy() {
+	testCases := []struct {
+		extensions Extensions
+		expected   UnionStrategy
+		isErr      bool
+	}{
+		{Extensions{}, "", false},
+		{Extensions{UnionStrategyExtension: "best-match"}, UnionBestMatch, false},
+		{Extensions{UnionStrategyExtension: "closest"}, "", true},
+	}
+
+	for i, testCase := range testCases {
+		failMsg := "test case %d failed"
+		strategy, err := testCase.extensions.UnionStrategy()
+		if testCase.isErr {
+			assert.NotNil(r.T(), err, failMsg, i)
+			continue
+		}
+		assert.Nil(r.T(), err, failMsg, i)
+		assert.Equal(r.T(), testCase.expected, strategy, failMsg, i)
+	}
+
+	extensions := Extensions{}
+	extensions.SetUnionStrategy(UnionTagged)
+	assert.Equal(r.T(), Extensions{UnionStrategyExtension: "tagged"}, extensions)
+	extensions.SetUnionStrategy("")
+	assert.Empty(r.T(), extensions)
+}
+
+func (r *UnionSuite) TestUnionOf() {
+	testCases := []struct {
+		schema   string
+		fallback UnionStrategy
+		expected UnionStrategy
+		isErr    bool
+	}{
+		{"Pet", UnionPassthrough, UnionTagged, false},
+		{"Animal", "", UnionBestMatch, false},
+		{"Animal", UnionPassthrough, UnionPassthrough, false},
+		{"Animal", UnionTagged, "", true},
+		{"Animal", "closest", "", true},
+		{"Raw", UnionBestMatch, UnionPassthrough, false},
+		{"Cat", "", "", true},
+	}
+
+	for i, testCase := range testCases {
+		failMsg := "test case %d failed"
+		schema := &Schema{Ref: "#/components/schemas/" + testCase.schema}
+		strategy, err := petstore(r.T(), unionPatch).UnionOf(schema, testCase.fallback)
+		if testCase.isErr {
+			assert.NotNil(r.T(), err, failMsg, i)
+			continue
+		}
+		assert.Nil(r.T(), err, failMsg, i)
+		assert.Equal(r.T(), testCase.expected, strategy, failMsg, i)
+	}
+}
+
+func (r *UnionSuite) TestSelectUnionMember() {
+	testCases := []struct {
+		schema   string
+		value    interface{}
+		expected string
+		isErr    bool
+	}{
+		{"Pet", map[string]interface{}{"kind": "doggo"}, "#/components/schemas/Dog", false},
+		{"Pet", map[string]interface{}{"kind": "Cat"}, "#/components/schemas/Cat", false},
+		{"Pet", map[string]interface{}{"kind": "Bird"}, "", true},
+		{"Pet", map[string]interface{}{}, "", true},
+		{"Animal", map[string]interface{}{"lives": 9}, "#/components/schemas/Cat", false},
+		{"Animal", map[string]interface{}{"lives": 9, "bark": "woof"}, "#/components/schemas/Dog", false},
+		{"Animal", "cat", "", true},
+		{"Raw", "cat", "", false},
+	}
+
+	for i, testCase := range testCases {
+		failMsg := "test case %d failed"
+		schema := &Schema{Ref: "#/components/schemas/" + testCase.schema}
+		member, err := petstore(r.T(), unionPatch).SelectUnionMember(schema, testCase.value, "", r.validate)
+		if testCase.isErr {
+			assert.NotNil(r.T(), err, failMsg, i)
+			continue
+		}
+		assert.Nil(r.T(), err, failMsg, i)
+		if testCase.expected == "" {
+			assert.Nil(r.T(), member, failMsg, i)
+			continue
+		}
+		assert.Equal(r.T(), testCase.expected, member.Ref, failMsg, i)
+	}
+
+	_, err := petstore(r.T(), unionPatch).SelectUnionMember(&Schema{Ref: "#/components/schemas/Animal"}, "cat", "", nil)
+	assert.NotNil(r.T(), err)
+}
+
+func TestUnionSuite(t *testing.T) {
+	suite.Run(t, new(UnionSuite))
+}
@@ -0,0 +1,238 @@
+package oas
+
+import (
+	"mime"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// Rules of the findings reported by AuditUploads.
+const (
+	RuleUploadKeywordVersion   = "upload-keyword-version"
+	RuleUploadUnknownPart      = "upload-unknown-part"
+	RuleUploadBinaryInJSON     = "upload-binary-in-json"
+	RuleUploadMissingPartTypes = "upload-missing-part-types"
+)
+
+// FilePart describes a file part of a multipart/form-data request body.
+type FilePart struct {
+	// Name describes the name of the form field holding the file.
+	Name string
+
+	// Description describes the file.
+	Description string
+
+	// MediaTypes describes the accepted media types of the file, which may
+	// be media type ranges (e.g. "image/*"). Defaults to
+	// "application/octet-stream".
+	MediaTypes []string
+
+	// Required describes whether the file must be sent.
+	Required bool
+
+	// Multiple describes whether several files may be sent under the name.
+	Multiple bool
+
+	// Headers describes the headers of the part other than Content-Type,
+	// such as Content-Disposition.
+	Headers map[string]*Header
+}
+
+// UploadOptions describes the request body declared by DeclareUpload.
+type UploadOptions struct {
+	// Files describes the file parts of the body.
+	Files []*FilePart
+
+	// Fields describes the other form fields of the body, keyed by name.
+	Fields map[string]*Schema
+
+	// RequiredFields describes the names of the required form fields.
+	RequiredFields []string
+}
+
+// is31 reports whether the document declares version 3.1 of the
+// specification.
+func (r OpenAPI) is31() bool {
+	return strings.HasPrefix(r.OpenAPI, "3.1")
+}
+
+// BinarySchema returns the schema of raw binary contents of the media type,
+// using the keywords of the version of the document: the binary format in
+// 3.0 and the contentMediaType keyword in 3.1.
+func (r OpenAPI) BinarySchema(mediaType string) *Schema {
+	if r.is31() {
+		return &Schema{Type: "string", ContentMediaType: mediaType}
+	}
+	return &Schema{Type: "string", Format: "binary"}
+}
+
+// Base64Schema returns the schema of base64 encoded contents of the media
+// type, embedded e.g. in JSON bodies, using the keywords of the version of
+// the document: the byte format in 3.0 and the contentEncoding and
+// contentMediaType keywords in 3.1.
+func (r OpenAPI) Base64Schema(mediaType string) *Schema {
+	if r.is31() {
+		return &Schema{Type: "string", ContentEncoding: "base64", ContentMediaType: mediaType}
+	}
+	return &Schema{Type: "string", Format: "byte"}
+}
+
+// IsBinary reports whether the schema describes raw binary contents, in
+// either the 3.0 or the 3.1 form.
+func (r Schema) IsBinary() bool {
+	if r.Type != "string" {
+		return false
+	}
+	return r.Format == "binary" || (r.ContentMediaType != "" && r.ContentEncoding == "")
+}
+
+// DeclareUpload declares a multipart/form-data request body on the
+// operation under the path and lowercase method, holding the file parts and
+// form fields of the options. The accepted media types and the headers of
+// the file parts are declared with the encoding of the body. An error is
+// returned when the operation already declares a request body or when a
+// part is declared twice.
+func (r *OpenAPI) DeclareUpload(path string, method string, opts UploadOptions) error {
+	op, err := r.Paths.operation(path, method)
+	if err != nil {
+		return err
+	}
+	if op.operation.RequestBody != nil {
+		return errors.Errorf("operation %s %s already declares a request body", strings.ToUpper(method), path)
+	}
+
+	schema := &Schema{Type: "object"}
+	for _, name := range sortedKeys(opts.Fields) {
+		schema.Properties.Set(name, opts.Fields[name])
+	}
+	schema.Required = append(schema.Required, opts.RequiredFields...)
+	encoding := map[string]*Encoding{}
+	for _, part := range opts.Files {
+		if _, ok := schema.Properties.Lookup(part.Name); ok || part.Name == "" {
+			return errors.Errorf("invalid or duplicate part name %q", part.Name)
+		}
+		mediaTypes := part.MediaTypes
+		if len(mediaTypes) == 0 {
+			mediaTypes = []string{"application/octet-stream"}
+		}
+		file := r.BinarySchema("application/octet-stream")
+		if len(mediaTypes) == 1 {
+			file = r.BinarySchema(mediaTypes[0])
+		}
+		if part.Multiple {
+			file = &Schema{Type: "array", Items: file}
+		}
+		file.Description = part.Description
+		schema.Properties.Set(part.Name, file)
+		encoding[part.Name] = &Encoding{ContentType: strings.Join(mediaTypes, ", "), Headers: part.Headers}
+		if part.Required {
+			schema.Required = append(schema.Required, part.Name)
+		}
+	}
+
+	op.operation.RequestBody = &RequestBody{
+		Required: len(schema.Required) > 0,
+		Content: map[string]*MediaType{
+			"multipart/form-data": {Schema: schema, Encoding: encoding},
+		},
+	}
+	return nil
+}
+
+// AuditUploads flags file contents modeled inconsistently: binary keywords
+// of the other version of the specification (the binary and byte formats in
+// 3.1, contentMediaType and contentEncoding in 3.0), multipart encodings of
+// undeclared parts, multipart file parts without accepted media types and
+// raw binary properties of JSON bodies, which cannot carry them.
+func (r OpenAPI) AuditUploads() []*Finding {
+	findings := make([]*Finding, 0)
+	_ = walk(&r, func(ptr string, node interface{}) error {
+		switch node := node.(type) {
+		case *Schema:
+			if message := r.uploadKeywordVersion(node); message != "" {
+				findings = append(findings, &Finding{
+					Pointer:  ptr,
+					Rule:     RuleUploadKeywordVersion,
+					Severity: SeverityWarning,
+					Message:  message,
+				})
+			}
+		case *MediaType:
+			tokens := splitPointer(ptr)
+			mediaType, _, err := mime.ParseMediaType(tokens[len(tokens)-1])
+			if err != nil {
+				return nil
+			}
+			schema := NewResolver(&r).schema(node.Schema)
+			if schema == nil {
+				return nil
+			}
+			switch {
+			case strings.HasPrefix(mediaType, "multipart/"):
+				findings = append(findings, r.auditMultipart(ptr, node, schema)...)
+			case isJSONMediaType(mediaType):
+				for _, name := range sortedKeys(schema.Properties) {
+					property := NewResolver(&r).schema(schema.Properties.Get(name))
+					if property != nil && property.IsBinary() {
+						findings = append(findings, &Finding{
+							Pointer:  join(ptr, "schema", "properties", name),
+							Rule:     RuleUploadBinaryInJSON,
+							Severity: SeverityWarning,
+							Message:  "JSON body property " + name + " holds raw binary contents, use base64 encoding or multipart",
+						})
+					}
+				}
+			}
+		}
+		return nil
+	})
+	sortFindings(findings)
+	return findings
+}
+
+// uploadKeywordVersion returns why the schema uses binary keywords of the
+// other version of the specification, or an empty string.
+func (r OpenAPI) uploadKeywordVersion(schema *Schema) string {
+	if r.is31() && (schema.Format == "binary" || schema.Format == "byte") {
+		return "format " + schema.Format + " is replaced by contentMediaType and contentEncoding in OpenAPI 3.1"
+	}
+	if !r.is31() && (schema.ContentMediaType != "" || schema.ContentEncoding != "") {
+		return "contentMediaType and contentEncoding require OpenAPI 3.1, use format binary or byte"
+	}
+	return ""
+}
+
+// auditMultipart flags the encodings of undeclared parts and the file parts
+// without accepted media types of the multipart media type.
+func (r OpenAPI) auditMultipart(ptr string, media *MediaType, schema *Schema) []*Finding {
+	findings := make([]*Finding, 0)
+	for _, name := range sortedKeys(media.Encoding) {
+		if _, ok := schema.Properties.Lookup(name); !ok {
+			findings = append(findings, &Finding{
+				Pointer:  join(ptr, "encoding", name),
+				Rule:     RuleUploadUnknownPart,
+				Severity: SeverityError,
+				Message:  "encoding of undeclared part " + name,
+			})
+		}
+	}
+	for _, name := range sortedKeys(schema.Properties) {
+		property := NewResolver(&r).schema(schema.Properties.Get(name))
+		if property != nil && property.Type == "array" && property.Items != nil {
+			property = NewResolver(&r).schema(property.Items)
+		}
+		if property == nil || !property.IsBinary() || property.ContentMediaType != "" {
+			continue
+		}
+		if encoding := media.Encoding[name]; encoding == nil || encoding.ContentType == "" {
+			findings = append(findings, &Finding{
+				Pointer:  join(ptr, "schema", "properties", name),
+				Rule:     RuleUploadMissingPartTypes,
+				Severity: SeverityInfo,
+				Message:  "file part " + name + " declares no accepted media types",
+			})
+		}
+	}
+	return findings
+}
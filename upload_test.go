@@ -0,0 +1,144 @@
+package oas
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+)
+
+type UploadSuite struct {
+	suite.Suite
+}
+
+func (r *UploadSuite) document(version string) *OpenAPI {
+	return &OpenAPI{
+		OpenAPI: version,
+		Info:    Info{Title: "Petstore", Version: "1.0.0"},
+		Paths: Paths{
+			PathItems: NewPathItems(map[string]*PathItem{
+				"/pets/{petId}/photos": {
+					Post: &Operation{
+						OperationID: "uploadPhotos",
+						Responses:   NewResponses(map[string]*Response{"204": {Description: "Uploaded."}}),
+					},
+				},
+			}),
+		},
+	}
+}
+
+func (r *UploadSuite) TestBinarySchemas() {
+	doc := r.document("3.0.3")
+	assert.Equal(r.T(), &Schema{Type: "string", Format: "binary"}, doc.BinarySchema("image/png"))
+	assert.Equal(r.T(), &Schema{Type: "string", Format: "byte"}, doc.Base64Schema("image/png"))
+	assert.True(r.T(), doc.BinarySchema("image/png").IsBinary())
+	assert.False(r.T(), doc.Base64Schema("image/png").IsBinary())
+
+	doc = r.document("3.1.0")
+	assert.Equal(r.T(), &Schema{Type: "string", ContentMediaType: "image/png"}, doc.BinarySchema("image/png"))
+	assert.Equal(r.T(), &Schema{Type: "string", ContentEncoding: "base64", ContentMediaType: "image/png"}, doc.Base64Schema("image/png"))
+	assert.True(r.T(), doc.BinarySchema("image/png").IsBinary())
+	assert.False(r.T(), doc.Base64Schema("image/png").IsBinary())
+}
+
+func (r *UploadSuite) TestDeclareUpload() {
+	disposition := map[string]*Header{"Content-Disposition": {Schema: &Schema{Type: "string"}}}
+	opts := UploadOptions{
+		Files: []*FilePart{
+			{Name: "photo", Description: "The photo.", MediaTypes: []string{"image/png"}, Required: true, Headers: disposition},
+			{Name: "thumbnails", MediaTypes: []string{"image/png", "image/jpeg"}, Multiple: true},
+		},
+		Fields:         map[string]*Schema{"caption": {Type: "string"}},
+		RequiredFields: []string{"caption"},
+	}
+
+	doc := r.document("3.0.3")
+	assert.Nil(r.T(), doc.DeclareUpload("/pets/{petId}/photos", "post", opts))
+	assert.Equal(r.T(), &RequestBody{
+		Required: true,
+		Content: map[string]*MediaType{"multipart/form-data": {
+			Schema: &Schema{
+				Type:     "object",
+				Required: []string{"caption", "photo"},
+				Properties: NewProperties(map[string]*Schema{
+					"caption":    {Type: "string"},
+					"photo":      {Type: "string", Format: "binary", Description: "The photo."},
+					"thumbnails": {Type: "array", Items: &Schema{Type: "string", Format: "binary"}},
+				}),
+			},
+			Encoding: map[string]*Encoding{
+				"photo":      {ContentType: "image/png", Headers: disposition},
+				"thumbnails": {ContentType: "image/png, image/jpeg"},
+			},
+		}},
+	}, doc.Paths.PathItems.Get("/pets/{petId}/photos").Post.RequestBody)
+	assert.Equal(r.T(), []*Finding{}, doc.AuditUploads())
+	assert.NotNil(r.T(), doc.DeclareUpload("/pets/{petId}/photos", "post", opts))
+
+	doc = r.document("3.1.0")
+	assert.Nil(r.T(), doc.DeclareUpload("/pets/{petId}/photos", "post", opts))
+	properties := doc.Paths.PathItems.Get("/pets/{petId}/photos").Post.RequestBody.Content["multipart/form-data"].Schema.Properties
+	assert.Equal(r.T(), &Schema{Type: "string", ContentMediaType: "image/png", Description: "The photo."}, properties.Get("photo"))
+	assert.Equal(r.T(), &Schema{Type: "string", ContentMediaType: "application/octet-stream"}, properties.Get("thumbnails").Items)
+	assert.Equal(r.T(), []*Finding{}, doc.AuditUploads())
+
+	opts.Files = append(opts.Files, &FilePart{Name: "caption"})
+	assert.NotNil(r.T(), r.document("3.0.3").DeclareUpload("/pets/{petId}/photos", "post", opts))
+}
+
+func (r *UploadSuite) TestAuditUploads() {
+	doc := r.document("3.0.3")
+	doc.Paths.PathItems.Get("/pets/{petId}/photos").Post.RequestBody = &RequestBody{
+		Content: map[string]*MediaType{
+			"multipart/form-data": {
+				Schema: &Schema{Type: "object", Properties: NewProperties(map[string]*Schema{
+					"photo": {Type: "string", Format: "binary"},
+				})},
+				Encoding: map[string]*Encoding{"image": {ContentType: "image/png"}},
+			},
+			"application/json": {
+				Schema: &Schema{Type: "object", Properties: NewProperties(map[string]*Schema{
+					"photo": {Type: "string", Format: "binary"},
+					"data":  {Type: "string", ContentEncoding: "base64"},
+				})},
+			},
+		},
+	}
+	ptr := "/paths/~1pets~1{petId}~1photos/post/requestBody/content"
+	assert.Equal(r.T(), []*Finding{
+		{
+			Pointer:  ptr + "/application~1json/schema/properties/data",
+			Rule:     RuleUploadKeywordVersion,
+			Severity: SeverityWarning,
+			Message:  "contentMediaType and contentEncoding require OpenAPI 3.1, use format binary or byte",
+		},
+		{
+			Pointer:  ptr + "/application~1json/schema/properties/photo",
+			Rule:     RuleUploadBinaryInJSON,
+			Severity: SeverityWarning,
+			Message:  "JSON body property photo holds raw binary contents, use base64 encoding or multipart",
+		},
+		{
+			Pointer:  ptr + "/multipart~1form-data/encoding/image",
+			Rule:     RuleUploadUnknownPart,
+			Severity: SeverityError,
+			Message:  "encoding of undeclared part image",
+		},
+		{
+			Pointer:  ptr + "/multipart~1form-data/schema/properties/photo",
+			Rule:     RuleUploadMissingPartTypes,
+			Severity: SeverityInfo,
+			Message:  "file part photo declares no accepted media types",
+		},
+	}, doc.AuditUploads())
+
+	doc.OpenAPI = "3.1.0"
+	findings := doc.AuditUploads()
+	assert.Equal(r.T(), RuleUploadKeywordVersion, findings[1].Rule)
+	assert.Equal(r.T(), "format binary is replaced by contentMediaType and contentEncoding in OpenAPI 3.1", findings[1].Message)
+}
+
+func TestUploadSuite(t *testing.T) {
+	suite.Run(t, new(UploadSuite))
+}
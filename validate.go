@@ -0,0 +1,373 @@
+package oas
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// Rules of the violations reported by Validate.
+const (
+	RuleVersionInvalid        = "version-invalid"
+	RuleFieldRequired         = "field-required"
+	RuleFieldInvalid          = "field-invalid"
+	RuleFieldsExclusive       = "fields-exclusive"
+	RulePathInvalid           = "path-invalid"
+	RuleResponseStatusInvalid = "response-status-invalid"
+	RuleComponentNameInvalid  = "component-name-invalid"
+	RuleTagDuplicate          = "tag-duplicate"
+	RuleSecuritySchemeUnknown = "security-scheme-unknown"
+	RuleSecurityScopesInvalid = "security-scopes-invalid"
+)
+
+var (
+	// versionPattern matches the versions of the specification the package
+	// implements.
+	versionPattern = regexp.MustCompile(`^3\.\d+\.\d+$`)
+
+	// statusPattern matches the keys of the responses of an operation.
+	statusPattern = regexp.MustCompile(`^([1-5]\d\d|[1-5]XX|default)$`)
+
+	// componentNamePattern matches the names of reusable components.
+	componentNamePattern = regexp.MustCompile(`^[a-zA-Z0-9.\-_]+$`)
+)
+
+// schemaTypes lists the values of the type of a schema.
+var schemaTypes = map[string]bool{
+	"array":   true,
+	"boolean": true,
+	"integer": true,
+	"number":  true,
+	"object":  true,
+	"string":  true,
+}
+
+// ValidationError describes the violations of the specification found by
+// Validate.
+type ValidationError struct {
+	// Violations describes every violation, ordered by pointer.
+	Violations []*Finding
+}
+
+// Error returns the string representation of the error.
+func (e *ValidationError) Error() string {
+	messages := make([]string, 0, len(e.Violations))
+	for _, violation := range e.Violations {
+		message := violation.Pointer + ": " + violation.Message
+		if violation.Location != nil {
+			message = violation.Location.String() + ": " + message
+		}
+		messages = append(messages, message)
+	}
+	return "invalid document: " + strings.Join(messages, "; ")
+}
+
+// Validate checks that the document follows the structural constraints of
+// the specification: required fields are present, enumerated fields such as
+// the location of parameters and the type of security schemes hold allowed
+// values, mutually exclusive fields are not set together, and names are well
+// formed and unique where required. Every violation is returned, as a
+// *ValidationError, rather than the first. References are not followed,
+// they are checked where the component they point to is declared. The
+// consistency of operations is checked by ValidateSemantics.
+func (r *OpenAPI) Validate(ctx context.Context) error {
+	v := &validator{doc: r}
+	v.document()
+	err := walk(r, func(ptr string, node interface{}) error {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		v.node(ptr, node)
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	if len(v.violations) == 0 {
+		return nil
+	}
+	sortFindings(v.violations)
+	return &ValidationError{Violations: v.violations}
+}
+
+// validator collects the violations of a document.
+type validator struct {
+	doc        *OpenAPI
+	violations []*Finding
+}
+
+// report adds a violation at the pointer.
+func (v *validator) report(ptr string, rule string, format string, args ...interface{}) {
+	v.violations = append(v.violations, &Finding{
+		Pointer:  ptr,
+		Rule:     rule,
+		Severity: SeverityError,
+		Message:  fmt.Sprintf(format, args...),
+	})
+}
+
+// require reports the field of the object at the pointer when empty.
+func (v *validator) require(ptr string, field string, value string) {
+	if value == "" {
+		v.report(join(ptr, field), RuleFieldRequired, "missing %s", field)
+	}
+}
+
+// document checks the constraints spanning several objects.
+func (v *validator) document() {
+	r := v.doc
+	if !versionPattern.MatchString(r.OpenAPI) {
+		v.report("/openapi", RuleVersionInvalid, "unsupported version %q", r.OpenAPI)
+	}
+
+	for _, path := range sortedKeys(r.Paths.PathItems) {
+		if !strings.HasPrefix(path, "/") {
+			v.report(join("/paths", path), RulePathInvalid, "path %q must begin with a slash", path)
+		}
+	}
+
+	tags := map[string]bool{}
+	for i, tag := range r.Tags {
+		if tag == nil || tag.Name == "" {
+			continue
+		}
+		if tags[tag.Name] {
+			v.report(index("", "tags", i), RuleTagDuplicate, "tag %q is already declared", tag.Name)
+		}
+		tags[tag.Name] = true
+	}
+
+	if r.Components != nil {
+		v.componentNames(r.Components)
+	}
+}
+
+// componentNames checks the names of the reusable components.
+func (v *validator) componentNames(r *Components) {
+	sections := map[string][]string{
+		"schemas":         sortedStrings(r.Schemas),
+		"responses":       sortedStrings(r.Responses),
+		"parameters":      sortedStrings(r.Parameters),
+		"examples":        sortedStrings(r.Examples),
+		"requestBodies":   sortedStrings(r.RequestBodies),
+		"headers":         sortedStrings(r.Headers),
+		"securitySchemes": sortedStrings(r.SecuritySchemes),
+		"links":           sortedStrings(r.Links),
+		"callbacks":       sortedStrings(r.Callbacks),
+	}
+	for _, section := range sortedStrings(sections) {
+		for _, name := range sections[section] {
+			if !componentNamePattern.MatchString(name) {
+				v.report(join("/components", section, name), RuleComponentNameInvalid,
+					"component name %q must only contain letters, digits, dots, hyphens and underscores", name)
+			}
+		}
+	}
+}
+
+// node checks the constraints of the object at the pointer.
+func (v *validator) node(ptr string, node interface{}) {
+	switch node := node.(type) {
+	case *Info:
+		v.require(ptr, "title", node.Title)
+		v.require(ptr, "version", node.Version)
+	case *License:
+		v.require(ptr, "name", node.Name)
+	case *Server:
+		v.require(ptr, "url", node.URL)
+	case *ServerVariable:
+		v.require(ptr, "default", node.Default)
+		if node.Enum != nil && len(node.Enum) == 0 {
+			v.report(join(ptr, "enum"), RuleFieldInvalid, "enum must not be empty")
+		}
+	case *Tag:
+		v.require(ptr, "name", node.Name)
+	case *ExternalDocumentation:
+		v.require(ptr, "url", node.URL)
+	case *Operation:
+		v.operation(ptr, node)
+	case *Parameter:
+		v.parameter(ptr, node)
+	case *Header:
+		if node.Ref == "" {
+			v.headerFields(ptr, node)
+		}
+	case *RequestBody:
+		if node.Ref == "" && len(node.Content) == 0 {
+			v.report(join(ptr, "content"), RuleFieldRequired, "missing content")
+		}
+	case *MediaType:
+		if node.Example != nil && len(node.Examples) > 0 {
+			v.report(ptr, RuleFieldsExclusive, "example and examples are mutually exclusive")
+		}
+	case *Response:
+		if node.Ref == "" {
+			v.require(ptr, "description", node.Description)
+		}
+	case *Example:
+		if node.Value != nil && node.ExternalValue != "" {
+			v.report(ptr, RuleFieldsExclusive, "value and externalValue are mutually exclusive")
+		}
+	case *Link:
+		v.link(ptr, node)
+	case *SecurityScheme:
+		v.securityScheme(ptr, node)
+	case *SecurityRequirement:
+		v.securityRequirement(ptr, *node)
+	case *Schema:
+		v.schema(ptr, node)
+	}
+}
+
+// operation checks the responses of the operation.
+func (v *validator) operation(ptr string, r *Operation) {
+	if r.Responses.Len() == 0 {
+		v.report(join(ptr, "responses"), RuleFieldRequired, "missing responses")
+	}
+	for _, status := range sortedKeys(r.Responses) {
+		if !statusPattern.MatchString(status) {
+			v.report(join(ptr, "responses", status), RuleResponseStatusInvalid,
+				"response key %q must be an HTTP status code, a range such as 2XX or default", status)
+		}
+	}
+}
+
+// parameter checks the name and the location of the parameter.
+func (v *validator) parameter(ptr string, r *Parameter) {
+	if r.Ref != "" {
+		return
+	}
+	v.require(ptr, "name", r.Name)
+	switch r.In {
+	case "":
+		v.require(ptr, "in", r.In)
+	case "query", "header", "path", "cookie":
+	default:
+		v.report(join(ptr, "in"), RuleFieldInvalid,
+			"in must be one of query, header, path and cookie, not %q", r.In)
+	}
+	v.headerFields(ptr, &r.Header)
+}
+
+// headerFields checks the fields parameters and headers have in common.
+func (v *validator) headerFields(ptr string, r *Header) {
+	switch {
+	case r.Schema != nil && len(r.Content) > 0:
+		v.report(ptr, RuleFieldsExclusive, "schema and content are mutually exclusive")
+	case r.Schema == nil && len(r.Content) == 0:
+		v.report(ptr, RuleFieldRequired, "missing schema or content")
+	case len(r.Content) > 1:
+		v.report(join(ptr, "content"), RuleFieldInvalid, "content must hold a single media type")
+	}
+	if r.Example != nil && len(r.Examples) > 0 {
+		v.report(ptr, RuleFieldsExclusive, "example and examples are mutually exclusive")
+	}
+}
+
+// link checks that the link targets a single operation.
+func (v *validator) link(ptr string, r *Link) {
+	if r.Ref != "" {
+		return
+	}
+	switch {
+	case r.OperationRef != "" && r.OperationID != "":
+		v.report(ptr, RuleFieldsExclusive, "operationRef and operationId are mutually exclusive")
+	case r.OperationRef == "" && r.OperationID == "":
+		v.report(ptr, RuleFieldRequired, "missing operationRef or operationId")
+	}
+}
+
+// securityScheme checks the fields required by the type of the scheme.
+func (v *validator) securityScheme(ptr string, r *SecurityScheme) {
+	if r.Ref != "" {
+		return
+	}
+	switch r.Type {
+	case "":
+		v.require(ptr, "type", r.Type)
+	case "apiKey":
+		v.require(ptr, "name", r.Name)
+		switch r.In {
+		case "":
+			v.require(ptr, "in", r.In)
+		case "query", "header", "cookie":
+		default:
+			v.report(join(ptr, "in"), RuleFieldInvalid,
+				"in must be one of query, header and cookie, not %q", r.In)
+		}
+	case "http":
+		v.require(ptr, "scheme", r.Scheme)
+	case "oauth2":
+		flows := r.Flows
+		if flows.Implicit == nil && flows.Password == nil && flows.ClientCredentials == nil && flows.AuthorizationCode == nil {
+			v.report(join(ptr, "flows"), RuleFieldRequired, "missing flows")
+		}
+		v.oauthFlow(join(ptr, "flows", "implicit"), flows.Implicit, true, false)
+		v.oauthFlow(join(ptr, "flows", "password"), flows.Password, false, true)
+		v.oauthFlow(join(ptr, "flows", "clientCredentials"), flows.ClientCredentials, false, true)
+		v.oauthFlow(join(ptr, "flows", "authorizationCode"), flows.AuthorizationCode, true, true)
+	case "openIdConnect":
+		v.require(ptr, "openIdConnectUrl", r.OpenIDConnectURL)
+	default:
+		v.report(join(ptr, "type"), RuleFieldInvalid,
+			"type must be one of apiKey, http, oauth2 and openIdConnect, not %q", r.Type)
+	}
+}
+
+// oauthFlow checks the URLs and scopes of the flow, if present.
+func (v *validator) oauthFlow(ptr string, r *OAuthFlow, authorization bool, token bool) {
+	if r == nil {
+		return
+	}
+	if authorization {
+		v.require(ptr, "authorizationUrl", r.AuthorizationURL)
+	}
+	if token {
+		v.require(ptr, "tokenUrl", r.TokenURL)
+	}
+	if r.Scopes == nil {
+		v.report(join(ptr, "scopes"), RuleFieldRequired, "missing scopes")
+	}
+}
+
+// securityRequirement checks that the requirement names declared security
+// schemes, and that only OAuth2 and OpenID Connect schemes list scopes.
+// Schemes declared by reference are checked as resolved.
+func (v *validator) securityRequirement(ptr string, r SecurityRequirement) {
+	for _, name := range sortedStrings(r) {
+		var scheme *SecurityScheme
+		if v.doc.Components != nil {
+			scheme = v.doc.Components.SecuritySchemes[name]
+		}
+		if scheme == nil {
+			v.report(join(ptr, name), RuleSecuritySchemeUnknown, "security scheme %q is not declared", name)
+			continue
+		}
+		if scheme.Ref != "" {
+			resolved, err := NewResolver(v.doc).ResolveSecurityScheme(scheme.Ref)
+			if err != nil {
+				v.report(join(ptr, name), RuleSecuritySchemeUnknown, "security scheme %q refers to unresolved %q", name, scheme.Ref)
+				continue
+			}
+			scheme = resolved
+		}
+		if len(r[name]) > 0 && scheme.Type != "oauth2" && scheme.Type != "openIdConnect" {
+			v.report(join(ptr, name), RuleSecurityScopesInvalid,
+				"scopes must be empty for %s security scheme %q", scheme.Type, name)
+		}
+	}
+}
+
+// schema checks the type of the schema.
+func (v *validator) schema(ptr string, r *Schema) {
+	if r.Ref != "" {
+		return
+	}
+	if r.Type != "" && !schemaTypes[r.Type] {
+		v.report(join(ptr, "type"), RuleFieldInvalid, "unknown type %q", r.Type)
+	}
+	if r.Type == "array" && r.Items == nil {
+		v.report(join(ptr, "items"), RuleFieldRequired, "missing items of array")
+	}
+}
@@ -0,0 +1,213 @@
+package oas
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+)
+
+type ValidateSuite struct {
+	suite.Suite
+}
+
+// validatePatch is the patch of the petstore fixture the suite validates.
+const validatePatch = `
+paths:
+  /pets/{petId}:
+    get:
+      security:
+        - key: []
+      responses:
+        default:
+          $ref: "#/components/responses/Error"
+components:
+  responses:
+    Error:
+      description: Error.
+  securitySchemes:
+    key:
+      type: apiKey
+      name: X-Key
+      in: header
+`
+
+func (r *ValidateSuite) TestValidate() {
+	testCases := []struct {
+		patch    string
+		expected []string
+	}{
+		{
+			"",
+			nil,
+		},
+		{
+			`
+openapi: "2.0"
+info:
+  title: null
+  version: null
+`,
+			[]string{
+				"/info/title " + RuleFieldRequired,
+				"/info/version " + RuleFieldRequired,
+				"/openapi " + RuleVersionInvalid,
+			},
+		},
+		{
+			`
+paths:
+  /pets/{petId}:
+    get:
+      parameters:
+        - name: q
+          in: body
+          schema:
+            type: string
+        - name: id
+          in: path
+          schema:
+            type: string
+      responses:
+        "200":
+          description: ""
+        "20x":
+          description: Bad.
+  pets: {}
+`,
+			[]string{
+				"/paths/pets " + RulePathInvalid,
+				"/paths/~1pets~1{petId}/get/parameters/0/in " + RuleFieldInvalid,
+				"/paths/~1pets~1{petId}/get/responses/200/description " + RuleFieldRequired,
+				"/paths/~1pets~1{petId}/get/responses/20x " + RuleResponseStatusInvalid,
+			},
+		},
+		{
+			`
+paths:
+  /owners:
+    get:
+      operationId: getPet
+`,
+			[]string{
+				"/paths/~1owners/get/responses " + RuleFieldRequired,
+			},
+		},
+		{
+			`
+security:
+  - key: [read]
+    missing: []
+components:
+  securitySchemes:
+    key:
+      name: null
+      in: body
+    basic:
+      type: basic
+    oauth:
+      type: oauth2
+      flows:
+        implicit: {}
+    bad id:
+      type: http
+      scheme: bearer
+`,
+			[]string{
+				"/components/securitySchemes/bad id " + RuleComponentNameInvalid,
+				"/components/securitySchemes/basic/type " + RuleFieldInvalid,
+				"/components/securitySchemes/key/in " + RuleFieldInvalid,
+				"/components/securitySchemes/key/name " + RuleFieldRequired,
+				"/components/securitySchemes/oauth/flows/implicit/authorizationUrl " + RuleFieldRequired,
+				"/components/securitySchemes/oauth/flows/implicit/scopes " + RuleFieldRequired,
+				"/security/0/key " + RuleSecurityScopesInvalid,
+				"/security/0/missing " + RuleSecuritySchemeUnknown,
+			},
+		},
+		{
+			`
+security:
+  - login: [read]
+  - gone: []
+components:
+  securitySchemes:
+    oauth:
+      type: oauth2
+      flows:
+        clientCredentials:
+          tokenUrl: https://example.com/token
+          scopes: {}
+    login:
+      $ref: "#/components/securitySchemes/oauth"
+    gone:
+      $ref: "#/components/securitySchemes/missing"
+`,
+			[]string{
+				"/security/1/gone " + RuleSecuritySchemeUnknown,
+			},
+		},
+		{
+			`
+tags:
+  - name: pets
+  - name: pets
+components:
+  schemas:
+    Pets:
+      type: array
+    Pet:
+      type: dict
+  links:
+    Owner: {}
+  examples:
+    Pet:
+      value: Rex
+      externalValue: https://example.com/rex.json
+`,
+			[]string{
+				"/components/examples/Pet " + RuleFieldsExclusive,
+				"/components/links/Owner " + RuleFieldRequired,
+				"/components/schemas/Pet/type " + RuleFieldInvalid,
+				"/components/schemas/Pets/items " + RuleFieldRequired,
+				"/tags/1 " + RuleTagDuplicate,
+			},
+		},
+	}
+
+	for i, testCase := range testCases {
+		failMsg := "test case %d failed"
+		err := petstore(r.T(), validatePatch, testCase.patch).Validate(context.Background())
+		if testCase.expected == nil {
+			assert.Nil(r.T(), err, failMsg, i)
+			continue
+		}
+		if !assert.IsType(r.T(), &ValidationError{}, err, failMsg, i) {
+			continue
+		}
+		actual := make([]string, 0)
+		for _, violation := range err.(*ValidationError).Violations {
+			actual = append(actual, violation.Pointer+" "+violation.Rule)
+		}
+		assert.Equal(r.T(), testCase.expected, actual, failMsg, i)
+	}
+}
+
+func (r *ValidateSuite) TestValidateCanceled() {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	err := petstore(r.T(), validatePatch).Validate(ctx)
+	assert.Equal(r.T(), context.Canceled, err)
+}
+
+func (r *ValidateSuite) TestValidationError() {
+	err := &ValidationError{Violations: []*Finding{
+		{Pointer: "/info/title", Message: "missing title"},
+		{Pointer: "/openapi", Message: "unsupported version \"2.0\""},
+	}}
+	assert.Equal(r.T(), `invalid document: /info/title: missing title; /openapi: unsupported version "2.0"`, err.Error())
+}
+
+func TestValidateSuite(t *testing.T) {
+	suite.Run(t, new(ValidateSuite))
+}
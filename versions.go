@@ -0,0 +1,254 @@
+package oas
+
+import (
+	"reflect"
+	"strconv"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// PreviousOperationExtension names the specification extension holding the
+// operationId an operation had in the previous major version of the API. It
+// lets MigrationReport follow operations which were renamed.
+const PreviousOperationExtension = "x-previous-operation-id"
+
+// Statuses of the operations of a migration report.
+const (
+	// MigrationCarried describes an operation carried forward unchanged.
+	MigrationCarried = "carried"
+
+	// MigrationRenamed describes an operation carried forward under a new
+	// operationId.
+	MigrationRenamed = "renamed"
+
+	// MigrationMoved describes an operation carried forward to a new method or
+	// path.
+	MigrationMoved = "moved"
+
+	// MigrationRemoved describes an operation of the previous version missing
+	// from the new version.
+	MigrationRemoved = "removed"
+
+	// MigrationAdded describes an operation new in the new version.
+	MigrationAdded = "added"
+)
+
+// VersionBump describes the number of a semantic version a change between two
+// versions of a document calls for incrementing.
+type VersionBump string
+
+// Version bumps, from the least to the most significant.
+const (
+	// BumpNone describes documents without changes.
+	BumpNone VersionBump = "none"
+
+	// BumpPatch describes changes to documentation and specification
+	// extensions only.
+	BumpPatch VersionBump = "patch"
+
+	// BumpMinor describes additive changes, such as new paths, operations,
+	// properties or responses.
+	BumpMinor VersionBump = "minor"
+
+	// BumpMajor describes changes which are not additive.
+	BumpMajor VersionBump = "major"
+)
+
+// RecommendBump compares the next version of the document against it and
+// returns the version bump the changes call for. Changes are classified as
+// CheckFrozen does, whether or not the document is frozen: anything
+// CheckFrozen would report calls for a major bump, added entries for a minor
+// bump and any other change, such as to descriptions or extensions, for a
+// patch bump. The version of the documents and the frozen mark are ignored.
+func (r OpenAPI) RecommendBump(next OpenAPI) (VersionBump, error) {
+	before, err := genericValue(r)
+	if err != nil {
+		return "", err
+	}
+	after, err := genericValue(next)
+	if err != nil {
+		return "", err
+	}
+	for _, value := range []interface{}{before, after} {
+		if obj, ok := value.(map[string]interface{}); ok {
+			delete(obj, FrozenExtension)
+			if info, ok := obj["info"].(map[string]interface{}); ok {
+				delete(info, "version")
+			}
+		}
+	}
+	if reflect.DeepEqual(before, after) {
+		return BumpNone, nil
+	}
+
+	findings := make([]*Finding, 0)
+	added := false
+	checkFrozenValue("", "", before, after, false, &findings, &added)
+	switch {
+	case len(findings) > 0:
+		return BumpMajor, nil
+	case added:
+		return BumpMinor, nil
+	default:
+		return BumpPatch, nil
+	}
+}
+
+// BumpVersion increments the semantic version of the document by the bump,
+// resetting the less significant numbers and dropping the pre-release and
+// build metadata (e.g. a minor bump turns "1.2.3-rc.1" into "1.3.0"). A "v"
+// prefix is kept. An error is returned if the version of the document is not
+// a semantic version.
+func (r *OpenAPI) BumpVersion(bump VersionBump) error {
+	version, ok := parseVersion(r.Info.Version)
+	if !ok {
+		return errors.Errorf("%q is not a semantic version", r.Info.Version)
+	}
+	switch bump {
+	case BumpNone:
+		return nil
+	case BumpPatch:
+		version.numbers[2]++
+	case BumpMinor:
+		version.numbers = [3]uint64{version.numbers[0], version.numbers[1] + 1, 0}
+	case BumpMajor:
+		version.numbers = [3]uint64{version.numbers[0] + 1, 0, 0}
+	default:
+		return errors.Errorf("unknown version bump %q", bump)
+	}
+
+	numbers := make([]string, 0, len(version.numbers))
+	for _, number := range version.numbers {
+		numbers = append(numbers, strconv.FormatUint(number, 10))
+	}
+	prefix := ""
+	if strings.HasPrefix(r.Info.Version, "v") {
+		prefix = "v"
+	}
+	r.Info.Version = prefix + strings.Join(numbers, ".")
+	return nil
+}
+
+// NextVersion returns a skeleton of the next major version of the API: a
+// copy of the document with the given version whose operations record their
+// current operationId with the x-previous-operation-id extension, so they
+// can be renamed, moved or removed while MigrationReport keeps track of
+// them. A frozen mark is not carried forward.
+func (r OpenAPI) NextVersion(version string) (*OpenAPI, error) {
+	if version == "" {
+		return nil, errors.New("missing version")
+	}
+	next, err := r.Clone()
+	if err != nil {
+		return nil, err
+	}
+	next.Info.Version = version
+	delete(next.Extensions, FrozenExtension)
+	for _, op := range next.Paths.operations() {
+		if op.operation.OperationID != "" {
+			setExtension(&op.operation.Extensions, PreviousOperationExtension, op.operation.OperationID)
+		}
+	}
+	return next, nil
+}
+
+// OperationReference identifies an operation of a version of the API.
+type OperationReference struct {
+	// OperationID describes the operationId of the operation.
+	OperationID string `json:"operationId,omitempty" yaml:"operationId,omitempty"`
+
+	// Method describes the uppercase HTTP method of the operation.
+	Method string `json:"method" yaml:"method"`
+
+	// Path describes the path template of the operation.
+	Path string `json:"path" yaml:"path"`
+}
+
+// OperationMigration describes what became of an operation between two
+// versions of the API.
+type OperationMigration struct {
+	// Status describes the outcome, one of MigrationCarried,
+	// MigrationRenamed, MigrationMoved, MigrationRemoved or MigrationAdded.
+	Status string `json:"status" yaml:"status"`
+
+	// From describes the operation in the previous version, nil for added
+	// operations.
+	From *OperationReference `json:"from,omitempty" yaml:"from,omitempty"`
+
+	// To describes the operation in the new version, nil for removed
+	// operations.
+	To *OperationReference `json:"to,omitempty" yaml:"to,omitempty"`
+}
+
+// MigrationReport maps the operations of the previous version of the API to
+// the operations of the next version. Operations are matched through the
+// x-previous-operation-id extension, then through an unchanged operationId
+// and finally through an unchanged method and path. Operations of the
+// previous version are reported first in path and method order, followed by
+// the added operations.
+func MigrationReport(previous OpenAPI, next OpenAPI) []*OperationMigration {
+	type entry struct {
+		ref     *OperationReference
+		matched bool
+	}
+	reference := func(op pathOperation) *OperationReference {
+		return &OperationReference{
+			OperationID: op.operation.OperationID,
+			Method:      strings.ToUpper(op.method),
+			Path:        op.path,
+		}
+	}
+
+	olds := make([]*entry, 0)
+	byID := map[string]*entry{}
+	byEndpoint := map[string]*entry{}
+	for _, op := range previous.Paths.operations() {
+		old := &entry{ref: reference(op)}
+		olds = append(olds, old)
+		if old.ref.OperationID != "" {
+			byID[old.ref.OperationID] = old
+		}
+		byEndpoint[old.ref.Method+" "+old.ref.Path] = old
+	}
+
+	migrations := map[*entry]*OperationMigration{}
+	added := make([]*OperationMigration, 0)
+	for _, op := range next.Paths.operations() {
+		ref := reference(op)
+		var match *entry
+		if id, ok := op.operation.Extensions.stringValue(PreviousOperationExtension); ok {
+			match = byID[id]
+		}
+		if match == nil && ref.OperationID != "" {
+			match = byID[ref.OperationID]
+		}
+		if match == nil {
+			match = byEndpoint[ref.Method+" "+ref.Path]
+		}
+		if match == nil || match.matched {
+			added = append(added, &OperationMigration{Status: MigrationAdded, To: ref})
+			continue
+		}
+
+		match.matched = true
+		status := MigrationCarried
+		switch {
+		case match.ref.OperationID != ref.OperationID:
+			status = MigrationRenamed
+		case match.ref.Method != ref.Method || match.ref.Path != ref.Path:
+			status = MigrationMoved
+		}
+		migrations[match] = &OperationMigration{Status: status, From: match.ref, To: ref}
+	}
+
+	report := make([]*OperationMigration, 0, len(olds)+len(added))
+	for _, old := range olds {
+		if migration, ok := migrations[old]; ok {
+			report = append(report, migration)
+		} else {
+			report = append(report, &OperationMigration{Status: MigrationRemoved, From: old.ref})
+		}
+	}
+	return append(report, added...)
+}
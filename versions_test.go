@@ -0,0 +1,131 @@
+package oas
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+)
+
+type VersionsSuite struct {
+	suite.Suite
+}
+
+// versionsPatch is the patch of the petstore fixture the suite versions,
+// freezing the released version of the document.
+const versionsPatch = `
+info:
+  version: 1.4.0
+x-frozen: true
+paths:
+  /health:
+    get: {}
+`
+
+func (r *VersionsSuite) TestNextVersion() {
+	_, err := petstore(r.T(), versionsPatch).NextVersion("")
+	assert.NotNil(r.T(), err)
+
+	next, err := petstore(r.T(), versionsPatch).NextVersion("2.0.0")
+	assert.Nil(r.T(), err)
+	assert.Equal(r.T(), "2.0.0", next.Info.Version)
+	assert.False(r.T(), next.IsFrozen())
+	assert.Equal(r.T(), Extensions{PreviousOperationExtension: "listPets"}, next.Paths.PathItems.Get("/pets").Get.Extensions)
+	assert.Nil(r.T(), next.Paths.PathItems.Get("/health").Get.Extensions)
+	assert.Equal(r.T(), "1.4.0", petstore(r.T(), versionsPatch).Info.Version)
+}
+
+func (r *VersionsSuite) TestMigrationReport() {
+	previous := petstore(r.T(), versionsPatch)
+	next, err := previous.NextVersion("2.0.0")
+	assert.Nil(r.T(), err)
+
+	pets := next.Paths.PathItems.Get("/pets")
+	pets.Get.OperationID = "searchPets"
+	item := next.Paths.PathItems.Get("/pets/{petId}")
+	next.Paths.PathItems.Set("/pets/{id}", &PathItem{Get: item.Get})
+	next.Paths.PathItems.Delete("/pets/{petId}")
+	next.Paths.PathItems.Set("/owners", &PathItem{Get: &Operation{OperationID: "listOwners"}})
+
+	assert.Equal(r.T(), []*OperationMigration{
+		{
+			Status: MigrationCarried,
+			From:   &OperationReference{Method: "GET", Path: "/health"},
+			To:     &OperationReference{Method: "GET", Path: "/health"},
+		},
+		{
+			Status: MigrationRenamed,
+			From:   &OperationReference{OperationID: "listPets", Method: "GET", Path: "/pets"},
+			To:     &OperationReference{OperationID: "searchPets", Method: "GET", Path: "/pets"},
+		},
+		{
+			Status: MigrationCarried,
+			From:   &OperationReference{OperationID: "createPet", Method: "POST", Path: "/pets"},
+			To:     &OperationReference{OperationID: "createPet", Method: "POST", Path: "/pets"},
+		},
+		{
+			Status: MigrationMoved,
+			From:   &OperationReference{OperationID: "getPet", Method: "GET", Path: "/pets/{petId}"},
+			To:     &OperationReference{OperationID: "getPet", Method: "GET", Path: "/pets/{id}"},
+		},
+		{
+			Status: MigrationRemoved,
+			From:   &OperationReference{OperationID: "deletePet", Method: "DELETE", Path: "/pets/{petId}"},
+		},
+		{
+			Status: MigrationAdded,
+			To:     &OperationReference{OperationID: "listOwners", Method: "GET", Path: "/owners"},
+		},
+	}, MigrationReport(*previous, *next))
+}
+
+func (r *VersionsSuite) TestRecommendBump() {
+	testCases := []struct {
+		patch    string
+		expected VersionBump
+	}{
+		{"", BumpNone},
+		{"info: {version: 1.5.0}", BumpNone},
+		{"paths: {/pets: {get: {description: Lists pets.}}}", BumpPatch},
+		{"paths: {/pets: {put: {operationId: replacePets}}}", BumpMinor},
+		{"paths: {/owners: {get: {}}}", BumpMinor},
+		{"paths: {/health: null}", BumpMajor},
+		{"paths: {/pets: {get: {operationId: searchPets}}}", BumpMajor},
+	}
+
+	failMsg := "test case %d failed"
+	for i, testCase := range testCases {
+		next := petstore(r.T(), versionsPatch, testCase.patch)
+		actual, err := petstore(r.T(), versionsPatch).RecommendBump(*next)
+		assert.Nil(r.T(), err, failMsg, i)
+		assert.Equal(r.T(), testCase.expected, actual, failMsg, i)
+	}
+}
+
+func (r *VersionsSuite) TestBumpVersion() {
+	testCases := []struct {
+		shouldFail bool
+		version    string
+		bump       VersionBump
+		expected   string
+	}{
+		{false, "1.4.2", BumpNone, "1.4.2"},
+		{false, "1.4.2", BumpPatch, "1.4.3"},
+		{false, "1.4.2", BumpMinor, "1.5.0"},
+		{false, "v1.4.2-rc.1+build.5", BumpMajor, "v2.0.0"},
+		{true, "1.4", BumpPatch, "1.4"},
+		{true, "1.4.2", VersionBump("huge"), "1.4.2"},
+	}
+
+	failMsg := "test case %d failed"
+	for i, testCase := range testCases {
+		doc := &OpenAPI{Info: Info{Version: testCase.version}}
+		err := doc.BumpVersion(testCase.bump)
+		assert.Equal(r.T(), testCase.shouldFail, err != nil, failMsg, i)
+		assert.Equal(r.T(), testCase.expected, doc.Info.Version, failMsg, i)
+	}
+}
+
+func TestVersionsSuite(t *testing.T) {
+	suite.Run(t, new(VersionsSuite))
+}
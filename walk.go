@@ -0,0 +1,576 @@
+package oas
+
+import (
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// walkFunc is invoked for every object visited by walk. The pointer argument
+// holds the JSON Pointer of the node relative to the document root and node
+// holds a pointer to the visited object (e.g. *Schema, *Operation).
+type walkFunc func(pointer string, node interface{}) error
+
+// walker traverses the object tree of a document in a deterministic order.
+// Maps are visited in sorted key order and every node is visited before its
+// children, so callbacks may safely rewrite a node in place before it is
+// descended into.
+type walker struct {
+	fn walkFunc
+}
+
+// walk visits every object reachable from the document root.
+func walk(doc *OpenAPI, fn walkFunc) error {
+	w := &walker{fn: fn}
+	return w.openAPI("", doc)
+}
+
+// escapePointer escapes a single JSON Pointer reference token.
+func escapePointer(token string) string {
+	token = strings.Replace(token, "~", "~0", -1)
+	return strings.Replace(token, "/", "~1", -1)
+}
+
+// unescapePointer reverses escapePointer.
+func unescapePointer(token string) string {
+	token = strings.Replace(token, "~1", "/", -1)
+	return strings.Replace(token, "~0", "~", -1)
+}
+
+func join(pointer string, tokens ...string) string {
+	for _, token := range tokens {
+		pointer += "/" + escapePointer(token)
+	}
+	return pointer
+}
+
+func index(pointer string, token string, i int) string {
+	return join(pointer, token, strconv.Itoa(i))
+}
+
+func sortedKeys(m interface{}) []string {
+	keys := make([]string, 0)
+	switch m := m.(type) {
+	case map[string]*Schema:
+		for k := range m {
+			keys = append(keys, k)
+		}
+	case map[string]*Response:
+		for k := range m {
+			keys = append(keys, k)
+		}
+	case map[string]*Parameter:
+		for k := range m {
+			keys = append(keys, k)
+		}
+	case map[string]*Example:
+		for k := range m {
+			keys = append(keys, k)
+		}
+	case map[string]*RequestBody:
+		for k := range m {
+			keys = append(keys, k)
+		}
+	case map[string]*Header:
+		for k := range m {
+			keys = append(keys, k)
+		}
+	case map[string]*SecurityScheme:
+		for k := range m {
+			keys = append(keys, k)
+		}
+	case map[string]*Link:
+		for k := range m {
+			keys = append(keys, k)
+		}
+	case map[string]*Callback:
+		for k := range m {
+			keys = append(keys, k)
+		}
+	case map[string]*MediaType:
+		for k := range m {
+			keys = append(keys, k)
+		}
+	case map[string]*Encoding:
+		for k := range m {
+			keys = append(keys, k)
+		}
+	case map[string]*ServerVariable:
+		for k := range m {
+			keys = append(keys, k)
+		}
+	case PathItems:
+		keys = m.Keys()
+	case Properties:
+		keys = m.Keys()
+	case Responses:
+		keys = m.Keys()
+	case CallbackItems:
+		for k := range m {
+			keys = append(keys, k)
+		}
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+func (w *walker) openAPI(ptr string, r *OpenAPI) error {
+	if r == nil {
+		return nil
+	}
+	if err := w.fn(ptr, r); err != nil {
+		return err
+	}
+	if err := w.info(join(ptr, "info"), &r.Info); err != nil {
+		return err
+	}
+	if err := w.servers(join(ptr, "servers"), r.Servers); err != nil {
+		return err
+	}
+	for _, key := range sortedKeys(r.Paths.PathItems) {
+		if err := w.pathItem(join(ptr, "paths", key), r.Paths.PathItems.Get(key)); err != nil {
+			return err
+		}
+	}
+	if err := w.components(join(ptr, "components"), r.Components); err != nil {
+		return err
+	}
+	for i, value := range r.Security {
+		if value == nil {
+			continue
+		}
+		if err := w.fn(index(ptr, "security", i), value); err != nil {
+			return err
+		}
+	}
+	for i, value := range r.Tags {
+		if err := w.tag(index(ptr, "tags", i), value); err != nil {
+			return err
+		}
+	}
+	return w.externalDocs(join(ptr, "externalDocs"), r.ExternalDocs)
+}
+
+func (w *walker) info(ptr string, r *Info) error {
+	if err := w.fn(ptr, r); err != nil {
+		return err
+	}
+	if r.Contact != nil {
+		if err := w.fn(join(ptr, "contact"), r.Contact); err != nil {
+			return err
+		}
+	}
+	if r.License != nil {
+		if err := w.fn(join(ptr, "license"), r.License); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (w *walker) servers(ptr string, servers []*Server) error {
+	for i, value := range servers {
+		if value == nil {
+			continue
+		}
+		sptr := join(ptr, strconv.Itoa(i))
+		if err := w.fn(sptr, value); err != nil {
+			return err
+		}
+		for _, key := range sortedKeys(value.Variables) {
+			if value.Variables[key] == nil {
+				continue
+			}
+			if err := w.fn(join(sptr, "variables", key), value.Variables[key]); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func (w *walker) tag(ptr string, r *Tag) error {
+	if r == nil {
+		return nil
+	}
+	if err := w.fn(ptr, r); err != nil {
+		return err
+	}
+	return w.externalDocs(join(ptr, "externalDocs"), r.ExternalDocs)
+}
+
+func (w *walker) externalDocs(ptr string, r *ExternalDocumentation) error {
+	if r == nil {
+		return nil
+	}
+	return w.fn(ptr, r)
+}
+
+func (w *walker) components(ptr string, r *Components) error {
+	if r == nil {
+		return nil
+	}
+	if err := w.fn(ptr, r); err != nil {
+		return err
+	}
+	for _, key := range sortedKeys(r.Schemas) {
+		if err := w.schema(join(ptr, "schemas", key), r.Schemas[key]); err != nil {
+			return err
+		}
+	}
+	for _, key := range sortedKeys(r.Responses) {
+		if err := w.response(join(ptr, "responses", key), r.Responses[key]); err != nil {
+			return err
+		}
+	}
+	for _, key := range sortedKeys(r.Parameters) {
+		if err := w.parameter(join(ptr, "parameters", key), r.Parameters[key]); err != nil {
+			return err
+		}
+	}
+	for _, key := range sortedKeys(r.Examples) {
+		if err := w.example(join(ptr, "examples", key), r.Examples[key]); err != nil {
+			return err
+		}
+	}
+	for _, key := range sortedKeys(r.RequestBodies) {
+		if err := w.requestBody(join(ptr, "requestBodies", key), r.RequestBodies[key]); err != nil {
+			return err
+		}
+	}
+	for _, key := range sortedKeys(r.Headers) {
+		if err := w.header(join(ptr, "headers", key), r.Headers[key]); err != nil {
+			return err
+		}
+	}
+	for _, key := range sortedKeys(r.SecuritySchemes) {
+		if r.SecuritySchemes[key] == nil {
+			continue
+		}
+		if err := w.fn(join(ptr, "securitySchemes", key), r.SecuritySchemes[key]); err != nil {
+			return err
+		}
+	}
+	for _, key := range sortedKeys(r.Links) {
+		if err := w.link(join(ptr, "links", key), r.Links[key]); err != nil {
+			return err
+		}
+	}
+	for _, key := range sortedKeys(r.Callbacks) {
+		if err := w.callback(join(ptr, "callbacks", key), r.Callbacks[key]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (w *walker) pathItem(ptr string, r *PathItem) error {
+	if r == nil {
+		return nil
+	}
+	if err := w.fn(ptr, r); err != nil {
+		return err
+	}
+	for _, op := range r.operations() {
+		if err := w.operation(join(ptr, op.method), op.operation); err != nil {
+			return err
+		}
+	}
+	if err := w.servers(join(ptr, "servers"), r.Servers); err != nil {
+		return err
+	}
+	for i, value := range r.Parameters {
+		if err := w.parameter(index(ptr, "parameters", i), value); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (w *walker) operation(ptr string, r *Operation) error {
+	if err := w.fn(ptr, r); err != nil {
+		return err
+	}
+	if err := w.externalDocs(join(ptr, "externalDocs"), r.ExternalDocs); err != nil {
+		return err
+	}
+	for i, value := range r.Parameters {
+		if err := w.parameter(index(ptr, "parameters", i), value); err != nil {
+			return err
+		}
+	}
+	if err := w.requestBody(join(ptr, "requestBody"), r.RequestBody); err != nil {
+		return err
+	}
+	for _, key := range sortedKeys(r.Responses) {
+		if err := w.response(join(ptr, "responses", key), r.Responses.Get(key)); err != nil {
+			return err
+		}
+	}
+	for _, key := range sortedKeys(r.Callbacks) {
+		if err := w.callback(join(ptr, "callbacks", key), r.Callbacks[key]); err != nil {
+			return err
+		}
+	}
+	for i, value := range r.Security {
+		if value == nil {
+			continue
+		}
+		if err := w.fn(index(ptr, "security", i), value); err != nil {
+			return err
+		}
+	}
+	return w.servers(join(ptr, "servers"), r.Servers)
+}
+
+func (w *walker) parameter(ptr string, r *Parameter) error {
+	if r == nil {
+		return nil
+	}
+	if err := w.fn(ptr, r); err != nil {
+		return err
+	}
+	return w.headerFields(ptr, &r.Header)
+}
+
+func (w *walker) header(ptr string, r *Header) error {
+	if r == nil {
+		return nil
+	}
+	if err := w.fn(ptr, r); err != nil {
+		return err
+	}
+	return w.headerFields(ptr, r)
+}
+
+func (w *walker) headerFields(ptr string, r *Header) error {
+	if err := w.schema(join(ptr, "schema"), r.Schema); err != nil {
+		return err
+	}
+	for _, key := range sortedKeys(r.Examples) {
+		if err := w.example(join(ptr, "examples", key), r.Examples[key]); err != nil {
+			return err
+		}
+	}
+	return w.content(join(ptr, "content"), r.Content)
+}
+
+func (w *walker) requestBody(ptr string, r *RequestBody) error {
+	if r == nil {
+		return nil
+	}
+	if err := w.fn(ptr, r); err != nil {
+		return err
+	}
+	return w.content(join(ptr, "content"), r.Content)
+}
+
+func (w *walker) content(ptr string, content map[string]*MediaType) error {
+	for _, key := range sortedKeys(content) {
+		if err := w.mediaType(join(ptr, key), content[key]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (w *walker) mediaType(ptr string, r *MediaType) error {
+	if r == nil {
+		return nil
+	}
+	if err := w.fn(ptr, r); err != nil {
+		return err
+	}
+	if err := w.schema(join(ptr, "schema"), r.Schema); err != nil {
+		return err
+	}
+	for _, key := range sortedKeys(r.Examples) {
+		if err := w.example(join(ptr, "examples", key), r.Examples[key]); err != nil {
+			return err
+		}
+	}
+	for _, key := range sortedKeys(r.Encoding) {
+		value := r.Encoding[key]
+		if value == nil {
+			continue
+		}
+		eptr := join(ptr, "encoding", key)
+		if err := w.fn(eptr, value); err != nil {
+			return err
+		}
+		for _, name := range sortedKeys(value.Headers) {
+			if err := w.header(join(eptr, "headers", name), value.Headers[name]); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func (w *walker) response(ptr string, r *Response) error {
+	if r == nil {
+		return nil
+	}
+	if err := w.fn(ptr, r); err != nil {
+		return err
+	}
+	for _, key := range sortedKeys(r.Headers) {
+		if err := w.header(join(ptr, "headers", key), r.Headers[key]); err != nil {
+			return err
+		}
+	}
+	if err := w.content(join(ptr, "content"), r.Content); err != nil {
+		return err
+	}
+	for _, key := range sortedKeys(r.Links) {
+		if err := w.link(join(ptr, "links", key), r.Links[key]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (w *walker) example(ptr string, r *Example) error {
+	if r == nil {
+		return nil
+	}
+	return w.fn(ptr, r)
+}
+
+func (w *walker) link(ptr string, r *Link) error {
+	if r == nil {
+		return nil
+	}
+	if err := w.fn(ptr, r); err != nil {
+		return err
+	}
+	if r.Server != nil {
+		return w.fn(join(ptr, "server"), r.Server)
+	}
+	return nil
+}
+
+func (w *walker) callback(ptr string, r *Callback) error {
+	if r == nil {
+		return nil
+	}
+	if err := w.fn(ptr, r); err != nil {
+		return err
+	}
+	for _, key := range sortedKeys(r.CallbackItems) {
+		if err := w.pathItem(join(ptr, key), r.CallbackItems[key]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (w *walker) schema(ptr string, r *Schema) error {
+	if r == nil {
+		return nil
+	}
+	if err := w.fn(ptr, r); err != nil {
+		return err
+	}
+	if err := w.externalDocs(join(ptr, "externalDocs"), r.ExternalDocs); err != nil {
+		return err
+	}
+	if err := w.schema(join(ptr, "items"), r.Items); err != nil {
+		return err
+	}
+	for _, key := range sortedKeys(r.Properties) {
+		if err := w.schema(join(ptr, "properties", key), r.Properties.Get(key)); err != nil {
+			return err
+		}
+	}
+	if err := w.schema(join(ptr, "additionalProperties"), r.AdditionalProperties); err != nil {
+		return err
+	}
+	for i, value := range r.AllOf {
+		if err := w.schema(index(ptr, "allOf", i), value); err != nil {
+			return err
+		}
+	}
+	for i, value := range r.AnyOf {
+		if err := w.schema(index(ptr, "anyOf", i), value); err != nil {
+			return err
+		}
+	}
+	for i, value := range r.OneOf {
+		if err := w.schema(index(ptr, "oneOf", i), value); err != nil {
+			return err
+		}
+	}
+	return w.schema(join(ptr, "not"), r.Not)
+}
+
+// refOf returns the address of the $ref field of the node if it has one.
+func refOf(node interface{}) *string {
+	switch node := node.(type) {
+	case *Schema:
+		return &node.Ref
+	case *Parameter:
+		return &node.Ref
+	case *Header:
+		return &node.Ref
+	case *Response:
+		return &node.Ref
+	case *RequestBody:
+		return &node.Ref
+	case *Example:
+		return &node.Ref
+	case *Link:
+		return &node.Ref
+	case *Callback:
+		return &node.Ref
+	case *SecurityScheme:
+		return &node.Ref
+	case *PathItem:
+		return &node.Ref
+	}
+	return nil
+}
+
+// componentName returns the name of the component a local reference points
+// to if the reference addresses a component of the kind (e.g. "schemas")
+// directly.
+func componentName(ref string, kind string) (string, bool) {
+	prefix := "#/components/" + kind + "/"
+	if !strings.HasPrefix(ref, prefix) || strings.Contains(ref[len(prefix):], "/") {
+		return "", false
+	}
+	return unescapePointer(ref[len(prefix):]), true
+}
+
+// walkSchemas calls fn for every schema reachable from the root schema,
+// following references to component schemas instead of reporting them.
+// Components recorded in visited are skipped, so a visited map shared across
+// calls reports every component schema once.
+func walkSchemas(components *Components, ptr string, root *Schema, visited map[string]bool, fn func(ptr string, schema *Schema)) {
+	type pending struct {
+		ptr    string
+		schema *Schema
+	}
+	queue := []pending{{ptr, root}}
+	for len(queue) > 0 {
+		item := queue[0]
+		queue = queue[1:]
+		_ = (&walker{fn: func(ptr string, node interface{}) error {
+			schema, ok := node.(*Schema)
+			if !ok {
+				return nil
+			}
+			if schema.Ref == "" {
+				fn(ptr, schema)
+				return nil
+			}
+			name, ok := componentName(schema.Ref, "schemas")
+			if !ok || visited[name] || components == nil || components.Schemas[name] == nil {
+				return nil
+			}
+			visited[name] = true
+			queue = append(queue, pending{join("/components/schemas", name), components.Schemas[name]})
+			return nil
+		}}).schema(item.ptr, item.schema)
+	}
+}
@@ -0,0 +1,190 @@
+package oas
+
+import (
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// WebSocketExtension names the specification extension marking a GET
+// operation as the handshake of a WebSocket endpoint and declaring the
+// messages exchanged over the connection.
+const WebSocketExtension = "x-websocket"
+
+// Rules of the findings reported by AuditWebSockets.
+const (
+	RuleWebSocketInvalid         = "websocket-invalid"
+	RuleWebSocketMethod          = "websocket-method"
+	RuleWebSocketMissingUpgrade  = "websocket-missing-upgrade"
+	RuleWebSocketUnknownSchema   = "websocket-unknown-schema"
+	RuleWebSocketMissingMessages = "websocket-missing-messages"
+)
+
+// WebSocket describes the connection of a WebSocket endpoint.
+type WebSocket struct {
+	// Subprotocols describes the subprotocols the server accepts in the
+	// Sec-WebSocket-Protocol header, in order of preference.
+	Subprotocols []string `json:"subprotocols,omitempty" yaml:"subprotocols,omitempty"`
+
+	// Publish describes the messages clients send to the server, keyed by
+	// message name.
+	Publish map[string]*Schema `json:"publish,omitempty" yaml:"publish,omitempty"`
+
+	// Subscribe describes the messages the server sends to clients, keyed by
+	// message name.
+	Subscribe map[string]*Schema `json:"subscribe,omitempty" yaml:"subscribe,omitempty"`
+}
+
+// validate checks that the WebSocket endpoint is well formed.
+func (r WebSocket) validate() error {
+	for _, messages := range []map[string]*Schema{r.Publish, r.Subscribe} {
+		for _, name := range sortedKeys(messages) {
+			if messages[name] == nil {
+				return errors.Errorf("missing schema of message %q", name)
+			}
+		}
+	}
+	return nil
+}
+
+// WebSocket returns the WebSocket endpoint declared by the x-websocket
+// extension, or nil if the extension is absent.
+func (r Extensions) WebSocket() (*WebSocket, error) {
+	ws := &WebSocket{}
+	ok, err := r.decode(WebSocketExtension, ws)
+	if !ok || err != nil {
+		return nil, err
+	}
+	if err := ws.validate(); err != nil {
+		return nil, errors.Wrap(err, WebSocketExtension)
+	}
+	return ws, nil
+}
+
+// SetWebSocket declares the WebSocket endpoint with the x-websocket
+// extension, or removes the extension when nil.
+func (r *Extensions) SetWebSocket(ws *WebSocket) {
+	if ws == nil {
+		delete(*r, WebSocketExtension)
+		return
+	}
+	setExtension(r, WebSocketExtension, ws)
+}
+
+// DeclareWebSocket declares the path as a WebSocket endpoint. Its GET
+// operation, added if missing, documents the 101 Switching Protocols
+// response of the handshake and is marked with the x-websocket extension,
+// so REST and WebSocket endpoints share the document.
+func (r *OpenAPI) DeclareWebSocket(path string, ws *WebSocket) error {
+	if err := ws.validate(); err != nil {
+		return err
+	}
+	item, ok := r.Paths.PathItems.Lookup(path)
+	if !ok || item == nil {
+		return errors.Errorf("path %s not found", path)
+	}
+	if item.Ref != "" {
+		return errors.Errorf("path %s is a reference", path)
+	}
+	if item.Get == nil {
+		item.Get = &Operation{}
+	}
+	op := item.Get
+	response := op.Responses.Get("101")
+	if response != nil && response.Ref != "" {
+		return errors.Errorf("%s: the 101 response references a component", join("/paths", path, "get"))
+	}
+	if response == nil {
+		response = &Response{Description: "Switching Protocols, the connection is upgraded to WebSocket."}
+		op.Responses.Set("101", response)
+	}
+	if !hasHeader(response.Headers, "Upgrade") {
+		if response.Headers == nil {
+			response.Headers = map[string]*Header{}
+		}
+		response.Headers["Upgrade"] = &Header{
+			Required: true,
+			Schema:   &Schema{Type: "string", Enum: []interface{}{"websocket"}},
+		}
+	}
+	if len(ws.Subprotocols) > 0 && !hasHeader(response.Headers, "Sec-WebSocket-Protocol") {
+		enum := make([]interface{}, len(ws.Subprotocols))
+		for i, subprotocol := range ws.Subprotocols {
+			enum[i] = subprotocol
+		}
+		response.Headers["Sec-WebSocket-Protocol"] = &Header{
+			Description: "The subprotocol selected by the server.",
+			Schema:      &Schema{Type: "string", Enum: enum},
+		}
+	}
+	op.Extensions.SetWebSocket(ws)
+	return nil
+}
+
+// AuditWebSockets checks the WebSocket endpoints declared with the
+// x-websocket extension. It flags malformed extensions, extensions on
+// operations other than GET, handshakes without a 101 response, endpoints
+// which declare no messages and message schemas referencing undeclared
+// components.
+func (r OpenAPI) AuditWebSockets() []*Finding {
+	findings := make([]*Finding, 0)
+	for _, op := range r.Paths.operations() {
+		ptr := join("/paths", op.path, op.method)
+		ws, err := op.operation.Extensions.WebSocket()
+		if err != nil {
+			findings = append(findings, &Finding{
+				Pointer:  join(ptr, WebSocketExtension),
+				Rule:     RuleWebSocketInvalid,
+				Severity: SeverityError,
+				Message:  err.Error(),
+			})
+			continue
+		}
+		if ws == nil {
+			continue
+		}
+		if op.method != "get" {
+			findings = append(findings, &Finding{
+				Pointer:  join(ptr, WebSocketExtension),
+				Rule:     RuleWebSocketMethod,
+				Severity: SeverityError,
+				Message:  "WebSocket handshake must be a GET operation, found " + strings.ToUpper(op.method),
+			})
+		}
+		if _, response := NewResolver(&r).response("", op.operation.Responses.Get("101")); response == nil {
+			findings = append(findings, &Finding{
+				Pointer:  join(ptr, "responses"),
+				Rule:     RuleWebSocketMissingUpgrade,
+				Severity: SeverityWarning,
+				Message:  "WebSocket handshake declares no 101 Switching Protocols response",
+			})
+		}
+		if len(ws.Publish) == 0 && len(ws.Subscribe) == 0 {
+			findings = append(findings, &Finding{
+				Pointer:  join(ptr, WebSocketExtension),
+				Rule:     RuleWebSocketMissingMessages,
+				Severity: SeverityWarning,
+				Message:  "WebSocket endpoint declares no messages",
+			})
+		}
+		for _, direction := range []string{"publish", "subscribe"} {
+			messages := ws.Publish
+			if direction == "subscribe" {
+				messages = ws.Subscribe
+			}
+			for _, name := range sortedKeys(messages) {
+				schema := messages[name]
+				if schema.Ref != "" && NewResolver(&r).schema(schema) == nil {
+					findings = append(findings, &Finding{
+						Pointer:  join(ptr, WebSocketExtension, direction, name),
+						Rule:     RuleWebSocketUnknownSchema,
+						Severity: SeverityError,
+						Message:  "message " + name + " references undeclared schema " + schema.Ref,
+					})
+				}
+			}
+		}
+	}
+	sortFindings(findings)
+	return findings
+}
@@ -0,0 +1,148 @@
+package oas
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+)
+
+type WebSocketSuite struct {
+	suite.Suite
+}
+
+// webSocketPatch is the patch of the petstore fixture the suite declares
+// WebSocket endpoints on.
+const webSocketPatch = `
+paths:
+  /pets/{petId}/ws: {}
+`
+
+func (r *WebSocketSuite) TestDeclareWebSocket() {
+	ws := &WebSocket{
+		Subprotocols: []string{"chat.v1"},
+		Publish:      map[string]*Schema{"say": {Ref: "#/components/schemas/Pet"}},
+		Subscribe:    map[string]*Schema{"said": {Ref: "#/components/schemas/Pet"}},
+	}
+	doc := petstore(r.T(), webSocketPatch)
+	assert.Nil(r.T(), doc.DeclareWebSocket("/pets/{petId}/ws", ws))
+	op := doc.Paths.PathItems.Get("/pets/{petId}/ws").Get
+	assert.Equal(r.T(), &Response{
+		Description: "Switching Protocols, the connection is upgraded to WebSocket.",
+		Headers: map[string]*Header{
+			"Upgrade": {Required: true, Schema: &Schema{Type: "string", Enum: []interface{}{"websocket"}}},
+			"Sec-WebSocket-Protocol": {
+				Description: "The subprotocol selected by the server.",
+				Schema:      &Schema{Type: "string", Enum: []interface{}{"chat.v1"}},
+			},
+		},
+	}, op.Responses.Get("101"))
+	declared, err := op.Extensions.WebSocket()
+	assert.Nil(r.T(), err)
+	assert.Equal(r.T(), ws, declared)
+	assert.Equal(r.T(), []*Finding{}, doc.AuditWebSockets())
+
+	rbytes, err := MarshalCanonicalYAML(doc)
+	assert.Nil(r.T(), err)
+	decoded := &OpenAPI{}
+	assert.Nil(r.T(), decodeDocument(rbytes, decoded))
+	declared, err = decoded.Paths.PathItems.Get("/pets/{petId}/ws").Get.Extensions.WebSocket()
+	assert.Nil(r.T(), err)
+	assert.Equal(r.T(), ws, declared)
+
+	assert.NotNil(r.T(), doc.DeclareWebSocket("/unknown", ws))
+	assert.NotNil(r.T(), doc.DeclareWebSocket("/pets/{petId}/ws", &WebSocket{Publish: map[string]*Schema{"say": nil}}))
+
+	op.Extensions.SetWebSocket(nil)
+	declared, err = op.Extensions.WebSocket()
+	assert.Nil(r.T(), err)
+	assert.Nil(r.T(), declared)
+}
+
+func (r *WebSocketSuite) TestAuditWebSockets() {
+	testCases := []struct {
+		patch    string
+		findings []*Finding
+	}{
+		{
+			`
+paths:
+  /pets:
+    post:
+      x-websocket:
+        subscribe:
+          said:
+            $ref: "#/components/schemas/Said"
+`,
+			[]*Finding{
+				{
+					Pointer:  "/paths/~1pets/post/responses",
+					Rule:     RuleWebSocketMissingUpgrade,
+					Severity: SeverityWarning,
+					Message:  "WebSocket handshake declares no 101 Switching Protocols response",
+				},
+				{
+					Pointer:  "/paths/~1pets/post/x-websocket",
+					Rule:     RuleWebSocketMethod,
+					Severity: SeverityError,
+					Message:  "WebSocket handshake must be a GET operation, found POST",
+				},
+				{
+					Pointer:  "/paths/~1pets/post/x-websocket/subscribe/said",
+					Rule:     RuleWebSocketUnknownSchema,
+					Severity: SeverityError,
+					Message:  "message said references undeclared schema #/components/schemas/Said",
+				},
+			},
+		},
+		{
+			`
+paths:
+  /pets/{petId}/ws:
+    get:
+      responses:
+        "101":
+          description: Switching Protocols.
+      x-websocket: {}
+`,
+			[]*Finding{
+				{
+					Pointer:  "/paths/~1pets~1{petId}~1ws/get/x-websocket",
+					Rule:     RuleWebSocketMissingMessages,
+					Severity: SeverityWarning,
+					Message:  "WebSocket endpoint declares no messages",
+				},
+			},
+		},
+		{
+			`
+paths:
+  /pets:
+    post:
+      x-websocket: chat
+`,
+			[]*Finding{
+				{
+					Pointer:  "/paths/~1pets/post/x-websocket",
+					Rule:     RuleWebSocketInvalid,
+					Severity: SeverityError,
+				},
+			},
+		},
+	}
+
+	for i, testCase := range testCases {
+		failMsg := "test case %d failed"
+		findings := petstore(r.T(), webSocketPatch, testCase.patch).AuditWebSockets()
+		for _, finding := range findings {
+			if finding.Rule == RuleWebSocketInvalid {
+				finding.Message = ""
+			}
+		}
+		assert.Equal(r.T(), testCase.findings, findings, failMsg, i+1)
+	}
+}
+
+func TestWebSocketSuite(t *testing.T) {
+	suite.Run(t, new(WebSocketSuite))
+}
@@ -0,0 +1,260 @@
+package oas
+
+import (
+	"context"
+	"io/ioutil"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/pkg/errors"
+)
+
+// DefaultWorkspaceConcurrency is the number of documents a Workspace loads
+// at once when it does not specify a limit.
+const DefaultWorkspaceConcurrency = 8
+
+// registryScheme prefixes the locations of documents fetched from the
+// Registry of a Workspace.
+const registryScheme = "registry:"
+
+// RegistryLocation returns the location a Workspace fetches the version of
+// the named document from its Registry with (e.g. "registry:pets@1.2.0").
+// The LatestVersion alias fetches the highest published version.
+func RegistryLocation(name string, version string) string {
+	return registryScheme + name + "@" + version
+}
+
+// Workspace holds a set of documents loaded by location, such as all the
+// APIs of a platform analyzed together. It is safe for concurrent use.
+type Workspace struct {
+	// Read describes how the content of a location is read. Defaults to
+	// ReadLocation.
+	Read func(ctx context.Context, location string) ([]byte, error)
+
+	// Loader describes how the content of a location is decoded, so the
+	// versions it accepts are checked and failures are returned as
+	// *LoadError.
+	Loader Loader
+
+	// Bundle describes whether the files referenced by the documents are
+	// bundled into them as Loader.LoadBundle does, read with the RefLoader
+	// of Loader or with Read when it has none.
+	Bundle bool
+
+	// Registry describes the registry the locations returned by
+	// RegistryLocation are fetched from. When nil, such locations are read
+	// like any other.
+	Registry Registry
+
+	// Concurrency describes the maximum number of documents read and decoded
+	// at once. Defaults to DefaultWorkspaceConcurrency.
+	Concurrency int
+
+	// Interner describes the interner the strings of the loaded documents
+	// are deduplicated with. When nil, strings are not interned.
+	Interner *Interner
+
+	// Profiles describes the active profiles the loaded documents are
+	// resolved for with ResolveProfiles. When nil, documents are kept whole.
+	Profiles []string
+
+	mutex      sync.RWMutex
+	docs       map[string]*OpenAPI
+	provenance map[string]Provenance
+}
+
+// WorkspaceError describes the documents a Workspace failed to load.
+type WorkspaceError struct {
+	// Errors describes the error of every location which failed to load,
+	// a *LoadError unless loading was canceled.
+	Errors map[string]error
+}
+
+// Error returns the string representation of the error.
+func (e *WorkspaceError) Error() string {
+	locations := sortedStrings(e.Errors)
+	messages := make([]string, 0, len(locations))
+	for _, location := range locations {
+		if _, ok := e.Errors[location].(*LoadError); ok {
+			messages = append(messages, e.Errors[location].Error())
+			continue
+		}
+		messages = append(messages, location+": "+e.Errors[location].Error())
+	}
+	return "failed to load " + strings.Join(messages, "; ")
+}
+
+// Load reads and decodes the documents at the locations concurrently,
+// reading at most Concurrency documents at once. Every document loaded
+// successfully is added to the workspace, replacing a document previously
+// loaded from the same location. Failures do not stop the other documents
+// from loading, they are returned together as a *WorkspaceError.
+func (r *Workspace) Load(ctx context.Context, locations ...string) error {
+	read := r.Read
+	if read == nil {
+		read = ReadLocation
+	}
+	concurrency := r.Concurrency
+	if concurrency <= 0 {
+		concurrency = DefaultWorkspaceConcurrency
+	}
+
+	var mutex sync.Mutex
+	failures := map[string]error{}
+	semaphore := make(chan struct{}, concurrency)
+	var wg sync.WaitGroup
+	for _, location := range locations {
+		wg.Add(1)
+		go func(location string) {
+			defer wg.Done()
+			semaphore <- struct{}{}
+			defer func() { <-semaphore }()
+
+			doc, provenance, err := r.load(ctx, read, location)
+			if err != nil {
+				mutex.Lock()
+				failures[location] = err
+				mutex.Unlock()
+				return
+			}
+
+			r.mutex.Lock()
+			if r.docs == nil {
+				r.docs = map[string]*OpenAPI{}
+				r.provenance = map[string]Provenance{}
+			}
+			r.docs[location] = doc
+			r.provenance[location] = provenance
+			r.mutex.Unlock()
+		}(location)
+	}
+	wg.Wait()
+
+	if len(failures) > 0 {
+		return &WorkspaceError{Errors: failures}
+	}
+	return nil
+}
+
+// load reads and decodes a single document and records its provenance.
+func (r *Workspace) load(ctx context.Context, read func(context.Context, string) ([]byte, error), location string) (*OpenAPI, Provenance, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, nil, errors.WithStack(err)
+	}
+	doc, provenance, err := r.decode(ctx, read, location)
+	if err != nil {
+		return nil, nil, err
+	}
+	if r.Profiles != nil {
+		if _, err := doc.ResolveProfiles(r.Profiles...); err != nil {
+			return nil, nil, &LoadError{Location: location, Err: err}
+		}
+	}
+	if r.Interner != nil {
+		doc.Intern(r.Interner)
+	}
+	return doc, provenance, nil
+}
+
+// decode returns the document at the location and its provenance, fetching
+// it from the registry or reading and decoding it with the loader. Documents
+// fetched from the registry were decoded by it, so their provenance holds
+// the location alone.
+func (r *Workspace) decode(ctx context.Context, read func(context.Context, string) ([]byte, error), location string) (*OpenAPI, Provenance, error) {
+	if r.Registry != nil && strings.HasPrefix(location, registryScheme) {
+		name, version := strings.TrimPrefix(location, registryScheme), LatestVersion
+		if i := strings.LastIndex(name, "@"); i >= 0 {
+			name, version = name[:i], name[i+1:]
+		}
+		doc, err := r.Registry.Fetch(ctx, name, version)
+		if err != nil {
+			return nil, nil, &LoadError{Location: location, Err: err}
+		}
+		if err := r.Loader.checkVersion(doc.OpenAPI); err != nil {
+			return nil, nil, &LoadError{Location: location, Err: err}
+		}
+		return doc, Provenance{"": {Location: location}}, nil
+	}
+
+	data, err := read(ctx, location)
+	if err != nil {
+		return nil, nil, &LoadError{Location: location, Err: err}
+	}
+	doc, provenance, err := r.Loader.LoadLocated(location, data)
+	if err != nil {
+		return nil, nil, err
+	}
+	if r.Bundle {
+		loader := r.Loader.RefLoader
+		if loader == nil {
+			loader = RefLoaderFunc(read)
+		}
+		if err := doc.ResolveExternalRefs(ctx, location, loader); err != nil {
+			return nil, nil, &LoadError{Location: location, Err: err}
+		}
+	}
+	return doc, provenance, nil
+}
+
+// Document returns the document loaded from the location.
+func (r *Workspace) Document(location string) (*OpenAPI, bool) {
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+	doc, ok := r.docs[location]
+	return doc, ok
+}
+
+// Provenance returns the provenance of the nodes of the document loaded
+// from the location, so errors and generated output can point back at the
+// file and line a node was read from.
+func (r *Workspace) Provenance(location string) (Provenance, bool) {
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+	provenance, ok := r.provenance[location]
+	return provenance, ok
+}
+
+// Locations returns the locations of the loaded documents in sorted order.
+func (r *Workspace) Locations() []string {
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+	locations := make([]string, 0, len(r.docs))
+	for location := range r.docs {
+		locations = append(locations, location)
+	}
+	sort.Strings(locations)
+	return locations
+}
+
+// ReadLocation reads the content of an http or https URL with the default
+// HTTP client, or of a file otherwise.
+func ReadLocation(ctx context.Context, location string) ([]byte, error) {
+	if !isURL(location) {
+		data, err := ioutil.ReadFile(location)
+		if err != nil {
+			return nil, errors.WithStack(err)
+		}
+		return data, nil
+	}
+
+	req, err := http.NewRequest(http.MethodGet, location, nil)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+	resp, err := http.DefaultClient.Do(req.WithContext(ctx))
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return nil, errors.Errorf("unexpected status %s", resp.Status)
+	}
+	data, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+	return data, nil
+}
@@ -0,0 +1,181 @@
+package oas
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+)
+
+type WorkspaceSuite struct {
+	suite.Suite
+}
+
+func (r *WorkspaceSuite) TestLoad() {
+	var mutex sync.Mutex
+	active, peak := 0, 0
+	workspace := &Workspace{
+		Concurrency: 2,
+		Read: func(ctx context.Context, location string) ([]byte, error) {
+			mutex.Lock()
+			active++
+			if active > peak {
+				peak = active
+			}
+			mutex.Unlock()
+			defer func() {
+				mutex.Lock()
+				active--
+				mutex.Unlock()
+			}()
+
+			time.Sleep(10 * time.Millisecond)
+			switch location {
+			case "missing":
+				return nil, errors.New("not found")
+			case "broken":
+				return []byte("{"), nil
+			}
+			return []byte(fmt.Sprintf(`{"openapi": "3.0.0", "info": {"title": %q, "version": "1.0.0"}, "paths": {}}`, location)), nil
+		},
+	}
+
+	locations := []string{"pets", "owners", "missing", "stores", "broken", "orders"}
+	err := workspace.Load(context.Background(), locations...)
+	assert.NotNil(r.T(), err)
+	if failure, ok := err.(*WorkspaceError); assert.True(r.T(), ok) {
+		assert.Equal(r.T(), []string{"broken", "missing"}, sortedStrings(failure.Errors))
+		assert.Contains(r.T(), failure.Error(), "missing: not found")
+	}
+	assert.Equal(r.T(), 2, peak)
+
+	assert.Equal(r.T(), []string{"orders", "owners", "pets", "stores"}, workspace.Locations())
+	doc, ok := workspace.Document("pets")
+	assert.True(r.T(), ok)
+	assert.Equal(r.T(), "pets", doc.Info.Title)
+	_, ok = workspace.Document("missing")
+	assert.False(r.T(), ok)
+}
+
+func (r *WorkspaceSuite) TestCanceled() {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	workspace := &Workspace{}
+	err := workspace.Load(ctx, "pets.yaml")
+	assert.NotNil(r.T(), err)
+	assert.Empty(r.T(), workspace.Locations())
+}
+
+func (r *WorkspaceSuite) TestReadLocation() {
+	data := []byte("openapi: 3.0.0\ninfo:\n  title: Petstore\n  version: 1.0.0\npaths: {}\n")
+	dir, err := ioutil.TempDir("", "workspace")
+	assert.Nil(r.T(), err)
+	defer os.RemoveAll(dir)
+	file := filepath.Join(dir, "pets.yaml")
+	assert.Nil(r.T(), ioutil.WriteFile(file, data, 0644))
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		if req.URL.Path != "/pets.yaml" {
+			http.NotFound(w, req)
+			return
+		}
+		_, _ = w.Write(data)
+	}))
+	defer server.Close()
+
+	workspace := &Workspace{Interner: &Interner{}}
+	assert.Nil(r.T(), workspace.Load(context.Background(), file, server.URL+"/pets.yaml"))
+	for _, location := range []string{file, server.URL + "/pets.yaml"} {
+		doc, ok := workspace.Document(location)
+		if assert.True(r.T(), ok) {
+			assert.Equal(r.T(), "Petstore", doc.Info.Title)
+		}
+	}
+
+	err = workspace.Load(context.Background(), server.URL+"/missing.yaml", filepath.Join(dir, "missing.yaml"))
+	if failure, ok := err.(*WorkspaceError); assert.True(r.T(), ok) {
+		assert.Len(r.T(), failure.Errors, 2)
+	}
+}
+
+func (r *WorkspaceSuite) TestLoader() {
+	files := map[string]string{
+		"pets.yaml":   "openapi: 3.0.3\ninfo:\n  title: Pets\n  version: 1.0.0\npaths: {}\ncomponents:\n  schemas:\n    Pet:\n      $ref: pet.yaml\n",
+		"pet.yaml":    "type: object\n",
+		"legacy.yaml": "openapi: 3.1.0\ninfo:\n  title: Legacy\n  version: 1.0.0\npaths: {}\n",
+		"broken.yaml": "openapi: 3.0.3\npaths:\n  /pets:\n    get:\n      parameters: {}\n",
+	}
+	workspace := &Workspace{
+		Loader: Loader{Versions: []string{"3.0"}},
+		Bundle: true,
+		Read: func(ctx context.Context, location string) ([]byte, error) {
+			if content, ok := files[location]; ok {
+				return []byte(content), nil
+			}
+			return nil, os.ErrNotExist
+		},
+	}
+
+	err := workspace.Load(context.Background(), "pets.yaml", "legacy.yaml", "broken.yaml")
+	if failure, ok := err.(*WorkspaceError); assert.True(r.T(), ok) {
+		assert.Equal(r.T(), []string{"broken.yaml", "legacy.yaml"}, sortedStrings(failure.Errors))
+		if loadErr, ok := failure.Errors["legacy.yaml"].(*LoadError); assert.True(r.T(), ok) {
+			assert.Equal(r.T(), Source{Location: "legacy.yaml", Line: 1, Column: 1}, loadErr.Source())
+		}
+		if loadErr, ok := failure.Errors["broken.yaml"].(*LoadError); assert.True(r.T(), ok) {
+			assert.Equal(r.T(), "/paths/~1pets/get/parameters", loadErr.Pointer)
+			assert.Equal(r.T(), 5, loadErr.Line)
+		}
+		assert.Contains(r.T(), failure.Error(), `failed to load broken.yaml:5:7: `)
+	}
+
+	doc, ok := workspace.Document("pets.yaml")
+	if assert.True(r.T(), ok) {
+		assert.Equal(r.T(), "#/components/schemas/pet", doc.Components.Schemas["Pet"].Ref)
+		assert.Equal(r.T(), "object", doc.Components.Schemas["pet"].Type)
+	}
+	provenance, _ := workspace.Provenance("pets.yaml")
+	source, _ := provenance.Lookup("/info/title")
+	assert.Equal(r.T(), "pets.yaml:3:3", source.String())
+}
+
+func (r *WorkspaceSuite) TestRegistry() {
+	dir, err := ioutil.TempDir("", "workspace")
+	assert.Nil(r.T(), err)
+	defer os.RemoveAll(dir)
+
+	registry := FileRegistry{Root: dir}
+	for _, version := range []string{"1.0.0", "1.2.0"} {
+		doc := &OpenAPI{OpenAPI: "3.0.3", Info: Info{Title: "Pets", Version: version}}
+		assert.Nil(r.T(), registry.Publish(context.Background(), "pets", doc))
+	}
+
+	workspace := &Workspace{Registry: registry}
+	locations := []string{RegistryLocation("pets", "1.0.0"), RegistryLocation("pets", LatestVersion), RegistryLocation("pets", "2.0.0")}
+	err = workspace.Load(context.Background(), locations...)
+	if failure, ok := err.(*WorkspaceError); assert.True(r.T(), ok) {
+		assert.Equal(r.T(), []string{"registry:pets@2.0.0"}, sortedStrings(failure.Errors))
+		assert.Equal(r.T(), ErrVersionNotFound, errors.Cause(failure.Errors["registry:pets@2.0.0"]))
+	}
+	for location, version := range map[string]string{locations[0]: "1.0.0", locations[1]: "1.2.0"} {
+		doc, ok := workspace.Document(location)
+		if assert.True(r.T(), ok, location) {
+			assert.Equal(r.T(), version, doc.Info.Version)
+		}
+	}
+}
+
+func TestWorkspaceSuite(t *testing.T) {
+	suite.Run(t, new(WorkspaceSuite))
+}
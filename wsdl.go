@@ -0,0 +1,478 @@
+package oas
+
+import (
+	"encoding/xml"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// wsdlDefinitions describes the parts of a WSDL 1.1 document ImportWSDL
+// understands. Elements are matched by local name regardless of their
+// namespace prefix.
+type wsdlDefinitions struct {
+	Attrs           []xml.Attr     `xml:",any,attr"`
+	Name            string         `xml:"name,attr"`
+	TargetNamespace string         `xml:"targetNamespace,attr"`
+	Documentation   string         `xml:"documentation"`
+	Schemas         []xsdSchema    `xml:"types>schema"`
+	Messages        []wsdlMessage  `xml:"message"`
+	PortTypes       []wsdlPortType `xml:"portType"`
+	Bindings        []wsdlBinding  `xml:"binding"`
+	Services        []wsdlService  `xml:"service"`
+}
+
+type wsdlMessage struct {
+	Name  string `xml:"name,attr"`
+	Parts []struct {
+		Name    string `xml:"name,attr"`
+		Element string `xml:"element,attr"`
+		Type    string `xml:"type,attr"`
+	} `xml:"part"`
+}
+
+type wsdlPortType struct {
+	Name       string `xml:"name,attr"`
+	Operations []struct {
+		Name          string           `xml:"name,attr"`
+		Documentation string           `xml:"documentation"`
+		Input         wsdlMessageRef   `xml:"input"`
+		Output        wsdlMessageRef   `xml:"output"`
+		Faults        []wsdlMessageRef `xml:"fault"`
+	} `xml:"operation"`
+}
+
+type wsdlMessageRef struct {
+	Message string `xml:"message,attr"`
+}
+
+type wsdlBinding struct {
+	Name       string `xml:"name,attr"`
+	Type       string `xml:"type,attr"`
+	Operations []struct {
+		Name string `xml:"name,attr"`
+		SOAP struct {
+			Action string `xml:"soapAction,attr"`
+		} `xml:"operation"`
+	} `xml:"operation"`
+}
+
+type wsdlService struct {
+	Name  string `xml:"name,attr"`
+	Ports []struct {
+		Binding string `xml:"binding,attr"`
+		Address struct {
+			Location string `xml:"location,attr"`
+		} `xml:"address"`
+	} `xml:"port"`
+}
+
+type xsdSchema struct {
+	Attrs           []xml.Attr       `xml:",any,attr"`
+	TargetNamespace string           `xml:"targetNamespace,attr"`
+	Elements        []xsdElement     `xml:"element"`
+	ComplexTypes    []xsdComplexType `xml:"complexType"`
+	SimpleTypes     []xsdSimpleType  `xml:"simpleType"`
+}
+
+type xsdElement struct {
+	Name          string          `xml:"name,attr"`
+	Type          string          `xml:"type,attr"`
+	Ref           string          `xml:"ref,attr"`
+	MinOccurs     string          `xml:"minOccurs,attr"`
+	MaxOccurs     string          `xml:"maxOccurs,attr"`
+	Nillable      bool            `xml:"nillable,attr"`
+	Documentation string          `xml:"annotation>documentation"`
+	ComplexType   *xsdComplexType `xml:"complexType"`
+	SimpleType    *xsdSimpleType  `xml:"simpleType"`
+}
+
+type xsdComplexType struct {
+	Name          string         `xml:"name,attr"`
+	Documentation string         `xml:"annotation>documentation"`
+	Sequence      []xsdElement   `xml:"sequence>element"`
+	All           []xsdElement   `xml:"all>element"`
+	Choice        []xsdElement   `xml:"choice>element"`
+	Attributes    []xsdAttribute `xml:"attribute"`
+	Extension     *struct {
+		Base       string         `xml:"base,attr"`
+		Sequence   []xsdElement   `xml:"sequence>element"`
+		Attributes []xsdAttribute `xml:"attribute"`
+	} `xml:"complexContent>extension"`
+}
+
+type xsdAttribute struct {
+	Name string `xml:"name,attr"`
+	Type string `xml:"type,attr"`
+	Use  string `xml:"use,attr"`
+}
+
+type xsdSimpleType struct {
+	Name          string `xml:"name,attr"`
+	Documentation string `xml:"annotation>documentation"`
+	Restriction   struct {
+		Base         string `xml:"base,attr"`
+		Enumerations []struct {
+			Value string `xml:"value,attr"`
+		} `xml:"enumeration"`
+	} `xml:"restriction"`
+}
+
+// xsdTypes maps the built-in XML Schema types onto schemas. Other built-in
+// types are mapped to strings.
+var xsdTypes = map[string]Schema{
+	"boolean":      {Type: "boolean"},
+	"byte":         {Type: "integer", Format: "int32"},
+	"short":        {Type: "integer", Format: "int32"},
+	"int":          {Type: "integer", Format: "int32"},
+	"integer":      {Type: "integer"},
+	"long":         {Type: "integer", Format: "int64"},
+	"decimal":      {Type: "number"},
+	"float":        {Type: "number", Format: "float"},
+	"double":       {Type: "number", Format: "double"},
+	"date":         {Type: "string", Format: "date"},
+	"dateTime":     {Type: "string", Format: "date-time"},
+	"base64Binary": {Type: "string", Format: "byte"},
+}
+
+// ImportWSDL converts a WSDL 1.1 document describing SOAP services into a
+// document, on a best-effort basis, to help documenting legacy services
+// while they are migrated. Every operation of the port types becomes a POST
+// operation on a path named after it, requiring its SOAPAction header and
+// exchanging text/xml bodies; faults become the 500 response. Complex
+// types, simple types and elements of the embedded XML schemas become
+// component schemas with their XML metadata populated. Constructs which are
+// not understood, such as imported schemas, groups or substitution groups,
+// are ignored.
+func ImportWSDL(data []byte) (*OpenAPI, error) {
+	definitions := wsdlDefinitions{}
+	if err := xml.Unmarshal(data, &definitions); err != nil {
+		return nil, errors.WithStack(err)
+	}
+	if len(definitions.PortTypes) == 0 {
+		return nil, errors.New("document declares no port types")
+	}
+
+	doc := &OpenAPI{
+		OpenAPI: "3.0.0",
+		Info: Info{
+			Title:       definitions.Name,
+			Description: strings.TrimSpace(definitions.Documentation),
+			Version:     "1.0.0",
+		},
+		Components: &Components{Schemas: map[string]*Schema{}},
+	}
+	if doc.Info.Title == "" && len(definitions.Services) > 0 {
+		doc.Info.Title = definitions.Services[0].Name
+	}
+
+	importer := &wsdlImporter{builtin: map[string]bool{}, elements: map[string]string{}}
+	importer.declare(definitions.Attrs)
+	for _, schema := range definitions.Schemas {
+		importer.declare(schema.Attrs)
+		importer.importXSD(doc.Components.Schemas, schema)
+	}
+
+	actions := map[string]string{}
+	for _, binding := range definitions.Bindings {
+		for _, operation := range binding.Operations {
+			if operation.SOAP.Action != "" {
+				actions[operation.Name] = operation.SOAP.Action
+			}
+		}
+	}
+	for _, service := range definitions.Services {
+		for _, port := range service.Ports {
+			if location := port.Address.Location; location != "" && !hasServer(doc.Servers, location) {
+				doc.Servers = append(doc.Servers, &Server{URL: location, Description: service.Name})
+			}
+		}
+	}
+
+	messages := map[string]wsdlMessage{}
+	for _, message := range definitions.Messages {
+		messages[message.Name] = message
+	}
+	for _, portType := range definitions.PortTypes {
+		for _, operation := range portType.Operations {
+			path := "/" + operation.Name
+			if _, ok := doc.Paths.PathItems.Lookup(path); ok {
+				return nil, errors.Errorf("operation %q is declared more than once", operation.Name)
+			}
+
+			op := &Operation{
+				OperationID: operation.Name,
+				Tags:        []string{portType.Name},
+				Description: strings.TrimSpace(operation.Documentation),
+			}
+			action := &Schema{Type: "string"}
+			if value, ok := actions[operation.Name]; ok {
+				action.Enum = []interface{}{value}
+			}
+			op.Parameters = []*Parameter{{
+				Name:   "SOAPAction",
+				In:     "header",
+				Header: Header{Required: true, Schema: action},
+			}}
+			if message, ok := messages[localName(operation.Input.Message)]; ok {
+				op.RequestBody = &RequestBody{
+					Required: true,
+					Content:  map[string]*MediaType{"text/xml": {Schema: importer.messageSchema(message)}},
+				}
+			}
+			response := &Response{Description: "Successful response."}
+			if message, ok := messages[localName(operation.Output.Message)]; ok {
+				response.Content = map[string]*MediaType{"text/xml": {Schema: importer.messageSchema(message)}}
+			}
+			op.Responses.Set("200", response)
+			faults := make([]*Schema, 0, len(operation.Faults))
+			for _, fault := range operation.Faults {
+				if message, ok := messages[localName(fault.Message)]; ok {
+					faults = append(faults, importer.messageSchema(message))
+				}
+			}
+			switch len(faults) {
+			case 0:
+			case 1:
+				op.Responses.Set("500", &Response{
+					Description: "SOAP fault.",
+					Content:     map[string]*MediaType{"text/xml": {Schema: faults[0]}},
+				})
+			default:
+				op.Responses.Set("500", &Response{
+					Description: "SOAP fault.",
+					Content:     map[string]*MediaType{"text/xml": {Schema: &Schema{OneOf: faults}}},
+				})
+			}
+			doc.Paths.PathItems.Set(path, &PathItem{Post: op})
+		}
+	}
+	return doc, nil
+}
+
+// hasServer reports whether the servers declare the URL.
+func hasServer(servers []*Server, url string) bool {
+	for _, server := range servers {
+		if server.URL == url {
+			return true
+		}
+	}
+	return false
+}
+
+// xmlSchemaNamespace is the namespace of the built-in XML Schema types.
+const xmlSchemaNamespace = "http://www.w3.org/2001/XMLSchema"
+
+// wsdlImporter tracks the namespace prefixes bound to XML Schema and the
+// schemas the elements were imported as.
+type wsdlImporter struct {
+	builtin  map[string]bool
+	elements map[string]string
+}
+
+// declare records the prefixes the namespace declarations bind to XML
+// Schema.
+func (r *wsdlImporter) declare(attrs []xml.Attr) {
+	for _, attr := range attrs {
+		if attr.Name.Space == "xmlns" && attr.Value == xmlSchemaNamespace {
+			r.builtin[attr.Name.Local] = true
+		}
+	}
+}
+
+// importXSD adds the named types and elements of the XML schema to the
+// schemas. Elements typed with a complex type of the same name share its
+// schema and other elements colliding with a type are suffixed with
+// "Element".
+func (r *wsdlImporter) importXSD(schemas map[string]*Schema, xsd xsdSchema) {
+	complexTypes := map[string]bool{}
+	for _, complexType := range xsd.ComplexTypes {
+		complexTypes[complexType.Name] = true
+	}
+	for _, simpleType := range xsd.SimpleTypes {
+		complexTypes[simpleType.Name] = false
+	}
+	shared := map[string]bool{}
+	for _, element := range xsd.Elements {
+		name := element.Name
+		if complex, ok := complexTypes[name]; ok {
+			if complex && localName(element.Type) == name {
+				shared[name] = true
+			} else {
+				name += "Element"
+			}
+		}
+		r.elements[element.Name] = name
+	}
+
+	for _, complexType := range xsd.ComplexTypes {
+		schema := r.complexSchema(complexType)
+		schema.XML = &XML{Name: complexType.Name, Namespace: xsd.TargetNamespace}
+		schemas[complexType.Name] = schema
+	}
+	for _, simpleType := range xsd.SimpleTypes {
+		schemas[simpleType.Name] = r.simpleSchema(simpleType)
+	}
+	for _, element := range xsd.Elements {
+		if shared[element.Name] {
+			continue
+		}
+		schema := r.elementSchema(element)
+		if schema.Ref != "" {
+			schema = &Schema{AllOf: []*Schema{schema}}
+		}
+		schema.XML = &XML{Name: element.Name, Namespace: xsd.TargetNamespace}
+		schemas[r.elements[element.Name]] = schema
+	}
+}
+
+// complexSchema converts a complex type into an object schema. Choices
+// become optional properties and extensions become an allOf of the base
+// type and the extending properties.
+func (r *wsdlImporter) complexSchema(complexType xsdComplexType) *Schema {
+	schema := &Schema{Type: "object", Description: strings.TrimSpace(complexType.Documentation)}
+	r.properties(schema, complexType.Sequence, true)
+	r.properties(schema, complexType.All, true)
+	r.properties(schema, complexType.Choice, false)
+	r.attributes(schema, complexType.Attributes)
+	if extension := complexType.Extension; extension != nil {
+		r.properties(schema, extension.Sequence, true)
+		r.attributes(schema, extension.Attributes)
+		base := r.typeSchema(extension.Base)
+		if schema.Properties.Len() == 0 {
+			return &Schema{AllOf: []*Schema{base}, Description: schema.Description}
+		}
+		return &Schema{AllOf: []*Schema{base, schema}}
+	}
+	return schema
+}
+
+// properties adds the elements to the properties of the object schema.
+// Elements are required when they may be and their minOccurs is not zero.
+func (r *wsdlImporter) properties(schema *Schema, elements []xsdElement, required bool) {
+	for _, element := range elements {
+		name := element.Name
+		if name == "" {
+			name = localName(element.Ref)
+		}
+		if name == "" {
+			continue
+		}
+		schema.Properties.Set(name, r.elementSchema(element))
+		if required && element.MinOccurs != "0" {
+			schema.Required = append(schema.Required, name)
+		}
+	}
+}
+
+// attributes adds the attributes to the properties of the object schema.
+func (r *wsdlImporter) attributes(schema *Schema, attributes []xsdAttribute) {
+	for _, attribute := range attributes {
+		if attribute.Name == "" {
+			continue
+		}
+		property := r.typeSchema(attribute.Type)
+		if property.Ref != "" {
+			property = &Schema{AllOf: []*Schema{property}}
+		}
+		property.XML = &XML{Attribute: true}
+		schema.Properties.Set(attribute.Name, property)
+		if attribute.Use == "required" {
+			schema.Required = append(schema.Required, attribute.Name)
+		}
+	}
+}
+
+// elementSchema converts an element, wrapping it into an array when it
+// may occur more than once.
+func (r *wsdlImporter) elementSchema(element xsdElement) *Schema {
+	var schema *Schema
+	switch {
+	case element.ComplexType != nil:
+		schema = r.complexSchema(*element.ComplexType)
+	case element.SimpleType != nil:
+		schema = r.simpleSchema(*element.SimpleType)
+	case element.Ref != "":
+		schema = r.elementRef(element.Ref)
+	default:
+		schema = r.typeSchema(element.Type)
+	}
+	if schema.Ref == "" {
+		schema.Nullable = element.Nillable
+		if description := strings.TrimSpace(element.Documentation); description != "" {
+			schema.Description = description
+		}
+	}
+	if element.MaxOccurs != "" && element.MaxOccurs != "0" && element.MaxOccurs != "1" {
+		return &Schema{Type: "array", Items: schema}
+	}
+	return schema
+}
+
+// simpleSchema converts a simple type restricting a built-in type,
+// keeping its enumeration.
+func (r *wsdlImporter) simpleSchema(simpleType xsdSimpleType) *Schema {
+	schema := r.typeSchema(simpleType.Restriction.Base)
+	if schema.Ref != "" {
+		schema = &Schema{AllOf: []*Schema{schema}}
+	}
+	schema.Description = strings.TrimSpace(simpleType.Documentation)
+	for _, enumeration := range simpleType.Restriction.Enumerations {
+		schema.Enum = append(schema.Enum, enumeration.Value)
+	}
+	return schema
+}
+
+// typeSchema returns the schema of the qualified type name, referencing
+// the components for types which are not built-in.
+func (r *wsdlImporter) typeSchema(name string) *Schema {
+	if name == "" {
+		return &Schema{Type: "string"}
+	}
+	prefix := ""
+	if i := strings.Index(name, ":"); i >= 0 {
+		prefix = name[:i]
+	}
+	if r.builtin[prefix] {
+		if schema, ok := xsdTypes[localName(name)]; ok {
+			return &schema
+		}
+		return &Schema{Type: "string"}
+	}
+	return &Schema{Ref: "#/components/schemas/" + localName(name)}
+}
+
+// messageSchema returns the schema of the message: the schema of the
+// element of single element messages and an object of the parts otherwise.
+func (r *wsdlImporter) messageSchema(message wsdlMessage) *Schema {
+	if len(message.Parts) == 1 && message.Parts[0].Element != "" {
+		return r.elementRef(message.Parts[0].Element)
+	}
+	schema := &Schema{Type: "object", XML: &XML{Name: message.Name}}
+	for _, part := range message.Parts {
+		if part.Element != "" {
+			schema.Properties.Set(part.Name, r.elementRef(part.Element))
+		} else {
+			schema.Properties.Set(part.Name, r.typeSchema(part.Type))
+		}
+		schema.Required = append(schema.Required, part.Name)
+	}
+	return schema
+}
+
+// elementRef returns a reference to the schema the qualified element was
+// imported as.
+func (r *wsdlImporter) elementRef(name string) *Schema {
+	if schema, ok := r.elements[localName(name)]; ok {
+		return &Schema{Ref: "#/components/schemas/" + schema}
+	}
+	return &Schema{Ref: "#/components/schemas/" + localName(name)}
+}
+
+// localName returns the qualified name without its namespace prefix.
+func localName(name string) string {
+	if i := strings.LastIndex(name, ":"); i >= 0 {
+		return name[i+1:]
+	}
+	return name
+}
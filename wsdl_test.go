@@ -0,0 +1,168 @@
+package oas
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+)
+
+type WSDLSuite struct {
+	suite.Suite
+}
+
+const stockQuoteWSDL = `<?xml version="1.0"?>
+<wsdl:definitions name="StockQuote"
+    targetNamespace="http://example.com/stockquote.wsdl"
+    xmlns:tns="http://example.com/stockquote.wsdl"
+    xmlns:s="http://www.w3.org/2001/XMLSchema"
+    xmlns:soap="http://schemas.xmlsoap.org/wsdl/soap/"
+    xmlns:wsdl="http://schemas.xmlsoap.org/wsdl/">
+  <wsdl:documentation>Stock quotes.</wsdl:documentation>
+  <wsdl:types>
+    <s:schema targetNamespace="http://example.com/stockquote.xsd">
+      <s:element name="TradePriceRequest">
+        <s:complexType>
+          <s:sequence>
+            <s:element name="tickerSymbol" type="s:string"/>
+            <s:element name="exchange" type="tns:Exchange" minOccurs="0"/>
+          </s:sequence>
+        </s:complexType>
+      </s:element>
+      <s:element name="TradePrice" type="tns:TradePrice"/>
+      <s:complexType name="TradePrice">
+        <s:annotation><s:documentation>A price.</s:documentation></s:annotation>
+        <s:sequence>
+          <s:element name="price" type="s:double"/>
+          <s:element name="history" type="s:decimal" maxOccurs="unbounded" nillable="true"/>
+        </s:sequence>
+        <s:attribute name="currency" type="s:string" use="required"/>
+      </s:complexType>
+      <s:simpleType name="Exchange">
+        <s:restriction base="s:string">
+          <s:enumeration value="NYSE"/>
+          <s:enumeration value="NASDAQ"/>
+        </s:restriction>
+      </s:simpleType>
+      <s:element name="Exchange" type="tns:Exchange"/>
+      <s:element name="Fault">
+        <s:complexType><s:sequence><s:element ref="tns:Exchange"/></s:sequence></s:complexType>
+      </s:element>
+    </s:schema>
+  </wsdl:types>
+  <wsdl:message name="GetLastTradePriceInput">
+    <wsdl:part name="body" element="tns:TradePriceRequest"/>
+  </wsdl:message>
+  <wsdl:message name="GetLastTradePriceOutput">
+    <wsdl:part name="body" element="tns:TradePrice"/>
+  </wsdl:message>
+  <wsdl:message name="GetLastTradePriceFault">
+    <wsdl:part name="fault" element="tns:Fault"/>
+  </wsdl:message>
+  <wsdl:message name="PingInput">
+    <wsdl:part name="count" type="s:int"/>
+  </wsdl:message>
+  <wsdl:portType name="StockQuotePortType">
+    <wsdl:operation name="GetLastTradePrice">
+      <wsdl:documentation>Returns the last trade price.</wsdl:documentation>
+      <wsdl:input message="tns:GetLastTradePriceInput"/>
+      <wsdl:output message="tns:GetLastTradePriceOutput"/>
+      <wsdl:fault name="fault" message="tns:GetLastTradePriceFault"/>
+    </wsdl:operation>
+    <wsdl:operation name="Ping">
+      <wsdl:input message="tns:PingInput"/>
+    </wsdl:operation>
+  </wsdl:portType>
+  <wsdl:binding name="StockQuoteSoapBinding" type="tns:StockQuotePortType">
+    <soap:binding style="document" transport="http://schemas.xmlsoap.org/soap/http"/>
+    <wsdl:operation name="GetLastTradePrice">
+      <soap:operation soapAction="http://example.com/GetLastTradePrice"/>
+    </wsdl:operation>
+  </wsdl:binding>
+  <wsdl:service name="StockQuoteService">
+    <wsdl:port name="StockQuotePort" binding="tns:StockQuoteSoapBinding">
+      <soap:address location="http://example.com/stockquote"/>
+    </wsdl:port>
+  </wsdl:service>
+</wsdl:definitions>`
+
+func (r *WSDLSuite) TestImportWSDL() {
+	doc, err := ImportWSDL([]byte(stockQuoteWSDL))
+	assert.Nil(r.T(), err)
+
+	assert.Equal(r.T(), Info{Title: "StockQuote", Description: "Stock quotes.", Version: "1.0.0"}, doc.Info)
+	assert.Equal(r.T(), []*Server{{URL: "http://example.com/stockquote", Description: "StockQuoteService"}}, doc.Servers)
+	assert.Equal(r.T(), []string{"/GetLastTradePrice", "/Ping"}, sortedKeys(doc.Paths.PathItems))
+
+	namespace := "http://example.com/stockquote.xsd"
+	request := Properties{}
+	request.Set("tickerSymbol", &Schema{Type: "string"})
+	request.Set("exchange", &Schema{Ref: "#/components/schemas/Exchange"})
+	price := Properties{}
+	price.Set("price", &Schema{Type: "number", Format: "double"})
+	price.Set("history", &Schema{Type: "array", Items: &Schema{Type: "number", Nullable: true}})
+	price.Set("currency", &Schema{Type: "string", XML: &XML{Attribute: true}})
+	assert.Equal(r.T(), map[string]*Schema{
+		"TradePriceRequest": {
+			Type:       "object",
+			Required:   []string{"tickerSymbol"},
+			Properties: request,
+			XML:        &XML{Name: "TradePriceRequest", Namespace: namespace},
+		},
+		"TradePrice": {
+			Type:        "object",
+			Description: "A price.",
+			Required:    []string{"price", "history", "currency"},
+			Properties:  price,
+			XML:         &XML{Name: "TradePrice", Namespace: namespace},
+		},
+		"Exchange": {Type: "string", Enum: []interface{}{"NYSE", "NASDAQ"}},
+		"ExchangeElement": {
+			AllOf: []*Schema{{Ref: "#/components/schemas/Exchange"}},
+			XML:   &XML{Name: "Exchange", Namespace: namespace},
+		},
+		"Fault": {
+			Type:       "object",
+			Required:   []string{"Exchange"},
+			Properties: NewProperties(map[string]*Schema{"Exchange": {Ref: "#/components/schemas/ExchangeElement"}}),
+			XML:        &XML{Name: "Fault", Namespace: namespace},
+		},
+	}, doc.Components.Schemas)
+
+	op := doc.Paths.PathItems.Get("/GetLastTradePrice").Post
+	assert.Equal(r.T(), "GetLastTradePrice", op.OperationID)
+	assert.Equal(r.T(), []string{"StockQuotePortType"}, op.Tags)
+	assert.Equal(r.T(), "Returns the last trade price.", op.Description)
+	assert.Equal(r.T(), []interface{}{"http://example.com/GetLastTradePrice"}, op.Parameters[0].Schema.Enum)
+	assert.Equal(r.T(), &Schema{Ref: "#/components/schemas/TradePriceRequest"}, op.RequestBody.Content["text/xml"].Schema)
+	assert.Equal(r.T(), &Schema{Ref: "#/components/schemas/TradePrice"}, op.Responses.Get("200").Content["text/xml"].Schema)
+	assert.Equal(r.T(), &Schema{Ref: "#/components/schemas/Fault"}, op.Responses.Get("500").Content["text/xml"].Schema)
+
+	ping := doc.Paths.PathItems.Get("/Ping").Post
+	assert.Equal(r.T(), &Schema{Type: "string"}, ping.Parameters[0].Schema)
+	assert.Equal(r.T(), &Schema{
+		Type:       "object",
+		Required:   []string{"count"},
+		Properties: NewProperties(map[string]*Schema{"count": {Type: "integer", Format: "int32"}}),
+		XML:        &XML{Name: "PingInput"},
+	}, ping.RequestBody.Content["text/xml"].Schema)
+	assert.Nil(r.T(), ping.Responses.Get("200").Content)
+}
+
+func (r *WSDLSuite) TestErrors() {
+	testCases := []string{
+		"<definitions",
+		`<definitions name="Empty"/>`,
+		`<definitions><portType><operation name="A"/></portType><portType><operation name="A"/></portType></definitions>`,
+	}
+
+	for i, testCase := range testCases {
+		failMsg := "test case %d failed"
+		_, err := ImportWSDL([]byte(testCase))
+		assert.NotNil(r.T(), err, failMsg, i)
+	}
+}
+
+func TestWSDLSuite(t *testing.T) {
+	suite.Run(t, new(WSDLSuite))
+}